@@ -0,0 +1,59 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+vglexplorer is a block explorer backend for Vigil.
+
+It follows the main chain of vgld via websocket block notifications and
+indexes blocks, transactions, addresses, tickets, votes, and treasury spends
+into an embedded database.  Blocks that are no longer in the main chain of the
+node are disconnected from the index along with all of their effects before
+the blocks of the new main chain are indexed, so the index is always
+consistent with the node after a reorganization.
+
+The indexed data is served as HTML pages and as a JSON API.  Ticket pool
+statistics, agenda voting progress, and the treasury balance are obtained from
+vgld on request.  The JSON API provides the following endpoints:
+
+	/api/status             Tip of the index and running totals
+	/api/blocks             Most recent blocks; optional height and count
+	                        parameters select a page of older blocks
+	/api/block/{id}         Block by height or hash
+	/api/tx/{txid}          Transaction
+	/api/address/{addr}     Address totals and transactions; optional skip
+	                        and count parameters select a page
+	/api/ticket/{hash}      Ticket status
+	/api/ticketpool         Ticket pool size, value, and stake difficulty
+	/api/agendas            Agenda voting progress; optional version
+	                        parameter selects the vote version
+	/api/treasury           Treasury balance and recent treasury spends
+
+Usage:
+
+	vglexplorer [OPTIONS]
+
+Application Options:
+
+	-A, --appdata=             Path to application home directory
+	-C, --configfile=          Path to configuration file
+	-b, --datadir=             Directory to store data
+	    --logdir=              Directory to log output
+	    --testnet              Use the test network
+	    --simnet               Use the simulation test network
+	    --regnet               Use the regression test network
+	-d, --debuglevel=          Logging level for all subsystems {trace, debug,
+	                           info, warn, error, critical} (info)
+	    --noderpcconnect=      Hostname/IP and port of the vgld RPC server
+	                           (default port: 9109, testnet: 19109)
+	    --noderpcuser=         Username for vgld RPC connections
+	    --noderpcpass=         Password for vgld RPC connections
+	    --noderpccert=         File containing the vgld RPC certificate
+	    --listen=              Add an interface/port to serve the explorer on
+	                           (default: localhost:7777)
+
+Help Options:
+
+	-h, --help                 Show this help message
+*/
+package main
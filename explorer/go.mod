@@ -0,0 +1,30 @@
+module github.com/kdsmith18542/vigil/explorer
+
+go 1.23.0
+
+require (
+	github.com/jessevdk/go-flags v1.5.0
+	github.com/jrick/logrotate v1.0.0
+	github.com/kdsmith18542/vigil/blockchain/stake/v5 v5.0.1
+	github.com/kdsmith18542/vigil/chaincfg/chainhash v1.0.4
+	github.com/kdsmith18542/vigil/chaincfg/v3 v3.2.1
+	github.com/kdsmith18542/vigil/dcrtest/vgldtest v1.0.1-0.20240404170936-a2529e936df1
+	github.com/kdsmith18542/vigil/dcrutil/v4 v4.0.2
+	github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4 v4.2.0
+	github.com/kdsmith18542/vigil/rpcclient/v8 v8.0.1
+	github.com/kdsmith18542/vigil/slog v1.2.0
+	github.com/kdsmith18542/vigil/txscript/v4 v4.1.1
+	github.com/kdsmith18542/vigil/wire v1.7.0
+	go.etcd.io/bbolt v1.3.11
+)
+
+replace (
+	github.com/kdsmith18542/vigil/blockchain/stake/v5 => ../node/blockchain/stake
+	github.com/kdsmith18542/vigil/chaincfg/chainhash => ../node/chaincfg/chainhash
+	github.com/kdsmith18542/vigil/chaincfg/v3 => ../node/chaincfg
+	github.com/kdsmith18542/vigil/dcrutil/v4 => ../node/dcrutil
+	github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4 => ../node/rpc/jsonrpc/types
+	github.com/kdsmith18542/vigil/rpcclient/v8 => ../node/rpcclient
+	github.com/kdsmith18542/vigil/txscript/v4 => ../node/txscript
+	github.com/kdsmith18542/vigil/wire => ../node/wire
+)
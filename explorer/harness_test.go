@@ -0,0 +1,237 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// This file is ignored during the regular tests due to the following build tag.
+//go:build rpctest
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/dcrtest/vgldtest"
+	"github.com/kdsmith18542/vigil/explorer/internal/explorerdb"
+)
+
+// waitForSync waits for the tip of the explorer database to match the best
+// block of the harness node.
+func waitForSync(ctx context.Context, t *testing.T, h *vgldtest.Harness, db *explorerdb.DB) *explorerdb.Block {
+	t.Helper()
+	bestHash, bestHeight, err := h.Node.GetBestBlock(ctx)
+	if err != nil {
+		t.Fatalf("unable to get best block: %v", err)
+	}
+	deadline := time.Now().Add(time.Second * 30)
+	for time.Now().Before(deadline) {
+		tip, err := db.Tip()
+		if err == nil && tip.Hash == bestHash.String() {
+			if tip.Height != bestHeight {
+				t.Fatalf("mismatched tip height -- got %d, want %d",
+					tip.Height, bestHeight)
+			}
+			return tip
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+	t.Fatalf("explorer did not sync to block %s (height %d)", bestHash,
+		bestHeight)
+	return nil
+}
+
+// invalidateBlock invalidates the provided block on the harness node which
+// makes the node reorganize to its parent.
+func invalidateBlock(ctx context.Context, t *testing.T, h *vgldtest.Harness, hash *chainhash.Hash) {
+	t.Helper()
+	param, err := json.Marshal(hash.String())
+	if err != nil {
+		t.Fatalf("unable to marshal block hash: %v", err)
+	}
+	_, err = h.Node.RawRequest(ctx, "invalidateblock",
+		[]json.RawMessage{param})
+	if err != nil {
+		t.Fatalf("unable to invalidate block %s: %v", hash, err)
+	}
+}
+
+// getJSON performs a GET request for the provided path on the test server and
+// decodes the JSON response into v.
+func getJSON(t *testing.T, srv *httptest.Server, path string, v interface{}) {
+	t.Helper()
+	resp, err := http.Get(srv.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("unable to decode response of GET %s: %v", path, err)
+	}
+}
+
+// TestExplorer ensures the explorer follows the main chain of a simnet node
+// including reorganizations and serves the indexed data via its API.
+func TestExplorer(t *testing.T) {
+	params := chaincfg.SimNetParams()
+	harness, err := vgldtest.New(t, params, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to create harness: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := harness.SetUp(ctx, true, 25); err != nil {
+		// Even though the harness was not fully setup, it still needs
+		// to be torn down to ensure all resources such as temp
+		// directories are cleaned up.
+		_ = harness.TearDown()
+		t.Fatalf("unable to setup test chain: %v", err)
+	}
+	defer harness.TearDownInTest(t)
+
+	db, err := explorerdb.Open(filepath.Join(t.TempDir(), "explorer.db"))
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer db.Close()
+
+	rpcCfg := harness.RPCConfig()
+	e, err := newExplorer(params, db, &rpcCfg)
+	if err != nil {
+		t.Fatalf("unable to create explorer: %v", err)
+	}
+	runErr := make(chan error, 1)
+	go func() { runErr <- e.run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-runErr; err != nil {
+			t.Errorf("explorer run failed: %v", err)
+		}
+	}()
+
+	// Ensure the initial chain and newly generated blocks are indexed.
+	waitForSync(ctx, t, harness, db)
+	if _, err := harness.Node.Generate(ctx, 2); err != nil {
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
+	tip := waitForSync(ctx, t, harness, db)
+
+	// Every block must match the main chain of the node.
+	for height := int64(0); height <= tip.Height; height++ {
+		hash, err := harness.Node.GetBlockHash(ctx, height)
+		if err != nil {
+			t.Fatalf("unable to get block hash: %v", err)
+		}
+		block, err := db.BlockByHeight(height)
+		if err != nil {
+			t.Fatalf("unable to fetch block at height %d: %v", height, err)
+		}
+		if block.Hash != hash.String() {
+			t.Fatalf("mismatched block at height %d -- got %s, want %s",
+				height, block.Hash, hash)
+		}
+	}
+
+	// Reorganize the last two blocks away and extend the new main chain
+	// beyond the old tip.
+	orphaned, err := db.Blocks(tip.Height, 2)
+	if err != nil {
+		t.Fatalf("unable to fetch blocks: %v", err)
+	}
+	forkHash, err := chainhash.NewHashFromStr(orphaned[1].Hash)
+	if err != nil {
+		t.Fatalf("unable to parse block hash: %v", err)
+	}
+	invalidateBlock(ctx, t, harness, forkHash)
+	if _, err := harness.Node.Generate(ctx, 3); err != nil {
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
+	newTip := waitForSync(ctx, t, harness, db)
+	if newTip.Height != tip.Height+1 {
+		t.Fatalf("unexpected tip height after reorg -- got %d, want %d",
+			newTip.Height, tip.Height+1)
+	}
+	for _, block := range orphaned {
+		_, err := db.BlockByHash(block.Hash)
+		if !errors.Is(err, explorerdb.ErrBlockNotFound) {
+			t.Fatalf("orphaned block %s is still indexed (err %v)",
+				block.Hash, err)
+		}
+		_, err = db.Tx(block.TxIDs[0])
+		if !errors.Is(err, explorerdb.ErrTxNotFound) {
+			t.Fatalf("coinbase of orphaned block %s is still indexed "+
+				"(err %v)", block.Hash, err)
+		}
+	}
+
+	// Ensure the API serves the indexed chain and the node data.
+	handler, err := e.handler()
+	if err != nil {
+		t.Fatalf("unable to create handler: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	var status statusResponse
+	getJSON(t, srv, "/api/status", &status)
+	if status.Hash != newTip.Hash || status.Height != newTip.Height {
+		t.Fatalf("unexpected status -- got %s (height %d), want %s "+
+			"(height %d)", status.Hash, status.Height, newTip.Hash,
+			newTip.Height)
+	}
+
+	var blocks []*explorerdb.Block
+	getJSON(t, srv, "/api/blocks?count=5", &blocks)
+	if len(blocks) != 5 || blocks[0].Hash != newTip.Hash {
+		t.Fatalf("unexpected blocks -- got %d blocks", len(blocks))
+	}
+
+	var tx explorerdb.Tx
+	getJSON(t, srv, "/api/tx/"+newTip.TxIDs[0], &tx)
+	if tx.Type != explorerdb.TxCoinbase || tx.BlockHash != newTip.Hash {
+		t.Fatalf("unexpected coinbase -- got type %s in block %s", tx.Type,
+			tx.BlockHash)
+	}
+	for _, out := range tx.Outputs {
+		if len(out.Addresses) == 0 {
+			continue
+		}
+		var addr addressResponse
+		getJSON(t, srv, "/api/address/"+out.Addresses[0], &addr)
+		if addr.NumTxns == 0 || addr.Received == 0 {
+			t.Fatalf("unexpected address summary -- got %+v",
+				addr.AddressSummary)
+		}
+		break
+	}
+
+	var pool ticketPoolResponse
+	getJSON(t, srv, "/api/ticketpool", &pool)
+	var agendas json.RawMessage
+	getJSON(t, srv, "/api/agendas", &agendas)
+	var treasury treasuryResponse
+	getJSON(t, srv, "/api/treasury", &treasury)
+
+	for _, path := range []string{"/", "/block/1", "/tx/" + tx.TxID,
+		"/tickets", "/agendas", "/treasury"} {
+
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s returned status %d", path, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/explorer/internal/explorerdb"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdscript"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// txType returns the explorer database type of the transaction at the provided
+// index of the given tree.
+func txType(tx *wire.MsgTx, tree int8, index int) explorerdb.TxType {
+	if tree == wire.TxTreeRegular {
+		if index == 0 {
+			return explorerdb.TxCoinbase
+		}
+		return explorerdb.TxRegular
+	}
+
+	switch stake.DetermineTxType(tx) {
+	case stake.TxTypeSStx:
+		return explorerdb.TxTicket
+	case stake.TxTypeSSGen:
+		return explorerdb.TxVote
+	case stake.TxTypeSSRtx:
+		return explorerdb.TxRevocation
+	case stake.TxTypeTAdd:
+		return explorerdb.TxTreasuryAdd
+	case stake.TxTypeTSpend:
+		return explorerdb.TxTreasurySpend
+	case stake.TxTypeTreasuryBase:
+		return explorerdb.TxTreasuryBase
+	}
+	return explorerdb.TxRegular
+}
+
+// indexTx converts the transaction at the provided index of the given tree of
+// a block to its explorer database representation.
+func indexTx(tx *wire.MsgTx, block *explorerdb.Block, tree int8, index int, params *chaincfg.Params) *explorerdb.Tx {
+	t := &explorerdb.Tx{
+		TxID:        tx.TxHash().String(),
+		BlockHash:   block.Hash,
+		BlockHeight: block.Height,
+		BlockIndex:  uint32(index),
+		Tree:        tree,
+		Type:        txType(tx, tree, index),
+		Size:        tx.SerializeSize(),
+		Expiry:      tx.Expiry,
+		Inputs:      make([]explorerdb.Input, 0, len(tx.TxIn)),
+		Outputs:     make([]explorerdb.Output, 0, len(tx.TxOut)),
+	}
+
+	// Inputs that do not spend a previous output such as those of coinbases,
+	// stakebases, treasury bases, and treasury spends reference the zero
+	// hash and are indexed without a previous transaction.
+	var amountIn, amountOut int64
+	for i, txIn := range tx.TxIn {
+		in := explorerdb.Input{AmountIn: txIn.ValueIn}
+		isGenerated := t.Type == explorerdb.TxCoinbase ||
+			t.Type == explorerdb.TxTreasuryBase ||
+			t.Type == explorerdb.TxTreasurySpend ||
+			(t.Type == explorerdb.TxVote && i == 0)
+		if !isGenerated {
+			prevOut := &txIn.PreviousOutPoint
+			in.PrevTxID = prevOut.Hash.String()
+			in.PrevIndex = prevOut.Index
+			in.PrevTree = prevOut.Tree
+		}
+		t.Inputs = append(t.Inputs, in)
+		amountIn += txIn.ValueIn
+	}
+	for _, txOut := range tx.TxOut {
+		scriptType, addrs := stdscript.ExtractAddrs(txOut.Version,
+			txOut.PkScript, params)
+		out := explorerdb.Output{
+			Value:      txOut.Value,
+			Version:    txOut.Version,
+			ScriptType: scriptType.String(),
+		}
+		for _, addr := range addrs {
+			out.Addresses = append(out.Addresses, addr.String())
+		}
+		t.Outputs = append(t.Outputs, out)
+		amountOut += txOut.Value
+	}
+
+	switch t.Type {
+	case explorerdb.TxCoinbase, explorerdb.TxTreasuryBase, explorerdb.TxVote:
+	default:
+		t.Fee = amountIn - amountOut
+	}
+
+	if t.Type == explorerdb.TxVote {
+		votedHash, votedHeight := stake.SSGenBlockVotedOn(tx)
+		t.Vote = &explorerdb.VoteInfo{
+			TicketHash:  tx.TxIn[1].PreviousOutPoint.Hash.String(),
+			BlockHash:   votedHash.String(),
+			BlockHeight: votedHeight,
+			VoteBits:    stake.SSGenVoteBits(tx),
+			Version:     stake.SSGenVersion(tx),
+		}
+	}
+	return t
+}
+
+// indexBlock converts the provided block to its explorer database
+// representation.
+func indexBlock(msgBlock *wire.MsgBlock, params *chaincfg.Params) *explorerdb.BlockData {
+	header := &msgBlock.Header
+	block := &explorerdb.Block{
+		Hash:        header.BlockHash().String(),
+		PrevHash:    header.PrevBlock.String(),
+		Height:      int64(header.Height),
+		Time:        header.Timestamp.Unix(),
+		Size:        header.Size,
+		Bits:        header.Bits,
+		SBits:       header.SBits,
+		Voters:      header.Voters,
+		FreshStake:  header.FreshStake,
+		Revocations: header.Revocations,
+		PoolSize:    header.PoolSize,
+		TxIDs:       make([]string, 0, len(msgBlock.Transactions)),
+		STxIDs:      make([]string, 0, len(msgBlock.STransactions)),
+	}
+
+	data := &explorerdb.BlockData{
+		Block: block,
+		Txns: make([]*explorerdb.Tx, 0, len(msgBlock.Transactions)+
+			len(msgBlock.STransactions)),
+	}
+	for i, tx := range msgBlock.Transactions {
+		t := indexTx(tx, block, wire.TxTreeRegular, i, params)
+		block.TxIDs = append(block.TxIDs, t.TxID)
+		data.Txns = append(data.Txns, t)
+	}
+	for i, tx := range msgBlock.STransactions {
+		t := indexTx(tx, block, wire.TxTreeStake, i, params)
+		block.STxIDs = append(block.STxIDs, t.TxID)
+		data.Txns = append(data.Txns, t)
+	}
+	return data
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package explorerdb
+
+// ErrorKind identifies a kind of error.  It has full support for errors.Is and
+// errors.As, so the caller can directly check against an error kind when
+// determining the reason for an error.
+type ErrorKind string
+
+// These constants are used to identify a specific Error.
+const (
+	// ErrBlockNotFound indicates a block is not indexed in the database.
+	ErrBlockNotFound = ErrorKind("ErrBlockNotFound")
+
+	// ErrTxNotFound indicates a transaction is not indexed in the database.
+	ErrTxNotFound = ErrorKind("ErrTxNotFound")
+
+	// ErrTicketNotFound indicates a ticket is not indexed in the database.
+	ErrTicketNotFound = ErrorKind("ErrTicketNotFound")
+
+	// ErrNotTipChild indicates an attempt to connect a block that does not
+	// extend the current tip of the indexed chain.
+	ErrNotTipChild = ErrorKind("ErrNotTipChild")
+
+	// ErrEmptyChain indicates an attempt to access or disconnect the tip when
+	// no blocks are indexed.
+	ErrEmptyChain = ErrorKind("ErrEmptyChain")
+
+	// ErrBadVersion indicates the database was created by a newer version of
+	// the software.
+	ErrBadVersion = ErrorKind("ErrBadVersion")
+)
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e ErrorKind) Error() string {
+	return string(e)
+}
+
+// Error identifies an error related to the explorer database.  It has full
+// support for errors.Is and errors.As, so the caller can ascertain the specific
+// reason for the error by checking the underlying error.
+type Error struct {
+	Description string
+	Err         error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e Error) Error() string {
+	return e.Description
+}
+
+// Unwrap returns the underlying wrapped error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// makeError creates an Error given a set of arguments.
+func makeError(kind ErrorKind, desc string) Error {
+	return Error{Err: kind, Description: desc}
+}
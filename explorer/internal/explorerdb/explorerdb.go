@@ -0,0 +1,858 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package explorerdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// dbVersion is the current version of the database layout.
+	dbVersion = 1
+
+	// addrKeySep separates the address from the height and transaction hash
+	// in the keys of the addresses bucket.
+	addrKeySep = 0x00
+)
+
+var (
+	metaBucket      = []byte("meta")
+	blocksBucket    = []byte("blocks")
+	blockHashBucket = []byte("blockhashes")
+	txnsBucket      = []byte("txns")
+	addrBucket      = []byte("addresses")
+	ticketsBucket   = []byte("tickets")
+	tspendsBucket   = []byte("tspends")
+	versionKey      = []byte("version")
+	tipKey          = []byte("tip")
+	statsKey        = []byte("stats")
+)
+
+// TxType identifies the type of a transaction.
+type TxType string
+
+// These constants define the possible transaction types.
+const (
+	TxRegular       TxType = "regular"
+	TxCoinbase      TxType = "coinbase"
+	TxTicket        TxType = "ticket"
+	TxVote          TxType = "vote"
+	TxRevocation    TxType = "revocation"
+	TxTreasuryAdd   TxType = "treasuryadd"
+	TxTreasurySpend TxType = "treasuryspend"
+	TxTreasuryBase  TxType = "treasurybase"
+)
+
+// TicketStatus identifies the state of a ticket.
+type TicketStatus string
+
+// These constants define the possible ticket statuses.
+const (
+	// TicketUnspent indicates the ticket has not yet voted or been revoked.
+	// It is either immature, live, or missed but not yet revoked.
+	TicketUnspent TicketStatus = "unspent"
+
+	// TicketVoted indicates the ticket was spent by a vote.
+	TicketVoted TicketStatus = "voted"
+
+	// TicketRevoked indicates the ticket was spent by a revocation.
+	TicketRevoked TicketStatus = "revoked"
+)
+
+// Block is an indexed block.  The transaction hashes of the regular and stake
+// trees are in block order.
+type Block struct {
+	Hash        string   `json:"hash"`
+	PrevHash    string   `json:"prevhash"`
+	Height      int64    `json:"height"`
+	Time        int64    `json:"time"`
+	Size        uint32   `json:"size"`
+	Bits        uint32   `json:"bits"`
+	SBits       int64    `json:"sbits"`
+	Voters      uint16   `json:"voters"`
+	FreshStake  uint8    `json:"freshstake"`
+	Revocations uint8    `json:"revocations"`
+	PoolSize    uint32   `json:"poolsize"`
+	TxIDs       []string `json:"tx"`
+	STxIDs      []string `json:"stx"`
+}
+
+// Input is a transaction input.  The previous transaction hash is empty for
+// inputs that do not spend a previous output such as those of a coinbase.
+// The addresses are those of the spent output and are filled in when the
+// block is connected.
+type Input struct {
+	PrevTxID  string   `json:"prevtxid,omitempty"`
+	PrevIndex uint32   `json:"previndex"`
+	PrevTree  int8     `json:"prevtree"`
+	AmountIn  int64    `json:"amountin"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// Output is a transaction output.  SpentBy is the hash of the transaction
+// that spends the output once it is spent.
+type Output struct {
+	Value      int64    `json:"value"`
+	Version    uint16   `json:"version"`
+	ScriptType string   `json:"scripttype"`
+	Addresses  []string `json:"addresses,omitempty"`
+	SpentBy    string   `json:"spentby,omitempty"`
+}
+
+// VoteInfo houses the details specific to votes.
+type VoteInfo struct {
+	TicketHash  string `json:"ticket"`
+	BlockHash   string `json:"blockhash"`
+	BlockHeight uint32 `json:"blockheight"`
+	VoteBits    uint16 `json:"votebits"`
+	Version     uint32 `json:"version"`
+}
+
+// Tx is an indexed transaction.  The fee is zero for transactions that do not
+// pay one such as coinbases and votes.
+type Tx struct {
+	TxID        string    `json:"txid"`
+	BlockHash   string    `json:"blockhash"`
+	BlockHeight int64     `json:"blockheight"`
+	BlockIndex  uint32    `json:"blockindex"`
+	Tree        int8      `json:"tree"`
+	Type        TxType    `json:"type"`
+	Size        int       `json:"size"`
+	Fee         int64     `json:"fee"`
+	Expiry      uint32    `json:"expiry"`
+	Inputs      []Input   `json:"vin"`
+	Outputs     []Output  `json:"vout"`
+	Vote        *VoteInfo `json:"vote,omitempty"`
+}
+
+// BlockData houses a block along with all of its transactions, regular tree
+// first, to connect to the indexed chain.
+type BlockData struct {
+	Block *Block
+	Txns  []*Tx
+}
+
+// Ticket is an indexed ticket.  Price is the amount committed to the ticket in
+// atoms.
+type Ticket struct {
+	Hash        string       `json:"hash"`
+	Height      int64        `json:"height"`
+	Price       int64        `json:"price"`
+	Status      TicketStatus `json:"status"`
+	SpentBy     string       `json:"spentby,omitempty"`
+	SpentHeight int64        `json:"spentheight,omitempty"`
+}
+
+// AddressTx describes the effect of a transaction on the balance of an
+// address.  The amounts are in atoms.
+type AddressTx struct {
+	TxID     string `json:"txid"`
+	Height   int64  `json:"height"`
+	Received int64  `json:"received"`
+	Sent     int64  `json:"sent"`
+}
+
+// AddressSummary houses the totals of all indexed transactions of an address.
+// The amounts are in atoms.
+type AddressSummary struct {
+	Address  string `json:"address"`
+	NumTxns  int64  `json:"numtxns"`
+	Received int64  `json:"received"`
+	Sent     int64  `json:"sent"`
+	Balance  int64  `json:"balance"`
+}
+
+// Stats houses running totals for the indexed chain.
+type Stats struct {
+	NumTxns        int64 `json:"numtxns"`
+	NumTickets     int64 `json:"numtickets"`
+	UnspentTickets int64 `json:"unspenttickets"`
+	NumVotes       int64 `json:"numvotes"`
+	NumRevocations int64 `json:"numrevocations"`
+	NumTSpends     int64 `json:"numtspends"`
+}
+
+// DB houses the indexed blocks, transactions, addresses, tickets, and
+// treasury spends of the explorer.
+type DB struct {
+	bdb *bolt.DB
+}
+
+// Open opens the database at the provided path, creating it when it does not
+// exist.
+func Open(path string) (*DB, error) {
+	bdb, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = bdb.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{metaBucket, blocksBucket,
+			blockHashBucket, txnsBucket, addrBucket, ticketsBucket,
+			tspendsBucket} {
+
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		meta := tx.Bucket(metaBucket)
+		v := meta.Get(versionKey)
+		if v == nil {
+			var b [4]byte
+			binary.BigEndian.PutUint32(b[:], dbVersion)
+			return meta.Put(versionKey, b[:])
+		}
+		if len(v) != 4 || binary.BigEndian.Uint32(v) > dbVersion {
+			str := fmt.Sprintf("explorer database version %x is newer "+
+				"than the supported version %d", v, dbVersion)
+			return makeError(ErrBadVersion, str)
+		}
+		return nil
+	})
+	if err != nil {
+		bdb.Close()
+		return nil, err
+	}
+	return &DB{bdb: bdb}, nil
+}
+
+// Close closes the database.
+func (db *DB) Close() error {
+	return db.bdb.Close()
+}
+
+// heightKey returns the key for the blocks bucket for the provided height.
+func heightKey(height int64) []byte {
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], uint64(height))
+	return key[:]
+}
+
+// addrKeyPrefix returns the prefix of all keys in the addresses bucket for the
+// provided address.
+func addrKeyPrefix(addr string) []byte {
+	prefix := make([]byte, 0, len(addr)+1)
+	prefix = append(prefix, addr...)
+	return append(prefix, addrKeySep)
+}
+
+// addrKey returns the key for the addresses bucket for the provided address,
+// height, and transaction hash.  Keys sort by height for each address.
+func addrKey(addr string, height int64, txID string) []byte {
+	key := addrKeyPrefix(addr)
+	key = append(key, heightKey(height)...)
+	return append(key, txID...)
+}
+
+// tspendKey returns the key for the tspends bucket for the provided height and
+// transaction hash.
+func tspendKey(height int64, txID string) []byte {
+	return append(heightKey(height), txID...)
+}
+
+// getJSON unmarshals the value of the provided key in the bucket into v and
+// returns whether or not the key exists.
+func getJSON(bucket *bolt.Bucket, key []byte, v interface{}) (bool, error) {
+	b := bucket.Get(key)
+	if b == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+// putJSON stores the JSON encoding of v in the bucket under the provided key.
+func putJSON(bucket *bolt.Bucket, key []byte, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, b)
+}
+
+// fetchTip returns the tip of the indexed chain.
+func fetchTip(tx *bolt.Tx) (*Block, error) {
+	meta := tx.Bucket(metaBucket)
+	v := meta.Get(tipKey)
+	if v == nil {
+		return nil, makeError(ErrEmptyChain, "no blocks are indexed")
+	}
+	return fetchBlockByHeight(tx, int64(binary.BigEndian.Uint64(v)))
+}
+
+// fetchBlockByHeight returns the indexed block at the provided height.
+func fetchBlockByHeight(tx *bolt.Tx, height int64) (*Block, error) {
+	var block Block
+	ok, err := getJSON(tx.Bucket(blocksBucket), heightKey(height), &block)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		str := fmt.Sprintf("block at height %d is not indexed", height)
+		return nil, makeError(ErrBlockNotFound, str)
+	}
+	return &block, nil
+}
+
+// fetchTx returns the indexed transaction with the provided hash.
+func fetchTx(tx *bolt.Tx, txID string) (*Tx, error) {
+	var t Tx
+	ok, err := getJSON(tx.Bucket(txnsBucket), []byte(txID), &t)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		str := fmt.Sprintf("transaction %s is not indexed", txID)
+		return nil, makeError(ErrTxNotFound, str)
+	}
+	return &t, nil
+}
+
+// fetchStats returns the running totals for the indexed chain.
+func fetchStats(tx *bolt.Tx) (*Stats, error) {
+	var stats Stats
+	_, err := getJSON(tx.Bucket(metaBucket), statsKey, &stats)
+	return &stats, err
+}
+
+// ticketSpent returns the hash of the ticket spent by the provided vote or
+// revocation, if any.
+func ticketSpent(t *Tx) string {
+	switch t.Type {
+	case TxVote:
+		// The first input of a vote is the stakebase.
+		if len(t.Inputs) > 1 {
+			return t.Inputs[1].PrevTxID
+		}
+	case TxRevocation:
+		if len(t.Inputs) > 0 {
+			return t.Inputs[0].PrevTxID
+		}
+	}
+	return ""
+}
+
+// addressTxns returns the effect of the provided transaction on the balance of
+// every address it involves keyed by address.
+func addressTxns(t *Tx) map[string]*AddressTx {
+	addrTxns := make(map[string]*AddressTx)
+	entry := func(addr string) *AddressTx {
+		addrTx, ok := addrTxns[addr]
+		if !ok {
+			addrTx = &AddressTx{TxID: t.TxID, Height: t.BlockHeight}
+			addrTxns[addr] = addrTx
+		}
+		return addrTx
+	}
+	for i := range t.Inputs {
+		for _, addr := range t.Inputs[i].Addresses {
+			entry(addr).Sent += t.Inputs[i].AmountIn
+		}
+	}
+	for i := range t.Outputs {
+		for _, addr := range t.Outputs[i].Addresses {
+			entry(addr).Received += t.Outputs[i].Value
+		}
+	}
+	return addrTxns
+}
+
+// ConnectBlock indexes the provided block and its transactions.  The block
+// must extend the current tip of the indexed chain unless no blocks are
+// indexed yet.
+//
+// The addresses of the inputs are filled in from the outputs they spend and
+// the spent outputs are marked as spent by the spending transaction.
+func (db *DB) ConnectBlock(data *BlockData) error {
+	block := data.Block
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		tip, err := fetchTip(tx)
+		switch {
+		case err == nil:
+			if block.Height != tip.Height+1 || block.PrevHash != tip.Hash {
+				str := fmt.Sprintf("block %s (height %d) does not extend "+
+					"the indexed tip %s (height %d)", block.Hash,
+					block.Height, tip.Hash, tip.Height)
+				return makeError(ErrNotTipChild, str)
+			}
+		case !errors.Is(err, ErrEmptyChain):
+			return err
+		}
+		stats, err := fetchStats(tx)
+		if err != nil {
+			return err
+		}
+
+		// Resolve the spent outputs from the transactions in the block
+		// itself or the previously indexed ones.
+		inBlock := make(map[string]*Tx, len(data.Txns))
+		for _, t := range data.Txns {
+			inBlock[t.TxID] = t
+		}
+		spentTxns := make(map[string]*Tx)
+		for _, t := range data.Txns {
+			for i := range t.Inputs {
+				in := &t.Inputs[i]
+				if in.PrevTxID == "" {
+					continue
+				}
+				prev, ok := inBlock[in.PrevTxID]
+				if !ok {
+					prev, ok = spentTxns[in.PrevTxID]
+				}
+				if !ok {
+					prev, err = fetchTx(tx, in.PrevTxID)
+					if errors.Is(err, ErrTxNotFound) {
+						continue
+					}
+					if err != nil {
+						return err
+					}
+					spentTxns[in.PrevTxID] = prev
+				}
+				if in.PrevIndex >= uint32(len(prev.Outputs)) {
+					continue
+				}
+				out := &prev.Outputs[in.PrevIndex]
+				in.Addresses = out.Addresses
+				out.SpentBy = t.TxID
+			}
+		}
+		txns := tx.Bucket(txnsBucket)
+		for txID, prev := range spentTxns {
+			if err := putJSON(txns, []byte(txID), prev); err != nil {
+				return err
+			}
+		}
+
+		addrs := tx.Bucket(addrBucket)
+		tickets := tx.Bucket(ticketsBucket)
+		tspends := tx.Bucket(tspendsBucket)
+		for _, t := range data.Txns {
+			if err := putJSON(txns, []byte(t.TxID), t); err != nil {
+				return err
+			}
+			for addr, addrTx := range addressTxns(t) {
+				key := addrKey(addr, block.Height, t.TxID)
+				if err := putJSON(addrs, key, addrTx); err != nil {
+					return err
+				}
+			}
+			stats.NumTxns++
+
+			switch t.Type {
+			case TxTicket:
+				var price int64
+				if len(t.Outputs) > 0 {
+					price = t.Outputs[0].Value
+				}
+				ticket := &Ticket{
+					Hash:   t.TxID,
+					Height: block.Height,
+					Price:  price,
+					Status: TicketUnspent,
+				}
+				if err := putJSON(tickets, []byte(t.TxID), ticket); err != nil {
+					return err
+				}
+				stats.NumTickets++
+				stats.UnspentTickets++
+
+			case TxVote, TxRevocation:
+				status := TicketVoted
+				stats.NumVotes++
+				if t.Type == TxRevocation {
+					status = TicketRevoked
+					stats.NumVotes--
+					stats.NumRevocations++
+				}
+				var ticket Ticket
+				hash := []byte(ticketSpent(t))
+				ok, err := getJSON(tickets, hash, &ticket)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+				ticket.Status = status
+				ticket.SpentBy = t.TxID
+				ticket.SpentHeight = block.Height
+				if err := putJSON(tickets, hash, &ticket); err != nil {
+					return err
+				}
+				stats.UnspentTickets--
+
+			case TxTreasurySpend:
+				key := tspendKey(block.Height, t.TxID)
+				if err := tspends.Put(key, nil); err != nil {
+					return err
+				}
+				stats.NumTSpends++
+			}
+		}
+
+		err = putJSON(tx.Bucket(blocksBucket), heightKey(block.Height), block)
+		if err != nil {
+			return err
+		}
+		hashes := tx.Bucket(blockHashBucket)
+		err = hashes.Put([]byte(block.Hash), heightKey(block.Height))
+		if err != nil {
+			return err
+		}
+		meta := tx.Bucket(metaBucket)
+		if err := putJSON(meta, statsKey, stats); err != nil {
+			return err
+		}
+		return meta.Put(tipKey, heightKey(block.Height))
+	})
+}
+
+// DisconnectTip removes the tip block of the indexed chain along with all of
+// its transactions and reverts the effects they had on previously indexed
+// data.  It returns the removed block.
+func (db *DB) DisconnectTip() (*Block, error) {
+	var block *Block
+	err := db.bdb.Update(func(tx *bolt.Tx) error {
+		var err error
+		block, err = fetchTip(tx)
+		if err != nil {
+			return err
+		}
+		stats, err := fetchStats(tx)
+		if err != nil {
+			return err
+		}
+
+		// Load the transactions of the block in reverse order so that
+		// spends are undone before the outputs they spend.
+		txIDs := make([]string, 0, len(block.TxIDs)+len(block.STxIDs))
+		txIDs = append(txIDs, block.TxIDs...)
+		txIDs = append(txIDs, block.STxIDs...)
+		inBlock := make(map[string]struct{}, len(txIDs))
+		for _, txID := range txIDs {
+			inBlock[txID] = struct{}{}
+		}
+
+		txns := tx.Bucket(txnsBucket)
+		addrs := tx.Bucket(addrBucket)
+		tickets := tx.Bucket(ticketsBucket)
+		tspends := tx.Bucket(tspendsBucket)
+		spentTxns := make(map[string]*Tx)
+		for i := len(txIDs) - 1; i >= 0; i-- {
+			t, err := fetchTx(tx, txIDs[i])
+			if err != nil {
+				return err
+			}
+
+			// Mark the outputs spent by the transaction as unspent.
+			for j := range t.Inputs {
+				in := &t.Inputs[j]
+				if in.PrevTxID == "" {
+					continue
+				}
+				if _, ok := inBlock[in.PrevTxID]; ok {
+					continue
+				}
+				prev, ok := spentTxns[in.PrevTxID]
+				if !ok {
+					prev, err = fetchTx(tx, in.PrevTxID)
+					if errors.Is(err, ErrTxNotFound) {
+						continue
+					}
+					if err != nil {
+						return err
+					}
+					spentTxns[in.PrevTxID] = prev
+				}
+				if in.PrevIndex >= uint32(len(prev.Outputs)) {
+					continue
+				}
+				out := &prev.Outputs[in.PrevIndex]
+				if out.SpentBy == t.TxID {
+					out.SpentBy = ""
+				}
+			}
+
+			for addr := range addressTxns(t) {
+				if err := addrs.Delete(addrKey(addr, block.Height, t.TxID)); err != nil {
+					return err
+				}
+			}
+			if err := txns.Delete([]byte(t.TxID)); err != nil {
+				return err
+			}
+			stats.NumTxns--
+
+			switch t.Type {
+			case TxTicket:
+				if err := tickets.Delete([]byte(t.TxID)); err != nil {
+					return err
+				}
+				stats.NumTickets--
+				stats.UnspentTickets--
+
+			case TxVote, TxRevocation:
+				if t.Type == TxVote {
+					stats.NumVotes--
+				} else {
+					stats.NumRevocations--
+				}
+				var ticket Ticket
+				hash := []byte(ticketSpent(t))
+				ok, err := getJSON(tickets, hash, &ticket)
+				if err != nil {
+					return err
+				}
+				if !ok || ticket.SpentBy != t.TxID {
+					continue
+				}
+				ticket.Status = TicketUnspent
+				ticket.SpentBy = ""
+				ticket.SpentHeight = 0
+				if err := putJSON(tickets, hash, &ticket); err != nil {
+					return err
+				}
+				stats.UnspentTickets++
+
+			case TxTreasurySpend:
+				key := tspendKey(block.Height, t.TxID)
+				if err := tspends.Delete(key); err != nil {
+					return err
+				}
+				stats.NumTSpends--
+			}
+		}
+		for txID, prev := range spentTxns {
+			if err := putJSON(txns, []byte(txID), prev); err != nil {
+				return err
+			}
+		}
+
+		err = tx.Bucket(blocksBucket).Delete(heightKey(block.Height))
+		if err != nil {
+			return err
+		}
+		err = tx.Bucket(blockHashBucket).Delete([]byte(block.Hash))
+		if err != nil {
+			return err
+		}
+		meta := tx.Bucket(metaBucket)
+		if err := putJSON(meta, statsKey, stats); err != nil {
+			return err
+		}
+
+		// The chain is empty once the first indexed block is removed.
+		_, err = fetchBlockByHeight(tx, block.Height-1)
+		if errors.Is(err, ErrBlockNotFound) {
+			return meta.Delete(tipKey)
+		}
+		if err != nil {
+			return err
+		}
+		return meta.Put(tipKey, heightKey(block.Height-1))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// Tip returns the tip block of the indexed chain.  An error with the kind
+// ErrEmptyChain is returned when no blocks are indexed.
+func (db *DB) Tip() (*Block, error) {
+	var block *Block
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		var err error
+		block, err = fetchTip(tx)
+		return err
+	})
+	return block, err
+}
+
+// BlockByHeight returns the indexed block at the provided height.
+func (db *DB) BlockByHeight(height int64) (*Block, error) {
+	var block *Block
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		var err error
+		block, err = fetchBlockByHeight(tx, height)
+		return err
+	})
+	return block, err
+}
+
+// BlockByHash returns the indexed block with the provided hash.
+func (db *DB) BlockByHash(hash string) (*Block, error) {
+	var block *Block
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(blockHashBucket).Get([]byte(hash))
+		if v == nil {
+			str := fmt.Sprintf("block %s is not indexed", hash)
+			return makeError(ErrBlockNotFound, str)
+		}
+		var err error
+		block, err = fetchBlockByHeight(tx, int64(binary.BigEndian.Uint64(v)))
+		return err
+	})
+	return block, err
+}
+
+// Blocks returns up to count indexed blocks in descending order of height
+// starting with the block at the provided height.
+func (db *DB) Blocks(height int64, count int) ([]*Block, error) {
+	var blocks []*Block
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		for ; height >= 0 && len(blocks) < count; height-- {
+			block, err := fetchBlockByHeight(tx, height)
+			if errors.Is(err, ErrBlockNotFound) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			blocks = append(blocks, block)
+		}
+		return nil
+	})
+	return blocks, err
+}
+
+// Tx returns the indexed transaction with the provided hash.
+func (db *DB) Tx(txID string) (*Tx, error) {
+	var t *Tx
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		var err error
+		t, err = fetchTx(tx, txID)
+		return err
+	})
+	return t, err
+}
+
+// Txns returns the indexed transactions with the provided hashes.
+func (db *DB) Txns(txIDs []string) ([]*Tx, error) {
+	txns := make([]*Tx, 0, len(txIDs))
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		for _, txID := range txIDs {
+			t, err := fetchTx(tx, txID)
+			if err != nil {
+				return err
+			}
+			txns = append(txns, t)
+		}
+		return nil
+	})
+	return txns, err
+}
+
+// AddressSummary returns the totals of all indexed transactions of the
+// provided address.
+func (db *DB) AddressSummary(addr string) (*AddressSummary, error) {
+	summary := &AddressSummary{Address: addr}
+	prefix := addrKeyPrefix(addr)
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(addrBucket).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var addrTx AddressTx
+			if err := json.Unmarshal(v, &addrTx); err != nil {
+				return err
+			}
+			summary.NumTxns++
+			summary.Received += addrTx.Received
+			summary.Sent += addrTx.Sent
+		}
+		return nil
+	})
+	summary.Balance = summary.Received - summary.Sent
+	return summary, err
+}
+
+// AddressTxns returns up to count indexed transactions of the provided address
+// newest first after skipping the provided number of transactions.
+func (db *DB) AddressTxns(addr string, skip, count int) ([]*AddressTx, error) {
+	var addrTxns []*AddressTx
+	prefix := addrKeyPrefix(addr)
+	end := append(bytes.Clone(prefix), 0xff)
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(addrBucket).Cursor()
+		k, v := c.Seek(end)
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, v = c.Prev() {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if len(addrTxns) >= count {
+				break
+			}
+			var addrTx AddressTx
+			if err := json.Unmarshal(v, &addrTx); err != nil {
+				return err
+			}
+			addrTxns = append(addrTxns, &addrTx)
+		}
+		return nil
+	})
+	return addrTxns, err
+}
+
+// Ticket returns the indexed ticket with the provided hash.
+func (db *DB) Ticket(hash string) (*Ticket, error) {
+	var ticket Ticket
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		ok, err := getJSON(tx.Bucket(ticketsBucket), []byte(hash), &ticket)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			str := fmt.Sprintf("ticket %s is not indexed", hash)
+			return makeError(ErrTicketNotFound, str)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// TSpends returns up to count indexed treasury spends newest first.
+func (db *DB) TSpends(count int) ([]*Tx, error) {
+	var tspends []*Tx
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(tspendsBucket).Cursor()
+		for k, _ := c.Last(); k != nil && len(tspends) < count; k, _ = c.Prev() {
+			t, err := fetchTx(tx, string(k[8:]))
+			if err != nil {
+				return err
+			}
+			tspends = append(tspends, t)
+		}
+		return nil
+	})
+	return tspends, err
+}
+
+// Stats returns the running totals for the indexed chain.
+func (db *DB) Stats() (*Stats, error) {
+	var stats *Stats
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		var err error
+		stats, err = fetchStats(tx)
+		return err
+	})
+	return stats, err
+}
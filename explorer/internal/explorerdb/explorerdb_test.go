@@ -0,0 +1,312 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package explorerdb
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// openTestDB opens a new database in a temporary directory that is closed when
+// the test finishes.
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "explorer.db"))
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// testChain returns a chain of three blocks where the first block pays the
+// coinbase to address A, the second block purchases a ticket with it, and the
+// third block votes the ticket to address B and includes a treasury spend.
+func testChain() []*BlockData {
+	coinbase := &Tx{
+		TxID:    "c0",
+		Type:    TxCoinbase,
+		Inputs:  []Input{{}},
+		Outputs: []Output{{Value: 1000, Addresses: []string{"A"}}},
+	}
+	block0 := &BlockData{
+		Block: &Block{Hash: "b0", Height: 0, TxIDs: []string{"c0"}},
+		Txns:  []*Tx{coinbase},
+	}
+
+	coinbase1 := &Tx{
+		TxID:    "c1",
+		Type:    TxCoinbase,
+		Inputs:  []Input{{}},
+		Outputs: []Output{{Value: 500, Addresses: []string{"C"}}},
+	}
+	ticket := &Tx{
+		TxID:   "t1",
+		Tree:   1,
+		Type:   TxTicket,
+		Fee:    100,
+		Inputs: []Input{{PrevTxID: "c0", AmountIn: 1000}},
+		Outputs: []Output{
+			{Value: 900, Addresses: []string{"T"}},
+			{Addresses: []string{"A"}},
+		},
+	}
+	block1 := &BlockData{
+		Block: &Block{
+			Hash:       "b1",
+			PrevHash:   "b0",
+			Height:     1,
+			FreshStake: 1,
+			TxIDs:      []string{"c1"},
+			STxIDs:     []string{"t1"},
+		},
+		Txns: []*Tx{coinbase1, ticket},
+	}
+
+	vote := &Tx{
+		TxID: "v2",
+		Tree: 1,
+		Type: TxVote,
+		Inputs: []Input{
+			{AmountIn: 50},
+			{PrevTxID: "t1", PrevTree: 1, AmountIn: 900},
+		},
+		Outputs: []Output{{}, {}, {Value: 950, Addresses: []string{"B"}}},
+		Vote:    &VoteInfo{TicketHash: "t1", BlockHash: "b1", BlockHeight: 1},
+	}
+	tspend := &Tx{
+		TxID:    "s2",
+		Tree:    1,
+		Type:    TxTreasurySpend,
+		Inputs:  []Input{{AmountIn: 10}},
+		Outputs: []Output{{}, {Value: 10, Addresses: []string{"D"}}},
+	}
+	block2 := &BlockData{
+		Block: &Block{
+			Hash:     "b2",
+			PrevHash: "b1",
+			Height:   2,
+			Voters:   1,
+			STxIDs:   []string{"v2", "s2"},
+		},
+		Txns: []*Tx{vote, tspend},
+	}
+
+	return []*BlockData{block0, block1, block2}
+}
+
+// TestConnectDisconnect ensures connecting blocks indexes their transactions,
+// addresses, tickets, and treasury spends and that disconnecting them reverts
+// all of their effects.
+func TestConnectDisconnect(t *testing.T) {
+	db := openTestDB(t)
+
+	if _, err := db.Tip(); !errors.Is(err, ErrEmptyChain) {
+		t.Fatalf("unexpected error for empty tip -- got %v, want %v", err,
+			ErrEmptyChain)
+	}
+
+	chain := testChain()
+	for _, data := range chain {
+		if err := db.ConnectBlock(data); err != nil {
+			t.Fatalf("unexpected error connecting block %s: %v",
+				data.Block.Hash, err)
+		}
+	}
+
+	// Blocks that do not extend the tip must be rejected.
+	orphan := &BlockData{Block: &Block{Hash: "x", PrevHash: "b1", Height: 2}}
+	if err := db.ConnectBlock(orphan); !errors.Is(err, ErrNotTipChild) {
+		t.Fatalf("unexpected error connecting orphan -- got %v, want %v",
+			err, ErrNotTipChild)
+	}
+
+	tip, err := db.Tip()
+	if err != nil {
+		t.Fatalf("unexpected error fetching tip: %v", err)
+	}
+	if tip.Hash != "b2" {
+		t.Fatalf("unexpected tip -- got %s, want b2", tip.Hash)
+	}
+	block, err := db.BlockByHash("b1")
+	if err != nil {
+		t.Fatalf("unexpected error fetching block: %v", err)
+	}
+	if block.Height != 1 {
+		t.Fatalf("unexpected block height -- got %d, want 1", block.Height)
+	}
+	blocks, err := db.Blocks(2, 5)
+	if err != nil {
+		t.Fatalf("unexpected error fetching blocks: %v", err)
+	}
+	if len(blocks) != 3 || blocks[0].Hash != "b2" || blocks[2].Hash != "b0" {
+		t.Fatalf("unexpected blocks -- got %d blocks", len(blocks))
+	}
+
+	// The coinbase output must be spent by the ticket and the ticket input
+	// must have the address of the spent output.
+	coinbase, err := db.Tx("c0")
+	if err != nil {
+		t.Fatalf("unexpected error fetching tx: %v", err)
+	}
+	if coinbase.Outputs[0].SpentBy != "t1" {
+		t.Fatalf("unexpected spender -- got %q, want t1",
+			coinbase.Outputs[0].SpentBy)
+	}
+	ticketTx, err := db.Tx("t1")
+	if err != nil {
+		t.Fatalf("unexpected error fetching tx: %v", err)
+	}
+	if !reflect.DeepEqual(ticketTx.Inputs[0].Addresses, []string{"A"}) {
+		t.Fatalf("unexpected input addresses -- got %v, want [A]",
+			ticketTx.Inputs[0].Addresses)
+	}
+
+	ticket, err := db.Ticket("t1")
+	if err != nil {
+		t.Fatalf("unexpected error fetching ticket: %v", err)
+	}
+	wantTicket := &Ticket{
+		Hash:        "t1",
+		Height:      1,
+		Price:       900,
+		Status:      TicketVoted,
+		SpentBy:     "v2",
+		SpentHeight: 2,
+	}
+	if !reflect.DeepEqual(ticket, wantTicket) {
+		t.Fatalf("unexpected ticket -- got %+v, want %+v", ticket, wantTicket)
+	}
+
+	summary, err := db.AddressSummary("A")
+	if err != nil {
+		t.Fatalf("unexpected error fetching address summary: %v", err)
+	}
+	wantSummary := &AddressSummary{
+		Address:  "A",
+		NumTxns:  2,
+		Received: 1000,
+		Sent:     1000,
+	}
+	if !reflect.DeepEqual(summary, wantSummary) {
+		t.Fatalf("unexpected address summary -- got %+v, want %+v", summary,
+			wantSummary)
+	}
+	addrTxns, err := db.AddressTxns("A", 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error fetching address txns: %v", err)
+	}
+	if len(addrTxns) != 2 || addrTxns[0].TxID != "t1" || addrTxns[1].TxID != "c0" {
+		t.Fatalf("unexpected address txns -- got %+v", addrTxns)
+	}
+	addrTxns, err = db.AddressTxns("A", 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error fetching address txns: %v", err)
+	}
+	if len(addrTxns) != 1 || addrTxns[0].TxID != "c0" {
+		t.Fatalf("unexpected address txns after skip -- got %+v", addrTxns)
+	}
+
+	tspends, err := db.TSpends(10)
+	if err != nil {
+		t.Fatalf("unexpected error fetching tspends: %v", err)
+	}
+	if len(tspends) != 1 || tspends[0].TxID != "s2" {
+		t.Fatalf("unexpected tspends -- got %+v", tspends)
+	}
+
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatalf("unexpected error fetching stats: %v", err)
+	}
+	wantStats := &Stats{NumTxns: 5, NumTickets: 1, NumVotes: 1, NumTSpends: 1}
+	if !reflect.DeepEqual(stats, wantStats) {
+		t.Fatalf("unexpected stats -- got %+v, want %+v", stats, wantStats)
+	}
+
+	// Disconnecting the tip must make the ticket unspent again and remove
+	// the vote and treasury spend.
+	disconnected, err := db.DisconnectTip()
+	if err != nil {
+		t.Fatalf("unexpected error disconnecting tip: %v", err)
+	}
+	if disconnected.Hash != "b2" {
+		t.Fatalf("unexpected disconnected block -- got %s, want b2",
+			disconnected.Hash)
+	}
+	ticket, err = db.Ticket("t1")
+	if err != nil {
+		t.Fatalf("unexpected error fetching ticket: %v", err)
+	}
+	if ticket.Status != TicketUnspent || ticket.SpentBy != "" {
+		t.Fatalf("unexpected ticket after disconnect -- got %+v", ticket)
+	}
+	if _, err := db.Tx("v2"); !errors.Is(err, ErrTxNotFound) {
+		t.Fatalf("unexpected error fetching vote -- got %v, want %v", err,
+			ErrTxNotFound)
+	}
+	if _, err := db.BlockByHash("b2"); !errors.Is(err, ErrBlockNotFound) {
+		t.Fatalf("unexpected error fetching block -- got %v, want %v", err,
+			ErrBlockNotFound)
+	}
+	tspends, err = db.TSpends(10)
+	if err != nil {
+		t.Fatalf("unexpected error fetching tspends: %v", err)
+	}
+	if len(tspends) != 0 {
+		t.Fatalf("unexpected tspends after disconnect -- got %+v", tspends)
+	}
+	summary, err = db.AddressSummary("B")
+	if err != nil {
+		t.Fatalf("unexpected error fetching address summary: %v", err)
+	}
+	if summary.NumTxns != 0 {
+		t.Fatalf("unexpected address txns after disconnect -- got %d",
+			summary.NumTxns)
+	}
+
+	// Disconnecting the ticket purchase must mark the coinbase unspent.
+	if _, err := db.DisconnectTip(); err != nil {
+		t.Fatalf("unexpected error disconnecting tip: %v", err)
+	}
+	coinbase, err = db.Tx("c0")
+	if err != nil {
+		t.Fatalf("unexpected error fetching tx: %v", err)
+	}
+	if coinbase.Outputs[0].SpentBy != "" {
+		t.Fatalf("unexpected spender after disconnect -- got %q",
+			coinbase.Outputs[0].SpentBy)
+	}
+	if _, err := db.Ticket("t1"); !errors.Is(err, ErrTicketNotFound) {
+		t.Fatalf("unexpected error fetching ticket -- got %v, want %v", err,
+			ErrTicketNotFound)
+	}
+
+	// Disconnecting the final block must leave an empty chain.
+	if _, err := db.DisconnectTip(); err != nil {
+		t.Fatalf("unexpected error disconnecting tip: %v", err)
+	}
+	if _, err := db.DisconnectTip(); !errors.Is(err, ErrEmptyChain) {
+		t.Fatalf("unexpected error disconnecting empty chain -- got %v, "+
+			"want %v", err, ErrEmptyChain)
+	}
+	stats, err = db.Stats()
+	if err != nil {
+		t.Fatalf("unexpected error fetching stats: %v", err)
+	}
+	if !reflect.DeepEqual(stats, &Stats{}) {
+		t.Fatalf("unexpected stats after disconnect -- got %+v", stats)
+	}
+
+	// The chain must be able to be indexed again from scratch.
+	for _, data := range testChain() {
+		if err := db.ConnectBlock(data); err != nil {
+			t.Fatalf("unexpected error reconnecting block %s: %v",
+				data.Block.Hash, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+)
+
+// params is used to group parameters for various networks such as the main
+// network and test networks.
+type params struct {
+	*chaincfg.Params
+	nodeRPCPort string
+}
+
+// mainNetParams contains parameters specific to the main network
+// (wire.MainNet).
+var mainNetParams = params{
+	Params:      chaincfg.MainNetParams(),
+	nodeRPCPort: "9109",
+}
+
+// testNet3Params contains parameters specific to the test network (version 3)
+// (wire.TestNet3).
+var testNet3Params = params{
+	Params:      chaincfg.TestNet3Params(),
+	nodeRPCPort: "19109",
+}
+
+// simNetParams contains parameters specific to the simulation test network
+// (wire.SimNet).
+var simNetParams = params{
+	Params:      chaincfg.SimNetParams(),
+	nodeRPCPort: "19556",
+}
+
+// regNetParams contains parameters specific to the regression test
+// network (wire.RegNet).
+var regNetParams = params{
+	Params:      chaincfg.RegNetParams(),
+	nodeRPCPort: "18656",
+}
@@ -0,0 +1,646 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/explorer/internal/explorerdb"
+	chainjson "github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+)
+
+const (
+	// defaultPageSize is the number of blocks, address transactions, and
+	// treasury spends returned when no count is requested.
+	defaultPageSize = 20
+
+	// maxPageSize is the maximum number of blocks, address transactions, and
+	// treasury spends that can be requested at once.
+	maxPageSize = 100
+
+	// atomsPerCoin is the number of atoms in one coin.
+	atomsPerCoin = 1e8
+
+	// shutdownTimeout is the maximum amount of time to wait for in-flight
+	// HTTP requests to complete on shutdown.
+	shutdownTimeout = time.Second * 10
+)
+
+var (
+	// errInvalidRequest is wrapped by errors that are the result of invalid
+	// request parameters.
+	errInvalidRequest = errors.New("invalid request")
+
+	// errPageNotFound is returned for requests of pages that do not exist.
+	errPageNotFound = errors.New("page not found")
+)
+
+// templateFS houses the HTML templates of the explorer pages.
+//
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// statusResponse is the response to a status request.
+type statusResponse struct {
+	Network string            `json:"network"`
+	Height  int64             `json:"height"`
+	Hash    string            `json:"hash"`
+	Stats   *explorerdb.Stats `json:"stats"`
+}
+
+// blockResponse is the response to a block request.
+type blockResponse struct {
+	*explorerdb.Block
+	Txns  []*explorerdb.Tx `json:"-"`
+	STxns []*explorerdb.Tx `json:"-"`
+}
+
+// addressResponse is the response to an address request.
+type addressResponse struct {
+	*explorerdb.AddressSummary
+	Skip  int                     `json:"skip"`
+	Txns  []*explorerdb.AddressTx `json:"txns"`
+	Count int                     `json:"-"`
+}
+
+// ticketPoolResponse is the response to a ticket pool request.  The amounts
+// are in coins.
+type ticketPoolResponse struct {
+	PoolSize            uint32  `json:"poolsize"`
+	PoolValue           float64 `json:"poolvalue"`
+	StakeDifficulty     float64 `json:"stakedifficulty"`
+	NextStakeDifficulty float64 `json:"nextstakedifficulty"`
+	TargetPoolSize      int64   `json:"targetpoolsize"`
+	*explorerdb.Stats
+}
+
+// treasuryResponse is the response to a treasury request.  The balance is in
+// coins.
+type treasuryResponse struct {
+	Height  int64            `json:"height"`
+	Hash    string           `json:"hash"`
+	Balance float64          `json:"balance"`
+	TSpends []*explorerdb.Tx `json:"tspends"`
+}
+
+// errorResponse is the response to a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// coin returns the provided amount of atoms formatted as coins.
+func coin(atoms int64) string {
+	return strconv.FormatFloat(float64(atoms)/atomsPerCoin, 'f', -1, 64)
+}
+
+// parseTemplates parses the embedded HTML templates of the explorer pages.
+func parseTemplates() (*template.Template, error) {
+	funcs := template.FuncMap{
+		"add":  func(a, b int) int { return a + b },
+		"coin": coin,
+		"time": func(unix int64) string {
+			return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+		},
+	}
+	return template.New("").Funcs(funcs).ParseFS(templateFS,
+		"templates/*.html")
+}
+
+// errorCode returns the HTTP status code for the provided error.
+func errorCode(err error) int {
+	switch {
+	case errors.Is(err, errInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, errPageNotFound),
+		errors.Is(err, explorerdb.ErrBlockNotFound),
+		errors.Is(err, explorerdb.ErrTxNotFound),
+		errors.Is(err, explorerdb.ErrTicketNotFound),
+		errors.Is(err, explorerdb.ErrEmptyChain):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// errorMessage returns the message to show for the provided error.  Internal
+// errors are logged and replaced by a generic message.
+func errorMessage(code int, err error) string {
+	if code == http.StatusInternalServerError {
+		explLog.Errorf("Unable to serve request: %v", err)
+		return "internal error"
+	}
+	return err.Error()
+}
+
+// writeJSON writes the provided value as a JSON response with the provided
+// HTTP status code.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		explLog.Debugf("Unable to write response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response for the provided error.
+func writeError(w http.ResponseWriter, err error) {
+	code := errorCode(err)
+	writeJSON(w, code, &errorResponse{Error: errorMessage(code, err)})
+}
+
+// pageParams parses the optional skip and count query parameters of the
+// provided request.
+func pageParams(r *http.Request) (int, int, error) {
+	skip, count := 0, defaultPageSize
+	if v := r.URL.Query().Get("skip"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("%w: invalid skip %q", errInvalidRequest, v)
+		}
+		skip = n
+	}
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageSize {
+			return 0, 0, fmt.Errorf("%w: count must be between 1 and %d",
+				errInvalidRequest, maxPageSize)
+		}
+		count = n
+	}
+	return skip, count, nil
+}
+
+// isHash returns whether or not the provided string is a hex-encoded hash.
+func isHash(s string) bool {
+	if len(s) != chainhash.MaxHashStringSize {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// status returns the tip of the indexed chain along with its running totals.
+func (e *explorer) status() (*statusResponse, error) {
+	tip, err := e.db.Tip()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := e.db.Stats()
+	if err != nil {
+		return nil, err
+	}
+	return &statusResponse{
+		Network: e.params.Name,
+		Height:  tip.Height,
+		Hash:    tip.Hash,
+		Stats:   stats,
+	}, nil
+}
+
+// blocks returns the indexed blocks requested by the optional height and
+// count query parameters of the provided request newest first.  The blocks
+// start at the tip when no height is requested.
+func (e *explorer) blocks(r *http.Request) ([]*explorerdb.Block, error) {
+	_, count, err := pageParams(r)
+	if err != nil {
+		return nil, err
+	}
+	var height int64
+	if v := r.URL.Query().Get("height"); v != "" {
+		height, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || height < 0 {
+			return nil, fmt.Errorf("%w: invalid height %q", errInvalidRequest,
+				v)
+		}
+	} else {
+		tip, err := e.db.Tip()
+		if err != nil {
+			return nil, err
+		}
+		height = tip.Height
+	}
+	return e.db.Blocks(height, count)
+}
+
+// block returns the indexed block identified by the provided height or hash.
+// The transactions of the block are only loaded when requested.
+func (e *explorer) block(id string, withTxns bool) (*blockResponse, error) {
+	var block *explorerdb.Block
+	var err error
+	if isHash(id) {
+		block, err = e.db.BlockByHash(id)
+	} else {
+		height, perr := strconv.ParseInt(id, 10, 64)
+		if perr != nil || height < 0 {
+			return nil, fmt.Errorf("%w: %q is not a block height or hash",
+				errInvalidRequest, id)
+		}
+		block, err = e.db.BlockByHeight(height)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &blockResponse{Block: block}
+	if withTxns {
+		resp.Txns, err = e.db.Txns(block.TxIDs)
+		if err != nil {
+			return nil, err
+		}
+		resp.STxns, err = e.db.Txns(block.STxIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// address returns the totals and a page of the transactions of the address
+// requested by the provided request.
+func (e *explorer) address(r *http.Request) (*addressResponse, error) {
+	addr := r.PathValue("addr")
+	if _, err := stdaddr.DecodeAddress(addr, e.params); err != nil {
+		return nil, fmt.Errorf("%w: invalid %s address %q", errInvalidRequest,
+			e.params.Name, addr)
+	}
+	skip, count, err := pageParams(r)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := e.db.AddressSummary(addr)
+	if err != nil {
+		return nil, err
+	}
+	txns, err := e.db.AddressTxns(addr, skip, count)
+	if err != nil {
+		return nil, err
+	}
+	return &addressResponse{
+		AddressSummary: summary,
+		Skip:           skip,
+		Txns:           txns,
+		Count:          count,
+	}, nil
+}
+
+// ticketPool returns the state of the ticket pool as reported by the node
+// along with the ticket totals of the indexed chain.
+func (e *explorer) ticketPool(ctx context.Context) (*ticketPoolResponse, error) {
+	poolValue, err := e.node.GetTicketPoolValue(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ticket pool value: %w", err)
+	}
+	stakeDiff, err := e.node.GetStakeDifficulty(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get stake difficulty: %w", err)
+	}
+	tip, err := e.db.Tip()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := e.db.Stats()
+	if err != nil {
+		return nil, err
+	}
+	return &ticketPoolResponse{
+		PoolSize:            tip.PoolSize,
+		PoolValue:           poolValue.ToCoin(),
+		StakeDifficulty:     stakeDiff.CurrentStakeDifficulty,
+		NextStakeDifficulty: stakeDiff.NextStakeDifficulty,
+		TargetPoolSize: int64(e.params.TicketPoolSize) *
+			int64(e.params.TicketsPerBlock),
+		Stats: stats,
+	}, nil
+}
+
+// agendas returns the voting state of the agendas of the vote version
+// requested by the optional version query parameter of the provided request.
+// The latest vote version defined by the network is used when no version is
+// requested.
+func (e *explorer) agendas(r *http.Request) (*chainjson.GetVoteInfoResult, error) {
+	var version uint32
+	if v := r.URL.Query().Get("version"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid version %q", errInvalidRequest,
+				v)
+		}
+		version = uint32(n)
+	} else {
+		for v := range e.params.Deployments {
+			if v > version {
+				version = v
+			}
+		}
+	}
+	info, err := e.node.GetVoteInfo(r.Context(), version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get vote info: %w", err)
+	}
+	return info, nil
+}
+
+// treasury returns the treasury balance as reported by the node along with
+// the most recent treasury spends of the indexed chain.
+func (e *explorer) treasury(ctx context.Context) (*treasuryResponse, error) {
+	balance, err := e.node.GetTreasuryBalance(ctx, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get treasury balance: %w", err)
+	}
+	tspends, err := e.db.TSpends(defaultPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &treasuryResponse{
+		Height:  balance.Height,
+		Hash:    balance.Hash,
+		Balance: float64(balance.Balance) / atomsPerCoin,
+		TSpends: tspends,
+	}, nil
+}
+
+// handleAPIStatus serves the tip and running totals of the indexed chain.
+func (e *explorer) handleAPIStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := e.status()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleAPIBlocks serves a page of indexed blocks.
+func (e *explorer) handleAPIBlocks(w http.ResponseWriter, r *http.Request) {
+	blocks, err := e.blocks(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, blocks)
+}
+
+// handleAPIBlock serves an indexed block.
+func (e *explorer) handleAPIBlock(w http.ResponseWriter, r *http.Request) {
+	block, err := e.block(r.PathValue("id"), false)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, block)
+}
+
+// handleAPITx serves an indexed transaction.
+func (e *explorer) handleAPITx(w http.ResponseWriter, r *http.Request) {
+	tx, err := e.db.Tx(r.PathValue("txid"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tx)
+}
+
+// handleAPIAddress serves the totals and a page of transactions of an
+// address.
+func (e *explorer) handleAPIAddress(w http.ResponseWriter, r *http.Request) {
+	addr, err := e.address(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, addr)
+}
+
+// handleAPITicket serves an indexed ticket.
+func (e *explorer) handleAPITicket(w http.ResponseWriter, r *http.Request) {
+	ticket, err := e.db.Ticket(r.PathValue("hash"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ticket)
+}
+
+// handleAPITicketPool serves the state of the ticket pool.
+func (e *explorer) handleAPITicketPool(w http.ResponseWriter, r *http.Request) {
+	pool, err := e.ticketPool(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, pool)
+}
+
+// handleAPIAgendas serves the voting state of the agendas.
+func (e *explorer) handleAPIAgendas(w http.ResponseWriter, r *http.Request) {
+	agendas, err := e.agendas(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, agendas)
+}
+
+// handleAPITreasury serves the treasury balance and recent treasury spends.
+func (e *explorer) handleAPITreasury(w http.ResponseWriter, r *http.Request) {
+	treasury, err := e.treasury(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, treasury)
+}
+
+// pageServer serves the HTML pages of the explorer.
+type pageServer struct {
+	e    *explorer
+	tmpl *template.Template
+}
+
+// render renders the named template with the provided data.  The error page
+// is rendered instead when the provided error is not nil.
+func (s *pageServer) render(w http.ResponseWriter, name string, data interface{}, err error) {
+	code := http.StatusOK
+	if err != nil {
+		code = errorCode(err)
+		name = "error.html"
+		data = errorMessage(code, err)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if err := s.tmpl.ExecuteTemplate(w, name, data); err != nil {
+		explLog.Errorf("Unable to render %s: %v", name, err)
+	}
+}
+
+// handleHome serves the home page with the most recent blocks.
+func (s *pageServer) handleHome(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		s.render(w, "", nil, errPageNotFound)
+		return
+	}
+	var data struct {
+		Status *statusResponse
+		Blocks []*explorerdb.Block
+	}
+	var err error
+	data.Status, err = s.e.status()
+	if err == nil {
+		data.Blocks, err = s.e.blocks(r)
+	}
+	s.render(w, "home.html", &data, err)
+}
+
+// handleBlock serves the page of a block.
+func (s *pageServer) handleBlock(w http.ResponseWriter, r *http.Request) {
+	block, err := s.e.block(r.PathValue("id"), true)
+	s.render(w, "block.html", block, err)
+}
+
+// handleTx serves the page of a transaction.
+func (s *pageServer) handleTx(w http.ResponseWriter, r *http.Request) {
+	var data struct {
+		*explorerdb.Tx
+		Ticket *explorerdb.Ticket
+	}
+	var err error
+	data.Tx, err = s.e.db.Tx(r.PathValue("txid"))
+	if err == nil && data.Type == explorerdb.TxTicket {
+		data.Ticket, err = s.e.db.Ticket(data.TxID)
+	}
+	s.render(w, "tx.html", &data, err)
+}
+
+// handleAddress serves the page of an address.
+func (s *pageServer) handleAddress(w http.ResponseWriter, r *http.Request) {
+	addr, err := s.e.address(r)
+	s.render(w, "address.html", addr, err)
+}
+
+// handleTickets serves the ticket pool page.
+func (s *pageServer) handleTickets(w http.ResponseWriter, r *http.Request) {
+	pool, err := s.e.ticketPool(r.Context())
+	s.render(w, "tickets.html", pool, err)
+}
+
+// handleAgendas serves the agendas page.
+func (s *pageServer) handleAgendas(w http.ResponseWriter, r *http.Request) {
+	agendas, err := s.e.agendas(r)
+	s.render(w, "agendas.html", agendas, err)
+}
+
+// handleTreasury serves the treasury page.
+func (s *pageServer) handleTreasury(w http.ResponseWriter, r *http.Request) {
+	treasury, err := s.e.treasury(r.Context())
+	s.render(w, "treasury.html", treasury, err)
+}
+
+// handleSearch redirects to the page of the block, transaction, or address
+// given by the q query parameter.
+func (s *pageServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	var target string
+	switch {
+	case q == "":
+		target = "/"
+	case isHash(q):
+		if _, err := s.e.db.BlockByHash(q); err == nil {
+			target = "/block/" + q
+		} else {
+			target = "/tx/" + q
+		}
+	default:
+		if _, err := strconv.ParseUint(q, 10, 32); err == nil {
+			target = "/block/" + q
+		} else {
+			target = "/address/" + url.PathEscape(q)
+		}
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// handler returns the HTTP handler that serves the explorer pages and JSON
+// API.
+func (e *explorer) handler() (http.Handler, error) {
+	tmpl, err := parseTemplates()
+	if err != nil {
+		return nil, err
+	}
+	pages := &pageServer{e: e, tmpl: tmpl}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /", pages.handleHome)
+	mux.HandleFunc("GET /block/{id}", pages.handleBlock)
+	mux.HandleFunc("GET /tx/{txid}", pages.handleTx)
+	mux.HandleFunc("GET /address/{addr}", pages.handleAddress)
+	mux.HandleFunc("GET /tickets", pages.handleTickets)
+	mux.HandleFunc("GET /agendas", pages.handleAgendas)
+	mux.HandleFunc("GET /treasury", pages.handleTreasury)
+	mux.HandleFunc("GET /search", pages.handleSearch)
+	mux.HandleFunc("GET /api/status", e.handleAPIStatus)
+	mux.HandleFunc("GET /api/blocks", e.handleAPIBlocks)
+	mux.HandleFunc("GET /api/block/{id}", e.handleAPIBlock)
+	mux.HandleFunc("GET /api/tx/{txid}", e.handleAPITx)
+	mux.HandleFunc("GET /api/address/{addr}", e.handleAPIAddress)
+	mux.HandleFunc("GET /api/ticket/{hash}", e.handleAPITicket)
+	mux.HandleFunc("GET /api/ticketpool", e.handleAPITicketPool)
+	mux.HandleFunc("GET /api/agendas", e.handleAPIAgendas)
+	mux.HandleFunc("GET /api/treasury", e.handleAPITreasury)
+	return mux, nil
+}
+
+// serve serves the provided handler on the provided listeners until the
+// provided context is cancelled.
+func serve(ctx context.Context, handler http.Handler, listeners []net.Listener) error {
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: time.Second * 10,
+		ReadTimeout:       time.Second * 30,
+		WriteTimeout:      time.Second * 60,
+	}
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		explLog.Infof("Explorer listening on %s", listener.Addr())
+		wg.Add(1)
+		go func(listener net.Listener) {
+			defer wg.Done()
+			err := srv.Serve(listener)
+			if !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}(listener)
+	}
+
+	var runErr error
+	select {
+	case err := <-errCh:
+		runErr = err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(),
+		shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		explLog.Warnf("Unable to gracefully stop HTTP server: %v", err)
+	}
+	wg.Wait()
+	return runErr
+}
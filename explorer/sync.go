@@ -0,0 +1,185 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/explorer/internal/explorerdb"
+	"github.com/kdsmith18542/vigil/rpcclient/v8"
+)
+
+// syncRetryInterval is how long to wait before syncing again after a failed
+// sync.
+const syncRetryInterval = time.Second * 10
+
+// explorer houses the state of the block explorer.  It follows the main chain
+// of the node and indexes it into the explorer database.
+type explorer struct {
+	params *chaincfg.Params
+	db     *explorerdb.DB
+	node   *rpcclient.Client
+
+	// syncCh is signalled when a block is connected to or disconnected from
+	// the main chain of the node.
+	syncCh chan struct{}
+}
+
+// newExplorer returns a new explorer with a websocket connection to the node
+// RPC server described by the provided connection config.
+func newExplorer(params *chaincfg.Params, db *explorerdb.DB, connCfg *rpcclient.ConnConfig) (*explorer, error) {
+	e := &explorer{
+		params: params,
+		db:     db,
+		syncCh: make(chan struct{}, 1),
+	}
+
+	ntfnHandlers := &rpcclient.NotificationHandlers{
+		OnClientConnected: func() {
+			// Register for notifications again on reconnect.  This is
+			// done in a goroutine since the handlers must not block.
+			go e.registerNotifications()
+		},
+		OnBlockConnected: func(_ []byte, _ [][]byte) {
+			e.signalSync()
+		},
+		OnBlockDisconnected: func(_ []byte) {
+			e.signalSync()
+		},
+	}
+	cfg := *connCfg
+	cfg.Endpoint = "ws"
+	cfg.DisableConnectOnNew = true
+	node, err := rpcclient.New(&cfg, ntfnHandlers)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create vgld RPC client: %w", err)
+	}
+	e.node = node
+	return e, nil
+}
+
+// registerNotifications registers for block notifications from the node and
+// syncs any blocks that were missed while disconnected.
+func (e *explorer) registerNotifications() {
+	if err := e.node.NotifyBlocks(context.Background()); err != nil {
+		explLog.Errorf("Unable to register for block notifications: %v", err)
+	}
+	e.signalSync()
+}
+
+// signalSync signals the sync loop to sync with the node without blocking.
+func (e *explorer) signalSync() {
+	select {
+	case e.syncCh <- struct{}{}:
+	default:
+	}
+}
+
+// sync brings the explorer database in line with the main chain of the node.
+// Blocks of the indexed chain that are no longer in the main chain are
+// disconnected before the blocks of the main chain are connected.
+func (e *explorer) sync(ctx context.Context) error {
+	for {
+		_, bestHeight, err := e.node.GetBestBlock(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to get best block: %w", err)
+		}
+
+		// Disconnect blocks from the tip of the indexed chain until it is
+		// a block of the main chain that is not above the best block.
+		var height int64 = -1
+		for {
+			tip, err := e.db.Tip()
+			if errors.Is(err, explorerdb.ErrEmptyChain) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if tip.Height <= bestHeight {
+				hash, err := e.node.GetBlockHash(ctx, tip.Height)
+				if err != nil {
+					return fmt.Errorf("unable to get block hash: %w", err)
+				}
+				if hash.String() == tip.Hash {
+					height = tip.Height
+					break
+				}
+			}
+			if _, err := e.db.DisconnectTip(); err != nil {
+				return err
+			}
+			explLog.Infof("Disconnected block %s (height %d)", tip.Hash,
+				tip.Height)
+		}
+
+		// Connect the blocks of the main chain after the tip.  The sync is
+		// restarted when the main chain changes in the mean time so the
+		// blocks no longer extend the tip.
+		var reorged bool
+		for height < bestHeight && !reorged {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			hash, err := e.node.GetBlockHash(ctx, height+1)
+			if err != nil {
+				return fmt.Errorf("unable to get block hash: %w", err)
+			}
+			msgBlock, err := e.node.GetBlock(ctx, hash)
+			if err != nil {
+				return fmt.Errorf("unable to get block %s: %w", hash, err)
+			}
+			data := indexBlock(msgBlock, e.params)
+			err = e.db.ConnectBlock(data)
+			if errors.Is(err, explorerdb.ErrNotTipChild) {
+				reorged = true
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			height++
+			explLog.Debugf("Connected block %s (height %d)", hash, height)
+		}
+		if !reorged {
+			explLog.Infof("Synced to block height %d", height)
+			return nil
+		}
+	}
+}
+
+// run connects to the node and keeps the explorer database in sync with its
+// main chain until the provided context is canceled.
+func (e *explorer) run(ctx context.Context) error {
+	if err := e.node.Connect(ctx, true); err != nil {
+		return fmt.Errorf("unable to connect to vgld: %w", err)
+	}
+	defer func() {
+		e.node.Shutdown()
+		e.node.WaitForShutdown()
+	}()
+
+	retry := time.NewTimer(0)
+	defer retry.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-e.syncCh:
+		case <-retry.C:
+		}
+		if err := e.sync(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			explLog.Errorf("Unable to sync with vgld: %v", err)
+			retry.Reset(syncRetryInterval)
+		}
+	}
+}
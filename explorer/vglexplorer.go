@@ -0,0 +1,128 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kdsmith18542/vigil/explorer/internal/explorerdb"
+	"github.com/kdsmith18542/vigil/rpcclient/v8"
+)
+
+// run is the real main function for vglexplorer.  It is necessary to work
+// around the fact that deferred functions do not run when os.Exit() is called.
+func run() error {
+	// Load configuration and parse command line.  This function also
+	// initializes logging and configures it accordingly.
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if logRotator != nil {
+			logRotator.Close()
+		}
+	}()
+
+	// Get a context that will be canceled when a shutdown signal has been
+	// triggered from an OS signal such as SIGINT (Ctrl+C).
+	ctx := shutdownListener()
+	defer explLog.Info("Shutdown complete")
+
+	explLog.Infof("Home dir: %s", cfg.HomeDir)
+	if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+		explLog.Errorf("Unable to create data directory: %v", err)
+		return err
+	}
+
+	// Open the explorer database.
+	dbPath := filepath.Join(cfg.DataDir, defaultDBFilename)
+	db, err := explorerdb.Open(dbPath)
+	if err != nil {
+		explLog.Errorf("Unable to open explorer database: %v", err)
+		return err
+	}
+	defer func() {
+		explLog.Infof("Gracefully shutting down the explorer database...")
+		db.Close()
+	}()
+
+	// Return now if a shutdown signal was triggered.
+	if shutdownRequested(ctx) {
+		return nil
+	}
+
+	nodeCert, err := os.ReadFile(cfg.NodeRPCCert)
+	if err != nil {
+		explLog.Errorf("Unable to read vgld RPC certificate: %v", err)
+		return err
+	}
+	e, err := newExplorer(cfg.params.Params, db, &rpcclient.ConnConfig{
+		Host:         cfg.NodeRPCConnect,
+		User:         cfg.NodeRPCUser,
+		Pass:         cfg.NodeRPCPass,
+		Certificates: nodeCert,
+	})
+	if err != nil {
+		explLog.Errorf("%v", err)
+		return err
+	}
+	handler, err := e.handler()
+	if err != nil {
+		explLog.Errorf("Unable to parse templates: %v", err)
+		return err
+	}
+
+	listeners := make([]net.Listener, 0, len(cfg.Listeners))
+	for _, addr := range cfg.Listeners {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			explLog.Errorf("Unable to listen on %s: %v", addr, err)
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	// Serve the explorer while following the node and stop both when either
+	// fails.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		errs[0] = e.run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		errs[1] = serve(ctx, handler, listeners)
+	}()
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			explLog.Errorf("%v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -753,6 +753,14 @@ func EncodeHost(host string) (NetAddressType, []byte) {
 		return IPv6Address, ip
 	}
 
+	// Look for Tor v3 onion service and I2P addresses.
+	if pubKey, ok := decodeTorV3Host(host); ok {
+		return TORv3Address, pubKey
+	}
+	if hash, ok := decodeI2PHost(host); ok {
+		return I2PAddress, hash
+	}
+
 	// The given host address could not be recognized
 	return UnknownAddressType, nil
 }
@@ -1083,6 +1091,10 @@ const (
 
 	// Ipv6Strong represents a connection state between two IPv6 addresses.
 	Ipv6Strong
+
+	// Private represents a connection state between two addresses on the same
+	// overlay network such as Tor or I2P.
+	Private
 )
 
 // getRemoteReachabilityFromLocal returns the type of connection reachability
@@ -1094,6 +1106,21 @@ func getRemoteReachabilityFromLocal(localAddr, remoteAddr *NetAddress) NetAddres
 	case !remoteAddr.IsRoutable():
 		return Unreachable
 
+	case remoteAddr.Type == TORv3Address || remoteAddr.Type == I2PAddress:
+		switch {
+		case localAddr.Type == remoteAddr.Type:
+			return Private
+		case localAddr.IsRoutable() && localAddr.Type == IPv4Address:
+			return Ipv4
+		default:
+			return Default
+		}
+
+	// Overlay network addresses are only useful to peers on the same overlay
+	// network.
+	case localAddr.Type == TORv3Address || localAddr.Type == I2PAddress:
+		return Default
+
 	case isRFC4380(remoteAddr.IP):
 		switch {
 		case !localAddr.IsRoutable():
@@ -852,21 +852,38 @@ func TestGetBestLocalAddress(t *testing.T) {
 			continue
 		}
 	}
-	/*
-		// Add a Tor generated IP address
-		localAddr = wire.NetAddress{IP: net.ParseIP("fd87:d87e:eb43:25::1")}
-		amgr.AddLocalAddress(&localAddr, ManualPrio)
-
-		// Test against want3
-		for x, test := range tests {
-			got := amgr.GetBestLocalAddress(&test.remoteAddr)
-			if !test.want3.IP.Equal(got.IP) {
-				t.Errorf("TestGetBestLocalAddress test3 #%d failed for remote address %s: want %s got %s",
-					x, test.remoteAddr.IP, test.want3.IP, got.IP)
-				continue
-			}
+
+	// Store a local Tor v3 onion service address.
+	newOnionAddress := func(host string) *NetAddress {
+		addrType, addrBytes := EncodeHost(host)
+		na, err := NewNetAddressFromParams(addrType, addrBytes, 0, time.Now(),
+			wire.SFNodeNetwork)
+		if err != nil {
+			t.Fatalf("NewNetAddressFromParams: unexpected error: %v", err)
+		}
+		return na
+	}
+	localOnionAddr := newOnionAddress("2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion")
+	amgr.AddLocalAddress(localOnionAddr, ManualPrio)
+
+	// Test4: The onion service address is only suggested to remote Tor peers.
+	for x, test := range tests {
+		got := amgr.GetBestLocalAddress(test.remoteAddr, natfAny)
+		if !reflect.DeepEqual(test.want2.IP, got.IP) {
+			remoteIP := net.IP(test.remoteAddr.IP)
+			wantIP := net.IP(test.want2.IP)
+			gotIP := net.IP(got.IP)
+			t.Errorf("TestGetBestLocalAddress test4 #%d failed for remote address %s: want %s got %s",
+				x, remoteIP, wantIP, gotIP)
+			continue
 		}
-	*/
+	}
+	remoteOnionAddr := newOnionAddress("duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion")
+	got := amgr.GetBestLocalAddress(remoteOnionAddr, natfAny)
+	if !reflect.DeepEqual(localOnionAddr.IP, got.IP) {
+		t.Errorf("TestGetBestLocalAddress test4 failed for remote address %s: "+
+			"want %s got %s", remoteOnionAddr, localOnionAddr, got)
+	}
 }
 
 // TestIsExternalAddrCandidate makes sure that when a remote peer suggests that
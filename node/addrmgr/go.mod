@@ -7,12 +7,12 @@ require (
 	github.com/kdsmith18542/vigil/crypto/rand v1.0.0
 	github.com/kdsmith18542/vigil/wire v1.7.0
 	github.com/kdsmith18542/vigil/slog v1.2.0
+	golang.org/x/crypto v0.24.0
 )
 
 require (
 	github.com/kdsmith18542/vigil/crypto/blake256 v1.0.1 // indirect
 	github.com/klauspost/cpuid/v2 v2.0.9 // indirect
-	golang.org/x/crypto v0.24.0 // indirect
 	golang.org/x/sys v0.21.0 // indirect
 	lukechampine.com/blake3 v1.3.0 // indirect
 )
@@ -34,8 +34,13 @@ type NetAddress struct {
 }
 
 // IsRoutable returns a boolean indicating whether the network address is
-// routable.
+// routable.  Tor v3 and I2P addresses are always considered routable since
+// they are only reachable through their respective overlay networks.
 func (netAddr *NetAddress) IsRoutable() bool {
+	switch netAddr.Type {
+	case TORv3Address, I2PAddress:
+		return true
+	}
 	return IsRoutable(netAddr.IP)
 }
 
@@ -48,6 +53,10 @@ func (netAddr *NetAddress) ipString() string {
 		return net.IP(netIP).String()
 	case IPv4Address:
 		return net.IP(netIP).String()
+	case TORv3Address:
+		return encodeTorV3Host(netIP)
+	case I2PAddress:
+		return encodeI2PHost(netIP)
 	}
 
 	// If the netAddr.Type is not recognized in the switch:
@@ -115,6 +124,19 @@ func canonicalizeIP(addrType NetAddressType, addrBytes []byte) []byte {
 // checkNetAddressType returns an error if the suggested address type does not
 // appear to match the provided address.
 func checkNetAddressType(addrType NetAddressType, addrBytes []byte) error {
+	// Tor v3 and I2P addresses are both 32 bytes, so the type can't be derived
+	// from the address bytes and only the length is checked instead.
+	switch addrType {
+	case TORv3Address, I2PAddress:
+		if len(addrBytes) != 32 {
+			str := fmt.Sprintf("address type %v requires 32 address bytes "+
+				"(got %d bytes, address bytes %v).", addrType,
+				len(addrBytes), addrBytes)
+			return makeError(ErrMismatchedAddressType, str)
+		}
+		return nil
+	}
+
 	derivedAddressType, err := deriveNetAddressType(addrBytes)
 	if err != nil {
 		return err
@@ -157,6 +157,7 @@ func TestNewNetAddressFromParams(t *testing.T) {
 	const port = 8345
 	const services = wire.SFNodeNetwork
 	timestamp := time.Unix(time.Now().Unix(), 0)
+	_, torV3PubKey := EncodeHost("2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion")
 
 	tests := []struct {
 		name           string
@@ -206,6 +207,26 @@ func TestNewNetAddressFromParams(t *testing.T) {
 			},
 			error_expected: false,
 		},
+		{
+			name:      "32 byte tor v3 address stored in 32 bytes",
+			addrType:  TORv3Address,
+			addrBytes: torV3PubKey,
+			want: &NetAddress{
+				IP:        torV3PubKey,
+				Port:      port,
+				Services:  services,
+				Timestamp: timestamp,
+				Type:      TORv3Address,
+			},
+			error_expected: false,
+		},
+		{
+			name:           "Error: tor v3 address is not 32 bytes",
+			addrType:       TORv3Address,
+			addrBytes:      torV3PubKey[:16],
+			want:           nil,
+			error_expected: true,
+		},
 		{
 			name:           "Error: Cannot derive net address type",
 			addrType:       UnknownAddressType,
@@ -260,21 +281,22 @@ func TestNewNetAddressFromString(t *testing.T) {
 		addrString:    "1.2.3.4:abc",
 		want:          nil,
 		errorExpected: true,
-	},
-	// These tests will be added in a future commit, after a few changes to how hosts are parsed.
-	// {
-	// 	name:           "Error: ParseHost errored because the IP was not base32.StdEncoding",
-	// 	addrString:     "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA!@.onion:8345",
-	// 	want:           nil,
-	// 	errorExpected: true,
-	// },
-	// {
-	// 	name:           "Error: ParseHost did not error, but returned UnknownAddressType",
-	// 	addrString:     "abc:8345",
-	// 	want:           nil,
-	// 	errorExpected: true,
-	// },
-	}
+	}, {
+		name:          "Error: host is not valid base32",
+		addrString:    "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA!@.onion:8345",
+		want:          nil,
+		errorExpected: true,
+	}, {
+		name:          "Error: tor v3 host has an invalid checksum",
+		addrString:    "3gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion:8345",
+		want:          nil,
+		errorExpected: true,
+	}, {
+		name:          "Error: host is not a known address type",
+		addrString:    "abc:8345",
+		want:          nil,
+		errorExpected: true,
+	}}
 
 	for _, test := range tests {
 		addr, err := amgr.newNetAddressFromString(test.addrString)
@@ -6,7 +6,12 @@
 package addrmgr
 
 import (
+	"encoding/base32"
+	"fmt"
 	"net"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
 )
 
 var (
@@ -105,8 +110,107 @@ const (
 	IPv4Address        NetAddressType = 1
 	IPv6Address        NetAddressType = 2
 	// TorV2Address       NetAddressType = 3  // No longer supported
+	TORv3Address NetAddressType = 4
+	I2PAddress   NetAddressType = 5
+)
+
+const (
+	// torV3PubKeySize is the size of the ed25519 public key that identifies
+	// a Tor v3 onion service.
+	torV3PubKeySize = 32
+
+	// torV3Version is the version byte encoded in Tor v3 onion addresses.
+	torV3Version = 3
+
+	// torV3HostLen is the length of a Tor v3 onion address, excluding the
+	// .onion suffix.  It is the base32 encoding of the public key, a 2-byte
+	// checksum, and the version byte.
+	torV3HostLen = 56
+
+	// i2pHashSize is the size of the SHA-256 hash of an I2P destination that
+	// makes up its .b32.i2p address.
+	i2pHashSize = 32
+
+	// i2pHostLen is the length of a .b32.i2p address, excluding the suffix.
+	i2pHostLen = 52
 )
 
+// overlayEncoding is the lowercase unpadded base32 encoding used by both Tor
+// v3 onion addresses and I2P .b32.i2p addresses.
+var overlayEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").
+	WithPadding(base32.NoPadding)
+
+// torV3Checksum returns the checksum of the provided Tor v3 onion service
+// public key as defined by the Tor rendezvous specification.
+func torV3Checksum(pubKey []byte) [2]byte {
+	h := sha3.New256()
+	h.Write([]byte(".onion checksum"))
+	h.Write(pubKey)
+	h.Write([]byte{torV3Version})
+	var checksum [2]byte
+	copy(checksum[:], h.Sum(nil))
+	return checksum
+}
+
+// decodeTorV3Host returns the onion service public key encoded by the provided
+// Tor v3 host along with whether or not the host is a valid Tor v3 address.
+func decodeTorV3Host(host string) ([]byte, bool) {
+	host = strings.ToLower(host)
+	if !strings.HasSuffix(host, ".onion") {
+		return nil, false
+	}
+	host = strings.TrimSuffix(host, ".onion")
+	if len(host) != torV3HostLen {
+		return nil, false
+	}
+	data, err := overlayEncoding.DecodeString(host)
+	if err != nil {
+		return nil, false
+	}
+	pubKey := data[:torV3PubKeySize]
+	checksum := torV3Checksum(pubKey)
+	if data[torV3PubKeySize] != checksum[0] ||
+		data[torV3PubKeySize+1] != checksum[1] ||
+		data[torV3PubKeySize+2] != torV3Version {
+		return nil, false
+	}
+	return pubKey, true
+}
+
+// encodeTorV3Host returns the Tor v3 host, including the .onion suffix, for
+// the provided onion service public key.
+func encodeTorV3Host(pubKey []byte) string {
+	checksum := torV3Checksum(pubKey)
+	data := make([]byte, 0, torV3PubKeySize+3)
+	data = append(data, pubKey...)
+	data = append(data, checksum[0], checksum[1], torV3Version)
+	return overlayEncoding.EncodeToString(data) + ".onion"
+}
+
+// decodeI2PHost returns the destination hash encoded by the provided I2P host
+// along with whether or not the host is a valid .b32.i2p address.
+func decodeI2PHost(host string) ([]byte, bool) {
+	host = strings.ToLower(host)
+	if !strings.HasSuffix(host, ".b32.i2p") {
+		return nil, false
+	}
+	host = strings.TrimSuffix(host, ".b32.i2p")
+	if len(host) != i2pHostLen {
+		return nil, false
+	}
+	hash, err := overlayEncoding.DecodeString(host)
+	if err != nil {
+		return nil, false
+	}
+	return hash, true
+}
+
+// encodeI2PHost returns the I2P host, including the .b32.i2p suffix, for the
+// provided destination hash.
+func encodeI2PHost(hash []byte) string {
+	return overlayEncoding.EncodeToString(hash) + ".b32.i2p"
+}
+
 // NetAddressTypeFilter represents a function that returns whether a particular
 // network address type matches a filter.  Internally, it is used to ensure that
 // only addresses that pass the filter's constraints are returned by the address
@@ -226,10 +330,20 @@ func IsRoutable(netIP net.IP) bool {
 }
 
 // GroupKey returns a string representing the network group an address is part
-// of.  This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
-// "local" for a local address, and the string "unroutable" for an unroutable
-// address.
+// of.  This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the network
+// name followed by the first 4 bits of the address for Tor v3 and I2P, the
+// string "local" for a local address, and the string "unroutable" for an
+// unroutable address.
 func (na *NetAddress) GroupKey() string {
+	// Tor v3 and I2P addresses are not assigned hierarchically, so they are
+	// grouped into 16 buckets per network in the same manner as bitcoind.
+	switch na.Type {
+	case TORv3Address:
+		return fmt.Sprintf("torv3:%d", na.IP[0]&((1<<4)-1))
+	case I2PAddress:
+		return fmt.Sprintf("i2p:%d", na.IP[0]&((1<<4)-1))
+	}
+
 	netIP := net.IP(na.IP)
 	if isLocal(netIP) {
 		return "local"
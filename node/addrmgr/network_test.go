@@ -7,7 +7,9 @@ package addrmgr
 
 import (
 	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kdsmith18542/vigil/wire"
 )
@@ -198,3 +200,81 @@ func TestGroupKey(t *testing.T) {
 		}
 	}
 }
+
+// TestOverlayAddresses ensures Tor v3 and I2P hosts are recognized, encoded,
+// grouped, and converted back to their string representation as intended.
+func TestOverlayAddresses(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		addrType NetAddressType
+		groupKey string
+	}{{
+		name:     "tor v3 onion service",
+		host:     "2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion",
+		addrType: TORv3Address,
+		groupKey: "torv3:1",
+	}, {
+		name:     "tor v3 onion service 2",
+		host:     "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion",
+		addrType: TORv3Address,
+		groupKey: "torv3:13",
+	}, {
+		name:     "tor v3 onion service uppercase",
+		host:     "DUCKDUCKGOGG42XJOC72X3SJASOWOARFBGCMVFIMAFTT6TWAGSWZCZAD.onion",
+		addrType: TORv3Address,
+		groupKey: "torv3:13",
+	}, {
+		name:     "tor v3 onion service bad checksum",
+		host:     "euckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion",
+		addrType: UnknownAddressType,
+	}, {
+		name:     "tor v3 onion service bad version",
+		host:     "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczae.onion",
+		addrType: UnknownAddressType,
+	}, {
+		name:     "tor v2 onion service",
+		host:     "expyuzz4wqqyqhjn.onion",
+		addrType: UnknownAddressType,
+	}, {
+		name:     "i2p destination",
+		host:     "ukeu3k5oycgaauneqgtnvselmt4yemvoilkln7jpvamvfx7dnkdq.b32.i2p",
+		addrType: I2PAddress,
+		groupKey: "i2p:2",
+	}, {
+		name:     "i2p destination wrong length",
+		host:     "ukeu3k5oycgaauneqgtnvselmt4yemvoilkln7jpvamvfx7dnk.b32.i2p",
+		addrType: UnknownAddressType,
+	}}
+
+	for _, test := range tests {
+		addrType, addrBytes := EncodeHost(test.host)
+		if addrType != test.addrType {
+			t.Errorf("%q: mismatched address type - got %v, want %v",
+				test.name, addrType, test.addrType)
+			continue
+		}
+		if addrType == UnknownAddressType {
+			continue
+		}
+
+		na, err := NewNetAddressFromParams(addrType, addrBytes, 8333,
+			time.Now(), wire.SFNodeNetwork)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !na.IsRoutable() {
+			t.Errorf("%q: address is not routable", test.name)
+		}
+		if key := na.GroupKey(); key != test.groupKey {
+			t.Errorf("%q: unexpected group key - got %q, want %q", test.name,
+				key, test.groupKey)
+		}
+		wantKey := strings.ToLower(test.host) + ":8333"
+		if key := na.Key(); key != wantKey {
+			t.Errorf("%q: unexpected key - got %q, want %q", test.name, key,
+				wantKey)
+		}
+	}
+}
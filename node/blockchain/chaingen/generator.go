@@ -176,6 +176,11 @@ const (
 
 	// PHABlake3 specifies the blake3 hashing algorithm introduced by VGLP0011.
 	PHABlake3
+
+	// PHAKawPow specifies the KawPoW hashing algorithm.  Solving a block with
+	// it also sets the mix hash of the header since it is part of the
+	// solution.
+	PHAKawPow
 )
 
 // PowDifficultyAlgorithm defines the supported proof of work difficulty
@@ -271,6 +276,7 @@ func (g *Generator) UsePowHashAlgo(algo PowHashAlgorithm) {
 	switch algo {
 	case PHABlake256r14:
 	case PHABlake3:
+	case PHAKawPow:
 	default:
 		panic(fmt.Sprintf("unsupported proof of work hash algorithm %d", algo))
 	}
@@ -1847,7 +1853,8 @@ func hashToBig(hash *chainhash.Hash) *big.Int {
 // IsSolved returns whether or not the header hashes to a value that is less
 // than or equal to the target difficulty as specified by its bits field while
 // respecting the proof of work hashing algorithm associated with the generator
-// state.
+// state.  For KawPoW, the mix hash of the header must also match the one
+// calculated from the header.
 func (g *Generator) IsSolved(header *wire.BlockHeader) bool {
 	targetDifficulty := compactToBig(header.Bits)
 	var hash chainhash.Hash
@@ -1856,6 +1863,8 @@ func (g *Generator) IsSolved(header *wire.BlockHeader) bool {
 		hash = header.PowHashV1()
 	case PHABlake3:
 		hash = header.PowHashV2()
+	case PHAKawPow:
+		return header.VerifyKawPow(targetDifficulty) == nil
 	default:
 		panic(fmt.Sprintf("unsupported proof of work hash algorithm %d",
 			g.powHashAlgo))
@@ -1865,8 +1874,9 @@ func (g *Generator) IsSolved(header *wire.BlockHeader) bool {
 
 // solveBlock attempts to find a nonce which makes the passed block header hash
 // to a value less than the target difficulty.  When a successful solution is
-// found, true is returned and the nonce field of the passed header is updated
-// with the solution.  False is returned if no solution exists.
+// found, true is returned and the nonce field of the passed header, along with
+// the mix hash field for KawPoW, is updated with the solution.  False is
+// returned if no solution exists.
 //
 // NOTE: This function will never solve blocks with a nonce of 0.  This is done
 // so the 'NextBlock' function can properly detect when a nonce was modified by
@@ -1874,8 +1884,9 @@ func (g *Generator) IsSolved(header *wire.BlockHeader) bool {
 func (g *Generator) solveBlock(header *wire.BlockHeader) bool {
 	// sbResult is used by the solver goroutines to send results.
 	type sbResult struct {
-		found bool
-		nonce uint32
+		found   bool
+		nonce   uint64
+		mixHash chainhash.Hash
 	}
 
 	// solver accepts a block header and a nonce range to test. It is
@@ -1883,7 +1894,7 @@ func (g *Generator) solveBlock(header *wire.BlockHeader) bool {
 	targetDifficulty := compactToBig(header.Bits)
 	quit := make(chan bool)
 	results := make(chan sbResult)
-	solver := func(hdr wire.BlockHeader, startNonce, stopNonce uint32) {
+	solver := func(hdr wire.BlockHeader, startNonce, stopNonce uint64) {
 		// Choose which proof of work hash algorithm to use based on the
 		// associated state.
 		var powHashFn func() chainhash.Hash
@@ -1892,6 +1903,17 @@ func (g *Generator) solveBlock(header *wire.BlockHeader) bool {
 			powHashFn = hdr.PowHashV1
 		case PHABlake3:
 			powHashFn = hdr.PowHashV2
+		case PHAKawPow:
+			// The mix hash is part of the KawPoW solution, so it is
+			// stored in the header copy along with the nonce.
+			powHashFn = func() chainhash.Hash {
+				mixHash, finalHash, err := hdr.PowHashKawPow()
+				if err != nil {
+					panic(err)
+				}
+				hdr.MixHash = mixHash
+				return finalHash
+			}
 		default:
 			panic(fmt.Sprintf("unsupported proof of work hash algorithm %d",
 				g.powHashAlgo))
@@ -1902,25 +1924,25 @@ func (g *Generator) solveBlock(header *wire.BlockHeader) bool {
 		for i := startNonce; i >= startNonce && i <= stopNonce; i++ {
 			select {
 			case <-quit:
-				results <- sbResult{false, 0}
+				results <- sbResult{}
 				return
 			default:
 				hdr.Nonce = i
 				hash := powHashFn()
 				if hashToBig(&hash).Cmp(targetDifficulty) <= 0 {
-					results <- sbResult{true, i}
+					results <- sbResult{true, i, hdr.MixHash}
 					return
 				}
 			}
 		}
-		results <- sbResult{false, 0}
+		results <- sbResult{}
 	}
 
-	startNonce := uint32(1)
-	stopNonce := uint32(math.MaxUint32)
-	numCores := uint32(runtime.NumCPU())
+	startNonce := uint64(1)
+	stopNonce := uint64(math.MaxUint32)
+	numCores := uint64(runtime.NumCPU())
 	noncesPerCore := (stopNonce - startNonce) / numCores
-	for i := uint32(0); i < numCores; i++ {
+	for i := uint64(0); i < numCores; i++ {
 		rangeStart := startNonce + (noncesPerCore * i)
 		rangeStop := startNonce + (noncesPerCore * (i + 1)) - 1
 		if i == numCores-1 {
@@ -1929,11 +1951,12 @@ func (g *Generator) solveBlock(header *wire.BlockHeader) bool {
 		go solver(*header, rangeStart, rangeStop)
 	}
 	var foundResult bool
-	for i := uint32(0); i < numCores; i++ {
+	for i := uint64(0); i < numCores; i++ {
 		result := <-results
 		if !foundResult && result.found {
 			close(quit)
 			header.Nonce = result.nonce
+			header.MixHash = result.mixHash
 			foundResult = true
 		}
 	}
@@ -45,6 +45,10 @@ const (
 	// than the required target difficultly.
 	ErrHighHash = ErrorKind("ErrHighHash")
 
+	// ErrBadMixHash indicates the KawPoW mix hash in the block header does not
+	// match the mix hash calculated from the header and nonce.
+	ErrBadMixHash = ErrorKind("ErrBadMixHash")
+
 	// ErrBadMerkleRoot indicates the calculated merkle root does not match the
 	// expected value.
 	ErrBadMerkleRoot = ErrorKind("ErrBadMerkleRoot")
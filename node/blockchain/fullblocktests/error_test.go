@@ -24,6 +24,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrTimeTooNew, "ErrTimeTooNew"},
 		{ErrUnexpectedDifficulty, "ErrUnexpectedDifficulty"},
 		{ErrHighHash, "ErrHighHash"},
+		{ErrBadMixHash, "ErrBadMixHash"},
 		{ErrBadMerkleRoot, "ErrBadMerkleRoot"},
 		{ErrNoTransactions, "ErrNoTransactions"},
 		{ErrNoTxInputs, "ErrNoTxInputs"},
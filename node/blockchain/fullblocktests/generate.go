@@ -16,6 +16,7 @@ import (
 	"github.com/kdsmith18542/vigil/blockchain/v5/chaingen"
 	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
 	"github.com/kdsmith18542/vigil/crypto/rand"
+	"github.com/kdsmith18542/vigil/kawpow"
 	"github.com/kdsmith18542/vigil/VGLec"
 	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4"
 	"github.com/kdsmith18542/vigil/VGLutil/v4"
@@ -373,6 +374,7 @@ func Generate(includeLargeReorg bool) (tests [][]TestInstance, err error) {
 	if err != nil {
 		return nil, err
 	}
+	g.UsePowHashAlgo(chaingen.PHAKawPow)
 
 	// Define some convenience helper functions to return an individual test
 	// instance that has the described characteristics.
@@ -1684,16 +1686,48 @@ func Generate(includeLargeReorg bool) (tests [][]TestInstance, err error) {
 	// This can't be done inside a munge function passed to NextBlock
 	// because the block is solved after the function returns and this test
 	// requires an unsolved block.  Thus, just increment the nonce until
-	// it's not solved and then replace it in the generator's state.
+	// it's not solved and then replace it in the generator's state.  The mix
+	// hash is updated to match the new nonce so the block is rejected due to
+	// its final hash as opposed to a mismatched mix hash.
 	{
 		origHash := bmf3.BlockHash()
 		for g.IsSolved(&bmf3.Header) {
 			bmf3.Header.Nonce++
+			mixHash, _, err := bmf3.Header.PowHashKawPow()
+			if err != nil {
+				panic(err)
+			}
+			bmf3.Header.MixHash = mixHash
 		}
 		g.UpdateBlockState("bmf3", origHash, "bmf3", bmf3)
 	}
 	rejected(ErrHighHash)
 
+	// Create block with a mix hash that does not match the one calculated from
+	// the header and nonce.
+	//
+	//   ... -> brs3(14)
+	//                  \-> bmf3a(15)
+	g.SetTip("brs3")
+	bmf3a := g.NextBlock("bmf3a", outs[15], ticketOuts[15])
+	// As above, this can't be done inside a munge function.  The mix hash is
+	// modified until the final hash calculated from it still satisfies the
+	// target difficulty so the block is rejected due to the mismatched mix
+	// hash as opposed to the cheap target filter that is applied first.
+	{
+		origHash := bmf3a.BlockHash()
+		target := kawpow.CompactToTarget(bmf3a.Header.Bits)
+		for {
+			bmf3a.Header.MixHash[0]++
+			err := bmf3a.Header.VerifyKawPow(target)
+			if errors.Is(err, kawpow.ErrBadMixHash) {
+				break
+			}
+		}
+		g.UpdateBlockState("bmf3a", origHash, "bmf3a", bmf3a)
+	}
+	rejected(ErrBadMixHash)
+
 	// Create block with a timestamp too far in the future.
 	//
 	//   ... -> brs3(14)
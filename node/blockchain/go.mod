@@ -9,6 +9,7 @@ require (
 	github.com/kdsmith18542/vigil/dcrec v1.0.1
 	github.com/kdsmith18542/vigil/dcrec/secp256k1/v4 v4.3.0
 	github.com/kdsmith18542/vigil/dcrutil/v4 v4.0.2
+	github.com/kdsmith18542/vigil/kawpow v0.0.0-00010101000000-000000000000
 	github.com/kdsmith18542/vigil/txscript/v4 v4.1.1
 	github.com/kdsmith18542/vigil/wire v1.7.0
 )
@@ -26,3 +27,5 @@ require (
 	golang.org/x/sys v0.21.0 // indirect
 	lukechampine.com/blake3 v1.3.0 // indirect
 )
+
+replace github.com/kdsmith18542/vigil/kawpow => ../kawpow
@@ -0,0 +1,599 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// MainNetParams returns the network parameters for the main Vigil network.
+func MainNetParams() *Params {
+	// mainPowLimit is the highest proof of work value a Vigil block can have
+	// for the main network.  It is the value 2^224 - 1.
+	mainPowLimit := new(big.Int).Sub(new(big.Int).Lsh(bigOne, 224), bigOne)
+
+	// mainPowLimitBits is the main network proof of work limit in its compact
+	// representation.
+	//
+	// Note that due to the limited precision of the compact representation,
+	// this is not exactly equal to the pow limit.  It is the value:
+	//
+	// 0x00000000ffff0000000000000000000000000000000000000000000000000000
+	const mainPowLimitBits = 0x1d00ffff // 486604799
+
+	// genesisBlock defines the genesis block of the block chain which serves as
+	// the public transaction ledger for the main network.
+	//
+	// The genesis block is not subject to the proof of work rules, so its
+	// KawPoW nonce and mix hash are left zero.
+	genesisBlock := wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   1,
+			PrevBlock: chainhash.Hash{},
+			// MerkleRoot: Calculated below.
+			StakeRoot:    chainhash.Hash{},
+			Timestamp:    time.Unix(1735689600, 0), // 2025-01-01 00:00:00 +0000 UTC
+			Bits:         mainPowLimitBits,
+			SBits:        2 * 1e8, // 2 Coin
+			Nonce:        0x00000000,
+			StakeVersion: 0,
+		},
+		Transactions: []*wire.MsgTx{{
+			SerType: wire.TxSerializeFull,
+			Version: 1,
+			TxIn: []*wire.TxIn{{
+				// Fully null.
+				PreviousOutPoint: wire.OutPoint{
+					Hash:  chainhash.Hash{},
+					Index: 0xffffffff,
+					Tree:  0,
+				},
+				SignatureScript: hexDecode("0000"),
+				Sequence:        0xffffffff,
+				BlockHeight:     wire.NullBlockHeight,
+				BlockIndex:      wire.NullBlockIndex,
+				ValueIn:         wire.NullValueIn,
+			}},
+			TxOut: []*wire.TxOut{{
+				Version: 0x0000,
+				Value:   0x00000000,
+				// OP_RETURN "Vigil mainnet genesis 2025-01-01"
+				PkScript: hexDecode("6a20566967696c206d61696e6e65742067656e65" +
+					"73697320323032352d30312d3031"),
+			}},
+			LockTime: 0,
+			Expiry:   0,
+		}},
+	}
+	genesisBlock.Header.MerkleRoot = genesisBlock.Transactions[0].TxHashFull()
+
+	return &Params{
+		Name:        "mainnet",
+		Net:         wire.MainNet,
+		DefaultPort: "9508",
+		DNSSeeds: []DNSSeed{
+			{"mainnet-seed.vigil.network", true},
+		},
+
+		// Chain parameters
+		GenesisBlock:         &genesisBlock,
+		GenesisHash:          genesisBlock.BlockHash(),
+		PowLimit:             mainPowLimit,
+		PowLimitBits:         mainPowLimitBits,
+		ReduceMinDifficulty:  false,
+		MinDiffReductionTime: 0, // Does not apply since ReduceMinDifficulty false
+		GenerateSupported:    false,
+		MaximumBlockSizes:    []int{1310720},
+		MaxTxSize:            1000000,
+		TargetTimePerBlock:   time.Second * 150,
+
+		// Version 1 difficulty algorithm (EMA + BLAKE256) parameters.
+		//
+		// These are only retained for completeness since KawPoW is active from
+		// the genesis block.
+		WorkDiffAlpha:            1,
+		WorkDiffWindowSize:       144,
+		WorkDiffWindows:          20,
+		TargetTimespan:           time.Second * 150 * 144, // TimePerBlock * WindowSize
+		RetargetAdjustmentFactor: 4,
+
+		// Version 2 difficulty algorithm (ASERT) parameters.  These are also
+		// used for KawPoW.
+		WorkDiffV2Blake3StartBits: mainPowLimitBits,
+		WorkDiffV2HalfLifeSecs:    21600, // 144 * TimePerBlock (6 hours)
+
+		// Subsidy parameters.
+		//
+		// The block subsidy starts at 20 Coin and is reduced by 1% every 6144
+		// blocks (~10.7 days).  It is split 50% to proof of work, 40% to proof
+		// of stake, and 10% to the treasury.
+		BaseSubsidy:              2000000000, // 20 Coin
+		MulSubsidy:               99,
+		DivSubsidy:               100,
+		SubsidyReductionInterval: 6144,
+		WorkRewardProportion:     5,
+		WorkRewardProportionV2:   5,
+		StakeRewardProportion:    4,
+		StakeRewardProportionV2:  4,
+		BlockTaxProportion:       1,
+
+		// AssumeValid is the hash of a block that has been externally verified
+		// to be valid.  It allows several validation checks to be skipped for
+		// blocks that are both an ancestor of the assumed valid block and an
+		// ancestor of the best header.  It is also used to determine the old
+		// forks rejection checkpoint.  This is intended to be updated
+		// periodically with new releases.
+		//
+		// Not set until the main network has been launched.
+		AssumeValid: chainhash.Hash{},
+
+		// MinKnownChainWork is the minimum amount of known total work for the
+		// chain at a given point in time.  This is intended to be updated
+		// periodically with new releases.
+		//
+		// Not set until the main network has been launched.
+		MinKnownChainWork: nil,
+
+		// Consensus rule change deployments.
+		//
+		// The miner confirmation window is defined as:
+		//   target proof of work timespan / target proof of work spacing
+		//
+		// KawPoW and the agendas that were voted in on the network Vigil is
+		// derived from are forced active from the genesis block.  The agendas
+		// that would change the proof of work hash function to BLAKE3 or alter
+		// the 50/40/10 subsidy split are forced to fail.
+		RuleChangeActivationQuorum:     8064, // 10 % of RuleChangeActivationInterval * TicketsPerBlock
+		RuleChangeActivationMultiplier: 3,    // 75%
+		RuleChangeActivationDivisor:    4,
+		RuleChangeActivationInterval:   16128, // 4 weeks
+		Deployments: map[uint32][]ConsensusDeployment{
+			5: {{
+				Vote: Vote{
+					Id:          VoteIDSDiffAlgorithm,
+					Description: "Change stake difficulty algorithm as defined in VGLP0001",
+					Mask:        0x0006, // Bits 1 and 2
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing algorithm",
+						Bits:        0x0002, // Bit 1
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new algorithm",
+						Bits:        0x0004, // Bit 2
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "yes",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}},
+			6: {{
+				Vote: Vote{
+					Id:          VoteIDLNFeatures,
+					Description: "Enable features defined in VGLP0002 and VGLP0003 necessary to support Lightning Network (LN)",
+					Mask:        0x0006, // Bits 1 and 2
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0002, // Bit 1
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0004, // Bit 2
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "yes",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}},
+			7: {{
+				Vote: Vote{
+					Id:          VoteIDFixLNSeqLocks,
+					Description: "Modify sequence lock handling as defined in VGLP0004",
+					Mask:        0x0006, // Bits 1 and 2
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0002, // Bit 1
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0004, // Bit 2
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "yes",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}},
+			8: {{
+				Vote: Vote{
+					Id:          VoteIDHeaderCommitments,
+					Description: "Enable header commitments as defined in VGLP0005",
+					Mask:        0x0006, // Bits 1 and 2
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0002, // Bit 1
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0004, // Bit 2
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "yes",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}},
+			9: {{
+				Vote: Vote{
+					Id:          VoteIDTreasury,
+					Description: "Enable decentralized Treasury opcodes as defined in VGLP0006",
+					Mask:        0x0006, // Bits 1 and 2
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0002, // Bit 1
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0004, // Bit 2
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "yes",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}},
+			10: {{
+				Vote: Vote{
+					Id:          VoteIDRevertTreasuryPolicy,
+					Description: "Change maximum treasury expenditure policy as defined in VGLP0007",
+					Mask:        0x0006, // Bits 1 and 2
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0002, // Bit 1
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0004, // Bit 2
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "yes",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}, {
+				Vote: Vote{
+					Id:          VoteIDExplicitVersionUpgrades,
+					Description: "Enable explicit version upgrades as defined in VGLP0008",
+					Mask:        0x0018, // Bits 3 and 4
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain from voting",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0008, // Bit 3
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0010, // Bit 4
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "yes",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}, {
+				Vote: Vote{
+					Id:          VoteIDAutoRevocations,
+					Description: "Enable automatic ticket revocations as defined in VGLP0009",
+					Mask:        0x0060, // Bits 5 and 6
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0020, // Bit 5
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0040, // Bit 6
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "yes",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}, {
+				Vote: Vote{
+					Id:          VoteIDChangeSubsidySplit,
+					Description: "Change block reward subsidy split to 10/80/10 as defined in VGLP0010",
+					Mask:        0x0180, // Bits 7 and 8
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain from voting",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0080, // Bit 7
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0100, // Bit 8
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "no",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}},
+			11: {{
+				Vote: Vote{
+					Id:          VoteIDBlake3Pow,
+					Description: "Change proof of work hashing algorithm to BLAKE3 as defined in VGLP0011",
+					Mask:        0x0006, // Bits 1 and 2
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0002, // Bit 1
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0004, // Bit 2
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "no",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}, {
+				Vote: Vote{
+					Id:          VoteIDChangeSubsidySplitR2,
+					Description: "Change block reward subsidy split to 1/89/10 as defined in VGLP0012",
+					Mask:        0x0060, // Bits 5 and 6
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "keep the existing consensus rules",
+						Bits:        0x0020, // Bit 5
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "change to the new consensus rules",
+						Bits:        0x0040, // Bit 6
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "no",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}},
+			12: {{
+				Vote: Vote{
+					Id:          VoteIDKawPoW,
+					Description: "Activate KawPoW as the new proof of work algorithm",
+					Mask:        0x0006, // Bits 1 and 2
+					Choices: []Choice{{
+						Id:          "abstain",
+						Description: "abstain from voting for change",
+						Bits:        0x0000,
+						IsAbstain:   true,
+						IsNo:        false,
+					}, {
+						Id:          "no",
+						Description: "reject KawPoW",
+						Bits:        0x0002, // Bit 1
+						IsAbstain:   false,
+						IsNo:        true,
+					}, {
+						Id:          "yes",
+						Description: "support KawPoW",
+						Bits:        0x0004, // Bit 2
+						IsAbstain:   false,
+						IsNo:        false,
+					}},
+				},
+				ForcedChoiceID: "yes",
+				StartTime:      0,             // Always available for vote
+				ExpireTime:     math.MaxInt64, // Never expires
+			}},
+		},
+
+		// Enforce current block version once majority of the network has
+		// upgraded.
+		// 75% (750 / 1000)
+		// Reject previous block versions once a majority of the network has
+		// upgraded.
+		// 95% (950 / 1000)
+		BlockEnforceNumRequired: 750,
+		BlockRejectNumRequired:  950,
+		BlockUpgradeNumToCheck:  1000,
+
+		// AcceptNonStdTxs is a mempool param to either accept and relay non
+		// standard txs to the network or reject them
+		AcceptNonStdTxs: false,
+
+		// Address encoding magics
+		NetworkAddressPrefix: "V",
+		PubKeyAddrID:         [2]byte{0x2c, 0x08}, // starts with Vk
+		PubKeyHashAddrID:     [2]byte{0x10, 0x41}, // starts with Vs
+		PKHEdwardsAddrID:     [2]byte{0x10, 0x21}, // starts with Ve
+		PKHSchnorrAddrID:     [2]byte{0x10, 0x03}, // starts with VS
+		ScriptHashAddrID:     [2]byte{0x10, 0x1c}, // starts with Vc
+		PrivateKeyID:         [2]byte{0x23, 0x1b}, // starts with Pv
+
+		// BIP32 hierarchical deterministic extended key magics
+		HDPrivateKeyID: [4]byte{0xfd, 0x8b, 0x9b, 0x26}, // starts with vprv
+		HDPublicKeyID:  [4]byte{0xfd, 0x8c, 0x90, 0x65}, // starts with vpub
+
+		// BIP44 coin type used in the hierarchical deterministic path for
+		// address generation.  Coin type 7767 is not registered in SLIP-0044,
+		// so it must be registered or replaced before the network launches.
+		SLIP0044CoinType: 7767,
+		LegacyCoinType:   20, // for backwards compatibility
+
+		// Vigil PoS parameters
+		MinimumStakeDiff:        2 * 1e8, // 2 Coin
+		TicketPoolSize:          8192,
+		TicketsPerBlock:         5,
+		TicketMaturity:          256,
+		TicketExpiry:            40960, // 5*TicketPoolSize
+		CoinbaseMaturity:        256,
+		SStxChangeMaturity:      1,
+		TicketPoolSizeWeight:    4,
+		StakeDiffAlpha:          1, // Minimal
+		StakeDiffWindowSize:     144,
+		StakeDiffWindows:        20,
+		StakeVersionInterval:    144 * 2 * 7, // ~1 week
+		MaxFreshStakePerBlock:   20,          // 4*TicketsPerBlock
+		StakeEnabledHeight:      256 + 256,   // CoinbaseMaturity + TicketMaturity
+		StakeValidationHeight:   4096,        // ~7 hours
+		StakeBaseSigScript:      []byte{0x00, 0x00},
+		StakeMajorityMultiplier: 3,
+		StakeMajorityDivisor:    4,
+
+		// vigil.networkanization related parameters
+		//
+		// The organization script is intentionally unset until it is produced
+		// by the mainnet key ceremony.  vgld refuses to run on the main
+		// network without it.
+		OrganizationPkScript:        nil,
+		OrganizationPkScriptVersion: 0,
+		BlockOneLedger:              tokenPayouts_MainNetParams(),
+
+		// Sanctioned Vigiliteia keys.
+		//
+		// The keys are intentionally unset until they are produced by the
+		// mainnet key ceremony.  vgld refuses to run on the main network
+		// without them.
+		PiKeys: nil,
+
+		// ~12 hours for tspend inclusion
+		TreasuryVoteInterval: 288,
+
+		// ~6 days for short circuit approval
+		TreasuryVoteIntervalMultiplier: 12,
+
+		// ~12 day policy window
+		TreasuryExpenditureWindow: 2,
+
+		// ~72 day policy window check
+		TreasuryExpenditurePolicy: 6,
+
+		// 16000 VGL/tew as expense bootstrap
+		TreasuryExpenditureBootstrap: 16000 * 1e8,
+
+		TreasuryVoteQuorumMultiplier:   1, // 20% quorum required
+		TreasuryVoteQuorumDivisor:      5,
+		TreasuryVoteRequiredMultiplier: 3, // 60% yes votes required
+		TreasuryVoteRequiredDivisor:    5,
+
+		seeders: []string{
+			"mainnet-seed-1.vigil.network",
+			"mainnet-seed-2.vigil.network",
+		},
+	}
+}
@@ -11,21 +11,24 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
 )
 
 // TestGenesisBlock tests the genesis block of the main network for validity by
-// checking the encoded bytes and hashes.
+// checking the encoded bytes, merkle root, and hashes against known values.
 func TestGenesisBlock(t *testing.T) {
 	genesisBlockBytes, _ := hex.DecodeString("0100000000000000000000000000" +
-		"000000000000000000000000000000000000000000000dc101dfc3c6a2eb10ca0" +
-		"c5374e10d28feb53f7eabcc850511ceadb99174aa660000000000000000000000" +
+		"00000000000000000000000000000000000000000000fa72e2c9b5f1818e1d068" +
+		"4333d5e7e56640c850e8427574c29b2b5d9784238eb0000000000000000000000" +
 		"00000000000000000000000000000000000000000000000000000000000000000" +
-		"000000000ffff011b00c2eb0b000000000000000000000000a0d7b85600000000" +
+		"000000000ffff001d00c2eb0b0000000000000000000000008085746700000000" +
 		"00000000000000000000000000000000000000000000000000000000000000000" +
-		"00000000101000000010000000000000000000000000000000000000000000000" +
-		"000000000000000000ffffffff00ffffffff01000000000000000000002080167" +
-		"9e98561ada96caec2949a5d41c4cab3851eb740d951c10ecbcf265c1fd9000000" +
-		"000000000001ffffffffffffffff00000000ffffffff02000000")
+		"00000000000000000000000000000000000000000000000000000000000000000" +
+		"00000000000000000000000000000000000000000000000000000000000000000" +
+		"00000000000000101000000010000000000000000000000000000000000000000" +
+		"000000000000000000000000ffffffff00ffffffff01000000000000000000002" +
+		"26a20566967696c206d61696e6e65742067656e6573697320323032352d30312d" +
+		"3031000000000000000001ffffffffffffffff00000000ffffffff02000000")
 
 	// Encode the genesis block to raw bytes.
 	params := MainNetParams()
@@ -42,11 +45,27 @@ func TestGenesisBlock(t *testing.T) {
 			spew.Sdump(genesisBlockBytes))
 	}
 
-	// Check hash of the block against expected hash.
+	// Check the merkle root of the block against the expected merkle root.
+	const wantMerkleRoot = "eb384278d9b5b2294c5727840e850c64567e5e3d3384061d8e81f1b5c9e272fa"
+	merkleRoot := params.GenesisBlock.Header.MerkleRoot
+	if merkleRoot.String() != wantMerkleRoot {
+		t.Fatalf("TestGenesisBlock: Genesis block merkle root does not "+
+			"appear valid - got %v, want %v", merkleRoot, wantMerkleRoot)
+	}
+
+	// Check hash of the block and the hash in the parameters against the
+	// expected hash.
+	wantHash, err := chainhash.NewHashFromStr("ffb7d4c7c3e33925319b4d6f5adf37a62fa5e22f10a5158f688c0a52b2ac35ef")
+	if err != nil {
+		t.Fatalf("TestGenesisBlock: %v", err)
+	}
 	hash := params.GenesisBlock.BlockHash()
-	if !params.GenesisHash.IsEqual(&hash) {
+	if !wantHash.IsEqual(&hash) {
 		t.Fatalf("TestGenesisBlock: Genesis block hash does not "+
-			"appear valid - got %v, want %v", spew.Sdump(hash),
-			spew.Sdump(params.GenesisHash))
+			"appear valid - got %v, want %v", hash, wantHash)
+	}
+	if !wantHash.IsEqual(&params.GenesisHash) {
+		t.Fatalf("TestGenesisBlock: Genesis hash in parameters does not "+
+			"appear valid - got %v, want %v", params.GenesisHash, wantHash)
 	}
 }
@@ -50,12 +50,14 @@ const (
 	defaultUtxoCacheMaxSize = 150
 	minUtxoCacheMaxSize     = 25
 	maxUtxoCacheMaxSize     = 32768 // 32 GiB
+	minPruneTarget          = 1024  // 1 GiB
 
 	// Defaults for RPC server options and policy.
 	defaultTLSCurve             = "P-256"
 	defaultMaxRPCClients        = 10
 	defaultMaxRPCWebsockets     = 25
 	defaultMaxRPCConcurrentReqs = 20
+	defaultRESTRateLimit        = 10
 
 	// Defaults for P2P network options.
 	defaultMaxSameIP       = 5
@@ -69,6 +71,7 @@ const (
 
 	// Defaults for relay and mempool policy options.
 	defaultMaxOrphanTransactions = 100
+	defaultMaxMempoolSize        = 300
 	defaultAllowOldVotes         = false
 
 	// Defaults for mining options and policy.
@@ -77,11 +80,18 @@ const (
 	blockMaxSizeMin            = 1000
 	defaultNoMiningStateSync   = false
 	defaultAllowUnsyncedMining = false
+	defaultDAGDirname          = "dag"
+	defaultDAGsInMem           = 1
+	defaultStratumPort         = "3333"
+	defaultStratumDiff         = 1
 
 	// Defaults for indexing options.
 	defaultTxIndex           = false
 	defaultNoExistsAddrIndex = false
 
+	// Defaults for metrics options.
+	defaultMetricsPort = "9110"
+
 	// Authorization types.
 	authTypeBasic      = "basic"
 	authTypeClientCert = "clientcert"
@@ -128,6 +138,7 @@ type config struct {
 	DebugLevel       string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
 	SigCacheMaxSize  uint   `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
 	UtxoCacheMaxSize uint   `long:"utxocachemaxsize" description:"The maximum size in MiB of the utxo cache; (min: 25, max: 32768)"`
+	Prune            uint   `long:"prune" description:"Reduce storage requirements by removing the data for old blocks to keep the total size of the block data under the target in MiB (0 to disable, min: 1024).  The data for the recent blocks required by the stake validation rules is always retained.  Incompatible with --txindex and --addrindex"`
 
 	// RPC server options and policy.
 	DisableRPC           bool     `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
@@ -146,6 +157,8 @@ type config struct {
 	RPCMaxClients        int      `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
 	RPCMaxWebsockets     int      `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
 	RPCMaxConcurrentReqs int      `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
+	REST                 bool     `long:"rest" description:"Enable the unauthenticated REST interface for querying public chain data on the RPC listeners"`
+	RESTRateLimit        int      `long:"restratelimit" description:"Max number of REST requests per second allowed for each client IP address"`
 
 	// P2P proxy and Tor settings.
 	Proxy          string `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
@@ -161,7 +174,7 @@ type config struct {
 	AddPeers        []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
 	ConnectPeers    []string      `long:"connect" description:"Connect only to the specified peers at startup"`
 	DisableListen   bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
-	Listeners       []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9108, testnet: 19108)"`
+	Listeners       []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9508, testnet: 19108)"`
 	MaxSameIP       int           `long:"maxsameip" description:"Max number of connections with the same IP -- 0 to disable"`
 	MaxPeers        int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
 	DialTimeout     time.Duration `long:"dialtimeout" description:"How long to wait for TCP connection completion.  Valid time units are {s, m, h}.  Minimum 1 second"`
@@ -190,6 +203,8 @@ type config struct {
 	FreeTxRelayLimit float64 `long:"limitfreerelay" description:"DEPRECATED: This behavior is no longer available and this option will be removed in a future version of the software"`
 	NoRelayPriority  bool    `long:"norelaypriority" description:"DEPRECATED: This behavior is no longer available and this option will be removed in a future version of the software"`
 	MaxOrphanTxs     int     `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
+	MaxMempool       uint    `long:"maxmempool" description:"Max size of the transaction memory pool in megabytes; the transactions paying the lowest fees are evicted once it is exceeded (0 to disable the limit)"`
+	NoPersistMempool bool    `long:"nopersistmempool" description:"Do not save the transaction memory pool to disk on shutdown and reload it on startup"`
 	BlocksOnly       bool    `long:"blocksonly" description:"Do not accept transactions from remote peers"`
 	AcceptNonStd     bool    `long:"acceptnonstd" description:"Accept and relay non-standard transactions to the network regardless of the default settings for the active network"`
 	RejectNonStd     bool    `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network"`
@@ -205,13 +220,22 @@ type config struct {
 	NonAggressive       bool     `long:"nonaggressive" description:"Disable mining off of the parent block of the blockchain if there aren't enough voters"`
 	NoMiningStateSync   bool     `long:"nominingstatesync" description:"Disable synchronizing the mining state with other nodes"`
 	AllowUnsyncedMining bool     `long:"allowunsyncedmining" description:"Allow block templates to be generated even when the chain is not considered synced on networks other than the main network.  This is automatically enabled when the simnet option is set.  Don't do this unless you know what you're doing"`
+	DAGDir              string   `long:"dagdir" description:"Directory to store generated KawPoW light caches and datasets so they are reused across restarts (default: dag directory in the network data directory)"`
+	DAGsInMem           int      `long:"dagsinmem" description:"Number of KawPoW datasets to keep in memory"`
+	StratumListeners    []string `long:"stratumlisten" description:"Add an interface/port to listen for KawPoW stratum mining connections (default port: 3333).  At least one mining address is required if this option is set"`
+	StratumDiff         float64  `long:"stratumdiff" description:"Initial share difficulty for stratum mining clients"`
 
 	// Indexing options.
 	TxIndex             bool `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
-	DropTxIndex         bool `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits"`
+	DropTxIndex         bool `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits.  This will also delete the address index since it relies on the transaction index"`
+	AddrIndex           bool `long:"addrindex" description:"Maintain a full address-based transaction index which makes the searchrawtransactions, getaddresstxids, getaddressbalance, and getaddressutxos RPCs available.  Implies --txindex"`
+	DropAddrIndex       bool `long:"dropaddrindex" description:"Deletes the address-based transaction index from the database on start up and then exits"`
 	NoExistsAddrIndex   bool `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used"`
 	DropExistsAddrIndex bool `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits"`
 
+	// Metrics options.
+	MetricsListeners []string `long:"metricslisten" description:"Add an interface/port to serve Prometheus metrics over HTTP at /metrics (default port: 9110).  The metrics are not authenticated, so only listen on trusted interfaces"`
+
 	// IPC options.
 	PipeRx          uint `long:"piperx" description:"File descriptor of read end pipe to enable parent -> child process communication"`
 	PipeTx          uint `long:"pipetx" description:"File descriptor of write end pipe to enable parent <- child process communication"`
@@ -615,6 +639,7 @@ func loadConfig(appName string) (*config, []string, error) {
 		RPCMaxClients:        defaultMaxRPCClients,
 		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
 		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
+		RESTRateLimit:        defaultRESTRateLimit,
 
 		// P2P network options.
 		MaxSameIP:       defaultMaxSameIP,
@@ -629,6 +654,7 @@ func loadConfig(appName string) (*config, []string, error) {
 		// Relay and mempool policy.
 		MinRelayTxFee: mempool.DefaultMinRelayTxFee.ToCoin(),
 		MaxOrphanTxs:  defaultMaxOrphanTransactions,
+		MaxMempool:    defaultMaxMempoolSize,
 		AllowOldVotes: defaultAllowOldVotes,
 
 		// Mining options and policy.
@@ -636,6 +662,8 @@ func loadConfig(appName string) (*config, []string, error) {
 		BlockMaxSize:        defaultBlockMaxSize,
 		NoMiningStateSync:   defaultNoMiningStateSync,
 		AllowUnsyncedMining: defaultAllowUnsyncedMining,
+		DAGsInMem:           defaultDAGsInMem,
+		StratumDiff:         defaultStratumDiff,
 
 		// Indexing options.
 		TxIndex:           defaultTxIndex,
@@ -1079,6 +1107,19 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// The REST interface is served by the RPC server.
+	if cfg.REST && cfg.DisableRPC {
+		str := "%s: the --rest option requires the RPC server to be enabled"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+	if cfg.RESTRateLimit < 1 {
+		str := "%s: the restratelimit option may not be less than 1 -- " +
+			"parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.RESTRateLimit)
+		return nil, nil, err
+	}
+
 	// Validate the minrelaytxfee.
 	cfg.minRelayTxFee, err = VGLutil.NewAmount(cfg.MinRelayTxFee)
 	if err != nil {
@@ -1116,6 +1157,22 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Store the KawPoW light caches and datasets in the network data directory
+	// by default.
+	if cfg.DAGDir == "" {
+		cfg.DAGDir = filepath.Join(cfg.DataDir, defaultDAGDirname)
+	} else {
+		cfg.DAGDir = cleanAndExpandPath(cfg.DAGDir)
+	}
+
+	// At least one KawPoW dataset must be kept in memory for mining.
+	if cfg.DAGsInMem < 1 {
+		str := "%s: the dagsinmem option may not be less than 1 " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.DAGsInMem)
+		return nil, nil, err
+	}
+
 	// Limit the max orphan count to a sane value.
 	if cfg.MaxOrphanTxs < 0 {
 		str := "%s: the maxorphantx option may not be less than 0 " +
@@ -1132,6 +1189,49 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// --addrindex and --dropaddrindex do not mix.
+	if cfg.AddrIndex && cfg.DropAddrIndex {
+		err := fmt.Errorf("%s: the --addrindex and --dropaddrindex "+
+			"options may not be activated at the same time",
+			funcName)
+		return nil, nil, err
+	}
+
+	// --addrindex and --droptxindex do not mix.
+	if cfg.AddrIndex && cfg.DropTxIndex {
+		err := fmt.Errorf("%s: the --addrindex and --droptxindex "+
+			"options may not be activated at the same time "+
+			"because the address index relies on the transaction "+
+			"index", funcName)
+		return nil, nil, err
+	}
+
+	// Enable the transaction index if the address index is enabled since it
+	// requires it.
+	if cfg.AddrIndex && !cfg.TxIndex {
+		vgldLog.Infof("Transaction index enabled because it is required " +
+			"by the address index")
+		cfg.TxIndex = true
+	}
+
+	// --prune and --txindex do not mix since the transaction index relies on
+	// the block data.
+	if cfg.Prune != 0 && cfg.TxIndex {
+		err := fmt.Errorf("%s: the --prune and --txindex options may not be "+
+			"activated at the same time because the transaction index relies "+
+			"on the block data (note that --addrindex implies --txindex)",
+			funcName)
+		return nil, nil, err
+	}
+
+	// Ensure the prune target is not too small.
+	if cfg.Prune != 0 && cfg.Prune < minPruneTarget {
+		str := "%s: the prune option may not be less than %d MiB -- parsed " +
+			"[%d]"
+		err := fmt.Errorf(str, funcName, minPruneTarget, cfg.Prune)
+		return nil, nil, err
+	}
+
 	// !--noexistsaddrindex and --dropexistsaddrindex do not mix.
 	if !cfg.NoExistsAddrIndex && cfg.DropExistsAddrIndex {
 		err := fmt.Errorf("dropexistsaddrindex cannot be activated when " +
@@ -1160,6 +1260,23 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Ensure there is at least one mining address when the stratum server is
+	// enabled.
+	if len(cfg.StratumListeners) > 0 && len(cfg.miningAddrs) == 0 {
+		str := "%s: the stratumlisten option is set, but there are no " +
+			"mining addresses specified "
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+
+	// The initial stratum share difficulty must be positive.
+	if cfg.StratumDiff <= 0 {
+		str := "%s: the stratumdiff option must be greater than 0 " +
+			"-- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.StratumDiff)
+		return nil, nil, err
+	}
+
 	// Don't allow unsynchronized mining on mainnet.
 	if cfg.AllowUnsyncedMining && cfg.params == &mainNetParams {
 		str := "%s: allowunsyncedmining cannot be activated on mainnet"
@@ -1182,6 +1299,16 @@ func loadConfig(appName string) (*config, []string, error) {
 	cfg.RPCListeners = normalizeAddresses(cfg.RPCListeners,
 		cfg.params.rpcPort, normalizeInterfaceAddrs)
 
+	// Add default port to all stratum listener addresses if needed and remove
+	// duplicate addresses.
+	cfg.StratumListeners = normalizeAddresses(cfg.StratumListeners,
+		defaultStratumPort, normalizeInterfaceAddrs)
+
+	// Add default port to all metrics listener addresses if needed and remove
+	// duplicate addresses.
+	cfg.MetricsListeners = normalizeAddresses(cfg.MetricsListeners,
+		defaultMetricsPort, normalizeInterfaceAddrs)
+
 	// The authtype config must be one of "basic" or "clientcert".
 	switch cfg.RPCAuthType {
 	case authTypeBasic, authTypeClientCert:
@@ -121,6 +121,10 @@ const (
 	// ErrBlockNotFound instead.
 	ErrBlockRegionInvalid = ErrorKind("ErrBlockRegionInvalid")
 
+	// ErrBlockPruned indicates the data for a block with the provided hash
+	// is known to the database, but has been removed due to pruning.
+	ErrBlockPruned = ErrorKind("ErrBlockPruned")
+
 	// ------------------------------------------
 	// Support for driver-specific errors.
 	// ------------------------------------------
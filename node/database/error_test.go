@@ -38,6 +38,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrBlockNotFound, "ErrBlockNotFound"},
 		{ErrBlockExists, "ErrBlockExists"},
 		{ErrBlockRegionInvalid, "ErrBlockRegionInvalid"},
+		{ErrBlockPruned, "ErrBlockPruned"},
 		{ErrDriverSpecific, "ErrDriverSpecific"},
 	}
 
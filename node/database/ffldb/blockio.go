@@ -16,7 +16,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
 	"github.com/kdsmith18542/vigil/database/v3"
@@ -172,6 +175,10 @@ type blockStore struct {
 	// new blocks are written to.
 	writeCursor *writeCursor
 
+	// firstFileNum is the number of the oldest block file that has not been
+	// removed due to pruning.  All block files before it no longer exist.
+	firstFileNum atomic.Uint32
+
 	// These functions are set to openFile, openWriteFile, and deleteFile by
 	// default, but are exposed here to allow the whitebox tests to replace
 	// them when working with mock files.
@@ -324,6 +331,12 @@ func (s *blockStore) deleteFile(fileNum uint32) error {
 // separate goroutine to close the file after it is returned from here, but
 // before the caller has acquired a read lock.
 func (s *blockStore) blockFile(fileNum uint32) (*lockableFile, error) {
+	// Block files before the first one have been removed due to pruning.
+	if fileNum < s.firstFileNum.Load() {
+		str := fmt.Sprintf("block file %d has been pruned", fileNum)
+		return nil, makeDbErr(database.ErrBlockPruned, str)
+	}
+
 	// When the requested block file is open for writes, return it.
 	wc := s.writeCursor
 	wc.RLock()
@@ -719,27 +732,125 @@ func (s *blockStore) handleRollback(oldBlockFileNum, oldBlockOffset uint32) {
 }
 
 // scanBlockFiles searches the database directory for all flat block files to
-// find the end of the most recent file.  This position is considered the
-// current write cursor which is also stored in the metadata.  Thus, it is used
-// to detect unexpected shutdowns in the middle of writes so the block files
-// can be reconciled.
-func scanBlockFiles(dbPath string) (int, uint32) {
-	lastFile := -1
+// find the oldest file and the end of the most recent file.  The position of
+// the end of the most recent file is considered the current write cursor which
+// is also stored in the metadata.  Thus, it is used to detect unexpected
+// shutdowns in the middle of writes so the block files can be reconciled.
+//
+// The oldest file is not necessarily the first one since the block files prior
+// to it might have been removed due to pruning.
+//
+// Both of the returned file numbers are -1 when there are no block files.
+func scanBlockFiles(dbPath string) (int, int, uint32) {
+	entries, err := os.ReadDir(dbPath)
+	if err != nil {
+		return -1, -1, 0
+	}
+
+	// Find the first and last block files based on their file names.
+	firstFile, lastFile := -1, -1
+	for _, entry := range entries {
+		name := entry.Name()
+		numStr, ok := strings.CutSuffix(name, filepath.Ext(blockFilenameTemplate))
+		if !ok || len(numStr) < 9 {
+			continue
+		}
+		fileNum, err := strconv.ParseUint(numStr, 10, 32)
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		if firstFile == -1 || int(fileNum) < firstFile {
+			firstFile = int(fileNum)
+		}
+		if int(fileNum) > lastFile {
+			lastFile = int(fileNum)
+		}
+	}
+
 	fileLen := uint32(0)
-	for i := 0; ; i++ {
-		filePath := blockFilePath(dbPath, uint32(i))
+	if lastFile != -1 {
+		st, err := os.Stat(blockFilePath(dbPath, uint32(lastFile)))
+		if err == nil {
+			fileLen = uint32(st.Size())
+		}
+	}
+
+	log.Tracef("Scan found oldest block file #%d and latest block file #%d "+
+		"with length %d", firstFile, lastFile, fileLen)
+	return firstFile, lastFile, fileLen
+}
+
+// blocksSize returns the total number of bytes used by all of the block files.
+//
+// This function MUST be called with the database close lock held (for reads)
+// and the write lock held or the close lock held for writes.
+func (s *blockStore) blocksSize() (uint64, error) {
+	wc := s.writeCursor
+	totalSize := uint64(wc.curOffset)
+	for fileNum := s.firstFileNum.Load(); fileNum < wc.curFileNum; fileNum++ {
+		filePath := blockFilePath(s.basePath, fileNum)
 		st, err := os.Stat(filePath)
 		if err != nil {
-			break
+			str := fmt.Sprintf("failed to stat file %q: %v", filePath, err)
+			return 0, makeDbErr(database.ErrDriverSpecific, str)
+		}
+		totalSize += uint64(st.Size())
+	}
+	return totalSize, nil
+}
+
+// pruneFiles removes the oldest block files until the total size of all block
+// files is no more than the provided target size without removing the provided
+// keep file or any files after it.  The file that is currently being written to
+// is never removed.  It returns the number of bytes freed.
+//
+// This function MUST be called with the database close lock held for writes so
+// there are no readers or writers.
+func (s *blockStore) pruneFiles(targetSize uint64, keepFileNum uint32) (uint64, error) {
+	totalSize, err := s.blocksSize()
+	if err != nil {
+		return 0, err
+	}
+
+	var freed uint64
+	wc := s.writeCursor
+	for fileNum := s.firstFileNum.Load(); totalSize > targetSize &&
+		fileNum < keepFileNum && fileNum < wc.curFileNum; fileNum++ {
+
+		filePath := blockFilePath(s.basePath, fileNum)
+		st, err := os.Stat(filePath)
+		if err != nil {
+			str := fmt.Sprintf("failed to stat file %q: %v", filePath, err)
+			return freed, makeDbErr(database.ErrDriverSpecific, str)
+		}
+
+		// Close the file when it is open.
+		s.obfMutex.Lock()
+		if blockFile, ok := s.openBlockFiles[fileNum]; ok {
+			s.lruMutex.Lock()
+			s.openBlocksLRU.Remove(s.fileNumToLRUElem[fileNum])
+			delete(s.fileNumToLRUElem, fileNum)
+			s.lruMutex.Unlock()
+
+			_ = blockFile.file.Close()
+			delete(s.openBlockFiles, fileNum)
+		}
+		s.obfMutex.Unlock()
+
+		// Mark the file as pruned prior to removing it so any failures that
+		// happen during removal result in the block data for the file being
+		// treated as pruned as opposed to missing.
+		s.firstFileNum.Store(fileNum + 1)
+		if err := s.deleteFileFunc(fileNum); err != nil {
+			return freed, err
 		}
-		lastFile = i
 
-		fileLen = uint32(st.Size())
+		log.Debugf("Pruned block file #%d (%d bytes)", fileNum, st.Size())
+		totalSize -= uint64(st.Size())
+		freed += uint64(st.Size())
 	}
 
-	log.Tracef("Scan found latest block file #%d with length %d", lastFile,
-		fileLen)
-	return lastFile, fileLen
+	return freed, nil
 }
 
 // newBlockStore returns a new block store with the current block file number
@@ -748,8 +859,9 @@ func newBlockStore(basePath string, network wire.CurrencyNet) *blockStore {
 	// Look for the end of the latest block to file to determine what the
 	// write cursor position is from the viewpoint of the block files on
 	// disk.
-	fileNum, fileOff := scanBlockFiles(basePath)
+	firstFileNum, fileNum, fileOff := scanBlockFiles(basePath)
 	if fileNum == -1 {
+		firstFileNum = 0
 		fileNum = 0
 		fileOff = 0
 	}
@@ -768,6 +880,7 @@ func newBlockStore(basePath string, network wire.CurrencyNet) *blockStore {
 			curOffset:  fileOff,
 		},
 	}
+	store.firstFileNum.Store(uint32(firstFileNum))
 	store.openFileFunc = store.openFile
 	store.openWriteFileFunc = store.openWriteFile
 	store.deleteFileFunc = store.deleteFile
@@ -1798,8 +1798,11 @@ type db struct {
 	cache     *dbCache     // Cache layer which wraps underlying leveldb DB.
 }
 
-// Enforce db implements the database.DB interface.
-var _ database.DB = (*db)(nil)
+// Enforce db implements the database.DB and database.BlockPruner interfaces.
+var (
+	_ database.DB          = (*db)(nil)
+	_ database.BlockPruner = (*db)(nil)
+)
 
 // Type returns the database driver type the current database instance was
 // created with.
@@ -2026,6 +2029,63 @@ func (db *db) Flush() error {
 	return db.cache.Flush()
 }
 
+// BlocksSize returns the total number of bytes used to store blocks.
+//
+// This function is part of the database.BlockPruner interface implementation.
+func (db *db) BlocksSize() (uint64, error) {
+	// Prevent writes to the block files and database close while the size is
+	// calculated.
+	db.writeLock.Lock()
+	defer db.writeLock.Unlock()
+	db.closeLock.RLock()
+	defer db.closeLock.RUnlock()
+
+	if db.closed {
+		return 0, makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr)
+	}
+
+	return db.store.blocksSize()
+}
+
+// PruneBlocks removes the data for the oldest stored blocks until the total
+// number of bytes used to store blocks is no more than the target size.  The
+// data for the block with the provided hash and every block stored after it is
+// never removed even if that means the target size can't be reached.  It
+// returns the number of bytes that were freed.
+//
+// The data is removed in units of entire block files, so the total size will
+// typically be somewhat less than the target after pruning and the data for
+// some blocks stored prior to the provided block might be retained.
+//
+// This function is part of the database.BlockPruner interface implementation.
+func (db *db) PruneBlocks(targetSize uint64, keepHash *chainhash.Hash) (uint64, error) {
+	// Determine which block file houses the block that must be kept.
+	var keepLoc blockLocation
+	err := db.View(func(dbTx database.Tx) error {
+		blockRow, err := dbTx.(*transaction).fetchBlockRow(keepHash)
+		if err != nil {
+			return err
+		}
+		keepLoc = deserializeBlockLoc(blockRow)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Since all transactions have a read lock on this mutex, this will wait
+	// for all readers and writers to complete and prevent any new ones from
+	// starting while the block files are removed.
+	db.closeLock.Lock()
+	defer db.closeLock.Unlock()
+
+	if db.closed {
+		return 0, makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr)
+	}
+
+	return db.store.pruneFiles(targetSize, keepLoc.blockFileNum)
+}
+
 // fileExists reports whether the named file or directory exists.
 func fileExists(name string) bool {
 	if _, err := os.Stat(name); err != nil {
@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
 	"github.com/kdsmith18542/vigil/database/v3"
 	"github.com/kdsmith18542/vigil/VGLutil/v4"
 	"github.com/kdsmith18542/vigil/wire"
@@ -676,3 +677,133 @@ func TestFailureScenarios(t *testing.T) {
 	// Test various corruption scenarios.
 	testCorruption(tc)
 }
+
+// TestPruneBlocks ensures pruning removes the oldest block files, never removes
+// the file that houses the block to keep or any files after it, reports pruned
+// blocks accordingly, and that the database can be reopened after pruning.
+func TestPruneBlocks(t *testing.T) {
+	// Create a new database to run tests against.
+	dbPath := t.TempDir()
+	idb, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer func() {
+		if idb != nil {
+			idb.Close()
+		}
+	}()
+
+	// Change the maximum file size to a small value to force multiple flat
+	// files with the test data set.
+	store := idb.(*db).store
+	store.maxBlockFileSize = 4096 // 4KiB
+
+	// Load and store the test blocks.
+	blocks, err := loadBlocks(t, blockDataFile, blockDataNet)
+	if err != nil {
+		t.Fatalf("loadBlocks: Unexpected error: %v", err)
+	}
+	err = idb.Update(func(tx database.Tx) error {
+		for _, block := range blocks {
+			if err := tx.StoreBlock(block); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StoreBlock: Unexpected error: %v", err)
+	}
+	if store.writeCursor.curFileNum < 4 {
+		t.Fatalf("test data did not result in enough block files -- got %d",
+			store.writeCursor.curFileNum+1)
+	}
+
+	// Ensure a block that does not exist is rejected.
+	pruner := idb.(database.BlockPruner)
+	var unknownHash [32]byte
+	_, err = pruner.PruneBlocks(0, (*chainhash.Hash)(&unknownHash))
+	if !checkDbError(t, "PruneBlocks unknown", err, database.ErrBlockNotFound) {
+		return
+	}
+
+	// Ensure the target size is respected by pruning to a size that only
+	// requires removing a single file.
+	sizeBefore, err := pruner.BlocksSize()
+	if err != nil {
+		t.Fatalf("BlocksSize: Unexpected error: %v", err)
+	}
+	lastHash := blocks[len(blocks)-1].Hash()
+	freed, err := pruner.PruneBlocks(sizeBefore-1, lastHash)
+	if err != nil {
+		t.Fatalf("PruneBlocks: Unexpected error: %v", err)
+	}
+	if freed == 0 || store.firstFileNum.Load() != 1 {
+		t.Fatalf("unexpected prune result -- freed %d, first file %d", freed,
+			store.firstFileNum.Load())
+	}
+	sizeAfter, err := pruner.BlocksSize()
+	if err != nil {
+		t.Fatalf("BlocksSize: Unexpected error: %v", err)
+	}
+	if sizeAfter != sizeBefore-freed {
+		t.Fatalf("mismatched size after pruning -- got %d, want %d",
+			sizeAfter, sizeBefore-freed)
+	}
+
+	// Prune everything possible while keeping a block in the middle and
+	// ensure the file that houses it is retained.
+	keepBlock := blocks[len(blocks)/2]
+	var keepLoc blockLocation
+	err = idb.View(func(tx database.Tx) error {
+		blockRow, err := tx.(*transaction).fetchBlockRow(keepBlock.Hash())
+		keepLoc = deserializeBlockLoc(blockRow)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("fetchBlockRow: Unexpected error: %v", err)
+	}
+	if _, err := pruner.PruneBlocks(0, keepBlock.Hash()); err != nil {
+		t.Fatalf("PruneBlocks: Unexpected error: %v", err)
+	}
+	if got := store.firstFileNum.Load(); got != keepLoc.blockFileNum {
+		t.Fatalf("mismatched first file -- got %d, want %d", got,
+			keepLoc.blockFileNum)
+	}
+
+	// Ensure fetching a pruned block returns the expected error while blocks
+	// that were kept are still available.
+	checkFetch := func(db database.DB) bool {
+		return db.View(func(tx database.Tx) error {
+			_, err := tx.FetchBlock(blocks[0].Hash())
+			if !checkDbError(t, "FetchBlock pruned", err,
+				database.ErrBlockPruned) {
+				return errSubTestFail
+			}
+			if _, err := tx.FetchBlock(keepBlock.Hash()); err != nil {
+				t.Errorf("FetchBlock kept: Unexpected error: %v", err)
+				return errSubTestFail
+			}
+			return nil
+		}) == nil
+	}
+	if !checkFetch(idb) {
+		return
+	}
+
+	// Ensure the database can be reopened after pruning and that the pruned
+	// state is detected.
+	if err := idb.Close(); err != nil {
+		t.Fatalf("Close: Unexpected error: %v", err)
+	}
+	idb, err = database.Open(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Open: Unexpected error: %v", err)
+	}
+	if got := idb.(*db).store.firstFileNum.Load(); got != keepLoc.blockFileNum {
+		t.Fatalf("mismatched first file after reopen -- got %d, want %d",
+			got, keepLoc.blockFileNum)
+	}
+	checkFetch(idb)
+}
@@ -479,3 +479,28 @@ type DB interface {
 	// Flush writes all outstanding cached entries to disk.
 	Flush() error
 }
+
+// BlockPruner is an optional interface that may be implemented by database
+// drivers which support removing the data for old blocks in order to reduce
+// the amount of storage used.
+//
+// The blocks are expected to be removed in the order they were stored and
+// removed blocks remain known to the database.  Attempting to fetch the data
+// for a removed block will return ErrBlockPruned.
+type BlockPruner interface {
+	// BlocksSize returns the total number of bytes used to store blocks.
+	BlocksSize() (uint64, error)
+
+	// PruneBlocks removes the data for the oldest stored blocks until the
+	// total number of bytes used to store blocks is no more than the target
+	// size.  The data for the block with the provided hash and every block
+	// stored after it is never removed even if that means the target size
+	// can't be reached.  It returns the number of bytes that were freed.
+	//
+	// The interface contract guarantees at least the following errors will
+	// be returned (other implementation-specific errors are possible):
+	//   - ErrBlockNotFound if the block with the provided hash does not
+	//     exist
+	//   - ErrDbNotOpen if the database is not open
+	PruneBlocks(targetSize uint64, keepHash *chainhash.Hash) (uint64, error)
+}
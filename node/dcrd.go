@@ -19,6 +19,7 @@ import (
 	"github.com/kdsmith18542/vigil/internal/blockchain/indexers"
 	"github.com/kdsmith18542/vigil/internal/limits"
 	"github.com/kdsmith18542/vigil/internal/version"
+	"github.com/kdsmith18542/vigil/kawpow"
 )
 
 var cfg *config
@@ -71,6 +72,14 @@ func vgldMain() error {
 		vgldLog.Info("File logging disabled")
 	}
 
+	// Refuse to run on networks without an organization script and Vigiliteia
+	// keys, which is the case for the main network until the mainnet key
+	// ceremony.
+	if err := checkTreasuryKeys(cfg.params.Params); err != nil {
+		vgldLog.Error(err)
+		return err
+	}
+
 	// Block and transaction processing can cause bursty allocations.  This
 	// limits the garbage collector from excessively overallocating during
 	// bursts.  It does this by tweaking the soft memory limit.
@@ -119,6 +128,16 @@ func vgldMain() error {
 		}
 	}
 
+	// Serve metrics if requested.
+	if len(cfg.MetricsListeners) > 0 {
+		var metricsSrv metricsServer
+		if err := metricsSrv.Start(cfg.MetricsListeners); err != nil {
+			vgldLog.Errorf("Unable to start metrics server: %v", err)
+			return err
+		}
+		defer metricsSrv.Stop()
+	}
+
 	// Write cpu profile if requested.
 	if cfg.CPUProfile != "" {
 		f, err := os.Create(cfg.CPUProfile)
@@ -161,6 +180,13 @@ func vgldMain() error {
 		return nil
 	}
 
+	// Persist the KawPoW light caches and datasets to the DAG directory so
+	// they are reused across restarts instead of being regenerated.
+	epochManager := kawpow.DefaultEpochManager()
+	epochManager.SetStore(kawpow.NewDAGStore(cfg.DAGDir, kawpow.DefaultDAGsOnDisk))
+	epochManager.SetMaxDAGs(cfg.DAGsInMem)
+	vgldLog.Infof("KawPoW DAG dir: %s", cfg.DAGDir)
+
 	// Load the block database.
 	lifetimeNotifier.notifyStartupEvent(lifetimeEventDBOpen)
 	db, err := loadBlockDB(cfg.params.Params)
@@ -202,10 +228,18 @@ func vgldMain() error {
 	//
 	// NOTE: The order is important here because dropping the tx index also
 	// drops the address index since it relies on it.
-	if err := indexers.DropAddrIndex(ctx, db); err != nil {
+	if err := indexers.DropLegacyAddrIndex(ctx, db); err != nil {
 		vgldLog.Errorf("%v", err)
 		return err
 	}
+	if cfg.DropAddrIndex {
+		if err := indexers.DropAddrIndex(ctx, db); err != nil {
+			vgldLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
 	if cfg.DropTxIndex {
 		if err := indexers.DropTxIndex(ctx, db); err != nil {
 			vgldLog.Errorf("%v", err)
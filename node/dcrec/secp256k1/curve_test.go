@@ -870,6 +870,33 @@ func TestScalarBaseMultJacobian(t *testing.T) {
 	}
 }
 
+// TestBytePointsTable ensures every point in the pre-computed table used to
+// accelerate scalar base multiplication decompresses to the expected multiple
+// of the base point.
+func TestBytePointsTable(t *testing.T) {
+	bytePoints := s256BytePoints()
+
+	// Each window of the table houses the multiples 0..255 of the base point
+	// shifted left by the number of bits in the less significant windows.
+	var windowBase JacobianPoint
+	bigAffineToJacobian(curveParams.Gx, curveParams.Gy, &windowBase)
+	for byteNum := len(bytePoints) - 1; byteNum >= 0; byteNum-- {
+		var want JacobianPoint
+		for i := 0; i < len(bytePoints[byteNum]); i++ {
+			got := &bytePoints[byteNum][i]
+			if !got.EquivalentNonConst(&want) {
+				t.Fatalf("mismatched point in window %d at index %d:\n"+
+					"got: (%s, %s, %s)\nwant: (%s, %s, %s)", byteNum, i,
+					got.X, got.Y, got.Z, want.X, want.Y, want.Z)
+			}
+			AddNonConst(&want, &windowBase, &want)
+		}
+		for i := 0; i < 8; i++ {
+			DoubleNonConst(&windowBase, &windowBase)
+		}
+	}
+}
+
 // modNBitLen returns the minimum number of bits required to represent the mod n
 // scalar.  The result is 0 when the value is 0.
 func modNBitLen(s *ModNScalar) uint16 {
@@ -88,6 +88,19 @@ to the signature scheme would invalidate existing uses.  Therefore changes in
 this regard will need to come in the form of a v1 signature scheme and be
 accompanied by the necessary consensus updates.
 
+# MuSig2-VGLv0 Multi-Party Signatures
+
+MuSig2 allows a group of signers to aggregate their public keys into a single
+public key with AggregatePubKeys and to jointly produce an EC-Schnorr-VGLv0
+signature for it in two rounds without any party holding the aggregate private
+key.  Each signer first shares a public nonce created with GenerateNonce.  Once
+all nonces are combined with AggregateNonces, each signer creates a partial
+signature with PartialSign and CombinePartialSignatures produces the final
+signature, which is verified exactly like a single-signer signature.
+
+A secret nonce must never be reused.  PartialSign zeroes the secret nonce it is
+given so it can not be used to sign again.
+
 # Schnorr use in Vigil
 
 At the time of this writing, Schnorr signatures are not yet in widespread use on
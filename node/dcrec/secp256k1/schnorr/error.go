@@ -54,6 +54,26 @@ const (
 	// ErrSigSTooBig is returned when a signature has s with a value that is
 	// greater than or equal to the group order.
 	ErrSigSTooBig = ErrorKind("ErrSigSTooBig")
+
+	// ErrMuSigNoPubKeys is returned when a MuSig2 operation is attempted
+	// without any public keys to aggregate.
+	ErrMuSigNoPubKeys = ErrorKind("ErrMuSigNoPubKeys")
+
+	// ErrMuSigInvalidNonce is returned when a MuSig2 public or aggregate
+	// nonce is malformed or no nonces are provided.
+	ErrMuSigInvalidNonce = ErrorKind("ErrMuSigInvalidNonce")
+
+	// ErrMuSigNonceReused is returned when an attempt is made to create a
+	// MuSig2 partial signature with a secret nonce that was already used.
+	ErrMuSigNonceReused = ErrorKind("ErrMuSigNonceReused")
+
+	// ErrMuSigSignerNotFound is returned when the key of a MuSig2 signer is
+	// not one of the aggregated keys or does not match its secret nonce.
+	ErrMuSigSignerNotFound = ErrorKind("ErrMuSigSignerNotFound")
+
+	// ErrMuSigInvalidPartialSig is returned when a MuSig2 partial signature
+	// is malformed or does not match the public nonce and key of its signer.
+	ErrMuSigInvalidPartialSig = ErrorKind("ErrMuSigInvalidPartialSig")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
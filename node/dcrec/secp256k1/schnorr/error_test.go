@@ -26,6 +26,11 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrSigTooLong, "ErrSigTooLong"},
 		{ErrSigRTooBig, "ErrSigRTooBig"},
 		{ErrSigSTooBig, "ErrSigSTooBig"},
+		{ErrMuSigNoPubKeys, "ErrMuSigNoPubKeys"},
+		{ErrMuSigInvalidNonce, "ErrMuSigInvalidNonce"},
+		{ErrMuSigNonceReused, "ErrMuSigNonceReused"},
+		{ErrMuSigSignerNotFound, "ErrMuSigSignerNotFound"},
+		{ErrMuSigInvalidPartialSig, "ErrMuSigInvalidPartialSig"},
 	}
 
 	for i, test := range tests {
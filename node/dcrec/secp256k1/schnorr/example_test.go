@@ -8,9 +8,9 @@ import (
 	"encoding/hex"
 	"fmt"
 
-	
 	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4"
 	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4/schnorr"
+	"github.com/kdsmith18542/vigil/kawpow"
 )
 
 // This example demonstrates signing a message with the EC-Schnorr-VGLv0 scheme
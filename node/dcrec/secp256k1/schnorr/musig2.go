@@ -0,0 +1,502 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4"
+	"github.com/kdsmith18542/vigil/crypto/blake256"
+	"github.com/kdsmith18542/vigil/kawpow"
+)
+
+const (
+	// PubNonceSize is the size of an encoded MuSig2 public nonce and of an
+	// encoded aggregate nonce.
+	PubNonceSize = 2 * PubKeyBytesLen
+
+	// PartialSignatureSize is the size of an encoded MuSig2 partial
+	// signature.
+	PartialSignatureSize = scalarSize
+)
+
+// Tags that domain separate the hashes used by MuSig2-VGLv0.
+var (
+	keyAggListTag  = []byte("MuSig2-VGLv0/KeyAgg list")
+	keyAggCoeffTag = []byte("MuSig2-VGLv0/KeyAgg coefficient")
+	nonceTag       = []byte("MuSig2-VGLv0/nonce")
+	nonceCoeffTag  = []byte("MuSig2-VGLv0/noncecoef")
+)
+
+// taggedHash returns the BLAKE-256 hash of the provided data prefixed twice by
+// the hash of the provided tag.
+func taggedHash(tag []byte, data ...[]byte) [32]byte {
+	tagHash := blake256.Sum256(tag)
+	h := blake256.NewHasher256()
+	h.WriteBytes(tagHash[:])
+	h.WriteBytes(tagHash[:])
+	for _, d := range data {
+		h.WriteBytes(d)
+	}
+	return h.Sum256()
+}
+
+// hashToScalar returns the provided hash reduced modulo the group order.
+func hashToScalar(hash [32]byte) secp256k1.ModNScalar {
+	var s secp256k1.ModNScalar
+	s.SetBytes(&hash)
+	return s
+}
+
+// isInfinity returns whether the provided Jacobian point is the point at
+// infinity.
+func isInfinity(p *secp256k1.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
+
+// SortPubKeys sorts the provided public keys in place by their compressed
+// serialization so all signers derive the same aggregate key regardless of the
+// order in which they learned the keys.
+func SortPubKeys(pubKeys []*secp256k1.PublicKey) {
+	sort.Slice(pubKeys, func(i, j int) bool {
+		return bytes.Compare(pubKeys[i].SerializeCompressed(),
+			pubKeys[j].SerializeCompressed()) < 0
+	})
+}
+
+// keyAggContext houses the aggregate key of a set of public keys along with
+// the data needed to calculate the key aggregation coefficient of each key.
+type keyAggContext struct {
+	aggKey    secp256k1.JacobianPoint
+	keysHash  [32]byte
+	secondKey []byte
+}
+
+// coefficient returns the key aggregation coefficient of the provided
+// compressed public key.
+func (c *keyAggContext) coefficient(pubKey []byte) secp256k1.ModNScalar {
+	// The second distinct key always has a coefficient of one which saves a
+	// scalar multiplication without affecting security.
+	if c.secondKey != nil && bytes.Equal(pubKey, c.secondKey) {
+		var one secp256k1.ModNScalar
+		one.SetInt(1)
+		return one
+	}
+	return hashToScalar(taggedHash(keyAggCoeffTag, c.keysHash[:], pubKey))
+}
+
+// newKeyAggContext aggregates the provided public keys in the provided order.
+func newKeyAggContext(pubKeys []*secp256k1.PublicKey) (*keyAggContext, error) {
+	if len(pubKeys) == 0 {
+		str := "no public keys to aggregate"
+		return nil, signatureError(ErrMuSigNoPubKeys, str)
+	}
+
+	serialized := make([][]byte, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		if !pubKey.IsOnCurve() {
+			str := "pubkey point is not on curve"
+			return nil, signatureError(ErrPubKeyNotOnCurve, str)
+		}
+		serialized = append(serialized, pubKey.SerializeCompressed())
+	}
+
+	var c keyAggContext
+	c.keysHash = taggedHash(keyAggListTag, serialized...)
+	for _, pubKey := range serialized[1:] {
+		if !bytes.Equal(pubKey, serialized[0]) {
+			c.secondKey = pubKey
+			break
+		}
+	}
+
+	// Q = sum(a_i * P_i)
+	for i, pubKey := range pubKeys {
+		a := c.coefficient(serialized[i])
+		var P, aP secp256k1.JacobianPoint
+		pubKey.AsJacobian(&P)
+		secp256k1.ScalarMultNonConst(&a, &P, &aP)
+		secp256k1.AddNonConst(&c.aggKey, &aP, &c.aggKey)
+	}
+	if isInfinity(&c.aggKey) {
+		str := "aggregate key is the point at infinity"
+		return nil, signatureError(ErrPubKeyNotOnCurve, str)
+	}
+	c.aggKey.ToAffine()
+	return &c, nil
+}
+
+// AggregatePubKeys returns the MuSig2 aggregate public key of the provided
+// public keys.  The aggregate key depends on the order of the keys, so all
+// signers must provide them in the same order, for example by sorting them
+// with SortPubKeys first.
+//
+// A signature by the aggregate key requires a partial signature from the
+// private key of every provided public key and is a regular EC-Schnorr-VGLv0
+// signature which can be verified with Verify.
+func AggregatePubKeys(pubKeys []*secp256k1.PublicKey) (*secp256k1.PublicKey, error) {
+	c, err := newKeyAggContext(pubKeys)
+	if err != nil {
+		return nil, err
+	}
+	return secp256k1.NewPublicKey(&c.aggKey.X, &c.aggKey.Y), nil
+}
+
+// SecretNonce is the secret part of a MuSig2 nonce.  It must only be used to
+// create a single partial signature and is zeroed when it is used.
+type SecretNonce struct {
+	k1, k2 secp256k1.ModNScalar
+	pubKey [PubKeyBytesLen]byte
+}
+
+// Zero clears the secret nonce so it can no longer be used.
+func (n *SecretNonce) Zero() {
+	n.k1.Zero()
+	n.k2.Zero()
+}
+
+// PublicNonce is the public part of a MuSig2 nonce which is shared with the
+// other signers.  It is the concatenation of the two compressed nonce points.
+type PublicNonce [PubNonceSize]byte
+
+// AggregateNonce is the sum of the public nonces of all signers.  It is the
+// concatenation of the two compressed aggregate nonce points, where a point at
+// infinity is encoded as all zeros.
+type AggregateNonce [PubNonceSize]byte
+
+// generateNonce generates a MuSig2 nonce for the provided private key and
+// message hash using randomness from the provided reader.
+func generateNonce(rnd io.Reader, privKey *secp256k1.PrivateKey, hash []byte) (*SecretNonce, *PublicNonce, error) {
+	var randBytes [32]byte
+	if _, err := io.ReadFull(rnd, randBytes[:]); err != nil {
+		return nil, nil, err
+	}
+
+	// The nonces are derived from the randomness along with the private key
+	// and the message so that they remain unpredictable even if the
+	// randomness is weak.
+	var privKeyBytes [scalarSize]byte
+	privKey.Key.PutBytes(&privKeyBytes)
+	defer zeroArray(&privKeyBytes)
+	pubKey := privKey.PubKey().SerializeCompressed()
+	var hashLen [4]byte
+	binary.BigEndian.PutUint32(hashLen[:], uint32(len(hash)))
+
+	var secNonce SecretNonce
+	var pubNonce PublicNonce
+	copy(secNonce.pubKey[:], pubKey)
+	for i, k := range []*secp256k1.ModNScalar{&secNonce.k1, &secNonce.k2} {
+		h := taggedHash(nonceTag, randBytes[:], privKeyBytes[:], pubKey,
+			hashLen[:], hash, []byte{byte(i)})
+		k.SetBytes(&h)
+		zeroArray(&h)
+		if k.IsZero() {
+			secNonce.Zero()
+			str := "generated nonce is zero"
+			return nil, nil, signatureError(ErrMuSigInvalidNonce, str)
+		}
+		var R secp256k1.JacobianPoint
+		secp256k1.ScalarBaseMultNonConst(k, &R)
+		R.ToAffine()
+		point := secp256k1.NewPublicKey(&R.X, &R.Y).SerializeCompressed()
+		copy(pubNonce[i*PubKeyBytesLen:], point)
+	}
+	zeroArray(&randBytes)
+	return &secNonce, &pubNonce, nil
+}
+
+// GenerateNonce generates a new MuSig2 nonce for signing the provided message
+// hash with the provided private key.  The public nonce must be shared with the
+// other signers before any partial signature is created, while the secret nonce
+// must be kept private and only ever be used for a single partial signature.
+func GenerateNonce(privKey *secp256k1.PrivateKey, hash []byte) (*SecretNonce, *PublicNonce, error) {
+	if privKey.Key.IsZero() {
+		str := "private key is zero"
+		return nil, nil, signatureError(ErrPrivateKeyIsZero, str)
+	}
+	return generateNonce(rand.Reader, privKey, hash)
+}
+
+// parseNoncePoint parses a compressed nonce point.  A point encoded as all
+// zeros is the point at infinity when allowed.
+func parseNoncePoint(b []byte, allowInfinity bool, result *secp256k1.JacobianPoint) error {
+	if allowInfinity && bytes.Equal(b, make([]byte, PubKeyBytesLen)) {
+		*result = secp256k1.JacobianPoint{}
+		return nil
+	}
+	point, err := ParsePubKey(b)
+	if err != nil {
+		str := fmt.Sprintf("invalid nonce point: %v", err)
+		return signatureError(ErrMuSigInvalidNonce, str)
+	}
+	point.AsJacobian(result)
+	return nil
+}
+
+// encodeNoncePoint encodes a nonce point as a compressed point or as all zeros
+// when it is the point at infinity.
+func encodeNoncePoint(p *secp256k1.JacobianPoint, b []byte) {
+	if isInfinity(p) {
+		copy(b, make([]byte, PubKeyBytesLen))
+		return
+	}
+	p.ToAffine()
+	copy(b, secp256k1.NewPublicKey(&p.X, &p.Y).SerializeCompressed())
+}
+
+// AggregateNonces returns the aggregate nonce of the provided public nonces of
+// all signers.
+func AggregateNonces(pubNonces []*PublicNonce) (*AggregateNonce, error) {
+	if len(pubNonces) == 0 {
+		str := "no public nonces to aggregate"
+		return nil, signatureError(ErrMuSigInvalidNonce, str)
+	}
+	var R1, R2 secp256k1.JacobianPoint
+	for _, pubNonce := range pubNonces {
+		var p1, p2 secp256k1.JacobianPoint
+		if err := parseNoncePoint(pubNonce[:PubKeyBytesLen], false, &p1); err != nil {
+			return nil, err
+		}
+		if err := parseNoncePoint(pubNonce[PubKeyBytesLen:], false, &p2); err != nil {
+			return nil, err
+		}
+		secp256k1.AddNonConst(&R1, &p1, &R1)
+		secp256k1.AddNonConst(&R2, &p2, &R2)
+	}
+	var aggNonce AggregateNonce
+	encodeNoncePoint(&R1, aggNonce[:PubKeyBytesLen])
+	encodeNoncePoint(&R2, aggNonce[PubKeyBytesLen:])
+	return &aggNonce, nil
+}
+
+// signingSession houses the values shared by all signers of a message which
+// are derived from the public keys, the aggregate nonce, and the message.
+type signingSession struct {
+	keys *keyAggContext
+
+	// b is the nonce coefficient and e is the signature challenge.
+	b, e secp256k1.ModNScalar
+
+	// r is the final nonce point in affine coordinates and negate is whether
+	// the nonces of the signers must be negated because its y coordinate is
+	// odd.
+	r      secp256k1.JacobianPoint
+	negate bool
+}
+
+// newSigningSession returns the signing session for the provided public keys,
+// aggregate nonce, and message hash.
+func newSigningSession(pubKeys []*secp256k1.PublicKey, aggNonce *AggregateNonce, hash []byte) (*signingSession, error) {
+	if len(hash) != scalarSize {
+		str := fmt.Sprintf("wrong size for message hash (got %v, want %v)",
+			len(hash), scalarSize)
+		return nil, signatureError(ErrInvalidHashLen, str)
+	}
+	keys, err := newKeyAggContext(pubKeys)
+	if err != nil {
+		return nil, err
+	}
+	var R1, R2 secp256k1.JacobianPoint
+	if err := parseNoncePoint(aggNonce[:PubKeyBytesLen], true, &R1); err != nil {
+		return nil, err
+	}
+	if err := parseNoncePoint(aggNonce[PubKeyBytesLen:], true, &R2); err != nil {
+		return nil, err
+	}
+
+	// b = H(aggnonce || Q || m)
+	s := &signingSession{keys: keys}
+	aggKey := secp256k1.NewPublicKey(&keys.aggKey.X, &keys.aggKey.Y)
+	s.b = hashToScalar(taggedHash(nonceCoeffTag, aggNonce[:],
+		aggKey.SerializeCompressed(), hash))
+
+	// R = R1 + b*R2, or G when that is the point at infinity.
+	var bR2 secp256k1.JacobianPoint
+	secp256k1.ScalarMultNonConst(&s.b, &R2, &bR2)
+	secp256k1.AddNonConst(&R1, &bR2, &s.r)
+	if isInfinity(&s.r) {
+		var one secp256k1.ModNScalar
+		one.SetInt(1)
+		secp256k1.ScalarBaseMultNonConst(&one, &s.r)
+	}
+	s.r.ToAffine()
+	s.negate = s.r.Y.IsOdd()
+
+	// e = H(r || m) exactly as calculated by the verification of the final
+	// signature.
+	var commitmentInput [scalarSize * 2]byte
+	s.r.X.PutBytesUnchecked(commitmentInput[0:scalarSize])
+	copy(commitmentInput[scalarSize:], hash)
+	commitmentHash := kawpow.HashFunc(commitmentInput[:])
+	var commitment [32]byte
+	copy(commitment[:], commitmentHash[:])
+	if overflow := s.e.SetBytes(&commitment); overflow != 0 {
+		str := "hash of (R || m) too big"
+		return nil, signatureError(ErrSchnorrHashValue, str)
+	}
+	return s, nil
+}
+
+// PartialSignature is the signature share of a single signer of a MuSig2
+// signature.
+type PartialSignature struct {
+	s secp256k1.ModNScalar
+}
+
+// Serialize returns the partial signature encoded as a big-endian uint256.
+func (sig *PartialSignature) Serialize() []byte {
+	var b [PartialSignatureSize]byte
+	sig.s.PutBytes(&b)
+	return b[:]
+}
+
+// ParsePartialSignature parses a MuSig2 partial signature.
+func ParsePartialSignature(sig []byte) (*PartialSignature, error) {
+	if len(sig) != PartialSignatureSize {
+		str := fmt.Sprintf("malformed partial signature: wrong size %d, "+
+			"want %d", len(sig), PartialSignatureSize)
+		return nil, signatureError(ErrMuSigInvalidPartialSig, str)
+	}
+	var s PartialSignature
+	if overflow := s.s.SetByteSlice(sig); overflow {
+		str := "invalid partial signature: s >= group order"
+		return nil, signatureError(ErrMuSigInvalidPartialSig, str)
+	}
+	return &s, nil
+}
+
+// PartialSign creates the partial signature of the provided message hash by
+// the provided private key for the aggregate key of the provided public keys,
+// which must include the public key of the private key.  The aggregate nonce
+// must be the aggregate of the public nonces of all signers including the one
+// belonging to the provided secret nonce.
+//
+// The secret nonce is zeroed before the partial signature is returned and can
+// not be used again since signing two different messages with the same nonce
+// reveals the private key.
+func PartialSign(secNonce *SecretNonce, privKey *secp256k1.PrivateKey,
+	pubKeys []*secp256k1.PublicKey, aggNonce *AggregateNonce,
+	hash []byte) (*PartialSignature, error) {
+
+	k1, k2 := secNonce.k1, secNonce.k2
+	defer k1.Zero()
+	defer k2.Zero()
+	secNonce.Zero()
+	if k1.IsZero() || k2.IsZero() {
+		str := "secret nonce was already used"
+		return nil, signatureError(ErrMuSigNonceReused, str)
+	}
+	if privKey.Key.IsZero() {
+		str := "private key is zero"
+		return nil, signatureError(ErrPrivateKeyIsZero, str)
+	}
+	pubKey := privKey.PubKey().SerializeCompressed()
+	if !bytes.Equal(pubKey, secNonce.pubKey[:]) {
+		str := "secret nonce was generated for a different private key"
+		return nil, signatureError(ErrMuSigSignerNotFound, str)
+	}
+	var found bool
+	for _, p := range pubKeys {
+		if bytes.Equal(p.SerializeCompressed(), pubKey) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		str := "public key of the signer is not one of the aggregated keys"
+		return nil, signatureError(ErrMuSigSignerNotFound, str)
+	}
+
+	s, err := newSigningSession(pubKeys, aggNonce, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// k = k1 + b*k2, negated when the final nonce has an odd y coordinate.
+	k := new(secp256k1.ModNScalar).Mul2(&s.b, &k2).Add(&k1)
+	defer k.Zero()
+	if s.negate {
+		k.Negate()
+	}
+
+	// s_i = k - e*a_i*d_i
+	a := s.keys.coefficient(pubKey)
+	ead := new(secp256k1.ModNScalar).Mul2(&s.e, &a).Mul(&privKey.Key)
+	sig := &PartialSignature{}
+	sig.s.Set(ead).Negate().Add(k)
+	ead.Zero()
+	return sig, nil
+}
+
+// VerifyPartialSignature returns whether the provided partial signature is a
+// valid signature share of the signer with the provided public key and public
+// nonce.  It allows identifying the signer responsible when the final
+// signature is invalid.
+func VerifyPartialSignature(sig *PartialSignature, pubNonce *PublicNonce,
+	pubKey *secp256k1.PublicKey, pubKeys []*secp256k1.PublicKey,
+	aggNonce *AggregateNonce, hash []byte) error {
+
+	s, err := newSigningSession(pubKeys, aggNonce, hash)
+	if err != nil {
+		return err
+	}
+	var R1, R2 secp256k1.JacobianPoint
+	if err := parseNoncePoint(pubNonce[:PubKeyBytesLen], false, &R1); err != nil {
+		return err
+	}
+	if err := parseNoncePoint(pubNonce[PubKeyBytesLen:], false, &R2); err != nil {
+		return err
+	}
+
+	// R_i = R1_i + b*R2_i, negated when the final nonce has an odd y
+	// coordinate.
+	var bR2, Ri secp256k1.JacobianPoint
+	secp256k1.ScalarMultNonConst(&s.b, &R2, &bR2)
+	secp256k1.AddNonConst(&R1, &bR2, &Ri)
+	if s.negate {
+		Ri.ToAffine()
+		Ri.Y.Negate(1).Normalize()
+	}
+
+	// s_i*G + e*a_i*P_i must equal R_i.
+	a := s.keys.coefficient(pubKey.SerializeCompressed())
+	ea := new(secp256k1.ModNScalar).Mul2(&s.e, &a)
+	var P, sG, eaP, calc secp256k1.JacobianPoint
+	pubKey.AsJacobian(&P)
+	secp256k1.ScalarBaseMultNonConst(&sig.s, &sG)
+	secp256k1.ScalarMultNonConst(ea, &P, &eaP)
+	secp256k1.AddNonConst(&sG, &eaP, &calc)
+	if isInfinity(&Ri) || !calc.EquivalentNonConst(&Ri) {
+		str := "partial signature does not match the public nonce and key"
+		return signatureError(ErrMuSigInvalidPartialSig, str)
+	}
+	return nil
+}
+
+// CombinePartialSignatures combines the partial signatures of all signers into
+// the final signature of the provided message hash by the aggregate key of the
+// provided public keys.  The partial signatures are not verified individually,
+// so the final signature should be verified with Verify, and the partial
+// signatures with VerifyPartialSignature to find the culprit when it is
+// invalid.
+func CombinePartialSignatures(pubKeys []*secp256k1.PublicKey,
+	aggNonce *AggregateNonce, hash []byte,
+	partialSigs []*PartialSignature) (*Signature, error) {
+
+	s, err := newSigningSession(pubKeys, aggNonce, hash)
+	if err != nil {
+		return nil, err
+	}
+	var sum secp256k1.ModNScalar
+	for _, sig := range partialSigs {
+		sum.Add(&sig.s)
+	}
+	return NewSignature(&s.r.X, &sum), nil
+}
@@ -0,0 +1,381 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4"
+)
+
+// musigTestKeys are the private keys used by the MuSig2 test vectors.  The
+// third key is the negation of the first one.
+var musigTestKeys = []string{
+	"0000000000000000000000000000000000000000000000000000000000000001",
+	"0000000000000000000000000000000000000000000000000000000000000002",
+	"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+	"6b1b3e8a7c5d2f4e9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f708192a3b4c5",
+}
+
+// musigTestPrivKeys returns the private keys with the provided indexes into
+// musigTestKeys along with their public keys.
+func musigTestPrivKeys(indexes []int) ([]*secp256k1.PrivateKey, []*secp256k1.PublicKey) {
+	privKeys := make([]*secp256k1.PrivateKey, 0, len(indexes))
+	pubKeys := make([]*secp256k1.PublicKey, 0, len(indexes))
+	for _, i := range indexes {
+		privKey := secp256k1.PrivKeyFromBytes(hexToBytes(musigTestKeys[i]))
+		privKeys = append(privKeys, privKey)
+		pubKeys = append(pubKeys, privKey.PubKey())
+	}
+	return privKeys, pubKeys
+}
+
+// TestMuSig2KeyAggregation ensures aggregate keys match the test vectors,
+// including for duplicate keys and keys which are negations of each other, and
+// that they depend on the order of the keys.
+func TestMuSig2KeyAggregation(t *testing.T) {
+	tests := []struct {
+		name string // test description
+		keys []int  // indexes into musigTestKeys
+		want string // hex encoded compressed aggregate key
+	}{{
+		name: "two keys",
+		keys: []int{0, 1},
+		want: "02739bbaba7ed6d751a542f3efd851ec80e3a54fb3020bfe31ebd2ebb754de73ad",
+	}, {
+		name: "two keys in reverse order",
+		keys: []int{1, 0},
+		want: "02fc9761bf2efb37e1dfc6a6877cfbc78d829e6dda66801f22a574d59a12173134",
+	}, {
+		name: "key and its negation",
+		keys: []int{0, 1, 2},
+		want: "03675d0e1c9ed923cd2f51b62fb12cdd62dd2ef9f0119b71da6dd2649eccbb68d3",
+	}, {
+		name: "duplicate key",
+		keys: []int{0, 0, 1},
+		want: "035f4ef51eea3a4c259e610741defdab555e760148d05905b93913f9a22e3a522b",
+	}, {
+		name: "single key",
+		keys: []int{3},
+		want: "0349390680c268629825dbd7a67b4790e53e961717947cb0753f0c7ee42a2a5a42",
+	}, {
+		name: "four keys",
+		keys: []int{0, 1, 2, 3},
+		want: "03445d2d0df5d94d4de1017922bd965ff6b87e487e45d8459aa81ddbbd1663aa62",
+	}}
+
+	for _, test := range tests {
+		_, pubKeys := musigTestPrivKeys(test.keys)
+		aggKey, err := AggregatePubKeys(pubKeys)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		got := hex.EncodeToString(aggKey.SerializeCompressed())
+		if got != test.want {
+			t.Errorf("%s: unexpected aggregate key -- got %s, want %s",
+				test.name, got, test.want)
+		}
+	}
+
+	_, err := AggregatePubKeys(nil)
+	if !errors.Is(err, ErrMuSigNoPubKeys) {
+		t.Fatalf("unexpected error aggregating no keys -- got %v, want %v",
+			err, ErrMuSigNoPubKeys)
+	}
+}
+
+// TestMuSig2SignVectors ensures nonces, partial signatures, and final
+// signatures match the test vectors when the nonces are generated with fixed
+// randomness and that the final signatures are valid for the aggregate key.
+func TestMuSig2SignVectors(t *testing.T) {
+	const hash = "4a8c57c1d0e3b2f5a6978877665544332211ffeeddccbbaa9988776655443322"
+	tests := []struct {
+		name        string   // test description
+		keys        []int    // indexes into musigTestKeys
+		pubNonces   []string // hex encoded public nonce per signer
+		aggNonce    string   // hex encoded aggregate nonce
+		partialSigs []string // hex encoded partial signature per signer
+		sig         string   // hex encoded final signature
+	}{{
+		name: "key and its negation",
+		keys: []int{0, 1, 2},
+		pubNonces: []string{
+			"037ca1e3e53239a1f67cce0481e3bbb13a166cfd4d19d8fb1994c71a449b36e801" +
+				"03c6f01f7bb7390f387435d2b7102d9c34863a65e8fdcc57ad7d800ed2ac17a89c",
+			"026ac94cddf4404cc2b6b80aeeb1353c81ed94bb0f4b63350d0e2812d9f5bbcb03" +
+				"0248b9c4da00303d2db0fcf71ea5257216ecc4ed897f22f2690b840f14a8ee11bc",
+			"034c2805af1d79b20b46f069d34d72f07386ddf490b5c2107ce1c959afff45f602" +
+				"031ebf03a3bc72e91dfb9f8a45f2a900b3db78271d6bcd276ae29f64a70501f635",
+		},
+		aggNonce: "026dfe7a06eb4d454176d2586d5875f3a3842dc100d66919c67a73bfc190e30ea6" +
+			"026499d17725c92c68964136fcb7e85aad7eb741273481ddafc371fb22cce77691",
+		partialSigs: []string{
+			"c7284ef0291bde6baca19f288791e922b73fdab70bb1dc2f40a0fe5985d4a05b",
+			"844dd48ad8c97fdfb94142244b9ba6b922c6c07b7a46e94892a491d8c9326d71",
+			"6d5891e7aa9218c4a0175f1a2a55f90d7fd522c9df93ac7910efa56be790849c",
+		},
+		sig: "fa703e8a3a0f8e5ca09d201b640160d9a07ee6e90689dfbc1a0dfdc2f8d991b2" +
+			"b8ceb562ac77771005fa4066fd8388ea9f2ce115b643d1b52462d71166615127",
+	}, {
+		name: "single signer",
+		keys: []int{3},
+		pubNonces: []string{
+			"03d196aefa28fbd1493a15fcf204de7a25f4a40cfb521f16443db22a312fb1dd59" +
+				"0396c81726b93697909c4e9b5108cfe917c79f54d54e466eb8833f14a1fbea1b08",
+		},
+		aggNonce: "03d196aefa28fbd1493a15fcf204de7a25f4a40cfb521f16443db22a312fb1dd59" +
+			"0396c81726b93697909c4e9b5108cfe917c79f54d54e466eb8833f14a1fbea1b08",
+		partialSigs: []string{
+			"0cb9629fc53875ec4ea877ba385a97dded6c8af7ad3205360885bedae43a180d",
+		},
+		sig: "8239f7b6a6e55ce7dd80c658530271bf314f605f1989806523fc9bbc65bb5c00" +
+			"0cb9629fc53875ec4ea877ba385a97dded6c8af7ad3205360885bedae43a180d",
+	}, {
+		name: "duplicate key",
+		keys: []int{0, 0, 1},
+		pubNonces: []string{
+			"037ca1e3e53239a1f67cce0481e3bbb13a166cfd4d19d8fb1994c71a449b36e801" +
+				"03c6f01f7bb7390f387435d2b7102d9c34863a65e8fdcc57ad7d800ed2ac17a89c",
+			"03e60e8e2ed139caf8733ea020afa2a95bf309015ee0a7653558e7e3a3462ec3de" +
+				"0370cf2153ecaa7e193987b3a77a8aeba48accda767eeb24e956e03710204d1602",
+			"03196d87d123c8e7b73e7931584b4e95215dcd335227b19a8606d05be47b294477" +
+				"0387b374649eb3eeddc1cd179933b1034da0e2b23eb04992cde4b45b3d77cafaf7",
+		},
+		aggNonce: "02ebb687c22f78399e74635b37055b94ad448466350c119d9fa10da750d1533e84" +
+			"038486107846aef8543d7be490e82e0606691842ab5b590790b800c72699f1b563",
+		partialSigs: []string{
+			"71bafaedbb7558e30da62fc1c6ddb0b93142d2648b9aceda71d0cbbbab7e06d9",
+			"3e31be63a974b5f108667ead0f573ee37df6b5780d218e671ff5a748f863ce15",
+			"0f1282219b739837540e10b086a8f46fedf8c364f18a232177308ba2d2d50749",
+		},
+		sig: "8aafa65f862b497d4899b5b94b4fad1ced4a424a4ff7d87aba64fc7077a49572" +
+			"beff3b73005da70b6a1abf1f5cdde40c9d324b418a46806308f6fea776b6dc37",
+	}}
+
+	hashBytes := hexToBytes(hash)
+	for _, test := range tests {
+		privKeys, pubKeys := musigTestPrivKeys(test.keys)
+
+		// Generate the nonce of each signer with fixed randomness.
+		secNonces := make([]*SecretNonce, 0, len(privKeys))
+		pubNonces := make([]*PublicNonce, 0, len(privKeys))
+		for i, privKey := range privKeys {
+			rnd := bytes.NewReader(bytes.Repeat([]byte{byte(0x10 + i)}, 32))
+			secNonce, pubNonce, err := generateNonce(rnd, privKey, hashBytes)
+			if err != nil {
+				t.Fatalf("%s: unexpected nonce error: %v", test.name, err)
+			}
+			got := hex.EncodeToString(pubNonce[:])
+			if got != test.pubNonces[i] {
+				t.Fatalf("%s: unexpected public nonce %d -- got %s, want %s",
+					test.name, i, got, test.pubNonces[i])
+			}
+			secNonces = append(secNonces, secNonce)
+			pubNonces = append(pubNonces, pubNonce)
+		}
+		aggNonce, err := AggregateNonces(pubNonces)
+		if err != nil {
+			t.Fatalf("%s: unexpected aggregate nonce error: %v", test.name,
+				err)
+		}
+		if got := hex.EncodeToString(aggNonce[:]); got != test.aggNonce {
+			t.Fatalf("%s: unexpected aggregate nonce -- got %s, want %s",
+				test.name, got, test.aggNonce)
+		}
+
+		partialSigs := make([]*PartialSignature, 0, len(privKeys))
+		for i, privKey := range privKeys {
+			partialSig, err := PartialSign(secNonces[i], privKey, pubKeys,
+				aggNonce, hashBytes)
+			if err != nil {
+				t.Fatalf("%s: unexpected partial signature error: %v",
+					test.name, err)
+			}
+			got := hex.EncodeToString(partialSig.Serialize())
+			if got != test.partialSigs[i] {
+				t.Fatalf("%s: unexpected partial signature %d -- got %s, "+
+					"want %s", test.name, i, got, test.partialSigs[i])
+			}
+			err = VerifyPartialSignature(partialSig, pubNonces[i],
+				pubKeys[i], pubKeys, aggNonce, hashBytes)
+			if err != nil {
+				t.Fatalf("%s: partial signature %d does not verify: %v",
+					test.name, i, err)
+			}
+			partialSigs = append(partialSigs, partialSig)
+		}
+
+		sig, err := CombinePartialSignatures(pubKeys, aggNonce, hashBytes,
+			partialSigs)
+		if err != nil {
+			t.Fatalf("%s: unexpected combine error: %v", test.name, err)
+		}
+		if got := hex.EncodeToString(sig.Serialize()); got != test.sig {
+			t.Fatalf("%s: unexpected signature -- got %s, want %s",
+				test.name, got, test.sig)
+		}
+		aggKey, err := AggregatePubKeys(pubKeys)
+		if err != nil {
+			t.Fatalf("%s: unexpected aggregation error: %v", test.name, err)
+		}
+		if !sig.Verify(hashBytes, aggKey) {
+			t.Fatalf("%s: signature does not verify", test.name)
+		}
+	}
+}
+
+// TestMuSig2SignRandom ensures signatures produced by random sets of signers
+// with random keys and messages are valid for their aggregate key and that
+// invalid partial signatures are detected.
+func TestMuSig2SignRandom(t *testing.T) {
+	// Use a unique random seed each test instance and log it if the tests fail.
+	seed := time.Now().Unix()
+	rng := rand.New(rand.NewSource(seed))
+	defer func(t *testing.T, seed int64) {
+		if t.Failed() {
+			t.Logf("random seed: %d", seed)
+		}
+	}(t, seed)
+
+	for i := 0; i < 50; i++ {
+		numSigners := 1 + rng.Intn(5)
+		privKeys := make([]*secp256k1.PrivateKey, 0, numSigners)
+		pubKeys := make([]*secp256k1.PublicKey, 0, numSigners)
+		for j := 0; j < numSigners; j++ {
+			privKey, err := secp256k1.GeneratePrivateKeyFromRand(rng)
+			if err != nil {
+				t.Fatalf("failed to generate private key: %v", err)
+			}
+			privKeys = append(privKeys, privKey)
+			pubKeys = append(pubKeys, privKey.PubKey())
+		}
+		SortPubKeys(pubKeys)
+		var hash [32]byte
+		if _, err := rng.Read(hash[:]); err != nil {
+			t.Fatalf("failed to read random hash: %v", err)
+		}
+
+		secNonces := make([]*SecretNonce, 0, numSigners)
+		pubNonces := make([]*PublicNonce, 0, numSigners)
+		for _, privKey := range privKeys {
+			secNonce, pubNonce, err := GenerateNonce(privKey, hash[:])
+			if err != nil {
+				t.Fatalf("failed to generate nonce: %v", err)
+			}
+			secNonces = append(secNonces, secNonce)
+			pubNonces = append(pubNonces, pubNonce)
+		}
+		aggNonce, err := AggregateNonces(pubNonces)
+		if err != nil {
+			t.Fatalf("failed to aggregate nonces: %v", err)
+		}
+		partialSigs := make([]*PartialSignature, 0, numSigners)
+		for j, privKey := range privKeys {
+			partialSig, err := PartialSign(secNonces[j], privKey, pubKeys,
+				aggNonce, hash[:])
+			if err != nil {
+				t.Fatalf("failed to create partial signature: %v", err)
+			}
+			partialSigs = append(partialSigs, partialSig)
+		}
+		sig, err := CombinePartialSignatures(pubKeys, aggNonce, hash[:],
+			partialSigs)
+		if err != nil {
+			t.Fatalf("failed to combine partial signatures: %v", err)
+		}
+		aggKey, err := AggregatePubKeys(pubKeys)
+		if err != nil {
+			t.Fatalf("failed to aggregate keys: %v", err)
+		}
+		if !sig.Verify(hash[:], aggKey) {
+			t.Fatalf("failed to verify signature\nsig: %x\nhash: %x\n"+
+				"aggregate key: %x", sig.Serialize(), hash,
+				aggKey.SerializeCompressed())
+		}
+
+		// A partial signature of another signer must be rejected.
+		signer := rng.Intn(numSigners)
+		other := partialSigs[(signer+1)%numSigners]
+		err = VerifyPartialSignature(other, pubNonces[signer],
+			privKeys[signer].PubKey(), pubKeys, aggNonce, hash[:])
+		if numSigners > 1 && !errors.Is(err, ErrMuSigInvalidPartialSig) {
+			t.Fatalf("unexpected error verifying partial signature of "+
+				"another signer -- got %v, want %v", err,
+				ErrMuSigInvalidPartialSig)
+		}
+	}
+}
+
+// TestMuSig2Errors ensures misuse of secret nonces and signer keys is
+// detected.
+func TestMuSig2Errors(t *testing.T) {
+	privKeys, pubKeys := musigTestPrivKeys([]int{0, 1})
+	otherKeys, _ := musigTestPrivKeys([]int{3})
+	hash := hexToBytes("4a8c57c1d0e3b2f5a6978877665544332211ffeeddccbbaa9988776655443322")
+
+	secNonce, pubNonce, err := GenerateNonce(privKeys[0], hash)
+	if err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+	_, pubNonce2, err := GenerateNonce(privKeys[1], hash)
+	if err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+	aggNonce, err := AggregateNonces([]*PublicNonce{pubNonce, pubNonce2})
+	if err != nil {
+		t.Fatalf("failed to aggregate nonces: %v", err)
+	}
+
+	// The secret nonce must belong to the signing key.
+	_, err = PartialSign(secNonce, privKeys[1], pubKeys, aggNonce, hash)
+	if !errors.Is(err, ErrMuSigSignerNotFound) {
+		t.Fatalf("unexpected error signing with another key -- got %v, "+
+			"want %v", err, ErrMuSigSignerNotFound)
+	}
+
+	// The secret nonce is consumed even by a failed attempt.
+	_, err = PartialSign(secNonce, privKeys[0], pubKeys, aggNonce, hash)
+	if !errors.Is(err, ErrMuSigNonceReused) {
+		t.Fatalf("unexpected error reusing nonce -- got %v, want %v", err,
+			ErrMuSigNonceReused)
+	}
+
+	// The signing key must be one of the aggregated keys.
+	secNonce, _, err = GenerateNonce(otherKeys[0], hash)
+	if err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+	_, err = PartialSign(secNonce, otherKeys[0], pubKeys, aggNonce, hash)
+	if !errors.Is(err, ErrMuSigSignerNotFound) {
+		t.Fatalf("unexpected error signing with unknown key -- got %v, "+
+			"want %v", err, ErrMuSigSignerNotFound)
+	}
+
+	// Malformed nonces and partial signatures must be rejected.
+	var badNonce PublicNonce
+	copy(badNonce[:], pubNonce[:])
+	badNonce[0] = 0x04
+	_, err = AggregateNonces([]*PublicNonce{&badNonce, pubNonce2})
+	if !errors.Is(err, ErrMuSigInvalidNonce) {
+		t.Fatalf("unexpected error aggregating bad nonce -- got %v, want %v",
+			err, ErrMuSigInvalidNonce)
+	}
+	_, err = ParsePartialSignature(bytes.Repeat([]byte{0xff}, 32))
+	if !errors.Is(err, ErrMuSigInvalidPartialSig) {
+		t.Fatalf("unexpected error parsing overflowing partial signature "+
+			"-- got %v, want %v", err, ErrMuSigInvalidPartialSig)
+	}
+	_, err = ParsePartialSignature(make([]byte, 31))
+	if !errors.Is(err, ErrMuSigInvalidPartialSig) {
+		t.Fatalf("unexpected error parsing short partial signature -- got "+
+			"%v, want %v", err, ErrMuSigInvalidPartialSig)
+	}
+}
@@ -11,8 +11,8 @@ import (
 	"testing"
 	"time"
 
-	
 	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4"
+	"github.com/kdsmith18542/vigil/kawpow"
 )
 
 // TestSignatureParsing ensures that signatures are properly parsed including
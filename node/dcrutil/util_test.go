@@ -67,12 +67,12 @@ func (p *mockAddrParams) AddrIDScriptHashV0() [2]byte {
 // was written.
 func mockMainNetParams() *mockAddrParams {
 	return &mockAddrParams{
-		pubKeyID:     [2]byte{0x13, 0x86}, // starts with Dk
-		pkhEcdsaID:   [2]byte{0x07, 0x3f}, // starts with Ds
-		pkhEd25519ID: [2]byte{0x07, 0x1f}, // starts with De
-		pkhSchnorrID: [2]byte{0x07, 0x01}, // starts with DS
-		scriptHashID: [2]byte{0x07, 0x1a}, // starts with Dc
-		privKeyID:    [2]byte{0x22, 0xde}, // starts with Pm
+		pubKeyID:     [2]byte{0x2c, 0x08}, // starts with Vk
+		pkhEcdsaID:   [2]byte{0x10, 0x41}, // starts with Vs
+		pkhEd25519ID: [2]byte{0x10, 0x21}, // starts with Ve
+		pkhSchnorrID: [2]byte{0x10, 0x03}, // starts with VS
+		scriptHashID: [2]byte{0x10, 0x1c}, // starts with Vc
+		privKeyID:    [2]byte{0x23, 0x1b}, // starts with Pv
 	}
 }
 
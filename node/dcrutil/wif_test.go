@@ -20,7 +20,7 @@ import (
 func TestWIF(t *testing.T) {
 	t.Parallel()
 
-	mainNetPrivKeyID := [2]byte{0x22, 0xde} // starts with Pm
+	mainNetPrivKeyID := [2]byte{0x23, 0x1b} // starts with Pv
 	testNetPrivKeyID := [2]byte{0x23, 0x0e} // starts with Pt
 	simNetPrivKeyID := [2]byte{0x23, 0x07}  // starts with Ps
 	regNetPrivKeyID := [2]byte{0x22, 0xfe}  // starts with Pr
@@ -76,19 +76,19 @@ func TestWIF(t *testing.T) {
 		name:      "encoded wif too long",
 		skipMake:  true,
 		net:       mainNetPrivKeyID,
-		wif:       "2jV1H5Abfm1gV6GxGxNAvVfuSJPApQ9mr5vNzqJctmzbA6Km9vsBDvW",
+		wif:       "2kAqKoo1rnhV5uKo5mtJAFKsKe9Xwgr2eRtVkceeT6QBb72kJHawinv",
 		decodeErr: ErrMalformedPrivateKey,
 	}, {
 		name:      "bad checksum",
 		skipMake:  true,
 		net:       mainNetPrivKeyID,
-		wif:       "PmQdMn8xafwaQouk8ngs1CccRCB1ZmsqQxBaxNR4vhQi5a5QB5717",
+		wif:       "PvRzpeywTMKToFUYzXJwUmEP8NgGMhQNkt8auW8ChebgVkGabxxxd",
 		decodeErr: ErrChecksumMismatch,
 	}, {
 		name:      "bad decoded data length",
 		skipMake:  true,
 		net:       mainNetPrivKeyID,
-		wif:       "6A9ymjvs2UDRijKt1s2qfC5i9cvQiZmBDbzmDukTfT3jZS4mStxo",
+		wif:       "6CCZBW5EyTAgXxNL3APhcNeHPUsyXDiadbz5nntRM629DXyJ9h9T",
 		decodeErr: ErrMalformedPrivateKey,
 	}, {
 		name:      "wif for wrong network",
@@ -100,7 +100,7 @@ func TestWIF(t *testing.T) {
 		name:      "invalid ed25519 privkey via decode",
 		skipMake:  true,
 		net:       mainNetPrivKeyID,
-		wif:       "PmQgtn9ZpDCASuNpjbwDkxHqskf3kTPFFpLZ4SMWtda6PmFyvM5aG",
+		wif:       "PvS4MezYgta3qLwdbLZJEWucawAJYNunbkHZ1a4efam4owT5wEn2W",
 		decodeErr: fmt.Errorf("not on subgroup (>N)"),
 	}, {
 		// ---------------------------------------------------------------------
@@ -111,7 +111,7 @@ func TestWIF(t *testing.T) {
 		privKey: priv1,
 		net:     mainNetPrivKeyID,
 		dsa:     VGLec.STEcdsaSecp256k1,
-		wif:     "PmQdMn8xafwaQouk8ngs1CccRCB1ZmsqQxBaxNR4vhQi5a5QB5716",
+		wif:     "PvRzpeywTMKToFUYzXJwUmEP8NgGMhQNkt8auW8ChebgVkGabxxxc",
 		pubKey:  pub1,
 	}, {
 		name:    "testnet ecdsa secp256k1 priv2",
@@ -139,7 +139,7 @@ func TestWIF(t *testing.T) {
 		privKey: priv1,
 		net:     mainNetPrivKeyID,
 		dsa:     VGLec.STEd25519,
-		wif:     "PmQfJXKC2ho1633ZiVbSdCZw1y68BVXYFpyE2UfDcbQN5xa3DByDn",
+		wif:     "PvS2mQAAuPAtUUcNaEDX6mBhj9bNyR45bkvDycNMPYbLW8m8e1nGW",
 		pubKey:  "667c1c28ec2d59ceb33673a94165a5e51d5cbcef620aec663cd83638643bba3b",
 	}, {
 		name:    "testnet ed25519 priv3",
@@ -167,7 +167,7 @@ func TestWIF(t *testing.T) {
 		privKey: priv1,
 		net:     mainNetPrivKeyID,
 		dsa:     VGLec.STSchnorrSecp256k1,
-		wif:     "PmQhFGVRUjeRmGBPJCW2FCXFck1EoDBF6hks6auNJVQ26M4h73W9W",
+		wif:     "PvS4i9LQMR2K9hkC9w86im92KvWVb8hnSdhs3icW5SazWXFksnvL8",
 		pubKey:  pub1,
 	}, {
 		name:    "testnet schnorr secp256k1 priv2",
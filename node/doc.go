@@ -51,6 +51,13 @@ Application Options:
 	                             verification cache (default: 100000)
 	    --utxocachemaxsize=      The maximum size in MiB of the utxo cache
 	                             (default: 150, minimum: 25, maximum: 32768)
+	    --prune=                 Reduce storage requirements by removing the
+	                             data for old blocks to keep the total size of
+	                             the block data under the target in MiB (0 to
+	                             disable, minimum: 1024).  The data for the
+	                             recent blocks required by the stake validation
+	                             rules is always retained.  Incompatible with
+	                             --txindex and --addrindex
 	    --norpc                  Disable built-in RPC server -- NOTE: The RPC
 	                             server is disabled by default if no
 	                             rpcuser/rpcpass or rpclimituser/rpclimitpass is
@@ -82,6 +89,10 @@ Application Options:
 	                             (default: 25)
 	    --rpcmaxconcurrentreqs=  Max number of concurrent RPC requests that may
 	                             be processed concurrently (default: 20)
+	    --rest                   Enable the unauthenticated REST interface for
+	                             querying public chain data on the RPC listeners
+	    --restratelimit=         Max number of REST requests per second allowed
+	                             for each client IP address (default: 10)
 	    --proxy=                 Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)
 	    --proxyuser=             Username for proxy server
 	    --proxypass=             Password for proxy server
@@ -100,7 +111,7 @@ Application Options:
 	                             without also specifying listen interfaces via
 	                             --listen
 	    --listen=                Add an interface/port to listen for connections
-	                             (default all interfaces port: 9108, testnet:
+	                             (default all interfaces port: 9508, testnet:
 	                             19108)
 	    --maxsameip=             Max number of connections with the same IP -- 0
 	                             to disable (default: 5)
@@ -147,6 +158,12 @@ Application Options:
 	                             version of the software
 	    --maxorphantx=           Max number of orphan transactions to keep in
 	                             memory (default: 100)
+	    --maxmempool=            Max size of the transaction memory pool in
+	                             megabytes; the transactions paying the lowest
+	                             fees are evicted once it is exceeded (0 to
+	                             disable the limit) (default: 300)
+	    --nopersistmempool       Do not save the transaction memory pool to disk
+	                             on shutdown and reload it on startup
 	    --blocksonly             Do not accept transactions from remote peers
 	    --acceptnonstd           Accept and relay non-standard transactions to
 	                             the network regardless of the default settings
@@ -180,15 +197,40 @@ Application Options:
 	                             automatically enabled when the simnet option is
 	                             set.  Don't do this unless you know what you're
 	                             doing
+	    --dagdir=                Directory to store generated KawPoW light caches
+	                             and datasets so they are reused across restarts
+	                             (default: dag directory in the network data
+	                             directory)
+	    --dagsinmem=             Number of KawPoW datasets to keep in memory
+	                             (default: 1)
+	    --stratumlisten=         Add an interface/port to listen for KawPoW
+	                             stratum mining connections (default port:
+	                             3333).  At least one mining address is required
+	                             if this option is set
+	    --stratumdiff=           Initial share difficulty for stratum mining
+	                             clients (default: 1)
 	    --txindex                Maintain a full hash-based transaction index
 	                             which makes all transactions available via the
 	                             getrawtransaction RPC
 	    --droptxindex            Deletes the hash-based transaction index from
-	                             the database on start up and then exits
+	                             the database on start up and then exits.  This
+	                             will also delete the address index since it
+	                             relies on the transaction index
+	    --addrindex              Maintain a full address-based transaction index
+	                             which makes the searchrawtransactions,
+	                             getaddresstxids, getaddressbalance, and
+	                             getaddressutxos RPCs available.  Implies
+	                             --txindex
+	    --dropaddrindex          Deletes the address-based transaction index
+	                             from the database on start up and then exits
 	    --noexistsaddrindex      Disable the exists address index, which tracks
 	                             whether or not an address has even been used
 	    --dropexistsaddrindex    Deletes the exists address index from the
 	                             database on start up and then exits
+	    --metricslisten=         Add an interface/port to serve Prometheus
+	                             metrics over HTTP at /metrics (default port:
+	                             9110).  The metrics are not authenticated, so
+	                             only listen on trusted interfaces
 	    --piperx=                File descriptor of read end pipe to enable
 	                             parent -> child process communication
 	    --pipetx=                File descriptor of write end pipe to enable
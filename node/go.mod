@@ -6,6 +6,7 @@ toolchain go1.24.4
 
 require (
 	github.com/davecgh/go-spew v1.1.1
+	github.com/dchest/siphash v1.2.3
 	github.com/btcsuite/btcutil v1.0.2
 	github.com/kdsmith18542/vigil/addrmgr/v3 v3.0.0
 	github.com/kdsmith18542/vigil/bech32 v1.1.4
@@ -26,11 +27,13 @@ require (
 	github.com/kdsmith18542/vigil/dcrjson/v4 v4.1.0
 	github.com/kdsmith18542/vigil/dcrutil/v4 v4.0.2
 	github.com/kdsmith18542/vigil/gcs/v4 v4.1.0
+	github.com/kdsmith18542/vigil/kawpow v0.0.0-00010101000000-000000000000
 	github.com/kdsmith18542/vigil/math/uint256 v1.0.2
 	github.com/kdsmith18542/vigil/mixing v0.3.0
 	github.com/kdsmith18542/vigil/peer/v3 v3.1.1
 	github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4 v4.2.0
 	github.com/kdsmith18542/vigil/rpcclient/v8 v8.0.1
+	github.com/kdsmith18542/vigil/stratum v0.0.0-00010101000000-000000000000
 	github.com/kdsmith18542/vigil/txscript/v4 v4.1.1
 	github.com/kdsmith18542/vigil/wire v1.7.0
 	github.com/kdsmith18542/vigil/dcrtest/vgldtest v1.0.1-0.20240404170936-a2529e936df1
@@ -53,7 +56,6 @@ require (
 	vigil.network/vgl/cspp/v2 v2.4.0 // indirect
 	github.com/agl/ed25519 v0.0.0-20170116200512-5312a6153412 // indirect
 	github.com/companyzero/sntrup4591761 v0.0.0-20220309191932-9e0f3af2f07a // indirect
-	github.com/dchest/siphash v1.2.3 // indirect
 	github.com/kdsmith18542/vigil/chaincfg v1.5.1 // indirect
 	github.com/kdsmith18542/vigil/chaincfg/v2 v2.0.2 // indirect
 	github.com/kdsmith18542/vigil/dcrec/edwards v1.0.0 // indirect
@@ -62,7 +64,6 @@ require (
 	github.com/kdsmith18542/vigil/dcrutil v1.4.1 // indirect
 	github.com/kdsmith18542/vigil/dcrutil/v2 v2.0.0 // indirect
 	github.com/kdsmith18542/vigil/hdkeychain/v3 v3.1.2 // indirect
-	github.com/kdsmith18542/vigil/kawpow v0.0.0-00010101000000-000000000000 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
 	github.com/klauspost/cpuid/v2 v2.0.9 // indirect
 	golang.org/x/crypto v0.39.0 // indirect
@@ -98,6 +99,7 @@ replace (
 	github.com/kdsmith18542/vigil/peer/v3 => ./peer
 	github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4 => ./rpc/jsonrpc/types
 	github.com/kdsmith18542/vigil/rpcclient/v8 => ./rpcclient
+	github.com/kdsmith18542/vigil/stratum => ./stratum
 	github.com/kdsmith18542/vigil/txscript/v4 => ./txscript
 	github.com/kdsmith18542/vigil/wire => ./wire
 
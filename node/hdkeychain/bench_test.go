@@ -11,8 +11,8 @@ import (
 
 // bip0032MasterPriv1 is the master private extended key from the first set of
 // test vectors in BIP0032.
-const bip0032MasterPriv1 = "dprv3hCznBesA6jBtmoyVFPfyMSZ1qYZ3WdjdebquvkEfmRfx" +
-	"C9VFEFi2YDaJqHnx7uGe75eGSa3Mn3oHK11hBW7KZUrPxwbCPBmuCi1nwm182s"
+const bip0032MasterPriv1 = "vprvVYh6FZcJRmGQNvkyhZr1CbeMUchmT6T3FpDbn4b2GZCLz" +
+	"FaT6uZqrzmR5Mwttja9NrFQpQfLsZRT7Hxd3BX2TD115XsN4ZzGcC5jr1HiM4VN"
 
 // BenchmarkDeriveHardened benchmarks how long it takes to derive a hardened
 // child from a master private extended key.
@@ -82,8 +82,8 @@ Extended keys are serialized and deserialized with the String and
 NewKeyFromString functions.  The serialized key is a Base58-encoded string which
 looks like the following:
 
-	public key:   dpubZCGVaKZBiMo7pMgLaZm1qmchjWenTeVcUdFQkTNsFGFEA6xs4EW8PKiqYqP7HBAitt9Hw16VQkQ1tjsZQSHNWFc6bEK6bLqrbco24FzBTY4
-	private key:  dprv3kUQDBztdyjKuwnaL3hfKYpT7W6X2huYH5d61YSWFBebSYwEBHAXJkCpQ7rvMAxPzKqxVCGLvBqWvGxXjAyMJsV1XwKkfnQCM9KctC8k8bk
+	public key:   vpubVdyeZUbCjzm8paXB9fzrQ96jBD4AoxrkvJkTheAQeiQiwTrMBdyfNA3yZRhC16buq8T5PgxLbKYA821VcsUUhEDwkVU63uNeoEHc6qGAd5sH
+	private key:  vprvVYkMezceTF9QWwvxJQeKBwqjNiNKR5eK4Ted2ACiY8cZujwEqqckgGyQKTEU28dCWCUB8dR3B7qEpvvaZDWVhCK1EfqkE3PV2e2MT6V4qqSc
 
 # Network
 
@@ -60,8 +60,8 @@ func Example_defaultWalletLayout() {
 	// and be decrypted or generated as the NewMaster example shows, but
 	// for the purposes of this example, the private extended key for the
 	// master node is being hard coded here.
-	master := "dprv3hCznBesA6jBushjx7y9NrfheE4ZshnaKYtsoLXefmLPzrXgEiXkd" +
-		"RMD6UngnmBYZzgNhdEd4K3PidxcaCiR6HC9hmpj8FcrP4Cv7zBwELA"
+	master := "vprvVYh6FZcJRmGQNwrsU2iag19adF6HTveC6W7towzogZCFiJEqHu47u" +
+		"beYi9bPnaDRen91Yqr1TFxShjHae4YEkyiiNqgFCVrhgfwEkLHb6gz5"
 
 	// Start by getting an extended key instance for the master node.
 	// This gives the path:
@@ -148,8 +148,8 @@ func Example_defaultWalletLayout() {
 	fmt.Println("Account 0 Internal Address 0:", acct0IntAddr)
 
 	// Output:
-	// Account 0 External Address 10: DshMmJ3bfvMDdk1mkXRD3x5xDuPwSxoYGfi
-	// Account 0 Internal Address 0: DsoTyktAyEDkYpgKSex6zx5rrkFDi2gAsHr
+	// Account 0 External Address 10: VsakhrSx3CMn5dXHWxsHzBDcPGe4gSQwGnC
+	// Account 0 Internal Address 0: VsgrvKHXLWEJziBqD6QBwBDX27VLwSKc2ez
 }
 
 // This example demonstrates the audits use case in BIP0032.
@@ -169,8 +169,8 @@ func Example_audits() {
 	// and be decrypted or generated as the NewMaster example shows, but
 	// for the purposes of this example, the private extended key for the
 	// master node is being hard coded here.
-	master := "dprv3hCznBesA6jBushjx7y9NrfheE4ZshnaKYtsoLXefmLPzrXgEiXkd" +
-		"RMD6UngnmBYZzgNhdEd4K3PidxcaCiR6HC9hmpj8FcrP4Cv7zBwELA"
+	master := "vprvVYh6FZcJRmGQNwrsU2iag19adF6HTveC6W7towzogZCFiJEqHu47u" +
+		"beYi9bPnaDRen91Yqr1TFxShjHae4YEkyiiNqgFCVrhgfwEkLHb6gz5"
 
 	// Start by getting an extended key instance for the master node.
 	// This gives the path:
@@ -191,5 +191,5 @@ func Example_audits() {
 	fmt.Println("Audit key N(m/*):", masterPubKey)
 
 	// Output:
-	// Audit key N(m/*): dpubZ9169KDAEUnypHbWCe2Vu5TxGEcqJeNeX6XCYFU1fqw2iQZK7fsMhzsEFArbLmyUdprUw9aXHneUNd92bjc31TqC6sUduMY6PK2z4JXDS8j
+	// Audit key N(m/*): vpubVdvPA3ariWt8gaT6KJ57tCQaRjn8rordxMDjVRxVo8zQk29wdhR2bUj7x82fVACiasPnah6pdCaQaVtm64moMjSAr17FbDPM31yr4qN8hXRd
 }
@@ -46,8 +46,8 @@ func (p *mockNetParams) HDPubKeyVersion() [4]byte {
 // written.
 func mockMainNetParams() *mockNetParams {
 	return &mockNetParams{
-		privKeyID: [4]byte{0x02, 0xfd, 0xa4, 0xe8}, // starts with dprv
-		pubKeyID:  [4]byte{0x02, 0xfd, 0xa9, 0x26}, // starts with dpub
+		privKeyID: [4]byte{0xfd, 0x8b, 0x9b, 0x26}, // starts with vprv
+		pubKeyID:  [4]byte{0xfd, 0x8c, 0x90, 0x65}, // starts with vpub
 	}
 }
 
@@ -91,48 +91,48 @@ func TestBIP0032Vectors(t *testing.T) {
 			name:     "test vector 1 chain m",
 			master:   testVec1MasterHex,
 			path:     []uint32{},
-			wantPub:  "dpubZ9169KDAEUnyoBhjjmT2VaEodr6pUTDoqCEAeqgbfr2JfkB88BbK77jbTYbcYXb2FVz7DKBdW4P618yd51MwF8DjKVopSbS7Lkgi6bowX5w",
-			wantPriv: "dprv3hCznBesA6jBtmoyVFPfyMSZ1qYZ3WdjdebquvkEfmRfxC9VFEFi2YDaJqHnx7uGe75eGSa3Mn3oHK11hBW7KZUrPxwbCPBmuCi1nwm182s",
+			wantPub:  "vpubVdvPA3ariWt8gZMCYqCYQnuMH7PcqyfV7fKSTYYiP8zW1yVZShvkYsqzKLQQWMxL8V4vCyGRjQr9C8QbgY3ZFy6ZPDjamkdF3yRVnsdL8Qyj",
+			wantPriv: "vprvVYh6FZcJRmGQNvkyhZr1CbeMUchmT6T3FpDbn4b2GZCLzFaT6uZqrzmR5Mwttja9NrFQpQfLsZRT7Hxd3BX2TD115XsN4ZzGcC5jr1HiM4VN",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 1 chain m/0H",
 			master:   testVec1MasterHex,
 			path:     []uint32{hkStart},
-			wantPub:  "dpubZCGVaKZBiMo7pMgLaZm1qmchjWenTeVcUdFQkTNsFGFEA6xs4EW8PKiqYqP7HBAitt9Hw16VQkQ1tjsZQSHNWFc6bEK6bLqrbco24FzBTY4",
-			wantPriv: "dprv3kUQDBztdyjKuwnaL3hfKYpT7W6X2huYH5d61YSWFBebSYwEBHAXJkCpQ7rvMAxPzKqxVCGLvBqWvGxXjAyMJsV1XwKkfnQCM9KctC8k8bk",
+			wantPub:  "vpubVdyeZUbCjzm8paXB9fzrQ96jBD4AoxrkvJkTheAQeiQiwTrMBdyfNA3yZRhC16buq8T5PgxLbKYA821VcsUUhEDwkVU63uNeoEHc6qGAd5sH",
+			wantPriv: "vprvVYkMezceTF9QWwvxJQeKBwqjNiNKR5eK4Ted2ACiY8cZujwEqqckgGyQKTEU28dCWCUB8dR3B7qEpvvaZDWVhCK1EfqkE3PV2e2MT6V4qqSc",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 1 chain m/0H/1",
 			master:   testVec1MasterHex,
 			path:     []uint32{hkStart, 1},
-			wantPub:  "dpubZEDyZgdnFBMHxqNhfCUwBfAg1UmXHiTmB5jKtzbAZhF8PTzy2PwAicNdkg1CmW6TARxQeUbgC7nAQenJts4YoG3KMiqcjsjgeMvwLc43w6C",
-			wantPriv: "dprv3nRtCZ5VAoHW4RUwQgRafSNRPUDFrmsgyY71A5eoZceVfuyL9SbZe2rcbwDW2UwpkEniE4urffgbypegscNchPajWzy9QS4cRxF8QYXsZtq",
+			wantPub:  "vpubVe1c3TxHLXagzizsWkdaKUzH9V2HYnvj51Cwcnhcx2qiqhDPHc96QVLdMdXp6avqZPztWQRqn6uYGXvQNMuFsXENyFxca3uYdH2k27av3Hqe",
+			wantPriv: "vprvVYnK8yyj3mxxh6QefVH37HjHLzLS9uiHDA76wJjvqT3ZoyJGwonBicG47f3pbowBvxP7tNHggsK5uzUGiMwtxaq6xeuPcn39SiqGxcpaEbwX",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 1 chain m/0H/1/2H",
 			master:   testVec1MasterHex,
 			path:     []uint32{hkStart, 1, hkStart + 2},
-			wantPub:  "dpubZGLz7gsJAWzUksvtw3opxx5eeLq5fRaUMDABA3bdUVfnGUk5fiS5Cc3kZGTjWtYr3jrEavQQnAF6jv2WCpZtFX4uFgifXqev6ED1TM9rTCB",
-			wantPriv: "dprv3pYtkZK168vgrU38gXkUSjHQ2LGpEUzQ9fXrR8fGUR59YviSnm6U82XjQYhpJEUPnVcC9bguJBQU5xVM4VFcDHu9BgScGPA6mQMH4bn5Cth",
+			wantPub:  "vpubVe3j41xWrSvLBX3Ri2UuDGHC87tM7AdqnBLNU3kdQwe9VaE8QFTbJyLJUS8GdLKHxHJnLLseWgx1CsBeZfrmCyVQZ9vVcqsTriu26EPxK76s",
+			wantPriv: "vprvVYpS9XyxZhJbstTCrm8N152CKdCViHRPvLEXnZnwJMqzTrK24T6gd6FjETfJv5giVzdwNHpTjVpon6c7NYpmx6jRNKas5dzEw4HP7Gryg1jj",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 1 chain m/0H/1/2H/2",
 			master:   testVec1MasterHex,
 			path:     []uint32{hkStart, 1, hkStart + 2, 2},
-			wantPub:  "dpubZHv6Cfp2XRSWHQXZBo1dLmVM421Zdkc4MePkyBXCLFttVkCmwZkxth4ZV9PzkFP3DtD5xcVq2CPSYpJMWMaoxu1ixz4GNZFVcE2xnHP6chJ",
-			wantPriv: "dprv3r7zqYFjT3NiNzdnwGxGpYh6S1TJCp1zA6mSEGaqLBJFnCB94cRMp7YYLR49aTZHZ7ya1CXwQJ6rodKeU9NgQTxkPSK7pzgZRgjYkQ7rgJh",
+			wantPub:  "vpubVe5JA6wTaopnD3a2NHE71e6bpXZXb8xsNBmc3rtYyoQNboVb6XJvCfRKHN1CtZg89TT9BiZjvvz9Yg5vQyPn8gsMNsDqDgb4SEtr3ZN4LN2U",
+			wantPriv: "vprvVYr1FcxuJ4D3uQyoX1sZoSqc22sgCFkRWLfmNNvrsDcDa5aUkix1WnLk3PXfFMuoPmGJk9RJmbwWApGwfxUu2HuUyXLjbCbmPiZmNxkhJTrB",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 1 chain m/0H/1/2H/2/1000000000",
 			master:   testVec1MasterHex,
 			path:     []uint32{hkStart, 1, hkStart + 2, 2, 1000000000},
-			wantPub:  "dpubZL6d9amjfRy1zeoZM2zHDU7uoMvwPqtxHRQAiJjeEtQQWjP3retQV1qKJyzUd6ZJNgbJGXjtc5pdoBcTTYTLoxQzvV9JJCzCjB2eCWpRf8T",
-			wantPriv: "dprv3tJXnTDSb3uE6Euo6WvvhFKfBMNfxuJt5smqyPoHEoomoBMQyhYoQSKJAHWtWxmuqdUVb8q9J2NaTkF6rYm6XDrSotkJ55bM21fffa7VV97",
+			wantPub:  "vpubVe7Uh3rRHwqJikpJNSU5fWoEPGuSxu4AG7YcTc1mRi2t7pUmNSQ3eFk63BqoNSXJQcFXQ2UyzWsajvTEWvaefXvkepivFcEo9MqqiyYoF4aM",
+			wantPriv: "vprvVYtBnZss1CDaR8E5XB7YTKYEanDba1qiQGSmn845K8Ej66Zf2e38xNfWoDQ7zJR223mofjMbyVfmtUPs8LtHSQfNfwoAmSggBJthVstCUobW",
 			net:      mainNetParams,
 		},
 
@@ -141,48 +141,48 @@ func TestBIP0032Vectors(t *testing.T) {
 			name:     "test vector 2 chain m",
 			master:   testVec2MasterHex,
 			path:     []uint32{},
-			wantPub:  "dpubZ9169KDAEUnynoD4qvXJwmxZt3FFA5UdWn1twnRReE9AxjCKJLNFY1uBoegbFmwzA4Du7yqnu8tLivhrCCH6P3DgBS1HH5vmf8MpNXvvYT9",
-			wantPriv: "dprv3hCznBesA6jBtPKJbQTxRZAKG2gyj8tZKEPaCsV4e9YYFBAgRP2eTSPAeu4r8dTMt9q51j2Vdt5zNqj7jbtovvocrP1qLj6WUTLF9xYQt4y",
+			wantPub:  "vpubVdvPA3ariWt8gYxhswMchF753MamGfHjwLuEBqVTD7NctGUadt5XVJk9ugWVV5Ch6Pd9zsw5toveSrCKufEUR71ZL5fnEb7jiHoAu9X9C6pB",
+			wantPriv: "vprvVYh6FZcJRmGQNvNV2g15V3r5Ertusn5J5VoPWMXm6XaTrYZUJ5icoRfafi1fwv5hU6JAF9woKqXVJPVM9DwR9pNKqzHSJiLBLmLN5NGYErop",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 2 chain m/0",
 			master:   testVec2MasterHex,
 			path:     []uint32{0},
-			wantPub:  "dpubZBA4RCkCybJFaNbqPuBiyfXY1rvmG1XTdCy1AY1U96dxkFqWc2i5KREMh7NYPpy7ZPMhdpFMAesex3JdFDfX4J5FEW3HjSacqEYPfwb9Cj7",
-			wantPriv: "dprv3jMy45BuuDETfxi59P8NTSjHPrNVq4wPRfLgRd57923L2hosj5NUEqiLYQ4i7fJtUpiXZLr2wUeToJY2Tm5sCpAJdajEHDmieVJiPQNXwu9",
+			wantPub:  "vpubVdxY8KUPmFzdxLY6eVLH7Gze1VQSnmDnmTLBJ4F3FcF7g41DqBmsK69V5ZyBSDFiDnxHoPmVT5Sdm5JvgiFrqnGQu8jpF3UPZTuMUSwNUQBT",
+			wantPriv: "vprvVYjFDqVqUWNuehwsoDyju5jeCzibPt1LucELcaHM92SxeL67VPQxdD4uqbWfou7Yzgy3hhZcs983moxA3x6cD6FgXmV9heprYwNLYbihYSb9",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 2 chain m/0/2147483647H",
 			master:   testVec2MasterHex,
 			path:     []uint32{0, hkStart + 2147483647},
-			wantPub:  "dpubZDUNkZEcCRCZEizDGL9sAQbZRKSnaxQLeqN9zpueeqCyq2VY7NUGMXASacsK96S8XzNjq3YgFgwLtj8MJBToW6To9U5zxuazEyh89bjR1xA",
-			wantPriv: "dprv3mgHPRgK838mLK6T1p6WeBoJoJtXA1pGTHjqFuyHekcM7UTuER8fGweRRsoLqSuHa98uskVPnJnfWZEBUC1AVmXnSCPDvUFKydXNnnPHTuQ",
+			wantPub:  "vpubVdzrSeptAUpYFztV2MmFFTji2trxp6AfeUxaStXwS7ygh8msrh7dW8FRATUgCxXBEmZJqaznnAUhT1zkQmDf8E4oT3hrxGwPvseWCvdhcBWb",
+			wantPriv: "vprvVYmZYArKsjCoxNJGB6Qi3GUiEQB7RCxDndrjmQaFKYBXfQrmWtkipFAqvUzQScu9PnHU61yGDyxByXCrCxXXWPD41a6ohJ5LAGWZD13uwqPF",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 2 chain m/0/2147483647H/1",
 			master:   testVec2MasterHex,
 			path:     []uint32{0, hkStart + 2147483647, 1},
-			wantPub:  "dpubZF3wJh7SfggGg74QZW3EE9ei8uQSJEFgd62uyuK5iMgQzUNjpSnprgTpYz3d6Q3fXXtEEXQqpzWcP4LUVuXFsgA8JKt1Hot5kyUk4pPRhDz",
-			wantPriv: "dprv3oFqwZZ9bJcUmhAeJyyshvrTWtrAsHfcRYQbEzNiiH5nGvM6wVTDn6woQEz92b2EHTYZBtLi82jKEnxSouA3cVaW8YWBsw5c3f4mwAhA3d2",
+			wantPub:  "vpubVe2S1Cxkzx61ySGZDew8cXUmBcSvToSWzTDFCscLsBWA8JDm4QBx4dQiYRqrWupnmm6pKzUewjnGiWKxXxwiabeVnCZexbqh2PeHpqrMYC52",
+			wantPriv: "vprvVYo96izCiCUHfogLNPabQLDmP7m54vE58c7QXPeekbi16aJeibq3NkL9JTMbEp3GLVbsjL77YKg8dFSaUJEgPVw6jGSvfFYASLY6c9YwBT4Z",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 2 chain m/0/2147483647H/1/2147483646H",
 			master:   testVec2MasterHex,
 			path:     []uint32{0, hkStart + 2147483647, 1, hkStart + 2147483646},
-			wantPub:  "dpubZH38NEg1CW19dGZs8NdaT4hDkz7wXPstio1mGpHSAXHpSGW3UnTrn25ERT1Mp8ae5GMoQHMbgQiPrChMXQMdx3UqS8YqFkT1pqait8fY92u",
-			wantPriv: "dprv3qF3177i87wMirg6sraDvqty8yZg6THpXFPSXuM5AShBiiUQbq8FhSZDGkYmBNR3RKfBrxzkKDBpsRFJfTnQfLsvpPPqRnakat6hHQA43X9",
+			wantPub:  "vpubVe4RCGWKZUuLrPS4gDoixkPohEXdy2c9CYvE4AXKDdfmXk1tN4Xd6YkKxJJpFdZKkJqHuAEbhbCUVyUKQzSYxg1pVLNKnZnFxTWPofANEEet",
+			wantPriv: "vprvVYq8HnXmGjHcYkqqpxTBkZ8otjqna9PhLhpPNgZd73scW26n2GAiQffkiKs9rxpf9dTzN1BmaWrb8t4sL9oJkYnQ9xHpJoPfasm8XVh4dFpw",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 2 chain m/0/2147483647H/1/2147483646H/2",
 			master:   testVec2MasterHex,
 			path:     []uint32{0, hkStart + 2147483647, 1, hkStart + 2147483646, 2},
-			wantPub:  "dpubZJoBFoQJ35zvEBgsfhJBssnAp8TY5gvruzQFLmyxcqRb7enVtGfSkLo2CkAZJMpa6T2fx6fUtvTgXtUvSVgAZ56bEwGxQsToeZfFV8VadE1",
-			wantPriv: "dprv3s15tfqzxhw8Kmo7RBEqMeyvC7uGekLniSmvbs3bckpxQ6ks1KKqfmH144Jgh3PLxkyZRcS367kp7DrtUmnG16NpnsoNhxSXRgKbJJ7MUQR",
+			wantPub:  "vpubVe6BFA53rKVLczMBgm8PaBCteHfyZauCAk7cYEV1k5yuJRQApU1pgX53k5byT7nZgL1xmi3uaoiDnfA6yuXsVH3SF9B3uiuGkHEULGDKyrr2",
+			wantPriv: "vprvVYrtLg6VZZscKMkxqVmrMywtqnz8AhgkJu1mrkXKdWBkGhV4UfeuzdzUW7AunUVdTAuJjZqCsHmA87sUuy7JbtXu3vnDr5ZXMiZMRWbop5MV",
 			net:      mainNetParams,
 		},
 
@@ -191,24 +191,24 @@ func TestBIP0032Vectors(t *testing.T) {
 			name:     "test vector 3 chain m",
 			master:   testVec3MasterHex,
 			path:     []uint32{},
-			wantPub:  "dpubZ9169KDAEUnynpjjLH5vXBfmaBym5MB4HsyZ4ZbsXtJh8DyFVRP8ucKKgNTNSyUZZC93dnJX5eSZxeURPmzHpnBDBieisUbaMJteHNHJpPK",
-			wantPriv: "dprv3hCznBesA6jBtQqy5m2ZzxsWxBRVeQaz6LMEKefWXoi4QfwccU3Xq2oJXgCZvgKQtRXKdgvkyt35PMhyavDyy95dTc1xjbMGd7vJ61LQan8",
+			wantPub:  "vpubVdvPA3ariWt8gYzEYRiBJpWnF3jVnaZSN81BqxGdf12nQRyMa5AYNgLa3ZEGGGQDfnm59PjYczSCg5v6UrpBcYkWs5xRgBWQWyyhj4M7zZJp",
+			wantPriv: "vprvVYh6FZcJRmGQNvQ1hAMe6dFnSZ3ePhLzWGuMAUJwYREdNi4FEGodgoFzoanofi8ZX6ZrVmuhbBXSPQ1L15FkKrabrbWSS7CS6uzx8JNUMFXq",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 3 chain m/0H",
 			master:   testVec3MasterHex,
 			path:     []uint32{hkStart},
-			wantPub:  "dpubZB4aNJNxjimmDnmC3ZCo7zmSW9ue8i6fRMwpwyHzi15X7j2KHXWSDiPr4ymhbQWg6D3HAjSnqSG3PHDaLVAkUCuRU5ohX9y9z6KL768vYm6",
-			wantPriv: "dprv3jGV1ApffLhyKNsRo39SbmyBt9MNhmWbDpKWD4MdhvUtQAzgQaAq98spvFaUmYyNJSPGLJ9JzgVDNN9bQP62tUpLwVigWZMjXmojbERDmhk",
+			wantPub:  "vpubVdxSeGa2X287TyxG18zJBRKsuyhRfdvMyFVA7qgKnB9ZERUQdsGffzSeZwqabQqFnKmyNvggtkE29WYqdoXN5CBF5NKaeqBn6cm8Qt8ZQ859",
+			wantPriv: "vprvVYj9jnbUEGWPAMN39sdkyE4t7V1aGkhv7QPKSMidfbMQChZJJ4ukz7N5KyNBaZ1DUWaiSUWv9CKtXP1mcticNmvLa5Q99tASZpeqZoXMcVNx",
 			net:      mainNetParams,
 		},
 		{
 			name:     "test vector 3 chain m/0H/0H",
 			master:   testVec3MasterHex,
 			path:     []uint32{hkStart, hkStart},
-			wantPub:  "dpubZESpio8DE1w5E9h5cBwuER5dmfcqZzh19UTstEDJMTfTAbWPJQ8mCFKeqyVgTFL5n4VmrUgbidcH1FmJaPLEWcdyizsdHocfyJTn8Zw8tkB",
-			wantPriv: "dprv3nejMfZv9dsHKjoKMftYiCHP9f4a946vwvqZ9KGwMP4pT3UkRSoA7fodhGCqPVBqgC2wijajQYW9ZQKNMdZ7Ac2L5i7xqHcq4tPzcxzAxhf",
+			wantPub:  "vpubVe1ptd4mmWRGmzKBthd3HXkC7FD8s5CxJybgAmwF5pc9AULtht9HzxyaNiqJaGg5C1dRscRvhdRNP8XPN3RXZEayddEeabqRcbyGruc15q5h",
+			wantPriv: "vprvVYnXz96DUkoYUMiy3SGW5LVCJkXHUBzWT8VqVHyYyEoz8kRnN5nPK5u18kNowAwRwtLN7rxMZcBuTa3wPqy5T43YZDcYSCthfMmRpqKN2PLD",
 			net:      mainNetParams,
 		},
 
@@ -321,8 +321,8 @@ tests:
 // other private keys works as intended.
 func TestPrivateDerivation(t *testing.T) {
 	// The private extended keys for test vectors in [BIP32].
-	testVec1MasterPrivKey := "dprv3hCznBesA6jBucms1ZhyGeFfvJfBSwfs7ZFrxS8tdYzbjDZe2UwSaL7EbYo1qa88DmtyyG5cL9tdGxHkD89JmeZTbz5sVYU4Dgtijiio4Sc"
-	testVec2MasterPrivKey := "dprv3hCznBesA6jBtPKJbQTxRZAKG2gyj8tZKEPaCsV4e9YYFBAgRP2eTSPAeu4r8dTMt9q51j2Vdt5zNqj7jbtovvocrP1qLj6WUTLF9xYQt4y"
+	testVec1MasterPrivKey := "vprvVYh6FZcJRmGQNwbwb6AKVtwAbXAt5Vt5PJ8Fo76QvWyuv2bsFgpXbYZJjefQ7d2NERvEA7UrSXoHwHbumhTfef65gjtWLs9YtWZvYx7q4BL9"
+	testVec2MasterPrivKey := "vprvVYh6FZcJRmGQNvNV2g15V3r5Ertusn5J5VoPWMXm6XaTrYZUJ5icoRfafi1fwv5hU6JAF9woKqXVJPVM9DwR9pNKqzHSJiLBLmLN5NGYErop"
 
 	tests := []struct {
 		name     string
@@ -335,37 +335,37 @@ func TestPrivateDerivation(t *testing.T) {
 			name:     "test vector 1 chain m",
 			master:   testVec1MasterPrivKey,
 			path:     []uint32{},
-			wantPriv: "dprv3hCznBesA6jBucms1ZhyGeFfvJfBSwfs7ZFrxS8tdYzbjDZe2UwSaL7EbYo1qa88DmtyyG5cL9tdGxHkD89JmeZTbz5sVYU4Dgtijiio4Sc",
+			wantPriv: "vprvVYh6FZcJRmGQNwbwb6AKVtwAbXAt5Vt5PJ8Fo76QvWyuv2bsFgpXbYZJjefQ7d2NERvEA7UrSXoHwHbumhTfef65gjtWLs9YtWZvYx7q4BL9",
 		},
 		{
 			name:     "test vector 1 chain m/0",
 			master:   testVec1MasterPrivKey,
 			path:     []uint32{0},
-			wantPriv: "dprv3jFfEhxvVxy6NJWopujhfg7syQL71xCRgNoGUpQTtjTpCwzigwtCwssQGbRQsby7PBs1Yp8Wu7isu396qeNof13EZuxbCTJVF1xkoFAQHWj",
+			wantPriv: "vprvVYj8v28cV78eHQHgXuWMEHy2oaGZ14tbwrwoCdUgVnAP8WLJLMHUMv74uKi2Wf4DDbLCBh2uM6m8Bugm8Kyu9YSZThpP4a4PKXtzb1bjBoJi",
 		},
 		{
 			name:     "test vector 1 chain m/0/1",
 			master:   testVec1MasterPrivKey,
 			path:     []uint32{0, 1},
-			wantPriv: "dprv3mWLns1v1fdLhxStaJHh3BqxmTi14RHHeWdNU6oU8sSkTDmAr54yK6La2APy3rAZr9ZJAdm5asTJaqBZ3vBYVSPHqyL8kbcCp5jgqfxBs4x",
+			wantPriv: "vprvVYmPbaHfUcqJXjwccetuDfUktNKvu7Mgoq5dJcm5W2JN4kc4nWQf8HKY55H14qJQg4HtUJrXunWrcbUoaYFhtNsuWyskc8Ch36xmX3vgMi1H",
 		},
 		{
 			name:     "test vector 1 chain m/0/1/2",
 			master:   testVec1MasterPrivKey,
 			path:     []uint32{0, 1, 2},
-			wantPriv: "dprv3oDxSziXR1rQVWwWWBRKgCQU3vN6dnR3ekzHzvZRdgfVvrYSE35saJh8UdfSxCgtMn7pnbeMXWbyBbwxoncC9LMrnuH1AoJSB259c4XgmnN",
+			wantPriv: "vprvVYo7DERN62BXbXW7Ean2rJVKPenazgipZqKzE9aqTX7apEEr3tNg2YXtdXkGYjevzZvSzvpRBjA1HCFZzJ88Y2mt5vohUYQPGTu6ypNQecxp",
 		},
 		{
 			name:     "test vector 1 chain m/0/1/2/2",
 			master:   testVec1MasterPrivKey,
 			path:     []uint32{0, 1, 2, 2},
-			wantPriv: "dprv3rYHNih25i8MqeRjhFq8mLnK4a3J63a9zkYTCFJd8kaJuNc5aDAAuG1XopkU7h93HvfbNvQaWdQLtwFmUEbDN3GCZ2Mxw6tq5ZSh8d1Chyw",
+			wantPriv: "vprvVYrRYA9LagsoYsdbTmrSfPdhEfSGC8yygBKYPLuaf2BVdCkuhEYkKsVD2rwMZu9P9W4zmX9BQiGoeuasnxa7ZFUnRgvnSJhyfNSUXLxqrVDn",
 		},
 		{
 			name:     "test vector 1 chain m/0/1/2/2/1000000000",
 			master:   testVec1MasterPrivKey,
 			path:     []uint32{0, 1, 2, 2, 1000000000},
-			wantPriv: "dprv3tKkzgLFKaX2VcQTir9JeNHWxikiKokSJtdyj7sYoiDkU3np2rc3DGYPRVmDhb2FFaAk98fnqRotQYTVCRaoyAZiHaoyNoPCFeYA9pEshBT",
+			wantPriv: "vprvVYtD1n6yovkCDXbaBoSkqGfCSZaycNk9xVTdusn9ah994mS6RhCCCBVjtUcNKV3GMTiVvHMSd35DCRC5Wgm79rc5wRVESkQU2YXZzN9zhUdC",
 		},
 
 		// Test vector 2
@@ -373,46 +373,46 @@ func TestPrivateDerivation(t *testing.T) {
 			name:     "test vector 2 chain m",
 			master:   testVec2MasterPrivKey,
 			path:     []uint32{},
-			wantPriv: "dprv3hCznBesA6jBtPKJbQTxRZAKG2gyj8tZKEPaCsV4e9YYFBAgRP2eTSPAeu4r8dTMt9q51j2Vdt5zNqj7jbtovvocrP1qLj6WUTLF9xYQt4y",
+			wantPriv: "vprvVYh6FZcJRmGQNvNV2g15V3r5Ertusn5J5VoPWMXm6XaTrYZUJ5icoRfafi1fwv5hU6JAF9woKqXVJPVM9DwR9pNKqzHSJiLBLmLN5NGYErop",
 		},
 		{
 			name:     "test vector 2 chain m/0",
 			master:   testVec2MasterPrivKey,
 			path:     []uint32{0},
-			wantPriv: "dprv3jMy45BuuDETfxi59P8NTSjHPrNVq4wPRfLgRd57923L2hosj5NUEqiLYQ4i7fJtUpiXZLr2wUeToJY2Tm5sCpAJdajEHDmieVJiPQNXwu9",
+			wantPriv: "vprvVYjFDqVqUWNuehwsoDyju5jeCzibPt1LucELcaHM92SxeL67VPQxdD4uqbWfou7Yzgy3hhZcs983moxA3x6cD6FgXmV9heprYwNLYbihYSb9",
 		},
 		{
 			name:     "test vector 2 chain m/0/2147483647",
 			master:   testVec2MasterPrivKey,
 			path:     []uint32{0, 2147483647},
-			wantPriv: "dprv3mgHPRgAnNboAb9edL4RPscKYrNLG77BhPvFe3eiTGPSiigDeXct3WeiZ2QqRrm9TiseBuYWGEG79xkBzazpBGfym1vRXjcEo5KUi4rbhZ1",
+			wantPriv: "vprvVYmZYArKjPYGzCaKNhvfx2AXF9ibEK3WhsxvBnhvkLhJm26yqJsD31jrDc91wDK1FfsCpL8KLTsfRAcNDUvXA4iCCtvLtuLh55xMJvLuX1R7",
 		},
 		{
 			name:     "test vector 2 chain m/0/2147483647/1",
 			master:   testVec2MasterPrivKey,
 			path:     []uint32{0, 2147483647, 1},
-			wantPriv: "dprv3onqUUAjN1xQUAW1BzwiBa5wisDCE8hX8YcAEgVe1DPvbgHVLauDt2NsZPQX6tJs6ozcQSU9GdsffhueTbxTxFMPEMPxM2iHiooMz2oQHWS",
+			wantPriv: "vprvVYog6FtpHyBdbW9fjGbZEorzsKjS6H573K7c6PLmfteKEu4b6zvVNrFaNcW1ctLYyJxKnYfEyUHGygMXfwwUoqgscNFpRido81gqCCMPdbMZ",
 		},
 		{
 			name:     "test vector 2 chain m/0/2147483647/1/2147483646",
 			master:   testVec2MasterPrivKey,
 			path:     []uint32{0, 2147483647, 1, 2147483646},
-			wantPriv: "dprv3r8NTJgGzAjY5cU7sLo5rhT8o2wdco2iqjks6nJoiDACTucrPMcsciccv9skwGMX69uRa8EaZofskV7YyzBDbVi6v4RXbJ4DyeZ6JpUgdUi",
+			wantPriv: "vprvVYr1dEjKqbLQj7bdqwwQcUzN4PuAXfjSF2JkoFSaqbe5WmHvU3hD2awp7yGUriibdJJEbiM1QmT5Bm8jaUKhZUwEL3xqzxu94GXavXDTFH43",
 		},
 		{
 			name:     "test vector 2 chain m/0/2147483647/1/2147483646/2",
 			master:   testVec2MasterPrivKey,
 			path:     []uint32{0, 2147483647, 1, 2147483646, 2},
-			wantPriv: "dprv3sp4xvFP9mL9UUEddSZUxrtNnhe5UcHs5wrpxdZVEFCXoT4EpYeHZJjCDhvVEQFK2KfSHXFmew6MeBuvtrJfQv1BnkiSV7xxUji66uvWasp",
+			wantPriv: "vprvVYshKkLtwkw1LWTQMi3B1b9oJPZryXYhPGWrm7HqX7g7r6qMrUtESXXvhGpXb1rVRETzcRk2braVfeqXxPBq1JMXQvf8urj3nmcjvKGr4f89",
 		},
 
 		// Custom tests to trigger specific conditions.
 		{
 			// Seed 000000000000000000000000000000da.
 			name:     "Derived privkey with zero high byte m/0",
-			master:   "dprv3jFfEhxvVxy6NJWopujhfg7syQL71xCRgNoGUpQTtjTpCwzigwtCwssQGbRQsby7PBs1Yp8Wu7isu396qeNof13EZuxbCTJVF1xkoFAQHWj",
+			master:   "vprvVYj8v28cV78eHQHgXuWMEHy2oaGZ14tbwrwoCdUgVnAP8WLJLMHUMv74uKi2Wf4DDbLCBh2uM6m8Bugm8Kyu9YSZThpP4a4PKXtzb1bjBoJi",
 			path:     []uint32{0},
-			wantPriv: "dprv3mWLns1v1fdLfeu5DKTA6NWQHLF6pFsPSwKCS6q4h4nkjm2DfuH5X2iDnW15jhHTGa3rzxSpvskuXugcbBcUUVWCETKKzjW7ja4V2jL4aw4",
+			wantPriv: "vprvVYmPbaHfUcqJXhe4oHv4gifRKtCTzsCGudWK8am76aVi539KqLEsEVFuiqccBX9XZUiP39BDf8XADYZJe5X8pMw2RNMjoNLax2T6KF7pFJbS",
 		},
 	}
 
@@ -450,8 +450,8 @@ tests:
 // other public keys works as intended.
 func TestPublicDerivation(t *testing.T) {
 	// The public extended keys for test vectors in [BIP32].
-	testVec1MasterPubKey := "dpubZF8BRmciAzYoTjXZ3bbRWLVCwUKtTquact3Tr6ye77Rgmw76VyqMb9TB9KpfrvUYEM5d1Au4fQzE2BbtxRjwzGsqnWHmtQP9UV1kxZaqvb6"
-	testVec2MasterPubKey := "dpubZF4LSCdF9YKZfNzTVYhz4RBxsjYXqms8AQnMBHXZ8GUKoRSigG7kQnKiJt5pzk93Q8FxcdVBEkQZruSXduGtWnkwXzGnjbSovQ97dCxqaXc"
+	testVec1MasterPubKey := "vpubVe2WFL3GGTPtWDu2N92goofbgR1quy4AtT1Fkjp1RaFuQ5gVR5izbMshu2BdZgMDeTv1im89AaCkL9TDxRTwGiFDVgk4jCSC679pqjdkcT79"
+	testVec2MasterPubKey := "vpubVe2SQLUGoRwfGRYVGayoNMkJSMH4ZLz8RzXze4zZLbQx37Aq3G1GzBWaSBjtipAt9dhC4NajH9YAfzB4b6wUDEm6bSE3k3dFkZ4xCQAXCqRz"
 
 	tests := []struct {
 		name    string
@@ -464,37 +464,37 @@ func TestPublicDerivation(t *testing.T) {
 			name:    "test vector 1 chain m",
 			master:  testVec1MasterPubKey,
 			path:    []uint32{},
-			wantPub: "dpubZF8BRmciAzYoTjXZ3bbRWLVCwUKtTquact3Tr6ye77Rgmw76VyqMb9TB9KpfrvUYEM5d1Au4fQzE2BbtxRjwzGsqnWHmtQP9UV1kxZaqvb6",
+			wantPub: "vpubVe2WFL3GGTPtWDu2N92goofbgR1quy4AtT1Fkjp1RaFuQ5gVR5izbMshu2BdZgMDeTv1im89AaCkL9TDxRTwGiFDVgk4jCSC679pqjdkcT79",
 		},
 		{
 			name:    "test vector 1 chain m/0",
 			master:  testVec1MasterPubKey,
 			path:    []uint32{0},
-			wantPub: "dpubZHm6cmVU9pvfDCe3BY7iESzsEnV6xfi4DfoYvycnWLM9cryzKA84DqJ2CphYq6cfiEXgo9C3YLJA4ou81mavw9NDtNc3bLCWVqJz8Fx8qxB",
+			wantPub: "vpubVe59AX392SEGMyN8rGyD6Xn7LiL18TsyN3o1qpgeZyUprvcNJtuHHzZYk5gWSeXMmwoTnZ6S9T84GC5XBUonFf7hsncNzuN1T8W84uLDBnPE",
 		},
 		{
 			name:    "test vector 1 chain m/0/1",
 			master:  testVec1MasterPubKey,
 			path:    []uint32{0, 1},
-			wantPub: "dpubZKtA6UTDuxeXV2PcYqoe68u7cgDhbTNbA4dUJoaAvfWzuCcRQCyG5S6dbpDZb2p3B5Y2XxLtD94Nemc8QRV4RspmvGwHvE2FZsfE5Pqpeor",
+			wantPub: "vpubVe7GDzk6nCMzEFBtReGu2PU1b6Djj6fdtzBqmCWbxPoziCwzjyx8VrAMMUg2TQTZ9QeU8Huaz7vpUn3EBsTgP9rARpWiFEFqCCYUJrVAFvGj",
 		},
 		{
 			name:    "test vector 1 chain m/0/1/2",
 			master:  testVec1MasterPubKey,
 			path:    []uint32{0, 1, 2},
-			wantPub: "dpubZMwLXm5dRVEJRvJHU8gNV7RwHeXMRRUnYFD4f6C8uNFfqksD1FCDARTwNPsQB3Pg4LuoKXkZbPnE6woUyedwNYVPvZToT5x4Kt6rs4GKa9c",
+			wantPub: "vpubVe9KQS2jBhta1C5o6ZZmknSYQmC3Nvdk6NNRMYoDvNWjP9WFXazMSw9ifFFgHzU8nHuqu5UzfWBYLEDRYSgqG6Wq3poEkm7kzxYuwe88rwrN",
 		},
 		{
 			name:    "test vector 1 chain m/0/1/2/2",
 			master:  testVec1MasterPubKey,
 			path:    []uint32{0, 1, 2, 2},
-			wantPub: "dpubZPfASfojwk6MhtAtkM6wPdQBr1ycVjoyqs3N51zR1keK6FcBhjBTtdW3Wn3kDLBZqgLnGozu8Gh3FV8GrFGpu3knmGVoF1Z6yGdqLU1Rz1S",
+			wantPub: "vpubVeB3ELwTJE9S4U3fhqnCKgxWfKZVdzx5HfzFexj2CUu82PzzWHULhfMkmPdre2kvg5FGt2mF134T9NkkLKHU9d26SfWGkZ3N3bwSv7Rzgr68",
 		},
 		{
 			name:    "test vector 1 chain m/0/1/2/2/1000000000",
 			master:  testVec1MasterPubKey,
 			path:    []uint32{0, 1, 2, 2, 1000000000},
-			wantPub: "dpubZR5Pf8cbUGikESevygwydenBaTsgcvoYnRSi7tygu23PxmVEG4GeMQj54oHFoPyRdt7Pg4sMad56yprQszbNyZVewaNEhDkn112C3mqB1fd",
+			wantPub: "vpubVeCTTZQG9kg4Szc9k583Mvytf41Pi894rcYf11c1UNAX7GWsYqoRt88ynwf69cpiXsT3VS27TVQqD76UUM2nhhXqJqp9C1FZidfqGppsFCZK",
 		},
 
 		// Test vector 2
@@ -502,37 +502,37 @@ func TestPublicDerivation(t *testing.T) {
 			name:    "test vector 2 chain m",
 			master:  testVec2MasterPubKey,
 			path:    []uint32{},
-			wantPub: "dpubZF4LSCdF9YKZfNzTVYhz4RBxsjYXqms8AQnMBHXZ8GUKoRSigG7kQnKiJt5pzk93Q8FxcdVBEkQZruSXduGtWnkwXzGnjbSovQ97dCxqaXc",
+			wantPub: "vpubVe2SQLUGoRwfGRYVGayoNMkJSMH4ZLz8RzXze4zZLbQx37Aq3G1GzBWaSBjtipAt9dhC4NajH9YAfzB4b6wUDEm6bSE3k3dFkZ4xCQAXCqRz",
 		},
 		{
 			name:    "test vector 2 chain m/0",
 			master:  testVec2MasterPubKey,
 			path:    []uint32{0},
-			wantPub: "dpubZHJs2Z3PtHbbpaXQCi5wBKPhU8tC5ztBKUYBCYNGKk8eZ1EmBs3MhnLJbxHFMAahGnDnZT7qZxC7AXKP8PB6BDNUZgkG77moNMRmXyQ6s6s",
+			wantPub: "vpubVe4gvvpgxAgwJak2DJ9BKUeWAwgQDbD9V9bkU6FQ3ntcMrkd5mcCbUWb2Up69AbKoWM9tKQMwUjxDHnwSbRNQuBi8TvXDR9ak12ErJvqKDP7",
 		},
 		{
 			name:    "test vector 2 chain m/0/2147483647",
 			master:  testVec2MasterPubKey,
 			path:    []uint32{0, 2147483647},
-			wantPub: "dpubZJgFEUcAZawGaLZdFEX6FfQBQVgU4bUC5qvDERUTD5dfcB2AQPnJ1dKp1R2DrAzC36BznZG43317s2oBJv3PuaZmA6HqmwMu6vNna4Gfumf",
+			wantPub: "vpubVe64K8kFiqzGyLW4SLfcUYzWet3CVZojVuy8W88WEgE7NuvQUz8wXnMaXtGq7fbjJGf86YWW9wpmDzJREmxEidYuR4L4o5yAqjbBsM1CUDMr",
 		},
 		{
 			name:    "test vector 2 chain m/0/2147483647/1",
 			master:  testVec2MasterPubKey,
 			path:    []uint32{0, 2147483647, 1},
-			wantPub: "dpubZLbgtFNyjt3k2cJtg4a3dD2iXPKFTLgNKP8rLC1p5UE3AyfRHLTcYrZ6brg8eUmGvKRrXZ7A3XyVfwGxvYtjfz8514dUoJPkmSnBmC6qQK6",
+			wantPub: "vpubVe7yknX2Y2HPSnmohmVfRvY9BzvqGxYwg9WM9Du3bYchkUj3js5crKaopUiV2TuWP9tMxHWMFxKjboCu2Pb64PxTiuJQS7LChQ7bGYDkpwEb",
 		},
 		{
 			name:    "test vector 2 chain m/0/2147483647/1/2147483646",
 			master:  testVec2MasterPubKey,
 			path:    []uint32{0, 2147483647, 1, 2147483646},
-			wantPub: "dpubZNyWTupEG35S6d4uN93vWXpGxQuxtW9zuThQbnWpWTHwRCzxREqSSc9eDYivRGiZnEkEhPece5ciSoHtW6Khc729f6eAxjPnBgU38U9hgYw",
+			wantPub: "vpubVeAMaNBTnYSR8rnZiTa9JorvkRxRzPiRJjauhVVYbybmeixPGzyzgDLQN6QXpEhTg1ogLTLtiYsNpa4uwy8X2L5MoZLR8GmCipMH7uRdhqn2",
 		},
 		{
 			name:    "test vector 2 chain m/0/2147483647/1/2147483646/2",
 			master:  testVec2MasterPubKey,
 			path:    []uint32{0, 2147483647, 1, 2147483646, 2},
-			wantPub: "dpubZRuRErXqhdJaZWD1AzXB6d5w2zw7UZ7ALxiS1gHbnQbVEohBzQzsVwGRzq97pmuE7ToA6DGn2QTH4DexxzdnMvkiYUpk8Nh2KEuYUM2RCeU",
+			wantPub: "vpubVeDHV98BPz2eHKfhpGRcZPxCQWYT8ymNUB5viuPKPFZ5CYZ5WaAA7GfX9sgx1eCeLM2jFrAWswCDPBVH2S2q75u6NSibhSQVxwuidFLHnyMj",
 		},
 	}
 
@@ -615,7 +615,7 @@ func TestExtendedKeyAPI(t *testing.T) {
 	}{
 		{
 			name:      "test vector 1 master node private",
-			extKey:    "dprv3hCznBesA6jBteCheVrorP4Nix6oEWFwtk79FaLy1rPzedNGg9Jhy9y596C9uCPMeeKJMvtEGZRaxPKxGuCFgR4uo2EySsA29GsnXcrsby8",
+			extKey:    "vprvVYh6FZcJRmGQNvdNRj6ULUfyJKpKhHSfU5K75QEczuHKJx1ftLUtrwPAaCCoFgedTrneUW9f4UCpty2wymEibZrb8vvfSpUErS9ucjzDTiNr",
 			isPrivate: true,
 			parentFP:  0,
 			privKey:   "33a63922ea4e6686c9fc31daf136888297537f66c1aabe3363df06af0b8274c7",
@@ -623,7 +623,7 @@ func TestExtendedKeyAPI(t *testing.T) {
 		},
 		{
 			name:       "test vector 2 chain m/0/2147483647/1/2147483646/2",
-			extKey:     "dpubZRuRErXqhdJaZWD1AzXB6d5w2zw7UZ7ALxiS1gHbnQbVEohBzQzsVwGRzq97pmuE7ToA6DGn2QTH4DexxzdnMvkiYUpk8Nh2KEuYUM2RCeU",
+			extKey:     "vpubVeDHV98BPz2eHKfhpGRcZPxCQWYT8ymNUB5viuPKPFZ5CYZ5WaAA7GfX9sgx1eCeLM2jFrAWswCDPBVH2S2q75u6NSibhSQVxwuidFLHnyMj",
 			isPrivate:  false,
 			parentFP:   4220580796,
 			privKeyErr: ErrNotPrivExtKey,
@@ -746,12 +746,12 @@ func TestErrors(t *testing.T) {
 		},
 		{
 			name: "bad checksum",
-			key:  "dpubZF6AWaFizAuUcbkZSs8cP8Gxzr6Sg5tLYYM7gEjZMC5GDaSHB4rW4F51zkWyo9U19BnXhc99kkEiPg248bYin8m9b8mGss9nxV6N2QpU8vj",
+			key:  "vpubVe2UEQquHGaFBNmFNYJDzgTPSUPcUBJ9eNfZQZwmLpLYyXKpbkp1jpyKjscKscaD7NkidTZPFfXzpWwe7bdk3W76oVNYEBtxjb9uSoUNuuyG",
 			err:  ErrBadChecksum,
 		},
 		{
 			name: "pubkey not on curve",
-			key:  "dpubZ9169KDAEUnyoTzA7pDGtXbxpji5LuUk8johUPVGY2CDsz6S7hahGNL6QkeYrUeAPnaJD1MBmrsUnErXScGZdjL6b2gjCRX1Z1GNhLdVCjv",
+			key:  "vpubVdvPA3ariWt8gZdUyDFJfBriSJHE6r7k3xs1zN6X41AfwBjUkhSjw36apHcTSfuPGdMWPxxbHgedauWUaueTtMhfkVGTgWTKxBg5TUSgDh8R",
 			err:  secp256k1.ErrPubKeyNotOnCurve,
 		},
 		{
@@ -786,7 +786,7 @@ func TestZero(t *testing.T) {
 		{
 			name:   "test vector 1 chain m",
 			master: "000102030405060708090a0b0c0d0e0f",
-			extKey: "dprv3hCznBesA6jBtmoyVFPfyMSZ1qYZ3WdjdebquvkEfmRfxC9VFEFi2YDaJqHnx7uGe75eGSa3Mn3oHK11hBW7KZUrPxwbCPBmuCi1nwm182s",
+			extKey: "vprvVYh6FZcJRmGQNvkyhZr1CbeMUchmT6T3FpDbn4b2GZCLzFaT6uZqrzmR5Mwttja9NrFQpQfLsZRT7Hxd3BX2TD115XsN4ZzGcC5jr1HiM4VN",
 			net:    mainNetParams,
 		},
 
@@ -794,7 +794,7 @@ func TestZero(t *testing.T) {
 		{
 			name:   "test vector 2 chain m",
 			master: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
-			extKey: "dprv3hCznBesA6jBtPKJbQTxRZAKG2gyj8tZKEPaCsV4e9YYFBAgRP2eTSPAeu4r8dTMt9q51j2Vdt5zNqj7jbtovvocrP1qLj6WUTLF9xYQt4y",
+			extKey: "vprvVYh6FZcJRmGQNvNV2g15V3r5Ertusn5J5VoPWMXm6XaTrYZUJ5icoRfafi1fwv5hU6JAF9woKqXVJPVM9DwR9pNKqzHSJiLBLmLN5NGYErop",
 			net:    mainNetParams,
 		},
 	}
@@ -919,8 +919,8 @@ func TestBIP0032Vector4(t *testing.T) {
 			name:        "test vector 4 chain m",
 			master:      testVec4MasterHex,
 			path:        []uint32{},
-			wantPub:     "dpubZ9169KDAEUnyovAoD3NuxWgVNvAWqC3eUFUrip8ZnT4emsEvAPCyhVgoymUF6p7gug7K6ewzEaUvy77tT7LStEjj56CaZxLRMSYjypMK1yC",
-			wantPriv:    "dprv3hCznBesA6jBuWH2xXKZSHtEkucFQFTaGhrXyuCCnNU24KDHHRsNcvAnq4596P5sYKTSgqbjUe2UNVxid8J9oDyFsuSWb3L93Jrka3vPtYV",
+			wantPub:     "vpubVdvPA3ariWt8ga5fcJUUJFqnxrTgYLQJxJNh9cXAMFbYN5cdEk8NDUDwXrdH8vEro9F3QrcC69NF36Njwv9XmcD5NyKyXsz9Mz7MpkpwQ9Hi",
+			wantPriv:    "vprvVYh6FZcJRmGQNwVSm37w64aoAMmq9TBs6TGrU8ZUEfoPLMhWtwmTXb9NHtAgEsqKykTncq4NZgHRnP9ak7TpVgfVV1oryxeQyLBtanP413hM",
 			wantPrivSer: "12c0d59c7aa3a10973dbd3f478b65f2516627e3fe61e00c345be9a477ad2e215",
 			net:         mainNetParams,
 		},
@@ -928,8 +928,8 @@ func TestBIP0032Vector4(t *testing.T) {
 			name:         "test vector 4 chain m/0H -- leading zeros in private key serialization",
 			master:       testVec4MasterHex,
 			path:         []uint32{hkStart},
-			wantPub:      "dpubZBYwK8RvCX3KjyCPhRQ9BYdX1ZYTvcFf67sRtoZGPnEYMCVgVyF5uWToDwePapvd1GtLYHAWGpQWo9ffod84MPvwpHmvKeZnZZf47EJcUmT",
-			wantPriv:     "dprv3jkqwzsd88yXqZJdSuLnfKqGPYzCVffataF79tcuPhdudeU3d1uUpvwn5Btmbai3BoVYhzbsEQ7tcH1XtEJ1BhzNSAtZfqv8BtSpdq5FEpj",
+			wantPub:      "vpubVdxw1DQ5UUvP2W8hCnrVXUsjzV74VRpWxvF5inWb3rviFewt15iQKgEiX6oTHQFfjEqpSJEQcBcAcvRHjGfKP5NGbiXYsdgNjCEU8tFcEsuj",
+			wantPriv:     "vprvVYje6jRXBjJeisYUMXVxKHckBzRD6Yc5759F3JYtwH8ZDw2mfHMVdoA9H8JVsP2x9PwpirDNhS3XCcvdZNZpM59Wba5K33SzxUmUerCVJgQW",
 			wantPrivSer:  "00d948e9261e41362a688b916f297121ba6bfb2274a3575ac0e456551dfd7f7e",
 			leadingZeros: 1, // 1 zero byte
 			net:          mainNetParams,
@@ -938,8 +938,8 @@ func TestBIP0032Vector4(t *testing.T) {
 			name:        "test vector 4 chain m/0H/1H -- completely different key",
 			master:      testVec4MasterHex,
 			path:        []uint32{hkStart, hkStart + 1},
-			wantPub:     "dpubZDVQT3iY5Bz6NtyCE8zfnpgwkN7AEUv5mr6H6vPVPRMupBbjTJojs7qRrvddW9adSJt6obdBgW6DsUAaFD8xRVQfVB4XfWCEYdTYRZ7updr",
-			wantPriv:    "dprv3mhK5vAEzovJUV5RycwKGbth8MYtoYL1aJTxN1T8PLmH6da6aMU8nYKQiC5Zj7mC7pH4G8xPxGZVR51FG9Ssptzx1c39A1JxADS6zDrsLPr",
+			wantPub:     "vpubVdzsUMKN6MbKo94U1Ka6469oRDudBjhBPbyJZzdRGrZqd7vz433xydr69jnSXKaKjfspCZYsHbHrKzjndiFLH9TkKPQqUyY1BBJGdCbJzrve",
+			wantPriv:    "vprvVYmaZsLoobybVWUFA4DYqttocjDmnrUjXksTtWfjAGmgbQ1siEh4HkmWumJgfWa1JKxcEQMjE9uxoRidGkUyDiLXB9WTcXcPnT6TwCaWuZv7",
 			wantPrivSer: "3a2086edd7d9df86c3487a5905a1712a9aa664bce8cc268141e07549eaa8661d",
 			net:         mainNetParams,
 		},
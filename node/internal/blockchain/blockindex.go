@@ -717,7 +717,7 @@ func (bi *blockIndex) addNode(node *blockNode) {
 	// Update the header with most known work that is also not known to be
 	// invalid to this node if needed.
 	if !node.status.KnownInvalid() && betterCandidate(node, bi.bestHeader) {
-		bi.bestHeader = node
+		bi.setBestHeader(node)
 	}
 }
 
@@ -1075,6 +1075,17 @@ func (bi *blockIndex) maybeUpdateBestInvalid(invalidNode *blockNode) {
 	}
 }
 
+// setBestHeader sets the header with the most known work that is also not known
+// to be invalid to the provided node and updates the associated metric.
+//
+// This function MUST be called with the block index lock held (for writes).
+func (bi *blockIndex) setBestHeader(node *blockNode) {
+	bi.bestHeader = node
+	if node != nil {
+		bestHeaderHeightGauge.Set(float64(node.height))
+	}
+}
+
 // maybeUpdateBestHeaderForTip potentially updates the best known header that is
 // not known to be invalid, as determined by having the most cumulative work.
 // It works by walking backwards from the provided tip so long as those headers
@@ -1085,7 +1096,7 @@ func (bi *blockIndex) maybeUpdateBestInvalid(invalidNode *blockNode) {
 func (bi *blockIndex) maybeUpdateBestHeaderForTip(tip *blockNode) {
 	for n := tip; n != nil && betterCandidate(n, bi.bestHeader); n = n.parent {
 		if !n.status.KnownInvalid() {
-			bi.bestHeader = n
+			bi.setBestHeader(n)
 			return
 		}
 	}
@@ -1219,7 +1230,7 @@ func (bi *blockIndex) MarkBlockFailedValidation(node *blockNode) {
 		for n != nil && n.status.KnownInvalid() {
 			n = n.parent
 		}
-		bi.bestHeader = n
+		bi.setBestHeader(n)
 
 		// Scour the block tree to find a new best header.
 		//
@@ -28,6 +28,7 @@ import (
 	"github.com/kdsmith18542/vigil/gcs/v4"
 	"github.com/kdsmith18542/vigil/gcs/v4/blockcf2"
 	"github.com/kdsmith18542/vigil/internal/blockchain/indexers"
+	"github.com/kdsmith18542/vigil/kawpow"
 	"github.com/kdsmith18542/vigil/math/uint256"
 	"github.com/kdsmith18542/vigil/txscript/v4"
 	"github.com/kdsmith18542/vigil/wire"
@@ -801,6 +802,10 @@ func (b *BlockChain) connectBlock(node *blockNode, block, parent *VGLutil.Block,
 	b.bestChain.SetTip(node)
 	b.index.MaybePruneCachedTips(node)
 
+	// Notify the KawPoW epoch manager of the new tip so the light cache for the
+	// next epoch is generated in the background ahead of the epoch boundary.
+	kawpow.DefaultEpochManager().NotifyHeight(uint64(node.height))
+
 	// Update the state for the best block.  Notice how this replaces the
 	// entire struct instead of updating the existing one.  This effectively
 	// allows the old version to act as a snapshot which callers can use
@@ -809,6 +814,7 @@ func (b *BlockChain) connectBlock(node *blockNode, block, parent *VGLutil.Block,
 	b.stateLock.Lock()
 	b.stateSnapshot = state
 	b.stateLock.Unlock()
+	chainHeightGauge.Set(float64(state.Height))
 
 	// Conditionally log target difficulty changes at retarget intervals.  Only
 	// log when the chain believes it is current since it is very noisy during
@@ -823,6 +829,20 @@ func (b *BlockChain) connectBlock(node *blockNode, block, parent *VGLutil.Block,
 		log.Debugf("New target %08x (%064x)", node.bits, newDiff)
 	}
 
+	// Notify subscribed indexes of the connected block.  The scripts spent by
+	// the block are provided via a separate script source built from the
+	// spent txouts since the view is modified once committed to the cache.
+	if b.indexSubscriber != nil {
+		prevScripts := stxosToScriptSource(block, stxos, isTreasuryEnabled)
+		b.indexSubscriber.Notify(&indexers.IndexNtfn{
+			NtfnType:          indexers.ConnectNtfn,
+			Block:             block,
+			Parent:            parent,
+			PrevScripts:       prevScripts,
+			IsTreasuryEnabled: isTreasuryEnabled,
+		})
+	}
+
 	// Notify the caller that the block was connected to the main chain.
 	// The caller would typically want to react with actions such as
 	// updating wallets.
@@ -831,7 +851,7 @@ func (b *BlockChain) connectBlock(node *blockNode, block, parent *VGLutil.Block,
 	// reorged and it is no longer valid.
 	b.kawpowWorkDiffAnchorCache = nil
 
-	b.chainLock.Lock()
+	b.chainLock.Unlock()
 	b.sendNotification(NTBlockConnected, &BlockConnectedNtfnsData{
 		Block:        block,
 		ParentBlock:  parent,
@@ -987,6 +1007,7 @@ func (b *BlockChain) disconnectBlock(node *blockNode, block, parent *VGLutil.Blo
 
 	// This node's parent is now the end of the best chain.
 	b.bestChain.SetTip(node.parent)
+	kawpow.DefaultEpochManager().NotifyHeight(uint64(node.parent.height))
 
 	// Update the state for the best block.  Notice how this replaces the
 	// entire struct instead of updating the existing one.  This effectively
@@ -996,6 +1017,17 @@ func (b *BlockChain) disconnectBlock(node *blockNode, block, parent *VGLutil.Blo
 	b.stateLock.Lock()
 	b.stateSnapshot = state
 	b.stateLock.Unlock()
+	chainHeightGauge.Set(float64(state.Height))
+
+	// Notify subscribed indexes of the disconnected block.
+	if b.indexSubscriber != nil {
+		b.indexSubscriber.Notify(&indexers.IndexNtfn{
+			NtfnType:          indexers.DisconnectNtfn,
+			Block:             block,
+			Parent:            parent,
+			IsTreasuryEnabled: checkTxFlags.IsTreasuryEnabled(),
+		})
+	}
 
 	// Notify the caller that the block was disconnected from the main
 	// chain.  The caller would typically want to react with actions such as
@@ -2341,6 +2373,26 @@ func (q *ChainQueryerAdapter) BlockHeaderByHash(hash *chainhash.Hash) (wire.Bloc
 	return q.HeaderByHash(hash)
 }
 
+// PrevScripts returns a source of previous transaction scripts and their
+// associated versions spent by the provided block.  The spend journal entry
+// for the block must exist, which is the case for all blocks in the main chain.
+//
+// This is part of the indexers.ChainQueryer interface.
+func (q *ChainQueryerAdapter) PrevScripts(dbTx database.Tx, block *VGLutil.Block) (indexers.PrevScripter, error) {
+	isTreasuryEnabled, err := q.IsTreasuryAgendaActive(
+		&block.MsgBlock().Header.PrevBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	stxos, err := dbFetchSpendJournalEntry(dbTx, block, isTreasuryEnabled)
+	if err != nil {
+		return nil, err
+	}
+
+	return stxosToScriptSource(block, stxos, isTreasuryEnabled), nil
+}
+
 // Config is a descriptor which specifies the blockchain instance configuration.
 type Config struct {
 	// DB defines the database which houses the blocks and will be used to
@@ -2420,6 +2472,15 @@ type Config struct {
 	//
 	// This field is required.
 	UtxoCache UtxoCacher
+
+	// PruneTarget is the target number of bytes to use for storing block
+	// data.  The data for the oldest blocks is removed as needed to remain
+	// under the target, however, the data for the blocks within the minimum
+	// prune depth is always retained even when that means exceeding the
+	// target.  See MinPruneDepth for details.
+	//
+	// Block data pruning is disabled when this is zero.
+	PruneTarget uint64
 }
 
 // newRecentBlocksCache returns a new LRU map for more efficient access to
@@ -2523,7 +2584,10 @@ func New(ctx context.Context, config *Config) (*BlockChain, error) {
 		calcStakeVersionCache:         make(map[[chainhash.HashSize]byte]uint32),
 		utxoCache:                     config.UtxoCache,
 	}
-	b.pruner = newChainPruner(&b)
+	b.pruner, err = newChainPruner(&b, config.PruneTarget)
+	if err != nil {
+		return nil, err
+	}
 
 	// Initialize the chain state from the passed database.  When the db
 	// does not yet contain any chain state, both it and the chain state
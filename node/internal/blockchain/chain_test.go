@@ -1031,3 +1031,57 @@ func TestLocateInventory(t *testing.T) {
 		}
 	}
 }
+
+// TestBlockConnectedNtfnChainLock ensures the chain lock is released while the
+// block connected notification is sent so notification handlers are able to
+// query the chain and that the lock is released once the block is processed.
+func TestBlockConnectedNtfnChainLock(t *testing.T) {
+	t.Parallel()
+
+	// Record whether or not the chain lock is held when the block connected
+	// notification is sent.
+	g := newChaingenHarness(t, chaincfg.RegNetParams())
+	var numConnected int
+	var lockHeld bool
+	g.chain.notifications = func(n *Notification) {
+		if n.Type != NTBlockConnected {
+			return
+		}
+		numConnected++
+		if !g.chain.chainLock.TryLock() {
+			lockHeld = true
+			return
+		}
+		g.chain.chainLock.Unlock()
+	}
+
+	// Process the first block in a separate goroutine so the test fails
+	// instead of hanging when connecting the block deadlocks.
+	g.CreateBlockOne("bfb", 0)
+	block := VGLutil.NewBlock(g.BlockByName("bfb"))
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := g.chain.ProcessBlock(block)
+		errChan <- err
+	}()
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("unexpected error processing block: %v", err)
+		}
+	case <-time.After(time.Second * 30):
+		t.Fatal("timeout processing block")
+	}
+
+	if numConnected != 1 {
+		t.Fatalf("unexpected number of block connected notifications -- "+
+			"got %d, want 1", numConnected)
+	}
+	if lockHeld {
+		t.Fatal("chain lock held while sending block connected notification")
+	}
+	if !g.chain.chainLock.TryLock() {
+		t.Fatal("chain lock held after processing block")
+	}
+	g.chain.chainLock.Unlock()
+}
@@ -790,6 +790,94 @@ func dbFetchSpendJournalEntry(dbTx database.Tx, block *VGLutil.Block, isTreasury
 	return stxos, nil
 }
 
+// scriptSourceEntry houses a script and its associated version.
+type scriptSourceEntry struct {
+	version uint16
+	script  []byte
+}
+
+// scriptSource provides a source of transaction output scripts and their
+// associated script version for given outpoints and implements the
+// PrevScripter interface so it may be used in cases that require access to
+// said scripts.
+type scriptSource map[wire.OutPoint]scriptSourceEntry
+
+// PrevScript returns the script and script version associated with the provided
+// previous outpoint along with a bool that indicates whether or not the
+// requested entry exists.  This ensures the caller is able to distinguish
+// between missing entries and empty v0 scripts.
+func (s scriptSource) PrevScript(prevOut *wire.OutPoint) (uint16, []byte, bool) {
+	entry, ok := s[*prevOut]
+	if !ok {
+		return 0, nil, false
+	}
+	return entry.version, entry.script, true
+}
+
+// stxosToScriptSource uses the provided block and spent txo information to
+// create a source of previous transaction scripts and versions spent by the
+// block.
+func stxosToScriptSource(block *VGLutil.Block, stxos []spentTxOut, isTreasuryEnabled bool) scriptSource {
+	source := make(scriptSource, len(stxos))
+	msgBlock := block.MsgBlock()
+
+	// Loop through all of the transaction inputs in the stake transaction tree
+	// (except for the stakebases, treasurybases, and treasury spends which
+	// have no inputs) and add the scripts and associated script versions from
+	// the referenced txos to the script source.
+	//
+	// Note that transactions in the stake tree are spent before transactions
+	// in the regular tree when originally creating the spend journal entry,
+	// thus the spent txouts need to be processed in the same order.
+	var stxoIdx int
+	for txIdx, tx := range msgBlock.STransactions {
+		// Ignore the treasurybase and treasury spends since they have no
+		// inputs.
+		isTreasuryBase := isTreasuryEnabled && txIdx == 0
+		if isTreasuryBase || (isTreasuryEnabled && stake.IsTSpend(tx)) {
+			continue
+		}
+
+		isVote := stake.IsSSGen(tx)
+		for txInIdx, txIn := range tx.TxIn {
+			// Ignore the stakebase since it has no input.
+			if txInIdx == 0 && isVote {
+				continue
+			}
+
+			// Ensure the spent txout index is incremented to stay in sync with
+			// the transaction input.
+			stxo := &stxos[stxoIdx]
+			stxoIdx++
+
+			source[txIn.PreviousOutPoint] = scriptSourceEntry{
+				version: stxo.scriptVersion,
+				script:  stxo.pkScript,
+			}
+		}
+	}
+
+	// Loop through all of the transaction inputs in the regular transaction
+	// tree (except for the coinbase which has no inputs) and add the scripts
+	// and associated script versions from the referenced txos to the script
+	// source.
+	for _, tx := range msgBlock.Transactions[1:] {
+		for _, txIn := range tx.TxIn {
+			// Ensure the spent txout index is incremented to stay in sync with
+			// the transaction input.
+			stxo := &stxos[stxoIdx]
+			stxoIdx++
+
+			source[txIn.PreviousOutPoint] = scriptSourceEntry{
+				version: stxo.scriptVersion,
+				script:  stxo.pkScript,
+			}
+		}
+	}
+
+	return source
+}
+
 // dbPutSpendJournalEntry uses an existing database transaction to update the
 // spend journal entry for the given block hash using the provided slice of
 // spent txouts.   The spent txouts slice must contain an entry for every txout
@@ -1756,6 +1844,7 @@ func (b *BlockChain) initChainState(ctx context.Context,
 			state.totalSubsidy, uint32(tip.stakeNode.PoolSize()),
 			nextStakeDiff, tip.stakeNode.ExpiringNextBlock(), tip.stakeNode.Winners(),
 			tip.stakeNode.MissedTickets(), tip.stakeNode.FinalState())
+		chainHeightGauge.Set(float64(tip.height))
 
 		return nil
 	})
@@ -270,7 +270,7 @@ func (b *BlockChain) kawpowWorkDiffAnchor(prevNode *blockNode) *blockNode {
 	// ancestor of the provided block.
 	if b.kawpowWorkDiffAnchorCache != nil {
 		anchor := b.kawpowWorkDiffAnchorCache
-		if prevNode.IsAncestorOf(anchor) {
+		if anchor.IsAncestorOf(prevNode) {
 			return anchor
 		}
 	}
@@ -6,7 +6,11 @@
 package blockchain
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"testing"
 	"time"
 
@@ -1135,3 +1139,126 @@ func TestMinDifficultyReduction(t *testing.T) {
 		}
 	}
 }
+
+// kawpowDiffTestChain describes a header chain rooted at the genesis block
+// along with the required KawPoW difficulty of each header as stored in the
+// KawPoW difficulty test data.
+type kawpowDiffTestChain struct {
+	Name    string `json:"name"`
+	Headers []struct {
+		Height    uint32 `json:"height"`
+		Timestamp int64  `json:"timestamp"`
+		Bits      string `json:"bits"`
+	} `json:"headers"`
+}
+
+// TestCalcNextKawpowDiff ensures the required difficulty calculated for the
+// KawPoW agenda matches the test data.  The wallet header chain validation tests
+// use a copy of the same data to ensure both implementations agree.
+func TestCalcNextKawpowDiff(t *testing.T) {
+	t.Parallel()
+
+	testDataFile := filepath.Join("testdata", "kawpowdiff.json")
+	data, err := os.ReadFile(testDataFile)
+	if err != nil {
+		t.Fatalf("unable to read test data: %v", err)
+	}
+	var testData struct {
+		Chains []kawpowDiffTestChain `json:"chains"`
+	}
+	if err := json.Unmarshal(data, &testData); err != nil {
+		t.Fatalf("unable to parse test data: %v", err)
+	}
+
+	// The KawPoW agenda is forced active on the main network.
+	params := chaincfg.MainNetParams()
+	for _, test := range testData.Chains {
+		bc := newFakeChain(params)
+		node := bc.bestChain.Tip()
+		for _, header := range test.Headers {
+			wantBits, err := strconv.ParseUint(header.Bits, 16, 32)
+			if err != nil {
+				t.Fatalf("%q: bad bits %q: %v", test.Name, header.Bits, err)
+			}
+
+			timestamp := time.Unix(header.Timestamp, 0)
+			gotBits, err := bc.calcNextRequiredDifficulty(node, timestamp)
+			if err != nil {
+				t.Fatalf("%q-%d: unexpected err: %v", test.Name, header.Height,
+					err)
+			}
+			if gotBits != uint32(wantBits) {
+				t.Fatalf("%q-%d: mismatched difficulty -- got %08x, want "+
+					"%08x", test.Name, header.Height, gotBits, wantBits)
+			}
+
+			node = newFakeNode(node, 12, 12, gotBits, timestamp)
+			bc.index.AddNode(node)
+			bc.bestChain.SetTip(node)
+		}
+	}
+}
+
+// TestKawpowWorkDiffAnchor ensures the cached KawPoW difficulty anchor is only
+// reused when it is an ancestor of the provided block and is otherwise found
+// again by walking back to the block where the agenda became active.
+func TestKawpowWorkDiffAnchor(t *testing.T) {
+	t.Parallel()
+
+	// The KawPoW agenda is forced active on the main network, so the anchor
+	// is always the genesis block when it is not cached.
+	params := chaincfg.MainNetParams()
+	bc := newFakeChain(params)
+	genesis := bc.bestChain.Tip()
+	node := genesis
+	timestamp := time.Unix(genesis.timestamp, 0)
+	for i := 0; i < 10; i++ {
+		timestamp = timestamp.Add(params.TargetTimePerBlock)
+		node = newFakeNode(node, 12, 12, params.PowLimitBits, timestamp)
+		bc.index.AddNode(node)
+		bc.bestChain.SetTip(node)
+	}
+	tip := node
+	mid := tip.Ancestor(5)
+
+	tests := []struct {
+		name   string     // test description
+		cached *blockNode // cached anchor prior to the call
+		prev   *blockNode // block to find the anchor for
+		want   *blockNode // expected anchor
+	}{{
+		name:   "no cached anchor",
+		cached: nil,
+		prev:   tip,
+		want:   genesis,
+	}, {
+		name:   "cached anchor is an ancestor",
+		cached: mid,
+		prev:   tip,
+		want:   mid,
+	}, {
+		name:   "cached anchor is the provided block",
+		cached: mid,
+		prev:   mid,
+		want:   mid,
+	}, {
+		name:   "cached anchor is a descendant",
+		cached: tip,
+		prev:   mid,
+		want:   genesis,
+	}}
+
+	for _, test := range tests {
+		bc.kawpowWorkDiffAnchorCache = test.cached
+		got := bc.kawpowWorkDiffAnchor(test.prev)
+		if got != test.want {
+			t.Errorf("%q: mismatched anchor -- got height %d, want height %d",
+				test.name, got.height, test.want.height)
+			continue
+		}
+		if bc.kawpowWorkDiffAnchorCache != test.want {
+			t.Errorf("%q: anchor at height %d not cached", test.name,
+				test.want.height)
+		}
+	}
+}
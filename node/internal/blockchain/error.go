@@ -95,6 +95,10 @@ const (
 	// lower than the required target difficultly.
 	ErrHighHash = ErrorKind("ErrHighHash")
 
+	// ErrBadMixHash indicates the KawPoW mix hash in the block header does
+	// not match the mix hash calculated from the header hash and nonce.
+	ErrBadMixHash = ErrorKind("ErrBadMixHash")
+
 	// ErrBadMerkleRoot indicates the calculated merkle root does not match
 	// the expected value.
 	ErrBadMerkleRoot = ErrorKind("ErrBadMerkleRoot")
@@ -29,6 +29,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrTimeTooNew, "ErrTimeTooNew"},
 		{ErrUnexpectedDifficulty, "ErrUnexpectedDifficulty"},
 		{ErrHighHash, "ErrHighHash"},
+		{ErrBadMixHash, "ErrBadMixHash"},
 		{ErrBadMerkleRoot, "ErrBadMerkleRoot"},
 		{ErrBadCommitmentRoot, "ErrBadCommitmentRoot"},
 		{ErrForkTooOld, "ErrForkTooOld"},
@@ -39,6 +39,8 @@ func fullBlockTestErrToLocalErr(t *testing.T, kind fullblocktests.ErrorKind) Err
 		return ErrUnexpectedDifficulty
 	case fullblocktests.ErrHighHash:
 		return ErrHighHash
+	case fullblocktests.ErrBadMixHash:
+		return ErrBadMixHash
 	case fullblocktests.ErrBadMerkleRoot:
 		return ErrBadMerkleRoot
 	case fullblocktests.ErrNoTransactions:
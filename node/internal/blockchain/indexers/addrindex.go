@@ -0,0 +1,1057 @@
+// Copyright (c) 2016 The btcsuite developers
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/database/v3"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdscript"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+const (
+	// addrIndexName is the human-readable name for the index.
+	addrIndexName = "address index"
+
+	// addrIndexVersion is the current version of the address index.
+	addrIndexVersion = 1
+
+	// addrIndexTxPrefix, addrIndexUtxoPrefix, and addrIndexUndoPrefix are
+	// the key prefixes which identify the type of the entries stored in the
+	// address index bucket.
+	addrIndexTxPrefix   = 't'
+	addrIndexUtxoPrefix = 'u'
+	addrIndexUndoPrefix = 'b'
+
+	// addrTxKeySize is the size of a transaction entry key.  It consists of
+	// 1 byte prefix + address key + 4 bytes block id + 1 byte tree + 4 bytes
+	// transaction index.
+	addrTxKeySize = 1 + addrKeySize + 4 + 1 + 4
+
+	// addrTxValueSize is the size of a transaction entry value.  It consists
+	// of 4 bytes start offset + 4 bytes transaction length.
+	addrTxValueSize = 4 + 4
+
+	// addrUtxoKeySize is the size of an unspent output entry key.  It
+	// consists of 1 byte prefix + address key + 32 bytes transaction hash +
+	// 4 bytes output index + 1 byte tree.
+	addrUtxoKeySize = 1 + addrKeySize + chainhash.HashSize + 4 + 1
+
+	// minAddrUtxoValueSize is the minimum size of an unspent output entry
+	// value.  It consists of 8 bytes amount + 4 bytes block height + 2 bytes
+	// script version followed by the variable length script.
+	minAddrUtxoValueSize = 8 + 4 + 2
+)
+
+var (
+	// addrIndexKey is the key of the address index and the db bucket used
+	// to house it.
+	addrIndexKey = []byte("addridx")
+
+	// bigEndian is the byte order used for the numeric fields of keys which
+	// are required to sort in numeric order.
+	bigEndian = binary.BigEndian
+)
+
+// -----------------------------------------------------------------------------
+// The address index maps addresses referenced in the blockchain to the
+// transactions that involve them, along with the currently unspent outputs
+// that pay to them.  It relies on the transaction index for the internal
+// block ID mapping described there, which means the transaction index must
+// also be enabled.
+//
+// All entries are stored in a single flat bucket so the index can be dropped
+// incrementally.  The first byte of each key identifies the kind of entry.
+//
+// The serialized format for the transaction entries is:
+//
+//   't'<addr key><block id><tree><tx index> = <start offset><tx length>
+//
+//   Field           Type       Size
+//   addr key        [21]byte   21 bytes
+//   block id        uint32     4 bytes (big endian)
+//   tree            int8       1 byte
+//   tx index        uint32     4 bytes (big endian)
+//   start offset    uint32     4 bytes
+//   tx length       uint32     4 bytes
+//
+// Note that the block id and transaction index are big endian so that a
+// cursor iterates the entries for an address in the order they appear in the
+// chain.
+//
+// The serialized format for the unspent output entries is:
+//
+//   'u'<addr key><tx hash><output index><tree> = <amount><height><version><script>
+//
+//   Field           Type              Size
+//   addr key        [21]byte          21 bytes
+//   tx hash         chainhash.Hash    32 bytes
+//   output index    uint32            4 bytes
+//   tree            int8              1 byte
+//   amount          int64             8 bytes
+//   height          uint32            4 bytes
+//   version         uint16            2 bytes
+//   script          []byte            variable
+//
+// Finally, an undo entry is stored for every connected block so that it can
+// be disconnected without access to the outputs it spent, which are no longer
+// available once the chain has removed its spend journal entry:
+//
+//   'b'<block hash> = <block id><num txs>[<addr key><tree><tx index>...]
+//                     <num spent>[<utxo key><value len><value>...]
+//
+//   Field           Type              Size
+//   block hash      chainhash.Hash    32 bytes
+//   block id        uint32            4 bytes
+//   num txs         VarInt            variable
+//   addr key        [21]byte          21 bytes
+//   tree            int8              1 byte
+//   tx index        uint32            4 bytes
+//   num spent       VarInt            variable
+//   utxo key        []byte            59 bytes
+//   value len       VarInt            variable
+//   value           []byte            variable
+//
+// A spent entry with an empty value indicates the spent output did not have
+// an unspent output entry when the block was connected.
+// -----------------------------------------------------------------------------
+
+// AddrUtxo houses information about an unspent transaction output that pays to
+// an address.
+type AddrUtxo struct {
+	OutPoint      wire.OutPoint
+	Amount        int64
+	Height        int64
+	ScriptVersion uint16
+	PkScript      []byte
+}
+
+// addrTxKey returns the key of the transaction entry for the provided address
+// key and transaction location.
+func addrTxKey(addrKey [addrKeySize]byte, blockID uint32, tree int8, txIdx uint32) []byte {
+	key := make([]byte, addrTxKeySize)
+	key[0] = addrIndexTxPrefix
+	copy(key[1:], addrKey[:])
+	offset := 1 + addrKeySize
+	bigEndian.PutUint32(key[offset:], blockID)
+	key[offset+4] = byte(tree)
+	bigEndian.PutUint32(key[offset+5:], txIdx)
+	return key
+}
+
+// addrUtxoKey returns the key of the unspent output entry for the provided
+// address key and outpoint.
+func addrUtxoKey(addrKey [addrKeySize]byte, outpoint *wire.OutPoint) []byte {
+	key := make([]byte, addrUtxoKeySize)
+	key[0] = addrIndexUtxoPrefix
+	copy(key[1:], addrKey[:])
+	offset := 1 + addrKeySize
+	copy(key[offset:], outpoint.Hash[:])
+	offset += chainhash.HashSize
+	byteOrder.PutUint32(key[offset:], outpoint.Index)
+	key[offset+4] = byte(outpoint.Tree)
+	return key
+}
+
+// addrUndoKey returns the key of the undo entry for the provided block hash.
+func addrUndoKey(hash *chainhash.Hash) []byte {
+	key := make([]byte, 1+chainhash.HashSize)
+	key[0] = addrIndexUndoPrefix
+	copy(key[1:], hash[:])
+	return key
+}
+
+// serializeAddrUtxo returns the serialized unspent output entry value for the
+// provided output and height.
+func serializeAddrUtxo(txOut *wire.TxOut, height int64) []byte {
+	serialized := make([]byte, minAddrUtxoValueSize+len(txOut.PkScript))
+	byteOrder.PutUint64(serialized, uint64(txOut.Value))
+	byteOrder.PutUint32(serialized[8:], uint32(height))
+	byteOrder.PutUint16(serialized[12:], txOut.Version)
+	copy(serialized[minAddrUtxoValueSize:], txOut.PkScript)
+	return serialized
+}
+
+// deserializeAddrUtxo decodes the provided unspent output entry key and value.
+func deserializeAddrUtxo(key, value []byte) (*AddrUtxo, error) {
+	if len(key) != addrUtxoKeySize || len(value) < minAddrUtxoValueSize {
+		return nil, makeDbErr(database.ErrCorruption, "corrupt address "+
+			"index unspent output entry")
+	}
+
+	var utxo AddrUtxo
+	offset := 1 + addrKeySize
+	copy(utxo.OutPoint.Hash[:], key[offset:])
+	offset += chainhash.HashSize
+	utxo.OutPoint.Index = byteOrder.Uint32(key[offset:])
+	utxo.OutPoint.Tree = int8(key[offset+4])
+	utxo.Amount = int64(byteOrder.Uint64(value))
+	utxo.Height = int64(byteOrder.Uint32(value[8:]))
+	utxo.ScriptVersion = byteOrder.Uint16(value[12:])
+	utxo.PkScript = make([]byte, len(value)-minAddrUtxoValueSize)
+	copy(utxo.PkScript, value[minAddrUtxoValueSize:])
+	return &utxo, nil
+}
+
+// addrSpentEntry describes an unspent output entry removed from the index by a
+// connected block.  It is stored in the undo entry for the block so the entry
+// can be restored when the block is disconnected.
+type addrSpentEntry struct {
+	key   []byte
+	value []byte
+}
+
+// addrUndoEntry houses the information needed to disconnect a block from the
+// address index.
+type addrUndoEntry struct {
+	blockID uint32
+	txKeys  [][]byte
+	spent   []addrSpentEntry
+}
+
+// serializeAddrUndoEntry serializes the provided undo entry according to the
+// format described above.
+func serializeAddrUndoEntry(entry *addrUndoEntry) []byte {
+	const txKeySuffixSize = addrKeySize + 1 + 4
+	size := 4 + wire.VarIntSerializeSize(uint64(len(entry.txKeys))) +
+		len(entry.txKeys)*txKeySuffixSize +
+		wire.VarIntSerializeSize(uint64(len(entry.spent)))
+	for _, spent := range entry.spent {
+		size += addrUtxoKeySize +
+			wire.VarIntSerializeSize(uint64(len(spent.value))) +
+			len(spent.value)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(size)
+	var serializedID [4]byte
+	byteOrder.PutUint32(serializedID[:], entry.blockID)
+	buf.Write(serializedID[:])
+	wire.WriteVarInt(&buf, 0, uint64(len(entry.txKeys)))
+	for _, txKey := range entry.txKeys {
+		// The block id is omitted since it is the same for all entries.
+		buf.Write(txKey[1 : 1+addrKeySize])
+		buf.Write(txKey[1+addrKeySize+4:])
+	}
+	wire.WriteVarInt(&buf, 0, uint64(len(entry.spent)))
+	for _, spent := range entry.spent {
+		buf.Write(spent.key)
+		wire.WriteVarInt(&buf, 0, uint64(len(spent.value)))
+		buf.Write(spent.value)
+	}
+	return buf.Bytes()
+}
+
+// deserializeAddrUndoEntry decodes the provided serialized undo entry.
+func deserializeAddrUndoEntry(serialized []byte) (*addrUndoEntry, error) {
+	corruptErr := func(err error) error {
+		str := fmt.Sprintf("corrupt address index undo entry: %v", err)
+		return makeDbErr(database.ErrCorruption, str)
+	}
+
+	if len(serialized) < 4 {
+		return nil, corruptErr(fmt.Errorf("unexpected end of data"))
+	}
+	var entry addrUndoEntry
+	entry.blockID = byteOrder.Uint32(serialized)
+	r := bytes.NewReader(serialized[4:])
+
+	numTxKeys, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, corruptErr(err)
+	}
+	if numTxKeys > uint64(r.Len()) {
+		return nil, corruptErr(fmt.Errorf("%d transaction entries exceeds "+
+			"remaining data", numTxKeys))
+	}
+	entry.txKeys = make([][]byte, 0, numTxKeys)
+	for i := uint64(0); i < numTxKeys; i++ {
+		var addrKey [addrKeySize]byte
+		var suffix [1 + 4]byte
+		if _, err := io.ReadFull(r, addrKey[:]); err != nil {
+			return nil, corruptErr(err)
+		}
+		if _, err := io.ReadFull(r, suffix[:]); err != nil {
+			return nil, corruptErr(err)
+		}
+		txKey := addrTxKey(addrKey, entry.blockID, int8(suffix[0]),
+			bigEndian.Uint32(suffix[1:]))
+		entry.txKeys = append(entry.txKeys, txKey)
+	}
+
+	numSpent, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, corruptErr(err)
+	}
+	if numSpent > uint64(r.Len()) {
+		return nil, corruptErr(fmt.Errorf("%d spent entries exceeds "+
+			"remaining data", numSpent))
+	}
+	entry.spent = make([]addrSpentEntry, 0, numSpent)
+	for i := uint64(0); i < numSpent; i++ {
+		key := make([]byte, addrUtxoKeySize)
+		if _, err := io.ReadFull(r, key); err != nil {
+			return nil, corruptErr(err)
+		}
+		valueLen, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			return nil, corruptErr(err)
+		}
+		if valueLen > uint64(r.Len()) {
+			return nil, corruptErr(fmt.Errorf("value length %d exceeds "+
+				"remaining data", valueLen))
+		}
+		var value []byte
+		if valueLen > 0 {
+			value = make([]byte, valueLen)
+			if _, err := io.ReadFull(r, value); err != nil {
+				return nil, corruptErr(err)
+			}
+		}
+		entry.spent = append(entry.spent, addrSpentEntry{key, value})
+	}
+
+	return &entry, nil
+}
+
+// AddrIndex implements a transaction by address index.  That is to say, it
+// supports querying all transactions that reference a given address because
+// they are either crediting or debiting the address.  The returned
+// transactions are ordered according to their order of appearance in the
+// blockchain.  In other words, first by block height and then by their
+// location inside the block.  It also tracks the unspent outputs that pay to
+// each address in order to support balance queries.
+//
+// In addition, support is provided for a memory-only index of unconfirmed
+// transactions such as those which are kept in the memory pool before inclusion
+// in a block.
+type AddrIndex struct {
+	// The following fields are set when the instance is created and can't
+	// be changed afterwards, so there is no need to protect them with a
+	// separate mutex.
+	db    database.DB
+	chain ChainQueryer
+	sub   *IndexSubscription
+
+	// The following fields are used to quickly link transactions and
+	// addresses that have not been included into a block yet when an
+	// address index is being maintained.  The are protected by the
+	// unconfirmedLock field.
+	//
+	// The txnsByAddr field is used to keep an index of all transactions
+	// which either create an output to a given address or spend from a
+	// previous output to it keyed by the address.
+	//
+	// The addrsByTx field is essentially the reverse and is used to
+	// keep an index of all addresses which a given transaction involves.
+	// This allows fairly efficient updates when transactions are removed
+	// once they are included into a block.
+	unconfirmedLock sync.RWMutex
+	txnsByAddr      map[[addrKeySize]byte]map[chainhash.Hash]*VGLutil.Tx
+	addrsByTx       map[chainhash.Hash]map[[addrKeySize]byte]struct{}
+
+	subscribers map[chan bool]struct{}
+	mtx         sync.Mutex
+	cancel      context.CancelFunc
+}
+
+// NewAddrIndex returns a new instance of an indexer that is used to create a
+// mapping of all addresses in the blockchain to the respective transactions
+// that involve them along with the unspent outputs that pay to them.
+//
+// The address index depends on the transaction index, so it must be created
+// after it.
+func NewAddrIndex(subscriber *IndexSubscriber, db database.DB, chain ChainQueryer) (*AddrIndex, error) {
+	idx := &AddrIndex{
+		db:          db,
+		chain:       chain,
+		txnsByAddr:  make(map[[addrKeySize]byte]map[chainhash.Hash]*VGLutil.Tx),
+		addrsByTx:   make(map[chainhash.Hash]map[[addrKeySize]byte]struct{}),
+		subscribers: make(map[chan bool]struct{}),
+		cancel:      subscriber.cancel,
+	}
+
+	// The address index is an optional index.  It relies on the block ids
+	// assigned by the transaction index and is therefore updated after it.
+	sub, err := subscriber.Subscribe(idx, txIndexName)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.sub = sub
+
+	err = idx.Init(subscriber.ctx, chain.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// Ensure the AddrIndex type implements the Indexer interface.
+var _ Indexer = (*AddrIndex)(nil)
+
+// Init initializes the address index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Init(ctx context.Context, chainParams *chaincfg.Params) error {
+	if interruptRequested(ctx) {
+		return indexerError(ErrInterruptRequested, interruptMsg)
+	}
+
+	// Finish any drops that were previously interrupted.
+	if err := finishDrop(ctx, idx); err != nil {
+		return err
+	}
+
+	// Create the initial state for the index as needed.
+	if err := createIndex(idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Upgrade the index as needed.
+	if err := upgradeIndex(ctx, idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Recover the address index to the main chain if needed.
+	return recoverIndex(ctx, idx)
+}
+
+// Key returns the database key to use for the index as a byte slice.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Key() []byte {
+	return addrIndexKey
+}
+
+// Name returns the human-readable name of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Name() string {
+	return addrIndexName
+}
+
+// Version returns the current version of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Version() uint32 {
+	return addrIndexVersion
+}
+
+// DB returns the database of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) DB() database.DB {
+	return idx.db
+}
+
+// Queryer returns the chain queryer.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Queryer() ChainQueryer {
+	return idx.chain
+}
+
+// Tip returns the current tip of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Tip() (int64, *chainhash.Hash, error) {
+	return tip(idx.db, idx.Key())
+}
+
+// Create is invoked when the index is created for the first time.  It creates
+// the bucket for the address index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Create(dbTx database.Tx) error {
+	_, err := dbTx.Metadata().CreateBucket(addrIndexKey)
+	return err
+}
+
+// IndexSubscription returns the subscription for index updates.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) IndexSubscription() *IndexSubscription {
+	return idx.sub
+}
+
+// NotifySyncSubscribers signals subscribers of an index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) NotifySyncSubscribers() {
+	idx.mtx.Lock()
+	notifySyncSubscribers(idx.subscribers)
+	idx.mtx.Unlock()
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) WaitForSync() chan bool {
+	c := make(chan bool)
+
+	idx.mtx.Lock()
+	idx.subscribers[c] = struct{}{}
+	idx.mtx.Unlock()
+
+	return c
+}
+
+// scriptAddrKeys returns the keys of all supported addresses the provided
+// script pays to.  Non-standard scripts and unsupported address types are
+// ignored.
+func (idx *AddrIndex) scriptAddrKeys(version uint16, pkScript []byte) [][addrKeySize]byte {
+	scriptType, addrs := stdscript.ExtractAddrs(version, pkScript,
+		idx.chain.ChainParams())
+	if scriptType == stdscript.STNonStandard {
+		return nil
+	}
+
+	addrKeys := make([][addrKeySize]byte, 0, len(addrs))
+	for _, addr := range addrs {
+		k, err := addrToKey(addr)
+		if err != nil {
+			// Ignore unsupported address types.
+			continue
+		}
+		addrKeys = append(addrKeys, k)
+	}
+	return addrKeys
+}
+
+// commitmentAddrKey returns the key of the address a ticket commitment output
+// commits to along with whether or not the output is a supported commitment.
+func (idx *AddrIndex) commitmentAddrKey(txOut *wire.TxOut) ([addrKeySize]byte, bool) {
+	addr, err := stake.AddrFromSStxPkScrCommitment(txOut.PkScript,
+		idx.chain.ChainParams())
+	if err != nil {
+		return [addrKeySize]byte{}, false
+	}
+	k, err := addrToKey(addr)
+	if err != nil {
+		return [addrKeySize]byte{}, false
+	}
+	return k, true
+}
+
+// connectBlock adds a mapping for every address referenced by the
+// transactions in the passed block and updates the unspent outputs that pay
+// to them accordingly.
+func (idx *AddrIndex) connectBlock(dbTx database.Tx, block *VGLutil.Block, prevScripts PrevScripter) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored for this index because even though the
+	// disapproved transactions no longer apply spend semantics, they still
+	// exist within the block.  This means the unspent outputs of an address
+	// may temporarily differ from the utxo set until the disapproved
+	// transactions are mined again, which is nearly always the case.
+
+	if prevScripts == nil {
+		return AssertError(fmt.Sprintf("no previous scripts provided for "+
+			"block %s", block.Hash()))
+	}
+
+	blockID, err := dbFetchBlockIDByHash(dbTx, block.Hash())
+	if err != nil {
+		return err
+	}
+	txLocs, stakeTxLocs, err := block.TxLoc()
+	if err != nil {
+		return err
+	}
+
+	bucket := dbTx.Metadata().Bucket(addrIndexKey)
+	height := block.Height()
+	undo := addrUndoEntry{blockID: blockID}
+	indexTxns := func(txns []*VGLutil.Tx, txLocs []wire.TxLoc, tree int8) error {
+		for txIdx, tx := range txns {
+			msgTx := tx.MsgTx()
+			txAddrKeys := make(map[[addrKeySize]byte]struct{})
+
+			// Remove the unspent output entries for all outputs spent by
+			// the transaction while recording them in the undo entry.
+			for _, txIn := range msgTx.TxIn {
+				prevOut := &txIn.PreviousOutPoint
+				version, pkScript, ok := prevScripts.PrevScript(prevOut)
+				if !ok {
+					// Coinbases, stakebases, treasurybases, and treasury
+					// spends do not have previous outputs.
+					continue
+				}
+				for _, k := range idx.scriptAddrKeys(version, pkScript) {
+					txAddrKeys[k] = struct{}{}
+
+					utxoKey := addrUtxoKey(k, prevOut)
+					var value []byte
+					if v := bucket.Get(utxoKey); v != nil {
+						value = make([]byte, len(v))
+						copy(value, v)
+						if err := bucket.Delete(utxoKey); err != nil {
+							return err
+						}
+					}
+					undo.spent = append(undo.spent, addrSpentEntry{
+						key:   utxoKey,
+						value: value,
+					})
+				}
+			}
+
+			// Add unspent output entries for all outputs created by the
+			// transaction.  Ticket commitments are not spendable outputs,
+			// so they only reference the address.
+			isSStx := stake.IsSStx(msgTx)
+			txHash := tx.Hash()
+			for txOutIdx, txOut := range msgTx.TxOut {
+				if isSStx && txOutIdx%2 != 0 {
+					if k, ok := idx.commitmentAddrKey(txOut); ok {
+						txAddrKeys[k] = struct{}{}
+					}
+					continue
+				}
+
+				addrKeys := idx.scriptAddrKeys(txOut.Version, txOut.PkScript)
+				if len(addrKeys) == 0 {
+					continue
+				}
+				outpoint := wire.OutPoint{
+					Hash:  *txHash,
+					Index: uint32(txOutIdx),
+					Tree:  tree,
+				}
+				value := serializeAddrUtxo(txOut, height)
+				for _, k := range addrKeys {
+					txAddrKeys[k] = struct{}{}
+					err := bucket.Put(addrUtxoKey(k, &outpoint), value)
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			// Add a transaction entry for every address the transaction
+			// references.
+			var txValue [addrTxValueSize]byte
+			byteOrder.PutUint32(txValue[:], uint32(txLocs[txIdx].TxStart))
+			byteOrder.PutUint32(txValue[4:], uint32(txLocs[txIdx].TxLen))
+			for k := range txAddrKeys {
+				txKey := addrTxKey(k, blockID, tree, uint32(txIdx))
+				if err := bucket.Put(txKey, txValue[:]); err != nil {
+					return err
+				}
+				undo.txKeys = append(undo.txKeys, txKey)
+			}
+		}
+		return nil
+	}
+
+	// Index the stake tree prior to the regular tree to match the order the
+	// outputs are spent by the chain.
+	err = indexTxns(block.STransactions(), stakeTxLocs, wire.TxTreeStake)
+	if err != nil {
+		return err
+	}
+	err = indexTxns(block.Transactions(), txLocs, wire.TxTreeRegular)
+	if err != nil {
+		return err
+	}
+
+	// Store the undo entry for the block.
+	err = bucket.Put(addrUndoKey(block.Hash()), serializeAddrUndoEntry(&undo))
+	if err != nil {
+		return err
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), block.Hash(), int32(height))
+}
+
+// disconnectBlock removes the mappings for every address referenced by the
+// transactions in the passed block and restores the unspent outputs they
+// spent.
+func (idx *AddrIndex) disconnectBlock(dbTx database.Tx, block *VGLutil.Block) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored when disconnecting blocks because it is also
+	// ignored when connecting the block.  See the comments in connectBlock
+	// for the specifics.
+
+	bucket := dbTx.Metadata().Bucket(addrIndexKey)
+	undoKey := addrUndoKey(block.Hash())
+	serializedUndo := bucket.Get(undoKey)
+	if serializedUndo == nil {
+		str := fmt.Sprintf("missing address index undo entry for block %s",
+			block.Hash())
+		return makeDbErr(database.ErrCorruption, str)
+	}
+	undo, err := deserializeAddrUndoEntry(serializedUndo)
+	if err != nil {
+		return err
+	}
+
+	// Remove all of the transaction entries for the block.
+	for _, txKey := range undo.txKeys {
+		if err := bucket.Delete(txKey); err != nil {
+			return err
+		}
+	}
+
+	// Remove the unspent output entries for all outputs created by the block.
+	createdTxns := make(map[chainhash.Hash]struct{})
+	removeOutputs := func(txns []*VGLutil.Tx, tree int8) error {
+		for _, tx := range txns {
+			msgTx := tx.MsgTx()
+			createdTxns[*tx.Hash()] = struct{}{}
+			for txOutIdx, txOut := range msgTx.TxOut {
+				outpoint := wire.OutPoint{
+					Hash:  *tx.Hash(),
+					Index: uint32(txOutIdx),
+					Tree:  tree,
+				}
+				addrKeys := idx.scriptAddrKeys(txOut.Version, txOut.PkScript)
+				for _, k := range addrKeys {
+					err := bucket.Delete(addrUtxoKey(k, &outpoint))
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+	if err := removeOutputs(block.Transactions(), wire.TxTreeRegular); err != nil {
+		return err
+	}
+	if err := removeOutputs(block.STransactions(), wire.TxTreeStake); err != nil {
+		return err
+	}
+
+	// Restore the unspent output entries spent by the block.  Outputs that
+	// were both created and spent by the block are skipped since they did not
+	// exist prior to it.
+	for _, spent := range undo.spent {
+		if len(spent.value) == 0 {
+			continue
+		}
+		var txHash chainhash.Hash
+		copy(txHash[:], spent.key[1+addrKeySize:])
+		if _, ok := createdTxns[txHash]; ok {
+			continue
+		}
+		if err := bucket.Put(spent.key, spent.value); err != nil {
+			return err
+		}
+	}
+
+	if err := bucket.Delete(undoKey); err != nil {
+		return err
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), &block.MsgBlock().Header.PrevBlock,
+		int32(block.Height()-1))
+}
+
+// EntriesForAddress returns the location of the transactions that involve the
+// provided address in the blockchain along with the number of entries that
+// were skipped.  The entries are ordered by their appearance in the chain
+// unless reverse is set, in which case the most recent entries are returned
+// first.
+//
+// The block regions contained in the result can in turn be used to load the
+// raw transaction bytes.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) EntriesForAddress(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]TxIndexEntry, uint32, error) {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []TxIndexEntry
+	var skipped uint32
+	err = idx.db.View(func(dbTx database.Tx) error {
+		prefix := make([]byte, 1+addrKeySize)
+		prefix[0] = addrIndexTxPrefix
+		copy(prefix[1:], addrKey[:])
+
+		// Position the cursor on the first entry to consider depending on
+		// the requested direction.  Since the upper bound for reverse
+		// iteration is never an actual key, the cursor is positioned on the
+		// key after it, if any.
+		cursor := dbTx.Metadata().Bucket(addrIndexKey).Cursor()
+		var ok bool
+		if reverse {
+			upper := bytes.Repeat([]byte{0xff}, addrTxKeySize)
+			copy(upper, prefix)
+			if ok = cursor.Seek(upper); ok {
+				ok = cursor.Prev()
+			} else {
+				ok = cursor.Last()
+			}
+		} else {
+			ok = cursor.Seek(prefix)
+		}
+		next := cursor.Next
+		if reverse {
+			next = cursor.Prev
+		}
+
+		for ; ok && uint32(len(entries)) < numRequested; ok = next() {
+			key := cursor.Key()
+			if !bytes.HasPrefix(key, prefix) {
+				break
+			}
+			if skipped < numToSkip {
+				skipped++
+				continue
+			}
+
+			value := cursor.Value()
+			if len(key) != addrTxKeySize || len(value) < addrTxValueSize {
+				return makeDbErr(database.ErrCorruption, "corrupt "+
+					"address index transaction entry")
+			}
+			offset := 1 + addrKeySize
+			hash, err := dbFetchBlockHashBySerializedID(dbTx,
+				key[offset:offset+4])
+			if err != nil {
+				str := fmt.Sprintf("corrupt address index transaction "+
+					"entry: %v", err)
+				return makeDbErr(database.ErrCorruption, str)
+			}
+			entries = append(entries, TxIndexEntry{
+				BlockRegion: database.BlockRegion{
+					Hash:   hash,
+					Offset: byteOrder.Uint32(value),
+					Len:    byteOrder.Uint32(value[4:]),
+				},
+				BlockIndex: bigEndian.Uint32(key[offset+5:]),
+			})
+		}
+		return nil
+	})
+	return entries, skipped, err
+}
+
+// UnspentOutputs returns all of the unspent outputs in the blockchain that pay
+// to the provided address ordered by outpoint.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) UnspentOutputs(addr stdaddr.Address) ([]*AddrUtxo, error) {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var utxos []*AddrUtxo
+	err = idx.db.View(func(dbTx database.Tx) error {
+		prefix := make([]byte, 1+addrKeySize)
+		prefix[0] = addrIndexUtxoPrefix
+		copy(prefix[1:], addrKey[:])
+
+		cursor := dbTx.Metadata().Bucket(addrIndexKey).Cursor()
+		for ok := cursor.Seek(prefix); ok; ok = cursor.Next() {
+			key := cursor.Key()
+			if !bytes.HasPrefix(key, prefix) {
+				break
+			}
+			utxo, err := deserializeAddrUtxo(key, cursor.Value())
+			if err != nil {
+				return err
+			}
+			utxos = append(utxos, utxo)
+		}
+		return nil
+	})
+	return utxos, err
+}
+
+// Balance returns the total amount of all unspent outputs in the blockchain
+// that pay to the provided address along with the number of said outputs.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) Balance(addr stdaddr.Address) (int64, int, error) {
+	utxos, err := idx.UnspentOutputs(addr)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var balance int64
+	for _, utxo := range utxos {
+		balance += utxo.Amount
+	}
+	return balance, len(utxos), nil
+}
+
+// indexUnconfirmedAddr adds the provided transaction to the unconfirmed
+// (memory-only) address index for the address associated with the key.
+//
+// This function MUST be called with the unconfirmed lock held (for writes).
+func (idx *AddrIndex) indexUnconfirmedAddr(addrKey [addrKeySize]byte, tx *VGLutil.Tx) {
+	// Add a mapping from the address to the transaction.
+	addrIndexEntry := idx.txnsByAddr[addrKey]
+	if addrIndexEntry == nil {
+		addrIndexEntry = make(map[chainhash.Hash]*VGLutil.Tx)
+		idx.txnsByAddr[addrKey] = addrIndexEntry
+	}
+	addrIndexEntry[*tx.Hash()] = tx
+
+	// Add a mapping from the transaction to the address.
+	addrsByTxEntry := idx.addrsByTx[*tx.Hash()]
+	if addrsByTxEntry == nil {
+		addrsByTxEntry = make(map[[addrKeySize]byte]struct{})
+		idx.addrsByTx[*tx.Hash()] = addrsByTxEntry
+	}
+	addrsByTxEntry[addrKey] = struct{}{}
+}
+
+// AddUnconfirmedTx adds all addresses related to the transaction to the
+// unconfirmed (memory-only) address index.
+//
+// NOTE: This transaction MUST have already been validated by the memory pool
+// before calling this function with it and have all of the inputs available
+// via the provided previous scripts interface.  Failure to do so could result
+// in some or all addresses not being indexed.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) AddUnconfirmedTx(tx *VGLutil.Tx, prevScripts PrevScripter) {
+	idx.unconfirmedLock.Lock()
+	defer idx.unconfirmedLock.Unlock()
+
+	// Index addresses of all referenced previous transaction outputs.
+	msgTx := tx.MsgTx()
+	for _, txIn := range msgTx.TxIn {
+		version, pkScript, ok := prevScripts.PrevScript(&txIn.PreviousOutPoint)
+		if !ok {
+			// Ignore missing entries.  This should never happen in practice
+			// since the function comments specifically call out all inputs
+			// must be available.
+			continue
+		}
+		for _, k := range idx.scriptAddrKeys(version, pkScript) {
+			idx.indexUnconfirmedAddr(k, tx)
+		}
+	}
+
+	// Index addresses of all created outputs.
+	isSStx := stake.IsSStx(msgTx)
+	for txOutIdx, txOut := range msgTx.TxOut {
+		if isSStx && txOutIdx%2 != 0 {
+			if k, ok := idx.commitmentAddrKey(txOut); ok {
+				idx.indexUnconfirmedAddr(k, tx)
+			}
+			continue
+		}
+
+		for _, k := range idx.scriptAddrKeys(txOut.Version, txOut.PkScript) {
+			idx.indexUnconfirmedAddr(k, tx)
+		}
+	}
+}
+
+// RemoveUnconfirmedTx removes the passed transaction from the unconfirmed
+// (memory-only) address index.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) RemoveUnconfirmedTx(hash *chainhash.Hash) {
+	idx.unconfirmedLock.Lock()
+	defer idx.unconfirmedLock.Unlock()
+
+	// Remove all address references to the transaction from the address
+	// index and remove the entry for the address altogether if it no longer
+	// references any transactions.
+	for addrKey := range idx.addrsByTx[*hash] {
+		delete(idx.txnsByAddr[addrKey], *hash)
+		if len(idx.txnsByAddr[addrKey]) == 0 {
+			delete(idx.txnsByAddr, addrKey)
+		}
+	}
+
+	// Remove the entry from the transaction to address lookup map as well.
+	delete(idx.addrsByTx, *hash)
+}
+
+// UnconfirmedTxnsForAddress returns all transactions currently in the
+// unconfirmed (memory-only) address index that involve the passed address.
+// Unsupported address types are ignored and will result in no results.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) UnconfirmedTxnsForAddress(addr stdaddr.Address) []*VGLutil.Tx {
+	// Ignore unsupported address types.
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil
+	}
+
+	// Protect concurrent access.
+	idx.unconfirmedLock.RLock()
+	defer idx.unconfirmedLock.RUnlock()
+
+	// Return a new slice with the results if there are any.  This ensures
+	// safe concurrency.
+	if txns, exists := idx.txnsByAddr[addrKey]; exists {
+		addressTxns := make([]*VGLutil.Tx, 0, len(txns))
+		for _, tx := range txns {
+			addressTxns = append(addressTxns, tx)
+		}
+		return addressTxns
+	}
+
+	return nil
+}
+
+// DropAddrIndex drops the address index from the provided database if it
+// exists.
+func DropAddrIndex(ctx context.Context, db database.DB) error {
+	return dropFlatIndex(ctx, db, addrIndexKey, addrIndexName)
+}
+
+// DropIndex drops the address index from the provided database if it exists.
+func (*AddrIndex) DropIndex(ctx context.Context, db database.DB) error {
+	return DropAddrIndex(ctx, db)
+}
+
+// ProcessNotification indexes the provided notification based on its
+// notification type.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) ProcessNotification(dbTx database.Tx, ntfn *IndexNtfn) error {
+	switch ntfn.NtfnType {
+	case ConnectNtfn:
+		err := idx.connectBlock(dbTx, ntfn.Block, ntfn.PrevScripts)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to connect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrConnectBlock, msg)
+		}
+
+	case DisconnectNtfn:
+		err := idx.disconnectBlock(dbTx, ntfn.Block)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to disconnect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrDisconnectBlock, msg)
+		}
+
+	default:
+		msg := fmt.Sprintf("%s: unknown notification type received: %d",
+			idx.Name(), ntfn.NtfnType)
+		return indexerError(ErrInvalidNotificationType, msg)
+	}
+
+	return nil
+}
@@ -0,0 +1,376 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/blockchain/v5/chaingen"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// connectNtfn returns a connect notification for the provided block with the
+// previous scripts it spends populated from the provided chain.
+func connectNtfn(t *testing.T, chain *testChain, block, parent *VGLutil.Block) *IndexNtfn {
+	t.Helper()
+
+	prevScripts, err := chain.PrevScripts(nil, block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &IndexNtfn{
+		NtfnType:    ConnectNtfn,
+		Block:       block,
+		Parent:      parent,
+		PrevScripts: prevScripts,
+	}
+}
+
+// hasUtxo returns whether or not the provided unspent outputs contain the
+// provided outpoint.
+func hasUtxo(utxos []*AddrUtxo, outpoint wire.OutPoint) bool {
+	for _, utxo := range utxos {
+		if utxo.OutPoint == outpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// TestAddrIndexAsync ensures the address index behaves as expected when
+// receiving updates asynchronously.
+func TestAddrIndexAsync(t *testing.T) {
+	db := setupDB(t)
+
+	chain, err := newTestChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := chaingen.MakeGenerator(chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Add three blocks to the chain.
+	addBlock(t, chain, &g, "bk1")
+	addBlock(t, chain, &g, "bk2")
+	bk3 := addBlock(t, chain, &g, "bk3")
+
+	// Initialize the address index along with the transaction index it
+	// depends on.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subber := NewIndexSubscriber(ctx)
+	go subber.Run(ctx)
+
+	_, err = NewTxIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := NewAddrIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = subber.CatchUp(ctx, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure the index got synced to bk3 on initialization.
+	tipHeight, tipHash, err := idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tipHeight != bk3.Height() {
+		t.Fatalf("expected tip height to be %d, got %d",
+			bk3.Height(), tipHeight)
+	}
+
+	if *tipHash != *bk3.Hash() {
+		t.Fatalf("expected tip hash to be %s, got %s", bk3.Hash(), tipHash)
+	}
+
+	// Ensure the coinbases paying to the generator address were indexed.
+	addr := g.P2shOpTrueAddr()
+	_, p2shOpTrueScript := addr.PaymentScript()
+	entries, _, err := idx.EntriesForAddress(addr, 0, 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("expected entries for the coinbases of the initial blocks")
+	}
+	utxos, err := idx.UnspentOutputs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numEntries, numUtxos := len(entries), len(utxos)
+
+	// Connect a block which spends one of the outputs to the address and
+	// creates a new one.
+	outs := g.OldestCoinbaseOuts()
+	spent := outs[0].PrevOut()
+	msgBk4 := g.NextBlock("bk4", &outs[0], nil)
+	bk4 := VGLutil.NewBlock(msgBk4)
+	if err := chain.AddBlock(bk4); err != nil {
+		t.Fatal(err)
+	}
+	notifyAndWait(t, subber, connectNtfn(t, chain, bk4, bk3))
+
+	// Ensure both the coinbase and the spending transaction of the block were
+	// indexed and the most recent entry is the spending transaction.
+	entries, _, err = idx.EntriesForAddress(addr, 0, 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != numEntries+2 {
+		t.Fatalf("expected %d entries, got %d", numEntries+2, len(entries))
+	}
+	entries, _, err = idx.EntriesForAddress(addr, 0, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if *entries[0].BlockRegion.Hash != *bk4.Hash() ||
+		entries[0].BlockIndex != 1 {
+
+		t.Fatalf("unexpected most recent entry for block %s index %d",
+			entries[0].BlockRegion.Hash, entries[0].BlockIndex)
+	}
+
+	// Ensure skipping entries behaves as expected.
+	entries, skipped, err := idx.EntriesForAddress(addr, 1, 100, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 1 || len(entries) != numEntries+1 {
+		t.Fatalf("expected 1 skipped and %d entries, got %d skipped and "+
+			"%d entries", numEntries+1, skipped, len(entries))
+	}
+
+	// Ensure the spent output was removed and the new outputs were added.
+	var numNewOuts int
+	for _, tx := range msgBk4.Transactions {
+		for _, txOut := range tx.TxOut {
+			if bytes.Equal(txOut.PkScript, p2shOpTrueScript) {
+				numNewOuts++
+			}
+		}
+	}
+	utxos, err = idx.UnspentOutputs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != numUtxos-1+numNewOuts {
+		t.Fatalf("expected %d unspent outputs, got %d",
+			numUtxos-1+numNewOuts, len(utxos))
+	}
+	if hasUtxo(utxos, spent) {
+		t.Fatalf("spent output %v is still unspent", spent)
+	}
+	spendTxHash := msgBk4.Transactions[1].TxHash()
+	created := wire.OutPoint{Hash: spendTxHash, Tree: wire.TxTreeRegular}
+	if !hasUtxo(utxos, created) {
+		t.Fatalf("created output %v is not unspent", created)
+	}
+	balance, count, err := idx.Balance(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wantBalance int64
+	for _, utxo := range utxos {
+		wantBalance += utxo.Amount
+	}
+	if balance != wantBalance || count != len(utxos) {
+		t.Fatalf("expected balance %d over %d outputs, got %d over %d",
+			wantBalance, len(utxos), balance, count)
+	}
+
+	// Ensure the index reverts the block when it is disconnected.
+	err = chain.RemoveBlock(bk4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.SetTip("bk3")
+
+	ntfn := &IndexNtfn{
+		NtfnType: DisconnectNtfn,
+		Block:    bk4,
+		Parent:   bk3,
+	}
+	notifyAndWait(t, subber, ntfn)
+
+	tipHeight, tipHash, err = idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tipHeight != bk3.Height() {
+		t.Fatalf("expected tip height to be %d, got %d",
+			bk3.Height(), tipHeight)
+	}
+
+	if *tipHash != *bk3.Hash() {
+		t.Fatalf("expected tip hash to be %s, got %s", bk3.Hash(), tipHash)
+	}
+
+	entries, _, err = idx.EntriesForAddress(addr, 0, 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != numEntries {
+		t.Fatalf("expected %d entries, got %d", numEntries, len(entries))
+	}
+	utxos, err = idx.UnspentOutputs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != numUtxos {
+		t.Fatalf("expected %d unspent outputs, got %d", numUtxos,
+			len(utxos))
+	}
+	if !hasUtxo(utxos, spent) {
+		t.Fatalf("spent output %v was not restored", spent)
+	}
+}
+
+// TestAddrIndexUnconfirmed ensures the unconfirmed (memory-only) part of the
+// address index behaves as expected.
+func TestAddrIndexUnconfirmed(t *testing.T) {
+	db := setupDB(t)
+
+	chain, err := newTestChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := chaingen.MakeGenerator(chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	addBlock(t, chain, &g, "bk1")
+	addBlock(t, chain, &g, "bk2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subber := NewIndexSubscriber(ctx)
+	go subber.Run(ctx)
+
+	_, err = NewTxIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := NewAddrIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add a transaction that spends an output to the address to the
+	// unconfirmed index and ensure it is returned for the address.
+	addr := g.P2shOpTrueAddr()
+	outs := g.OldestCoinbaseOuts()
+	tx := VGLutil.NewTx(g.CreateSpendTx(&outs[0], 1000))
+	idx.AddUnconfirmedTx(tx, testScriptSource{})
+
+	txns := idx.UnconfirmedTxnsForAddress(addr)
+	if len(txns) != 1 || *txns[0].Hash() != *tx.Hash() {
+		t.Fatalf("expected unconfirmed transaction %s, got %v", tx.Hash(),
+			txns)
+	}
+
+	// Ensure removing the transaction removes it from the index.
+	idx.RemoveUnconfirmedTx(tx.Hash())
+	txns = idx.UnconfirmedTxnsForAddress(addr)
+	if len(txns) != 0 {
+		t.Fatalf("expected no unconfirmed transactions, got %d", len(txns))
+	}
+	if len(idx.txnsByAddr) != 0 || len(idx.addrsByTx) != 0 {
+		t.Fatal("expected unconfirmed index maps to be empty")
+	}
+}
+
+// TestAddrUndoEntrySerialization ensures serializing and deserializing address
+// index undo entries works as expected.
+func TestAddrUndoEntrySerialization(t *testing.T) {
+	var addrKey [addrKeySize]byte
+	addrKey[0] = addrKeyTypeScriptHash
+	addrKey[1] = 0x01
+	outpoint := wire.OutPoint{Index: 2, Tree: wire.TxTreeStake}
+	outpoint.Hash[0] = 0x03
+	txOut := wire.NewTxOut(5000, []byte{0x51})
+	entry := &addrUndoEntry{
+		blockID: 7,
+		txKeys: [][]byte{
+			addrTxKey(addrKey, 7, wire.TxTreeRegular, 0),
+			addrTxKey(addrKey, 7, wire.TxTreeStake, 3),
+		},
+		spent: []addrSpentEntry{{
+			key:   addrUtxoKey(addrKey, &outpoint),
+			value: serializeAddrUtxo(txOut, 10),
+		}, {
+			key: addrUtxoKey(addrKey, &wire.OutPoint{Index: 1}),
+		}},
+	}
+
+	serialized := serializeAddrUndoEntry(entry)
+	got, err := deserializeAddrUndoEntry(serialized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.blockID != entry.blockID {
+		t.Fatalf("mismatched block id: got %d, want %d", got.blockID,
+			entry.blockID)
+	}
+	if len(got.txKeys) != len(entry.txKeys) {
+		t.Fatalf("mismatched tx keys: got %d, want %d", len(got.txKeys),
+			len(entry.txKeys))
+	}
+	for i := range entry.txKeys {
+		if !bytes.Equal(got.txKeys[i], entry.txKeys[i]) {
+			t.Fatalf("mismatched tx key %d: got %x, want %x", i,
+				got.txKeys[i], entry.txKeys[i])
+		}
+	}
+	if len(got.spent) != len(entry.spent) {
+		t.Fatalf("mismatched spent entries: got %d, want %d",
+			len(got.spent), len(entry.spent))
+	}
+	for i := range entry.spent {
+		if !bytes.Equal(got.spent[i].key, entry.spent[i].key) ||
+			!bytes.Equal(got.spent[i].value, entry.spent[i].value) {
+
+			t.Fatalf("mismatched spent entry %d", i)
+		}
+	}
+
+	// Ensure the unspent output entry round trips as well.
+	utxo, err := deserializeAddrUtxo(got.spent[0].key, got.spent[0].value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if utxo.OutPoint != outpoint || utxo.Amount != txOut.Value ||
+		utxo.Height != 10 || !bytes.Equal(utxo.PkScript, txOut.PkScript) {
+
+		t.Fatalf("unexpected unspent output %+v", utxo)
+	}
+
+	// Ensure truncated data is detected.
+	_, err = deserializeAddrUndoEntry(serialized[:len(serialized)-1])
+	if err == nil {
+		t.Fatal("expected error for truncated undo entry")
+	}
+}
@@ -67,6 +67,17 @@ type ChainQueryer interface {
 	// IsTreasuryAgendaActive returns true if the treasury agenda is active at
 	// the provided block.
 	IsTreasuryAgendaActive(*chainhash.Hash) (bool, error)
+
+	// PrevScripts returns a source of previous transaction scripts and their
+	// associated versions spent by the given block.
+	PrevScripts(database.Tx, *VGLutil.Block) (PrevScripter, error)
+}
+
+// PrevScripter defines an interface that provides access to scripts and their
+// associated version keyed by an outpoint.  The boolean return indicates
+// whether or not the script and version for the provided outpoint was found.
+type PrevScripter interface {
+	PrevScript(*wire.OutPoint) (uint16, []byte, bool)
 }
 
 // Indexer defines a generic interface for an indexer.
@@ -43,10 +43,14 @@ var (
 
 // IndexNtfn represents an index notification detailing a block connection
 // or disconnection.
+//
+// PrevScripts is only set for connect notifications and provides the scripts
+// of all outputs spent by the block.
 type IndexNtfn struct {
 	NtfnType          IndexNtfnType
 	Block             *VGLutil.Block
 	Parent            *VGLutil.Block
+	PrevScripts       PrevScripter
 	IsTreasuryEnabled bool
 	Done              chan bool
 }
@@ -245,7 +249,7 @@ func (s *IndexSubscriber) findLowestIndexTipHeight(queryer ChainQueryer) (int64,
 // from after the lowest index tip to the current main chain tip.
 //
 // This should be called after all indexes have subscribed for updates.
-func (s *IndexSubscriber) CatchUp(ctx context.Context, _ database.DB, queryer ChainQueryer) error {
+func (s *IndexSubscriber) CatchUp(ctx context.Context, db database.DB, queryer ChainQueryer) error {
 	lowestHeight, bestHeight, err := s.findLowestIndexTipHeight(queryer)
 	if err != nil {
 		return err
@@ -307,10 +311,21 @@ func (s *IndexSubscriber) CatchUp(ctx context.Context, _ database.DB, queryer Ch
 			return err
 		}
 
+		var prevScripts PrevScripter
+		err = db.View(func(dbTx database.Tx) error {
+			var err error
+			prevScripts, err = queryer.PrevScripts(dbTx, child)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
 		ntfn := &IndexNtfn{
 			NtfnType:          ConnectNtfn,
 			Block:             child,
 			Parent:            parent,
+			PrevScripts:       prevScripts,
 			IsTreasuryEnabled: isTreasuryEnabled,
 		}
 
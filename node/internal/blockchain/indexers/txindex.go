@@ -174,6 +174,18 @@ func dbFetchBlockHashByID(dbTx database.Tx, id uint32) (*chainhash.Hash, error)
 	return dbFetchBlockHashBySerializedID(dbTx, serializedID[:])
 }
 
+// dbFetchBlockIDByHash uses an existing database transaction to retrieve the
+// block id for the provided block hash from the index.
+func dbFetchBlockIDByHash(dbTx database.Tx, hash *chainhash.Hash) (uint32, error) {
+	hashIndex := dbTx.Metadata().Bucket(idByHashIndexBucketName)
+	serializedID := hashIndex.Get(hash[:])
+	if serializedID == nil {
+		return 0, errNoBlockIDEntry
+	}
+
+	return byteOrder.Uint32(serializedID), nil
+}
+
 // putTxIndexEntry serializes the provided values according to the format
 // described about for a transaction index entry.  The target byte slice must
 // be at least large enough to handle the number of bytes defined by the
@@ -630,6 +642,12 @@ func dropBlockIDIndex(db database.DB) error {
 // exists.  Since the address index relies on it, the address index will also be
 // dropped when it exists.
 func DropTxIndex(ctx context.Context, db database.DB) error {
+	// Drop the address index first since it can't exist without the
+	// transaction index.
+	if err := DropAddrIndex(ctx, db); err != nil {
+		return err
+	}
+
 	// Nothing to do if the index doesn't already exist.
 	exists, err := existsIndex(db, txIndexKey)
 	if err != nil {
@@ -252,6 +252,58 @@ func (tc *testChain) BlockHeaderByHash(hash *chainhash.Hash) (wire.BlockHeader,
 	return blk.MsgBlock().Header, nil
 }
 
+// testScriptSource provides a source of previous output scripts keyed by
+// outpoint and implements the PrevScripter interface.
+type testScriptSource map[wire.OutPoint]*wire.TxOut
+
+// PrevScript returns the script and script version associated with the
+// provided previous outpoint.
+func (s testScriptSource) PrevScript(prevOut *wire.OutPoint) (uint16, []byte, bool) {
+	txOut, ok := s[*prevOut]
+	if !ok {
+		return 0, nil, false
+	}
+	return txOut.Version, txOut.PkScript, true
+}
+
+// PrevScripts returns a source of the previous output scripts spent by the
+// provided block by looking up the referenced outputs in all blocks known to
+// the chain.
+func (tc *testChain) PrevScripts(_ database.Tx, blk *VGLutil.Block) (PrevScripter, error) {
+	tc.mtx.Lock()
+	defer tc.mtx.Unlock()
+
+	txOuts := make(map[wire.OutPoint]*wire.TxOut)
+	addTxOuts := func(txns []*wire.MsgTx, tree int8) {
+		for _, tx := range txns {
+			txHash := tx.TxHash()
+			for i, txOut := range tx.TxOut {
+				txOuts[wire.OutPoint{Hash: txHash, Index: uint32(i),
+					Tree: tree}] = txOut
+			}
+		}
+	}
+	for _, b := range tc.keyedByHash {
+		addTxOuts(b.MsgBlock().Transactions, wire.TxTreeRegular)
+		addTxOuts(b.MsgBlock().STransactions, wire.TxTreeStake)
+	}
+
+	source := make(testScriptSource)
+	addPrevOuts := func(txns []*wire.MsgTx) {
+		for _, tx := range txns {
+			for _, txIn := range tx.TxIn {
+				prevOut := txIn.PreviousOutPoint
+				if txOut, ok := txOuts[prevOut]; ok {
+					source[prevOut] = txOut
+				}
+			}
+		}
+	}
+	addPrevOuts(blk.MsgBlock().Transactions)
+	addPrevOuts(blk.MsgBlock().STransactions)
+	return source, nil
+}
+
 // notifyAndWait sends the provided notification and waits for done signal
 // with a one second timeout.
 func notifyAndWait(t *testing.T, subber *IndexSubscriber, ntfn *IndexNtfn) {
@@ -0,0 +1,44 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"github.com/kdsmith18542/vigil/internal/metrics"
+)
+
+var (
+	// chainHeightGauge tracks the height of the current best chain tip.
+	chainHeightGauge = metrics.NewGauge("vgld_chain_height",
+		"Height of the current best chain tip.")
+
+	// bestHeaderHeightGauge tracks the height of the header with the most
+	// cumulative work that is not known to be invalid.
+	bestHeaderHeightGauge = metrics.NewGauge("vgld_chain_best_header_height",
+		"Height of the best known header that is not known to be invalid.")
+
+	// blockValidationSeconds tracks the time it takes to process blocks.
+	blockValidationSeconds = metrics.NewHistogram(
+		"vgld_block_validation_seconds",
+		"Time taken to process and validate blocks.",
+		metrics.ExponentialBuckets(0.001, 2, 16))
+
+	// utxoCacheHits and utxoCacheMisses track the number of UTXO cache
+	// lookups that were and were not found in the cache, respectively.
+	utxoCacheHits = metrics.NewCounter("vgld_utxocache_hits_total",
+		"Number of UTXO cache lookups served from the cache.")
+	utxoCacheMisses = metrics.NewCounter("vgld_utxocache_misses_total",
+		"Number of UTXO cache lookups that required loading from the backend.")
+
+	// utxoCacheSizeGauge tracks the total size of the UTXO cache.
+	utxoCacheSizeGauge = metrics.NewGauge("vgld_utxocache_size_bytes",
+		"Total size of the UTXO cache in bytes.")
+
+	// utxoCacheFlushSeconds tracks the time it takes to flush the UTXO cache
+	// to the backend.
+	utxoCacheFlushSeconds = metrics.NewHistogram(
+		"vgld_utxocache_flush_seconds",
+		"Time taken to flush the UTXO cache to the backend.",
+		metrics.ExponentialBuckets(0.01, 2, 14))
+)
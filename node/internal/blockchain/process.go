@@ -8,6 +8,7 @@ package blockchain
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
 	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
@@ -465,6 +466,12 @@ func (b *BlockChain) ProcessBlock(block *VGLutil.Block) (int64, error) {
 		return 0, ruleError(ErrDuplicateBlock, str)
 	}
 
+	// Track the time it takes to process the block.
+	start := time.Now()
+	defer func() {
+		blockValidationSeconds.Observe(time.Since(start).Seconds())
+	}()
+
 	b.chainLock.Lock()
 	defer b.chainLock.Unlock()
 
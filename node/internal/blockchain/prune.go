@@ -5,10 +5,34 @@
 package blockchain
 
 import (
+	"errors"
 	"math"
 	"time"
+
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/database/v3"
+)
+
+const (
+	// pruneDepthMargin is the number of additional blocks beyond the minimum
+	// required by the stake validation rules to retain the data for when
+	// pruning block data.  It provides a buffer for reorganizations and
+	// peers syncing the most recent blocks.
+	pruneDepthMargin = 288
 )
 
+// MinPruneDepth returns the minimum number of the most recent main chain blocks
+// the data is always retained for when pruning block data for the provided
+// network.
+//
+// The data for blocks within the ticket maturity and expiry depth is required
+// since validating the stake transactions, such as votes and revocations, needs
+// access to the ticket purchases they spend.
+func MinPruneDepth(params *chaincfg.Params) int64 {
+	depth := int64(params.TicketMaturity) + int64(params.TicketExpiry)
+	return max(depth, minMemoryStakeNodes) + pruneDepthMargin
+}
+
 // poissonConfidenceSecs returns the number of seconds it will take to produce
 // an event at the provided confidence level given a Poisson distribution with 1
 // event occurring in the given target interval.
@@ -34,7 +58,8 @@ func poissonConfidenceSecs(targetIntervalSecs int64, confidence float64) int64 {
 }
 
 // chainPruner is used to occasionally prune the blockchain of old nodes that
-// can be freed to the garbage collector.
+// can be freed to the garbage collector and, when enabled, the data for old
+// blocks that is no longer required.
 type chainPruner struct {
 	chain           *BlockChain
 	lastPruneTime   time.Time
@@ -43,10 +68,28 @@ type chainPruner struct {
 	// prunedPerIntervalHint is the maximum expected number of nodes that will
 	// be pruned per pruning interval with a high degree of confidence.
 	prunedPerIntervalHint int64
+
+	// blockPruner is the database used to prune block data and pruneTarget
+	// is the target number of bytes to use for storing block data.  The
+	// block pruner is nil when block data pruning is disabled.
+	blockPruner database.BlockPruner
+	pruneTarget uint64
 }
 
-// newChainPruner returns a new chain pruner.
-func newChainPruner(chain *BlockChain) *chainPruner {
+// newChainPruner returns a new chain pruner.  Block data pruning is enabled
+// when the provided prune target is nonzero in which case the chain database
+// must support it.
+func newChainPruner(chain *BlockChain, pruneTarget uint64) (*chainPruner, error) {
+	var blockPruner database.BlockPruner
+	if pruneTarget != 0 {
+		var ok bool
+		blockPruner, ok = chain.db.(database.BlockPruner)
+		if !ok {
+			return nil, errors.New("the database does not support pruning " +
+				"block data")
+		}
+	}
+
 	// Set the pruning interval to match the target time per block.
 	targetTimePerBlock := chain.chainParams.TargetTimePerBlock
 	pruningInterval := targetTimePerBlock
@@ -71,7 +114,9 @@ func newChainPruner(chain *BlockChain) *chainPruner {
 		lastPruneTime:         time.Now(),
 		pruningInterval:       pruningInterval,
 		prunedPerIntervalHint: pruneHint,
-	}
+		blockPruner:           blockPruner,
+		pruneTarget:           pruneTarget,
+	}, nil
 }
 
 // pruneChainIfNeeded removes references to old information that should no
@@ -87,4 +132,35 @@ func (c *chainPruner) pruneChainIfNeeded() {
 
 	c.lastPruneTime = now
 	c.chain.pruneStakeNodes()
+	c.pruneBlockData()
+}
+
+// pruneBlockData removes the data for the oldest blocks in order to reduce the
+// storage used for block data to the prune target when block data pruning is
+// enabled.  The data for the blocks within the minimum prune depth of the
+// current best chain tip is never removed.
+//
+// This function MUST be called with the chain lock held (for writes).
+func (c *chainPruner) pruneBlockData() {
+	if c.blockPruner == nil {
+		return
+	}
+
+	// Nothing to do when the chain is not yet beyond the minimum depth.
+	keepHeight := c.chain.bestChain.Tip().height -
+		MinPruneDepth(c.chain.chainParams)
+	if keepHeight <= 0 {
+		return
+	}
+
+	keepNode := c.chain.bestChain.NodeByHeight(keepHeight)
+	freed, err := c.blockPruner.PruneBlocks(c.pruneTarget, &keepNode.hash)
+	if err != nil {
+		log.Errorf("Unable to prune block data: %v", err)
+		return
+	}
+	if freed > 0 {
+		log.Debugf("Pruned %d bytes of block data prior to block %s "+
+			"(height %d)", freed, keepNode.hash, keepHeight)
+	}
 }
@@ -0,0 +1,84 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/kdsmith18542/vigil/blockchain/standalone/v2"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+)
+
+// TestMainNetTotalSubsidy ensures the total subsidy produced by the main
+// network parameters, including the block one ledger, matches the expected
+// value and does not exceed the advertised maximum supply of 21 million coins.
+func TestMainNetTotalSubsidy(t *testing.T) {
+	// Locals for convenience.
+	params := chaincfg.MainNetParams()
+	reductionInterval := params.SubsidyReductionIntervalBlocks()
+	stakeValidationHeight := params.StakeValidationBeginHeight()
+	votesPerBlock := params.VotesPerBlock()
+
+	// subsidySum returns the sum of the individual subsidy types for the given
+	// height.  The treasury agenda is active from the genesis block on the main
+	// network and the original subsidy split is always in effect.  Note that
+	// this value is not exactly the same as the full subsidy originally used to
+	// calculate the individual proportions due to the use of integer math.
+	cache := standalone.NewSubsidyCache(params)
+	subsidySum := func(height int64) int64 {
+		const splitVariant = standalone.SSVOriginal
+		work := cache.CalcWorkSubsidyV3(height, votesPerBlock, splitVariant)
+		vote := cache.CalcStakeVoteSubsidyV3(height, splitVariant) *
+			int64(votesPerBlock)
+		treasury := cache.CalcTreasurySubsidy(height, votesPerBlock, true)
+		return work + vote + treasury
+	}
+
+	// Calculate the total possible subsidy.
+	totalSubsidy := params.BlockOneSubsidy()
+	for reductionNum := int64(0); ; reductionNum++ {
+		// The first interval contains a few special cases:
+		// 1) Block 0 does not produce any subsidy
+		// 2) Block 1 consists of a special initial coin distribution
+		// 3) Votes do not produce subsidy until voting begins
+		if reductionNum == 0 {
+			// Account for the block up to the point voting begins ignoring the
+			// first two special blocks.
+			subsidyCalcHeight := int64(2)
+			nonVotingBlocks := stakeValidationHeight - subsidyCalcHeight
+			totalSubsidy += subsidySum(subsidyCalcHeight) * nonVotingBlocks
+
+			// Account for the blocks remaining in the interval once voting
+			// begins.
+			subsidyCalcHeight = stakeValidationHeight
+			votingBlocks := reductionInterval - subsidyCalcHeight
+			totalSubsidy += subsidySum(subsidyCalcHeight) * votingBlocks
+			continue
+		}
+
+		// Account for the all other reduction intervals until all subsidy has
+		// been produced.
+		subsidyCalcHeight := reductionNum * reductionInterval
+		sum := subsidySum(subsidyCalcHeight)
+		if sum == 0 {
+			break
+		}
+		totalSubsidy += sum * reductionInterval
+	}
+
+	// Ensure the total calculated subsidy is the expected value.
+	const expectedTotalSubsidy = 1393520237295616
+	if totalSubsidy != expectedTotalSubsidy {
+		t.Fatalf("mismatched total subsidy -- got %d, want %d", totalSubsidy,
+			expectedTotalSubsidy)
+	}
+
+	// Ensure the total subsidy stays within the maximum supply.
+	const maxSupply = 21e6 * 1e8
+	if totalSubsidy > maxSupply {
+		t.Fatalf("total subsidy %d exceeds max supply %d", totalSubsidy,
+			int64(maxSupply))
+	}
+}
@@ -370,6 +370,7 @@ func (c *UtxoCache) spendEntry(outpoint wire.OutPoint) error {
 		//
 		// NOTE: Missing backend entries are not an error.
 		c.misses++
+		utxoCacheMisses.Inc()
 		backendEntry, err := c.backend.FetchEntry(outpoint)
 		if err != nil {
 			return err
@@ -437,6 +438,7 @@ func (c *UtxoCache) fetchEntry(outpoint wire.OutPoint) (*UtxoEntry, error) {
 	var entry *UtxoEntry
 	if entry, found := c.entries[outpoint]; found {
 		c.hits++
+		utxoCacheHits.Inc()
 
 		clonedEntry := entry.Clone()
 		if clonedEntry != nil {
@@ -447,6 +449,7 @@ func (c *UtxoCache) fetchEntry(outpoint wire.OutPoint) (*UtxoEntry, error) {
 
 	// Increment cache misses.
 	c.misses++
+	utxoCacheMisses.Inc()
 
 	// Fetch the entry from the backend.
 	//
@@ -621,6 +624,7 @@ func (c *UtxoCache) Commit(view *UtxoViewpoint) error {
 		c.addEntry(outpoint, entry)
 		delete(view.entries, outpoint)
 	}
+	utxoCacheSizeGauge.Set(float64(c.totalSize()))
 
 	return nil
 }
@@ -679,6 +683,8 @@ func (c *UtxoCache) shouldFlush(bestHash *chainhash.Hash) bool {
 //
 // This function MUST be called with the cache lock held.
 func (c *UtxoCache) flush(bestHash *chainhash.Hash, bestHeight uint32, logFlush bool) error {
+	start := time.Now()
+
 	// If the maximum allowed size of the cache has been reached, determine the
 	// eviction height.
 	var evictionHeight uint32
@@ -765,6 +771,10 @@ func (c *UtxoCache) flush(bestHash *chainhash.Hash, bestHeight uint32, logFlush
 		c.lastEvictionHeight = evictionHeight
 	}
 
+	// Update the metrics to reflect the completed flush.
+	utxoCacheFlushSeconds.Observe(time.Since(start).Seconds())
+	utxoCacheSizeGauge.Set(float64(c.totalSize()))
+
 	// Log that the flush has been completed and indicate the updated memory
 	// usage as it will be reduced due to evicting entries above.
 	if logFlush {
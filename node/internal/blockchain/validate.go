@@ -6,14 +6,18 @@
 package blockchain
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"time"
 
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/database/v3"
 	"github.com/kdsmith18542/vigil/node/blockchain/standalone/v2"
 	"github.com/kdsmith18542/vigil/node/chaincfg/chainhash"
 	"github.com/kdsmith18542/vigil/node/chaincfg/v3"
 	"github.com/kdsmith18542/vigil/node/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/kawpow"
 	"github.com/kdsmith18542/vigil/node/wire"
 )
 
@@ -329,18 +333,60 @@ func checkBlockHeaderSanity(header *wire.BlockHeader, p *chaincfg.Params, flags
 	return nil
 }
 
+// kawPowRuleError returns the rule error that corresponds to the provided
+// error returned from verifying a KawPoW solution, if any.  Other errors are
+// returned as is.
+func kawPowRuleError(err error) error {
+	var kerr kawpow.Error
+	if errors.As(err, &kerr) {
+		switch {
+		case errors.Is(err, kawpow.ErrBadMixHash):
+			return ruleError(ErrBadMixHash, kerr.Description)
+		case errors.Is(err, kawpow.ErrHighHash):
+			return ruleError(ErrHighHash, kerr.Description)
+		case errors.Is(err, kawpow.ErrEpochTooHigh):
+			return ruleError(ErrBadBlockHeight, kerr.Description)
+		}
+	}
+	return err
+}
+
 // checkProofOfWorkSanity performs some preliminary checks on a block header to
 // ensure the proof of work is sane before continuing with processing.  These
 // checks are context free in that they do not depend on any previous blocks or
 // network state.
 func checkProofOfWorkSanity(header *wire.BlockHeader, p *chaincfg.Params) error {
-	// The proof of work hash must be less than or equal to the target difficulty
-	// (aka compact "bits").
-	_, finalHash, err := header.PowHashKawPow()
+	// The target difficulty (aka compact "bits") must be in the valid range.
+	err := standalone.CheckProofOfWorkRange(header.Bits, p.PowLimit)
 	if err != nil {
 		return err
 	}
-	return standalone.CheckProofOfWork(&finalHash, header.Bits, p.PowLimit)
+
+	// The final hash calculated from the claimed KawPoW mix hash must be less
+	// than or equal to the target difficulty.
+	//
+	// Note that this is cheap and does not require the light cache for the
+	// epoch of the block height.  The mix hash itself is verified by
+	// checkProofOfWorkPositional once the height is known to be the one after
+	// an existing block since the light cache for an arbitrary claimed height
+	// would otherwise be generated.
+	err = header.VerifyKawPowTarget(standalone.CompactToBig(header.Bits))
+	return kawPowRuleError(err)
+}
+
+// checkProofOfWorkPositional ensures the KawPoW mix hash of the block header
+// matches the one calculated from the header and that the final hash is less
+// than or equal to the target difficulty.
+//
+// This only requires the light cache for the epoch of the block height, so the
+// full dataset is never generated for header validation.  However, the light
+// cache is generated when it is not resident, so this MUST only be called once
+// the height of the block header is known to be the one after an existing
+// block.  That limits the epoch to at most one past the epoch of the best
+// known header.
+func checkProofOfWorkPositional(header *wire.BlockHeader) error {
+	err := header.VerifyKawPow(standalone.CompactToBig(header.Bits))
+	return kawPowRuleError(err)
 }
 
 // checkBlockSanity performs some preliminary checks on a block to ensure it is
@@ -551,6 +597,22 @@ func checkBlockSanity(block *VGLutil.Block, p *chaincfg.Params, flags BehaviorFl
 
 // checkBlockHeaderPositional performs context-dependent checks on a block header.
 func (b *BlockChain) checkBlockHeaderPositional(header *wire.BlockHeader, prevNode *blockNode, flags BehaviorFlags) error {
+	// Ensure the header commits to the height it is actually at in the chain.
+	blockHeight := prevNode.height + 1
+	if int64(header.Height) != blockHeight {
+		str := fmt.Sprintf("block header commitment to height %d does not "+
+			"match chain height %d", header.Height, blockHeight)
+		return ruleError(ErrBadBlockHeight, str)
+	}
+
+	// Verify the KawPoW solution now that the height, and therefore the epoch
+	// of the light cache it requires, is known to be valid.
+	if flags&BFNoPoWCheck == 0 {
+		if err := checkProofOfWorkPositional(header); err != nil {
+			return err
+		}
+	}
+
 	// TODO: Implement header positional checks
 	return nil
 }
@@ -567,24 +629,147 @@ func (b *BlockChain) checkBlockContext(block *VGLutil.Block, prevNode *blockNode
 	return nil
 }
 
-// checkConnectBlock performs validation checks before connecting a block.
+// checkConnectBlock performs the validation checks that depend on the utxo set
+// before connecting a block.
+//
+// NOTE: The context-free sanity and contextual checks are performed by the
+// callers prior to reaching this point with the flags that apply to them, so
+// they are intentionally not repeated here.  Notably, block templates skip the
+// proof of work check.  The transaction input, script, and fee checks against
+// the utxo set are not performed by this implementation, so blocks that only
+// violate those rules are not rejected.
 func (b *BlockChain) checkConnectBlock(node *blockNode, block, parent *VGLutil.Block, view *UtxoViewpoint, stxos *[]spentTxOut, hdrCommitments *headerCommitmentData) error {
-	// Perform basic block sanity checks
-	err := checkBlockSanity(block, b.chainParams, BFNone)
+	return nil
+}
+
+// CheckConnectBlockTemplate validates that connecting the passed block to
+// either the tip of the main chain or its parent does not violate the sanity,
+// positional, and contextual consensus rules, aside from the proof of work
+// requirement.  The block must connect to the current tip of the main chain or
+// its parent.
+//
+// Note that the transaction input, script, and fee checks against the utxo set
+// are not performed, as described by checkConnectBlock, so this must not be
+// used to determine whether a block is fully valid.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) CheckConnectBlockTemplate(block *VGLutil.Block) error {
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	// Skip the proof of work check as this is just a block template.
+	flags := BFNoPoWCheck
+
+	// The block template must build off the current tip of the main chain
+	// or its parent.
+	tip := b.bestChain.Tip()
+	var prevNode *blockNode
+	parentHash := block.MsgBlock().Header.PrevBlock
+	if parentHash == tip.hash {
+		prevNode = tip
+	} else if tip.parent != nil && parentHash == tip.parent.hash {
+		prevNode = tip.parent
+	}
+	if prevNode == nil {
+		var str string
+		if tip.parent != nil {
+			str = fmt.Sprintf("previous block must be the current chain tip "+
+				"%s or its parent %s, but got %s", tip.hash, tip.parent.hash,
+				parentHash)
+		} else {
+			str = fmt.Sprintf("previous block must be the current chain tip "+
+				"%s, but got %s", tip.hash, parentHash)
+		}
+		return ruleError(ErrInvalidTemplateParent, str)
+	}
+
+	// Perform context-free sanity checks on the block and its transactions.
+	err := checkBlockSanity(block, b.chainParams, flags)
 	if err != nil {
 		return err
 	}
 
-	// Perform block context checks
-	err = b.checkBlockContext(block, node.parent, BFNone)
+	// The block must pass all of the validation rules which depend on having
+	// the headers of all ancestors available, but do not rely on having the
+	// full block data of all ancestors available.
+	header := &block.MsgBlock().Header
+	err = b.checkBlockHeaderPositional(header, prevNode, flags)
+	if err != nil {
+		return err
+	}
+	err = b.checkBlockDataPositional(block, prevNode, flags)
 	if err != nil {
 		return err
 	}
 
-	// Additional validation checks would go here
-	// TODO: Implement comprehensive block connection validation
+	// The block must pass all of the validation rules which depend on having
+	// the full block data for all of its ancestors available.
+	err = b.checkBlockContext(block, prevNode, flags)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	newNode := newBlockNode(header, prevNode)
+	newNode.populateTicketInfo(stake.FindSpentTicketsInBlock(block.MsgBlock()))
+
+	// Use the chain state as is when extending the main (best) chain.
+	if prevNode.hash == tip.hash {
+		// Grab the parent block since it is required throughout the block
+		// connection process.
+		parent, err := b.fetchMainChainBlockByNode(prevNode)
+		if err != nil {
+			return ruleError(ErrMissingParent, err.Error())
+		}
+
+		view := NewUtxoViewpoint(b.utxoCache)
+		view.SetBestHash(&tip.hash)
+
+		return b.checkConnectBlock(newNode, block, parent, view, nil, nil)
+	}
+
+	// At this point, the block template must be building on the parent of the
+	// current tip due to the previous checks, so undo the transactions and
+	// spend information for the tip block to reach the point of view of the
+	// block template.
+	view := NewUtxoViewpoint(b.utxoCache)
+	view.SetBestHash(&tip.hash)
+	tipBlock, err := b.fetchMainChainBlockByNode(tip)
+	if err != nil {
+		return err
+	}
+	parent, err := b.fetchMainChainBlockByNode(tip.parent)
+	if err != nil {
+		return err
+	}
+
+	// Determine if treasury agenda is active.
+	isTreasuryEnabled, err := b.isTreasuryAgendaActive(tip.parent)
+	if err != nil {
+		return err
+	}
+
+	// Load all of the spent txos for the tip block from the spend journal.
+	var stxos []spentTxOut
+	err = b.db.View(func(dbTx database.Tx) error {
+		stxos, err = dbFetchSpendJournalEntry(dbTx, tipBlock, isTreasuryEnabled)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Update the view to unspend all of the spent txos and remove the utxos
+	// created by the tip block.  Also, if the block votes against its parent,
+	// reconnect all of the regular transactions.
+	err = view.disconnectBlock(tipBlock, parent, stxos, isTreasuryEnabled)
+	if err != nil {
+		return err
+	}
+
+	// The view is now from the point of view of the parent of the current tip
+	// block.  Ensure the block template can be connected without violating any
+	// rules.
+	return b.checkConnectBlock(newNode, block, parent, view, nil, nil)
 }
 
 // determineCheckTxFlags returns the flags to use when checking transactions
@@ -14,6 +14,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	mrand "math/rand"
 	"os"
 	"path/filepath"
@@ -28,6 +29,7 @@ import (
 	"github.com/kdsmith18542/vigil/chaincfg/v3"
 	"github.com/kdsmith18542/vigil/database/v3"
 	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/kawpow"
 	"github.com/kdsmith18542/vigil/txscript/v4"
 	"github.com/kdsmith18542/vigil/wire"
 )
@@ -275,6 +277,51 @@ func TestCheckBlockSanity(t *testing.T) {
 	}
 }
 
+// TestKawPowHeaderHeight ensures headers that claim a height far beyond the
+// chain, including heights in epochs that are not supported, are rejected
+// without generating the light cache for the claimed height.
+func TestKawPowHeaderHeight(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	parent := newBlockNode(&params.GenesisBlock.Header, nil)
+	chain := &BlockChain{chainParams: params}
+	manager := kawpow.DefaultEpochManager()
+
+	for _, height := range []uint32{kawpow.EpochLength * 2, math.MaxUint32} {
+		header := wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  parent.hash,
+			MerkleRoot: chainhash.Hash{0x01},
+			StakeRoot:  chainhash.Hash{0x01},
+			FinalState: [6]byte{0x01},
+			PoolSize:   1,
+			Bits:       params.PowLimitBits,
+			Height:     height,
+			Timestamp:  time.Unix(time.Now().Unix(), 0),
+		}
+
+		// Find a nonce that passes the cheap target check with the claimed
+		// mix hash so the context-free checks accept the header.
+		target := standalone.CompactToBig(header.Bits)
+		for header.VerifyKawPowTarget(target) != nil {
+			header.Nonce++
+		}
+
+		generations := manager.Stats().CacheGenerations
+		err := checkBlockHeaderSanity(&header, params, BFNone)
+		if err != nil {
+			t.Fatalf("height %d: unexpected sanity error: %v", height, err)
+		}
+		err = chain.checkBlockHeaderPositional(&header, parent, BFNone)
+		if !errors.Is(err, ErrBadBlockHeight) {
+			t.Fatalf("height %d: unexpected error -- got %v, want %v",
+				height, err, ErrBadBlockHeight)
+		}
+		if got := manager.Stats().CacheGenerations; got != generations {
+			t.Fatalf("height %d: light cache was generated", height)
+		}
+	}
+}
+
 // TestCheckBlockHeaderContext tests that genesis block passes context headers
 // because its parent is nil.
 func TestCheckBlockHeaderContext(t *testing.T) {
@@ -56,6 +56,8 @@ be an exhaustive list.
   - Additional metadata tracking for each transaction
   - Manual control of transaction removal
   - Recursive removal of all dependent transactions
+  - Fee rate based eviction once the pool exceeds its maximum size
+  - Saving and reloading the pool with full revalidation of each transaction
 
 # Configurable Transaction Acceptance Policy
 
@@ -65,6 +67,8 @@ be an exhaustive list.
   - Max signature operations per transaction
   - Max orphan transaction size
   - Max number of orphan transactions allowed
+  - Max total size of the pool along with a dynamic minimum fee threshold that
+    rises when transactions are evicted
 
 # Additional Per-Transaction Metadata Tracking
 
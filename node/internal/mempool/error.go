@@ -91,6 +91,11 @@ const (
 
 	// ErrTSpendInvalidExpiry indicates a treasury spend expiry is invalid.
 	ErrTSpendInvalidExpiry = ErrorKind("ErrTSpendInvalidExpiry")
+
+	// ErrMempoolFull indicates a transaction was not accepted because the
+	// mempool is at its maximum size and the transaction does not pay a high
+	// enough fee rate to displace any of the existing transactions.
+	ErrMempoolFull = ErrorKind("ErrMempoolFull")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
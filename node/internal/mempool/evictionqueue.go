@@ -0,0 +1,68 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+// evictionEntry houses a transaction in the main pool along with the fee per
+// kilobyte it is expected to be mined at, which determines the order in which
+// transactions are evicted when the pool exceeds its maximum size.
+type evictionEntry struct {
+	txDesc *TxDesc
+	score  float64
+
+	// index is the index of the entry in the eviction queue.  It is -1 when
+	// the entry is not in the queue because the transaction is not
+	// evictable.
+	index int
+}
+
+// evictionQueue implements a priority queue of eviction entries such that the
+// entry for the transaction that should be evicted first is at the front.
+// Entries are ordered by increasing score with ties broken in favor of
+// evicting the most recently added transaction.
+type evictionQueue []*evictionEntry
+
+// Len returns the number of entries in the priority queue.  It is part of the
+// heap.Interface implementation.
+func (pq evictionQueue) Len() int {
+	return len(pq)
+}
+
+// Less returns whether the entry in the priority queue with index i should be
+// evicted before the entry with index j.  It is part of the heap.Interface
+// implementation.
+func (pq evictionQueue) Less(i, j int) bool {
+	if pq[i].score == pq[j].score {
+		return pq[i].txDesc.Added.After(pq[j].txDesc.Added)
+	}
+	return pq[i].score < pq[j].score
+}
+
+// Swap swaps the entries at the passed indices in the priority queue.  It is
+// part of the heap.Interface implementation.
+func (pq evictionQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+// Push pushes the passed entry onto the priority queue.  It is part of the
+// heap.Interface implementation.
+func (pq *evictionQueue) Push(x interface{}) {
+	entry := x.(*evictionEntry)
+	entry.index = len(*pq)
+	*pq = append(*pq, entry)
+}
+
+// Pop removes the last entry from the priority queue and returns it.  It is
+// part of the heap.Interface implementation.
+func (pq *evictionQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*pq = old[0 : n-1]
+	return entry
+}
@@ -6,8 +6,10 @@
 package mempool
 
 import (
+	"container/heap"
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -60,6 +62,12 @@ const (
 	// are allowed in the mempool. The number 7 is also the amount of
 	// physical space available for TSpend votes and thus is a hard limit.
 	MempoolMaxConcurrentTSpends = 7
+
+	// rollingFeeHalfLife is the amount of time it takes the dynamic minimum
+	// relay fee, which is raised whenever transactions are evicted from a
+	// full pool, to decay by half.  The decay is faster when the pool is well
+	// under its maximum size.
+	rollingFeeHalfLife = time.Hour * 12
 )
 
 // Tag represents an identifier to use for tagging orphan transactions.  The
@@ -125,6 +133,11 @@ type Config struct {
 	// This can be nil if the address index is not enabled.
 	ExistsAddrIndex *indexers.ExistsAddrIndex
 
+	// AddrIndex defines the optional address index instance to use for
+	// indexing the unconfirmed transactions in the memory pool.  This can be
+	// nil if the address index is not enabled.
+	AddrIndex *indexers.AddrIndex
+
 	// AddTxToFeeEstimation defines an optional function to be called whenever a
 	// new transaction is added to the mempool, which can be used to track fees
 	// for the purposes of smart fee estimation.
@@ -190,6 +203,12 @@ type Policy struct {
 	// considered a non-zero fee.
 	MinRelayTxFee VGLutil.Amount
 
+	// MaxPoolSize is the maximum total serialized size, in bytes, of the
+	// transactions in the main pool.  Once it is exceeded, the transactions
+	// that are least likely to be mined are evicted and the minimum relay fee
+	// is raised accordingly.  A value of zero disables the limit.
+	MaxPoolSize int64
+
 	// AllowOldVotes defines whether or not votes on old blocks will be
 	// admitted and relayed.
 	AllowOldVotes bool
@@ -271,6 +290,23 @@ type TxPool struct {
 	// the scan will only run when an orphan is added to the pool as opposed
 	// to on an unconditional timer.
 	nextExpireScan time.Time
+
+	// poolSize is the total serialized size of all transactions in the main
+	// pool.
+	poolSize int64
+
+	// rollingMinFee is the dynamic minimum relay fee in atoms/kB that is
+	// raised when transactions are evicted due to the pool exceeding its
+	// maximum size and decays over time as of lastRollingFeeUpdate.
+	rollingMinFee        float64
+	lastRollingFeeUpdate time.Time
+
+	// evictionEntries houses the eviction entry of every transaction in the
+	// main pool keyed by transaction hash.  The entries of the evictable
+	// transactions are also ordered in evictionQueue so the transaction to
+	// evict next is found without recalculating the scores of the pool.
+	evictionEntries map[chainhash.Hash]*evictionEntry
+	evictionQueue   evictionQueue
 }
 
 // insertVote inserts a vote into the map of block votes.
@@ -823,6 +859,12 @@ func (mp *TxPool) removeTransaction(tx *VGLutil.Tx, removeRedeemers bool) {
 		mp.miningView.RemoveTransaction(tx.Hash(), updateDescendantStats)
 
 		delete(mp.pool, *txHash)
+		mp.poolSize -= txDesc.TxSize
+		mp.updatePoolMetrics()
+
+		// Stop tracking the transaction for eviction and update the scores
+		// of the remaining transactions that depended on it.
+		mp.removeEvictionEntry(txDesc, updateDescendantStats)
 
 		mp.lastUpdated.Store(time.Now().Unix())
 
@@ -834,6 +876,12 @@ func (mp *TxPool) removeTransaction(tx *VGLutil.Tx, removeRedeemers bool) {
 
 		// Stop tracking if it's a tspend.
 		delete(mp.tspends, *txHash)
+
+		// Remove unconfirmed address index entries associated with the
+		// transaction if enabled.
+		if mp.cfg.AddrIndex != nil {
+			mp.cfg.AddrIndex.RemoveUnconfirmedTx(txHash)
+		}
 	}
 }
 
@@ -906,12 +954,16 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint, txDesc *TxD
 	// Add the transaction to the pool and mark the referenced outpoints
 	// as spent by the pool.
 	mp.pool[*txHash] = txDesc
+	mp.poolSize += txDesc.TxSize
+	mp.updatePoolMetrics()
 	mp.miningView.AddTransaction(&txDesc.TxDesc, mp.findTx)
 
 	msgTx := tx.MsgTx()
 	for _, txIn := range msgTx.TxIn {
 		mp.outpoints[txIn.PreviousOutPoint] = txDesc
 	}
+	mp.updateDescendantEvictionScores(tx)
+	mp.updateEvictionScore(txDesc)
 	mp.lastUpdated.Store(time.Now().Unix())
 
 	// Add unconfirmed exists address index entries associated with the
@@ -920,6 +972,12 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint, txDesc *TxD
 		mp.cfg.ExistsAddrIndex.AddUnconfirmedTx(msgTx)
 	}
 
+	// Add unconfirmed address index entries associated with the transaction
+	// if enabled.
+	if mp.cfg.AddrIndex != nil {
+		mp.cfg.AddrIndex.AddUnconfirmedTx(tx, utxoView)
+	}
+
 	// Inform the associated fee estimator that a new transaction has been added
 	// to the mempool.
 	if mp.cfg.AddTxToFeeEstimation != nil {
@@ -927,6 +985,242 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint, txDesc *TxD
 	}
 }
 
+// isEvictableTxType returns whether or not transactions of the provided type
+// may be evicted in order to keep the pool under its maximum size.  Only regular
+// transactions, ticket purchases, and treasury adds are evictable.  Votes,
+// revocations, and treasury spends are an integral part of block production and
+// governance, so they are never evicted.
+func isEvictableTxType(txType stake.TxType) bool {
+	switch txType {
+	case stake.TxTypeRegular, stake.TxTypeSStx, stake.TxTypeTAdd:
+		return true
+	}
+	return false
+}
+
+// calcFeePerKb returns the fee per kilobyte in atoms for the provided fee and
+// serialized size.
+func calcFeePerKb(fee, size int64) float64 {
+	if size <= 0 {
+		return 0
+	}
+	return float64(fee) * 1000 / float64(size)
+}
+
+// updateEvictionScore calculates the fee per kilobyte the provided transaction
+// in the main pool is expected to be mined at and updates its position in the
+// eviction queue accordingly.
+//
+// Mining selects transactions by the fee rate of the package formed by the
+// transaction and all of its unconfirmed ancestors as tracked by the mining
+// view, so a transaction with a low fee rate of its own is still mined quickly
+// when one of its descendants pays enough to bump the package.  Therefore, the
+// score of a transaction is the greater of its own ancestor package fee rate
+// and the scores of all of the transactions that redeem it.  Since evicting a
+// transaction also evicts all of its descendants, this ensures evicting the
+// transaction with the lowest score never evicts a transaction with a higher
+// one.
+//
+// The scores of the ancestors of the transaction in the main pool depend on
+// its score, so they are also updated when it changes.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) updateEvictionScore(txDesc *TxDesc) {
+	// Fall back to the fee rate of the transaction alone when the mining view
+	// does not have ancestor statistics for it.
+	txHash := txDesc.Tx.Hash()
+	fee, size := txDesc.Fee, txDesc.TxSize
+	stats, _ := mp.miningView.AncestorStats(txHash)
+	if stats.Fees >= 0 && stats.SizeBytes >= 0 {
+		fee += stats.Fees
+		size += stats.SizeBytes
+	}
+	score := calcFeePerKb(fee, size)
+	mp.forEachRedeemer(txDesc.Tx, func(redeemer *TxDesc) {
+		entry, ok := mp.evictionEntries[*redeemer.Tx.Hash()]
+		if ok && entry.score > score {
+			score = entry.score
+		}
+	})
+
+	entry, ok := mp.evictionEntries[*txHash]
+	switch {
+	case !ok:
+		entry = &evictionEntry{txDesc: txDesc, score: score, index: -1}
+		mp.evictionEntries[*txHash] = entry
+		if isEvictableTxType(txDesc.Type) {
+			heap.Push(&mp.evictionQueue, entry)
+		}
+
+	case entry.score == score:
+		return
+
+	default:
+		entry.score = score
+		if entry.index >= 0 {
+			heap.Fix(&mp.evictionQueue, entry.index)
+		}
+	}
+
+	for _, txIn := range txDesc.Tx.MsgTx().TxIn {
+		if parent, ok := mp.pool[txIn.PreviousOutPoint.Hash]; ok {
+			mp.updateEvictionScore(parent)
+		}
+	}
+}
+
+// updateDescendantEvictionScores updates the eviction scores of all of the
+// transactions in the main pool that descend from the provided transaction.
+// It must be called when the ancestor statistics of the descendants change
+// because the transaction was added to or removed from the pool.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) updateDescendantEvictionScores(tx *VGLutil.Tx) {
+	// Update the descendants in post order so the scores of the redeemers of
+	// each transaction are updated before its own.
+	visited := make(map[chainhash.Hash]struct{})
+	var update func(tx *VGLutil.Tx)
+	update = func(tx *VGLutil.Tx) {
+		mp.forEachRedeemer(tx, func(redeemer *TxDesc) {
+			redeemerHash := *redeemer.Tx.Hash()
+			if _, ok := visited[redeemerHash]; ok {
+				return
+			}
+			visited[redeemerHash] = struct{}{}
+			update(redeemer.Tx)
+			mp.updateEvictionScore(redeemer)
+		})
+	}
+	update(tx)
+}
+
+// removeEvictionEntry stops tracking the provided transaction, which has
+// already been removed from the main pool, for eviction and updates the scores
+// of its ancestors that remain in the pool.  The scores of its descendants are
+// also updated when the flag is set since their ancestor statistics no longer
+// include it.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) removeEvictionEntry(txDesc *TxDesc, updateDescendants bool) {
+	txHash := txDesc.Tx.Hash()
+	entry, ok := mp.evictionEntries[*txHash]
+	if !ok {
+		return
+	}
+	if entry.index >= 0 {
+		heap.Remove(&mp.evictionQueue, entry.index)
+	}
+	delete(mp.evictionEntries, *txHash)
+
+	if updateDescendants {
+		mp.updateDescendantEvictionScores(txDesc.Tx)
+	}
+	for _, txIn := range txDesc.Tx.MsgTx().TxIn {
+		if parent, ok := mp.pool[txIn.PreviousOutPoint.Hash]; ok {
+			mp.updateEvictionScore(parent)
+		}
+	}
+}
+
+// limitPoolSize evicts the evictable transactions that are least likely to be
+// mined, along with all transactions that depend on them, until the total size
+// of the main pool no longer exceeds the maximum allowed by policy.  The
+// dynamic minimum relay fee is raised above the score of every evicted
+// transaction so that transactions which would immediately be evicted again
+// are rejected up front.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) limitPoolSize(now time.Time) {
+	maxSize := mp.cfg.Policy.MaxPoolSize
+	for maxSize > 0 && mp.poolSize > maxSize {
+		// Evict the evictable transaction with the lowest score.  Ties are
+		// broken in favor of evicting the most recently added transaction.
+		if len(mp.evictionQueue) == 0 {
+			return
+		}
+		entry := mp.evictionQueue[0]
+		evict, evictScore := entry.txDesc, entry.score
+
+		log.Debugf("Evicting transaction %v with fee rate %.0f atoms/kB "+
+			"(pool size: %d bytes, max: %d bytes)", evict.Tx.Hash(),
+			evictScore, mp.poolSize, maxSize)
+		mp.removeTransaction(evict.Tx, true)
+		mp.raiseMinRelayTxFee(evictScore+float64(mp.cfg.Policy.MinRelayTxFee),
+			now)
+	}
+}
+
+// decayRollingMinFee decays the dynamic minimum relay fee according to the time
+// that has passed since it was last updated.  The fee halves every
+// rollingFeeHalfLife while the pool is at least half full, and decays two and
+// four times as fast when it is less than half and a quarter full,
+// respectively.  It is reset once it falls below half the minimum relay fee
+// defined by policy.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) decayRollingMinFee(now time.Time) {
+	elapsed := now.Sub(mp.lastRollingFeeUpdate)
+	if mp.rollingMinFee == 0 || elapsed <= 0 {
+		return
+	}
+
+	halfLife := rollingFeeHalfLife
+	if maxSize := mp.cfg.Policy.MaxPoolSize; maxSize > 0 {
+		switch {
+		case mp.poolSize < maxSize/4:
+			halfLife /= 4
+		case mp.poolSize < maxSize/2:
+			halfLife /= 2
+		}
+	}
+	mp.rollingMinFee /= math.Pow(2, float64(elapsed)/float64(halfLife))
+	mp.lastRollingFeeUpdate = now
+	if mp.rollingMinFee < float64(mp.cfg.Policy.MinRelayTxFee)/2 {
+		mp.rollingMinFee = 0
+	}
+}
+
+// raiseMinRelayTxFee raises the dynamic minimum relay fee to the provided fee
+// per kilobyte in atoms when it is not already higher.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) raiseMinRelayTxFee(feePerKb float64, now time.Time) {
+	mp.decayRollingMinFee(now)
+	if feePerKb > mp.rollingMinFee {
+		mp.rollingMinFee = feePerKb
+		mp.lastRollingFeeUpdate = now
+	}
+}
+
+// minRelayTxFee returns the minimum fee per kilobyte a transaction must pay to
+// be accepted to the pool.  It is the greater of the minimum relay fee defined
+// by policy and the dynamic minimum relay fee that rises when transactions are
+// evicted from a full pool.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) minRelayTxFee(now time.Time) VGLutil.Amount {
+	mp.decayRollingMinFee(now)
+	minFee := mp.cfg.Policy.MinRelayTxFee
+	rollingMinFee := VGLutil.Amount(math.Ceil(mp.rollingMinFee))
+	if rollingMinFee > minFee {
+		minFee = rollingMinFee
+	}
+	return minFee
+}
+
+// MinRelayTxFee returns the minimum fee per kilobyte a transaction must
+// currently pay to be accepted to the pool.  This is the minimum relay fee
+// defined by policy unless it has been raised due to the pool exceeding its
+// maximum size.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) MinRelayTxFee() VGLutil.Amount {
+	mp.mtx.Lock()
+	minFee := mp.minRelayTxFee(time.Now())
+	mp.mtx.Unlock()
+	return minFee
+}
+
 // checkPoolDoubleSpend checks whether or not the passed transaction is
 // attempting to spend coins already spent by other transactions in the pool.
 // Note it does not check for double spends against transactions already in the
@@ -1610,7 +1904,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *VGLutil.Tx, isNew, allowHighFees,
 	isTreasuryAdd := isTreasuryEnabled && txType == stake.TxTypeTAdd
 	serializedSize := int64(msgTx.SerializeSize())
 	minFee := calcMinRequiredTxRelayFee(serializedSize,
-		mp.cfg.Policy.MinRelayTxFee)
+		mp.minRelayTxFee(time.Now()))
 	if txFee < minFee && (txType == stake.TxTypeRegular || isTicket ||
 		isTreasuryAdd || isTSpend) {
 
@@ -1783,6 +2077,17 @@ func (mp *TxPool) maybeAcceptTransaction(tx *VGLutil.Tx, isNew, allowHighFees,
 		mp.tspends[*txHash] = tx
 	}
 
+	// Evict the transactions that are least likely to be mined when the pool
+	// now exceeds its maximum size.  Since the new transaction is a candidate
+	// for eviction as well, reject it when it did not pay enough to displace
+	// any of the existing transactions.
+	mp.limitPoolSize(time.Now())
+	if !mp.isTransactionInPool(txHash) {
+		str := fmt.Sprintf("transaction %v was not accepted because the "+
+			"mempool is full and its fee rate is too low", txHash)
+		return nil, txRuleError(ErrMempoolFull, str)
+	}
+
 	log.Debugf("Accepted transaction %v (pool size: %v)", txHash,
 		len(mp.pool))
 
@@ -2347,6 +2652,7 @@ func New(cfg *Config) *TxPool {
 		staged:          make(map[chainhash.Hash]*TxDesc),
 		stagedOutpoints: make(map[wire.OutPoint]*TxDesc),
 		transient:       make(map[chainhash.Hash]*VGLutil.Tx),
+		evictionEntries: make(map[chainhash.Hash]*evictionEntry),
 	}
 
 	// for a given transaction, scan the mempool to find which transactions
@@ -6,10 +6,13 @@
 package mempool
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"runtime"
 	"sync"
@@ -3501,3 +3504,504 @@ func TestMaybeAcceptTransactions(t *testing.T) {
 
 	testExpectedAncestorFee(txC, txAFee+txBFee)
 }
+
+// createFeeTestOutputs creates a transaction that splits the provided
+// spendable output into the requested number of outputs, adds it to the
+// harness chain as a mined transaction, and returns its outputs as spendable
+// outputs.
+func createFeeTestOutputs(t *testing.T, harness *poolHarness, out spendableOutput, numOutputs uint32) []spendableOutput {
+	t.Helper()
+
+	splitTx, err := harness.CreateSignedTx([]spendableOutput{out}, numOutputs)
+	if err != nil {
+		t.Fatalf("unable to create split transaction: %v", err)
+	}
+	harness.AddFakeUTXO(splitTx, harness.chain.BestHeight(), 0)
+	outputs := make([]spendableOutput, 0, numOutputs)
+	for i := uint32(0); i < numOutputs; i++ {
+		outputs = append(outputs, txOutToSpendableOut(splitTx, i,
+			wire.TxTreeRegular))
+	}
+	return outputs
+}
+
+// createFeeTestTx creates a transaction that spends the provided output and
+// pays the given number of atoms in addition to the minimum required fee.
+func createFeeTestTx(t *testing.T, harness *poolHarness, out spendableOutput, extraFee int64) *VGLutil.Tx {
+	t.Helper()
+
+	tx, err := harness.CreateSignedTx([]spendableOutput{out}, 1,
+		func(tx *wire.MsgTx) {
+			tx.TxOut[0].Value -= extraFee
+		})
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	return tx
+}
+
+// totalSerializeSize returns the total serialized size of the provided
+// transactions.
+func totalSerializeSize(txns ...*VGLutil.Tx) int64 {
+	var size int64
+	for _, tx := range txns {
+		size += int64(tx.MsgTx().SerializeSize())
+	}
+	return size
+}
+
+// TestPoolSizeLimit ensures that the transactions with the lowest fee rates are
+// evicted once the pool exceeds its maximum size, that the dynamic minimum
+// relay fee is raised accordingly, and that transactions which do not pay
+// enough to displace any others are rejected.
+func TestPoolSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	txPool := harness.txPool
+	outputs := createFeeTestOutputs(t, harness, spendableOuts[0], 5)
+
+	// Create transactions paying increasing fees.
+	tx1 := createFeeTestTx(t, harness, outputs[0], 0)
+	tx2 := createFeeTestTx(t, harness, outputs[1], 1000)
+	tx3 := createFeeTestTx(t, harness, outputs[2], 2000)
+	tx4 := createFeeTestTx(t, harness, outputs[3], 3000)
+	tx5 := createFeeTestTx(t, harness, outputs[4], 0)
+
+	// Limit the pool to exactly the size of the first three transactions and
+	// ensure they are all accepted.
+	txPool.cfg.Policy.MaxPoolSize = totalSerializeSize(tx1, tx2, tx3)
+	for _, tx := range []*VGLutil.Tx{tx1, tx2, tx3} {
+		_, err := txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("failed to accept valid transaction: %v", err)
+		}
+		testPoolMembership(tc, tx, false, true)
+	}
+	if txPool.poolSize != txPool.cfg.Policy.MaxPoolSize {
+		t.Fatalf("unexpected pool size -- got %d, want %d", txPool.poolSize,
+			txPool.cfg.Policy.MaxPoolSize)
+	}
+	if minFee := txPool.MinRelayTxFee(); minFee != 1000 {
+		t.Fatalf("unexpected min relay fee -- got %v, want %v", minFee, 1000)
+	}
+
+	// Ensure adding a transaction with a higher fee rate than all others
+	// evicts the one with the lowest fee rate.
+	_, err = txPool.ProcessTransaction(tx4, false, true, 0)
+	if err != nil {
+		t.Fatalf("failed to accept valid transaction: %v", err)
+	}
+	testPoolMembership(tc, tx1, false, false)
+	testPoolMembership(tc, tx2, false, true)
+	testPoolMembership(tc, tx3, false, true)
+	testPoolMembership(tc, tx4, false, true)
+	if txPool.poolSize > txPool.cfg.Policy.MaxPoolSize {
+		t.Fatalf("pool size %d exceeds max %d", txPool.poolSize,
+			txPool.cfg.Policy.MaxPoolSize)
+	}
+
+	// Ensure the dynamic minimum relay fee was raised to the fee rate of the
+	// evicted transaction plus the minimum relay fee defined by policy.
+	tx1Fee := int64(outputs[0].amount) - tx1.MsgTx().TxOut[0].Value
+	wantMinFee := calcFeePerKb(tx1Fee, int64(tx1.MsgTx().SerializeSize())) +
+		1000
+	minFee := float64(txPool.MinRelayTxFee())
+	if minFee < math.Floor(wantMinFee) || minFee > math.Ceil(wantMinFee) {
+		t.Fatalf("unexpected min relay fee -- got %v, want %v", minFee,
+			wantMinFee)
+	}
+
+	// Ensure a transaction that only pays the minimum relay fee defined by
+	// policy is now rejected due to the dynamic minimum relay fee.
+	_, err = txPool.ProcessTransaction(tx5, false, true, 0)
+	if !errors.Is(err, ErrInsufficientFee) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrInsufficientFee)
+	}
+	testPoolMembership(tc, tx5, false, false)
+}
+
+// TestPoolSizeLimitPackages ensures that eviction takes the fee rates of the
+// descendants of transactions into account such that a transaction with a low
+// fee rate of its own is not evicted when a descendant pays enough for the
+// package to be mined, and that transactions which do not pay enough to
+// displace any others are rejected.
+func TestPoolSizeLimitPackages(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	txPool := harness.txPool
+	outputs := createFeeTestOutputs(t, harness, spendableOuts[0], 4)
+
+	// Create a transaction with a moderate fee rate along with a parent that
+	// only pays the minimum fee and a child that pays a high fee.
+	txA := createFeeTestTx(t, harness, outputs[0], 1500)
+	parent := createFeeTestTx(t, harness, outputs[1], 0)
+	child := createFeeTestTx(t, harness, txOutToSpendableOut(parent, 0,
+		wire.TxTreeRegular), 5000)
+	txPool.cfg.Policy.MaxPoolSize = totalSerializeSize(txA, parent, child)
+	for _, tx := range []*VGLutil.Tx{txA, parent, child} {
+		_, err := txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("failed to accept valid transaction: %v", err)
+		}
+		testPoolMembership(tc, tx, false, true)
+	}
+
+	// Ensure a transaction with a fee rate lower than all others, including
+	// the one of the parent package, is rejected because the pool is full.
+	txD := createFeeTestTx(t, harness, outputs[2], 1000)
+	_, err = txPool.ProcessTransaction(txD, false, true, 0)
+	if !errors.Is(err, ErrMempoolFull) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, ErrMempoolFull)
+	}
+	testPoolMembership(tc, txD, false, false)
+	testPoolMembership(tc, txA, false, true)
+	testPoolMembership(tc, parent, false, true)
+	testPoolMembership(tc, child, false, true)
+
+	// Ensure a transaction with a higher fee rate than the moderate one evicts
+	// it as opposed to the parent with the lower fee rate of its own.
+	txE := createFeeTestTx(t, harness, outputs[3], 4000)
+	_, err = txPool.ProcessTransaction(txE, false, true, 0)
+	if err != nil {
+		t.Fatalf("failed to accept valid transaction: %v", err)
+	}
+	testPoolMembership(tc, txA, false, false)
+	testPoolMembership(tc, parent, false, true)
+	testPoolMembership(tc, child, false, true)
+	testPoolMembership(tc, txE, false, true)
+
+	// Ensure the entire package is evicted once the pool is no longer able to
+	// hold it.
+	txPool.cfg.Policy.MaxPoolSize = totalSerializeSize(txE)
+	txPool.mtx.Lock()
+	txPool.limitPoolSize(time.Now())
+	txPool.mtx.Unlock()
+	testPoolMembership(tc, parent, false, false)
+	testPoolMembership(tc, child, false, false)
+	testPoolMembership(tc, txE, false, true)
+}
+
+// checkEvictionScores ensures the incrementally updated eviction scores of the
+// transactions in the provided pool match the scores calculated from scratch
+// and that the eviction queue holds exactly the evictable transactions.
+func checkEvictionScores(t *testing.T, mp *TxPool) {
+	t.Helper()
+
+	// Calculate the expected scores from scratch as the greater of the
+	// ancestor package fee rate of each transaction and the scores of the
+	// transactions that redeem it.
+	want := make(map[chainhash.Hash]float64, len(mp.pool))
+	var calcScore func(txDesc *TxDesc) float64
+	calcScore = func(txDesc *TxDesc) float64 {
+		txHash := txDesc.Tx.Hash()
+		if score, ok := want[*txHash]; ok {
+			return score
+		}
+		fee, size := txDesc.Fee, txDesc.TxSize
+		stats, _ := mp.miningView.AncestorStats(txHash)
+		if stats.Fees >= 0 && stats.SizeBytes >= 0 {
+			fee += stats.Fees
+			size += stats.SizeBytes
+		}
+		score := calcFeePerKb(fee, size)
+		mp.forEachRedeemer(txDesc.Tx, func(redeemer *TxDesc) {
+			score = math.Max(score, calcScore(redeemer))
+		})
+		want[*txHash] = score
+		return score
+	}
+
+	var numEvictable int
+	for txHash, txDesc := range mp.pool {
+		entry, ok := mp.evictionEntries[txHash]
+		if !ok {
+			t.Fatalf("no eviction entry for transaction %v", txHash)
+		}
+		if wantScore := calcScore(txDesc); entry.score != wantScore {
+			t.Fatalf("mismatched eviction score for transaction %v -- got "+
+				"%v, want %v", txHash, entry.score, wantScore)
+		}
+		evictable := isEvictableTxType(txDesc.Type)
+		if evictable {
+			numEvictable++
+		}
+		inQueue := entry.index >= 0 && entry.index < len(mp.evictionQueue) &&
+			mp.evictionQueue[entry.index] == entry
+		if inQueue != evictable {
+			t.Fatalf("transaction %v in eviction queue: %v, evictable: %v",
+				txHash, inQueue, evictable)
+		}
+	}
+	if len(mp.evictionEntries) != len(mp.pool) {
+		t.Fatalf("mismatched number of eviction entries -- got %d, want %d",
+			len(mp.evictionEntries), len(mp.pool))
+	}
+	if len(mp.evictionQueue) != numEvictable {
+		t.Fatalf("mismatched eviction queue length -- got %d, want %d",
+			len(mp.evictionQueue), numEvictable)
+	}
+}
+
+// TestEvictionScores ensures the eviction scores of the transactions in the
+// pool are updated as transactions that depend on each other are added and
+// removed.
+func TestEvictionScores(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+	outputs := createFeeTestOutputs(t, harness, spendableOuts[0], 2)
+
+	// Create a chain of transactions where the parent only pays the minimum
+	// fee, the child pays a high fee, and the grandchild pays a moderate one
+	// along with an unrelated transaction.
+	parent := createFeeTestTx(t, harness, outputs[0], 0)
+	child := createFeeTestTx(t, harness, txOutToSpendableOut(parent, 0,
+		wire.TxTreeRegular), 5000)
+	grandchild := createFeeTestTx(t, harness, txOutToSpendableOut(child, 0,
+		wire.TxTreeRegular), 1500)
+	unrelated := createFeeTestTx(t, harness, outputs[1], 1000)
+	for _, tx := range []*VGLutil.Tx{parent, child, grandchild, unrelated} {
+		_, err := txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("failed to accept valid transaction: %v", err)
+		}
+		checkEvictionScores(t, txPool)
+	}
+
+	// Ensure the parent is scored by the package formed with its child.
+	parentEntry := txPool.evictionEntries[*parent.Hash()]
+	childEntry := txPool.evictionEntries[*child.Hash()]
+	if parentEntry.score != childEntry.score {
+		t.Fatalf("parent score %v does not match child score %v",
+			parentEntry.score, childEntry.score)
+	}
+
+	// Ensure the scores of the descendants are updated when the parent is
+	// removed without them as happens when it is mined.
+	txPool.RemoveTransaction(parent, false)
+	checkEvictionScores(t, txPool)
+
+	// Ensure the entries of all descendants are removed along with the
+	// transaction.
+	txPool.RemoveTransaction(child, true)
+	checkEvictionScores(t, txPool)
+	if len(txPool.evictionEntries) != 1 {
+		t.Fatalf("unexpected number of eviction entries -- got %d, want 1",
+			len(txPool.evictionEntries))
+	}
+}
+
+// TestObserveFeeRates ensures the fee rates used by the fee rate histogram are
+// those of the transactions currently in the pool as opposed to all of the
+// transactions that were ever accepted.
+func TestObserveFeeRates(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+	outputs := createFeeTestOutputs(t, harness, spendableOuts[0], 2)
+	tx1 := createFeeTestTx(t, harness, outputs[0], 0)
+	tx2 := createFeeTestTx(t, harness, outputs[1], 1000)
+	for _, tx := range []*VGLutil.Tx{tx1, tx2} {
+		_, err := txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("failed to accept valid transaction: %v", err)
+		}
+	}
+
+	feeRates := func() []float64 {
+		var rates []float64
+		txPool.ObserveFeeRates(func(v float64) {
+			rates = append(rates, v)
+		})
+		return rates
+	}
+	if rates := feeRates(); len(rates) != 2 {
+		t.Fatalf("unexpected number of fee rates -- got %d, want 2",
+			len(rates))
+	}
+
+	// Ensure only the fee rate of the remaining transaction is observed once
+	// the other one is removed.
+	txPool.RemoveTransaction(tx1, false)
+	tx2Fee := int64(outputs[1].amount) - tx2.MsgTx().TxOut[0].Value
+	want := calcFeePerKb(tx2Fee, int64(tx2.MsgTx().SerializeSize()))
+	rates := feeRates()
+	if len(rates) != 1 || rates[0] != want {
+		t.Fatalf("unexpected fee rates -- got %v, want [%v]", rates, want)
+	}
+}
+
+// TestMinRelayTxFeeDecay ensures the dynamic minimum relay fee decays over time
+// as expected.
+func TestMinRelayTxFeeDecay(t *testing.T) {
+	t.Parallel()
+
+	harness, _, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	tests := []struct {
+		name    string         // test description
+		elapsed time.Duration  // time since the previous test
+		want    VGLutil.Amount // expected min relay fee
+		rolling float64        // expected dynamic min relay fee
+	}{{
+		name:    "no time elapsed",
+		elapsed: 0,
+		want:    10000,
+		rolling: 10000,
+	}, {
+		name:    "one half-life",
+		elapsed: rollingFeeHalfLife,
+		want:    5000,
+		rolling: 5000,
+	}, {
+		name:    "three half-lives, below policy",
+		elapsed: 3 * rollingFeeHalfLife,
+		want:    1000,
+		rolling: 625,
+	}, {
+		name:    "below half of policy resets",
+		elapsed: rollingFeeHalfLife,
+		want:    1000,
+		rolling: 0,
+	}}
+
+	now := time.Now()
+	txPool.raiseMinRelayTxFee(10000, now)
+	for _, test := range tests {
+		now = now.Add(test.elapsed)
+		if got := txPool.minRelayTxFee(now); got != test.want {
+			t.Fatalf("%q: unexpected min relay fee -- got %v, want %v",
+				test.name, got, test.want)
+		}
+		if txPool.rollingMinFee != test.rolling {
+			t.Fatalf("%q: unexpected dynamic min relay fee -- got %v, want %v",
+				test.name, txPool.rollingMinFee, test.rolling)
+		}
+	}
+
+	// Ensure the fee decays faster when the pool is mostly empty.
+	txPool.cfg.Policy.MaxPoolSize = 1000000
+	txPool.raiseMinRelayTxFee(10000, now)
+	txPool.minRelayTxFee(now.Add(rollingFeeHalfLife / 4))
+	if txPool.rollingMinFee != 5000 {
+		t.Fatalf("unexpected dynamic min relay fee -- got %v, want %v",
+			txPool.rollingMinFee, 5000)
+	}
+}
+
+// TestDumpLoad ensures transactions dumped from the pool are loaded back into
+// a pool in an order that does not produce orphans and that invalid data and
+// transactions are handled properly.
+func TestDumpLoad(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	chainedTxns, err := harness.CreateTxChain(spendableOuts[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns {
+		_, err := txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("failed to accept valid transaction: %v", err)
+		}
+	}
+
+	// Make the last transaction in the chain appear to have been added first
+	// to ensure parents are still dumped before their children.
+	txPool.mtx.Lock()
+	txPool.pool[*chainedTxns[2].Hash()].Added = time.Time{}
+	txPool.mtx.Unlock()
+
+	var buf bytes.Buffer
+	numDumped, err := txPool.Dump(&buf)
+	if err != nil {
+		t.Fatalf("unexpected dump error: %v", err)
+	}
+	if numDumped != len(chainedTxns) {
+		t.Fatalf("unexpected number of dumped transactions -- got %d, want %d",
+			numDumped, len(chainedTxns))
+	}
+	dumped := buf.Bytes()
+
+	// Ensure all of the transactions are accepted into a new pool.
+	newPool := New(&txPool.cfg)
+	accepted, rejected, err := newPool.Load(context.Background(),
+		bytes.NewReader(dumped))
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if accepted != len(chainedTxns) || rejected != 0 {
+		t.Fatalf("unexpected load result -- got %d accepted and %d rejected, "+
+			"want %d accepted and 0 rejected", accepted, rejected,
+			len(chainedTxns))
+	}
+	for _, tx := range chainedTxns {
+		if !newPool.IsTransactionInPool(tx.Hash()) {
+			t.Fatalf("transaction %v was not loaded", tx.Hash())
+		}
+	}
+
+	// Ensure the transactions are rejected when loaded into the pool they
+	// already exist in.
+	accepted, rejected, err = txPool.Load(context.Background(),
+		bytes.NewReader(dumped))
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if accepted != 0 || rejected != len(chainedTxns) {
+		t.Fatalf("unexpected load result -- got %d accepted and %d rejected, "+
+			"want 0 accepted and %d rejected", accepted, rejected,
+			len(chainedTxns))
+	}
+
+	// Ensure loading stops when the context is cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = New(&txPool.cfg).Load(ctx, bytes.NewReader(dumped))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, context.Canceled)
+	}
+
+	// Ensure an unsupported version and truncated data are rejected.
+	badVersion := append([]byte{0xff}, dumped[1:]...)
+	_, _, err = New(&txPool.cfg).Load(context.Background(),
+		bytes.NewReader(badVersion))
+	if err == nil {
+		t.Fatal("did not receive error for unsupported version")
+	}
+	truncated := dumped[:len(dumped)-1]
+	_, _, err = New(&txPool.cfg).Load(context.Background(),
+		bytes.NewReader(truncated))
+	if err == nil {
+		t.Fatal("did not receive error for truncated data")
+	}
+}
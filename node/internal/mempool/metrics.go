@@ -0,0 +1,41 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import "github.com/kdsmith18542/vigil/internal/metrics"
+
+var (
+	// poolTxnsGauge and poolSizeGauge track the number of transactions in the
+	// main pool and their total serialized size, respectively.
+	poolTxnsGauge = metrics.NewGauge("vgld_mempool_transactions",
+		"Number of transactions in the main transaction pool.")
+	poolSizeGauge = metrics.NewGauge("vgld_mempool_size_bytes",
+		"Total serialized size of the transactions in the main transaction "+
+			"pool in bytes.")
+)
+
+// updatePoolMetrics updates the metrics that track the size of the main pool.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) updatePoolMetrics() {
+	poolTxnsGauge.Set(float64(len(mp.pool)))
+	poolSizeGauge.Set(float64(mp.poolSize))
+}
+
+// ObserveFeeRates invokes the provided function with the fee rate in atoms/kB
+// of every transaction in the main pool.  It is intended to be used as the
+// callback of a histogram that describes the fee rates of the pool.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) ObserveFeeRates(observe func(v float64)) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	for _, txDesc := range mp.pool {
+		if txDesc.TxSize > 0 {
+			observe(calcFeePerKb(txDesc.Fee, txDesc.TxSize))
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+const (
+	// dumpVersion is the version of the serialized format written by Dump.
+	//
+	// The serialized format is:
+	//
+	//   <version><num txns><tx 1><tx 2>...<tx n>
+	//
+	//   Field      Type     Size
+	//   version    uint32   4 bytes
+	//   num txns   VarInt   variable
+	//   tx         MsgTx    variable
+	dumpVersion = 1
+
+	// maxDumpAllocTxns is the maximum number of transactions space is
+	// preallocated for when loading serialized transactions in order to
+	// prevent a corrupt count from exhausting memory.
+	maxDumpAllocTxns = 100000
+)
+
+// Dump serializes all transactions in the main and stage pools to w in a
+// format suitable for Load.  The transactions are written in the order they
+// were added to the pool, except that every transaction is always preceded
+// by the transactions in the pool it spends.  This ensures they may be added
+// back to the pool one by one without any of them becoming orphans.  It
+// returns the number of transactions written.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) Dump(w io.Writer) (int, error) {
+	mp.mtx.RLock()
+	txDescs := make([]*TxDesc, 0, len(mp.pool)+len(mp.staged))
+	for _, txDesc := range mp.pool {
+		txDescs = append(txDescs, txDesc)
+	}
+	for _, txDesc := range mp.staged {
+		txDescs = append(txDescs, txDesc)
+	}
+	mp.mtx.RUnlock()
+
+	sort.Slice(txDescs, func(i, j int) bool {
+		return txDescs[i].Added.Before(txDescs[j].Added)
+	})
+	txDescsByHash := make(map[chainhash.Hash]*TxDesc, len(txDescs))
+	for _, txDesc := range txDescs {
+		txDescsByHash[*txDesc.Tx.Hash()] = txDesc
+	}
+
+	// Order the transactions such that parents come before their children.
+	sorted := make([]*wire.MsgTx, 0, len(txDescs))
+	visited := make(map[chainhash.Hash]struct{}, len(txDescs))
+	var visit func(txDesc *TxDesc)
+	visit = func(txDesc *TxDesc) {
+		txHash := *txDesc.Tx.Hash()
+		if _, ok := visited[txHash]; ok {
+			return
+		}
+		visited[txHash] = struct{}{}
+		for _, txIn := range txDesc.Tx.MsgTx().TxIn {
+			parent, ok := txDescsByHash[txIn.PreviousOutPoint.Hash]
+			if ok {
+				visit(parent)
+			}
+		}
+		sorted = append(sorted, txDesc.Tx.MsgTx())
+	}
+	for _, txDesc := range txDescs {
+		visit(txDesc)
+	}
+
+	err := binary.Write(w, binary.LittleEndian, uint32(dumpVersion))
+	if err != nil {
+		return 0, err
+	}
+	err = wire.WriteVarInt(w, 0, uint64(len(sorted)))
+	if err != nil {
+		return 0, err
+	}
+	for i, msgTx := range sorted {
+		if err := msgTx.Serialize(w); err != nil {
+			return i, err
+		}
+	}
+	return len(sorted), nil
+}
+
+// Load reads transactions previously serialized by Dump from r and attempts to
+// add each of them to the pool via MaybeAcceptTransaction.  This means every
+// transaction is fully revalidated against the current state of the chain and
+// the active policy, so transactions that have since been mined, double spent,
+// expired, or otherwise become invalid are skipped.  Loading stops early when
+// the provided context is cancelled.
+//
+// It returns the number of transactions that were accepted and rejected,
+// respectively.  An error is only returned when the serialized data itself is
+// invalid or the context is cancelled.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) Load(ctx context.Context, r io.Reader) (int, int, error) {
+	var version uint32
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return 0, 0, err
+	}
+	if version != dumpVersion {
+		return 0, 0, fmt.Errorf("unsupported serialized mempool version %d",
+			version)
+	}
+	numTxns, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Deserialize all of the transactions prior to adding any of them so that
+	// corrupt data is detected up front.
+	txns := make([]*VGLutil.Tx, 0, min(numTxns, maxDumpAllocTxns))
+	for i := uint64(0); i < numTxns; i++ {
+		var msgTx wire.MsgTx
+		if err := msgTx.Deserialize(r); err != nil {
+			return 0, 0, fmt.Errorf("unable to deserialize transaction %d: %w",
+				i, err)
+		}
+		txns = append(txns, VGLutil.NewTx(&msgTx))
+	}
+
+	var accepted, rejected int
+	for _, tx := range txns {
+		if err := ctx.Err(); err != nil {
+			return accepted, rejected, err
+		}
+
+		missingParents, err := mp.MaybeAcceptTransaction(tx, true)
+		switch {
+		case err != nil:
+			log.Debugf("Rejected saved transaction %v: %v", tx.Hash(), err)
+			rejected++
+		case len(missingParents) > 0:
+			log.Debugf("Rejected saved transaction %v: orphan", tx.Hash())
+			rejected++
+		default:
+			accepted++
+		}
+	}
+	return accepted, rejected, nil
+}
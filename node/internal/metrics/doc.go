@@ -0,0 +1,39 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package metrics provides lightweight counters, gauges, and histograms that are
+exposed in the Prometheus text exposition format.
+
+Tests are included to ensure proper functionality.
+
+# Feature Overview
+
+The following are the primary features provided:
+
+  - Counters, gauges, and histograms that are safe for concurrent access and
+    cheap enough to update from hot paths
+  - Counters, gauges, and histograms that are computed on demand via a
+    callback when they are collected
+  - Gauges and counters partitioned by the value of a single label
+  - A registry that writes all registered metrics in the Prometheus text
+    exposition format and an HTTP handler that serves it
+
+Subsystems register the metrics they own with the default registry when their
+package is initialized, typically by declaring them as package-level variables,
+and update them directly as events happen.  The registry only reads the current
+values when it is scraped, so there is no need for any polling.
+
+Metrics that describe the state of a specific instance, such as the
+transactions in a transaction pool, are instead registered by the node once it
+creates that instance so there is no need for any package-level state.
+
+# Naming
+
+Metric names must only consist of ASCII letters, digits, underscores, and
+colons and must not start with a digit.  By convention, all metrics created by
+vgld use the vgld_ prefix, counters use the _total suffix, and durations are
+measured in seconds.
+*/
+package metrics
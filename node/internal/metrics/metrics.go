@@ -0,0 +1,673 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// contentType is the content type of the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultRegistry is the registry the package-level constructors register
+// metrics with and that is served by the node.
+var DefaultRegistry = NewRegistry()
+
+// collector describes a registered metric that is able to write itself in the
+// text exposition format.
+type collector interface {
+	// desc returns the name, help text, and type of the metric.
+	desc() *metricDesc
+
+	// write writes the samples of the metric.
+	write(w *bufio.Writer)
+}
+
+// metricDesc houses the details common to all metrics.
+type metricDesc struct {
+	name       string
+	help       string
+	metricType string
+}
+
+// desc returns the details of the metric.  It allows the types that embed it
+// to implement part of the collector interface.
+func (d *metricDesc) desc() *metricDesc {
+	return d
+}
+
+// validName returns whether or not the provided name is a valid metric or
+// label name.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// atomicFloat provides a float64 that can be updated atomically.
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+// Load returns the current value.
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+// Store sets the current value.
+func (f *atomicFloat) Store(v float64) {
+	f.bits.Store(math.Float64bits(v))
+}
+
+// Add adds the provided value to the current value.
+func (f *atomicFloat) Add(v float64) {
+	for {
+		old := f.bits.Load()
+		updated := math.Float64bits(math.Float64frombits(old) + v)
+		if f.bits.CompareAndSwap(old, updated) {
+			return
+		}
+	}
+}
+
+// Counter is a metric whose value only ever increases.  It is safe for
+// concurrent access.
+type Counter struct {
+	metricDesc
+	value atomicFloat
+}
+
+// Inc increments the counter by one.
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add increases the counter by the provided value.  Negative values are
+// ignored since counters may only increase.
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		return
+	}
+	c.value.Add(v)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() float64 {
+	return c.value.Load()
+}
+
+// write writes the sample of the counter.
+func (c *Counter) write(w *bufio.Writer) {
+	writeSample(w, c.name, "", "", c.Value())
+}
+
+// CounterFunc is a counter whose value is obtained by invoking a callback each
+// time it is collected.  It is intended for totals that are maintained
+// elsewhere, so the callback must return a value that only ever increases and
+// must be safe for concurrent access.
+type CounterFunc struct {
+	metricDesc
+	fn func() float64
+}
+
+// write writes the sample of the counter as returned by its callback.
+func (c *CounterFunc) write(w *bufio.Writer) {
+	writeSample(w, c.name, "", "", c.fn())
+}
+
+// Gauge is a metric whose value may increase and decrease.  It is safe for
+// concurrent access.
+type Gauge struct {
+	metricDesc
+	value atomicFloat
+}
+
+// Set sets the gauge to the provided value.
+func (g *Gauge) Set(v float64) {
+	g.value.Store(v)
+}
+
+// Add adds the provided value, which may be negative, to the gauge.
+func (g *Gauge) Add(v float64) {
+	g.value.Add(v)
+}
+
+// Inc increments the gauge by one.
+func (g *Gauge) Inc() {
+	g.value.Add(1)
+}
+
+// Dec decrements the gauge by one.
+func (g *Gauge) Dec() {
+	g.value.Add(-1)
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	return g.value.Load()
+}
+
+// write writes the sample of the gauge.
+func (g *Gauge) write(w *bufio.Writer) {
+	writeSample(w, g.name, "", "", g.Value())
+}
+
+// GaugeFunc is a gauge whose value is obtained by invoking a callback each time
+// it is collected.  The callback must be safe for concurrent access.
+type GaugeFunc struct {
+	metricDesc
+	fn func() float64
+}
+
+// write writes the sample of the gauge as returned by its callback.
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	writeSample(w, g.name, "", "", g.fn())
+}
+
+// valuer describes a metric that has a single current value.
+type valuer interface {
+	Value() float64
+}
+
+// vec houses a set of metrics that are partitioned by the value of a single
+// label.
+type vec[T valuer] struct {
+	metricDesc
+	label     string
+	newMetric func() T
+
+	mtx     sync.Mutex
+	metrics map[string]T
+}
+
+// With returns the metric for the provided label value, creating it when it
+// does not already exist.
+func (v *vec[T]) With(labelValue string) T {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	m, ok := v.metrics[labelValue]
+	if !ok {
+		m = v.newMetric()
+		v.metrics[labelValue] = m
+	}
+	return m
+}
+
+// write writes the samples of all metrics in the vector ordered by their label
+// values.
+func (v *vec[T]) write(w *bufio.Writer) {
+	v.mtx.Lock()
+	labelValues := make([]string, 0, len(v.metrics))
+	for labelValue := range v.metrics {
+		labelValues = append(labelValues, labelValue)
+	}
+	values := make(map[string]float64, len(v.metrics))
+	for labelValue, m := range v.metrics {
+		values[labelValue] = m.Value()
+	}
+	v.mtx.Unlock()
+
+	slices.Sort(labelValues)
+	for _, labelValue := range labelValues {
+		writeSample(w, v.name, v.label, labelValue, values[labelValue])
+	}
+}
+
+// CounterVec is a set of counters that are partitioned by the value of a single
+// label.  It is safe for concurrent access.
+type CounterVec struct {
+	vec[*Counter]
+}
+
+// GaugeVec is a set of gauges that are partitioned by the value of a single
+// label.  It is safe for concurrent access.
+type GaugeVec struct {
+	vec[*Gauge]
+}
+
+// Histogram is a metric that samples observations, such as durations, and
+// counts them in configurable cumulative buckets along with tracking the sum
+// and count of all observations.  It is safe for concurrent access.
+type Histogram struct {
+	metricDesc
+	upperBounds []float64
+	buckets     []atomic.Uint64
+	count       atomic.Uint64
+	sum         atomicFloat
+}
+
+// Observe adds a single observation to the histogram.
+func (h *Histogram) Observe(v float64) {
+	// Find the first bucket with an upper bound the value does not exceed.
+	// Values that exceed all upper bounds are only reflected in the count
+	// which represents the implicit +Inf bucket.
+	idx, _ := slices.BinarySearch(h.upperBounds, v)
+	if idx < len(h.buckets) {
+		h.buckets[idx].Add(1)
+	}
+	h.sum.Add(v)
+	h.count.Add(1)
+}
+
+// Count returns the total number of observations.
+func (h *Histogram) Count() uint64 {
+	return h.count.Load()
+}
+
+// Sum returns the sum of all observations.
+func (h *Histogram) Sum() float64 {
+	return h.sum.Load()
+}
+
+// write writes the cumulative buckets, sum, and count of the histogram.
+func (h *Histogram) write(w *bufio.Writer) {
+	// Load the count prior to the buckets so the cumulative count of the final
+	// bucket never exceeds the +Inf bucket in the case of concurrent updates.
+	count := h.count.Load()
+	buckets := make([]uint64, len(h.buckets))
+	for i := range h.buckets {
+		buckets[i] = h.buckets[i].Load()
+	}
+	writeHistogram(w, h.name, h.upperBounds, buckets, count, h.sum.Load())
+}
+
+// HistogramFunc is a histogram whose observations are obtained by invoking a
+// callback each time it is collected.  Unlike a Histogram, which accumulates
+// observations as events happen, it describes the distribution of a set of
+// values at the time it is collected, such as those of the items currently in
+// a pool, so its counts may decrease.  The callback must be safe for
+// concurrent access.
+type HistogramFunc struct {
+	metricDesc
+	upperBounds []float64
+	fn          func(observe func(v float64))
+}
+
+// write writes the cumulative buckets, sum, and count of the observations made
+// by the callback.
+func (h *HistogramFunc) write(w *bufio.Writer) {
+	buckets := make([]uint64, len(h.upperBounds))
+	var count uint64
+	var sum float64
+	h.fn(func(v float64) {
+		idx, _ := slices.BinarySearch(h.upperBounds, v)
+		if idx < len(buckets) {
+			buckets[idx]++
+		}
+		sum += v
+		count++
+	})
+	writeHistogram(w, h.name, h.upperBounds, buckets, count, sum)
+}
+
+// writeHistogram writes the cumulative buckets, sum, and count of a histogram
+// with the provided per-bucket counts.
+func writeHistogram(w *bufio.Writer, name string, upperBounds []float64, buckets []uint64, count uint64, sum float64) {
+	bucketName := name + "_bucket"
+	var cumulative uint64
+	for i, upperBound := range upperBounds {
+		cumulative += buckets[i]
+		cumulative = min(cumulative, count)
+		writeSample(w, bucketName, "le", formatFloat(upperBound),
+			float64(cumulative))
+	}
+	writeSample(w, bucketName, "le", "+Inf", float64(count))
+	writeSample(w, name+"_sum", "", "", sum)
+	writeSample(w, name+"_count", "", "", float64(count))
+}
+
+// ExponentialBuckets returns the provided number of histogram bucket upper
+// bounds where the first one is start and each subsequent one is the previous
+// one multiplied by factor.
+//
+// It will panic if count is less than one, start is not positive, or factor is
+// not greater than one since that is a programming error.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	if count < 1 || start <= 0 || factor <= 1 {
+		panic(fmt.Sprintf("invalid exponential buckets (start %v, factor %v, "+
+			"count %d)", start, factor, count))
+	}
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// Registry houses a set of uniquely named metrics and writes them in the
+// Prometheus text exposition format.  It is safe for concurrent access.
+type Registry struct {
+	mtx        sync.Mutex
+	collectors map[string]collector
+}
+
+// NewRegistry returns a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: make(map[string]collector),
+	}
+}
+
+// register adds the provided metric to the registry and returns it.
+//
+// It will panic if the name of the metric is invalid or already registered
+// since that is a programming error.
+func register[T collector](r *Registry, c T) T {
+	d := c.desc()
+	if !validName(d.name) {
+		panic(fmt.Sprintf("invalid metric name %q", d.name))
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.collectors[d.name]; ok {
+		panic(fmt.Sprintf("metric %q is already registered", d.name))
+	}
+	r.collectors[d.name] = c
+	return c
+}
+
+// NewCounter creates a counter with the provided name and help text and
+// registers it with the registry.
+//
+// It will panic if the name is invalid or already registered.
+func (r *Registry) NewCounter(name, help string) *Counter {
+	return register(r, &Counter{metricDesc: metricDesc{name, help, "counter"}})
+}
+
+// NewCounterFunc creates a counter with the provided name and help text whose
+// value is obtained by invoking the provided callback each time it is collected
+// and registers it with the registry.
+//
+// It will panic if the name is invalid or already registered.
+func (r *Registry) NewCounterFunc(name, help string, fn func() float64) *CounterFunc {
+	return register(r, &CounterFunc{metricDesc: metricDesc{name, help, "counter"}, fn: fn})
+}
+
+// NewGauge creates a gauge with the provided name and help text and registers
+// it with the registry.
+//
+// It will panic if the name is invalid or already registered.
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	return register(r, &Gauge{metricDesc: metricDesc{name, help, "gauge"}})
+}
+
+// NewGaugeFunc creates a gauge with the provided name and help text whose value
+// is obtained by invoking the provided callback each time it is collected and
+// registers it with the registry.
+//
+// It will panic if the name is invalid or already registered.
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	return register(r, &GaugeFunc{metricDesc: metricDesc{name, help, "gauge"}, fn: fn})
+}
+
+// NewCounterVec creates a set of counters with the provided name and help text
+// that are partitioned by the value of the provided label and registers it
+// with the registry.
+//
+// It will panic if the name or label is invalid or the name is already
+// registered.
+func (r *Registry) NewCounterVec(name, help, label string) *CounterVec {
+	if !validName(label) {
+		panic(fmt.Sprintf("invalid label name %q", label))
+	}
+	d := metricDesc{name, help, "counter"}
+	return register(r, &CounterVec{vec[*Counter]{
+		metricDesc: d,
+		label:      label,
+		newMetric:  func() *Counter { return &Counter{metricDesc: d} },
+		metrics:    make(map[string]*Counter),
+	}})
+}
+
+// NewGaugeVec creates a set of gauges with the provided name and help text that
+// are partitioned by the value of the provided label and registers it with the
+// registry.
+//
+// It will panic if the name or label is invalid or the name is already
+// registered.
+func (r *Registry) NewGaugeVec(name, help, label string) *GaugeVec {
+	if !validName(label) {
+		panic(fmt.Sprintf("invalid label name %q", label))
+	}
+	d := metricDesc{name, help, "gauge"}
+	return register(r, &GaugeVec{vec[*Gauge]{
+		metricDesc: d,
+		label:      label,
+		newMetric:  func() *Gauge { return &Gauge{metricDesc: d} },
+		metrics:    make(map[string]*Gauge),
+	}})
+}
+
+// NewHistogram creates a histogram with the provided name, help text, and
+// bucket upper bounds and registers it with the registry.  The upper bounds
+// must be sorted in increasing order and an implicit +Inf bucket is always
+// included.
+//
+// It will panic if the name is invalid or already registered or the upper
+// bounds are not sorted.
+func (r *Registry) NewHistogram(name, help string, upperBounds []float64) *Histogram {
+	if !slices.IsSorted(upperBounds) {
+		panic(fmt.Sprintf("histogram %q buckets are not sorted", name))
+	}
+	upperBounds = slices.Clone(upperBounds)
+	return register(r, &Histogram{
+		metricDesc:  metricDesc{name, help, "histogram"},
+		upperBounds: upperBounds,
+		buckets:     make([]atomic.Uint64, len(upperBounds)),
+	})
+}
+
+// NewHistogramFunc creates a histogram with the provided name, help text, and
+// bucket upper bounds whose observations are obtained by invoking the provided
+// callback each time it is collected and registers it with the registry.  The
+// callback is passed a function to invoke with each observation.  The upper
+// bounds must be sorted in increasing order and an implicit +Inf bucket is
+// always included.
+//
+// It will panic if the name is invalid or already registered or the upper
+// bounds are not sorted.
+func (r *Registry) NewHistogramFunc(name, help string, upperBounds []float64, fn func(observe func(v float64))) *HistogramFunc {
+	if !slices.IsSorted(upperBounds) {
+		panic(fmt.Sprintf("histogram %q buckets are not sorted", name))
+	}
+	return register(r, &HistogramFunc{
+		metricDesc:  metricDesc{name, help, "histogram"},
+		upperBounds: slices.Clone(upperBounds),
+		fn:          fn,
+	})
+}
+
+// WriteTo writes all registered metrics ordered by their names to the provided
+// writer in the Prometheus text exposition format.
+//
+// This implements the io.WriterTo interface.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mtx.Lock()
+	collectors := make([]collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mtx.Unlock()
+	slices.SortFunc(collectors, func(a, b collector) int {
+		return strings.Compare(a.desc().name, b.desc().name)
+	})
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, c := range collectors {
+		d := c.desc()
+		if d.help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", d.name, helpEscaper.Replace(d.help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", d.name, d.metricType)
+		c.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP writes all registered metrics in the Prometheus text exposition
+// format.
+//
+// This implements the http.Handler interface.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if req.Method == http.MethodHead {
+		return
+	}
+	r.WriteTo(w)
+}
+
+// countingWriter wraps a writer to count the number of bytes written.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes the provided bytes to the underlying writer and adds the number
+// of bytes written to the total.
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+var (
+	// helpEscaper escapes help text per the text exposition format.
+	helpEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+
+	// labelEscaper escapes label values per the text exposition format.
+	labelEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\"", "\\\"")
+)
+
+// formatFloat returns the provided value formatted per the text exposition
+// format.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writeSample writes a single sample with the provided name and value along
+// with the provided label when it is not empty.
+func writeSample(w *bufio.Writer, name, label, labelValue string, value float64) {
+	w.WriteString(name)
+	if label != "" {
+		w.WriteByte('{')
+		w.WriteString(label)
+		w.WriteString(`="`)
+		w.WriteString(labelEscaper.Replace(labelValue))
+		w.WriteString(`"}`)
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// NewCounter creates a counter with the provided name and help text and
+// registers it with the default registry.
+//
+// It will panic if the name is invalid or already registered.
+func NewCounter(name, help string) *Counter {
+	return DefaultRegistry.NewCounter(name, help)
+}
+
+// NewCounterFunc creates a counter with the provided name and help text whose
+// value is obtained by invoking the provided callback each time it is collected
+// and registers it with the default registry.
+//
+// It will panic if the name is invalid or already registered.
+func NewCounterFunc(name, help string, fn func() float64) *CounterFunc {
+	return DefaultRegistry.NewCounterFunc(name, help, fn)
+}
+
+// NewGauge creates a gauge with the provided name and help text and registers
+// it with the default registry.
+//
+// It will panic if the name is invalid or already registered.
+func NewGauge(name, help string) *Gauge {
+	return DefaultRegistry.NewGauge(name, help)
+}
+
+// NewGaugeFunc creates a gauge with the provided name and help text whose value
+// is obtained by invoking the provided callback each time it is collected and
+// registers it with the default registry.
+//
+// It will panic if the name is invalid or already registered.
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	return DefaultRegistry.NewGaugeFunc(name, help, fn)
+}
+
+// NewCounterVec creates a set of counters with the provided name and help text
+// that are partitioned by the value of the provided label and registers it
+// with the default registry.
+//
+// It will panic if the name or label is invalid or the name is already
+// registered.
+func NewCounterVec(name, help, label string) *CounterVec {
+	return DefaultRegistry.NewCounterVec(name, help, label)
+}
+
+// NewGaugeVec creates a set of gauges with the provided name and help text that
+// are partitioned by the value of the provided label and registers it with the
+// default registry.
+//
+// It will panic if the name or label is invalid or the name is already
+// registered.
+func NewGaugeVec(name, help, label string) *GaugeVec {
+	return DefaultRegistry.NewGaugeVec(name, help, label)
+}
+
+// NewHistogram creates a histogram with the provided name, help text, and
+// bucket upper bounds and registers it with the default registry.
+//
+// It will panic if the name is invalid or already registered or the upper
+// bounds are not sorted.
+func NewHistogram(name, help string, upperBounds []float64) *Histogram {
+	return DefaultRegistry.NewHistogram(name, help, upperBounds)
+}
+
+// NewHistogramFunc creates a histogram with the provided name, help text, and
+// bucket upper bounds whose observations are obtained by invoking the provided
+// callback each time it is collected and registers it with the default
+// registry.
+//
+// It will panic if the name is invalid or already registered or the upper
+// bounds are not sorted.
+func NewHistogramFunc(name, help string, upperBounds []float64, fn func(observe func(v float64))) *HistogramFunc {
+	return DefaultRegistry.NewHistogramFunc(name, help, upperBounds, fn)
+}
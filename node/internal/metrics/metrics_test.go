@@ -0,0 +1,219 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestRegistryWrite ensures all metric types are written in the text
+// exposition format as expected and ordered by name.
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	counter := r.NewCounter("test_events_total", "Number of events.")
+	gauge := r.NewGauge("test_height", "Current height.\nSecond line.")
+	r.NewGaugeFunc("test_func", "", func() float64 { return math.Inf(1) })
+	r.NewCounterFunc("test_func_total", "Func events.", func() float64 { return 7 })
+	peers := r.NewGaugeVec("test_peers", "Connected peers.", "direction")
+	bytesRecv := r.NewCounterVec("test_bytes_total", "Bytes.", "kind")
+	hist := r.NewHistogram("test_seconds", "Durations.", []float64{0.1, 1, 10})
+	poolValues := []float64{3, 8, 30}
+	r.NewHistogramFunc("test_pool_values", "Pool values.", []float64{5, 10},
+		func(observe func(v float64)) {
+			for _, v := range poolValues {
+				observe(v)
+			}
+		})
+
+	counter.Inc()
+	counter.Add(2.5)
+	counter.Add(-1) // Ignored.
+	gauge.Set(10)
+	gauge.Dec()
+	peers.With("outbound").Add(8)
+	peers.With("inbound").Inc()
+	peers.With("inbound").Inc()
+	bytesRecv.With(`quote"and\slash`).Add(3)
+	for _, v := range []float64{0.05, 0.1, 0.5, 2, 20} {
+		hist.Observe(v)
+	}
+
+	const want = `# HELP test_bytes_total Bytes.
+# TYPE test_bytes_total counter
+test_bytes_total{kind="quote\"and\\slash"} 3
+# HELP test_events_total Number of events.
+# TYPE test_events_total counter
+test_events_total 3.5
+# TYPE test_func gauge
+test_func +Inf
+# HELP test_func_total Func events.
+# TYPE test_func_total counter
+test_func_total 7
+# HELP test_height Current height.\nSecond line.
+# TYPE test_height gauge
+test_height 9
+# HELP test_peers Connected peers.
+# TYPE test_peers gauge
+test_peers{direction="inbound"} 2
+test_peers{direction="outbound"} 8
+# HELP test_pool_values Pool values.
+# TYPE test_pool_values histogram
+test_pool_values_bucket{le="5"} 1
+test_pool_values_bucket{le="10"} 2
+test_pool_values_bucket{le="+Inf"} 3
+test_pool_values_sum 41
+test_pool_values_count 3
+# HELP test_seconds Durations.
+# TYPE test_seconds histogram
+test_seconds_bucket{le="0.1"} 2
+test_seconds_bucket{le="1"} 3
+test_seconds_bucket{le="10"} 4
+test_seconds_bucket{le="+Inf"} 5
+test_seconds_sum 22.65
+test_seconds_count 5
+`
+	var buf bytes.Buffer
+	n, err := r.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("mismatched written bytes -- got %d, want %d", n, buf.Len())
+	}
+	if got := buf.String(); got != want {
+		t.Fatalf("mismatched output -- got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestRegistryPanics ensures registering invalid or duplicate metrics panics.
+func TestRegistryPanics(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(r *Registry)
+	}{{
+		name:     "empty name",
+		register: func(r *Registry) { r.NewCounter("", "") },
+	}, {
+		name:     "name with leading digit",
+		register: func(r *Registry) { r.NewGauge("1abc", "") },
+	}, {
+		name:     "name with invalid char",
+		register: func(r *Registry) { r.NewGauge("a-b", "") },
+	}, {
+		name:     "invalid label",
+		register: func(r *Registry) { r.NewGaugeVec("ab", "", "a b") },
+	}, {
+		name: "duplicate name",
+		register: func(r *Registry) {
+			r.NewCounter("dup", "")
+			r.NewGauge("dup", "")
+		},
+	}, {
+		name: "unsorted buckets",
+		register: func(r *Registry) {
+			r.NewHistogram("hist", "", []float64{1, 0.5})
+		},
+	}, {
+		name: "unsorted func buckets",
+		register: func(r *Registry) {
+			r.NewHistogramFunc("hist", "", []float64{1, 0.5},
+				func(observe func(v float64)) {})
+		},
+	}}
+
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%q: did not panic", test.name)
+				}
+			}()
+			test.register(NewRegistry())
+		}()
+	}
+}
+
+// TestConcurrentUpdates ensures metrics are updated atomically when accessed
+// concurrently.
+func TestConcurrentUpdates(t *testing.T) {
+	r := NewRegistry()
+	counter := r.NewCounter("counter", "")
+	vec := r.NewCounterVec("vec", "", "label")
+	hist := r.NewHistogram("hist", "", ExponentialBuckets(1, 2, 4))
+
+	const goroutines, iterations = 8, 1000
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				counter.Inc()
+				vec.With("a").Inc()
+				hist.Observe(float64(j % 10))
+			}
+		}()
+	}
+	wg.Wait()
+
+	const want = goroutines * iterations
+	if got := counter.Value(); got != want {
+		t.Errorf("mismatched counter -- got %v, want %v", got, want)
+	}
+	if got := vec.With("a").Value(); got != want {
+		t.Errorf("mismatched vec counter -- got %v, want %v", got, want)
+	}
+	if got := hist.Count(); got != want {
+		t.Errorf("mismatched histogram count -- got %v, want %v", got, want)
+	}
+}
+
+// TestExponentialBuckets ensures the exponential bucket helper produces the
+// expected upper bounds.
+func TestExponentialBuckets(t *testing.T) {
+	got := ExponentialBuckets(0.001, 10, 4)
+	want := []float64{0.001, 0.01, 0.1, 1}
+	if len(got) != len(want) {
+		t.Fatalf("mismatched length -- got %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("mismatched bucket %d -- got %v, want %v", i, got[i],
+				want[i])
+		}
+	}
+}
+
+// TestServeHTTP ensures the registry serves metrics with the expected content
+// type and rejects unsupported methods.
+func TestServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.NewGauge("test_gauge", "A gauge.").Set(1)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != contentType {
+		t.Fatalf("mismatched content type -- got %q, want %q", got,
+			contentType)
+	}
+	if !strings.Contains(rec.Body.String(), "test_gauge 1\n") {
+		t.Fatalf("missing sample in body:\n%s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status code %d", rec.Code)
+	}
+}
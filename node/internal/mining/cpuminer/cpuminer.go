@@ -426,7 +426,7 @@ func (m *CPUMiner) solveBlockKawpowCPU(ctx context.Context, header *wire.BlockHe
 	nonceRange := [2]uint64{0, 0xFFFFFFFF}
 
 	// Call kawpow.Mine
-	headerHashArray := header.BlockHash()
+	headerHashArray := header.KawPowHeaderHash()
 	headerHash := headerHashArray[:]
 	startNonce := nonceRange[0]
 	nonceRangeSize := nonceRange[1] - nonceRange[0]
@@ -442,9 +442,9 @@ func (m *CPUMiner) solveBlockKawpowCPU(ctx context.Context, header *wire.BlockHe
 	if found && result != nil {
 		// Solution found - update header with result
 		header.Nonce = result.Nonce
-		copy(header.MixHash[:], result.MixHash)
+		header.MixHash = wire.KawPowToChainHash(&result.MixHash)
 		// Note: Hash is available in result.Hash if needed
-		log.Infof("KawPoW solution found for block %d: Nonce %x, MixDigest %x, Hash %x",
+		log.Infof("KawPoW solution found for block %d: Nonce %x, MixDigest %s, Hash %s",
 			header.Height, result.Nonce, result.MixHash, result.Hash)
 		return true
 	}
@@ -25,7 +25,6 @@ import (
 	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
 	"github.com/kdsmith18542/vigil/txscript/v4/stdscript"
 	"github.com/kdsmith18542/vigil/wire"
-	"github.com/kdsmith18542/vigil/kawpow"
 )
 
 var (
@@ -407,13 +406,15 @@ type BlockTemplate struct {
 	Block *wire.MsgBlock
 
 	// Fees contains the amount of fees each transaction in the generated
-	// template pays in base units.  Since the first transaction is the
-	// coinbase, the first entry (offset 0) will contain the negative of the
-	// sum of the fees of all other transactions.
+	// template pays in base units.  The entries are in block order with the
+	// regular transaction tree followed by the stake transaction tree.  Since
+	// the first transaction is the coinbase, the first entry (offset 0) will
+	// contain the negative of the sum of the fees of all other transactions.
 	Fees []int64
 
 	// SigOpCounts contains the number of signature operations each
-	// transaction in the generated template performs.
+	// transaction in the generated template performs.  The entries are in the
+	// same order as the fees.
 	SigOpCounts []int64
 
 	// Height is the height at which the block template connects to the main
@@ -1344,53 +1345,6 @@ func (g *BlkTmplGenerator) NewBlockTemplateKawPow(payToAddress stdaddr.Address)
 	}
 	blockTemplate.AddTransaction(VGLutil.NewTx(coinbaseTx))
 
-	// Calculate the KawPoW hash and mixhash.
-	// This is a placeholder for actual KawPoW mining logic.
-	// In a real scenario, this would involve a mining process to find a valid nonce and mixhash.
-	// For now, we'll use dummy values or a simplified calculation.
-
-	// Convert header to bytes for hashing.
-	headerBytes, err := blockTemplate.Block.Header.Bytes()
-	if err != nil {
-		return nil, err
-	}
-
-	// Calculate the KawPoW hash and mixhash.
-	// This is a placeholder for actual KawPoW mining logic.
-	// In a real scenario, this would involve a mining process to find a valid nonce and mixhash.
-	// For now, we'll use a simplified calculation.
-
-	// Calculate the block header hash.
-	headerHashChain := blockTemplate.Block.Header.BlockHash()
-
-	// Convert chainhash.Hash to kawpow.Hash type.
-	var headerHash kawpow.Hash
-	copy(headerHash[:], headerHashChain[:])
-
-	// Generate a dummy nonce for now. In a real scenario, this would be found through mining.
-	nonce := uint64(0)
-
-	// Create a dummy DAG for now. In a real scenario, this would be generated based on epoch.
-	dag := kawpow.NewDAG(uint64(blockTemplate.Block.Header.Height) / kawpow.EPOCH_LENGTH)
-	err = dag.Generate(uint64(blockTemplate.Block.Header.Height) / kawpow.EPOCH_LENGTH)
-	if err != nil {
-		return nil, err
-	}
-
-	// Calculate the KawPoW hash and mixhash.
-	_, mixHash, err := kawpow.KawPowHash(headerHash, nonce, blockTemplate.Block.Header.Height, dag)
-	if err != nil {
-		return nil, err
-	}
-
-	// Populate the block template with the calculated nonce and mixhash.
-	blockTemplate.Nonce = nonce
-	copy(blockTemplate.MixHash[:], mixHash)
-
-	// Set the nonce and mixhash in the block header as well.
-	blockTemplate.Block.Header.Nonce = nonce
-	copy(blockTemplate.Block.Header.MixHash[:], mixHash)
-
 	// Add all of the transactions from the transaction source pool to the block
 	// template.
 	numTxns := 0
@@ -1604,16 +1558,13 @@ func (g *BlkTmplGenerator) NewBlockTemplateKawPow(payToAddress stdaddr.Address)
 	blockUtxos := g.cfg.NewUtxoViewpoint()
 
 	// Create slices to hold the fees and number of signature operations
-	// for each of the selected transactions and add an entry for the
-	// coinbase.  This allows the code below to simply append details about
-	// a transaction as it is selected for inclusion in the final block.
-	// However, since the total fees aren't known yet, use a dummy value for
-	// the coinbase fee which will be updated later.
+	// for each of the selected transactions.  They are populated in block
+	// order once the final transactions are known, starting with the
+	// coinbase whose fee is updated to the negative of the total fees.
 	txFees := make([]int64, 0, len(sourceTxns))
 	txFeesMap := make(map[chainhash.Hash]int64)
 	txSigOpCounts := make([]int64, 0, len(sourceTxns))
 	txSigOpCountsMap := make(map[chainhash.Hash]int64)
-	txFees = append(txFees, -1) // Updated once known
 
 	log.Debugf("Considering %d transactions for inclusion to new block",
 		len(sourceTxns))
@@ -2424,8 +2375,6 @@ nextPriorityQueueItem:
 		totalFees /= int64(g.cfg.ChainParams.TicketsPerBlock)
 	}
 
-	txSigOpCounts = append(txSigOpCounts, numCoinbaseSigOps)
-
 	// Now that the actual transactions have been selected, update the
 	// block size for the real transaction count and coinbase value with
 	// the total fees accordingly.
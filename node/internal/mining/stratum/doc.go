@@ -0,0 +1,15 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package stratum serves the block templates of vgld to KawPoW mining software.
+
+It is a thin adapter around the shared stratum server provided by the
+github.com/kdsmith18542/vigil/stratum module, which is also used by vglpool.
+The adapter hands the headers of the block templates produced by the
+background block template generator to the shared server as work, and it
+reconstructs the full block for every solved header from the template it was
+created from so it can be processed like any other block from the network.
+*/
+package stratum
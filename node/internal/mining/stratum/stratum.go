@@ -0,0 +1,229 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stratum
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/internal/blockchain"
+	"github.com/kdsmith18542/vigil/internal/mining"
+	stratumserver "github.com/kdsmith18542/vigil/stratum"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+const (
+	// DefaultMaxClients is the default maximum number of clients that may be
+	// connected at once.
+	DefaultMaxClients = stratumserver.DefaultMaxClients
+
+	// MaxClientsLimit is the largest allowed maximum number of clients.
+	MaxClientsLimit = stratumserver.MaxClientsLimit
+
+	// maxTemplates is the maximum number of block templates for the current
+	// chain tip that solved block headers are matched against.  It is at
+	// least the number of jobs the stratum server accepts shares for.
+	maxTemplates = 8
+)
+
+// BlockTemplater provides the block templates clients of the server work on.
+// It is satisfied by mining.BgBlkTmplGenerator.
+type BlockTemplater interface {
+	// Subscribe returns a subscription for block template updates that
+	// immediately receives the current template, if any.
+	Subscribe() *mining.TemplateSubscription
+
+	// UpdateBlockTime updates the timestamp in the passed header to the
+	// current time while taking into account the consensus rules.
+	UpdateBlockTime(header *wire.BlockHeader)
+}
+
+// Config is a descriptor containing the stratum server configuration.
+type Config struct {
+	// ChainParams identifies which chain parameters the server is associated
+	// with.  The proof of work limit is used as the difficulty one target for
+	// share difficulties.
+	ChainParams *chaincfg.Params
+
+	// Listeners defines a slice of listeners for which the server will take
+	// ownership of and accept connections.
+	Listeners []net.Listener
+
+	// BlockTemplater provides the block templates clients work on.
+	BlockTemplater BlockTemplater
+
+	// ProcessBlock defines the function to call with any solved blocks.  It
+	// typically must run the provided block through the same set of rules and
+	// handling as any other block coming from the network.
+	ProcessBlock func(*VGLutil.Block) error
+
+	// InitialDifficulty is the share difficulty assigned to new clients.  It
+	// defaults to stratumserver.DefaultInitialDifficulty when zero.
+	InitialDifficulty float64
+
+	// MaxClients is the maximum number of clients that may be connected at
+	// once.  It defaults to DefaultMaxClients when zero and may not exceed
+	// MaxClientsLimit.
+	MaxClients int
+}
+
+// Server serves the block templates produced by the background block template
+// generator to KawPoW mining software via the shared stratum server and runs
+// any blocks they solve through the same processing as blocks from the
+// network.
+type Server struct {
+	cfg    Config
+	server *stratumserver.Server
+
+	// templates houses the blocks of the recent templates for the current
+	// chain tip keyed by their merkle root, which together with the parent
+	// identifies the template a solved header was created from.
+	mtx           sync.Mutex
+	templates     map[chainhash.Hash]*wire.MsgBlock
+	templateOrder []chainhash.Hash
+	prevBlock     chainhash.Hash
+}
+
+// New returns a new stratum server with the provided configuration.  An error
+// is returned when the configuration is invalid.
+func New(cfg *Config) (*Server, error) {
+	s := &Server{
+		cfg:       *cfg,
+		templates: make(map[chainhash.Hash]*wire.MsgBlock),
+	}
+	server, err := stratumserver.New(&stratumserver.Config{
+		DiffOne:           cfg.ChainParams.PowLimit,
+		Listeners:         cfg.Listeners,
+		SubmitBlock:       s.submitBlock,
+		InitialDifficulty: cfg.InitialDifficulty,
+		MaxClients:        cfg.MaxClients,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.server = server
+	return s, nil
+}
+
+// handleTemplate records the block of the provided block template and hands
+// its header to the stratum server as the new work.  All previously recorded
+// blocks are discarded when the template builds on a different parent.
+func (s *Server) handleTemplate(template *mining.BlockTemplate) {
+	if template == nil {
+		return
+	}
+
+	// Update the time of the block template to the current time while
+	// accounting for the median time of the past several blocks per the chain
+	// consensus rules.  Note that the header is copied to avoid mutating the
+	// shared block template.
+	header := template.Block.Header
+	s.cfg.BlockTemplater.UpdateBlockTime(&header)
+
+	s.mtx.Lock()
+	if header.PrevBlock != s.prevBlock {
+		s.templates = make(map[chainhash.Hash]*wire.MsgBlock)
+		s.templateOrder = s.templateOrder[:0]
+		s.prevBlock = header.PrevBlock
+	}
+	if _, ok := s.templates[header.MerkleRoot]; !ok {
+		s.templateOrder = append(s.templateOrder, header.MerkleRoot)
+	}
+	s.templates[header.MerkleRoot] = template.Block
+	if len(s.templateOrder) > maxTemplates {
+		delete(s.templates, s.templateOrder[0])
+		s.templateOrder = s.templateOrder[1:]
+	}
+	s.mtx.Unlock()
+
+	s.server.SetWork(&header)
+}
+
+// lookupBlock returns a copy of the block of the recorded template the
+// provided solved header was created from with its header replaced by the
+// solved one.  It returns nil when the template is no longer recorded.
+func (s *Server) lookupBlock(header *wire.BlockHeader) *wire.MsgBlock {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if header.PrevBlock != s.prevBlock {
+		return nil
+	}
+	templateBlock, ok := s.templates[header.MerkleRoot]
+	if !ok {
+		return nil
+	}
+	block := *templateBlock
+	block.Header = *header
+	return &block
+}
+
+// submitBlock submits the block for the provided solved header to the network
+// after ensuring it passes all of the consensus validation rules.  It returns
+// whether or not the block was accepted.
+func (s *Server) submitBlock(header *wire.BlockHeader, worker string) (bool, error) {
+	msgBlock := s.lookupBlock(header)
+	if msgBlock == nil {
+		log.Infof("Block submitted via stratum by %s rejected: stale "+
+			"template building on parent %v", worker, header.PrevBlock)
+		return false, nil
+	}
+
+	// Process this block using the same rules as blocks coming from other
+	// nodes.  This will in turn relay it to the network like normal.
+	block := VGLutil.NewBlock(msgBlock)
+	err := s.cfg.ProcessBlock(block)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrMissingParent) {
+			log.Infof("Block submitted via stratum rejected: orphan "+
+				"building on parent %v", header.PrevBlock)
+			return false, nil
+		}
+
+		// Anything other than a rule violation is an unexpected error, so
+		// return that error as an internal error.
+		var rErr blockchain.RuleError
+		if !errors.As(err, &rErr) {
+			return false, err
+		}
+
+		log.Infof("Block submitted via stratum rejected: %v", err)
+		return false, nil
+	}
+
+	// The block was accepted.
+	return true, nil
+}
+
+// Run starts the stratum server and blocks until the provided context is
+// cancelled.  It accepts connections on the configured listeners and sends new
+// jobs to clients as block templates are generated.
+func (s *Server) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.server.Run(ctx)
+	}()
+
+	templateSub := s.cfg.BlockTemplater.Subscribe()
+out:
+	for {
+		select {
+		case templateNtfn := <-templateSub.C():
+			s.handleTemplate(templateNtfn.Template)
+
+		case <-ctx.Done():
+			break out
+		}
+	}
+	templateSub.Stop()
+	wg.Wait()
+}
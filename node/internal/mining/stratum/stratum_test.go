@@ -0,0 +1,143 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stratum
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/internal/blockchain"
+	"github.com/kdsmith18542/vigil/internal/mining"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// fakeTemplater provides a BlockTemplater that sets a fixed block time.
+type fakeTemplater struct {
+	blockTime time.Time
+}
+
+// Subscribe is not used by the tests and returns nil.
+func (f *fakeTemplater) Subscribe() *mining.TemplateSubscription {
+	return nil
+}
+
+// UpdateBlockTime sets the timestamp of the passed header to the fixed block
+// time.
+func (f *fakeTemplater) UpdateBlockTime(header *wire.BlockHeader) {
+	header.Timestamp = f.blockTime
+}
+
+// TestSubmitBlock ensures solved headers are matched to the template they were
+// created from, the processed block consists of the template transactions and
+// the solved header, stale templates are rejected, and processing errors are
+// classified as expected.
+func TestSubmitBlock(t *testing.T) {
+	templater := &fakeTemplater{blockTime: time.Unix(1700000000, 0)}
+	var processed *VGLutil.Block
+	var processErr error
+	s, err := New(&Config{
+		ChainParams:    chaincfg.SimNetParams(),
+		BlockTemplater: templater,
+		ProcessBlock: func(block *VGLutil.Block) error {
+			processed = block
+			return processErr
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	// newTemplate returns a block template building on the provided parent
+	// with a distinct merkle root and coinbase.
+	newTemplate := func(prevBlock chainhash.Hash, id byte) *mining.BlockTemplate {
+		coinbase := wire.NewMsgTx()
+		coinbase.AddTxOut(wire.NewTxOut(int64(id), []byte{0x6a}))
+		return &mining.BlockTemplate{Block: &wire.MsgBlock{
+			Header: wire.BlockHeader{
+				PrevBlock:  prevBlock,
+				MerkleRoot: chainhash.Hash{id},
+				Height:     uint32(id),
+			},
+			Transactions: []*wire.MsgTx{coinbase},
+		}}
+	}
+
+	parent := chainhash.Hash{0x01}
+	template := newTemplate(parent, 1)
+	s.handleTemplate(template)
+	s.handleTemplate(newTemplate(parent, 2))
+	if template.Block.Header.Timestamp.Equal(templater.blockTime) {
+		t.Fatal("shared block template was mutated")
+	}
+
+	// Ensure a solved header for the first template is processed as the
+	// template block with the solved header.
+	solved := template.Block.Header
+	solved.Timestamp = templater.blockTime
+	solved.Nonce = 0x0001020304050607
+	accepted, err := s.submitBlock(&solved, "worker")
+	if err != nil || !accepted {
+		t.Fatalf("unexpected submit result -- got (%v, %v), want (true, "+
+			"<nil>)", accepted, err)
+	}
+	msgBlock := processed.MsgBlock()
+	if msgBlock.Header != solved {
+		t.Fatalf("unexpected processed header -- got %+v, want %+v",
+			msgBlock.Header, solved)
+	}
+	if len(msgBlock.Transactions) != 1 ||
+		msgBlock.Transactions[0] != template.Block.Transactions[0] {
+		t.Fatal("processed block does not have the template transactions")
+	}
+	if template.Block.Header.Nonce != 0 {
+		t.Fatal("shared block template was mutated")
+	}
+
+	// Ensure rule violations and orphans are rejected without an error while
+	// unexpected errors are returned.
+	processErr = blockchain.RuleError{Err: blockchain.ErrHighHash}
+	if accepted, err := s.submitBlock(&solved, "worker"); err != nil || accepted {
+		t.Fatalf("unexpected submit result for rule violation -- got (%v, "+
+			"%v)", accepted, err)
+	}
+	processErr = blockchain.ErrMissingParent
+	if accepted, err := s.submitBlock(&solved, "worker"); err != nil || accepted {
+		t.Fatalf("unexpected submit result for orphan -- got (%v, %v)",
+			accepted, err)
+	}
+	processErr = errors.New("database failure")
+	if _, err := s.submitBlock(&solved, "worker"); !errors.Is(err, processErr) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, processErr)
+	}
+	processErr = nil
+
+	// Ensure the templates are discarded once a template builds on a new
+	// parent.
+	s.handleTemplate(newTemplate(chainhash.Hash{0x02}, 3))
+	processed = nil
+	accepted, err = s.submitBlock(&solved, "worker")
+	if err != nil || accepted || processed != nil {
+		t.Fatalf("unexpected submit result for stale template -- got (%v, "+
+			"%v)", accepted, err)
+	}
+
+	// Ensure only the most recent templates are retained.
+	parent = chainhash.Hash{0x03}
+	for i := 0; i < maxTemplates+1; i++ {
+		s.handleTemplate(newTemplate(parent, byte(10+i)))
+	}
+	oldest := newTemplate(parent, 10).Block.Header
+	if block := s.lookupBlock(&oldest); block != nil {
+		t.Fatal("oldest template was not discarded")
+	}
+	newest := newTemplate(parent, byte(10+maxTemplates)).Block.Header
+	if block := s.lookupBlock(&newest); block == nil {
+		t.Fatal("newest template was not retained")
+	}
+}
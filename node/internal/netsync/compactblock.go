@@ -0,0 +1,312 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dchest/siphash"
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/blockchain/standalone/v2"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/internal/mempool"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+var (
+	// errShortIDCollision is returned when a compact block contains multiple
+	// transactions in the same tree that share the same short ID which makes
+	// it impossible to reconstruct the block.
+	errShortIDCollision = errors.New("compact block contains duplicate " +
+		"short transaction IDs")
+
+	// errBlockTxnMismatch is returned when a blocktxn message does not
+	// provide exactly the transactions that were requested.
+	errBlockTxnMismatch = errors.New("blocktxn message does not match the " +
+		"requested transactions")
+
+	// errMerkleRootMismatch is returned when a reconstructed block does not
+	// commit to the merkle roots in its header which typically means one of
+	// the short IDs matched the wrong transaction.
+	errMerkleRootMismatch = errors.New("reconstructed block merkle root " +
+		"mismatch")
+)
+
+// shortIDKeys returns the keys to use with SipHash-2-4 to calculate the short
+// transaction IDs for a compact block with the provided header and nonce.
+//
+// The keys are the first two little-endian 64-bit integers of the BLAKE-256
+// hash of the serialized header followed by the little-endian nonce.
+func shortIDKeys(header *wire.BlockHeader, nonce uint64) (uint64, uint64, error) {
+	headerBytes, err := header.Bytes()
+	if err != nil {
+		return 0, 0, err
+	}
+	preimage := make([]byte, len(headerBytes)+8)
+	copy(preimage, headerBytes)
+	binary.LittleEndian.PutUint64(preimage[len(headerBytes):], nonce)
+	hash := chainhash.HashB(preimage)
+	k0 := binary.LittleEndian.Uint64(hash[0:8])
+	k1 := binary.LittleEndian.Uint64(hash[8:16])
+	return k0, k1, nil
+}
+
+// shortTxID returns the short transaction ID of the transaction with the
+// provided hash for the given keys.
+func shortTxID(k0, k1 uint64, txHash *chainhash.Hash) uint64 {
+	return siphash.Hash(k0, k1, txHash[:]) & wire.MaxShortTxID
+}
+
+// isPrefilledStakeTx returns whether or not the provided stake transaction
+// should be sent in full in a compact block.  Votes and the treasurybase are
+// unlikely to be in the mempool of the receiver by the time the block arrives,
+// so they are always prefilled.
+func isPrefilledStakeTx(tx *wire.MsgTx) bool {
+	return stake.IsSSGen(tx) || stake.IsTreasuryBase(tx)
+}
+
+// NewCompactBlock returns a compact block for the provided block using the
+// given nonce to derive the short transaction IDs.  The coinbase, votes, and
+// treasurybase are prefilled while all other transactions are identified by
+// their short IDs.
+func NewCompactBlock(block *wire.MsgBlock, nonce uint64) (*wire.MsgCmpctBlock, error) {
+	k0, k1, err := shortIDKeys(&block.Header, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := wire.NewMsgCmpctBlock(&block.Header, nonce)
+	for i, tx := range block.Transactions {
+		if i == 0 {
+			msg.PrefilledTxs = append(msg.PrefilledTxs, wire.PrefilledTx{
+				Index: 0,
+				Tx:    tx,
+			})
+			continue
+		}
+		txHash := tx.TxHash()
+		msg.ShortIDs = append(msg.ShortIDs, shortTxID(k0, k1, &txHash))
+	}
+	for i, tx := range block.STransactions {
+		if isPrefilledStakeTx(tx) {
+			msg.PrefilledSTxs = append(msg.PrefilledSTxs, wire.PrefilledTx{
+				Index: uint32(i),
+				Tx:    tx,
+			})
+			continue
+		}
+		txHash := tx.TxHash()
+		msg.StakeShortIDs = append(msg.StakeShortIDs, shortTxID(k0, k1,
+			&txHash))
+	}
+	return msg, nil
+}
+
+// partialTxTree houses the state of a single transaction tree of a block that
+// is being reconstructed from a compact block.
+type partialTxTree struct {
+	// txns houses the transactions of the tree.  Entries are nil for the
+	// transactions that are not yet known.
+	txns []*wire.MsgTx
+
+	// slots maps the short IDs of the transactions that were not prefilled
+	// to their index in the tree.
+	slots map[uint64]uint32
+
+	// collided tracks the indices for which multiple candidate transactions
+	// were found so they are requested instead of guessed.
+	collided map[uint32]struct{}
+}
+
+// newPartialTxTree returns a partial transaction tree populated with the
+// provided prefilled transactions and with the remaining positions assigned
+// to the provided short IDs in order.
+func newPartialTxTree(shortIDs []uint64, prefilled []wire.PrefilledTx) (*partialTxTree, error) {
+	numTxns := len(shortIDs) + len(prefilled)
+	tree := &partialTxTree{
+		txns:  make([]*wire.MsgTx, numTxns),
+		slots: make(map[uint64]uint32, len(shortIDs)),
+	}
+	for i := range prefilled {
+		// The wire package ensures the indices are in range.
+		tree.txns[prefilled[i].Index] = prefilled[i].Tx
+	}
+	var shortIDIdx int
+	for i := 0; i < numTxns; i++ {
+		if tree.txns[i] != nil {
+			continue
+		}
+		shortID := shortIDs[shortIDIdx]
+		shortIDIdx++
+		if _, ok := tree.slots[shortID]; ok {
+			return nil, errShortIDCollision
+		}
+		tree.slots[shortID] = uint32(i)
+	}
+	return tree, nil
+}
+
+// match fills in the transaction with the provided short ID if it is
+// referenced by the tree.  Positions for which multiple candidates are found
+// are left unfilled so they are requested from the peer.
+func (t *partialTxTree) match(shortID uint64, tx *wire.MsgTx) {
+	index, ok := t.slots[shortID]
+	if !ok {
+		return
+	}
+	if _, ok := t.collided[index]; ok {
+		return
+	}
+	if t.txns[index] != nil {
+		if t.collided == nil {
+			t.collided = make(map[uint32]struct{})
+		}
+		t.collided[index] = struct{}{}
+		t.txns[index] = nil
+		return
+	}
+	t.txns[index] = tx
+}
+
+// missing returns the indices of the transactions in the tree that are not
+// yet known.
+func (t *partialTxTree) missing() []uint32 {
+	var indexes []uint32
+	for i, tx := range t.txns {
+		if tx == nil {
+			indexes = append(indexes, uint32(i))
+		}
+	}
+	return indexes
+}
+
+// fill sets the provided transactions at the given indices.
+func (t *partialTxTree) fill(indexes []uint32, txns []*wire.MsgTx) error {
+	if len(indexes) != len(txns) {
+		return errBlockTxnMismatch
+	}
+	for i, index := range indexes {
+		t.txns[index] = txns[i]
+	}
+	return nil
+}
+
+// partialBlock houses the state of a block that is being reconstructed from a
+// compact block along with any transactions that have been requested from the
+// peer that sent it.
+type partialBlock struct {
+	hash   chainhash.Hash
+	header wire.BlockHeader
+	txns   *partialTxTree
+	stxns  *partialTxTree
+
+	// missingTxns and missingSTxns are the indices of the transactions in
+	// each tree that have been requested from the peer.
+	missingTxns  []uint32
+	missingSTxns []uint32
+}
+
+// newPartialBlock attempts to reconstruct the block described by the provided
+// compact block using the transactions in the given mempool descriptors.
+//
+// The missing transactions are recorded in the returned partial block so they
+// can be requested from the peer.
+func newPartialBlock(msg *wire.MsgCmpctBlock, txDescs []*mempool.TxDesc) (*partialBlock, error) {
+	txns, err := newPartialTxTree(msg.ShortIDs, msg.PrefilledTxs)
+	if err != nil {
+		return nil, err
+	}
+	stxns, err := newPartialTxTree(msg.StakeShortIDs, msg.PrefilledSTxs)
+	if err != nil {
+		return nil, err
+	}
+	k0, k1, err := shortIDKeys(&msg.Header, msg.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, desc := range txDescs {
+		tx := desc.Tx.MsgTx()
+		shortID := shortTxID(k0, k1, desc.Tx.Hash())
+		if desc.Type == stake.TxTypeRegular {
+			txns.match(shortID, tx)
+			continue
+		}
+		stxns.match(shortID, tx)
+	}
+
+	return &partialBlock{
+		hash:         msg.Header.BlockHash(),
+		header:       msg.Header,
+		txns:         txns,
+		stxns:        stxns,
+		missingTxns:  txns.missing(),
+		missingSTxns: stxns.missing(),
+	}, nil
+}
+
+// isComplete returns whether or not all of the transactions of the block are
+// known.
+func (b *partialBlock) isComplete() bool {
+	return len(b.missingTxns) == 0 && len(b.missingSTxns) == 0
+}
+
+// getBlockTxnMsg returns a getblocktxn message that requests the missing
+// transactions of the block.
+func (b *partialBlock) getBlockTxnMsg() *wire.MsgGetBlockTxn {
+	msg := wire.NewMsgGetBlockTxn(&b.hash)
+	msg.Indexes = b.missingTxns
+	msg.StakeIndexes = b.missingSTxns
+	return msg
+}
+
+// fill sets the missing transactions of the block from the provided blocktxn
+// message.  The message must provide exactly the requested transactions.
+func (b *partialBlock) fill(msg *wire.MsgBlockTxn) error {
+	if msg.BlockHash != b.hash {
+		return errBlockTxnMismatch
+	}
+	if err := b.txns.fill(b.missingTxns, msg.Transactions); err != nil {
+		return err
+	}
+	if err := b.stxns.fill(b.missingSTxns, msg.STransactions); err != nil {
+		return err
+	}
+	b.missingTxns, b.missingSTxns = nil, nil
+	return nil
+}
+
+// calcTxTreeRoot returns the merkle root of the full hashes of the provided
+// transactions in the same way block validation does.
+func calcTxTreeRoot(txns []*wire.MsgTx) chainhash.Hash {
+	txHashes := make([]chainhash.Hash, len(txns))
+	for i, tx := range txns {
+		txHashes[i] = tx.TxHashFull()
+	}
+	return standalone.CalcMerkleRoot(txHashes)
+}
+
+// block returns the reconstructed block once all of its transactions are
+// known.  An error is returned when the transactions do not commit to the
+// merkle roots in the header.
+func (b *partialBlock) block() (*wire.MsgBlock, error) {
+	if !b.isComplete() {
+		return nil, fmt.Errorf("block %v is missing transactions", b.hash)
+	}
+
+	msgBlock := &wire.MsgBlock{
+		Header:        b.header,
+		Transactions:  b.txns.txns,
+		STransactions: b.stxns.txns,
+	}
+	merkleRoot := calcTxTreeRoot(msgBlock.Transactions)
+	stakeRoot := calcTxTreeRoot(msgBlock.STransactions)
+	if merkleRoot != b.header.MerkleRoot || stakeRoot != b.header.StakeRoot {
+		return nil, errMerkleRootMismatch
+	}
+	return msgBlock, nil
+}
@@ -0,0 +1,417 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/internal/mempool"
+	"github.com/kdsmith18542/vigil/internal/mining"
+	peerpkg "github.com/kdsmith18542/vigil/peer/v3"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// newTestTx returns a transaction that is made unique by the provided value.
+func newTestTx(value int64) *wire.MsgTx {
+	tx := wire.NewMsgTx()
+	tx.AddTxOut(wire.NewTxOut(value, []byte{0x51}))
+	return tx
+}
+
+// newTestBlock returns a block with a coinbase followed by the provided number
+// of regular transactions and the provided number of stake transactions that
+// are not prefilled in compact blocks.  The merkle roots commit to the
+// transactions.
+func newTestBlock(numTxns, numSTxns int) *wire.MsgBlock {
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   1,
+			Height:    100,
+			Timestamp: time.Unix(1700000000, 0),
+		},
+	}
+	block.Transactions = append(block.Transactions, newTestTx(0))
+	for i := 0; i < numTxns; i++ {
+		block.Transactions = append(block.Transactions, newTestTx(int64(i+1)))
+	}
+	for i := 0; i < numSTxns; i++ {
+		block.STransactions = append(block.STransactions,
+			newTestTx(int64(i+1000)))
+	}
+	block.Header.MerkleRoot = calcTxTreeRoot(block.Transactions)
+	block.Header.StakeRoot = calcTxTreeRoot(block.STransactions)
+	return block
+}
+
+// newTestTxDesc returns a mempool descriptor for the provided transaction of
+// the provided type.
+func newTestTxDesc(tx *wire.MsgTx, txType stake.TxType) *mempool.TxDesc {
+	return &mempool.TxDesc{TxDesc: mining.TxDesc{
+		Tx:   VGLutil.NewTx(tx),
+		Type: txType,
+	}}
+}
+
+// TestNewPartialTxTree ensures partial transaction trees assign the short IDs
+// to the positions that are not prefilled in order and reject compact blocks
+// with duplicate short IDs.
+func TestNewPartialTxTree(t *testing.T) {
+	tx0, tx2 := newTestTx(0), newTestTx(2)
+	prefilled := []wire.PrefilledTx{{Index: 0, Tx: tx0}, {Index: 2, Tx: tx2}}
+	tree, err := newPartialTxTree([]uint64{10, 11}, prefilled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantTxns := []*wire.MsgTx{tx0, nil, tx2, nil}
+	if !reflect.DeepEqual(tree.txns, wantTxns) {
+		t.Fatalf("unexpected transactions -- got %v, want %v", tree.txns,
+			wantTxns)
+	}
+	wantSlots := map[uint64]uint32{10: 1, 11: 3}
+	if !reflect.DeepEqual(tree.slots, wantSlots) {
+		t.Fatalf("unexpected slots -- got %v, want %v", tree.slots,
+			wantSlots)
+	}
+	if missing := tree.missing(); !reflect.DeepEqual(missing, []uint32{1, 3}) {
+		t.Fatalf("unexpected missing transactions -- got %v, want [1 3]",
+			missing)
+	}
+
+	_, err = newPartialTxTree([]uint64{10, 10}, prefilled)
+	if !errors.Is(err, errShortIDCollision) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			errShortIDCollision)
+	}
+}
+
+// TestPartialTxTreeMatch ensures transactions are only matched to the
+// positions of their short IDs and positions with multiple candidates are left
+// unfilled so they are requested instead.
+func TestPartialTxTreeMatch(t *testing.T) {
+	tree, err := newPartialTxTree([]uint64{10, 11}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Ensure transactions for unknown short IDs are ignored and known ones
+	// are matched.
+	tx1, tx2, tx3 := newTestTx(1), newTestTx(2), newTestTx(3)
+	tree.match(12, tx1)
+	tree.match(11, tx1)
+	if tree.txns[0] != nil || tree.txns[1] != tx1 {
+		t.Fatalf("unexpected transactions after match -- got %v",
+			tree.txns)
+	}
+
+	// Ensure a second candidate for the same short ID unfills the position
+	// and any further candidates are not matched.
+	tree.match(11, tx2)
+	tree.match(11, tx3)
+	if tree.txns[1] != nil {
+		t.Fatal("position with multiple candidates was filled")
+	}
+	if _, ok := tree.collided[1]; !ok {
+		t.Fatal("position with multiple candidates was not marked collided")
+	}
+	if missing := tree.missing(); !reflect.DeepEqual(missing, []uint32{0, 1}) {
+		t.Fatalf("unexpected missing transactions -- got %v, want [0 1]",
+			missing)
+	}
+}
+
+// TestPartialTxTreeFill ensures the missing transactions of a partial
+// transaction tree are only filled when the number of provided transactions
+// matches the number of requested indices.
+func TestPartialTxTreeFill(t *testing.T) {
+	tx0, tx1, tx2 := newTestTx(0), newTestTx(1), newTestTx(2)
+	tree, err := newPartialTxTree([]uint64{10, 11},
+		[]wire.PrefilledTx{{Index: 0, Tx: tx0}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	indexes := tree.missing()
+	err = tree.fill(indexes, []*wire.MsgTx{tx1})
+	if !errors.Is(err, errBlockTxnMismatch) {
+		t.Fatalf("unexpected error for too few transactions -- got %v, "+
+			"want %v", err, errBlockTxnMismatch)
+	}
+	err = tree.fill(indexes, []*wire.MsgTx{tx1, tx2, tx0})
+	if !errors.Is(err, errBlockTxnMismatch) {
+		t.Fatalf("unexpected error for too many transactions -- got %v, "+
+			"want %v", err, errBlockTxnMismatch)
+	}
+	if tree.txns[1] != nil || tree.txns[2] != nil {
+		t.Fatal("transactions filled from mismatched blocktxn")
+	}
+
+	if err := tree.fill(indexes, []*wire.MsgTx{tx1, tx2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantTxns := []*wire.MsgTx{tx0, tx1, tx2}
+	if !reflect.DeepEqual(tree.txns, wantTxns) {
+		t.Fatalf("unexpected transactions -- got %v, want %v", tree.txns,
+			wantTxns)
+	}
+}
+
+// TestCompactBlockReconstruction ensures blocks are reconstructed from compact
+// blocks using the transactions in the mempool and any missing transactions
+// provided via blocktxn, and that compact blocks that can't be reconstructed
+// result in errors that cause the full block to be requested instead.
+func TestCompactBlockReconstruction(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// mutateBlock modifies the block before the compact block is
+		// created, if set.
+		mutateBlock func(block *wire.MsgBlock)
+
+		// mutateCmpct modifies the compact block before it is
+		// reconstructed, if set.
+		mutateCmpct func(msg *wire.MsgCmpctBlock)
+
+		// mempoolTxns and mempoolSTxns are the indices of the regular and
+		// stake transactions of the block that are in the mempool.
+		mempoolTxns  []int
+		mempoolSTxns []int
+
+		// blockTxn returns the blocktxn message sent in response to the
+		// request for the missing transactions, if any.
+		blockTxn func(block *wire.MsgBlock) *wire.MsgBlockTxn
+
+		wantPartialErr error
+		wantMissing    []uint32
+		wantMissingS   []uint32
+		wantFillErr    error
+		wantBlockErr   error
+	}{{
+		name:         "full mempool match",
+		mempoolTxns:  []int{1, 2, 3},
+		mempoolSTxns: []int{0, 1},
+	}, {
+		name:         "missing transactions filled via blocktxn",
+		mempoolTxns:  []int{2},
+		mempoolSTxns: []int{0},
+		blockTxn: func(block *wire.MsgBlock) *wire.MsgBlockTxn {
+			return &wire.MsgBlockTxn{
+				BlockHash: block.BlockHash(),
+				Transactions: []*wire.MsgTx{block.Transactions[1],
+					block.Transactions[3]},
+				STransactions: []*wire.MsgTx{block.STransactions[1]},
+			}
+		},
+		wantMissing:  []uint32{1, 3},
+		wantMissingS: []uint32{1},
+	}, {
+		name: "short id collision falls back to full block",
+		mutateCmpct: func(msg *wire.MsgCmpctBlock) {
+			msg.ShortIDs[1] = msg.ShortIDs[0]
+		},
+		mempoolTxns:    []int{1, 2, 3},
+		mempoolSTxns:   []int{0, 1},
+		wantPartialErr: errShortIDCollision,
+	}, {
+		name: "merkle mismatch falls back to full block",
+		mutateBlock: func(block *wire.MsgBlock) {
+			block.Header.MerkleRoot[0] ^= 0xff
+		},
+		mempoolTxns:  []int{1, 2, 3},
+		mempoolSTxns: []int{0, 1},
+		wantBlockErr: errMerkleRootMismatch,
+	}, {
+		name:         "blocktxn with wrong transaction falls back to full block",
+		mempoolTxns:  []int{1, 2},
+		mempoolSTxns: []int{0, 1},
+		blockTxn: func(block *wire.MsgBlock) *wire.MsgBlockTxn {
+			return &wire.MsgBlockTxn{
+				BlockHash:    block.BlockHash(),
+				Transactions: []*wire.MsgTx{newTestTx(999)},
+			}
+		},
+		wantMissing:  []uint32{3},
+		wantBlockErr: errMerkleRootMismatch,
+	}, {
+		name:         "blocktxn with wrong count rejected",
+		mempoolTxns:  []int{2},
+		mempoolSTxns: []int{0, 1},
+		blockTxn: func(block *wire.MsgBlock) *wire.MsgBlockTxn {
+			return &wire.MsgBlockTxn{
+				BlockHash:    block.BlockHash(),
+				Transactions: []*wire.MsgTx{block.Transactions[1]},
+			}
+		},
+		wantMissing: []uint32{1, 3},
+		wantFillErr: errBlockTxnMismatch,
+	}, {
+		name:         "blocktxn for wrong block rejected",
+		mempoolTxns:  []int{2, 3},
+		mempoolSTxns: []int{0, 1},
+		blockTxn: func(block *wire.MsgBlock) *wire.MsgBlockTxn {
+			return &wire.MsgBlockTxn{
+				Transactions: []*wire.MsgTx{block.Transactions[1]},
+			}
+		},
+		wantMissing: []uint32{1},
+		wantFillErr: errBlockTxnMismatch,
+	}}
+
+	for _, test := range tests {
+		block := newTestBlock(3, 2)
+		if test.mutateBlock != nil {
+			test.mutateBlock(block)
+		}
+		msg, err := NewCompactBlock(block, 0x0102030405060708)
+		if err != nil {
+			t.Errorf("%q: unexpected error creating compact block: %v",
+				test.name, err)
+			continue
+		}
+		if len(msg.PrefilledTxs) != 1 || msg.PrefilledTxs[0].Tx !=
+			block.Transactions[0] {
+			t.Errorf("%q: coinbase was not prefilled", test.name)
+			continue
+		}
+		if test.mutateCmpct != nil {
+			test.mutateCmpct(msg)
+		}
+
+		// Add the mempool transactions along with an unrelated transaction
+		// that must not be matched.
+		txDescs := []*mempool.TxDesc{newTestTxDesc(newTestTx(500),
+			stake.TxTypeRegular)}
+		for _, i := range test.mempoolTxns {
+			txDescs = append(txDescs, newTestTxDesc(block.Transactions[i],
+				stake.TxTypeRegular))
+		}
+		for _, i := range test.mempoolSTxns {
+			txDescs = append(txDescs, newTestTxDesc(block.STransactions[i],
+				stake.TxTypeSStx))
+		}
+
+		partial, err := newPartialBlock(msg, txDescs)
+		if !errors.Is(err, test.wantPartialErr) {
+			t.Errorf("%q: unexpected error reconstructing block -- got %v, "+
+				"want %v", test.name, err, test.wantPartialErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(partial.missingTxns, test.wantMissing) ||
+			!reflect.DeepEqual(partial.missingSTxns, test.wantMissingS) {
+			t.Errorf("%q: unexpected missing transactions -- got (%v, %v), "+
+				"want (%v, %v)", test.name, partial.missingTxns,
+				partial.missingSTxns, test.wantMissing, test.wantMissingS)
+			continue
+		}
+
+		// Ensure the missing transactions are requested and provided via
+		// blocktxn.
+		if !partial.isComplete() {
+			if _, err := partial.block(); err == nil {
+				t.Errorf("%q: incomplete block was reconstructed", test.name)
+				continue
+			}
+			getBlockTxn := partial.getBlockTxnMsg()
+			if getBlockTxn.BlockHash != block.BlockHash() ||
+				!reflect.DeepEqual(getBlockTxn.Indexes, test.wantMissing) ||
+				!reflect.DeepEqual(getBlockTxn.StakeIndexes,
+					test.wantMissingS) {
+				t.Errorf("%q: unexpected getblocktxn message %+v",
+					test.name, getBlockTxn)
+				continue
+			}
+			err := partial.fill(test.blockTxn(block))
+			if !errors.Is(err, test.wantFillErr) {
+				t.Errorf("%q: unexpected error filling block -- got %v, "+
+					"want %v", test.name, err, test.wantFillErr)
+				continue
+			}
+			if err != nil {
+				continue
+			}
+		}
+
+		reconstructed, err := partial.block()
+		if !errors.Is(err, test.wantBlockErr) {
+			t.Errorf("%q: unexpected error completing block -- got %v, "+
+				"want %v", test.name, err, test.wantBlockErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(reconstructed, block) {
+			t.Errorf("%q: reconstructed block does not match -- got %v, "+
+				"want %v", test.name, reconstructed, block)
+		}
+	}
+}
+
+// TestHandleBlockTxnMsgUnrequested ensures peers that send blocktxn messages
+// that were not requested or do not match the request are disconnected.
+func TestHandleBlockTxnMsgUnrequested(t *testing.T) {
+	block := newTestBlock(2, 0)
+	msg, err := NewCompactBlock(block, 0)
+	if err != nil {
+		t.Fatalf("unexpected error creating compact block: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		pending  bool
+		blockTxn *wire.MsgBlockTxn
+	}{{
+		name: "no pending compact block",
+		blockTxn: &wire.MsgBlockTxn{
+			BlockHash:    block.BlockHash(),
+			Transactions: block.Transactions[1:],
+		},
+	}, {
+		name:    "different block",
+		pending: true,
+		blockTxn: &wire.MsgBlockTxn{
+			Transactions: block.Transactions[1:],
+		},
+	}, {
+		name:    "wrong count",
+		pending: true,
+		blockTxn: &wire.MsgBlockTxn{
+			BlockHash:    block.BlockHash(),
+			Transactions: block.Transactions[1:2],
+		},
+	}}
+
+	m := &SyncManager{}
+	for _, test := range tests {
+		peer := NewPeer(peerpkg.NewInboundPeer(&peerpkg.Config{}))
+		if test.pending {
+			partial, err := newPartialBlock(msg, nil)
+			if err != nil {
+				t.Fatalf("%q: unexpected error reconstructing block: %v",
+					test.name, err)
+			}
+			peer.pendingCmpctBlock = partial
+		}
+
+		m.handleBlockTxnMsg(&blockTxnMsg{blockTxn: test.blockTxn, peer: peer})
+		disconnected := make(chan struct{})
+		go func() {
+			peer.WaitForDisconnect()
+			close(disconnected)
+		}()
+		select {
+		case <-disconnected:
+		case <-time.After(time.Second):
+			t.Errorf("%q: peer was not disconnected", test.name)
+		}
+	}
+}
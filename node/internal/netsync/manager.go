@@ -118,6 +118,22 @@ type blockMsg struct {
 	reply chan struct{}
 }
 
+// cmpctBlockMsg packages a Vigil cmpctblock message and the peer it came from
+// together so the event handler has access to that information.
+type cmpctBlockMsg struct {
+	cmpctBlock *wire.MsgCmpctBlock
+	peer       *Peer
+	reply      chan struct{}
+}
+
+// blockTxnMsg packages a Vigil blocktxn message and the peer it came from
+// together so the event handler has access to that information.
+type blockTxnMsg struct {
+	blockTxn *wire.MsgBlockTxn
+	peer     *Peer
+	reply    chan struct{}
+}
+
 // invMsg packages a Vigil inv message and the peer it came from together
 // so the event handler has access to that information.
 type invMsg struct {
@@ -243,6 +259,10 @@ type Peer struct {
 	announcedOrphanBlock *chainhash.Hash
 	bestAnnouncedBlock   *chainhash.Hash
 	bestAnnouncedWork    *uint256.Uint256
+
+	// pendingCmpctBlock is the block sent by the peer as a compact block that
+	// is waiting on the missing transactions requested via getblocktxn.
+	pendingCmpctBlock *partialBlock
 }
 
 // NewPeer returns a new instance of a peer that wraps the provided underlying
@@ -1035,6 +1055,14 @@ func (m *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 		return
 	}
 
+	// Discard any compact block reconstruction that is in progress for the
+	// block since the full block is now available.
+	if pending := peer.pendingCmpctBlock; pending != nil &&
+		pending.hash == *blockHash {
+
+		peer.pendingCmpctBlock = nil
+	}
+
 	// Save whether or not the chain believes it is current prior to processing
 	// the block for use below in determining logging behavior.
 	chain := m.cfg.Chain
@@ -1103,6 +1131,7 @@ func (m *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 
 		return
 	}
+	m.updateSyncMetrics()
 
 	// Log information about the block.  Use the progress logger when the chain
 	// was not already current prior to processing the block to provide nicer
@@ -1162,6 +1191,100 @@ func (m *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 	}
 }
 
+// requestFullBlock requests the full block with the provided hash from the
+// peer.  It is used to fall back to the full block when a compact block can't
+// be reconstructed.  The block must already be tracked as requested from the
+// peer.
+func (m *SyncManager) requestFullBlock(peer *Peer, hash *chainhash.Hash) {
+	gdmsg := wire.NewMsgGetDataSizeHint(1)
+	gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, hash))
+	peer.QueueMessage(gdmsg, nil)
+}
+
+// processReconstructedBlock processes a block that was fully reconstructed
+// from a compact block in the same way as a block received in full.  The full
+// block is requested instead when the reconstructed transactions do not match
+// the header.
+func (m *SyncManager) processReconstructedBlock(peer *Peer, partial *partialBlock) {
+	msgBlock, err := partial.block()
+	if err != nil {
+		log.Debugf("Unable to reconstruct compact block %v from %s: %v -- "+
+			"requesting full block", partial.hash, peer, err)
+		m.requestFullBlock(peer, &partial.hash)
+		return
+	}
+
+	m.handleBlockMsg(&blockMsg{block: VGLutil.NewBlock(msgBlock), peer: peer})
+}
+
+// handleCmpctBlockMsg handles cmpctblock messages from all peers.  It attempts
+// to reconstruct the block from the transactions in the mempool and requests
+// any missing transactions from the peer.  The full block is requested when
+// the block can't be reconstructed.
+func (m *SyncManager) handleCmpctBlockMsg(cmsg *cmpctBlockMsg) {
+	peer := cmsg.peer
+
+	// The remote peer is misbehaving when the block was not requested.
+	blockHash := cmsg.cmpctBlock.Header.BlockHash()
+	if _, exists := peer.requestedBlocks[blockHash]; !exists {
+		log.Warnf("Got unrequested compact block %v from %s -- "+
+			"disconnecting", blockHash, peer)
+		peer.Disconnect()
+		return
+	}
+
+	// Nothing more to do when the block is already known.
+	if m.cfg.Chain.HaveBlock(&blockHash) {
+		delete(peer.requestedBlocks, blockHash)
+		delete(m.requestedBlocks, blockHash)
+		return
+	}
+
+	partial, err := newPartialBlock(cmsg.cmpctBlock, m.cfg.TxMemPool.TxDescs())
+	if err != nil {
+		log.Debugf("Unable to reconstruct compact block %v from %s: %v -- "+
+			"requesting full block", blockHash, peer, err)
+		m.requestFullBlock(peer, &blockHash)
+		return
+	}
+	if partial.isComplete() {
+		m.processReconstructedBlock(peer, partial)
+		return
+	}
+
+	log.Debugf("Requesting %d missing transactions for compact block %v "+
+		"from %s", len(partial.missingTxns)+len(partial.missingSTxns),
+		blockHash, peer)
+	peer.pendingCmpctBlock = partial
+	peer.QueueMessage(partial.getBlockTxnMsg(), nil)
+}
+
+// handleBlockTxnMsg handles blocktxn messages from all peers.  It completes
+// the reconstruction of the compact block the transactions were requested for
+// and processes the resulting block.
+func (m *SyncManager) handleBlockTxnMsg(bmsg *blockTxnMsg) {
+	peer := bmsg.peer
+
+	// The remote peer is misbehaving when the transactions were not
+	// requested or do not match the request.
+	partial := peer.pendingCmpctBlock
+	if partial == nil || partial.hash != bmsg.blockTxn.BlockHash {
+		log.Warnf("Got unrequested blocktxn for block %v from %s -- "+
+			"disconnecting", bmsg.blockTxn.BlockHash, peer)
+		peer.Disconnect()
+		return
+	}
+	peer.pendingCmpctBlock = nil
+	if err := partial.fill(bmsg.blockTxn); err != nil {
+		log.Warnf("Got invalid blocktxn for block %v from %s: %v -- "+
+			"disconnecting", partial.hash, peer, err)
+		peer.Disconnect()
+		return
+	}
+
+	m.processReconstructedBlock(peer, partial)
+}
+
 // guessHeaderSyncProgress returns a percentage that is a guess of the progress
 // of the header sync progress for the given currently best known header based
 // on an algorithm that considers the total number of expected headers based on
@@ -1404,6 +1527,8 @@ func (m *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 		}
 	}
 
+	m.updateSyncMetrics()
+
 	// Request more headers when the peer announced the maximum number of
 	// headers that can be sent in a single message since it probably has more.
 	if receivedMaxHeaders {
@@ -1463,7 +1588,15 @@ func (m *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 	// available headers once that code supports downloading from multiple peers
 	// and associated infrastructure to efficiently determine which peers have
 	// the associated block(s).
+	//
+	// Compact blocks are requested from peers that support them since the
+	// majority of the transactions in newly announced blocks are typically
+	// already in the mempool.
 	if isChainCurrent {
+		invType := wire.InvTypeBlock
+		if peer.ProtocolVersion() >= wire.CompactBlocksVersion {
+			invType = wire.InvTypeCompactBlock
+		}
 		gdmsg := wire.NewMsgGetDataSizeHint(uint(len(headers)))
 		for i := range headerHashes {
 			// Skip the block when it has already been requested or is otherwise
@@ -1481,7 +1614,7 @@ func (m *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 
 			m.requestedBlocks[*hash] = peer
 			peer.requestedBlocks[*hash] = struct{}{}
-			iv := wire.NewInvVect(wire.InvTypeBlock, hash)
+			iv := wire.NewInvVect(invType, hash)
 			gdmsg.AddInvVect(iv)
 		}
 		if len(gdmsg.InvList) > 0 {
@@ -1504,7 +1637,7 @@ func (m *SyncManager) handleNotFoundMsg(nfmsg *notFoundMsg) {
 		// verify the hash was actually announced by the peer
 		// before deleting from the global requested maps.
 		switch inv.Type {
-		case wire.InvTypeBlock:
+		case wire.InvTypeBlock, wire.InvTypeCompactBlock:
 			if _, exists := peer.requestedBlocks[inv.Hash]; exists {
 				delete(peer.requestedBlocks, inv.Hash)
 				delete(m.requestedBlocks, inv.Hash)
@@ -1743,6 +1876,20 @@ out:
 				case <-ctx.Done():
 				}
 
+			case *cmpctBlockMsg:
+				m.handleCmpctBlockMsg(msg)
+				select {
+				case msg.reply <- struct{}{}:
+				case <-ctx.Done():
+				}
+
+			case *blockTxnMsg:
+				m.handleBlockTxnMsg(msg)
+				select {
+				case msg.reply <- struct{}{}:
+				case <-ctx.Done():
+				}
+
 			case *mixMsg:
 				err := m.handleMixMsg(msg)
 				select {
@@ -1851,6 +1998,26 @@ func (m *SyncManager) OnBlock(block *VGLutil.Block, peer *Peer, done chan struct
 	}
 }
 
+// OnCmpctBlock adds the passed cmpctblock message and peer to the event
+// handling queue.
+func (m *SyncManager) OnCmpctBlock(cmpctBlock *wire.MsgCmpctBlock, peer *Peer, done chan struct{}) {
+	select {
+	case m.msgChan <- &cmpctBlockMsg{cmpctBlock: cmpctBlock, peer: peer, reply: done}:
+	case <-m.quit:
+		done <- struct{}{}
+	}
+}
+
+// OnBlockTxn adds the passed blocktxn message and peer to the event handling
+// queue.
+func (m *SyncManager) OnBlockTxn(blockTxn *wire.MsgBlockTxn, peer *Peer, done chan struct{}) {
+	select {
+	case m.msgChan <- &blockTxnMsg{blockTxn: blockTxn, peer: peer, reply: done}:
+	case <-m.quit:
+		done <- struct{}{}
+	}
+}
+
 // OnInv adds the passed inv message and peer to the event handling queue.
 func (m *SyncManager) OnInv(inv *wire.MsgInv, peer *Peer) {
 	select {
@@ -0,0 +1,46 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"github.com/kdsmith18542/vigil/internal/metrics"
+)
+
+var (
+	// syncHeightGauge tracks the best known height of the network as reported
+	// by peers and the best known header.
+	syncHeightGauge = metrics.NewGauge("vgld_sync_height",
+		"Best known height of the network being synced to.")
+
+	// syncHeaderProgressGauge and syncBlockProgressGauge track the estimated
+	// progress of the header and block sync processes, respectively.
+	syncHeaderProgressGauge = metrics.NewGauge(
+		"vgld_sync_header_progress_ratio",
+		"Estimated progress of the header sync process from 0 to 1.")
+	syncBlockProgressGauge = metrics.NewGauge("vgld_sync_block_progress_ratio",
+		"Progress of the block sync process towards the best header from 0 "+
+			"to 1.")
+
+	// syncCurrentGauge tracks whether or not the sync manager believes it is
+	// synced with the network.
+	syncCurrentGauge = metrics.NewGauge("vgld_sync_current",
+		"Whether or not the node believes it is synced with the network (1 "+
+			"if it is, 0 otherwise).")
+)
+
+// updateSyncMetrics updates the metrics that track the progress of the sync
+// process.
+//
+// This function MUST be called from the event handler goroutine.
+func (m *SyncManager) updateSyncMetrics() {
+	syncHeightGauge.Set(float64(m.SyncHeight()))
+	syncHeaderProgressGauge.Set(m.headerSyncProgress() / 100)
+	syncBlockProgressGauge.Set(m.cfg.Chain.VerifyProgress() / 100)
+	var current float64
+	if m.IsCurrent() {
+		current = 1
+	}
+	syncCurrentGauge.Set(current)
+}
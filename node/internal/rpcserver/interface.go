@@ -657,6 +657,10 @@ type TxMempooler interface {
 	// TSpendHashes returns the hashes of the treasury spend transactions
 	// currently in the mempool.
 	TSpendHashes() []chainhash.Hash
+
+	// MinRelayTxFee returns the minimum fee per kilobyte a transaction must
+	// currently pay to be accepted to the mempool.
+	MinRelayTxFee() VGLutil.Amount
 }
 
 // MixPooler represents a source of mixpool message data for the RPC server.
@@ -693,6 +697,43 @@ type TxIndexer interface {
 	Entry(hash *chainhash.Hash) (*indexers.TxIndexEntry, error)
 }
 
+// AddrIndexer provides an interface for retrieving the transactions and
+// unspent outputs associated with a given address.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+//
+// AddrIndexer may be nil. The RPC server must check for the presence of an
+// AddrIndexer before calling methods associated with it.
+type AddrIndexer interface {
+	// Name returns the human-readable name of the index.
+	Name() string
+
+	// Tip returns the current index tip.
+	Tip() (int64, *chainhash.Hash, error)
+
+	// WaitForSync subscribes clients for the next index sync update.
+	WaitForSync() chan bool
+
+	// EntriesForAddress returns the transaction index entries for the
+	// transactions which involve the provided address in the main chain
+	// along with the number of entries that were skipped.  The entries are
+	// returned in the order they appear in the chain unless reverse is set.
+	EntriesForAddress(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]indexers.TxIndexEntry, uint32, error)
+
+	// UnconfirmedTxnsForAddress returns the transactions in the memory pool
+	// which involve the provided address.
+	UnconfirmedTxnsForAddress(addr stdaddr.Address) []*VGLutil.Tx
+
+	// UnspentOutputs returns the unspent outputs in the main chain which pay
+	// to the provided address.
+	UnspentOutputs(addr stdaddr.Address) ([]*indexers.AddrUtxo, error)
+
+	// Balance returns the total amount of the unspent outputs in the main
+	// chain which pay to the provided address along with their count.
+	Balance(addr stdaddr.Address) (int64, int, error)
+}
+
 // NtfnManager provides an interface for processing and sending chain
 // notifications.
 //
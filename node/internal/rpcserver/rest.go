@@ -0,0 +1,559 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLjson/v4"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/database/v3"
+	"github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+const (
+	// restPathPrefix is the URL path prefix for all REST endpoints.
+	restPathPrefix = "/rest/"
+
+	// restMaxHeaders is the maximum number of headers that may be requested
+	// via a single REST headers request.
+	restMaxHeaders = 2000
+
+	// restMaxOutpoints is the maximum number of outpoints that may be
+	// queried via a single REST getutxos request.
+	restMaxOutpoints = 15
+
+	// restRateLimiterPruneInterval is the interval at which idle clients are
+	// removed from the REST rate limiter.
+	restRateLimiterPruneInterval = time.Minute
+)
+
+// restFormat describes the encoding of a REST response.
+type restFormat int
+
+const (
+	restFormatJSON restFormat = iota
+	restFormatBinary
+	restFormatHex
+)
+
+// restUtxosResult models the data returned by the REST getutxos endpoint.  The
+// entries in UTXOs correspond to the requested outpoints in order and are null
+// when the associated output does not exist or is spent.
+type restUtxosResult struct {
+	ChainHeight  int64                   `json:"chainheight"`
+	ChainTipHash string                  `json:"chaintiphash"`
+	UTXOs        []*types.GetTxOutResult `json:"utxos"`
+}
+
+// restRateBucket houses the token bucket state for a single REST client.
+type restRateBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// restRateLimiter provides a per-client token bucket rate limiter for the
+// REST interface.  Each client, as identified by its IP address, may make up to
+// burst requests at once and the available requests are replenished at rate
+// requests per second.
+type restRateLimiter struct {
+	rate  float64
+	burst float64
+
+	mtx       sync.Mutex
+	clients   map[string]*restRateBucket
+	lastPrune time.Time
+}
+
+// newRESTRateLimiter returns a new REST rate limiter that allows the provided
+// number of requests per second per client with bursts of up to twice that.
+func newRESTRateLimiter(requestsPerSec int) *restRateLimiter {
+	return &restRateLimiter{
+		rate:    float64(requestsPerSec),
+		burst:   float64(requestsPerSec * 2),
+		clients: make(map[string]*restRateBucket),
+	}
+}
+
+// allow returns whether or not the client with the provided host is permitted
+// to make a request at the provided time and consumes a request from its
+// allowance when it is.
+//
+// This function is safe for concurrent access.
+func (l *restRateLimiter) allow(host string, now time.Time) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	// Periodically remove clients that have been idle long enough for their
+	// allowance to fully replenish since they are indistinguishable from new
+	// clients at that point.
+	if now.Sub(l.lastPrune) >= restRateLimiterPruneInterval {
+		fullAfter := time.Duration(l.burst / l.rate * float64(time.Second))
+		for clientHost, bucket := range l.clients {
+			if now.Sub(bucket.lastSeen) >= fullAfter {
+				delete(l.clients, clientHost)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	bucket, ok := l.clients[host]
+	if !ok {
+		bucket = &restRateBucket{tokens: l.burst, lastSeen: now}
+		l.clients[host] = bucket
+	}
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	if elapsed > 0 {
+		bucket.tokens += elapsed * l.rate
+		if bucket.tokens > l.burst {
+			bucket.tokens = l.burst
+		}
+	}
+	bucket.lastSeen = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// parseRESTFormat splits the provided final REST path component into its
+// resource and the requested response format as denoted by its extension.
+// Components without an extension default to JSON.
+func parseRESTFormat(component string) (string, restFormat, error) {
+	dot := strings.LastIndexByte(component, '.')
+	if dot == -1 {
+		return component, restFormatJSON, nil
+	}
+	resource, ext := component[:dot], component[dot+1:]
+	switch ext {
+	case "json":
+		return resource, restFormatJSON, nil
+	case "bin":
+		return resource, restFormatBinary, nil
+	case "hex":
+		return resource, restFormatHex, nil
+	}
+	return "", 0, fmt.Errorf("output format %q not supported (available: "+
+		"json, bin, hex)", ext)
+}
+
+// restError writes the provided plain text error message with the provided
+// HTTP status code.
+func restError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintln(w, message)
+}
+
+// restRPCError writes the provided error returned by an RPC handler with an
+// HTTP status code that corresponds to its RPC error code.
+func restRPCError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var rpcErr *VGLjson.RPCError
+	if !errors.As(err, &rpcErr) {
+		restError(w, status, err.Error())
+		return
+	}
+	switch rpcErr.Code {
+	case VGLjson.ErrRPCBlockNotFound:
+		status = http.StatusNotFound
+	case VGLjson.ErrRPCDecodeHexString, VGLjson.ErrRPCInvalidParameter,
+		VGLjson.ErrRPCInvalidParams.Code, VGLjson.ErrRPCType:
+		status = http.StatusBadRequest
+	}
+	restError(w, status, rpcErr.Message)
+}
+
+// restWriteJSON writes the provided result as JSON.
+func restWriteJSON(w http.ResponseWriter, result interface{}) {
+	marshalled, err := json.Marshal(result)
+	if err != nil {
+		restRPCError(w, rpcInternalErr(err, "Failed to marshal REST reply"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(marshalled)
+	w.Write([]byte{'\n'})
+}
+
+// restWriteBytes writes the provided serialized data in the provided format
+// which must be either binary or hex.
+func restWriteBytes(w http.ResponseWriter, format restFormat, data []byte) {
+	if format == restFormatHex {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(hex.EncodeToString(data)))
+		w.Write([]byte{'\n'})
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(data)
+}
+
+// restHandler handles all requests made to the REST interface.  The REST
+// interface is unauthenticated and only provides access to public data, so
+// requests are instead subject to per-client rate limiting.
+func (s *Server) restHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		restError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Limit the number of connections to max allowed.
+	if s.limitConnections(w, r.RemoteAddr) {
+		return
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !s.restLimiter.allow(host, time.Now()) {
+		log.Debugf("REST rate limit exceeded by client %s", r.RemoteAddr)
+		restError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+		return
+	}
+
+	// Keep track of the number of connected clients.
+	s.incrementClients()
+	defer s.decrementClients()
+
+	ctx := r.Context()
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, restPathPrefix), "/")
+	switch {
+	case parts[0] == "block" && len(parts) == 2:
+		s.handleRESTBlock(ctx, w, parts[1])
+	case parts[0] == "headers" && len(parts) == 3:
+		s.handleRESTHeaders(ctx, w, parts[1], parts[2])
+	case parts[0] == "tx" && len(parts) == 2:
+		s.handleRESTTx(ctx, w, parts[1])
+	case len(parts) == 1 && (parts[0] == "chaininfo" ||
+		parts[0] == "chaininfo.json"):
+
+		s.handleRESTJSON(ctx, w, handleGetBlockchainInfo, nil)
+	case parts[0] == "mempool" && len(parts) == 2 &&
+		(parts[1] == "info" || parts[1] == "info.json"):
+
+		s.handleRESTJSON(ctx, w, handleGetMempoolInfo, nil)
+	case parts[0] == "getutxos" && len(parts) > 1:
+		s.handleRESTGetUtxos(ctx, w, parts[1:])
+	case parts[0] == "cfilter" && len(parts) == 2:
+		s.handleRESTCFilter(ctx, w, parts[1])
+	default:
+		restError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// handleRESTJSON invokes the provided RPC handler with the provided command
+// and writes the result as JSON.
+func (s *Server) handleRESTJSON(ctx context.Context, w http.ResponseWriter, handler commandHandler, cmd interface{}) {
+	result, err := handler(ctx, s, cmd)
+	if err != nil {
+		restRPCError(w, err)
+		return
+	}
+	restWriteJSON(w, result)
+}
+
+// handleRESTBlock handles requests for /rest/block/<hash>.<json|bin|hex>.
+func (s *Server) handleRESTBlock(ctx context.Context, w http.ResponseWriter, component string) {
+	hashStr, format, err := parseRESTFormat(component)
+	if err != nil {
+		restError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	hash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		restRPCError(w, rpcDecodeHexError(hashStr))
+		return
+	}
+
+	blk, err := s.cfg.Chain.BlockByHash(hash)
+	if err != nil {
+		if errors.Is(err, database.ErrBlockPruned) {
+			restError(w, http.StatusNotFound, rpcBlockPrunedError(*hash).Message)
+			return
+		}
+		restError(w, http.StatusNotFound, fmt.Sprintf("Block not found: %v",
+			hash))
+		return
+	}
+
+	if format == restFormatJSON {
+		verbose, verboseTx := true, true
+		s.handleRESTJSON(ctx, w, handleGetBlock, &types.GetBlockCmd{
+			Hash:      hashStr,
+			Verbose:   &verbose,
+			VerboseTx: &verboseTx,
+		})
+		return
+	}
+
+	blkBytes, err := blk.Bytes()
+	if err != nil {
+		restRPCError(w, rpcInternalErr(err, "Could not serialize block"))
+		return
+	}
+	restWriteBytes(w, format, blkBytes)
+}
+
+// handleRESTHeaders handles requests for
+// /rest/headers/<count>/<hash>.<json|bin|hex>.  It returns up to count headers
+// starting with the provided block and following the main chain.
+func (s *Server) handleRESTHeaders(ctx context.Context, w http.ResponseWriter, countStr, component string) {
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count < 1 || count > restMaxHeaders {
+		restError(w, http.StatusBadRequest, fmt.Sprintf("Header count must "+
+			"be between 1 and %d (not %q)", restMaxHeaders, countStr))
+		return
+	}
+	hashStr, format, err := parseRESTFormat(component)
+	if err != nil {
+		restError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	hash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		restRPCError(w, rpcDecodeHexError(hashStr))
+		return
+	}
+
+	chain := s.cfg.Chain
+	header, err := chain.HeaderByHash(hash)
+	if err != nil {
+		restError(w, http.StatusNotFound, fmt.Sprintf("Block not found: %v",
+			hash))
+		return
+	}
+
+	// Collect the requested headers by following the main chain from the
+	// provided block.  Only the provided block is returned when it is not
+	// part of the main chain.
+	best := chain.BestSnapshot()
+	hashes := []chainhash.Hash{*hash}
+	headers := []wire.BlockHeader{header}
+	if chain.MainChainHasBlock(hash) {
+		for height := int64(header.Height) + 1; height <= best.Height &&
+			len(hashes) < count; height++ {
+
+			nextHash, err := chain.BlockHashByHeight(height)
+			if err != nil {
+				break
+			}
+			nextHeader, err := chain.HeaderByHash(nextHash)
+			if err != nil {
+				break
+			}
+			hashes = append(hashes, *nextHash)
+			headers = append(headers, nextHeader)
+		}
+	}
+
+	if format == restFormatJSON {
+		results := make([]interface{}, 0, len(hashes))
+		verbose := true
+		for i := range hashes {
+			result, err := handleGetBlockHeader(ctx, s, &types.GetBlockHeaderCmd{
+				Hash:    hashes[i].String(),
+				Verbose: &verbose,
+			})
+			if err != nil {
+				restRPCError(w, err)
+				return
+			}
+			results = append(results, result)
+		}
+		restWriteJSON(w, results)
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(headers) * wire.MaxBlockHeaderPayload)
+	for i := range headers {
+		if err := headers[i].Serialize(&buf); err != nil {
+			restRPCError(w, rpcInternalErr(err, "Failed to serialize "+
+				"block header"))
+			return
+		}
+	}
+	restWriteBytes(w, format, buf.Bytes())
+}
+
+// handleRESTTx handles requests for /rest/tx/<txid>.<json|bin|hex>.  Only
+// transactions in the mempool are available unless the transaction index is
+// enabled.
+func (s *Server) handleRESTTx(ctx context.Context, w http.ResponseWriter, component string) {
+	txid, format, err := parseRESTFormat(component)
+	if err != nil {
+		restError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	txHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		restRPCError(w, rpcDecodeHexError(txid))
+		return
+	}
+	if s.cfg.TxIndexer == nil {
+		if _, err := s.cfg.TxMempooler.FetchTransaction(txHash); err != nil {
+			restError(w, http.StatusNotFound, "Transaction not found in "+
+				"mempool and the transaction index is not enabled "+
+				"(specify --txindex)")
+			return
+		}
+	}
+
+	verbose := 1
+	if format != restFormatJSON {
+		verbose = 0
+	}
+	result, err := handleGetRawTransaction(ctx, s, &types.GetRawTransactionCmd{
+		Txid:    txid,
+		Verbose: &verbose,
+	})
+	if err != nil {
+		restRPCError(w, err)
+		return
+	}
+	if format == restFormatJSON {
+		restWriteJSON(w, result)
+		return
+	}
+
+	txBytes, err := hex.DecodeString(result.(string))
+	if err != nil {
+		restRPCError(w, rpcInternalErr(err, "Failed to decode transaction"))
+		return
+	}
+	restWriteBytes(w, format, txBytes)
+}
+
+// handleRESTGetUtxos handles requests for
+// /rest/getutxos[/checkmempool]/<txid>-<vout>[-<tree>]/... with up to
+// restMaxOutpoints outpoints.  The tree defaults to the regular tree when it
+// is not specified.
+func (s *Server) handleRESTGetUtxos(ctx context.Context, w http.ResponseWriter, parts []string) {
+	// Only JSON is supported and the format extension, when present, is on
+	// the final component.
+	last, format, err := parseRESTFormat(parts[len(parts)-1])
+	if err != nil {
+		restError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if format != restFormatJSON {
+		restError(w, http.StatusBadRequest, "Output format not supported "+
+			"(available: json)")
+		return
+	}
+	parts[len(parts)-1] = last
+
+	checkMempool := parts[0] == "checkmempool"
+	if checkMempool {
+		parts = parts[1:]
+	}
+	if len(parts) == 0 || len(parts) > restMaxOutpoints {
+		restError(w, http.StatusBadRequest, fmt.Sprintf("Number of "+
+			"outpoints must be between 1 and %d", restMaxOutpoints))
+		return
+	}
+
+	cmds := make([]types.GetTxOutCmd, 0, len(parts))
+	for _, outpoint := range parts {
+		fields := strings.Split(outpoint, "-")
+		if len(fields) < 2 || len(fields) > 3 {
+			restError(w, http.StatusBadRequest, fmt.Sprintf("Invalid "+
+				"outpoint %q", outpoint))
+			return
+		}
+		vout, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			restError(w, http.StatusBadRequest, fmt.Sprintf("Invalid "+
+				"output index in outpoint %q", outpoint))
+			return
+		}
+		tree := wire.TxTreeRegular
+		if len(fields) == 3 {
+			parsedTree, err := strconv.ParseInt(fields[2], 10, 8)
+			if err != nil {
+				restError(w, http.StatusBadRequest, fmt.Sprintf("Invalid "+
+					"tree in outpoint %q", outpoint))
+				return
+			}
+			tree = int8(parsedTree)
+		}
+		cmds = append(cmds, types.GetTxOutCmd{
+			Txid:           fields[0],
+			Vout:           uint32(vout),
+			Tree:           tree,
+			IncludeMempool: &checkMempool,
+		})
+	}
+
+	best := s.cfg.Chain.BestSnapshot()
+	reply := restUtxosResult{
+		ChainHeight:  best.Height,
+		ChainTipHash: best.Hash.String(),
+		UTXOs:        make([]*types.GetTxOutResult, 0, len(cmds)),
+	}
+	for i := range cmds {
+		result, err := handleGetTxOut(ctx, s, &cmds[i])
+		if err != nil {
+			var rpcErr *VGLjson.RPCError
+			if errors.As(err, &rpcErr) &&
+				rpcErr.Code == VGLjson.ErrRPCInvalidTxVout {
+
+				reply.UTXOs = append(reply.UTXOs, nil)
+				continue
+			}
+			restRPCError(w, err)
+			return
+		}
+		txOut, _ := result.(*types.GetTxOutResult)
+		reply.UTXOs = append(reply.UTXOs, txOut)
+	}
+	restWriteJSON(w, &reply)
+}
+
+// handleRESTCFilter handles requests for /rest/cfilter/<hash>.<json|bin|hex>
+// which returns the version 2 committed filter for the provided block.  The
+// binary and hex formats only include the filter data.
+func (s *Server) handleRESTCFilter(ctx context.Context, w http.ResponseWriter, component string) {
+	hashStr, format, err := parseRESTFormat(component)
+	if err != nil {
+		restError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	result, err := handleGetCFilterV2(ctx, s, &types.GetCFilterV2Cmd{
+		BlockHash: hashStr,
+	})
+	if err != nil {
+		restRPCError(w, err)
+		return
+	}
+	if format == restFormatJSON {
+		restWriteJSON(w, result)
+		return
+	}
+
+	filter := result.(*types.GetCFilterV2Result)
+	data, err := hex.DecodeString(filter.Data)
+	if err != nil {
+		restRPCError(w, rpcInternalErr(err, "Failed to decode filter"))
+		return
+	}
+	restWriteBytes(w, format, data)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdsmith18542/vigil/database/v3"
+)
+
+// TestRESTRateLimiter ensures the REST rate limiter allows bursts, replenishes
+// requests over time, tracks clients independently, and prunes idle clients.
+func TestRESTRateLimiter(t *testing.T) {
+	l := newRESTRateLimiter(2)
+	now := time.Unix(1700000000, 0)
+
+	// The burst is twice the rate.
+	for i := 0; i < 4; i++ {
+		if !l.allow("10.0.0.1", now) {
+			t.Fatalf("request %d within burst was not allowed", i)
+		}
+	}
+	if l.allow("10.0.0.1", now) {
+		t.Fatal("request exceeding burst was allowed")
+	}
+
+	// Other clients are unaffected.
+	if !l.allow("10.0.0.2", now) {
+		t.Fatal("request from other client was not allowed")
+	}
+
+	// Requests are replenished at the configured rate.
+	now = now.Add(500 * time.Millisecond)
+	if !l.allow("10.0.0.1", now) {
+		t.Fatal("replenished request was not allowed")
+	}
+	if l.allow("10.0.0.1", now) {
+		t.Fatal("request exceeding replenished allowance was allowed")
+	}
+
+	// Idle clients are pruned once their allowance is fully replenished.
+	now = now.Add(restRateLimiterPruneInterval)
+	if !l.allow("10.0.0.3", now) {
+		t.Fatal("request from new client was not allowed")
+	}
+	if len(l.clients) != 1 {
+		t.Fatalf("idle clients were not pruned -- have %d clients",
+			len(l.clients))
+	}
+}
+
+// TestRESTHandler ensures the REST interface routes requests, encodes responses
+// in the requested format, and reports errors with the expected status codes.
+func TestRESTHandler(t *testing.T) {
+	t.Parallel()
+
+	blkBytes, err := block432100.Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize block: %v", err)
+	}
+	var hdrBuf bytes.Buffer
+	if err := block432100.Header.Serialize(&hdrBuf); err != nil {
+		t.Fatalf("unable to serialize header: %v", err)
+	}
+	blkHash := block432100.BlockHash().String()
+
+	prunedChain := defaultMockRPCChain()
+	prunedChain.blockByHash = nil
+	prunedChain.blockByHashErr = database.ErrBlockPruned
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		chain    *testRPCChain
+		wantCode int
+		wantBody string
+	}{{
+		name:     "block binary",
+		path:     "/rest/block/" + blkHash + ".bin",
+		wantCode: http.StatusOK,
+		wantBody: string(blkBytes),
+	}, {
+		name:     "block hex",
+		path:     "/rest/block/" + blkHash + ".hex",
+		wantCode: http.StatusOK,
+		wantBody: hex.EncodeToString(blkBytes) + "\n",
+	}, {
+		name:     "block pruned",
+		path:     "/rest/block/" + blkHash + ".bin",
+		chain:    prunedChain,
+		wantCode: http.StatusNotFound,
+		wantBody: "Block " + blkHash + " is not available (pruned data)\n",
+	}, {
+		name:     "block invalid hash",
+		path:     "/rest/block/zz.bin",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "block unsupported format",
+		path:     "/rest/block/" + blkHash + ".xml",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "headers hex at tip",
+		path:     "/rest/headers/5/" + blkHash + ".hex",
+		wantCode: http.StatusOK,
+		wantBody: hex.EncodeToString(hdrBuf.Bytes()) + "\n",
+	}, {
+		name:     "headers invalid count",
+		path:     "/rest/headers/0/" + blkHash + ".bin",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "headers count too large",
+		path:     "/rest/headers/2001/" + blkHash + ".bin",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "getutxos too many outpoints",
+		path:     "/rest/getutxos" + strings.Repeat("/"+blkHash+"-0", 16),
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "getutxos invalid outpoint",
+		path:     "/rest/getutxos/" + blkHash + ".json",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "getutxos binary not supported",
+		path:     "/rest/getutxos/" + blkHash + "-0.bin",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "unknown endpoint",
+		path:     "/rest/unknown",
+		wantCode: http.StatusNotFound,
+	}, {
+		name:     "method not allowed",
+		method:   http.MethodPost,
+		path:     "/rest/chaininfo",
+		wantCode: http.StatusMethodNotAllowed,
+	}}
+
+	for _, test := range tests {
+		cfg := defaultMockConfig(defaultChainParams)
+		cfg.REST = true
+		cfg.RESTRateLimit = 10
+		cfg.RPCMaxClients = 10
+		if test.chain != nil {
+			cfg.Chain = test.chain
+		}
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatalf("%q: unable to create server: %v", test.name, err)
+		}
+
+		method := test.method
+		if method == "" {
+			method = http.MethodGet
+		}
+		rec := httptest.NewRecorder()
+		s.restHandler(rec, httptest.NewRequest(method, test.path, nil))
+		if rec.Code != test.wantCode {
+			t.Errorf("%q: unexpected status code -- got %d, want %d (%s)",
+				test.name, rec.Code, test.wantCode, rec.Body.String())
+			continue
+		}
+		if test.wantBody != "" && rec.Body.String() != test.wantBody {
+			t.Errorf("%q: mismatched body -- got %q, want %q", test.name,
+				rec.Body.String(), test.wantBody)
+		}
+	}
+}
+
+// TestRESTHandlerRateLimit ensures clients that exceed the REST rate limit are
+// rejected.
+func TestRESTHandlerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.REST = true
+	cfg.RESTRateLimit = 1
+	cfg.RPCMaxClients = 10
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create server: %v", err)
+	}
+
+	// The burst allows two requests.
+	wantCodes := []int{http.StatusNotFound, http.StatusNotFound,
+		http.StatusTooManyRequests}
+	for i, wantCode := range wantCodes {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/rest/unknown", nil)
+		s.restHandler(rec, req)
+		if rec.Code != wantCode {
+			t.Fatalf("request %d: unexpected status code -- got %d, want %d",
+				i, rec.Code, wantCode)
+		}
+	}
+}
@@ -151,6 +151,15 @@ var (
 	// kawpowPad is the extra data needed for KawPoW work data, which includes the mix hash and nonce
 	kawpowPad = make([]byte, 32+8) // 32 bytes for mix hash + 8 bytes for nonce
 
+	// gbtCapabilities describes the optional BIP 0023 capabilities supported by
+	// the getblocktemplate RPC.
+	gbtCapabilities = []string{"longpoll"}
+
+	// gbtMutableFields are the manipulations the server allows to be made to
+	// block templates returned by the getblocktemplate RPC.
+	gbtMutableFields = []string{"time", "transactions/add", "prevblock",
+		"coinbase/append"}
+
 	// JSON 2.0 batched request prefix.
 	batchedRequestPrefix = []byte("[")
 
@@ -200,6 +209,9 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"existsmempooltxs":      handleExistsMempoolTxs,
 	"generate":              handleGenerate,
 	"getaddednodeinfo":      handleGetAddedNodeInfo,
+	"getaddressbalance":     handleGetAddressBalance,
+	"getaddresstxids":       handleGetAddressTxIDs,
+	"getaddressutxos":       handleGetAddressUtxos,
 	"getbestblock":          handleGetBestBlock,
 	"getbestblockhash":      handleGetBestBlockHash,
 	"getblock":              handleGetBlock,
@@ -208,6 +220,7 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"getblockhash":          handleGetBlockHash,
 	"getblockheader":        handleGetBlockHeader,
 	"getblocksubsidy":       handleGetBlockSubsidy,
+	"getblocktemplate":      handleGetBlockTemplate,
 	"getcfilterv2":          handleGetCFilterV2,
 	"getchaintips":          handleGetChainTips,
 	"getcoinsupply":         handleGetCoinSupply,
@@ -245,6 +258,7 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"ping":                  handlePing,
 	"reconsiderblock":       handleReconsiderBlock,
 	"regentemplate":         handleRegenTemplate,
+	"searchrawtransactions": handleSearchRawTransactions,
 	"sendrawmixmessage":     handleSendRawMixMessage,
 	"sendrawtransaction":    handleSendRawTransaction,
 	"setgenerate":           handleSetGenerate,
@@ -363,59 +377,63 @@ var rpcLimited = map[string]struct{}{
 	"help": {},
 
 	// HTTP/S-only commands
-	"createrawsstx":        {},
-	"createrawssrtx":       {},
-	"createrawtransaction": {},
-	"decoderawtransaction": {},
-	"decodescript":         {},
-	"estimatefee":          {},
-	"estimatesmartfee":     {},
-	"estimatestakediff":    {},
-	"existsaddress":        {},
-	"existsaddresses":      {},
-	"existsliveticket":     {},
-	"existslivetickets":    {},
-	"existsmempooltxs":     {},
-	"getbestblock":         {},
-	"getbestblockhash":     {},
-	"getblock":             {},
-	"getblockchaininfo":    {},
-	"getblockcount":        {},
-	"getblockhash":         {},
-	"getblockheader":       {},
-	"getblocksubsidy":      {},
-	"getcfilterv2":         {},
-	"getchaintips":         {},
-	"getcoinsupply":        {},
-	"getcurrentnet":        {},
-	"getdifficulty":        {},
-	"getheaders":           {},
-	"getinfo":              {},
-	"getmixmessage":        {},
-	"getmixpairrequests":   {},
-	"getnettotals":         {},
-	"getnetworkhashps":     {},
-	"getnetworkinfo":       {},
-	"getrawmempool":        {},
-	"getstakedifficulty":   {},
-	"getstakeversioninfo":  {},
-	"getstakeversions":     {},
-	"getrawtransaction":    {},
-	"gettreasurybalance":   {},
-	"gettxout":             {},
-	"getvoteinfo":          {},
-	"livetickets":          {},
-	"regentemplate":        {},
-	"sendrawmixmessage":    {},
-	"sendrawtransaction":   {},
-	"submitblock":          {},
-	"ticketfeeinfo":        {},
-	"ticketsforaddress":    {},
-	"ticketvwap":           {},
-	"txfeeinfo":            {},
-	"validateaddress":      {},
-	"verifymessage":        {},
-	"version":              {},
+	"createrawsstx":         {},
+	"createrawssrtx":        {},
+	"createrawtransaction":  {},
+	"decoderawtransaction":  {},
+	"decodescript":          {},
+	"estimatefee":           {},
+	"estimatesmartfee":      {},
+	"estimatestakediff":     {},
+	"existsaddress":         {},
+	"existsaddresses":       {},
+	"existsliveticket":      {},
+	"existslivetickets":     {},
+	"existsmempooltxs":      {},
+	"getaddressbalance":     {},
+	"getaddresstxids":       {},
+	"getaddressutxos":       {},
+	"getbestblock":          {},
+	"getbestblockhash":      {},
+	"getblock":              {},
+	"getblockchaininfo":     {},
+	"getblockcount":         {},
+	"getblockhash":          {},
+	"getblockheader":        {},
+	"getblocksubsidy":       {},
+	"getcfilterv2":          {},
+	"getchaintips":          {},
+	"getcoinsupply":         {},
+	"getcurrentnet":         {},
+	"getdifficulty":         {},
+	"getheaders":            {},
+	"getinfo":               {},
+	"getmixmessage":         {},
+	"getmixpairrequests":    {},
+	"getnettotals":          {},
+	"getnetworkhashps":      {},
+	"getnetworkinfo":        {},
+	"getrawmempool":         {},
+	"getstakedifficulty":    {},
+	"getstakeversioninfo":   {},
+	"getstakeversions":      {},
+	"getrawtransaction":     {},
+	"gettreasurybalance":    {},
+	"gettxout":              {},
+	"getvoteinfo":           {},
+	"livetickets":           {},
+	"regentemplate":         {},
+	"searchrawtransactions": {},
+	"sendrawmixmessage":     {},
+	"sendrawtransaction":    {},
+	"submitblock":           {},
+	"ticketfeeinfo":         {},
+	"ticketsforaddress":     {},
+	"ticketvwap":            {},
+	"txfeeinfo":             {},
+	"validateaddress":       {},
+	"verifymessage":         {},
+	"version":               {},
 }
 
 // rpcInternalErr is a convenience function to convert an internal error to an
@@ -497,6 +515,14 @@ func rpcBlockNotFoundError(blockHash chainhash.Hash) *VGLjson.RPCError {
 			blockHash))
 }
 
+// rpcBlockPrunedError is a convenience function for returning a nicely
+// formatted RPC error which indicates that the data for the provided block is
+// no longer available due to pruning.
+func rpcBlockPrunedError(blockHash chainhash.Hash) *VGLjson.RPCError {
+	return VGLjson.NewRPCError(VGLjson.ErrRPCMisc,
+		fmt.Sprintf("Block %v is not available (pruned data)", blockHash))
+}
+
 // rpcMixMessageNotFoundError is a convenience function for returning a nicely
 // formatted RPC error which indicates that the mix message was not found in
 // the mixpool.
@@ -1896,6 +1922,169 @@ func handleGetAddedNodeInfo(_ context.Context, s *Server, cmd interface{}) (inte
 	return results, nil
 }
 
+// syncedAddrIndex returns the address index after ensuring it is enabled and
+// synced with the current best chain tip.
+func (s *Server) syncedAddrIndex() (AddrIndexer, error) {
+	addrIndex := s.cfg.AddrIndexer
+	if addrIndex == nil {
+		err := errors.New("the address index must be enabled to query " +
+			"addresses (specify --addrindex)")
+		return nil, rpcInternalErr(err, "Configuration")
+	}
+
+	// Ensure the address index is synced.
+	tHeight, tHash, err := addrIndex.Tip()
+	if err != nil {
+		return nil, rpcInternalErr(err, "Address index tip")
+	}
+
+	chain := s.cfg.Chain
+
+	// Return an out-of-sync error if index is lagging a
+	// maximum reorg depth (6) blocks or more from the chain tip.
+	if chain.BestSnapshot().Height > (tHeight + 5) {
+		err := fmt.Errorf("%s: index not synced", addrIndex.Name())
+		return nil, rpcInternalErr(err, "Sync")
+	}
+
+sync:
+	for !chain.BestSnapshot().Hash.IsEqual(tHash) {
+		select {
+		case <-time.After(syncWait):
+			err := fmt.Errorf("%s: index not synced", addrIndex.Name())
+			return nil, rpcInternalErr(err, "Sync")
+		case <-addrIndex.WaitForSync():
+			break sync
+		}
+	}
+
+	return addrIndex, nil
+}
+
+// handleGetAddressBalance implements the getaddressbalance command.
+func handleGetAddressBalance(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetAddressBalanceCmd)
+
+	addrIndex, err := s.syncedAddrIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the provided address.  This also ensures the network encoded with
+	// the address matches the network the server is currently on.
+	addr, err := stdaddr.DecodeAddress(c.Address, s.cfg.ChainParams)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
+	}
+
+	balance, numUtxos, err := addrIndex.Balance(addr)
+	if err != nil {
+		return nil, rpcInternalErr(err, "Failed to load address balance")
+	}
+
+	return &types.GetAddressBalanceResult{
+		Balance:   VGLutil.Amount(balance).ToCoin(),
+		UtxoCount: int64(numUtxos),
+	}, nil
+}
+
+// handleGetAddressTxIDs implements the getaddresstxids command.
+func handleGetAddressTxIDs(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetAddressTxIDsCmd)
+
+	addrIndex, err := s.syncedAddrIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the provided address.  This also ensures the network encoded with
+	// the address matches the network the server is currently on.
+	addr, err := stdaddr.DecodeAddress(c.Address, s.cfg.ChainParams)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
+	}
+
+	// Override the default number of requested entries if needed.  Also,
+	// just return now if the number of requested entries is zero to avoid
+	// extra work.
+	numRequested := 100
+	if c.Count != nil {
+		numRequested = *c.Count
+		if numRequested < 0 {
+			numRequested = 1
+		}
+	}
+	if numRequested == 0 {
+		return []string{}, nil
+	}
+
+	// Override the default number of entries to skip if needed.
+	var numToSkip int
+	if c.Skip != nil && *c.Skip > 0 {
+		numToSkip = *c.Skip
+	}
+
+	var reverse bool
+	if c.Reverse != nil {
+		reverse = *c.Reverse
+	}
+
+	addressTxns, err := s.fetchAddrTxns(addrIndex, addr, uint32(numToSkip),
+		uint32(numRequested), reverse)
+	if err != nil {
+		return nil, err
+	}
+
+	txIDs := make([]string, 0, len(addressTxns))
+	for i := range addressTxns {
+		mtx, err := addressTxns[i].msgTx()
+		if err != nil {
+			return nil, err
+		}
+		txIDs = append(txIDs, mtx.TxHash().String())
+	}
+	return txIDs, nil
+}
+
+// handleGetAddressUtxos implements the getaddressutxos command.
+func handleGetAddressUtxos(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetAddressUtxosCmd)
+
+	addrIndex, err := s.syncedAddrIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the provided address.  This also ensures the network encoded with
+	// the address matches the network the server is currently on.
+	addr, err := stdaddr.DecodeAddress(c.Address, s.cfg.ChainParams)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
+	}
+
+	utxos, err := addrIndex.UnspentOutputs(addr)
+	if err != nil {
+		return nil, rpcInternalErr(err, "Failed to load unspent outputs")
+	}
+
+	results := make([]types.GetAddressUtxosResult, 0, len(utxos))
+	for _, utxo := range utxos {
+		results = append(results, types.GetAddressUtxosResult{
+			TxID:          utxo.OutPoint.Hash.String(),
+			Vout:          utxo.OutPoint.Index,
+			Tree:          utxo.OutPoint.Tree,
+			Amount:        VGLutil.Amount(utxo.Amount).ToCoin(),
+			Height:        utxo.Height,
+			ScriptVersion: utxo.ScriptVersion,
+			ScriptPubKey:  hex.EncodeToString(utxo.PkScript),
+		})
+	}
+	return results, nil
+}
+
 // handleGetBestBlock implements the getbestblock command.
 func handleGetBestBlock(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
 	// All other "get block" commands give either the height, the hash, or
@@ -1947,6 +2136,9 @@ func handleGetBlock(_ context.Context, s *Server, cmd interface{}) (interface{},
 	chain := s.cfg.Chain
 	blk, err := chain.BlockByHash(hash)
 	if err != nil {
+		if errors.Is(err, database.ErrBlockPruned) {
+			return nil, rpcBlockPrunedError(*hash)
+		}
 		return nil, &VGLjson.RPCError{
 			Code:    VGLjson.ErrRPCBlockNotFound,
 			Message: fmt.Sprintf("Block not found: %v", hash),
@@ -2342,15 +2534,27 @@ func handleGetBlockSubsidy(_ context.Context, s *Server, cmd interface{}) (inter
 		}
 		prevBlkHash = header.PrevBlock
 	}
-	isTreasuryEnabled, err := s.isTreasuryAgendaActive(&prevBlkHash)
+	rep, err := s.calcBlockSubsidy(&prevBlkHash, height, voters)
 	if err != nil {
 		return nil, err
 	}
-	isSubsidyEnabled, err := s.isSubsidySplitAgendaActive(&prevBlkHash)
+	return *rep, nil
+}
+
+// calcBlockSubsidy returns the treasury, proof-of-stake, and proof-of-work
+// subsidy for a block at the provided height with the given number of voters
+// according to the agendas that are active for blocks building on the provided
+// previous block.
+func (s *Server) calcBlockSubsidy(prevBlkHash *chainhash.Hash, height int64, voters uint16) (*types.GetBlockSubsidyResult, error) {
+	isTreasuryEnabled, err := s.isTreasuryAgendaActive(prevBlkHash)
 	if err != nil {
 		return nil, err
 	}
-	isSubsidyR2Enabled, err := s.isSubsidySplitR2AgendaActive(&prevBlkHash)
+	isSubsidyEnabled, err := s.isSubsidySplitAgendaActive(prevBlkHash)
+	if err != nil {
+		return nil, err
+	}
+	isSubsidyR2Enabled, err := s.isSubsidySplitR2AgendaActive(prevBlkHash)
 	if err != nil {
 		return nil, err
 	}
@@ -2372,14 +2576,275 @@ func handleGetBlockSubsidy(_ context.Context, s *Server, cmd interface{}) (inter
 	pow := subsidyCache.CalcWorkSubsidyV3(height, voters, subsidySplitVariant)
 	total := dev + pos + pow
 
-	rep := types.GetBlockSubsidyResult{
+	return &types.GetBlockSubsidyResult{
 		Developer: dev,
 		PoS:       pos,
 		PoW:       pow,
 		Total:     total,
+	}, nil
+}
+
+// txTypeString returns the string representation of the passed transaction
+// type as used in the getblocktemplate results.
+func txTypeString(txType stake.TxType) string {
+	switch txType {
+	case stake.TxTypeSStx:
+		return "ticket"
+	case stake.TxTypeSSGen:
+		return "vote"
+	case stake.TxTypeSSRtx:
+		return "revocation"
+	case stake.TxTypeTAdd:
+		return "tadd"
+	case stake.TxTypeTSpend:
+		return "tspend"
+	case stake.TxTypeTreasuryBase:
+		return "treasurybase"
+	}
+	return "regular"
+}
+
+// templateLongPollID returns the long poll id for the passed block template.
+// It changes whenever the template builds on a different block or the
+// transactions it contains change.
+func templateLongPollID(template *mining.BlockTemplate) string {
+	header := &template.Block.Header
+	return fmt.Sprintf("%s-%x", header.PrevBlock, getWorkTemplateKey(header))
+}
+
+// templateTxns converts the passed transactions from a block template to the
+// form used in the getblocktemplate results.  The fees and signature operation
+// counts must be in the same order as the transactions and the indices that
+// are used to identify dependencies start at the provided value.
+func templateTxns(txns []*wire.MsgTx, fees, sigOpCounts []int64, firstIndex int64) ([]types.GetBlockTemplateResultTx, error) {
+	txIndex := make(map[chainhash.Hash]int64, len(txns))
+	result := make([]types.GetBlockTemplateResultTx, 0, len(txns))
+	for i, tx := range txns {
+		txHash := tx.TxHash()
+		txIndex[txHash] = firstIndex + int64(i)
+
+		// Create a list of the indices of transactions in the same tree that
+		// this transaction depends on.
+		depends := make([]int64, 0)
+		seen := make(map[int64]struct{})
+		for _, txIn := range tx.TxIn {
+			idx, ok := txIndex[txIn.PreviousOutPoint.Hash]
+			if !ok {
+				continue
+			}
+			if _, ok := seen[idx]; !ok {
+				seen[idx] = struct{}{}
+				depends = append(depends, idx)
+			}
+		}
+
+		txBuf, err := tx.Bytes()
+		if err != nil {
+			context := "Failed to serialize transaction"
+			return nil, rpcInternalErr(err, context)
+		}
+
+		var fee, sigOps int64
+		if i < len(fees) {
+			fee = fees[i]
+		}
+		if i < len(sigOpCounts) {
+			sigOps = sigOpCounts[i]
+		}
+		result = append(result, types.GetBlockTemplateResultTx{
+			Data:    hex.EncodeToString(txBuf),
+			Hash:    txHash.String(),
+			Depends: depends,
+			Fee:     fee,
+			SigOps:  sigOps,
+			TxType:  txTypeString(stake.DetermineTxType(tx)),
+		})
 	}
+	return result, nil
+}
 
-	return rep, nil
+// blockTemplateResult returns the getblocktemplate result for the passed block
+// template.
+func (s *Server) blockTemplateResult(template *mining.BlockTemplate) (*types.GetBlockTemplateResult, error) {
+	// Update the time of the block template to the current time while
+	// accounting for the median time of the past several blocks per the chain
+	// consensus rules.  Note that the header is copied to avoid mutating the
+	// shared block template.
+	msgBlock := template.Block
+	header := msgBlock.Header
+	s.cfg.BlockTemplater.UpdateBlockTime(&header)
+	headerBytes, err := header.Bytes()
+	if err != nil {
+		return nil, rpcInternalErr(err, "Failed to serialize header")
+	}
+
+	chain := s.cfg.Chain
+	medianTime, err := chain.MedianTimeByHash(&header.PrevBlock)
+	if err != nil {
+		context := "Failed to retrieve median time"
+		return nil, rpcInternalErr(err, context)
+	}
+	maxBlockSize, err := chain.MaxBlockSize(&header.PrevBlock)
+	if err != nil {
+		context := "Failed to retrieve max block size"
+		return nil, rpcInternalErr(err, context)
+	}
+
+	// Convert the transactions in both trees.  The fees and signature
+	// operation counts in the template are ordered with the regular tree
+	// followed by the stake tree.
+	numRegular := len(msgBlock.Transactions)
+	fees, sigOpCounts := template.Fees, template.SigOpCounts
+	regularTxns, err := templateTxns(msgBlock.Transactions, fees, sigOpCounts, 0)
+	if err != nil {
+		return nil, err
+	}
+	var stakeFees, stakeSigOpCounts []int64
+	if len(fees) > numRegular {
+		stakeFees = fees[numRegular:]
+	}
+	if len(sigOpCounts) > numRegular {
+		stakeSigOpCounts = sigOpCounts[numRegular:]
+	}
+	stakeTxns, err := templateTxns(msgBlock.STransactions, stakeFees,
+		stakeSigOpCounts, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Split the subsidy into its proof-of-work, proof-of-stake, and treasury
+	// portions based on the number of votes included in the template.
+	var voters uint16
+	for _, stx := range msgBlock.STransactions {
+		if stake.IsSSGen(stx) {
+			voters++
+		}
+	}
+	height := int64(header.Height)
+	subsidy, err := s.calcBlockSubsidy(&header.PrevBlock, height, voters)
+	if err != nil {
+		return nil, err
+	}
+	var totalFees int64
+	if len(fees) > 0 {
+		totalFees = -fees[0]
+	}
+
+	// The coinbase is not part of the transactions per BIP 0022.
+	var coinbaseTxn *types.GetBlockTemplateResultTx
+	if len(regularTxns) > 0 {
+		coinbaseTxn = &regularTxns[0]
+		coinbaseTxn.Fee = 0
+		regularTxns = regularTxns[1:]
+	}
+
+	// The KawPoW header and seed hashes are encoded in the same byte order as
+	// the stratum server uses for its jobs.
+	epoch := kawpow.EpochForHeight(uint64(header.Height))
+	seedHash := kawpow.SeedHash(epoch)
+	kawpowHeaderHash := header.KawPowHeaderHash()
+	target := standalone.CompactToBig(header.Bits)
+	reply := &types.GetBlockTemplateResult{
+		Header:           hex.EncodeToString(headerBytes),
+		Version:          header.Version,
+		PreviousHash:     header.PrevBlock.String(),
+		Height:           height,
+		CurTime:          header.Timestamp.Unix(),
+		Bits:             strconv.FormatInt(int64(header.Bits), 16),
+		SizeLimit:        maxBlockSize,
+		CoinbaseTxn:      coinbaseTxn,
+		Transactions:     regularTxns,
+		STransactions:    stakeTxns,
+		StakeVersion:     header.StakeVersion,
+		SBits:            header.SBits,
+		Voters:           voters,
+		CoinbaseValue:    subsidy.PoW + totalFees,
+		Fees:             totalFees,
+		Subsidy:          *subsidy,
+		KawPoWEpoch:      epoch,
+		KawPoWSeedHash:   hex.EncodeToString(seedHash[:]),
+		KawPoWHeaderHash: hex.EncodeToString(kawpowHeaderHash[:]),
+		Capabilities:     gbtCapabilities,
+		LongPollID:       templateLongPollID(template),
+		Target:           fmt.Sprintf("%064x", target),
+		MinTime:          medianTime.Unix() + 1,
+		MaxTime:          header.Timestamp.Unix() + blockchain.MaxTimeOffsetSeconds,
+		Mutable:          gbtMutableFields,
+	}
+	return reply, nil
+}
+
+// handleGetBlockTemplateRequest is a helper for handleGetBlockTemplate which
+// deals with generating and returning block templates to the caller.  It
+// supports long polling by blocking until a template that differs from the
+// one identified by the provided long poll id is available.
+func handleGetBlockTemplateRequest(ctx context.Context, s *Server, request *types.TemplateRequest) (interface{}, error) {
+	bt := s.cfg.BlockTemplater
+	if bt == nil {
+		err := errors.New("node is not configured for mining")
+		return nil, rpcInternalErr(err, "Configuration")
+	}
+	if err := s.checkMiningReady(); err != nil {
+		return nil, err
+	}
+
+	// Return an error immediately in the case of a failed background template.
+	template, err := bt.CurrentTemplate()
+	if err != nil {
+		return nil, rpcMiscError(fmt.Sprintf("no template is available: %v",
+			err))
+	}
+
+	// Wait for a new template when the caller provided the long poll id of the
+	// current template.  Long poll ids that do not match the current template
+	// are stale and therefore immediately return the current template.
+	if request != nil && request.LongPollID != "" && template != nil &&
+		templateLongPollID(template) == request.LongPollID {
+
+		templateSub := bt.Subscribe()
+		for template != nil &&
+			templateLongPollID(template) == request.LongPollID {
+
+			select {
+			case templateNtfn := <-templateSub.C():
+				template = templateNtfn.Template
+			case <-ctx.Done():
+				templateSub.Stop()
+				return nil, rpcConnectionClosedError()
+			}
+		}
+		templateSub.Stop()
+	}
+	if template == nil {
+		return nil, rpcMiscError("no template is available during a chain " +
+			"reorganization")
+	}
+
+	return s.blockTemplateResult(template)
+}
+
+// handleGetBlockTemplate implements the getblocktemplate command.
+func handleGetBlockTemplate(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetBlockTemplateCmd)
+	request := c.Request
+
+	// Set the default mode and override it if supplied.
+	mode := "template"
+	if request != nil && request.Mode != "" {
+		mode = request.Mode
+	}
+
+	switch mode {
+	case "template":
+		return handleGetBlockTemplateRequest(ctx, s, request)
+	case "proposal":
+		// Block proposals are not supported since the block connection
+		// checks that would be required to validate them are not performed.
+		return nil, VGLjson.NewRPCError(VGLjson.ErrRPCUnimplemented,
+			"Block proposals are not supported")
+	}
+
+	return nil, rpcInvalidError("Invalid mode")
 }
 
 // handleGetChainTips implements the getchaintips command.
@@ -2495,7 +2960,7 @@ func handleGetCFilterV2(_ context.Context, s *Server, cmd interface{}) (interfac
 
 	var proofHashes []string
 	if len(proof.ProofHashes) > 0 {
-		proofHashes = make([]string, 0, len(proof.ProofHashes))
+		proofHashes = make([]string, len(proof.ProofHashes))
 		for i := range proof.ProofHashes {
 			proofHashes[i] = proof.ProofHashes[i].String()
 		}
@@ -2541,8 +3006,9 @@ func handleGetMempoolInfo(_ context.Context, s *Server, _ interface{}) (interfac
 	}
 
 	ret := &types.GetMempoolInfoResult{
-		Size:  int64(len(mempoolTxns)),
-		Bytes: numBytes,
+		Size:          int64(len(mempoolTxns)),
+		Bytes:         numBytes,
+		MempoolMinFee: s.cfg.TxMempooler.MinRelayTxFee().ToCoin(),
 	}
 
 	return ret, nil
@@ -4044,12 +4510,23 @@ func handleGetWorkSubmission(_ context.Context, s *Server, hexData string) (inte
 	// why the block was rejected since attempting to determine the state of a
 	// voting agenda requires all previous blocks to be known.
 	prevBlkHash := &submittedHeader.PrevBlock
-	if _, err := s.cfg.Chain.HeaderByHash(prevBlkHash); err != nil {
+	prevHeader, err := s.cfg.Chain.HeaderByHash(prevBlkHash)
+	if err != nil {
 		log.Infof("Block submitted via getwork rejected: orphan building on "+
 			"parent %v", prevBlkHash)
 		return false, nil // nolint: nilerr
 	}
 
+	// Reject blocks that do not commit to the height after their parent since
+	// verifying the KawPoW solution requires the light cache for the epoch of
+	// the claimed height.
+	if submittedHeader.Height != prevHeader.Height+1 {
+		log.Infof("Block submitted via getwork rejected: height %d does not "+
+			"follow parent height %d", submittedHeader.Height,
+			prevHeader.Height)
+		return false, nil
+	}
+
 	// Determine if KawPoW is active.
 	isKawpowActive, err := s.isKawpowAgendaActive(prevBlkHash)
 	if err != nil {
@@ -4170,11 +4647,11 @@ func handleGetWorkSubmission(_ context.Context, s *Server, hexData string) (inte
 	
 	// Add pow hash info.
 	if isKawpowActive {
-			powHash, _, err := submittedHeader.PowHashKawPow()
+			_, powHash, err := submittedHeader.PowHashKawPow()
 			if err != nil {
 				return false, err
 			}
-			powHashStr = powHash.String()
+			powHashStr = ", pow hash " + powHash.String()
 	} else {
 		powHashFn := submittedHeader.PowHashV1
 		if isBlake3PowActive {
@@ -4191,26 +4668,21 @@ func handleGetWorkSubmission(_ context.Context, s *Server, hexData string) (inte
 	return true, nil
 }
 
-// handleGetWork implements the getwork command.
-func handleGetWork(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
-	if s.cfg.CPUMiner.IsMining() {
-		return nil, rpcMiscError("getwork polling is disallowed " +
-			"while CPU mining is enabled. Please disable CPU " +
-			"mining and try again.")
-	}
-
+// checkMiningReady returns an error suitable for returning to RPC callers when
+// the server is not in a state that allows it to hand out or accept work.
+func (s *Server) checkMiningReady() error {
 	// Respond with an error if there are no addresses to pay the created
 	// blocks to.
 	if len(s.cfg.MiningAddrs) == 0 {
 		err := errors.New("no payment addresses specified via --miningaddr")
-		return nil, rpcInternalErr(err, "Configuration")
+		return rpcInternalErr(err, "Configuration")
 	}
 
 	// Return an error if there are no peers connected since there is no way to
 	// relay a found block or receive transactions to work on unless
 	// unsynchronized mining has specifically been allowed.
 	if !s.cfg.AllowUnsyncedMining && s.cfg.ConnMgr.ConnectedCount() == 0 {
-		return nil, &VGLjson.RPCError{
+		return &VGLjson.RPCError{
 			Code:    VGLjson.ErrRPCClientNotConnected,
 			Message: "Vigil is not connected",
 		}
@@ -4223,12 +4695,27 @@ func handleGetWork(ctx context.Context, s *Server, cmd interface{}) (interface{}
 	bestHeight := chain.BestSnapshot().Height
 	initialChainState := bestHeaderHeight == 0 && bestHeight == 0
 	if !s.cfg.AllowUnsyncedMining && !initialChainState && !chain.IsCurrent() {
-		return nil, &VGLjson.RPCError{
+		return &VGLjson.RPCError{
 			Code:    VGLjson.ErrRPCClientInInitialDownload,
 			Message: "Vigil is downloading blocks...",
 		}
 	}
 
+	return nil
+}
+
+// handleGetWork implements the getwork command.
+func handleGetWork(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	if s.cfg.CPUMiner.IsMining() {
+		return nil, rpcMiscError("getwork polling is disallowed " +
+			"while CPU mining is enabled. Please disable CPU " +
+			"mining and try again.")
+	}
+
+	if err := s.checkMiningReady(); err != nil {
+		return nil, err
+	}
+
 	c := cmd.(*types.GetWorkCmd)
 
 	// Protect concurrent access from multiple RPC invocations for work requests
@@ -4402,6 +4889,452 @@ func handleRegenTemplate(_ context.Context, s *Server, _ interface{}) (interface
 	return nil, nil
 }
 
+// retrievedTx represents a transaction that was either loaded from the
+// transaction memory pool or from the database.  When a transaction is loaded
+// from the database, it is loaded with the raw serialized bytes while the
+// mempool has the fully deserialized structure.  This structure therefore will
+// have one of the two fields set depending on where is was retrieved from.
+// This is mainly done for efficiency to avoid extra serialization steps when
+// possible.
+type retrievedTx struct {
+	txBytes  []byte
+	blkHash  *chainhash.Hash // Only set when transaction is in a block.
+	blkIndex uint32          // Only set when transaction is in a block.
+	tx       *VGLutil.Tx
+}
+
+// msgTx returns the deserialized transaction, deserializing it from the raw
+// bytes first when it was loaded from the database.
+func (rtx *retrievedTx) msgTx() (*wire.MsgTx, error) {
+	if rtx.tx != nil {
+		return rtx.tx.MsgTx(), nil
+	}
+
+	var mtx wire.MsgTx
+	err := mtx.Deserialize(bytes.NewReader(rtx.txBytes))
+	if err != nil {
+		return nil, rpcInternalErr(err, "Failed to deserialize transaction")
+	}
+	return &mtx, nil
+}
+
+// fetchInputTxos fetches the outputs from all transactions referenced by the
+// inputs to the passed transaction by checking the transaction mempool first
+// then the transaction index for those already mined into blocks.
+func fetchInputTxos(s *Server, tx *wire.MsgTx, isTreasuryEnabled bool) (map[wire.OutPoint]*wire.TxOut, error) {
+	originOutputs := make(map[wire.OutPoint]*wire.TxOut)
+
+	// Coinbases, treasurybases, and treasury spends do not reference any
+	// previous outputs.
+	if standalone.IsCoinBaseTx(tx, isTreasuryEnabled) ||
+		(isTreasuryEnabled && (standalone.IsTreasuryBase(tx) ||
+			stake.IsTSpend(tx))) {
+
+		return originOutputs, nil
+	}
+
+	txIndex := s.cfg.TxIndexer
+	if txIndex == nil {
+		err := errors.New("the transaction index must be enabled to " +
+			"query previous outputs (specify --txindex)")
+		return nil, rpcInternalErr(err, "Configuration")
+	}
+
+	isVote := stake.IsSSGen(tx)
+	for txInIndex, txIn := range tx.TxIn {
+		// The first input of a vote is the stakebase which does not
+		// reference a previous output.
+		if isVote && txInIndex == 0 {
+			continue
+		}
+
+		// Attempt to fetch and use the referenced transaction from the
+		// memory pool.
+		origin := &txIn.PreviousOutPoint
+		var originTx *wire.MsgTx
+		if mpTx, err := s.cfg.TxMempooler.FetchTransaction(&origin.Hash); err == nil {
+			originTx = mpTx.MsgTx()
+		} else {
+			// Look up the location of the transaction.
+			idxEntry, err := txIndex.Entry(&origin.Hash)
+			if err != nil {
+				const context = "Failed to retrieve transaction location"
+				return nil, rpcInternalErr(err, context)
+			}
+			if idxEntry == nil {
+				return nil, rpcNoTxInfoError(&origin.Hash)
+			}
+
+			// Load the raw transaction bytes from the database.
+			var txBytes []byte
+			err = s.cfg.DB.View(func(dbTx database.Tx) error {
+				var err error
+				txBytes, err = dbTx.FetchBlockRegion(&idxEntry.BlockRegion)
+				return err
+			})
+			if err != nil {
+				return nil, rpcNoTxInfoError(&origin.Hash)
+			}
+
+			// Deserialize the transaction.
+			var msgTx wire.MsgTx
+			err = msgTx.Deserialize(bytes.NewReader(txBytes))
+			if err != nil {
+				const context = "Failed to deserialize transaction"
+				return nil, rpcInternalErr(err, context)
+			}
+			originTx = &msgTx
+		}
+
+		// Add the referenced output to the map.
+		if origin.Index >= uint32(len(originTx.TxOut)) {
+			return nil, rpcInvalidError("Unable to find output %v "+
+				"referenced from transaction %s:%d", origin, tx.TxHash(),
+				txInIndex)
+		}
+		originOutputs[*origin] = originTx.TxOut[origin.Index]
+	}
+
+	return originOutputs, nil
+}
+
+// createVinListPrevOut returns a slice of JSON objects for the inputs of the
+// passed transaction.  The previous output details are included when vinExtra
+// is set and, when filter addresses are provided, only the inputs that spend
+// outputs which pay to one of them are included.
+func createVinListPrevOut(s *Server, mtx *wire.MsgTx, chainParams *chaincfg.Params,
+	vinExtra bool, filterAddrMap map[string]struct{},
+	isTreasuryEnabled bool) ([]types.VinPrevOut, error) {
+
+	// Lookup all of the referenced transaction outputs needed to populate
+	// the previous output information if requested.
+	var originOutputs map[wire.OutPoint]*wire.TxOut
+	if vinExtra || len(filterAddrMap) > 0 {
+		var err error
+		originOutputs, err = fetchInputTxos(s, mtx, isTreasuryEnabled)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Use a dynamically sized list to accommodate the address filter.
+	vins := createVinList(mtx, isTreasuryEnabled)
+	vinList := make([]types.VinPrevOut, 0, len(vins))
+	for i := range vins {
+		vin := &vins[i]
+		vinEntry := types.VinPrevOut{
+			Coinbase:      vin.Coinbase,
+			Stakebase:     vin.Stakebase,
+			Treasurybase:  vin.Treasurybase,
+			TreasurySpend: vin.TreasurySpend,
+			Txid:          vin.Txid,
+			Vout:          vin.Vout,
+			Tree:          vin.Tree,
+			Sequence:      vin.Sequence,
+			AmountIn:      vin.AmountIn,
+			BlockHeight:   vin.BlockHeight,
+			BlockIndex:    vin.BlockIndex,
+			ScriptSig:     vin.ScriptSig,
+		}
+
+		// Only inputs that spend a previous output have additional
+		// information to provide.
+		originTxOut, ok := originOutputs[mtx.TxIn[i].PreviousOutPoint]
+		if vin.Txid == "" || !ok {
+			vinList = append(vinList, vinEntry)
+			continue
+		}
+
+		// Attempt to extract known addresses associated with the script
+		// while checking if the input passes the filter when needed.
+		_, addrs := stdscript.ExtractAddrs(originTxOut.Version,
+			originTxOut.PkScript, chainParams)
+		passesFilter := len(filterAddrMap) == 0
+		encodedAddrs := make([]string, len(addrs))
+		for j, addr := range addrs {
+			encodedAddr := addr.String()
+			encodedAddrs[j] = encodedAddr
+
+			// No need to check the map again if the filter already
+			// passes.
+			if passesFilter {
+				continue
+			}
+			if _, exists := filterAddrMap[encodedAddr]; exists {
+				passesFilter = true
+			}
+		}
+		if !passesFilter {
+			continue
+		}
+
+		if vinExtra {
+			vinEntry.PrevOut = &types.PrevOut{
+				Addresses: encodedAddrs,
+				Value:     VGLutil.Amount(originTxOut.Value).ToCoin(),
+			}
+		}
+		vinList = append(vinList, vinEntry)
+	}
+
+	return vinList, nil
+}
+
+// fetchMempoolTxnsForAddress queries the address index for all unconfirmed
+// transactions that involve the provided address.  The results will be limited
+// by the number to skip and the number requested.
+func fetchMempoolTxnsForAddress(addrIndex AddrIndexer, addr stdaddr.Address,
+	numToSkip, numRequested uint32) ([]*VGLutil.Tx, uint32) {
+
+	// There are no entries to return when there are less available than the
+	// number being skipped.
+	mpTxns := addrIndex.UnconfirmedTxnsForAddress(addr)
+	numAvailable := uint32(len(mpTxns))
+	if numToSkip > numAvailable {
+		return nil, numAvailable
+	}
+
+	// Filter the available entries based on the number to skip and number
+	// requested.
+	rangeEnd := numToSkip + numRequested
+	if rangeEnd > numAvailable {
+		rangeEnd = numAvailable
+	}
+	return mpTxns[numToSkip:rangeEnd], numToSkip
+}
+
+// fetchAddrTxns returns the transactions that involve the provided address
+// from both the address index and the memory pool limited by the number to
+// skip and the number requested.  The unconfirmed transactions come first when
+// reverse is set and last otherwise.
+func (s *Server) fetchAddrTxns(addrIndex AddrIndexer, addr stdaddr.Address,
+	numToSkip, numRequested uint32, reverse bool) ([]retrievedTx, error) {
+
+	// Add transactions from mempool first if client asked for reverse
+	// order.  Otherwise, they will be added last (as needed depending on
+	// the requested counts).
+	//
+	// NOTE: This code doesn't sort by dependency.  This might be something
+	// to do in the future for the client's convenience, or leave it to the
+	// client.
+	var numSkipped uint32
+	addressTxns := make([]retrievedTx, 0, numRequested)
+	if reverse {
+		mpTxns, mpSkipped := fetchMempoolTxnsForAddress(addrIndex, addr,
+			numToSkip, numRequested)
+		numSkipped += mpSkipped
+		for _, tx := range mpTxns {
+			addressTxns = append(addressTxns, retrievedTx{tx: tx})
+		}
+	}
+
+	// Fetch transactions from the database in the desired order if more are
+	// needed.
+	if uint32(len(addressTxns)) < numRequested {
+		entries, dbSkipped, err := addrIndex.EntriesForAddress(addr,
+			numToSkip-numSkipped, numRequested-uint32(len(addressTxns)),
+			reverse)
+		if err != nil {
+			const context = "Failed to load address index entries"
+			return nil, rpcInternalErr(err, context)
+		}
+		numSkipped += dbSkipped
+
+		// Load the raw transaction bytes from the database.
+		regions := make([]database.BlockRegion, 0, len(entries))
+		for i := range entries {
+			regions = append(regions, entries[i].BlockRegion)
+		}
+		var serializedTxns [][]byte
+		err = s.cfg.DB.View(func(dbTx database.Tx) error {
+			var err error
+			serializedTxns, err = dbTx.FetchBlockRegions(regions)
+			return err
+		})
+		if err != nil {
+			const context = "Failed to load address index entries"
+			return nil, rpcInternalErr(err, context)
+		}
+		for i, serializedTx := range serializedTxns {
+			addressTxns = append(addressTxns, retrievedTx{
+				txBytes:  serializedTx,
+				blkHash:  entries[i].BlockRegion.Hash,
+				blkIndex: entries[i].BlockIndex,
+			})
+		}
+	}
+
+	// Add transactions from mempool last if client did not request reverse
+	// order and the number of results is still under the number requested.
+	if !reverse && uint32(len(addressTxns)) < numRequested {
+		mpTxns, _ := fetchMempoolTxnsForAddress(addrIndex, addr,
+			numToSkip-numSkipped, numRequested-uint32(len(addressTxns)))
+		for _, tx := range mpTxns {
+			addressTxns = append(addressTxns, retrievedTx{tx: tx})
+		}
+	}
+
+	return addressTxns, nil
+}
+
+// handleSearchRawTransactions implements the searchrawtransactions command.
+func handleSearchRawTransactions(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.SearchRawTransactionsCmd)
+
+	addrIndex, err := s.syncedAddrIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the provided address.  This also ensures the network encoded with
+	// the address matches the network the server is currently on.
+	chainParams := s.cfg.ChainParams
+	addr, err := stdaddr.DecodeAddress(c.Address, chainParams)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
+	}
+
+	// Override the flag for including extra previous output information in
+	// each input if needed.
+	var vinExtra bool
+	if c.VinExtra != nil {
+		vinExtra = *c.VinExtra != 0
+	}
+
+	// Override the default number of requested entries if needed.  Also,
+	// just return now if the number of requested entries is zero to avoid
+	// extra work.
+	numRequested := 100
+	if c.Count != nil {
+		numRequested = *c.Count
+		if numRequested < 0 {
+			numRequested = 1
+		}
+	}
+	if numRequested == 0 {
+		return nil, nil
+	}
+
+	// Override the default number of entries to skip if needed.
+	var numToSkip int
+	if c.Skip != nil && *c.Skip > 0 {
+		numToSkip = *c.Skip
+	}
+
+	var reverse bool
+	if c.Reverse != nil {
+		reverse = *c.Reverse
+	}
+
+	addressTxns, err := s.fetchAddrTxns(addrIndex, addr, uint32(numToSkip),
+		uint32(numRequested), reverse)
+	if err != nil {
+		return nil, err
+	}
+
+	// Address has never been used if neither source yielded any results.
+	if len(addressTxns) == 0 {
+		return nil, VGLjson.NewRPCError(VGLjson.ErrRPCNoTxInfo,
+			"No information available about address")
+	}
+
+	// Serialize all of the transactions to hex.
+	hexTxns := make([]string, len(addressTxns))
+	for i := range addressTxns {
+		// Simply encode the raw bytes to hex when the retrieved
+		// transaction is already in serialized form.
+		rtx := &addressTxns[i]
+		if rtx.txBytes != nil {
+			hexTxns[i] = hex.EncodeToString(rtx.txBytes)
+			continue
+		}
+
+		// Serialize the transaction first and convert to hex when the
+		// retrieved transaction is the deserialized structure.
+		hexTxns[i], err = s.messageToHex(rtx.tx.MsgTx())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// When not in verbose mode, simply return a list of serialized txns.
+	if c.Verbose != nil && *c.Verbose == 0 {
+		return hexTxns, nil
+	}
+
+	// Normalize the provided filter addresses (if any) to ensure there are
+	// no duplicates.
+	filterAddrMap := make(map[string]struct{})
+	if c.FilterAddrs != nil {
+		for _, addr := range *c.FilterAddrs {
+			filterAddrMap[addr] = struct{}{}
+		}
+	}
+
+	// The verbose flag is set, so generate the JSON object and return it.
+	chain := s.cfg.Chain
+	best := chain.BestSnapshot()
+	srtList := make([]types.SearchRawTransactionsResult, len(addressTxns))
+	for i := range addressTxns {
+		rtx := &addressTxns[i]
+		mtx, err := rtx.msgTx()
+		if err != nil {
+			return nil, err
+		}
+
+		// Transactions grabbed from the mempool aren't yet in a block, so
+		// conditionally fetch block details here.  This will be reflected in
+		// the final JSON output (mempool won't have confirmations or block
+		// information).
+		var blkHeader *wire.BlockHeader
+		prevBlkHash := best.Hash
+		if rtx.blkHash != nil {
+			header, err := chain.HeaderByHash(rtx.blkHash)
+			if err != nil {
+				const context = "Failed to fetch block header"
+				return nil, rpcInternalErr(err, context)
+			}
+			blkHeader = &header
+			prevBlkHash = header.PrevBlock
+		}
+
+		// Determine if the treasury rules are active as of either the block
+		// that contains the transaction or the current best tip when it is in
+		// the mempool.
+		isTreasuryEnabled, err := s.isTreasuryAgendaActive(&prevBlkHash)
+		if err != nil {
+			return nil, rpcInternalErr(err, "Treasury Status")
+		}
+
+		vinList, err := createVinListPrevOut(s, mtx, chainParams, vinExtra,
+			filterAddrMap, isTreasuryEnabled)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &srtList[i]
+		result.Hex = hexTxns[i]
+		result.Txid = mtx.TxHash().String()
+		result.Vin = vinList
+		result.Vout = createVoutList(mtx, chainParams, filterAddrMap)
+		result.Version = int32(mtx.Version)
+		result.LockTime = mtx.LockTime
+		result.Expiry = mtx.Expiry
+		if blkHeader != nil {
+			// This is not a typo, they are identical in bitcoind as well.
+			result.Time = blkHeader.Timestamp.Unix()
+			result.Blocktime = blkHeader.Timestamp.Unix()
+			result.BlockHash = rtx.blkHash.String()
+			result.BlockHeight = int64(blkHeader.Height)
+			result.BlockIndex = rtx.blkIndex
+			result.Confirmations = 1 + best.Height - int64(blkHeader.Height)
+		}
+	}
+
+	return srtList, nil
+}
+
 // handleSendRawMixMessage implements the sendrawmixmessage command.
 func handleSendRawMixMessage(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.SendRawMixMessageCmd)
@@ -5303,6 +6236,10 @@ type Server struct {
 	helpCacher             RPCHelpCacher
 	requestProcessShutdown chan struct{}
 
+	// restLimiter limits the rate of requests made to the REST interface by
+	// each client.  It is only set when the REST interface is enabled.
+	restLimiter *restRateLimiter
+
 	// kawpowHasher is the hash.Hash object that is used after
 	// deserializing mixing messages.  Message handlers may be executed
 	// concurrently, and access requires the mutex.
@@ -6037,6 +6974,11 @@ func (s *Server) route(ctx context.Context) *http.Server {
 		s.jsonRPCRead(r.Context(), w, r, isAdmin)
 	})
 
+	// REST endpoint.
+	if s.cfg.REST {
+		rpcServeMux.HandleFunc(restPathPrefix, s.restHandler)
+	}
+
 	// Websocket endpoint.
 	rpcServeMux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		authenticated, isAdmin, err := s.checkAuth(r, false)
@@ -6245,6 +7187,10 @@ type Config struct {
 	// use.
 	TxIndexer TxIndexer
 
+	// AddrIndexer defines the optional address indexer for the RPC server to
+	// use.
+	AddrIndexer AddrIndexer
+
 	// NetInfo defines a slice of the available networks.
 	NetInfo []types.NetworksResult
 
@@ -6298,6 +7244,14 @@ type Config struct {
 
 	// MixPooler defines the mixpool for the RPC server to use.
 	MixPooler MixPooler
+
+	// REST specifies whether or not the unauthenticated REST interface for
+	// querying public chain data is enabled.
+	REST bool
+
+	// RESTRateLimit defines the max number of requests per second each client
+	// may make to the REST interface.
+	RESTRateLimit int
 }
 
 // New returns a new instance of the Server struct.
@@ -6328,6 +7282,9 @@ func New(config *Config) (*Server, error) {
 			base64.StdEncoding.EncodeToString([]byte(login))
 		rpc.authMAC(rpc.limitauthsha[:0], []byte(auth))
 	}
+	if config.REST {
+		rpc.restLimiter = newRESTRateLimiter(config.RESTRateLimit)
+	}
 	rpc.ntfnMgr = newWsNotificationManager(&rpc)
 
 	return &rpc, nil
@@ -41,6 +41,7 @@ import (
 	"github.com/kdsmith18542/vigil/internal/mempool"
 	"github.com/kdsmith18542/vigil/internal/mining"
 	"github.com/kdsmith18542/vigil/internal/version"
+	"github.com/kdsmith18542/vigil/kawpow"
 	"github.com/kdsmith18542/vigil/math/uint256"
 	"github.com/kdsmith18542/vigil/mixing"
 	"github.com/kdsmith18542/vigil/peer/v3"
@@ -705,6 +706,80 @@ func (t *testTxIndexer) Entry(hash *chainhash.Hash) (*indexers.TxIndexEntry, err
 	return t.entry(hash)
 }
 
+// testAddrIndexer provides a mock address indexer by implementing the
+// AddrIndexer interface.
+type testAddrIndexer struct {
+	entries         []indexers.TxIndexEntry
+	entriesErr      error
+	unconfirmedTxns []*VGLutil.Tx
+	utxos           []*indexers.AddrUtxo
+	utxosErr        error
+	tipHeight       int64
+	tipHash         *chainhash.Hash
+	tipErr          error
+	signalOnWait    bool
+}
+
+// Name returns the human-readable name of the index.
+func (a *testAddrIndexer) Name() string {
+	return "testAddrIndexer"
+}
+
+// Tip returns the current index tip.
+func (a *testAddrIndexer) Tip() (int64, *chainhash.Hash, error) {
+	return a.tipHeight, a.tipHash, a.tipErr
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+func (a *testAddrIndexer) WaitForSync() chan bool {
+	c := make(chan bool)
+	if a.signalOnWait {
+		close(c)
+	}
+	return c
+}
+
+// EntriesForAddress returns the mocked transaction index entries for the
+// provided address limited by the number to skip and the number requested.
+func (a *testAddrIndexer) EntriesForAddress(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]indexers.TxIndexEntry, uint32, error) {
+	if a.entriesErr != nil {
+		return nil, 0, a.entriesErr
+	}
+	numAvailable := uint32(len(a.entries))
+	if numToSkip > numAvailable {
+		return nil, numAvailable, nil
+	}
+	rangeEnd := numToSkip + numRequested
+	if rangeEnd > numAvailable {
+		rangeEnd = numAvailable
+	}
+	return a.entries[numToSkip:rangeEnd], numToSkip, nil
+}
+
+// UnconfirmedTxnsForAddress returns the mocked unconfirmed transactions for
+// the provided address.
+func (a *testAddrIndexer) UnconfirmedTxnsForAddress(addr stdaddr.Address) []*VGLutil.Tx {
+	return a.unconfirmedTxns
+}
+
+// UnspentOutputs returns the mocked unspent outputs for the provided address.
+func (a *testAddrIndexer) UnspentOutputs(addr stdaddr.Address) ([]*indexers.AddrUtxo, error) {
+	return a.utxos, a.utxosErr
+}
+
+// Balance returns the sum and count of the mocked unspent outputs for the
+// provided address.
+func (a *testAddrIndexer) Balance(addr stdaddr.Address) (int64, int, error) {
+	if a.utxosErr != nil {
+		return 0, 0, a.utxosErr
+	}
+	var balance int64
+	for _, utxo := range a.utxos {
+		balance += utxo.Amount
+	}
+	return balance, len(a.utxos), nil
+}
+
 // testDB provides a mock database by implementing the database.DB interface.
 type testDB struct {
 	dbType   string
@@ -1144,6 +1219,7 @@ type testTxMempooler struct {
 	fetchTransaction    *VGLutil.Tx
 	fetchTransactionErr error
 	tspendHashes        []chainhash.Hash
+	minRelayTxFee       VGLutil.Amount
 }
 
 // HaveTransactions returns a mocked bool slice representing whether or not the
@@ -1181,6 +1257,12 @@ func (mp *testTxMempooler) TSpendHashes() []chainhash.Hash {
 	return mp.tspendHashes
 }
 
+// MinRelayTxFee returns the mocked minimum fee per kilobyte a transaction must
+// pay to be accepted to the pool.
+func (mp *testTxMempooler) MinRelayTxFee() VGLutil.Amount {
+	return mp.minRelayTxFee
+}
+
 // testNtfnManager provides a mock notification manager by implementing the
 // NtfnManager interface.
 type testNtfnManager struct {
@@ -1438,6 +1520,8 @@ type rpcTest struct {
 	setExistsAddresserNil bool
 	mockTxIndexer         *testTxIndexer
 	setTxIndexerNil       bool
+	mockAddrIndexer       *testAddrIndexer
+	setAddrIndexerNil     bool
 	mockDB                *testDB
 	mockConnManager       *testConnManager
 	mockClock             *testClock
@@ -1614,7 +1698,7 @@ func defaultMockSanityChecker() *testSanityChecker {
 // *testMiningState, and then setting rpcTest.mockMiningState as that
 // *testMiningState.
 func defaultMockMiningState() *testMiningState {
-	addrStr := "DcurAwesomeAddressmqDctW5wJCW1Cn2MF"
+	addrStr := "VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz"
 	addr, err := stdaddr.DecodeAddress(addrStr, defaultChainParams)
 	if err != nil {
 		panic(fmt.Sprintf("invalid address %q in source file: %v", addrStr, err))
@@ -1700,6 +1784,21 @@ func defaultMockTxIndexer() *testTxIndexer {
 	}
 }
 
+// defaultMockAddrIndexer provides a default mock address indexer to be used
+// throughout the tests. Tests can override these defaults by calling
+// defaultMockAddrIndexer, updating fields as necessary on the returned
+// *testAddrIndexer, and then setting rpcTest.mockAddrIndexer as that
+// *testAddrIndexer.
+func defaultMockAddrIndexer() *testAddrIndexer {
+	bestHeight := int64(block432100.Header.Height)
+	bestHash := block432100.Header.BlockHash()
+	return &testAddrIndexer{
+		tipHeight:    bestHeight,
+		tipHash:      &bestHash,
+		signalOnWait: true,
+	}
+}
+
 // defaultMockDB provides a default mock database to be used throughout the
 // tests. Tests can override these defaults by calling defaultMockDB, updating
 // fields as necessary on the returned *testDB, and then setting rpcTest.mockDB
@@ -1847,6 +1946,7 @@ func defaultMockCPUMiner() *testCPUMiner {
 func defaultMockTxMempooler() *testTxMempooler {
 	return &testTxMempooler{
 		fetchTransactionErr: errors.New("transaction is not in the pool"),
+		minRelayTxFee:       1e4,
 	}
 }
 
@@ -1863,6 +1963,7 @@ func defaultMockConfig(chainParams *chaincfg.Params) *Config {
 		SyncMgr:         defaultMockSyncManager(),
 		ExistsAddresser: defaultMockExistsAddresser(),
 		TxIndexer:       defaultMockTxIndexer(),
+		AddrIndexer:     defaultMockAddrIndexer(),
 		DB:              defaultMockDB(),
 		ConnMgr:         defaultMockConnManager(),
 		CPUMiner:        defaultMockCPUMiner(),
@@ -1973,11 +2074,11 @@ func TestHandleCreateRawSStx(t *testing.T) {
 		Tree: 0,
 		Amt:  100000000,
 	}}
-	defaultCmdAmount := map[string]int64{"DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS": 100000000}
+	defaultCmdAmount := map[string]int64{"VcnoGWXXbiAVuzhcAWwrGQsbDQLyFWYnHpE": 100000000}
 	defaultCmdCOuts := []types.SStxCommitOut{{
-		Addr:       "DsRah84zx6jdA4nMYboMfLERA5V3KhBr4ru",
+		Addr:       "VsJydgUMKNkBbxHsK3FSbZN5KSjAZA9yLBs",
 		CommitAmt:  100000000,
-		ChangeAddr: "DsfkbtrSUr5cFdQYq3WSKo9vvFs5qxZXbgF",
+		ChangeAddr: "VsZ9YTFnr86AhWv4bUxXG2Hb5d7D5NAog2i",
 		ChangeAmt:  0,
 	}}
 	testRPCServerHandler(t, []rpcTest{{
@@ -2020,13 +2121,13 @@ func TestHandleCreateRawSStx(t *testing.T) {
 		cmd: &types.CreateRawSStxCmd{
 			Inputs: defaultCmdInputs,
 			Amount: map[string]int64{
-				"DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS": 90000000,
-				"DcqgK4N4Ccucu2Sq4VDAdu4wH4LASLhzLVp": 10000000,
+				"VcnoGWXXbiAVuzhcAWwrGQsbDQLyFWYnHpE": 90000000,
+				"Vcj5FcmQZtvBLuxLpvfFa8CbSRaHfhYRE7s": 10000000,
 			},
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DsRah84zx6jdA4nMYboMfLERA5V3KhBr4ru",
+				Addr:       "VsJydgUMKNkBbxHsK3FSbZN5KSjAZA9yLBs",
 				CommitAmt:  90000000,
-				ChangeAddr: "DsfkbtrSUr5cFdQYq3WSKo9vvFs5qxZXbgF",
+				ChangeAddr: "VsZ9YTFnr86AhWv4bUxXG2Hb5d7D5NAog2i",
 				ChangeAmt:  10000000,
 			}},
 		},
@@ -2068,7 +2169,7 @@ func TestHandleCreateRawSStx(t *testing.T) {
 		cmd: &types.CreateRawSStxCmd{
 			Inputs: defaultCmdInputs,
 			Amount: map[string]int64{
-				"DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS": VGLutil.MaxAmount + 1,
+				"VcnoGWXXbiAVuzhcAWwrGQsbDQLyFWYnHpE": VGLutil.MaxAmount + 1,
 			},
 			COuts: defaultCmdCOuts,
 		},
@@ -2079,7 +2180,7 @@ func TestHandleCreateRawSStx(t *testing.T) {
 		handler: handleCreateRawSStx,
 		cmd: &types.CreateRawSStxCmd{
 			Inputs: defaultCmdInputs,
-			Amount: map[string]int64{"DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS": -1},
+			Amount: map[string]int64{"VcnoGWXXbiAVuzhcAWwrGQsbDQLyFWYnHpE": -1},
 			COuts:  defaultCmdCOuts,
 		},
 		wantErr: true,
@@ -2100,7 +2201,7 @@ func TestHandleCreateRawSStx(t *testing.T) {
 		cmd: &types.CreateRawSStxCmd{
 			Inputs: defaultCmdInputs,
 			Amount: map[string]int64{
-				"DkM3EyZ546GghVSkvzb6J47PvGDyntqiDtFgipQhNj78Xm2mUYRpf": 100000000,
+				"VkSwJ85pxSkhK83rLhyVCp4wfgGSUSJw5DwzTUNPMKHY4KphbxzRQ": 100000000,
 			},
 			COuts: defaultCmdCOuts,
 		},
@@ -2111,7 +2212,7 @@ func TestHandleCreateRawSStx(t *testing.T) {
 		handler: handleCreateRawSStx,
 		cmd: &types.CreateRawSStxCmd{
 			Inputs: defaultCmdInputs,
-			Amount: map[string]int64{"DSXcZv4oSRiEoWL2a9aD8sgfptRo1YEXNKj": 100000000},
+			Amount: map[string]int64{"VSR1WUU9ohioFPqYLb2J56pKzFfvEscYtkj": 100000000},
 			COuts:  defaultCmdCOuts,
 		},
 		wantErr: true,
@@ -2123,9 +2224,9 @@ func TestHandleCreateRawSStx(t *testing.T) {
 			Inputs: defaultCmdInputs,
 			Amount: defaultCmdAmount,
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DsRah84zx6jdA4nMYboMfLERA5V3KhBr4ru",
+				Addr:       "VsJydgUMKNkBbxHsK3FSbZN5KSjAZA9yLBs",
 				CommitAmt:  100000000,
-				ChangeAddr: "DsfkbtrSUr5cFdQYq3WSKo9vvFs5qxZXbgF",
+				ChangeAddr: "VsZ9YTFnr86AhWv4bUxXG2Hb5d7D5NAog2i",
 				ChangeAmt:  200000000,
 			}},
 		},
@@ -2138,9 +2239,9 @@ func TestHandleCreateRawSStx(t *testing.T) {
 			Inputs: defaultCmdInputs,
 			Amount: defaultCmdAmount,
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DsRaInvalidjdA4nMYboMfLERA5V3KhBr4ru",
+				Addr:       "VsRaInvalidjdA4nMYboMfLERA5V3KhBr4ru",
 				CommitAmt:  100000000,
-				ChangeAddr: "DsfkbtrSUr5cFdQYq3WSKo9vvFs5qxZXbgF",
+				ChangeAddr: "VsZ9YTFnr86AhWv4bUxXG2Hb5d7D5NAog2i",
 				ChangeAmt:  0,
 			}},
 		},
@@ -2153,9 +2254,9 @@ func TestHandleCreateRawSStx(t *testing.T) {
 			Inputs: defaultCmdInputs,
 			Amount: defaultCmdAmount,
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DkM3EyZ546GghVSkvzb6J47PvGDyntqiDtFgipQhNj78Xm2mUYRpf",
+				Addr:       "VkSwJ85pxSkhK83rLhyVCp4wfgGSUSJw5DwzTUNPMKHY4KphbxzRQ",
 				CommitAmt:  100000000,
-				ChangeAddr: "DsfkbtrSUr5cFdQYq3WSKo9vvFs5qxZXbgF",
+				ChangeAddr: "VsZ9YTFnr86AhWv4bUxXG2Hb5d7D5NAog2i",
 				ChangeAmt:  0,
 			}},
 		},
@@ -2168,9 +2269,9 @@ func TestHandleCreateRawSStx(t *testing.T) {
 			Inputs: defaultCmdInputs,
 			Amount: defaultCmdAmount,
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DSXcZv4oSRiEoWL2a9aD8sgfptRo1YEXNKj",
+				Addr:       "VSR1WUU9ohioFPqYLb2J56pKzFfvEscYtkj",
 				CommitAmt:  100000000,
-				ChangeAddr: "DsfkbtrSUr5cFdQYq3WSKo9vvFs5qxZXbgF",
+				ChangeAddr: "VsZ9YTFnr86AhWv4bUxXG2Hb5d7D5NAog2i",
 				ChangeAmt:  0,
 			}},
 		},
@@ -2188,9 +2289,9 @@ func TestHandleCreateRawSStx(t *testing.T) {
 			}},
 			Amount: defaultCmdAmount,
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DsRah84zx6jdA4nMYboMfLERA5V3KhBr4ru",
+				Addr:       "VsJydgUMKNkBbxHsK3FSbZN5KSjAZA9yLBs",
 				CommitAmt:  100000000,
-				ChangeAddr: "DsfkbtrSUr5cFdQYq3WSKo9vvFs5qxZXbgF",
+				ChangeAddr: "VsZ9YTFnr86AhWv4bUxXG2Hb5d7D5NAog2i",
 				ChangeAmt:  VGLutil.MaxAmount + 1,
 			}},
 		},
@@ -2203,9 +2304,9 @@ func TestHandleCreateRawSStx(t *testing.T) {
 			Inputs: defaultCmdInputs,
 			Amount: defaultCmdAmount,
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DsRah84zx6jdA4nMYboMfLERA5V3KhBr4ru",
+				Addr:       "VsJydgUMKNkBbxHsK3FSbZN5KSjAZA9yLBs",
 				CommitAmt:  100000000,
-				ChangeAddr: "DsfkbtrSUr5cFdQYq3WSKo9vvFs5qxZXbgF",
+				ChangeAddr: "VsZ9YTFnr86AhWv4bUxXG2Hb5d7D5NAog2i",
 				ChangeAmt:  -1,
 			}},
 		},
@@ -2218,9 +2319,9 @@ func TestHandleCreateRawSStx(t *testing.T) {
 			Inputs: defaultCmdInputs,
 			Amount: defaultCmdAmount,
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DsRah84zx6jdA4nMYboMfLERA5V3KhBr4ru",
+				Addr:       "VsJydgUMKNkBbxHsK3FSbZN5KSjAZA9yLBs",
 				CommitAmt:  100000000,
-				ChangeAddr: "DsfkInvalidcFdQYq3WSKo9vvFs5qxZXbgF",
+				ChangeAddr: "VsfkInvalidcFdQYq3WSKo9vvFs5qxZXbgF",
 				ChangeAmt:  0,
 			}},
 		},
@@ -2233,9 +2334,9 @@ func TestHandleCreateRawSStx(t *testing.T) {
 			Inputs: defaultCmdInputs,
 			Amount: defaultCmdAmount,
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DsRah84zx6jdA4nMYboMfLERA5V3KhBr4ru",
+				Addr:       "VsJydgUMKNkBbxHsK3FSbZN5KSjAZA9yLBs",
 				CommitAmt:  100000000,
-				ChangeAddr: "DkM3EyZ546GghVSkvzb6J47PvGDyntqiDtFgipQhNj78Xm2mUYRpf",
+				ChangeAddr: "VkSwJ85pxSkhK83rLhyVCp4wfgGSUSJw5DwzTUNPMKHY4KphbxzRQ",
 				ChangeAmt:  0,
 			}},
 		},
@@ -2248,9 +2349,9 @@ func TestHandleCreateRawSStx(t *testing.T) {
 			Inputs: defaultCmdInputs,
 			Amount: defaultCmdAmount,
 			COuts: []types.SStxCommitOut{{
-				Addr:       "DsRah84zx6jdA4nMYboMfLERA5V3KhBr4ru",
+				Addr:       "VsJydgUMKNkBbxHsK3FSbZN5KSjAZA9yLBs",
 				CommitAmt:  100000000,
-				ChangeAddr: "DSXcZv4oSRiEoWL2a9aD8sgfptRo1YEXNKj",
+				ChangeAddr: "VSR1WUU9ohioFPqYLb2J56pKzFfvEscYtkj",
 				ChangeAmt:  0,
 			}},
 		},
@@ -2569,7 +2670,7 @@ func TestHandleCreateRawTransaction(t *testing.T) {
 		Vout:   0,
 		Tree:   0,
 	}}
-	defaultCmdAmounts := map[string]float64{"DcurAwesomeAddressmqDctW5wJCW1Cn2MF": 1}
+	defaultCmdAmounts := map[string]float64{"VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz": 1}
 	defaultCmdLockTime := VGLjson.Int64(1)
 	defaultCmdExpiry := VGLjson.Int64(1)
 	testRPCServerHandler(t, []rpcTest{{
@@ -2650,7 +2751,7 @@ func TestHandleCreateRawTransaction(t *testing.T) {
 				Tree:   0,
 			}},
 			Amounts: map[string]float64{
-				"DcurAwesomeAddressmqDctW5wJCW1Cn2MF": (VGLutil.MaxAmount + 1) / 1e8,
+				"VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz": (VGLutil.MaxAmount + 1) / 1e8,
 			},
 			LockTime: defaultCmdLockTime,
 			Expiry:   defaultCmdExpiry,
@@ -2674,7 +2775,7 @@ func TestHandleCreateRawTransaction(t *testing.T) {
 		cmd: &types.CreateRawTransactionCmd{
 			Inputs: defaultCmdInputs,
 			Amounts: map[string]float64{
-				"DkRMCQhwDFTRwW6umM59KEJiMvTPke9X7akJJfbzKocNPDqZMAUEq": 1,
+				"VkXFFZEh7bwSZ8i1B4TYDzGG7LVrSBcjxvSc3KZgJPnmundZPh8r7": 1,
 			},
 			LockTime: defaultCmdLockTime,
 			Expiry:   defaultCmdExpiry,
@@ -2758,7 +2859,7 @@ func TestHandleDecodeRawTransaction(t *testing.T) {
 					ReqSigs: 1,
 					Type:    "scripthash",
 					Addresses: []string{
-						"DcurAwesomeAddressmqDctW5wJCW1Cn2MF",
+						"VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz",
 					},
 				},
 			}},
@@ -2800,7 +2901,7 @@ func TestHandleDecodeRawTransaction(t *testing.T) {
 					ReqSigs: 1,
 					Type:    "scripthash",
 					Addresses: []string{
-						"DcurAwesomeAddressmqDctW5wJCW1Cn2MF",
+						"VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz",
 					},
 				},
 			}},
@@ -2840,8 +2941,8 @@ func TestHandleDecodeScript(t *testing.T) {
 			"000000000000 OP_EQUALVERIFY OP_CHECKSIG",
 		ReqSigs:   1,
 		Type:      "stakesubmission-pubkeyhash",
-		Addresses: []string{"DsQxuVRvS4eaJ42dhQEsCXauMWjvopWgrVg"},
-		P2sh:      "DcaBW1ecMLBzXSS9Q8YRV3aBc5qQeaA1WPo",
+		Addresses: []string{"VsJMr3qGoLf8jwY9Tqgx8kiZWsz43FYRRkt"},
+		P2sh:      "VcTaSa3xicCYyKwfAZzWRGhqmT5Xswh4oph",
 	}
 	aHex := "0A"
 	aHexRes := types.DecodeScriptResult{
@@ -2849,7 +2950,7 @@ func TestHandleDecodeScript(t *testing.T) {
 		ReqSigs:   0,
 		Type:      "nonstandard",
 		Addresses: []string{},
-		P2sh:      "DcbuYCoW1nJZhFf1ZyGXjoPL6D3ezNwwWjj",
+		P2sh:      "VcVJUmCrP4K899AXLQicg2WzFaHnDkn4B8N",
 	}
 	// This is a 2 of 2 multisig script.
 	multiSig := "5221030000000000000000000000000000000000000000000000000" +
@@ -2861,9 +2962,9 @@ func TestHandleDecodeScript(t *testing.T) {
 			"000000000000000000000000000002 2 OP_CHECKMULTISIG",
 		ReqSigs: 2,
 		Type:    "multisig",
-		Addresses: []string{"DsdvMfW6wGbGCXSNWidWtfP1tPmnCLNXQyC",
-			"DsSkAQDPhDW3foES4fcfmpkPYYZhnV3R4ws"},
-		P2sh: "DcexHKLpqiM49auD2jbxPH6enwm9u1ZFAo6",
+		Addresses: []string{"VsXKJDuTJYbpeQwtHA5bptWg3m1uRkAh5bZ",
+			"VsL96xck4VWc7gjwq74ki3t3huoq1s6tide"},
+		P2sh: "VcYMDskBCzMcbUQioB43KWEJxK1H8MfAK92",
 	}
 	// This is a pay to script hash script.
 	p2sh := "a914000000000000000000000000000000000000000087"
@@ -2872,7 +2973,7 @@ func TestHandleDecodeScript(t *testing.T) {
 			"OP_EQUAL",
 		ReqSigs:   1,
 		Type:      "scripthash",
-		Addresses: []string{"DcXTb4QtmnyRsnzUVViYQawqFE5PuYTdX2C"},
+		Addresses: []string{"VcQrXcpF94yzKgVzFwAdLp5VQbKX8u38jvs"},
 	}
 	testRPCServerHandler(t, []rpcTest{{
 		name:    "handleDecodeScript: ok no version",
@@ -3115,7 +3216,7 @@ func TestHandleEstimateStakeDiff(t *testing.T) {
 func TestHandleExistsAddress(t *testing.T) {
 	t.Parallel()
 
-	validAddr := "DcurAwesomeAddressmqDctW5wJCW1Cn2MF"
+	validAddr := "VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz"
 	testRPCServerHandler(t, []rpcTest{{
 		name:    "handleExistsAddress: ok, index is synced",
 		handler: handleExistsAddress,
@@ -3213,7 +3314,7 @@ func TestHandleExistsAddress(t *testing.T) {
 func TestHandleExistsAddresses(t *testing.T) {
 	t.Parallel()
 
-	validAddr := "DcurAwesomeAddressmqDctW5wJCW1Cn2MF"
+	validAddr := "VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz"
 	validAddrs := []string{validAddr, validAddr, validAddr}
 	existsSlice := []bool{false, true, true}
 	// existsSlice as a bitset is 110 binary which is 6 in hex.
@@ -3628,6 +3729,253 @@ func TestHandleGetAddedNodeInfo(t *testing.T) {
 	}})
 }
 
+func TestHandleGetAddressBalance(t *testing.T) {
+	t.Parallel()
+
+	validAddr := "VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz"
+	addrIndex := func() *testAddrIndexer {
+		idx := defaultMockAddrIndexer()
+		idx.utxos = []*indexers.AddrUtxo{{Amount: 100000000}, {Amount: 50000000}}
+		return idx
+	}()
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetAddressBalance: ok",
+		handler: handleGetAddressBalance,
+		cmd: &types.GetAddressBalanceCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: addrIndex,
+		result: &types.GetAddressBalanceResult{
+			Balance:   1.5,
+			UtxoCount: 2,
+		},
+	}, {
+		name:    "handleGetAddressBalance: address indexing not enabled",
+		handler: handleGetAddressBalance,
+		cmd: &types.GetAddressBalanceCmd{
+			Address: validAddr,
+		},
+		setAddrIndexerNil: true,
+		wantErr:           true,
+		errCode:           VGLjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetAddressBalance: bad address",
+		handler: handleGetAddressBalance,
+		cmd: &types.GetAddressBalanceCmd{
+			Address: "bad address",
+		},
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleGetAddressBalance: unable to fetch index tip",
+		handler: handleGetAddressBalance,
+		cmd: &types.GetAddressBalanceCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.tipErr = errors.New("unable to fetch index tip")
+			return idx
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetAddressBalance: index is not synced",
+		handler: handleGetAddressBalance,
+		cmd: &types.GetAddressBalanceCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			bestHeight := int64(block432100.Header.Height)
+			idx := defaultMockAddrIndexer()
+			idx.tipHeight = bestHeight - 6
+			return idx
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetAddressBalance: unable to load balance",
+		handler: handleGetAddressBalance,
+		cmd: &types.GetAddressBalanceCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.utxosErr = errors.New("unable to load balance")
+			return idx
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInternal.Code,
+	}})
+}
+
+func TestHandleGetAddressTxIDs(t *testing.T) {
+	t.Parallel()
+
+	validAddr := "VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz"
+	txHex := hexFromFile("tx432098-11.hex")
+	var tx wire.MsgTx
+	err := tx.FromBytes(hexToBytes(txHex))
+	if err != nil {
+		t.Fatalf("unable to create tx from bytes: %v", err)
+	}
+	minedEntry := indexers.TxIndexEntry{
+		BlockRegion: database.BlockRegion{
+			Hash: mustParseHash("00000000000000001fc4c4c7a3f2ec6d552dda16a3a928f27bd6" +
+				"bd16d8f1e9b3"),
+			Offset: 52508,
+			Len:    453,
+		},
+		BlockIndex: 11,
+	}
+	db := func() *testDB {
+		db := defaultMockDB()
+		db.viewTx = &testDatabaseTx{
+			fetchBlockRegions: func(regions []database.BlockRegion) ([][]byte, error) {
+				txns := make([][]byte, 0, len(regions))
+				for range regions {
+					txns = append(txns, hexToBytes(txHex))
+				}
+				return txns, nil
+			},
+		}
+		return db
+	}()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetAddressTxIDs: ok, mined",
+		handler: handleGetAddressTxIDs,
+		cmd: &types.GetAddressTxIDsCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.entries = []indexers.TxIndexEntry{minedEntry}
+			return idx
+		}(),
+		mockDB: db,
+		result: []string{tx.TxHash().String()},
+	}, {
+		name:    "handleGetAddressTxIDs: ok, unconfirmed",
+		handler: handleGetAddressTxIDs,
+		cmd: &types.GetAddressTxIDsCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.unconfirmedTxns = []*VGLutil.Tx{VGLutil.NewTx(&tx)}
+			return idx
+		}(),
+		mockDB: db,
+		result: []string{tx.TxHash().String()},
+	}, {
+		name:    "handleGetAddressTxIDs: ok, skip all",
+		handler: handleGetAddressTxIDs,
+		cmd: &types.GetAddressTxIDsCmd{
+			Address: validAddr,
+			Skip:    VGLjson.Int(2),
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.entries = []indexers.TxIndexEntry{minedEntry}
+			idx.unconfirmedTxns = []*VGLutil.Tx{VGLutil.NewTx(&tx)}
+			return idx
+		}(),
+		mockDB: db,
+		result: []string{},
+	}, {
+		name:    "handleGetAddressTxIDs: address indexing not enabled",
+		handler: handleGetAddressTxIDs,
+		cmd: &types.GetAddressTxIDsCmd{
+			Address: validAddr,
+		},
+		setAddrIndexerNil: true,
+		wantErr:           true,
+		errCode:           VGLjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetAddressTxIDs: unable to load entries",
+		handler: handleGetAddressTxIDs,
+		cmd: &types.GetAddressTxIDsCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.entriesErr = errors.New("unable to load entries")
+			return idx
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInternal.Code,
+	}})
+}
+
+func TestHandleGetAddressUtxos(t *testing.T) {
+	t.Parallel()
+
+	validAddr := "VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz"
+	utxo := &indexers.AddrUtxo{
+		OutPoint: wire.OutPoint{
+			Hash: *mustParseHash("4b8d0d4b1b4a6d5a9c1f0e3c2b8a9d7e6f5c4b3a2918" +
+				"07f6e5d4c3b2a1908f7e"),
+			Index: 1,
+			Tree:  wire.TxTreeRegular,
+		},
+		Amount:        100000000,
+		Height:        432100,
+		ScriptVersion: 0,
+		PkScript:      hexToBytes("76a914000000000000000000000000000000000000000088ac"),
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetAddressUtxos: ok",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.utxos = []*indexers.AddrUtxo{utxo}
+			return idx
+		}(),
+		result: []types.GetAddressUtxosResult{{
+			TxID:          utxo.OutPoint.Hash.String(),
+			Vout:          1,
+			Tree:          wire.TxTreeRegular,
+			Amount:        1,
+			Height:        432100,
+			ScriptVersion: 0,
+			ScriptPubKey:  "76a914000000000000000000000000000000000000000088ac",
+		}},
+	}, {
+		name:    "handleGetAddressUtxos: ok, no outputs",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: validAddr,
+		},
+		result: []types.GetAddressUtxosResult{},
+	}, {
+		name:    "handleGetAddressUtxos: address indexing not enabled",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: validAddr,
+		},
+		setAddrIndexerNil: true,
+		wantErr:           true,
+		errCode:           VGLjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetAddressUtxos: unable to load outputs",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.utxosErr = errors.New("unable to load outputs")
+			return idx
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleGetBestBlock(t *testing.T) {
 	t.Parallel()
 
@@ -4004,6 +4352,21 @@ func TestHandleGetBlock(t *testing.T) {
 		}(),
 		wantErr: true,
 		errCode: VGLjson.ErrRPCBlockNotFound,
+	}, {
+		name:    "handleGetBlock: block data pruned",
+		handler: handleGetBlock,
+		cmd: &types.GetBlockCmd{
+			Hash:      blkHashString,
+			Verbose:   VGLjson.Bool(false),
+			VerboseTx: VGLjson.Bool(false),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockByHashErr = database.ErrBlockPruned
+			return chain
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCMisc,
 	}, {
 		name:    "handleGetBlock: could not fetch chain work",
 		handler: handleGetBlock,
@@ -4370,6 +4733,193 @@ func TestHandleGetCFilterV2(t *testing.T) {
 	}})
 }
 
+func TestHandleGetBlockTemplate(t *testing.T) {
+	t.Parallel()
+
+	// Create a mock block template based on the header of the existing test
+	// block that consists of a coinbase, a transaction, and a second
+	// transaction that spends an output of the first one.
+	coinbase := wire.NewMsgTx()
+	coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{},
+		wire.MaxPrevOutIndex, wire.TxTreeRegular), 0, []byte{0x51, 0x51}))
+	coinbase.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	tx1 := wire.NewMsgTx()
+	tx1.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0,
+		wire.TxTreeRegular), 5000, nil))
+	tx1.AddTxOut(wire.NewTxOut(4900, []byte{0x51}))
+	tx1Hash := tx1.TxHash()
+	tx2 := wire.NewMsgTx()
+	tx2.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&tx1Hash, 0,
+		wire.TxTreeRegular), 4900, nil))
+	tx2.AddTxOut(wire.NewTxOut(4700, []byte{0x51}))
+	tmplBlock := wire.MsgBlock{
+		Header:       block432100.Header,
+		Transactions: []*wire.MsgTx{coinbase, tx1, tx2},
+	}
+	template := &mining.BlockTemplate{
+		Block:       &tmplBlock,
+		Fees:        []int64{-300, 100, 200},
+		SigOpCounts: []int64{1, 2, 3},
+		Height:      int64(tmplBlock.Header.Height),
+	}
+	templater := func() *testBlockTemplater {
+		templater := defaultMockBlockTemplater()
+		templater.currTemplate = template
+		return templater
+	}()
+
+	// Create the expected result for the template.
+	mustTxHex := func(tx *wire.MsgTx) string {
+		txBytes, err := tx.Bytes()
+		if err != nil {
+			t.Fatalf("unexpected serialize error: %v", err)
+		}
+		return hex.EncodeToString(txBytes)
+	}
+	header := &tmplBlock.Header
+	headerBytes, err := header.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected serialize error: %v", err)
+	}
+	height := int64(header.Height)
+	subsidyCache := standalone.NewSubsidyCache(defaultChainParams)
+	powSubsidy := subsidyCache.CalcWorkSubsidyV3(height, 0, standalone.SSVOriginal)
+	devSubsidy := subsidyCache.CalcTreasurySubsidy(height, 0, true)
+	epoch := kawpow.EpochForHeight(uint64(header.Height))
+	seedHash := kawpow.SeedHash(epoch)
+	kawpowHeaderHash := header.KawPowHeaderHash()
+	longPollID := templateLongPollID(template)
+	templateResult := &types.GetBlockTemplateResult{
+		Header:       hex.EncodeToString(headerBytes),
+		Version:      header.Version,
+		PreviousHash: header.PrevBlock.String(),
+		Height:       height,
+		CurTime:      header.Timestamp.Unix(),
+		Bits:         strconv.FormatInt(int64(header.Bits), 16),
+		SizeLimit:    393216,
+		CoinbaseTxn: &types.GetBlockTemplateResultTx{
+			Data:    mustTxHex(coinbase),
+			Hash:    coinbase.TxHash().String(),
+			Depends: []int64{},
+			SigOps:  1,
+			TxType:  "regular",
+		},
+		Transactions: []types.GetBlockTemplateResultTx{{
+			Data:    mustTxHex(tx1),
+			Hash:    tx1Hash.String(),
+			Depends: []int64{},
+			Fee:     100,
+			SigOps:  2,
+			TxType:  "regular",
+		}, {
+			Data:    mustTxHex(tx2),
+			Hash:    tx2.TxHash().String(),
+			Depends: []int64{1},
+			Fee:     200,
+			SigOps:  3,
+			TxType:  "regular",
+		}},
+		STransactions: []types.GetBlockTemplateResultTx{},
+		StakeVersion:  header.StakeVersion,
+		SBits:         header.SBits,
+		CoinbaseValue: powSubsidy + 300,
+		Fees:          300,
+		Subsidy: types.GetBlockSubsidyResult{
+			Developer: devSubsidy,
+			PoW:       powSubsidy,
+			Total:     devSubsidy + powSubsidy,
+		},
+		KawPoWEpoch:      epoch,
+		KawPoWSeedHash:   hex.EncodeToString(seedHash[:]),
+		KawPoWHeaderHash: hex.EncodeToString(kawpowHeaderHash[:]),
+		Capabilities:     []string{"longpoll"},
+		LongPollID:       longPollID,
+		Target:           fmt.Sprintf("%064x", standalone.CompactToBig(header.Bits)),
+		MinTime:          time.Time{}.Unix() + 1,
+		MaxTime:          header.Timestamp.Unix() + blockchain.MaxTimeOffsetSeconds,
+		Mutable: []string{"time", "transactions/add", "prevblock",
+			"coinbase/append"},
+	}
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:               "handleGetBlockTemplate: ok",
+		handler:            handleGetBlockTemplate,
+		cmd:                &types.GetBlockTemplateCmd{},
+		mockMiningState:    defaultMockMiningState(),
+		mockBlockTemplater: templater,
+		result:             templateResult,
+	}, {
+		name:    "handleGetBlockTemplate: stale long poll id",
+		handler: handleGetBlockTemplate,
+		cmd: &types.GetBlockTemplateCmd{
+			Request: &types.TemplateRequest{
+				Mode:       "template",
+				LongPollID: "stale",
+			},
+		},
+		mockMiningState:    defaultMockMiningState(),
+		mockBlockTemplater: templater,
+		result:             templateResult,
+	}, {
+		name:                 "handleGetBlockTemplate: node not configured for mining",
+		handler:              handleGetBlockTemplate,
+		cmd:                  &types.GetBlockTemplateCmd{},
+		mockMiningState:      defaultMockMiningState(),
+		setBlockTemplaterNil: true,
+		wantErr:              true,
+		errCode:              VGLjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetBlockTemplate: no mining address provided",
+		handler: handleGetBlockTemplate,
+		cmd:     &types.GetBlockTemplateCmd{},
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInternal.Code,
+	}, {
+		name:            "handleGetBlockTemplate: chain is syncing",
+		handler:         handleGetBlockTemplate,
+		cmd:             &types.GetBlockTemplateCmd{},
+		mockMiningState: defaultMockMiningState(),
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.bestSnapshot = &blockchain.BestState{
+				Height: 100,
+			}
+			chain.isCurrent = false
+			return chain
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCClientInInitialDownload,
+	}, {
+		name:            "handleGetBlockTemplate: unable to retrieve template",
+		handler:         handleGetBlockTemplate,
+		cmd:             &types.GetBlockTemplateCmd{},
+		mockMiningState: defaultMockMiningState(),
+		mockBlockTemplater: func() *testBlockTemplater {
+			templater := defaultMockBlockTemplater()
+			templater.currTemplateErr = errors.New("unable to retrieve template")
+			return templater
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCMisc,
+	}, {
+		name:    "handleGetBlockTemplate: invalid mode",
+		handler: handleGetBlockTemplate,
+		cmd: &types.GetBlockTemplateCmd{
+			Request: &types.TemplateRequest{Mode: "invalid"},
+		},
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetBlockTemplate: proposal unsupported",
+		handler: handleGetBlockTemplate,
+		cmd: &types.GetBlockTemplateCmd{
+			Request: &types.TemplateRequest{Mode: "proposal", Data: "00"},
+		},
+		wantErr: true,
+		errCode: VGLjson.ErrRPCUnimplemented,
+	}})
+}
+
 func TestHandleGetChainTips(t *testing.T) {
 	t.Parallel()
 
@@ -4561,8 +5111,9 @@ func TestHandleGetMempoolInfo(t *testing.T) {
 		}(),
 		cmd: &types.GetMempoolInfoCmd{},
 		result: &types.GetMempoolInfoResult{
-			Size:  2,
-			Bytes: 627,
+			Size:          2,
+			Bytes:         627,
+			MempoolMinFee: 0.0001,
 		},
 	}})
 }
@@ -5647,11 +6198,11 @@ func TestHandleValidateAddress(t *testing.T) {
 		name:    "handleValidateAddress: ok",
 		handler: handleValidateAddress,
 		cmd: &types.ValidateAddressCmd{
-			Address: "DcdacYDf5SUH5dYyDxufRngdiaVhi6n83ka",
+			Address: "VcWyZ6d1SiUqXX4UzQMkN1pHswjpwZHohyR",
 		},
 		result: types.ValidateAddressChainResult{
 			IsValid: true,
-			Address: "DcdacYDf5SUH5dYyDxufRngdiaVhi6n83ka",
+			Address: "VcWyZ6d1SiUqXX4UzQMkN1pHswjpwZHohyR",
 		},
 	}, {
 		name:    "handleValidateAddress: invalid address",
@@ -6407,7 +6958,7 @@ func TestHandleTSpendVotes(t *testing.T) {
 func TestTicketsForAddress(t *testing.T) {
 	t.Parallel()
 
-	addr := "DsRM6qwzT3r85evKvDBJBviTgYcaLKL4ipD"
+	addr := "VsJk3QMLpKrgXYRqgedP89r7qurhZjAxcvn"
 	hashStrings := []string{"822e537612dfd03b0dd2c2610083a93fde655f11c32adc7511d75e460abf4283",
 		"8319c4e0623f0f2c73444047eff595493aa87fe195a192c086204c06a758cdee",
 		"c870cd4c47be2ff342997b6d48a56f55b44932b4925098943c58d01b0ed5e725"}
@@ -6841,11 +7392,11 @@ func TestHandleVerifyMessage(t *testing.T) {
 
 	// private key : 0x0000000000000000000000000000000000000000000000000000000000000001
 	msg := "test message"
-	p2shAddr := "DcXTb4QtmnyRsnzUVViYQawqFE5PuYTdX2C"
-	compressedPKHAddr := "DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"
+	p2shAddr := "VcQrXcpF94yzKgVzFwAdLp5VQbKX8u38jvs"
+	compressedPKHAddr := "Vsf1V3zjNen97unZvsTCQh4Rfu1PsYshD3E"
 	compressedCompactSig := "H18ier4CIfSBOk0FKPjO4mggno0ES1w2P+41GpJnnyiSRW" +
 		"dE2n02YwE29Sw0n2ALT3M1Q1+GQW7moKqsem1COF8="
-	uncompressedPKHAddr := "DsbnCMAYV13buumdjHuwiJeJWZWvgjZRTbE"
+	uncompressedPKHAddr := "VsVB8uZtrH4AMoH9VjN2eXmxfvm3v9Z1zLV"
 	uncompressedCompactSig := "G18ier4CIfSBOk0FKPjO4mggno0ES1w2P+41GpJnnyiS" +
 		"RWdE2n02YwE29Sw0n2ALT3M1Q1+GQW7moKqsem1COF8="
 	malformedCompactSig := "MEUCIQD8wKh2jPvO/PLK3Xz7D7GO0I3S4q6EvvGkdKPTUbJ" +
@@ -6922,6 +7473,152 @@ func TestHandleVerifyMessage(t *testing.T) {
 	}})
 }
 
+func TestHandleSearchRawTransactions(t *testing.T) {
+	t.Parallel()
+
+	validAddr := "VcoF7W4EB3ej5XNAeKDv9r2AFJYKjM54QLz"
+	txHex := hexFromFile("tx432098-11.hex")
+	var tx wire.MsgTx
+	err := tx.FromBytes(hexToBytes(txHex))
+	if err != nil {
+		t.Fatalf("unable to create tx from bytes: %v", err)
+	}
+	nonVerbose := 0
+	addrIndex := func() *testAddrIndexer {
+		idx := defaultMockAddrIndexer()
+		idx.entries = []indexers.TxIndexEntry{{
+			BlockRegion: database.BlockRegion{
+				Hash: mustParseHash("00000000000000001fc4c4c7a3f2ec6d552dda16a3a9" +
+					"28f27bd6bd16d8f1e9b3"),
+				Offset: 52508,
+				Len:    453,
+			},
+			BlockIndex: 11,
+		}}
+		return idx
+	}()
+	db := func() *testDB {
+		db := defaultMockDB()
+		db.viewTx = &testDatabaseTx{
+			fetchBlockRegions: func(regions []database.BlockRegion) ([][]byte, error) {
+				txns := make([][]byte, 0, len(regions))
+				for range regions {
+					txns = append(txns, hexToBytes(txHex))
+				}
+				return txns, nil
+			},
+		}
+		return db
+	}()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleSearchRawTransactions: ok, not verbose, mined",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+			Verbose: &nonVerbose,
+		},
+		mockAddrIndexer: addrIndex,
+		mockDB:          db,
+		result:          []string{txHex},
+	}, {
+		name:    "handleSearchRawTransactions: ok, not verbose, unconfirmed first",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+			Verbose: &nonVerbose,
+			Reverse: VGLjson.Bool(true),
+			Count:   VGLjson.Int(1),
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.entries = addrIndex.entries
+			idx.unconfirmedTxns = []*VGLutil.Tx{VGLutil.NewTx(&tx)}
+			return idx
+		}(),
+		mockDB: func() *testDB {
+			db := defaultMockDB()
+			db.viewTx = &testDatabaseTx{
+				fetchBlockRegions: func(regions []database.BlockRegion) ([][]byte, error) {
+					if len(regions) != 0 {
+						return nil, errors.New("unexpected database access")
+					}
+					return nil, nil
+				},
+			}
+			return db
+		}(),
+		result: []string{txHex},
+	}, {
+		name:    "handleSearchRawTransactions: ok, zero count",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+			Count:   VGLjson.Int(0),
+		},
+		mockAddrIndexer: addrIndex,
+		result:          nil,
+	}, {
+		name:    "handleSearchRawTransactions: address indexing not enabled",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+		},
+		setAddrIndexerNil: true,
+		wantErr:           true,
+		errCode:           VGLjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: bad address",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: "bad address",
+		},
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleSearchRawTransactions: index is not synced after syncWait",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.tipHash = &zeroHash
+			idx.signalOnWait = false
+			return idx
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: no info about address",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+		},
+		mockDB:  db,
+		wantErr: true,
+		errCode: VGLjson.ErrRPCNoTxInfo,
+	}, {
+		name:    "handleSearchRawTransactions: unable to fetch block regions",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: addrIndex,
+		mockDB: func() *testDB {
+			db := defaultMockDB()
+			db.viewTx = &testDatabaseTx{
+				fetchBlockRegions: func(regions []database.BlockRegion) ([][]byte, error) {
+					return nil, errors.New("unable to fetch block regions")
+				},
+			}
+			return db
+		}(),
+		wantErr: true,
+		errCode: VGLjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleSendRawTransaction(t *testing.T) {
 	t.Parallel()
 
@@ -7552,7 +8249,7 @@ func TestHandleGetRawTransaction(t *testing.T) {
 				Hex:       "76a914762432e9619f5ddaf122ac663684152ffe9eb0ec88ac",
 				ReqSigs:   1,
 				Type:      "pubkeyhash",
-				Addresses: []string{"DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3"},
+				Addresses: []string{"VsV8X9cPPhuzTX9gVUMYfYnuiU2aE1sQte2"},
 			}}, {
 			Value:   144.27441143,
 			N:       1,
@@ -7563,7 +8260,7 @@ func TestHandleGetRawTransaction(t *testing.T) {
 				Hex:       "76a914762432e9619f5ddaf122ac663684152ffe9eb0ec88ac",
 				ReqSigs:   1,
 				Type:      "pubkeyhash",
-				Addresses: []string{"DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3"},
+				Addresses: []string{"VsV8X9cPPhuzTX9gVUMYfYnuiU2aE1sQte2"},
 			}}, {
 			Value:   68.00443066,
 			N:       2,
@@ -7574,7 +8271,7 @@ func TestHandleGetRawTransaction(t *testing.T) {
 				Hex:       "76a914bc3c059489f447afbf542ff33432adb9ded7f8e988ac",
 				ReqSigs:   1,
 				Type:      "pubkeyhash",
-				Addresses: []string{"Dsi8CRt85xYyempXs7ZPL1rBxvDdAGZmgsg"},
+				Addresses: []string{"VsbX8zHUTEZY6fL3dZ1UGEyr8HTkPiFDVt8"},
 			},
 		}},
 		BlockHash: "00000000000000001fc4c4c7a3f2ec6d552dda16a3a928f27b" +
@@ -8198,6 +8895,12 @@ func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 			if test.setTxIndexerNil {
 				rpcserverConfig.TxIndexer = nil
 			}
+			if test.mockAddrIndexer != nil {
+				rpcserverConfig.AddrIndexer = test.mockAddrIndexer
+			}
+			if test.setAddrIndexerNil {
+				rpcserverConfig.AddrIndexer = nil
+			}
 			if test.mockDB != nil {
 				rpcserverConfig.DB = test.mockDB
 			}
@@ -212,6 +212,35 @@ var helpDescsEnUS = map[string]string{
 	"getaddednodeinfo--condition1": "dns=true",
 	"getaddednodeinfo--result0":    "List of added peers",
 
+	// GetAddressBalanceCmd help.
+	"getaddressbalance--synopsis": "Returns the total amount of all unspent outputs in the main chain that pay to the provided address (requires --addrindex).",
+	"getaddressbalance-address":   "The address to query the balance for",
+
+	// GetAddressBalanceResult help.
+	"getaddressbalanceresult-balance":   "The total amount of the unspent outputs that pay to the address in VGL",
+	"getaddressbalanceresult-utxocount": "The number of unspent outputs that pay to the address",
+
+	// GetAddressTxIDsCmd help.
+	"getaddresstxids--synopsis": "Returns the hashes of all transactions in the main chain and memory pool that involve the provided address (requires --addrindex).",
+	"getaddresstxids-address":   "The address to query the transactions for",
+	"getaddresstxids-skip":      "The number of leading transactions to leave out of the final response",
+	"getaddresstxids-count":     "The maximum number of transactions to return",
+	"getaddresstxids-reverse":   "Specifies that the transactions should be returned in reverse chronological order",
+	"getaddresstxids--result0":  "The hashes of the transactions that involve the address",
+
+	// GetAddressUtxosCmd help.
+	"getaddressutxos--synopsis": "Returns all unspent outputs in the main chain that pay to the provided address (requires --addrindex).",
+	"getaddressutxos-address":   "The address to query the unspent outputs for",
+
+	// GetAddressUtxosResult help.
+	"getaddressutxosresult-txid":          "The hash of the transaction that contains the output",
+	"getaddressutxosresult-vout":          "The index of the output",
+	"getaddressutxosresult-tree":          "The tree of the transaction that contains the output",
+	"getaddressutxosresult-amount":        "The amount of the output in VGL",
+	"getaddressutxosresult-height":        "The height of the block that contains the output",
+	"getaddressutxosresult-scriptversion": "The version of the public key script",
+	"getaddressutxosresult-scriptPubKey":  "The hex-encoded public key script of the output",
+
 	// GetBestBlockResult help.
 	"getbestblockresult-hash":   "Hex-encoded bytes of the best block hash",
 	"getbestblockresult-height": "Height of the best block",
@@ -360,6 +389,51 @@ var helpDescsEnUS = map[string]string{
 	"getblocksubsidyresult-pow":       "The Proof-of-Work subsidy",
 	"getblocksubsidyresult-total":     "The total subsidy",
 
+	// TemplateRequest help.
+	"templaterequest-mode":         "This is 'template' or omitted since block proposals are not supported",
+	"templaterequest-capabilities": "List of capabilities",
+	"templaterequest-longpollid":   "The long poll ID of a job to monitor for expiration; required and valid only for long poll requests",
+	"templaterequest-data":         "Hex-encoded block data for block proposals, which are not supported",
+
+	// GetBlockTemplateCmd help.
+	"getblocktemplate--synopsis": "Returns a block template for external mining purposes per BIP 0022.",
+	"getblocktemplate-request":   "Request object which controls the mode and several parameters",
+
+	// GetBlockTemplateResultTx help.
+	"getblocktemplateresulttx-data":    "Hex-encoded transaction data (byte-for-byte)",
+	"getblocktemplateresulttx-hash":    "Hex-encoded transaction hash (little endian if treated as a 256-bit number)",
+	"getblocktemplateresulttx-depends": "Other transactions in the same tree before this one (by 1-based index in the 'transactions' or 'stransactions' list) that must be present in the final block if this one is",
+	"getblocktemplateresulttx-fee":     "Difference in value between transaction inputs and outputs (in atoms)",
+	"getblocktemplateresulttx-sigops":  "Total number of signature operations as counted for purposes of block limits",
+	"getblocktemplateresulttx-txtype":  "The type of the transaction (regular, ticket, vote, revocation, tadd, tspend, or treasurybase)",
+
+	// GetBlockTemplateResult help.
+	"getblocktemplateresult-header":            "Hex-encoded serialized block header of the template with the current time applied",
+	"getblocktemplateresult-version":           "The block version",
+	"getblocktemplateresult-previousblockhash": "Hex-encoded big-endian hash of the previous block",
+	"getblocktemplateresult-height":            "Height of the block to be solved",
+	"getblocktemplateresult-curtime":           "Current time as seen by the server (recommended for block time); must fall within mintime/maxtime rules",
+	"getblocktemplateresult-bits":              "Hex-encoded compressed difficulty",
+	"getblocktemplateresult-sizelimit":         "Number of bytes allowed in blocks",
+	"getblocktemplateresult-coinbasetxn":       "Information about the coinbase transaction",
+	"getblocktemplateresult-transactions":      "Array of regular transactions excluding the coinbase",
+	"getblocktemplateresult-stransactions":     "Array of stake transactions including votes, tickets, revocations, and treasury transactions",
+	"getblocktemplateresult-stakeversion":      "The stake version of the block",
+	"getblocktemplateresult-sbits":             "The stake difficulty of the block in atoms",
+	"getblocktemplateresult-voters":            "The number of votes included in the template",
+	"getblocktemplateresult-coinbasevalue":     "Total amount available for the proof-of-work coinbase outputs in atoms, which is the proof-of-work subsidy plus the fees of the transactions",
+	"getblocktemplateresult-fees":              "Total fees paid by the transactions in the template in atoms",
+	"getblocktemplateresult-subsidy":           "The subsidy split into its proof-of-work, proof-of-stake, and treasury portions",
+	"getblocktemplateresult-kawpowepoch":       "The KawPoW epoch of the block",
+	"getblocktemplateresult-kawpowseedhash":    "Hex-encoded KawPoW seed hash for the epoch of the block",
+	"getblocktemplateresult-kawpowheaderhash":  "Hex-encoded KawPoW header hash that is used as the input to the proof of work for the returned header",
+	"getblocktemplateresult-capabilities":      "List of server capabilities, which is 'longpoll'",
+	"getblocktemplateresult-longpollid":        "Identifier for long poll request which allows monitoring for expiration",
+	"getblocktemplateresult-target":            "Hex-encoded big-endian target",
+	"getblocktemplateresult-mintime":           "Minimum allowed time for the block",
+	"getblocktemplateresult-maxtime":           "Maximum allowed time for the block",
+	"getblocktemplateresult-mutable":           "List of mutations the server explicitly allows",
+
 	// GetCFilterV2Cmd help.
 	"getcfilterv2--synopsis": "Returns the version 2 block filter for the given block along with a proof that can be used to prove the filter is committed to by the block header",
 	"getcfilterv2-blockhash": "The block hash of the filter to retrieve",
@@ -490,8 +564,9 @@ var helpDescsEnUS = map[string]string{
 	"getmempoolinfo--synopsis": "Returns memory pool information",
 
 	// GetMempoolInfoResult help.
-	"getmempoolinforesult-bytes": "Size in bytes of the mempool",
-	"getmempoolinforesult-size":  "Number of transactions in the mempool",
+	"getmempoolinforesult-bytes":         "Size in bytes of the mempool",
+	"getmempoolinforesult-size":          "Number of transactions in the mempool",
+	"getmempoolinforesult-mempoolminfee": "The minimum fee in VGL/kB a transaction must currently pay to be accepted to the mempool, which is raised above the minimum relay fee while the mempool is full",
 
 	// GetMiningInfoResult help.
 	"getmininginforesult-blocks":           "Height of the latest best block",
@@ -714,6 +789,38 @@ var helpDescsEnUS = map[string]string{
 		"Any descendants that are neither themselves marked as having failed validation, nor descendants of another such block, are also made eligibile for best chain selection.",
 	"reconsiderblock-blockhash": "The hash of the block to reconsider",
 
+	// SearchRawTransactionsCmd help.
+	"searchrawtransactions--synopsis": "Returns raw data for transactions involving the passed address.\n" +
+		"Returned transactions are pulled from both the database, and transactions currently in the mempool.\n" +
+		"Transactions pulled from the mempool will have the 'confirmations' field set to 0.\n" +
+		"Usage of this RPC requires the optional --addrindex flag to be activated, otherwise all responses will simply return with an error stating the address index has not yet been built.\n" +
+		"Similarly, until the address index has caught up with the current best height, all requests will return an error response in order to avoid serving stale data.",
+	"searchrawtransactions-address":     "The VGL address to search for",
+	"searchrawtransactions-verbose":     "Specifies the transaction is returned as a JSON object instead of hex-encoded string",
+	"searchrawtransactions--condition0": "verbose=0",
+	"searchrawtransactions--condition1": "verbose=1",
+	"searchrawtransactions-skip":        "The number of leading transactions to leave out of the final response",
+	"searchrawtransactions-count":       "The maximum number of transactions to return",
+	"searchrawtransactions-vinextra":    "Specify that extra data from previous output will be returned in vin",
+	"searchrawtransactions-reverse":     "Specifies that the transactions should be returned in reverse chronological order",
+	"searchrawtransactions-filteraddrs": "Address list.  Only inputs or outputs with matching address will be returned",
+	"searchrawtransactions--result0":    "Hex-encoded serialized transaction",
+
+	// SearchRawTransactionsResult help.
+	"searchrawtransactionsresult-hex":           "Hex-encoded transaction",
+	"searchrawtransactionsresult-txid":          "The hash of the transaction",
+	"searchrawtransactionsresult-version":       "The transaction version",
+	"searchrawtransactionsresult-locktime":      "The transaction lock time",
+	"searchrawtransactionsresult-expiry":        "The transaction expiry",
+	"searchrawtransactionsresult-vin":           "The transaction inputs as JSON objects",
+	"searchrawtransactionsresult-vout":          "The transaction outputs as JSON objects",
+	"searchrawtransactionsresult-blockhash":     "The hash of the block that contains the transaction",
+	"searchrawtransactionsresult-blockheight":   "The height of the block that contains the transaction",
+	"searchrawtransactionsresult-blockindex":    "The index within the array of transactions contained by the block",
+	"searchrawtransactionsresult-confirmations": "Number of confirmations of the block",
+	"searchrawtransactionsresult-time":          "Transaction time in seconds since 1 Jan 1970 GMT",
+	"searchrawtransactionsresult-blocktime":     "Block time in seconds since the 1 Jan 1970 GMT",
+
 	// SendRawTransactionCmd help.
 	"sendrawtransaction--synopsis":     "Submits the serialized, hex-encoded transaction to the local peer and relays it to the network.",
 	"sendrawtransaction-hextx":         "Serialized, hex-encoded signed transaction",
@@ -969,6 +1076,9 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"existsmempooltxs":      {(*string)(nil)},
 	"generate":              {(*[]string)(nil)},
 	"getaddednodeinfo":      {(*[]string)(nil), (*[]types.GetAddedNodeInfoResult)(nil)},
+	"getaddressbalance":     {(*types.GetAddressBalanceResult)(nil)},
+	"getaddresstxids":       {(*[]string)(nil)},
+	"getaddressutxos":       {(*[]types.GetAddressUtxosResult)(nil)},
 	"getbestblock":          {(*types.GetBestBlockResult)(nil)},
 	"getbestblockhash":      {(*string)(nil)},
 	"getblock":              {(*string)(nil), (*types.GetBlockVerboseResult)(nil)},
@@ -977,6 +1087,7 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"getblockhash":          {(*string)(nil)},
 	"getblockheader":        {(*string)(nil), (*types.GetBlockHeaderVerboseResult)(nil)},
 	"getblocksubsidy":       {(*types.GetBlockSubsidyResult)(nil)},
+	"getblocktemplate":      {(*types.GetBlockTemplateResult)(nil)},
 	"getcfilterv2":          {(*types.GetCFilterV2Result)(nil)},
 	"getchaintips":          {(*[]types.GetChainTipsResult)(nil)},
 	"getcoinsupply":         {(*int64)(nil)},
@@ -1014,6 +1125,7 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"ping":                  nil,
 	"reconsiderblock":       nil,
 	"regentemplate":         nil,
+	"searchrawtransactions": {(*[]string)(nil), (*[]types.SearchRawTransactionsResult)(nil)},
 	"sendrawmixmessage":     nil,
 	"sendrawtransaction":    {(*string)(nil)},
 	"setgenerate":           nil,
@@ -24,6 +24,7 @@ import (
 	"github.com/kdsmith18542/vigil/chaincfg/v3"
 	"github.com/kdsmith18542/vigil/crypto/rand"
 	"github.com/kdsmith18542/vigil/crypto/ripemd160"
+	"github.com/kdsmith18542/vigil/database/v3"
 	"github.com/kdsmith18542/vigil/VGLjson/v4"
 	"github.com/kdsmith18542/vigil/VGLutil/v4"
 	"github.com/kdsmith18542/vigil/internal/blockchain"
@@ -2377,6 +2378,9 @@ func handleRescan(_ context.Context, wsc *wsClient, icmd interface{}) (interface
 	for i := range blockHashes {
 		block, err := bc.BlockByHash(&blockHashes[i])
 		if err != nil {
+			if errors.Is(err, database.ErrBlockPruned) {
+				return nil, rpcBlockPrunedError(blockHashes[i])
+			}
 			return nil, &VGLjson.RPCError{
 				Code:    VGLjson.ErrRPCBlockNotFound,
 				Message: "Failed to fetch block: " + err.Error(),
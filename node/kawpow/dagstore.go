@@ -0,0 +1,421 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// DefaultDAGsOnDisk is the default number of most recent epochs a DAG
+	// store keeps the light cache and full dataset files for.
+	DefaultDAGsOnDisk = 2
+
+	// storeVersion is the version of the on-disk file format.  It is part of
+	// the file names, so files written with other versions are never loaded
+	// and are removed when the store is pruned.
+	storeVersion = 1
+
+	// storeFilePrefix is the prefix of every file written by a DAG store.
+	storeFilePrefix = "kawpow"
+
+	// storeHeaderSize is the size of the header that precedes the data in
+	// every file.  It is a multiple of the word size so the data that follows
+	// is suitably aligned when the file is memory mapped.
+	//
+	// The header consists of:
+	//
+	//   magic (8 bytes) || version (4 bytes) || kind (4 bytes) ||
+	//   epoch (8 bytes) || CRC-32C of data (4 bytes) || reserved (4 bytes)
+	//
+	// All integers are little endian and the data consists of the 32-bit
+	// words of the light cache or full dataset, also in little endian.
+	//
+	// The checksum is written for both kinds of files, but it is only
+	// verified when loading light caches.  See datasetSpotChecks.
+	storeHeaderSize = 32
+
+	// datasetSpotChecks is the number of items of a full dataset file that
+	// are recalculated from the light cache and compared when the file is
+	// loaded.  Checksumming the entire dataset, which is over a gigabyte, on
+	// every load would take a significant amount of time and read all of the
+	// pages of the memory mapped file in up front, whereas a handful of items
+	// spread across the file is enough to detect a file written for another
+	// epoch or with a broken implementation.  A corrupt dataset only results
+	// in miners producing invalid solutions that are rejected by consensus
+	// validation, which only ever uses the light cache.
+	datasetSpotChecks = 64
+
+	// storeWriteChunkWords is the number of words that are encoded at a time
+	// when writing files.
+	storeWriteChunkWords = 1 << 16
+)
+
+// storeMagic identifies files written by a DAG store.
+var storeMagic = [8]byte{'K', 'A', 'W', 'P', 'O', 'W', 'D', 'S'}
+
+// castagnoli is the CRC-32C table used to checksum the data in files.
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// hostLittleEndian is whether the host stores words in little endian, which
+// allows the data of memory mapped files to be used directly.
+var hostLittleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+
+// storeKind identifies the type of data held by a file in a DAG store.
+type storeKind uint32
+
+const (
+	storeKindCache   storeKind = 1
+	storeKindDataset storeKind = 2
+)
+
+// String returns the name of the kind as used in file names.
+func (k storeKind) String() string {
+	switch k {
+	case storeKindCache:
+		return "cache"
+	case storeKindDataset:
+		return "dataset"
+	}
+	return "unknown"
+}
+
+// DAGStore persists generated light caches and full datasets to files in a
+// directory so they are reused across restarts instead of regenerated.  Files
+// are versioned per epoch and validated when loaded, loaded through memory
+// mapping where supported, and pruned once their epoch is sufficiently old.
+//
+// It is safe for concurrent access so long as the same epoch of a given kind
+// is not saved concurrently, which the epoch manager guarantees.
+type DAGStore struct {
+	dir        string
+	keepEpochs uint64
+}
+
+// NewDAGStore returns a DAG store that keeps its files in the provided
+// directory, which is created on demand, and retains the files for the
+// provided number of most recent epochs when pruned.  Values less than one are
+// treated as one.
+func NewDAGStore(dir string, keepEpochs int) *DAGStore {
+	return &DAGStore{
+		dir:        dir,
+		keepEpochs: uint64(max(keepEpochs, 1)),
+	}
+}
+
+// Dir returns the directory the store keeps its files in.
+func (s *DAGStore) Dir() string {
+	return s.dir
+}
+
+// fileName returns the name of the file for the given kind and epoch.  The
+// name also commits to the seed hash so it is easy to identify by anyone
+// familiar with other Ethash-based DAG directories.
+func fileName(kind storeKind, epoch uint64) string {
+	seed := SeedHash(epoch)
+	return fmt.Sprintf("%s-%s-v%d-%d-%x", storeFilePrefix, kind, storeVersion,
+		epoch, seed[:8])
+}
+
+// parseFileName parses the version and epoch from the provided file name.
+// The final return value is false when the name is not for a file written by a
+// DAG store.
+func parseFileName(name string) (uint64, uint64, bool) {
+	parts := strings.Split(name, "-")
+	if len(parts) != 5 || parts[0] != storeFilePrefix {
+		return 0, 0, false
+	}
+	if !strings.HasPrefix(parts[2], "v") {
+		return 0, 0, false
+	}
+	version, err := strconv.ParseUint(parts[2][1:], 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	epoch, err := strconv.ParseUint(parts[3], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return version, epoch, true
+}
+
+// path returns the full path to the file for the given kind and epoch.
+func (s *DAGStore) path(kind storeKind, epoch uint64) string {
+	return filepath.Join(s.dir, fileName(kind, epoch))
+}
+
+// bytesToWords returns the little-endian 32-bit words encoded in the provided
+// bytes.  The returned bool is true when the words share memory with the
+// provided bytes as opposed to being a decoded copy.
+func bytesToWords(b []byte) ([]uint32, bool) {
+	if len(b) == 0 {
+		return nil, false
+	}
+	if hostLittleEndian && uintptr(unsafe.Pointer(&b[0]))%4 == 0 {
+		return unsafe.Slice((*uint32)(unsafe.Pointer(&b[0])), len(b)/4), true
+	}
+	words := make([]uint32, len(b)/4)
+	for i := range words {
+		words[i] = le.Uint32(b[i*4:])
+	}
+	return words, false
+}
+
+// saveWords atomically writes the provided words as the file for the given
+// kind and epoch.  The data is first written to a temporary file in the same
+// directory that is then renamed so a partially written file is never loaded.
+func (s *DAGStore) saveWords(kind storeKind, epoch uint64, words []uint32) (err error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	path := s.path(kind, epoch)
+	f, err := os.CreateTemp(s.dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	// Reserve space for the header since the checksum is not known until all
+	// of the data is written.
+	var header [storeHeaderSize]byte
+	w := bufio.NewWriterSize(f, 1<<20)
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	var checksum uint32
+	chunk := make([]byte, storeWriteChunkWords*4)
+	for len(words) > 0 {
+		n := min(len(words), storeWriteChunkWords)
+		buf := chunk[:n*4]
+		for i, word := range words[:n] {
+			le.PutUint32(buf[i*4:], word)
+		}
+		checksum = crc32.Update(checksum, castagnoli, buf)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		words = words[n:]
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	copy(header[0:8], storeMagic[:])
+	le.PutUint32(header[8:12], storeVersion)
+	le.PutUint32(header[12:16], uint32(kind))
+	le.PutUint64(header[16:24], epoch)
+	le.PutUint32(header[24:28], checksum)
+	if _, err := f.WriteAt(header[:], 0); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// loadWords loads the file for the given kind and epoch which must contain the
+// provided number of words.  The checksum of the data is only verified when
+// requested.  The returned release function must be called once the words are
+// no longer in use.
+//
+// An error that matches fs.ErrNotExist is returned when there is no file for
+// the kind and epoch, while ErrBadStoreFile is returned when the file exists
+// but is not valid.
+func (s *DAGStore) loadWords(kind storeKind, epoch uint64, numWords uint64, verifyChecksum bool) ([]uint32, func(), error) {
+	path := s.path(kind, epoch)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	wantSize := storeHeaderSize + int64(numWords)*4
+	if fi.Size() != wantSize {
+		str := fmt.Sprintf("%s file %s is %d bytes instead of the expected "+
+			"%d bytes", kind, path, fi.Size(), wantSize)
+		return nil, nil, makeError(ErrBadStoreFile, str)
+	}
+
+	b, unmap, err := mapFile(f, wantSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	release := func() {
+		if err := unmap(); err != nil {
+			log.Warnf("Unable to unmap KawPoW %s file %s: %v", kind, path,
+				err)
+		}
+	}
+
+	header, data := b[:storeHeaderSize], b[storeHeaderSize:]
+	var str string
+	switch {
+	case [8]byte(header[0:8]) != storeMagic:
+		str = fmt.Sprintf("%s file %s has an invalid magic number", kind,
+			path)
+	case le.Uint32(header[8:12]) != storeVersion:
+		str = fmt.Sprintf("%s file %s has unsupported version %d", kind,
+			path, le.Uint32(header[8:12]))
+	case storeKind(le.Uint32(header[12:16])) != kind:
+		str = fmt.Sprintf("%s file %s holds a %s", kind, path,
+			storeKind(le.Uint32(header[12:16])))
+	case le.Uint64(header[16:24]) != epoch:
+		str = fmt.Sprintf("%s file %s is for epoch %d instead of %d", kind,
+			path, le.Uint64(header[16:24]), epoch)
+	case verifyChecksum &&
+		crc32.Checksum(data, castagnoli) != le.Uint32(header[24:28]):
+		str = fmt.Sprintf("%s file %s failed its checksum", kind, path)
+	}
+	if str != "" {
+		release()
+		return nil, nil, makeError(ErrBadStoreFile, str)
+	}
+
+	words, shared := bytesToWords(data)
+	if !shared {
+		release()
+		release = func() {}
+	}
+	return words, release, nil
+}
+
+// LoadCache loads the light cache for the given epoch from the store.  The
+// caller holds the only reference to the returned light cache and must release
+// it once it is no longer in use.  An error that matches fs.ErrNotExist is
+// returned when the store does not have the light cache for the epoch.
+func (s *DAGStore) LoadCache(epoch uint64) (*Cache, error) {
+	numItems := cacheNumItems(epoch)
+	data, release, err := s.loadWords(storeKindCache, epoch,
+		numItems*cacheItemWords, true)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cache{
+		epoch:    epoch,
+		numItems: uint32(numItems),
+		data:     data,
+		refs:     newDataRefs(release),
+	}
+	c.generateL1()
+	return c, nil
+}
+
+// SaveCache saves the provided light cache to the store.
+func (s *DAGStore) SaveCache(c *Cache) error {
+	return s.saveWords(storeKindCache, c.epoch, c.data)
+}
+
+// LoadDAG loads the full dataset for the epoch of the provided light cache
+// from the store.  The returned DAG takes its own reference to the light cache
+// and the caller holds the only reference to the DAG, which it must release
+// once the DAG is no longer in use.  An error that matches fs.ErrNotExist is
+// returned when the store does not have the full dataset for the epoch.
+func (s *DAGStore) LoadDAG(cache *Cache) (*DAG, error) {
+	numItems := datasetNumItems(cache.epoch) * (datasetItemBytes / cacheItemBytes)
+	data, release, err := s.loadWords(storeKindDataset, cache.epoch,
+		numItems*cacheItemWords, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recalculate items spread across the dataset from the light cache to
+	// detect corrupt files without reading the entire dataset.
+	h := sha3.NewLegacyKeccak512()
+	var buf [cacheItemBytes]byte
+	var item [cacheItemWords]uint32
+	for i := uint64(0); i < datasetSpotChecks; i++ {
+		index := i * (numItems - 1) / (datasetSpotChecks - 1)
+		cache.datasetItem(h, &buf, uint32(index), item[:])
+		offset := index * cacheItemWords
+		if !slices.Equal(item[:], data[offset:offset+cacheItemWords]) {
+			release()
+			str := fmt.Sprintf("dataset file %s has an invalid item at "+
+				"index %d", s.path(storeKindDataset, cache.epoch), index)
+			return nil, makeError(ErrBadStoreFile, str)
+		}
+	}
+
+	cache.refs.retain()
+	d := &DAG{
+		epoch: cache.epoch,
+		cache: cache,
+		data:  data,
+		refs:  newDataRefs(release),
+	}
+	return d, nil
+}
+
+// SaveDAG saves the full dataset of the provided DAG to the store.  The DAG
+// must have been generated.
+func (s *DAGStore) SaveDAG(d *DAG) error {
+	if d.data == nil {
+		str := fmt.Sprintf("DAG not generated for epoch %d", d.epoch)
+		return makeError(ErrDAGNotGenerated, str)
+	}
+	return s.saveWords(storeKindDataset, d.epoch, d.data)
+}
+
+// Prune removes the files for epochs that are at least the number of epochs
+// the store retains older than the provided current epoch along with any files
+// written with a different version of the file format and any temporary files
+// left behind by interrupted writes.
+func (s *DAGStore) Prune(currentEpoch uint64) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, storeFilePrefix+"-") || entry.IsDir() {
+			continue
+		}
+		stale := strings.Contains(name, ".tmp")
+		if !stale {
+			version, epoch, ok := parseFileName(name)
+			if !ok {
+				continue
+			}
+			stale = version != storeVersion || epoch+s.keepEpochs <= currentEpoch
+		}
+		if !stale {
+			continue
+		}
+		log.Debugf("Removing stale KawPoW file %s", name)
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// TestDAGStoreWords ensures words saved to a DAG store are loaded back intact
+// and that missing, truncated, and corrupted files are detected.
+func TestDAGStoreWords(t *testing.T) {
+	store := NewDAGStore(t.TempDir(), DefaultDAGsOnDisk)
+
+	words := make([]uint32, storeWriteChunkWords+3)
+	for i := range words {
+		words[i] = uint32(i) * 0x9e3779b9
+	}
+
+	_, _, err := store.loadWords(storeKindDataset, 3, uint64(len(words)),
+		true)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("unexpected error loading missing file: %v", err)
+	}
+
+	if err := store.saveWords(storeKindDataset, 3, words); err != nil {
+		t.Fatalf("unexpected error saving words: %v", err)
+	}
+	got, release, err := store.loadWords(storeKindDataset, 3,
+		uint64(len(words)), true)
+	if err != nil {
+		t.Fatalf("unexpected error loading words: %v", err)
+	}
+	if !slices.Equal(got, words) {
+		t.Fatal("loaded words do not match saved words")
+	}
+	release()
+
+	// Loading with a different kind, epoch, or size must fail.
+	_, _, err = store.loadWords(storeKindCache, 3, uint64(len(words)),
+		true)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("unexpected error loading wrong kind: %v", err)
+	}
+	_, _, err = store.loadWords(storeKindDataset, 3, uint64(len(words)-1),
+		true)
+	if !errors.Is(err, ErrBadStoreFile) {
+		t.Fatalf("unexpected error loading with wrong size: %v", err)
+	}
+
+	// Flip a bit in the data and ensure the checksum catches it.
+	path := store.path(storeKindDataset, 3)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading file: %v", err)
+	}
+	b[storeHeaderSize+100] ^= 0x01
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatalf("unexpected error writing file: %v", err)
+	}
+	_, _, err = store.loadWords(storeKindDataset, 3, uint64(len(words)),
+		true)
+	if !errors.Is(err, ErrBadStoreFile) {
+		t.Fatalf("unexpected error loading corrupted file: %v", err)
+	}
+
+	// The checksum is not verified when it is not requested.
+	_, release, err = store.loadWords(storeKindDataset, 3,
+		uint64(len(words)), false)
+	if err != nil {
+		t.Fatalf("unexpected error loading without checksum: %v", err)
+	}
+	release()
+}
+
+// TestDAGStoreCache ensures a light cache saved to a DAG store is loaded back
+// with identical data and produces the same hashes.
+func TestDAGStoreCache(t *testing.T) {
+	store := NewDAGStore(t.TempDir(), DefaultDAGsOnDisk)
+	cache := epochZeroCache()
+	if err := store.SaveCache(cache); err != nil {
+		t.Fatalf("unexpected error saving cache: %v", err)
+	}
+
+	loaded, err := store.LoadCache(0)
+	if err != nil {
+		t.Fatalf("unexpected error loading cache: %v", err)
+	}
+	if loaded.numItems != cache.numItems || !slices.Equal(loaded.data, cache.data) {
+		t.Fatal("loaded cache does not match saved cache")
+	}
+	if loaded.l1 != cache.l1 {
+		t.Fatal("loaded cache L1 does not match saved cache")
+	}
+
+	var headerHash Hash
+	wantMix, wantFinal := cache.lightHash(&headerHash, 1, 1)
+	gotMix, gotFinal := loaded.lightHash(&headerHash, 1, 1)
+	if gotMix != wantMix || gotFinal != wantFinal {
+		t.Fatal("loaded cache produced different hashes")
+	}
+	loaded.Release()
+}
+
+// TestDAGStoreDatasetSpotCheck ensures a full dataset file with data that does
+// not match the items calculated from the light cache is rejected when loaded.
+func TestDAGStoreDatasetSpotCheck(t *testing.T) {
+	store := NewDAGStore(t.TempDir(), DefaultDAGsOnDisk)
+	cache := epochZeroCache()
+
+	// Create a sparse dataset file with a valid header and all zero data.
+	var header [storeHeaderSize]byte
+	copy(header[0:8], storeMagic[:])
+	le.PutUint32(header[8:12], storeVersion)
+	le.PutUint32(header[12:16], uint32(storeKindDataset))
+	le.PutUint64(header[16:24], 0)
+	path := store.path(storeKindDataset, 0)
+	if err := os.MkdirAll(store.Dir(), 0700); err != nil {
+		t.Fatalf("unexpected error creating dir: %v", err)
+	}
+	if err := os.WriteFile(path, header[:], 0600); err != nil {
+		t.Fatalf("unexpected error writing file: %v", err)
+	}
+	size := storeHeaderSize + int64(DatasetSize(0))
+	if err := os.Truncate(path, size); err != nil {
+		t.Fatalf("unexpected error extending file: %v", err)
+	}
+
+	if _, err := store.LoadDAG(cache); !errors.Is(err, ErrBadStoreFile) {
+		t.Fatalf("unexpected error loading invalid dataset: %v", err)
+	}
+}
+
+// TestDAGStorePrune ensures pruning a DAG store removes the files for old
+// epochs, other file format versions, and interrupted writes while retaining
+// the files for recent epochs and unrelated files.
+func TestDAGStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	store := NewDAGStore(dir, 2)
+
+	for epoch := uint64(0); epoch < 5; epoch++ {
+		if err := store.saveWords(storeKindCache, epoch, []uint32{1}); err != nil {
+			t.Fatalf("unexpected error saving words: %v", err)
+		}
+	}
+	extra := []string{
+		"kawpow-cache-v0-4-0000000000000000",
+		fileName(storeKindDataset, 4) + ".tmp123",
+		"unrelated",
+	}
+	for _, name := range extra {
+		err := os.WriteFile(filepath.Join(dir, name), nil, 0600)
+		if err != nil {
+			t.Fatalf("unexpected error writing file: %v", err)
+		}
+	}
+
+	if err := store.Prune(4); err != nil {
+		t.Fatalf("unexpected error pruning: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error reading dir: %v", err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	want := []string{
+		fileName(storeKindCache, 3),
+		fileName(storeKindCache, 4),
+		"unrelated",
+	}
+	slices.Sort(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected files after pruning -- got %v, want %v", got,
+			want)
+	}
+}
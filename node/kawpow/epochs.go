@@ -0,0 +1,493 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxEpochs is the default maximum number of epoch light caches an
+	// epoch manager keeps resident.  The current and next epochs are always
+	// retained, so this allows one additional epoch for reorgs and stale work
+	// that crosses an epoch boundary.
+	DefaultMaxEpochs = 3
+
+	// DefaultMaxDAGs is the default maximum number of full datasets an epoch
+	// manager keeps resident.
+	DefaultMaxDAGs = 1
+
+	// PregenerateDistance is the number of blocks before an epoch boundary at
+	// which the light cache for the next epoch starts being generated in the
+	// background.
+	PregenerateDistance = 250
+)
+
+// epochEntry houses a light cache that is either resident or in the process of
+// being generated.  The manager holds a reference to the light cache that is
+// released when the entry is evicted.  Entries with waiters are never evicted
+// since the waiters have yet to take their own reference to the light cache.
+type epochEntry struct {
+	epoch    uint64
+	cache    *Cache
+	done     chan struct{}
+	lastUsed uint64
+	waiters  int
+}
+
+// datasetEntry houses a full dataset that is either resident or in the process
+// of being generated.  It is reference counted in the same way as epochEntry.
+type datasetEntry struct {
+	epoch    uint64
+	dag      *DAG
+	err      error
+	done     chan struct{}
+	lastUsed uint64
+	waiters  int
+}
+
+// EpochManagerStats houses statistics about the epochs managed by an epoch
+// manager.
+type EpochManagerStats struct {
+	// CurrentEpoch is the epoch of the most recent height the manager was
+	// notified about.
+	CurrentEpoch uint64
+
+	// EpochsResident are the epochs with a generated light cache.
+	EpochsResident []uint64
+
+	// DAGsResident are the epochs with a generated full dataset.
+	DAGsResident []uint64
+
+	// CacheGenerations is the number of light caches generated and
+	// LastCacheGenTime is the time it took to generate the most recent one.
+	CacheGenerations uint64
+	LastCacheGenTime time.Duration
+
+	// DAGGenerations is the number of full datasets generated and
+	// LastDAGGenTime is the time it took to generate the most recent one.
+	DAGGenerations uint64
+	LastDAGGenTime time.Duration
+
+	// CacheLoads and DAGLoads are the number of light caches and full
+	// datasets loaded from the DAG store, respectively.
+	CacheLoads uint64
+	DAGLoads   uint64
+
+	// CacheMemory and DAGMemory are the number of bytes used by the resident
+	// light caches and full dataset, respectively.
+	CacheMemory uint64
+	DAGMemory   uint64
+}
+
+// EpochManager provides access to the KawPoW light caches and full datasets
+// keyed by epoch.  Light caches for the most recently used epochs are kept in
+// memory up to a configured maximum while always retaining the current and
+// next epoch, and the light cache for the next epoch is generated in the
+// background as the chain approaches an epoch boundary.
+//
+// Full datasets, which are only needed for mining, are retained up to a
+// separately configured maximum that defaults to a single dataset due to their
+// size.
+//
+// When a DAG store is configured, light caches and full datasets are loaded
+// from it when available and saved to it after they are generated, so they
+// survive restarts.
+//
+// The light caches and full datasets returned by the manager hold a reference
+// that the caller must release once it is done with them.  Those that are
+// memory mapped are unmapped once they have been evicted and every reference
+// is released.
+//
+// It is safe for concurrent access.
+type EpochManager struct {
+	maxEpochs int
+	maxDAGs   int
+	store     *DAGStore
+
+	// newCache generates the light cache for an epoch.  It is a field so
+	// tests can avoid generating real caches.
+	newCache func(epoch uint64) *Cache
+
+	mtx          sync.Mutex
+	epochs       map[uint64]*epochEntry
+	useCounter   uint64
+	currentEpoch uint64
+	dags         map[uint64]*datasetEntry
+
+	cacheGenerations uint64
+	lastCacheGenTime time.Duration
+	dagGenerations   uint64
+	lastDAGGenTime   time.Duration
+	cacheLoads       uint64
+	dagLoads         uint64
+}
+
+// NewEpochManager returns a new epoch manager that keeps at most the provided
+// number of light caches resident.  The current and next epochs are always
+// retained, so values less than two are treated as two.  At most
+// DefaultMaxDAGs full datasets are kept resident and no DAG store is used
+// until configured otherwise.
+func NewEpochManager(maxEpochs int) *EpochManager {
+	return &EpochManager{
+		maxEpochs: max(maxEpochs, 2),
+		maxDAGs:   DefaultMaxDAGs,
+		newCache:  NewCache,
+		epochs:    make(map[uint64]*epochEntry),
+		dags:      make(map[uint64]*datasetEntry),
+	}
+}
+
+// defaultEpochManager is the process-wide epoch manager.
+var defaultEpochManager = NewEpochManager(DefaultMaxEpochs)
+
+// DefaultEpochManager returns the process-wide epoch manager which is shared
+// by every consumer of KawPoW hashes in the process.
+func DefaultEpochManager() *EpochManager {
+	return defaultEpochManager
+}
+
+// SetStore configures the manager to load light caches and full datasets from,
+// and save newly generated ones to, the provided DAG store.  A nil store
+// disables persistence.
+func (m *EpochManager) SetStore(store *DAGStore) {
+	m.mtx.Lock()
+	m.store = store
+	m.mtx.Unlock()
+}
+
+// SetMaxDAGs sets the maximum number of full datasets the manager keeps
+// resident.  Values less than one are treated as one.
+func (m *EpochManager) SetMaxDAGs(maxDAGs int) {
+	m.mtx.Lock()
+	m.maxDAGs = max(maxDAGs, 1)
+	m.evictDAGs()
+	m.mtx.Unlock()
+}
+
+// isPinned returns whether the provided epoch must be retained regardless of
+// how recently it was used.
+//
+// This function MUST be called with the manager lock held.
+func (m *EpochManager) isPinned(epoch uint64) bool {
+	return epoch == m.currentEpoch || epoch == m.currentEpoch+1
+}
+
+// evict removes the least recently used light caches that are not pinned
+// until the number of entries no longer exceeds the maximum and releases the
+// references the manager holds to them.  Entries that are still being
+// generated or waited on are never evicted.
+//
+// This function MUST be called with the manager lock held.
+func (m *EpochManager) evict() {
+	for len(m.epochs) > m.maxEpochs {
+		var oldest *epochEntry
+		for _, entry := range m.epochs {
+			if m.isPinned(entry.epoch) || entry.cache == nil ||
+				entry.waiters > 0 {
+				continue
+			}
+			if oldest == nil || entry.lastUsed < oldest.lastUsed {
+				oldest = entry
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		delete(m.epochs, oldest.epoch)
+		oldest.cache.Release()
+	}
+}
+
+// entry returns the entry for the provided epoch, creating it when needed.
+// The returned bool is true when the entry was created and therefore the
+// caller is responsible for generating the light cache.
+//
+// This function MUST be called with the manager lock held.
+func (m *EpochManager) entry(epoch uint64) (*epochEntry, bool) {
+	m.useCounter++
+	if entry, ok := m.epochs[epoch]; ok {
+		entry.lastUsed = m.useCounter
+		return entry, false
+	}
+	entry := &epochEntry{
+		epoch:    epoch,
+		done:     make(chan struct{}),
+		lastUsed: m.useCounter,
+	}
+	m.epochs[epoch] = entry
+	return entry, true
+}
+
+// generate loads the light cache for the provided entry from the DAG store, or
+// generates it when the store is not configured or does not have it, and marks
+// the entry done.  Newly generated light caches are saved to the store.
+func (m *EpochManager) generate(entry *epochEntry) {
+	m.mtx.Lock()
+	store := m.store
+	m.mtx.Unlock()
+
+	if store != nil {
+		cache, err := store.LoadCache(entry.epoch)
+		if err == nil {
+			log.Debugf("Loaded KawPoW light cache for epoch %d from %s",
+				entry.epoch, store.Dir())
+			m.mtx.Lock()
+			entry.cache = cache
+			m.cacheLoads++
+			m.evict()
+			m.mtx.Unlock()
+			close(entry.done)
+			return
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warnf("Unable to load KawPoW light cache for epoch %d: %v",
+				entry.epoch, err)
+		}
+	}
+
+	start := time.Now()
+	cache := m.newCache(entry.epoch)
+	elapsed := time.Since(start)
+	log.Debugf("Generated KawPoW light cache for epoch %d in %v",
+		entry.epoch, elapsed)
+	if store != nil {
+		if err := store.SaveCache(cache); err != nil {
+			log.Warnf("Unable to save KawPoW light cache for epoch %d: %v",
+				entry.epoch, err)
+		}
+	}
+
+	m.mtx.Lock()
+	entry.cache = cache
+	m.cacheGenerations++
+	m.lastCacheGenTime = elapsed
+	m.evict()
+	m.mtx.Unlock()
+	close(entry.done)
+}
+
+// Cache returns the light cache for the provided epoch, generating it when it
+// is not already resident.  Concurrent requests for the same epoch share a
+// single generation.  The caller must release the returned light cache once it
+// is done with it.
+func (m *EpochManager) Cache(epoch uint64) *Cache {
+	m.mtx.Lock()
+	entry, isNew := m.entry(epoch)
+	entry.waiters++
+	m.mtx.Unlock()
+
+	if isNew {
+		m.generate(entry)
+	}
+	<-entry.done
+
+	m.mtx.Lock()
+	entry.waiters--
+	cache := entry.cache
+	cache.refs.retain()
+	m.evict()
+	m.mtx.Unlock()
+	return cache
+}
+
+// CacheForHeight returns the light cache for the epoch of the provided block
+// height.  See Cache for more details.
+func (m *EpochManager) CacheForHeight(height uint64) *Cache {
+	return m.Cache(EpochForHeight(height))
+}
+
+// pregenerate starts generating the light cache for the provided epoch in the
+// background when it is neither resident nor already being generated.
+//
+// This function MUST be called with the manager lock held.
+func (m *EpochManager) pregenerate(epoch uint64) {
+	entry, isNew := m.entry(epoch)
+	if isNew {
+		go m.generate(entry)
+	}
+}
+
+// NotifyHeight informs the manager of the current chain tip height.  The
+// epoch of the height becomes the current epoch and its light cache is
+// generated in the background when it is not already resident.  Likewise, the
+// light cache for the next epoch is generated in the background once the
+// height is within PregenerateDistance blocks of it.
+//
+// The files for epochs that are no longer retained are also pruned from the
+// DAG store, if any, when the current epoch changes.
+func (m *EpochManager) NotifyHeight(height uint64) {
+	epoch := EpochForHeight(height)
+
+	m.mtx.Lock()
+	epochChanged := epoch != m.currentEpoch
+	m.currentEpoch = epoch
+	m.pregenerate(epoch)
+	if height%EpochLength >= EpochLength-PregenerateDistance {
+		m.pregenerate(epoch + 1)
+	}
+	m.evict()
+	store := m.store
+	m.mtx.Unlock()
+
+	if epochChanged && store != nil {
+		if err := store.Prune(epoch); err != nil {
+			log.Warnf("Unable to prune KawPoW DAG store: %v", err)
+		}
+	}
+}
+
+// evictDAGs removes the least recently used full datasets until the number of
+// resident datasets no longer exceeds the maximum and releases the references
+// the manager holds to them.  Datasets that are still being generated or
+// waited on are never evicted.
+//
+// This function MUST be called with the manager lock held.
+func (m *EpochManager) evictDAGs() {
+	for len(m.dags) > m.maxDAGs {
+		var oldest *datasetEntry
+		for _, entry := range m.dags {
+			if entry.dag == nil || entry.waiters > 0 {
+				continue
+			}
+			if oldest == nil || entry.lastUsed < oldest.lastUsed {
+				oldest = entry
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		delete(m.dags, oldest.epoch)
+		oldest.dag.Release()
+	}
+}
+
+// loadDAG loads the full dataset for the provided epoch from the DAG store, or
+// generates it when the store is not configured or does not have it.  Newly
+// generated datasets are saved to the store.  The returned bool is true when
+// the dataset was loaded from the store.
+func (m *EpochManager) loadDAG(store *DAGStore, epoch uint64) (*DAG, bool, error) {
+	cache := m.Cache(epoch)
+	defer cache.Release()
+	if store != nil {
+		dag, err := store.LoadDAG(cache)
+		if err == nil {
+			log.Debugf("Loaded KawPoW DAG for epoch %d from %s", epoch,
+				store.Dir())
+			return dag, true, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warnf("Unable to load KawPoW DAG for epoch %d: %v", epoch,
+				err)
+		}
+	}
+
+	log.Infof("Generating KawPoW DAG for epoch %d (%d MiB)", epoch,
+		DatasetSize(epoch)>>20)
+	dag := NewDAGFromCache(cache)
+	if err := dag.Generate(); err != nil {
+		dag.Release()
+		return nil, false, err
+	}
+	if store != nil {
+		if err := store.SaveDAG(dag); err != nil {
+			log.Warnf("Unable to save KawPoW DAG for epoch %d: %v", epoch,
+				err)
+		}
+	}
+	return dag, false, nil
+}
+
+// DAG returns the full dataset for the provided epoch, loading it from the DAG
+// store or generating it when it is not already resident.  Concurrent requests
+// for the same epoch share a single generation and the least recently used
+// datasets are released once more than the configured maximum are resident.
+// The caller must release the returned DAG once it is done with it.
+func (m *EpochManager) DAG(epoch uint64) (*DAG, error) {
+	m.mtx.Lock()
+	m.useCounter++
+	entry, ok := m.dags[epoch]
+	if ok {
+		entry.lastUsed = m.useCounter
+	} else {
+		entry = &datasetEntry{
+			epoch:    epoch,
+			done:     make(chan struct{}),
+			lastUsed: m.useCounter,
+		}
+		m.dags[epoch] = entry
+	}
+	entry.waiters++
+	store := m.store
+	m.mtx.Unlock()
+
+	if !ok {
+		start := time.Now()
+		dag, loaded, err := m.loadDAG(store, epoch)
+		elapsed := time.Since(start)
+
+		m.mtx.Lock()
+		entry.dag, entry.err = dag, err
+		switch {
+		case err != nil:
+			delete(m.dags, epoch)
+		case loaded:
+			m.dagLoads++
+		default:
+			m.dagGenerations++
+			m.lastDAGGenTime = elapsed
+		}
+		m.mtx.Unlock()
+		close(entry.done)
+	}
+	<-entry.done
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	entry.waiters--
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	entry.dag.refs.retain()
+	m.evictDAGs()
+	return entry.dag, nil
+}
+
+// Stats returns statistics about the epochs held by the manager.
+func (m *EpochManager) Stats() EpochManagerStats {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	stats := EpochManagerStats{
+		CurrentEpoch:     m.currentEpoch,
+		CacheGenerations: m.cacheGenerations,
+		LastCacheGenTime: m.lastCacheGenTime,
+		DAGGenerations:   m.dagGenerations,
+		LastDAGGenTime:   m.lastDAGGenTime,
+		CacheLoads:       m.cacheLoads,
+		DAGLoads:         m.dagLoads,
+	}
+	for epoch, entry := range m.epochs {
+		if entry.cache == nil {
+			continue
+		}
+		stats.EpochsResident = append(stats.EpochsResident, epoch)
+		stats.CacheMemory += entry.cache.Size()
+	}
+	slices.Sort(stats.EpochsResident)
+	for epoch, entry := range m.dags {
+		if entry.dag == nil {
+			continue
+		}
+		stats.DAGsResident = append(stats.DAGsResident, epoch)
+		stats.DAGMemory += uint64(len(entry.dag.data)) * 4
+	}
+	slices.Sort(stats.DAGsResident)
+	return stats
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestEpochManager returns an epoch manager that creates small fake light
+// caches instead of generating real ones along with a counter of the number of
+// caches created.
+func newTestEpochManager(maxEpochs int) (*EpochManager, *atomic.Uint64) {
+	var generated atomic.Uint64
+	m := NewEpochManager(maxEpochs)
+	m.newCache = func(epoch uint64) *Cache {
+		generated.Add(1)
+		return &Cache{epoch: epoch, data: make([]uint32, cacheItemWords)}
+	}
+	return m, &generated
+}
+
+// waitForEpochs waits for the manager to have the provided epochs resident or
+// fails the test after a timeout.
+func waitForEpochs(t *testing.T, m *EpochManager, want []uint64) {
+	t.Helper()
+
+	var stats EpochManagerStats
+	for i := 0; i < 500; i++ {
+		stats = m.Stats()
+		if reflect.DeepEqual(stats.EpochsResident, want) {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Fatalf("unexpected resident epochs -- got %v, want %v",
+		stats.EpochsResident, want)
+}
+
+// TestEpochManagerLRU ensures the epoch manager evicts the least recently used
+// light caches while retaining the current and next epochs.
+func TestEpochManagerLRU(t *testing.T) {
+	m, generated := newTestEpochManager(3)
+
+	// Make epoch 5 current and request a few older epochs.
+	m.NotifyHeight(5 * EpochLength)
+	waitForEpochs(t, m, []uint64{5})
+	for _, epoch := range []uint64{1, 2} {
+		if cache := m.Cache(epoch); cache.Epoch() != epoch {
+			t.Fatalf("unexpected cache epoch -- got %d, want %d",
+				cache.Epoch(), epoch)
+		}
+	}
+	waitForEpochs(t, m, []uint64{1, 2, 5})
+
+	// Touch epoch 1 so epoch 2 becomes the least recently used and request a
+	// new epoch which must evict it.
+	m.Cache(1)
+	m.Cache(3)
+	waitForEpochs(t, m, []uint64{1, 3, 5})
+
+	// Request more epochs than the maximum to ensure the current epoch is
+	// never evicted even though it is the least recently used.
+	m.Cache(7)
+	m.Cache(8)
+	waitForEpochs(t, m, []uint64{5, 7, 8})
+
+	// Requesting resident caches must not regenerate them.
+	before := generated.Load()
+	m.Cache(7)
+	m.Cache(5)
+	if after := generated.Load(); after != before {
+		t.Fatalf("resident caches were regenerated %d times", after-before)
+	}
+}
+
+// TestEpochManagerPregenerate ensures the light cache for the next epoch is
+// generated in the background once the notified height is close enough to the
+// epoch boundary and is retained as the next epoch.
+func TestEpochManagerPregenerate(t *testing.T) {
+	m, _ := newTestEpochManager(2)
+
+	m.NotifyHeight(EpochLength - PregenerateDistance - 1)
+	waitForEpochs(t, m, []uint64{0})
+
+	m.NotifyHeight(EpochLength - PregenerateDistance)
+	waitForEpochs(t, m, []uint64{0, 1})
+
+	// Crossing the boundary makes the pre-generated epoch current and allows
+	// the previous one to be evicted once another epoch is needed.
+	m.NotifyHeight(EpochLength)
+	m.NotifyHeight(2*EpochLength - 1)
+	waitForEpochs(t, m, []uint64{1, 2})
+	if stats := m.Stats(); stats.CurrentEpoch != 1 {
+		t.Fatalf("unexpected current epoch -- got %d, want 1",
+			stats.CurrentEpoch)
+	}
+}
+
+// TestEpochManagerConcurrent ensures concurrent requests for the same epoch
+// share a single generation.
+func TestEpochManagerConcurrent(t *testing.T) {
+	m, generated := newTestEpochManager(DefaultMaxEpochs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if cache := m.CacheForHeight(EpochLength + 1); cache.Epoch() != 1 {
+				t.Errorf("unexpected cache epoch -- got %d, want 1",
+					cache.Epoch())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := generated.Load(); got != 1 {
+		t.Fatalf("unexpected number of generated caches -- got %d, want 1",
+			got)
+	}
+	stats := m.Stats()
+	if stats.CacheGenerations != 1 {
+		t.Fatalf("unexpected cache generations stat -- got %d, want 1",
+			stats.CacheGenerations)
+	}
+	if stats.CacheMemory != cacheItemBytes {
+		t.Fatalf("unexpected cache memory stat -- got %d, want %d",
+			stats.CacheMemory, cacheItemBytes)
+	}
+}
+
+// TestEpochManagerStore ensures light caches generated by an epoch manager
+// with a DAG store are saved to it and loaded from it by a later manager
+// instead of being regenerated.
+func TestEpochManagerStore(t *testing.T) {
+	store := NewDAGStore(t.TempDir(), DefaultDAGsOnDisk)
+
+	m := NewEpochManager(DefaultMaxEpochs)
+	m.newCache = func(epoch uint64) *Cache {
+		return epochZeroCache()
+	}
+	m.SetStore(store)
+	m.Cache(0)
+	if stats := m.Stats(); stats.CacheGenerations != 1 || stats.CacheLoads != 0 {
+		t.Fatalf("unexpected cache stats -- generations %d, loads %d",
+			stats.CacheGenerations, stats.CacheLoads)
+	}
+
+	m2, generated := newTestEpochManager(DefaultMaxEpochs)
+	m2.SetStore(store)
+	cache := m2.Cache(0)
+	if got := generated.Load(); got != 0 {
+		t.Fatalf("stored cache was regenerated %d times", got)
+	}
+	if stats := m2.Stats(); stats.CacheLoads != 1 {
+		t.Fatalf("unexpected cache loads -- got %d, want 1", stats.CacheLoads)
+	}
+	if cache.l1 != epochZeroCache().l1 {
+		t.Fatal("loaded cache does not match stored cache")
+	}
+}
+
+// TestEpochManagerRelease ensures light caches evicted by the epoch manager are
+// only unmapped once every reference handed out by the manager is released.
+func TestEpochManagerRelease(t *testing.T) {
+	var unmapped sync.Map
+	m := NewEpochManager(2)
+	m.newCache = func(epoch uint64) *Cache {
+		return &Cache{
+			epoch: epoch,
+			data:  make([]uint32, cacheItemWords),
+			refs:  newDataRefs(func() { unmapped.Store(epoch, true) }),
+		}
+	}
+
+	// Make epoch 5 current, which pins it along with epoch 6, and hold a
+	// reference to epoch 1 while requesting another epoch that evicts it.
+	m.NotifyHeight(5 * EpochLength)
+	waitForEpochs(t, m, []uint64{5})
+	cache := m.Cache(1)
+	m.Cache(2).Release()
+	waitForEpochs(t, m, []uint64{2, 5})
+	if _, ok := unmapped.Load(uint64(1)); ok {
+		t.Fatal("evicted cache was unmapped while still referenced")
+	}
+
+	cache.Release()
+	if _, ok := unmapped.Load(uint64(1)); !ok {
+		t.Fatal("evicted cache was not unmapped once released")
+	}
+
+	// Releasing more references than were handed out must not affect the
+	// reference held by the manager.
+	cache.Release()
+	if _, ok := unmapped.Load(uint64(2)); ok {
+		t.Fatal("resident cache was unmapped")
+	}
+}
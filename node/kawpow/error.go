@@ -0,0 +1,68 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+// ErrorKind identifies a kind of error.  It has full support for errors.Is and
+// errors.As, so the caller can directly check against an error kind when
+// determining the reason for an error.
+type ErrorKind string
+
+// These constants are used to identify a specific Error.
+const (
+	// ErrWrongEpoch indicates a light cache or DAG was used to hash a block
+	// height that belongs to a different epoch.
+	ErrWrongEpoch = ErrorKind("ErrWrongEpoch")
+
+	// ErrEpochTooHigh indicates a block height belongs to an epoch beyond
+	// MaxEpoch, whose light cache and full dataset can not be indexed.
+	ErrEpochTooHigh = ErrorKind("ErrEpochTooHigh")
+
+	// ErrDAGNotGenerated indicates a hash was requested from a DAG that has
+	// not had its full dataset generated.
+	ErrDAGNotGenerated = ErrorKind("ErrDAGNotGenerated")
+
+	// ErrBadMixHash indicates the mix hash of a solution does not match the
+	// mix hash calculated from the header hash, nonce, and height.
+	ErrBadMixHash = ErrorKind("ErrBadMixHash")
+
+	// ErrHighHash indicates the final hash of a solution is higher than the
+	// target difficulty.
+	ErrHighHash = ErrorKind("ErrHighHash")
+
+	// ErrBadTarget indicates a target difficulty is not a positive value.
+	ErrBadTarget = ErrorKind("ErrBadTarget")
+
+	// ErrBadStoreFile indicates a light cache or full dataset file in a DAG
+	// store is malformed, has an unexpected size, or fails its checksum.
+	ErrBadStoreFile = ErrorKind("ErrBadStoreFile")
+)
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e ErrorKind) Error() string {
+	return string(e)
+}
+
+// Error identifies an error related to KawPoW hashing and verification.  It
+// has full support for errors.Is and errors.As, so the caller can ascertain
+// the specific reason for the error by checking the underlying error.
+type Error struct {
+	Description string
+	Err         error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e Error) Error() string {
+	return e.Description
+}
+
+// Unwrap returns the underlying wrapped error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// makeError creates an Error given a set of arguments.
+func makeError(kind ErrorKind, desc string) Error {
+	return Error{Err: kind, Description: desc}
+}
@@ -0,0 +1,451 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"runtime"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// EpochLength is the number of blocks per KawPoW epoch.  The light cache
+	// and full dataset only change on epoch boundaries.  This matches the
+	// epoch length used by Ravencoin and therefore stock KawPoW miners.
+	EpochLength = 7500
+
+	// cacheInitBytes is the size of the light cache for the first epoch
+	// before rounding down to a prime number of items.
+	cacheInitBytes = 1 << 24
+
+	// cacheGrowthBytes is the number of bytes the light cache grows by each
+	// epoch before rounding down to a prime number of items.
+	cacheGrowthBytes = 1 << 17
+
+	// cacheRounds is the number of RandMemoHash rounds used when building the
+	// light cache.
+	cacheRounds = 3
+
+	// datasetInitBytes is the size of the full dataset for the first epoch
+	// before rounding down to a prime number of items.
+	datasetInitBytes = 1 << 30
+
+	// datasetGrowthBytes is the number of bytes the full dataset grows by
+	// each epoch before rounding down to a prime number of items.
+	datasetGrowthBytes = 1 << 23
+
+	// datasetParents is the number of light cache items that are combined to
+	// produce each full dataset item.
+	datasetParents = 512
+
+	// cacheItemBytes is the size of a light cache item in bytes.
+	cacheItemBytes = 64
+
+	// cacheItemWords is the number of 32-bit words in a light cache item.
+	cacheItemWords = cacheItemBytes / 4
+
+	// datasetItemBytes is the size in bytes of the items used to determine
+	// the full dataset size.
+	datasetItemBytes = 128
+
+	// dagEntryWords is the number of 32-bit words in the 2048-bit DAG entries
+	// ProgPoW reads in every round.
+	dagEntryWords = 64
+
+	// fnvPrime is the 32-bit FNV prime.
+	fnvPrime = 0x01000193
+
+	// MaxEpoch is the largest supported epoch.  The full datasets of later
+	// epochs have more items than the 32-bit item indices used by the
+	// algorithm are able to address.
+	MaxEpoch = (1<<32 - datasetInitBytes/datasetItemBytes) /
+		(datasetGrowthBytes / datasetItemBytes)
+)
+
+// le is a convenience alias for the little-endian byte order used throughout
+// the algorithm.
+var le = binary.LittleEndian
+
+// EpochForHeight returns the KawPoW epoch that the given block height falls in.
+func EpochForHeight(height uint64) uint64 {
+	return height / EpochLength
+}
+
+// CheckEpoch ensures the provided epoch is not beyond MaxEpoch.  It must be
+// used to reject untrusted epochs before requesting their light cache or full
+// dataset since generating them is not possible for such epochs.
+//
+// The returned error is of type Error with ErrEpochTooHigh when the epoch is
+// not supported.
+func CheckEpoch(epoch uint64) error {
+	if epoch > MaxEpoch {
+		str := fmt.Sprintf("epoch %d is higher than the max supported epoch "+
+			"of %d", epoch, MaxEpoch)
+		return makeError(ErrEpochTooHigh, str)
+	}
+	return nil
+}
+
+// isOddPrime returns whether the given odd number is prime.
+func isOddPrime(n uint64) bool {
+	for d := uint64(3); d*d <= n; d += 2 {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// largestPrime returns the largest prime number that is not greater than the
+// provided upper bound.
+func largestPrime(upper uint64) uint64 {
+	if upper < 2 {
+		return 0
+	}
+	if upper == 2 {
+		return 2
+	}
+	n := upper
+	if n%2 == 0 {
+		n--
+	}
+	for !isOddPrime(n) {
+		n -= 2
+	}
+	return n
+}
+
+// cacheNumItems returns the number of 512-bit items in the light cache for the
+// given epoch.
+func cacheNumItems(epoch uint64) uint64 {
+	upper := cacheInitBytes/cacheItemBytes + epoch*(cacheGrowthBytes/cacheItemBytes)
+	return largestPrime(upper)
+}
+
+// datasetNumItems returns the number of 1024-bit items in the full dataset for
+// the given epoch.
+func datasetNumItems(epoch uint64) uint64 {
+	upper := datasetInitBytes/datasetItemBytes +
+		epoch*(datasetGrowthBytes/datasetItemBytes)
+	return largestPrime(upper)
+}
+
+// CacheSize returns the size of the light cache in bytes for the given epoch.
+func CacheSize(epoch uint64) uint64 {
+	return cacheNumItems(epoch) * cacheItemBytes
+}
+
+// DatasetSize returns the size of the full dataset in bytes for the given
+// epoch.
+func DatasetSize(epoch uint64) uint64 {
+	return datasetNumItems(epoch) * datasetItemBytes
+}
+
+// SeedHash returns the seed hash for the given epoch.  It is the result of
+// iteratively hashing 32 zero bytes with Keccak-256 once per epoch.
+func SeedHash(epoch uint64) Hash {
+	var seed Hash
+	h := sha3.NewLegacyKeccak256()
+	for i := uint64(0); i < epoch; i++ {
+		h.Reset()
+		h.Write(seed[:])
+		h.Sum(seed[:0])
+	}
+	return seed
+}
+
+// keccak512Words hashes the 512-bit item in src with Keccak-512 and stores the
+// result in dst.  The provided hasher and scratch buffer are reused to avoid
+// allocations.
+func keccak512Words(h hash.Hash, buf *[cacheItemBytes]byte, dst, src []uint32) {
+	for i := 0; i < cacheItemWords; i++ {
+		le.PutUint32(buf[i*4:], src[i])
+	}
+	h.Reset()
+	h.Write(buf[:])
+	h.Sum(buf[:0])
+	for i := 0; i < cacheItemWords; i++ {
+		dst[i] = le.Uint32(buf[i*4:])
+	}
+}
+
+// dataRefs tracks the references to the data of a light cache or full dataset
+// so that data memory mapped from a DAG store is unmapped as soon as the last
+// reference is released rather than whenever the garbage collector gets around
+// to it.  A nil dataRefs tracks nothing.
+type dataRefs struct {
+	mtx   sync.Mutex
+	refs  int
+	unmap func()
+}
+
+// newDataRefs returns a dataRefs with a single reference that calls the
+// provided function, if any, once all references are released.
+func newDataRefs(unmap func()) *dataRefs {
+	return &dataRefs{refs: 1, unmap: unmap}
+}
+
+// retain adds a reference.  It must only be called while the caller holds a
+// reference, so the data is guaranteed to not have been unmapped.
+func (r *dataRefs) retain() {
+	if r == nil {
+		return
+	}
+	r.mtx.Lock()
+	r.refs++
+	r.mtx.Unlock()
+}
+
+// release removes a reference and unmaps the data once none remain.  It
+// returns whether the final reference was released.  Releasing more
+// references than were held has no effect.
+func (r *dataRefs) release() bool {
+	if r == nil {
+		return false
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.refs == 0 {
+		return false
+	}
+	r.refs--
+	if r.refs != 0 {
+		return false
+	}
+	if r.unmap != nil {
+		r.unmap()
+		r.unmap = nil
+	}
+	return true
+}
+
+// Cache is the light cache for a KawPoW epoch.  It is sufficient to compute any
+// item of the full dataset on demand and therefore to verify hashes without
+// the full dataset.
+//
+// Light caches are reference counted since those loaded from a DAG store are
+// memory mapped.  The creator of a light cache holds a reference to it that
+// must be released with Release once the cache is no longer in use.
+type Cache struct {
+	epoch    uint64
+	numItems uint32
+	data     []uint32
+	l1       [l1CacheWords]uint32
+	refs     *dataRefs
+}
+
+// NewCache generates and returns the light cache for the given epoch.
+func NewCache(epoch uint64) *Cache {
+	numItems := cacheNumItems(epoch)
+	c := &Cache{
+		epoch:    epoch,
+		numItems: uint32(numItems),
+		data:     make([]uint32, numItems*cacheItemWords),
+		refs:     newDataRefs(nil),
+	}
+	seed := SeedHash(epoch)
+	c.generate(seed[:])
+	c.generateL1()
+	return c
+}
+
+// item returns the light cache item at the given index.
+func (c *Cache) item(i uint32) []uint32 {
+	return c.data[i*cacheItemWords : (i+1)*cacheItemWords]
+}
+
+// generate fills the cache from the provided seed using Keccak-512 followed by
+// the RandMemoHash rounds.
+func (c *Cache) generate(seed []byte) {
+	h := sha3.NewLegacyKeccak512()
+	var buf [cacheItemBytes]byte
+	h.Write(seed)
+	h.Sum(buf[:0])
+	first := c.item(0)
+	for i := range first {
+		first[i] = le.Uint32(buf[i*4:])
+	}
+	for i := uint32(1); i < c.numItems; i++ {
+		keccak512Words(h, &buf, c.item(i), c.item(i-1))
+	}
+
+	var x [cacheItemWords]uint32
+	for r := 0; r < cacheRounds; r++ {
+		for i := uint32(0); i < c.numItems; i++ {
+			v := c.item(i)[0] % c.numItems
+			w := (c.numItems + i - 1) % c.numItems
+			vItem, wItem := c.item(v), c.item(w)
+			for j := range x {
+				x[j] = vItem[j] ^ wItem[j]
+			}
+			keccak512Words(h, &buf, c.item(i), x[:])
+		}
+	}
+}
+
+// generateL1 populates the L1 cache from the first DAG entries.
+func (c *Cache) generateL1() {
+	h := sha3.NewLegacyKeccak512()
+	var buf [cacheItemBytes]byte
+	const numL1Items = l1CacheWords / cacheItemWords
+	for i := uint32(0); i < numL1Items; i++ {
+		c.datasetItem(h, &buf, i, c.l1[i*cacheItemWords:(i+1)*cacheItemWords])
+	}
+}
+
+// Epoch returns the epoch the cache was generated for.
+func (c *Cache) Epoch() uint64 {
+	return c.epoch
+}
+
+// Size returns the size of the cache data in bytes.
+func (c *Cache) Size() uint64 {
+	return uint64(len(c.data)) * 4
+}
+
+// Release releases a reference to the light cache.  A light cache that was
+// loaded from a DAG store is unmapped once all of its references are released,
+// so it must not be used by the caller after it is released.
+func (c *Cache) Release() {
+	c.refs.release()
+}
+
+// datasetItem calculates the 512-bit full dataset item at the given index from
+// the light cache and stores it in dst.
+func (c *Cache) datasetItem(h hash.Hash, buf *[cacheItemBytes]byte, index uint32, dst []uint32) {
+	var mix [cacheItemWords]uint32
+	copy(mix[:], c.item(index%c.numItems))
+	mix[0] ^= index
+	keccak512Words(h, buf, mix[:], mix[:])
+
+	for j := uint32(0); j < datasetParents; j++ {
+		parent := fnv1(index^j, mix[j%cacheItemWords]) % c.numItems
+		parentItem := c.item(parent)
+		for k := range mix {
+			mix[k] = fnv1(mix[k], parentItem[k])
+		}
+	}
+	keccak512Words(h, buf, dst, mix[:])
+}
+
+// dagEntry calculates the 2048-bit DAG entry at the given index from the light
+// cache.
+func (c *Cache) dagEntry(h hash.Hash, buf *[cacheItemBytes]byte, index uint32, entry *[dagEntryWords]uint32) {
+	const itemsPerEntry = dagEntryWords / cacheItemWords
+	for k := uint32(0); k < itemsPerEntry; k++ {
+		c.datasetItem(h, buf, index*itemsPerEntry+k,
+			entry[k*cacheItemWords:(k+1)*cacheItemWords])
+	}
+}
+
+// fnv1 returns the 32-bit FNV-1 combination of the provided values as used by
+// Ethash.
+func fnv1(u, v uint32) uint32 {
+	return (u * fnvPrime) ^ v
+}
+
+// DAG is the full KawPoW dataset for an epoch along with the light cache it
+// was derived from.
+//
+// Like light caches, DAGs are reference counted and the creator of a DAG holds
+// a reference to it that must be released with Release.  A DAG holds a
+// reference to its light cache until its own final reference is released.
+type DAG struct {
+	epoch uint64
+	cache *Cache
+	data  []uint32
+	refs  *dataRefs
+}
+
+// NewDAG returns a DAG for the given epoch.  The light cache and full dataset
+// are not generated until Generate is called.
+func NewDAG(epoch uint64) *DAG {
+	return &DAG{epoch: epoch, refs: newDataRefs(nil)}
+}
+
+// NewDAGFromCache returns a DAG for the epoch of the provided light cache.  The
+// full dataset is not generated until Generate is called.  The DAG takes its
+// own reference to the light cache, so the caller remains responsible for
+// releasing the reference it holds.
+func NewDAGFromCache(cache *Cache) *DAG {
+	cache.refs.retain()
+	return &DAG{epoch: cache.epoch, cache: cache, refs: newDataRefs(nil)}
+}
+
+// Generate generates the light cache, when needed, and the full dataset for the
+// epoch of the DAG.  The work is split across all available CPUs.
+func (d *DAG) Generate() error {
+	if d.cache == nil {
+		d.cache = NewCache(d.epoch)
+	}
+	if d.data != nil {
+		return nil
+	}
+
+	numItems := datasetNumItems(d.epoch) * (datasetItemBytes / cacheItemBytes)
+	data := make([]uint32, numItems*cacheItemWords)
+	workers := uint64(runtime.NumCPU())
+	chunk := (numItems + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := uint64(0); start < numItems; start += chunk {
+		end := min(start+chunk, numItems)
+		wg.Add(1)
+		go func(start, end uint64) {
+			defer wg.Done()
+			d.cache.generateDataset(data, start, end)
+		}(start, end)
+	}
+	wg.Wait()
+
+	d.data = data
+	return nil
+}
+
+// generateDataset calculates the 512-bit full dataset items in the range
+// [start, end) and stores them at their position in dst.
+func (c *Cache) generateDataset(dst []uint32, start, end uint64) {
+	h := sha3.NewLegacyKeccak512()
+	var buf [cacheItemBytes]byte
+	for i := start; i < end; i++ {
+		c.datasetItem(h, &buf, uint32(i), dst[i*cacheItemWords:(i+1)*cacheItemWords])
+	}
+}
+
+// Epoch returns the epoch of the DAG.
+func (d *DAG) Epoch() uint64 {
+	return d.epoch
+}
+
+// Cache returns the light cache the DAG was derived from.  It is nil until the
+// DAG is generated unless the DAG was created from an existing cache.  The
+// light cache remains usable until the DAG is released.
+func (d *DAG) Cache() *Cache {
+	return d.cache
+}
+
+// Generated returns whether the full dataset has been generated.
+func (d *DAG) Generated() bool {
+	return d.data != nil
+}
+
+// Size returns the size of the full dataset in bytes for the epoch of the DAG.
+func (d *DAG) Size() uint64 {
+	return DatasetSize(d.epoch)
+}
+
+// Release releases a reference to the DAG.  Once all of its references are
+// released, a full dataset that was loaded from a DAG store is unmapped and the
+// reference to the light cache is released, so the DAG must not be used by the
+// caller after it is released.
+func (d *DAG) Release() {
+	if d.refs.release() && d.cache != nil {
+		d.cache.Release()
+	}
+}
@@ -0,0 +1,364 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	// testCacheOnce and testCache are used to generate the light cache for
+	// the first epoch once and share it between tests since it is relatively
+	// expensive to generate.
+	testCacheOnce sync.Once
+	testCache     *Cache
+)
+
+// epochZeroCache returns the shared light cache for the first epoch.
+func epochZeroCache() *Cache {
+	testCacheOnce.Do(func() {
+		testCache = NewCache(0)
+	})
+	return testCache
+}
+
+// hexToHash converts the passed hex string into a Hash and will panic if there
+// is an error.  This is only provided for the hard-coded constants so errors
+// in the source code can be detected.  It will only (and must only) be called
+// with hard-coded values.
+func hexToHash(s string) Hash {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != HashSize {
+		panic("invalid hash in source file: " + s)
+	}
+	var h Hash
+	copy(h[:], b)
+	return h
+}
+
+// TestKeccakF800 ensures the Keccak-f[800] permutation produces the expected
+// result for the all zero state as published in the Keccak intermediate value
+// test vectors.
+func TestKeccakF800(t *testing.T) {
+	want := [8]uint32{
+		0xe531d45d, 0xf404c6fb, 0x23a0bf99, 0xf1f8452f,
+		0x51ffd042, 0xe539f578, 0xf00b80a7, 0xaf973664,
+	}
+
+	var state [25]uint32
+	keccakF800(&state)
+	for i := range want {
+		if state[i] != want[i] {
+			t.Fatalf("mismatched lane %d -- got %08x, want %08x", i,
+				state[i], want[i])
+		}
+	}
+}
+
+// TestKISS99 ensures the KISS99 generator produces the values from the ProgPoW
+// test vectors.
+func TestKISS99(t *testing.T) {
+	want := map[int]uint32{
+		1:      769445856,
+		2:      742012328,
+		3:      2121196314,
+		4:      2805620942,
+		100000: 941074834,
+	}
+
+	rng := kiss99{z: 362436069, w: 521288629, jsr: 123456789, jcong: 380116160}
+	for i := 1; i <= 100000; i++ {
+		got := rng.next()
+		if wantVal, ok := want[i]; ok && got != wantVal {
+			t.Fatalf("mismatched value for iteration %d -- got %d, want %d",
+				i, got, wantVal)
+		}
+	}
+}
+
+// TestFNV1a ensures the FNV-1a combination produces the values from the
+// ProgPoW test vectors.
+func TestFNV1a(t *testing.T) {
+	tests := []struct {
+		u, v uint32
+		want uint32
+	}{
+		{0x811c9dc5, 0xddd0a47b, 0xd37ee61a},
+		{0xd37ee61a, 0xee304846, 0xdedc7ad4},
+		{0xdedc7ad4, 0x00000000, 0xa9155bbc},
+	}
+
+	for i, test := range tests {
+		if got := fnv1a(test.u, test.v); got != test.want {
+			t.Errorf("test #%d: unexpected result -- got %08x, want %08x",
+				i, got, test.want)
+		}
+	}
+}
+
+// TestRandomMath ensures every ProgPoW random math operation produces the
+// values from the ProgPoW test vectors.
+func TestRandomMath(t *testing.T) {
+	tests := []struct {
+		name      string // test description
+		a, b, sel uint32
+		want      uint32
+	}{
+		{"add", 0x8626bb1f, 0xbbdfbc4e, 0x883e5b49, 0x4206776d},
+		{"mul", 0x3f4bdfac, 0xd79e414f, 0x36b71236, 0x4c5cb214},
+		{"mul_hi32", 0x6d175b7e, 0xc4e89d4c, 0x944ecabb, 0x53e9023f},
+		{"min", 0x2eddd94c, 0x7e70cb54, 0x3f472a85, 0x2eddd94c},
+		{"rotl32", 0x61ae0e62, 0xe0596b32, 0x3f472a85, 0x61ae0e62},
+		{"rotr32 (a)", 0x8a81e396, 0x3f4bdfac, 0xcec46e67, 0x1e3968a8},
+		{"rotr32 (b)", 0x8a81e396, 0x7e70cb54, 0xdbe71ff7, 0x1e3968a8},
+		{"and", 0xa7352f36, 0xa0eb7045, 0x59e7b9d8, 0xa0212004},
+		{"or", 0xc89805af, 0x64291e2f, 0x1bdc84a9, 0xecb91faf},
+		{"xor", 0x760726d3, 0x79fc6a48, 0xc675cac5, 0x0ffb4c9b},
+		{"clz", 0x75551d43, 0x3383ba34, 0x2863ad31, 0x00000003},
+		{"popcount", 0xea260841, 0xe92c44b7, 0xf83ffe7d, 0x0000001b},
+	}
+
+	for _, test := range tests {
+		got := randomMath(test.a, test.b, test.sel)
+		if got != test.want {
+			t.Errorf("%q: unexpected result -- got %08x, want %08x",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestRandomMerge ensures every ProgPoW random merge operation produces the
+// values from the ProgPoW test vectors.
+func TestRandomMerge(t *testing.T) {
+	tests := []struct {
+		name      string // test description
+		a, b, sel uint32
+		want      uint32
+	}{
+		{"mul add", 0x3b0bb37d, 0xa0212004, 0x9bd26ab0, 0x3ca34321},
+		{"xor mul", 0x10c02f0d, 0x870fa227, 0xd4f45515, 0x91c1326a},
+		{"rotl xor", 0x24d2bae4, 0x0ffb4c9b, 0x7fdbc2f2, 0x2eddd94c},
+		{"rotr xor", 0xda39e821, 0x089c4008, 0x8b6cd8c3, 0x8a81e396},
+	}
+
+	for _, test := range tests {
+		got := test.a
+		randomMerge(&got, test.b, test.sel)
+		if got != test.want {
+			t.Errorf("%q: unexpected result -- got %08x, want %08x",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestEpochParams ensures the light cache and full dataset sizes along with the
+// seed hashes match the published Ethash values.
+func TestEpochParams(t *testing.T) {
+	tests := []struct {
+		epoch       uint64
+		cacheSize   uint64
+		datasetSize uint64
+		seed        string
+	}{{
+		epoch:       0,
+		cacheSize:   16776896,
+		datasetSize: 1073739904,
+		seed:        "0000000000000000000000000000000000000000000000000000000000000000",
+	}, {
+		epoch:       1,
+		cacheSize:   16907456,
+		datasetSize: 1082130304,
+		seed:        "290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563",
+	}, {
+		epoch:       2,
+		cacheSize:   17039296,
+		datasetSize: 1090514816,
+		seed:        "510e4e770828ddbf7f7b00ab00a9f6adaf81c0dc9cc85f1f8249c256942d61d9",
+	}}
+
+	for _, test := range tests {
+		if got := CacheSize(test.epoch); got != test.cacheSize {
+			t.Errorf("epoch %d: unexpected cache size -- got %d, want %d",
+				test.epoch, got, test.cacheSize)
+		}
+		if got := DatasetSize(test.epoch); got != test.datasetSize {
+			t.Errorf("epoch %d: unexpected dataset size -- got %d, want %d",
+				test.epoch, got, test.datasetSize)
+		}
+		if got := SeedHash(test.epoch); got != hexToHash(test.seed) {
+			t.Errorf("epoch %d: unexpected seed hash -- got %s, want %s",
+				test.epoch, got, test.seed)
+		}
+	}
+
+	if got := EpochForHeight(EpochLength - 1); got != 0 {
+		t.Errorf("unexpected epoch for last block of epoch 0: got %d", got)
+	}
+	if got := EpochForHeight(EpochLength); got != 1 {
+		t.Errorf("unexpected epoch for first block of epoch 1: got %d", got)
+	}
+}
+
+// TestMaxEpoch ensures the number of full dataset items of the max supported
+// epoch, and therefore the number of light cache items and DAG entries, fit in
+// 32-bit indices while those of the following epoch do not, and that epochs
+// beyond it are rejected.
+func TestMaxEpoch(t *testing.T) {
+	if got := datasetNumItems(MaxEpoch); got > math.MaxUint32 {
+		t.Fatalf("dataset items of max epoch %d do not fit in 32 bits: %d",
+			uint64(MaxEpoch), got)
+	}
+	if got := datasetNumItems(MaxEpoch + 1); got <= math.MaxUint32 {
+		t.Fatalf("dataset items of epoch %d after max epoch fit in 32 "+
+			"bits: %d", uint64(MaxEpoch+1), got)
+	}
+
+	if err := CheckEpoch(MaxEpoch); err != nil {
+		t.Fatalf("unexpected error for max epoch: %v", err)
+	}
+	maxHeightEpoch := EpochForHeight(math.MaxUint32)
+	for _, epoch := range []uint64{MaxEpoch + 1, maxHeightEpoch} {
+		if err := CheckEpoch(epoch); !errors.Is(err, ErrEpochTooHigh) {
+			t.Errorf("epoch %d: unexpected error -- got %v, want %v",
+				epoch, err, ErrEpochTooHigh)
+		}
+	}
+}
+
+// TestKawPowHashVectors ensures the KawPoW hash produces the results from the
+// published KawPoW test vectors.  The vectors span several program periods
+// within the first epoch along with the last blocks of the fourth epoch and the
+// first block of the fifth epoch in order to exercise both the per-period
+// program generation and the epoch transition.
+func TestKawPowHashVectors(t *testing.T) {
+	tests := []struct {
+		height     uint64
+		headerHash string
+		nonce      uint64
+		mixHash    string
+		finalHash  string
+	}{{
+		height:     0,
+		headerHash: "0000000000000000000000000000000000000000000000000000000000000000",
+		nonce:      0x0000000000000000,
+		mixHash:    "6e97b47b134fda0c7888802988e1a373affeb28bcd813b6e9a0fc669c935d03a",
+		finalHash:  "e601a7257a70dc48fccc97a7330d704d776047623b92883d77111fb36870f3d1",
+	}, {
+		height:     49,
+		headerHash: "63155f732f2bf556967f906155b510c917e48e99685ead76ea83f4eca03ab12b",
+		nonce:      0x0000000007073c07,
+		mixHash:    "d36f7e815ee09e74eceb9c96993a3d681edf2bf0921fc7bb710364042db99777",
+		finalHash:  "e7ced124598fd2500a55ad9f9f48e3569327fe50493c77a4ac9799b96efb9463",
+	}, {
+		height:     50,
+		headerHash: "9e7248f20914913a73d80a70174c331b1d34f260535ac3631d770e656b5dd922",
+		nonce:      0x00000000076e482e,
+		mixHash:    "d6dc634ae837e2785b347648ea515e25e5d8821ae0b95e1c2a9c2d497e0dcfbd",
+		finalHash:  "ab0ad7ef8d8ee317dd12d10310aceed7321d34fb263791c2de5776a6658d177e",
+	}, {
+		height:     99,
+		headerHash: "de37e1824c86d35d154cf65a88de6d9286aec4f7f10c3fc9f0fa1bcc2687188d",
+		nonce:      0x000000003917afab,
+		mixHash:    "fa706860e5e0e830d5d1d7157e5bea7f5f8a350c7c8612ac1d1fcf2974d64244",
+		finalHash:  "aa85340690f2e907054324a5021937910e15edfd1ef1577231843e7d32ec3a61",
+	}, {
+		height:     29950,
+		headerHash: "ac7b55e801511b77e11d52e9599206101550144525b5679f2dab19386f23dcce",
+		nonce:      0x005d409dbc23a62a,
+		mixHash:    "5359807b77a74878269c3a3044df8618a576ce8dc52e1c48d927d4a60e7c6b79",
+		finalHash:  "022019e5408683f7f8326b4e46b42864a3a069f17b6151e434fcaedecaadd918",
+	}, {
+		height:     29999,
+		headerHash: "e43d7e0bdc8a4a3f6e291a5ed790b9fa1a0948a2b9e33c844888690847de19f5",
+		nonce:      0x005d409dbc23a62a,
+		mixHash:    "e0396a72cb9ef85c113daaf2874fc86111a4abbb06f933ae9914c9a20ad85ca5",
+		finalHash:  "f3fac04a99619c7a3d0386b4b32ac1030c89434e3b1534944be8c79ece460d8e",
+	}, {
+		height:     30000,
+		headerHash: "d34519f72c97cae8892c277776259db3320820cb5279a299d0ef1e155e5c6454",
+		nonce:      0x005d409dbc23a62a,
+		mixHash:    "77b72714583767be308075bb98380a1ed2bfa2fad69f0392d7424fde0e5f81c0",
+		finalHash:  "31466457e54e48de01eca0f30327017316ad4bd21c98975281542c42404393bc",
+	}}
+
+	caches := map[uint64]*Cache{0: epochZeroCache()}
+	for _, test := range tests {
+		epoch := EpochForHeight(test.height)
+		cache, ok := caches[epoch]
+		if !ok {
+			cache = NewCache(epoch)
+			caches[epoch] = cache
+		}
+
+		headerHash := hexToHash(test.headerHash)
+		mixHash, finalHash := cache.lightHash(&headerHash, test.nonce,
+			test.height)
+		if mixHash != hexToHash(test.mixHash) {
+			t.Errorf("height %d nonce %d: unexpected mix hash -- got %s, "+
+				"want %s", test.height, test.nonce, mixHash, test.mixHash)
+		}
+		if finalHash != hexToHash(test.finalHash) {
+			t.Errorf("height %d nonce %d: unexpected final hash -- got %s, "+
+				"want %s", test.height, test.nonce, finalHash, test.finalHash)
+		}
+	}
+}
+
+// TestDatasetFromCache ensures the DAG entries calculated on demand from the
+// light cache match the items of the full dataset along with the L1 cache
+// being the start of the full dataset.
+func TestDatasetFromCache(t *testing.T) {
+	cache := epochZeroCache()
+
+	const numEntries = 80
+	const numItems = numEntries * dagEntryWords / cacheItemWords
+	dataset := make([]uint32, numItems*cacheItemWords)
+	cache.generateDataset(dataset, 0, numItems)
+
+	for i := 0; i < l1CacheWords; i++ {
+		if cache.l1[i] != dataset[i] {
+			t.Fatalf("mismatched L1 cache word %d -- got %08x, want %08x",
+				i, cache.l1[i], dataset[i])
+		}
+	}
+
+	h := sha3.NewLegacyKeccak512()
+	var buf [cacheItemBytes]byte
+	var entry [dagEntryWords]uint32
+	dag := &DAG{epoch: 0, cache: cache, data: dataset}
+	for i := uint32(0); i < numEntries; i++ {
+		cache.dagEntry(h, &buf, i, &entry)
+		for j := range entry {
+			if want := dag.data[i*dagEntryWords+uint32(j)]; entry[j] != want {
+				t.Fatalf("mismatched entry %d word %d -- got %08x, want %08x",
+					i, j, entry[j], want)
+			}
+		}
+	}
+}
+
+// TestKawPowHashErrors ensures hashing with the full dataset fails when the DAG
+// has not been generated or is for a different epoch than the block height.
+func TestKawPowHashErrors(t *testing.T) {
+	cache := epochZeroCache()
+
+	_, _, err := KawPowHash(Hash{}, 0, 0, NewDAGFromCache(cache))
+	if !errors.Is(err, ErrDAGNotGenerated) {
+		t.Fatalf("unexpected error hashing with an ungenerated DAG: %v", err)
+	}
+
+	dag := &DAG{epoch: 0, cache: cache, data: make([]uint32, 1)}
+	_, _, err = KawPowHash(Hash{}, 0, EpochLength, dag)
+	if !errors.Is(err, ErrWrongEpoch) {
+		t.Fatalf("unexpected error hashing with a DAG from the wrong epoch: %v",
+			err)
+	}
+}
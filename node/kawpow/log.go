@@ -0,0 +1,32 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+// Logger is the subset of the slog logger interface used by the package.  It
+// is defined here so the package does not need to depend on slog while still
+// allowing callers to provide an slog logger.
+type Logger interface {
+	Debugf(format string, params ...interface{})
+	Infof(format string, params ...interface{})
+	Warnf(format string, params ...interface{})
+}
+
+// disabledLogger is a Logger that discards all output.
+type disabledLogger struct{}
+
+func (disabledLogger) Debugf(string, ...interface{}) {}
+func (disabledLogger) Infof(string, ...interface{})  {}
+func (disabledLogger) Warnf(string, ...interface{})  {}
+
+// log is a logger that is initialized with no output filters.  This
+// means the package will not perform any logging by default until the caller
+// requests it.
+// The default amount of logging is none.
+var log Logger = disabledLogger{}
+
+// UseLogger uses a specified Logger to output package logging info.
+func UseLogger(logger Logger) {
+	log = logger
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package kawpow
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the first size bytes of the provided file into memory since
+// memory mapping is not supported on this platform.  The returned function to
+// release the bytes is a no-op provided for parity with platforms that support
+// memory mapping.
+func mapFile(f *os.File, size int64) ([]byte, func() error, error) {
+	b := make([]byte, size)
+	if _, err := io.ReadFull(io.NewSectionReader(f, 0, size), b); err != nil {
+		return nil, nil, err
+	}
+	return b, func() error { return nil }, nil
+}
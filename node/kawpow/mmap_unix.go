@@ -0,0 +1,24 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package kawpow
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile memory maps the first size bytes of the provided file read only and
+// returns them along with a function to unmap them.  The file may be closed
+// once the mapping is created.
+func mapFile(f *os.File, size int64) ([]byte, func() error, error) {
+	b, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ,
+		syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, func() error { return syscall.Munmap(b) }, nil
+}
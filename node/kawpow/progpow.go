@@ -0,0 +1,414 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+import (
+	"math/bits"
+)
+
+const (
+	// progpowPeriodLength is the number of blocks for which a given random
+	// ProgPoW program remains the same.  KawPoW uses a period of 3 blocks.
+	progpowPeriodLength = 3
+
+	// progpowNumRegs is the number of 32-bit registers each lane works on.
+	progpowNumRegs = 32
+
+	// progpowNumLanes is the number of parallel lanes that coordinate to
+	// calculate a single hash instance.
+	progpowNumLanes = 16
+
+	// progpowNumCacheAccesses is the number of random L1 cache accesses per
+	// round.
+	progpowNumCacheAccesses = 11
+
+	// progpowNumMathOperations is the number of random math operations per
+	// round.
+	progpowNumMathOperations = 18
+
+	// progpowNumRounds is the number of DAG accesses, and therefore rounds,
+	// performed per hash.
+	progpowNumRounds = 64
+
+	// progpowNumWordsPerLane is the number of 32-bit words of each 2048-bit
+	// DAG entry that are consumed by a single lane.
+	progpowNumWordsPerLane = dagEntryWords / progpowNumLanes
+
+	// l1CacheSize is the size of the L1 cache in bytes.  The L1 cache is
+	// the first part of the full dataset.
+	l1CacheSize = 16 * 1024
+
+	// l1CacheWords is the number of 32-bit words in the L1 cache.
+	l1CacheWords = l1CacheSize / 4
+
+	// fnvOffsetBasis is the 32-bit FNV offset basis.
+	fnvOffsetBasis = 0x811c9dc5
+)
+
+// ravencoinKawPoW is the padding absorbed into both Keccak-f[800] passes of
+// KawPoW.  It is the string "rAVENCOINKAWPOW" as one character per 32-bit
+// word, exactly as used by the reference implementation.
+var ravencoinKawPoW = [15]uint32{
+	0x00000072, 0x00000041, 0x00000056, 0x00000045, 0x0000004e,
+	0x00000043, 0x0000004f, 0x00000049, 0x0000004e, 0x0000004b,
+	0x00000041, 0x00000057, 0x00000050, 0x0000004f, 0x00000057,
+}
+
+// keccakf800RoundConstants are the round constants for Keccak-f[800].  They
+// are the low 32 bits of the Keccak-f[1600] round constants.
+var keccakf800RoundConstants = [22]uint32{
+	0x00000001, 0x00008082, 0x0000808a, 0x80008000, 0x0000808b, 0x80000001,
+	0x80008081, 0x00008009, 0x0000008a, 0x00000088, 0x80008009, 0x8000000a,
+	0x8000808b, 0x0000008b, 0x00008089, 0x00008003, 0x00008002, 0x00000080,
+	0x0000800a, 0x8000000a, 0x80008081, 0x00008080,
+}
+
+// keccakf800RotationOffsets are the rho step rotation offsets for each lane
+// of the state indexed by x+5*y, reduced modulo the 32-bit lane size.
+var keccakf800RotationOffsets = [25]int{
+	0, 1, 62 % 32, 28, 27,
+	36 % 32, 44 % 32, 6, 55 % 32, 20,
+	3, 10, 43 % 32, 25, 39 % 32,
+	41 % 32, 45 % 32, 15, 21, 8,
+	18, 2, 61 % 32, 56 % 32, 14,
+}
+
+// keccakF800 applies the Keccak-f[800] permutation to the provided state.
+func keccakF800(a *[25]uint32) {
+	var b [25]uint32
+	var c, d [5]uint32
+	for round := 0; round < len(keccakf800RoundConstants); round++ {
+		// Theta.
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d[x] = c[(x+4)%5] ^ bits.RotateLeft32(c[(x+1)%5], 1)
+		}
+		for i := 0; i < 25; i++ {
+			a[i] ^= d[i%5]
+		}
+
+		// Rho and pi.
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				rotated := bits.RotateLeft32(a[x+5*y],
+					keccakf800RotationOffsets[x+5*y])
+				b[y+5*((2*x+3*y)%5)] = rotated
+			}
+		}
+
+		// Chi.
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[y+x] = b[y+x] ^ (^b[y+(x+1)%5] & b[y+(x+2)%5])
+			}
+		}
+
+		// Iota.
+		a[0] ^= keccakf800RoundConstants[round]
+	}
+}
+
+// kiss99 is the KISS99 pseudorandom number generator used by ProgPoW.
+type kiss99 struct {
+	z, w, jsr, jcong uint32
+}
+
+// next returns the next pseudorandom value and advances the generator.
+func (k *kiss99) next() uint32 {
+	k.z = 36969*(k.z&65535) + (k.z >> 16)
+	k.w = 18000*(k.w&65535) + (k.w >> 16)
+	mwc := (k.z << 16) + k.w
+	k.jsr ^= k.jsr << 17
+	k.jsr ^= k.jsr >> 13
+	k.jsr ^= k.jsr << 5
+	k.jcong = 69069*k.jcong + 1234567
+	return (mwc ^ k.jcong) + k.jsr
+}
+
+// fnv1a returns the 32-bit FNV-1a combination of the provided values.
+func fnv1a(u, v uint32) uint32 {
+	return (u ^ v) * fnvPrime
+}
+
+// mixRNGState houses the state of the random program used for a single
+// ProgPoW period.  It consists of the KISS99 generator along with random
+// permutations of the mix register destinations and sources.
+type mixRNGState struct {
+	rng        kiss99
+	dstCounter int
+	dstSeq     [progpowNumRegs]uint32
+	srcCounter int
+	srcSeq     [progpowNumRegs]uint32
+}
+
+// newMixRNGState returns the program state for the given 64-bit program seed
+// split into its low and high 32-bit words.
+func newMixRNGState(seedLo, seedHi uint32) mixRNGState {
+	z := fnv1a(fnvOffsetBasis, seedLo)
+	w := fnv1a(z, seedHi)
+	jsr := fnv1a(w, seedLo)
+	jcong := fnv1a(jsr, seedHi)
+
+	s := mixRNGState{rng: kiss99{z: z, w: w, jsr: jsr, jcong: jcong}}
+	for i := uint32(0); i < progpowNumRegs; i++ {
+		s.dstSeq[i] = i
+		s.srcSeq[i] = i
+	}
+
+	// Create random permutations of the mix destinations and sources using
+	// a Fisher-Yates shuffle.
+	for i := uint32(progpowNumRegs); i > 1; i-- {
+		j := s.rng.next() % i
+		s.dstSeq[i-1], s.dstSeq[j] = s.dstSeq[j], s.dstSeq[i-1]
+		j = s.rng.next() % i
+		s.srcSeq[i-1], s.srcSeq[j] = s.srcSeq[j], s.srcSeq[i-1]
+	}
+	return s
+}
+
+// nextDst returns the next mix destination register.
+func (s *mixRNGState) nextDst() uint32 {
+	dst := s.dstSeq[s.dstCounter%progpowNumRegs]
+	s.dstCounter++
+	return dst
+}
+
+// nextSrc returns the next mix source register.
+func (s *mixRNGState) nextSrc() uint32 {
+	src := s.srcSeq[s.srcCounter%progpowNumRegs]
+	s.srcCounter++
+	return src
+}
+
+// randomMath performs one of the ProgPoW random math operations on the
+// provided values as chosen by the selector.
+func randomMath(a, b, selector uint32) uint32 {
+	switch selector % 11 {
+	case 1:
+		return a * b
+	case 2:
+		return uint32((uint64(a) * uint64(b)) >> 32)
+	case 3:
+		return min(a, b)
+	case 4:
+		return bits.RotateLeft32(a, int(b&31))
+	case 5:
+		return bits.RotateLeft32(a, -int(b&31))
+	case 6:
+		return a & b
+	case 7:
+		return a | b
+	case 8:
+		return a ^ b
+	case 9:
+		return uint32(bits.LeadingZeros32(a) + bits.LeadingZeros32(b))
+	case 10:
+		return uint32(bits.OnesCount32(a) + bits.OnesCount32(b))
+	default:
+		return a + b
+	}
+}
+
+// randomMerge merges b into a using one of the ProgPoW merge operations as
+// chosen by the selector.  Since a is assumed to have high entropy, only
+// operations that retain its entropy even when b has low entropy are used.
+func randomMerge(a *uint32, b, selector uint32) {
+	x := int((selector>>16)%31 + 1)
+	switch selector % 4 {
+	case 0:
+		*a = (*a * 33) + b
+	case 1:
+		*a = (*a ^ b) * 33
+	case 2:
+		*a = bits.RotateLeft32(*a, x) ^ b
+	case 3:
+		*a = bits.RotateLeft32(*a, -x) ^ b
+	}
+}
+
+// mixArray is the ProgPoW mix state consisting of the registers of every lane.
+type mixArray [progpowNumLanes][progpowNumRegs]uint32
+
+// dagLookupFn is a function that returns the 2048-bit DAG entry at the given
+// index as 32-bit words.
+type dagLookupFn func(index uint32, entry *[dagEntryWords]uint32)
+
+// initMix returns the initial ProgPoW mix state for the given hash seed.
+func initMix(seedLo, seedHi uint32) mixArray {
+	z := fnv1a(fnvOffsetBasis, seedLo)
+	w := fnv1a(z, seedHi)
+
+	var mix mixArray
+	for l := uint32(0); l < progpowNumLanes; l++ {
+		jsr := fnv1a(w, l)
+		jcong := fnv1a(jsr, l)
+		rng := kiss99{z: z, w: w, jsr: jsr, jcong: jcong}
+		for r := range mix[l] {
+			mix[l][r] = rng.next()
+		}
+	}
+	return mix
+}
+
+// progpowRound performs a single ProgPoW round over the mix state.  The
+// program state is passed by value so every round replays the same random
+// program for the period.
+func progpowRound(l1 *[l1CacheWords]uint32, numEntries uint32, r uint32,
+	mix *mixArray, state mixRNGState, lookup dagLookupFn) {
+
+	var entry [dagEntryWords]uint32
+	lookup(mix[r%progpowNumLanes][0]%numEntries, &entry)
+
+	const maxOperations = max(progpowNumCacheAccesses, progpowNumMathOperations)
+	for i := 0; i < maxOperations; i++ {
+		// Random access to cached memory.
+		if i < progpowNumCacheAccesses {
+			src := state.nextSrc()
+			dst := state.nextDst()
+			sel := state.rng.next()
+			for l := 0; l < progpowNumLanes; l++ {
+				offset := mix[l][src] % l1CacheWords
+				randomMerge(&mix[l][dst], l1[offset], sel)
+			}
+		}
+
+		// Random math.
+		if i < progpowNumMathOperations {
+			// Generate 2 unique random sources.
+			srcRnd := state.rng.next() % (progpowNumRegs * (progpowNumRegs - 1))
+			src1 := srcRnd % progpowNumRegs
+			src2 := srcRnd / progpowNumRegs
+			if src2 >= src1 {
+				src2++
+			}
+
+			sel1 := state.rng.next()
+			dst := state.nextDst()
+			sel2 := state.rng.next()
+			for l := 0; l < progpowNumLanes; l++ {
+				data := randomMath(mix[l][src1], mix[l][src2], sel1)
+				randomMerge(&mix[l][dst], data, sel2)
+			}
+		}
+	}
+
+	// DAG access pattern.
+	var dsts, sels [progpowNumWordsPerLane]uint32
+	for i := 0; i < progpowNumWordsPerLane; i++ {
+		if i != 0 {
+			dsts[i] = state.nextDst()
+		}
+		sels[i] = state.rng.next()
+	}
+
+	// DAG access.
+	for l := uint32(0); l < progpowNumLanes; l++ {
+		offset := ((l ^ r) % progpowNumLanes) * progpowNumWordsPerLane
+		for i := uint32(0); i < progpowNumWordsPerLane; i++ {
+			randomMerge(&mix[l][dsts[i]], entry[offset+i], sels[i])
+		}
+	}
+}
+
+// progpowHashMix runs the ProgPoW main loop for the given block height and
+// hash seed and returns the resulting 256-bit mix digest as words.
+func progpowHashMix(l1 *[l1CacheWords]uint32, numEntries uint32, height uint64,
+	seedLo, seedHi uint32, lookup dagLookupFn) [8]uint32 {
+
+	mix := initMix(seedLo, seedHi)
+
+	period := height / progpowPeriodLength
+	state := newMixRNGState(uint32(period), uint32(period>>32))
+	for r := uint32(0); r < progpowNumRounds; r++ {
+		progpowRound(l1, numEntries, r, &mix, state, lookup)
+	}
+
+	// Reduce the mix data to a single per-lane result.
+	var laneHash [progpowNumLanes]uint32
+	for l := 0; l < progpowNumLanes; l++ {
+		laneHash[l] = fnvOffsetBasis
+		for r := 0; r < progpowNumRegs; r++ {
+			laneHash[l] = fnv1a(laneHash[l], mix[l][r])
+		}
+	}
+
+	// Reduce all lanes to a single 256-bit result.
+	var digest [8]uint32
+	for i := range digest {
+		digest[i] = fnvOffsetBasis
+	}
+	for l := 0; l < progpowNumLanes; l++ {
+		digest[l%8] = fnv1a(digest[l%8], laneHash[l])
+	}
+	return digest
+}
+
+// kawpowSeed performs the initial Keccak-f[800] pass of KawPoW which absorbs
+// the header hash and nonce along with the KawPoW padding.  The result seeds
+// the ProgPoW loop and is also absorbed by the final pass.
+func kawpowSeed(headerHash *Hash, nonce uint64) [8]uint32 {
+	var state [25]uint32
+	for i := 0; i < 8; i++ {
+		state[i] = le.Uint32(headerHash[i*4:])
+	}
+	state[8] = uint32(nonce)
+	state[9] = uint32(nonce >> 32)
+	copy(state[10:], ravencoinKawPoW[:])
+	keccakF800(&state)
+
+	var seed [8]uint32
+	copy(seed[:], state[:8])
+	return seed
+}
+
+// kawpowFinal performs the final Keccak-f[800] pass of KawPoW which absorbs the
+// result of the initial pass, the mix digest, and the KawPoW padding to produce
+// the final hash.
+func kawpowFinal(seed *[8]uint32, digest *[8]uint32) Hash {
+	var state [25]uint32
+	copy(state[:8], seed[:])
+	copy(state[8:16], digest[:])
+	copy(state[16:], ravencoinKawPoW[:9])
+	keccakF800(&state)
+
+	var finalHash Hash
+	for i := 0; i < 8; i++ {
+		le.PutUint32(finalHash[i*4:], state[i])
+	}
+	return finalHash
+}
+
+// kawpowHash computes the KawPoW mix digest and final hash for the given
+// header hash, nonce, and block height using the provided L1 cache and DAG
+// lookup function.
+func kawpowHash(l1 *[l1CacheWords]uint32, numEntries uint32, headerHash *Hash,
+	nonce uint64, height uint64, lookup dagLookupFn) (Hash, Hash) {
+
+	seed := kawpowSeed(headerHash, nonce)
+	digest := progpowHashMix(l1, numEntries, height, seed[0], seed[1], lookup)
+
+	var mixHash Hash
+	for i := 0; i < 8; i++ {
+		le.PutUint32(mixHash[i*4:], digest[i])
+	}
+	return mixHash, kawpowFinal(&seed, &digest)
+}
+
+// finalHashFromMix computes the KawPoW final hash for the given header hash and
+// nonce from the provided mix hash without running the ProgPoW loop.  The
+// result only matches the actual final hash when the mix hash is the one
+// calculated from the same inputs, however, it only requires two Keccak-f[800]
+// passes, so it serves as a cheap filter for solutions that can not possibly
+// satisfy a target.
+func finalHashFromMix(headerHash *Hash, nonce uint64, mixHash *Hash) Hash {
+	seed := kawpowSeed(headerHash, nonce)
+	var digest [8]uint32
+	for i := 0; i < 8; i++ {
+		digest[i] = le.Uint32(mixHash[i*4:])
+	}
+	return kawpowFinal(&seed, &digest)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// CompactToTarget converts the compact representation of a target difficulty
+// used in block headers to the target as a big integer.  Negative values are
+// returned as is so they may be rejected by the caller.
+func CompactToTarget(compact uint32) *big.Int {
+	// Extract the mantissa, sign bit, and exponent.
+	mantissa := compact & 0x007fffff
+	isNegative := compact&0x00800000 != 0
+	exponent := uint(compact >> 24)
+
+	// Since the base for the exponent is 256, the exponent can be treated as
+	// the number of bytes to represent the full 256-bit number.
+	var bn *big.Int
+	if exponent <= 3 {
+		mantissa >>= 8 * (3 - exponent)
+		bn = big.NewInt(int64(mantissa))
+	} else {
+		bn = big.NewInt(int64(mantissa))
+		bn.Lsh(bn, 8*(exponent-3))
+	}
+	if isNegative {
+		bn = bn.Neg(bn)
+	}
+	return bn
+}
+
+// checkTarget ensures the provided final hash, which is interpreted as a
+// big-endian number, is less than or equal to the target difficulty.
+func checkTarget(finalHash *Hash, target *big.Int) error {
+	if target.Sign() <= 0 {
+		str := fmt.Sprintf("target difficulty of %064x is not positive", target)
+		return makeError(ErrBadTarget, str)
+	}
+	if new(big.Int).SetBytes(finalHash[:]).Cmp(target) > 0 {
+		str := fmt.Sprintf("final hash %s is higher than expected max of "+
+			"%064x", finalHash, target)
+		return makeError(ErrHighHash, str)
+	}
+	return nil
+}
+
+// checkMixHash ensures the provided mix hash matches the calculated one.
+func checkMixHash(mixHash, calculated *Hash) error {
+	if *mixHash != *calculated {
+		str := fmt.Sprintf("mix hash %s does not match calculated mix hash %s",
+			mixHash, calculated)
+		return makeError(ErrBadMixHash, str)
+	}
+	return nil
+}
+
+// VerifyTarget ensures the final hash calculated from the provided claimed mix
+// hash, which is cheap, is less than or equal to the target difficulty.  It
+// does not require a light cache or full dataset and therefore may be used to
+// filter solutions before any are requested for the epoch of the solution.
+//
+// Note that passing this check does not mean the solution is valid since the
+// claimed mix hash has not been verified.  See VerifyLight for the complete
+// verification.
+//
+// The returned error is of type Error with one of the ErrorKind values when
+// the solution does not satisfy the target.
+func VerifyTarget(headerHash Hash, nonce uint64, mixHash Hash, target *big.Int) error {
+	claimedFinalHash := finalHashFromMix(&headerHash, nonce, &mixHash)
+	return checkTarget(&claimedFinalHash, target)
+}
+
+// VerifyLight verifies the KawPoW solution given by the nonce and mix hash for
+// the provided header hash and block height using only the light cache, which
+// must be for the epoch of the height.  The mix hash must match the one
+// calculated from the other inputs and the final hash must be less than or
+// equal to the target difficulty.
+//
+// The final hash is first calculated from the claimed mix hash, which is cheap,
+// and checked against the target before the mix hash is recalculated with the
+// much more expensive ProgPoW loop.  This means solutions that do not satisfy
+// the target are rejected with ErrHighHash, regardless of their mix hash,
+// without having to calculate any DAG entries, while producing a mix hash that
+// passes the filter requires the same amount of work as a valid solution.
+//
+// The returned error is of type Error with one of the ErrorKind values when
+// the solution is invalid.
+func VerifyLight(cache *Cache, headerHash Hash, nonce, height uint64, mixHash Hash, target *big.Int) error {
+	if epoch := EpochForHeight(height); epoch != cache.epoch {
+		str := fmt.Sprintf("light cache for epoch %d can not be used for "+
+			"block height %d in epoch %d", cache.epoch, height, epoch)
+		return makeError(ErrWrongEpoch, str)
+	}
+	if err := VerifyTarget(headerHash, nonce, mixHash, target); err != nil {
+		return err
+	}
+
+	// The final hash calculated from the claimed mix hash is the actual final
+	// hash when the mix hash matches, so there is no need to check it against
+	// the target again.
+	calcMixHash, _ := cache.lightHash(&headerHash, nonce, height)
+	return checkMixHash(&mixHash, &calcMixHash)
+}
+
+// Verify verifies a KawPoW solution using the full dataset of the provided DAG.
+// The mix hash must match the one calculated from the other inputs and the
+// final hash must be less than or equal to the target difficulty encoded by
+// the provided compact bits.  See VerifyLight for details regarding the cheap
+// filter that is applied before the mix hash is recalculated.
+func Verify(headerHash Hash, nonce uint64, mixHash []byte, bits uint32, height int64, dag *DAG) bool {
+	if len(mixHash) != HashSize || height < 0 {
+		return false
+	}
+
+	claimedFinalHash := finalHashFromMix(&headerHash, nonce, (*Hash)(mixHash))
+	if checkTarget(&claimedFinalHash, CompactToTarget(bits)) != nil {
+		return false
+	}
+	calcMixHash, _, err := KawPowHash(headerHash, nonce, uint64(height), dag)
+	if err != nil {
+		return false
+	}
+	return checkMixHash((*Hash)(mixHash), &calcMixHash) == nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package kawpow
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestCompactToTarget ensures converting from the compact representation used
+// for target difficulties to big integers produces the expected results.
+func TestCompactToTarget(t *testing.T) {
+	tests := []struct {
+		name    string // test description
+		compact uint32 // compact target to convert
+		want    string // expected target in hex
+	}{{
+		name:    "mainnet block 1 bits",
+		compact: 0x1b01ffff,
+		want:    "1ffff000000000000000000000000000000000000000000000000",
+	}, {
+		name:    "exponent below mantissa size",
+		compact: 0x02123456,
+		want:    "1234",
+	}, {
+		name:    "negative",
+		compact: 0x04923456,
+		want:    "-12345600",
+	}, {
+		name:    "zero",
+		compact: 0,
+		want:    "0",
+	}}
+
+	for _, test := range tests {
+		want, ok := new(big.Int).SetString(test.want, 16)
+		if !ok {
+			t.Fatalf("%q: invalid expected target", test.name)
+		}
+		if got := CompactToTarget(test.compact); got.Cmp(want) != 0 {
+			t.Errorf("%q: unexpected result -- got %x, want %x", test.name,
+				got, want)
+		}
+	}
+}
+
+// TestVerifyLight ensures verifying solutions with the light cache accepts
+// valid solutions and rejects invalid ones with the expected error kind.
+func TestVerifyLight(t *testing.T) {
+	cache := epochZeroCache()
+
+	// The first published test vector is used as the solution.
+	var headerHash Hash
+	const nonce = 0
+	mixHash := hexToHash("6e97b47b134fda0c7888802988e1a373affeb28bcd813b6e9a0fc669c935d03a")
+	finalHash := hexToHash("e601a7257a70dc48fccc97a7330d704d776047623b92883d77111fb36870f3d1")
+	exactTarget := new(big.Int).SetBytes(finalHash[:])
+	maxTarget := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256),
+		big.NewInt(1))
+	badMixHash := mixHash
+	badMixHash[0] ^= 0x01
+
+	// The final hash calculated from the mismatched mix hash minus one is used
+	// as the target so the cheap filter rejects it.
+	badFinalHash := finalHashFromMix(&headerHash, nonce, &badMixHash)
+	badFinalTarget := new(big.Int).SetBytes(badFinalHash[:])
+	badFinalTarget.Sub(badFinalTarget, big.NewInt(1))
+
+	tests := []struct {
+		name    string   // test description
+		height  uint64   // block height of the solution
+		mixHash Hash     // mix hash of the solution
+		target  *big.Int // target difficulty
+		err     error    // expected error
+	}{{
+		name:    "final hash equal to target",
+		mixHash: mixHash,
+		target:  exactTarget,
+	}, {
+		name:    "final hash below target",
+		mixHash: mixHash,
+		target:  new(big.Int).Add(exactTarget, big.NewInt(1)),
+	}, {
+		name:    "final hash above target",
+		mixHash: mixHash,
+		target:  new(big.Int).Sub(exactTarget, big.NewInt(1)),
+		err:     ErrHighHash,
+	}, {
+		name:    "mismatched mix hash that passes the target filter",
+		mixHash: badMixHash,
+		target:  maxTarget,
+		err:     ErrBadMixHash,
+	}, {
+		name:    "mismatched mix hash rejected by the target filter",
+		mixHash: badMixHash,
+		target:  badFinalTarget,
+		err:     ErrHighHash,
+	}, {
+		name:    "zero target",
+		mixHash: mixHash,
+		target:  new(big.Int),
+		err:     ErrBadTarget,
+	}, {
+		name:    "height in another epoch",
+		height:  EpochLength,
+		mixHash: mixHash,
+		target:  exactTarget,
+		err:     ErrWrongEpoch,
+	}}
+
+	for _, test := range tests {
+		err := VerifyLight(cache, headerHash, nonce, test.height, test.mixHash,
+			test.target)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name, err,
+				test.err)
+		}
+		var kerr Error
+		if test.err != nil && !errors.As(err, &kerr) {
+			t.Errorf("%q: error is not a kawpow.Error: %T", test.name, err)
+		}
+	}
+}
+
+// TestFinalHashFromMix ensures calculating the final hash from the mix hash
+// produces the same final hash as the full calculation.
+func TestFinalHashFromMix(t *testing.T) {
+	cache := epochZeroCache()
+
+	headerHash := hexToHash("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff")
+	for _, nonce := range []uint64{0, 1, 0x123456789abcdef0} {
+		mixHash, finalHash := cache.lightHash(&headerHash, nonce, 1)
+		if got := finalHashFromMix(&headerHash, nonce, &mixHash); got != finalHash {
+			t.Errorf("nonce %d: unexpected final hash -- got %s, want %s",
+				nonce, got, finalHash)
+		}
+	}
+}
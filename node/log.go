@@ -20,8 +20,10 @@ import (
 	"github.com/kdsmith18542/vigil/internal/mempool"
 	"github.com/kdsmith18542/vigil/internal/mining"
 	"github.com/kdsmith18542/vigil/internal/mining/cpuminer"
+	"github.com/kdsmith18542/vigil/internal/mining/stratum"
 	"github.com/kdsmith18542/vigil/internal/netsync"
 	"github.com/kdsmith18542/vigil/internal/rpcserver"
+	"github.com/kdsmith18542/vigil/kawpow"
 	"github.com/kdsmith18542/vigil/mixing/mixpool"
 	"github.com/kdsmith18542/vigil/peer/v3"
 	"github.com/kdsmith18542/vigil/txscript/v4"
@@ -68,6 +70,7 @@ var (
 	discLog = backendLog.Logger("DISC")
 	feesLog = backendLog.Logger("FEES")
 	indxLog = backendLog.Logger("INDX")
+	kawpLog = backendLog.Logger("KAWP")
 	minrLog = backendLog.Logger("MINR")
 	mixpLog = backendLog.Logger("MIXP")
 	peerLog = backendLog.Logger("PEER")
@@ -89,10 +92,12 @@ func init() {
 	database.UseLogger(bcdbLog)
 	fees.UseLogger(feesLog)
 	indexers.UseLogger(indxLog)
+	kawpow.UseLogger(kawpLog)
 	mempool.UseLogger(txmpLog)
 	mining.UseLogger(minrLog)
 	mixpool.UseLogger(mixpLog)
 	cpuminer.UseLogger(minrLog)
+	stratum.UseLogger(minrLog)
 	peer.UseLogger(peerLog)
 	rpcserver.UseLogger(rpcsLog)
 	stake.UseLogger(stkeLog)
@@ -111,6 +116,7 @@ var subsystemLoggers = map[string]slog.Logger{
 	"DISC": discLog,
 	"FEES": feesLog,
 	"INDX": indxLog,
+	"KAWP": kawpLog,
 	"MINR": minrLog,
 	"MIXP": mixpLog,
 	"PEER": peerLog,
@@ -0,0 +1,156 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kdsmith18542/vigil/internal/metrics"
+	"github.com/kdsmith18542/vigil/kawpow"
+)
+
+var (
+	// peersGauge tracks the number of connected peers partitioned by the
+	// direction of the connection.  Persistent peers are outbound.
+	peersGauge = metrics.NewGaugeVec("vgld_peers",
+		"Number of connected peers by connection direction.", "direction")
+
+	// bytesReceivedCounter and bytesSentCounter track the total number of
+	// bytes received from and sent to all peers, respectively.
+	bytesReceivedCounter = metrics.NewCounter("vgld_net_bytes_received_total",
+		"Total number of bytes received from peers.")
+	bytesSentCounter = metrics.NewCounter("vgld_net_bytes_sent_total",
+		"Total number of bytes sent to peers.")
+)
+
+// registerServerMetrics registers the metrics that describe the state owned by
+// the provided server, such as its transaction pool, with the default metrics
+// registry.  It must only be called once the server has been created.
+func registerServerMetrics(s *server) {
+	metrics.NewHistogramFunc("vgld_mempool_fee_rate_atoms_per_kb",
+		"Fee rate of the transactions in the main transaction pool in "+
+			"atoms/kB.",
+		metrics.ExponentialBuckets(1000, 2, 14), s.txMemPool.ObserveFeeRates)
+
+	// The KawPoW package is a separate module that is unaware of the metrics
+	// package, so expose the statistics of its default epoch manager here.
+	kawpowStat := func(fn func(s *kawpow.EpochManagerStats) float64) func() float64 {
+		return func() float64 {
+			stats := kawpow.DefaultEpochManager().Stats()
+			return fn(&stats)
+		}
+	}
+	metrics.NewGaugeFunc("vgld_kawpow_epoch",
+		"Current KawPoW epoch.",
+		kawpowStat(func(stats *kawpow.EpochManagerStats) float64 {
+			return float64(stats.CurrentEpoch)
+		}))
+	metrics.NewCounterFunc("vgld_kawpow_cache_generations_total",
+		"Total number of KawPoW light caches generated.",
+		kawpowStat(func(stats *kawpow.EpochManagerStats) float64 {
+			return float64(stats.CacheGenerations)
+		}))
+	metrics.NewGaugeFunc("vgld_kawpow_cache_generation_seconds",
+		"Time taken to generate the most recent KawPoW light cache.",
+		kawpowStat(func(stats *kawpow.EpochManagerStats) float64 {
+			return stats.LastCacheGenTime.Seconds()
+		}))
+	metrics.NewCounterFunc("vgld_kawpow_dag_generations_total",
+		"Total number of KawPoW full datasets (DAGs) generated.",
+		kawpowStat(func(stats *kawpow.EpochManagerStats) float64 {
+			return float64(stats.DAGGenerations)
+		}))
+	metrics.NewGaugeFunc("vgld_kawpow_dag_generation_seconds",
+		"Time taken to generate the most recent KawPoW full dataset (DAG).",
+		kawpowStat(func(stats *kawpow.EpochManagerStats) float64 {
+			return stats.LastDAGGenTime.Seconds()
+		}))
+	metrics.NewGaugeFunc("vgld_kawpow_memory_bytes",
+		"Memory used by the resident KawPoW light caches and full dataset.",
+		kawpowStat(func(stats *kawpow.EpochManagerStats) float64 {
+			return float64(stats.CacheMemory + stats.DAGMemory)
+		}))
+}
+
+// metricsServer provides facilities for starting and stopping an HTTP server
+// that serves the metrics registered with the default metrics registry in the
+// Prometheus text exposition format.
+type metricsServer struct {
+	wg     sync.WaitGroup
+	server *http.Server
+}
+
+// Start binds listeners to the provided addresses and launches an HTTP server
+// that serves metrics at /metrics in the background using those listeners.
+// The addresses are expected to already be normalized.  An error is returned
+// when any of the listeners fail to bind.
+//
+// It is the caller's responsibility to call the Stop method to shutdown the
+// server.
+func (s *metricsServer) Start(listenAddrs []string) error {
+	// Expand the provided listen addresses into relevant IPv4 and IPv6
+	// net.Addrs to listen on with TCP.  This includes properly detecting
+	// addresses which apply to "all interfaces" and adds the address as both
+	// IPv4 and IPv6.
+	netAddrs, err := parseListeners(listenAddrs)
+	if err != nil {
+		return err
+	}
+
+	listeners := make([]net.Listener, 0, len(netAddrs))
+	for _, addr := range netAddrs {
+		listener, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return fmt.Errorf("unable to listen on %s: %w", addr, err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.DefaultRegistry)
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: time.Second * 3,
+	}
+	for _, listener := range listeners {
+		vgldLog.Infof("Metrics server listening on %s", listener.Addr())
+		s.wg.Add(1)
+		go func(listener net.Listener) {
+			defer s.wg.Done()
+
+			err := s.server.Serve(listener)
+			if !errors.Is(err, http.ErrServerClosed) {
+				vgldLog.Errorf("Metrics server listening on %s exited with "+
+					"unexpected error: %v", listener.Addr(), err)
+			}
+		}(listener)
+	}
+
+	return nil
+}
+
+// Stop immediately closes the active listeners and any connections to the
+// metrics server.
+//
+// It has no effect when the server is not running.
+func (s *metricsServer) Stop() {
+	if s.server == nil {
+		return
+	}
+
+	if err := s.server.Close(); err != nil {
+		vgldLog.Errorf("Metrics server stopped with unexpected error: %v", err)
+	}
+	s.wg.Wait()
+	s.server = nil
+}
@@ -137,8 +137,24 @@ func TestVigilNetworkSettings(t *testing.T) {
 	checkGenesisBlockRespectsNetworkPowLimit(t, simNetParams)
 	checkGenesisBlockRespectsNetworkPowLimit(t, regNetParams)
 
-	checkAddressPrefixesAreConsistent(t, "Pm", mainNetParams)
+	checkAddressPrefixesAreConsistent(t, "Pv", mainNetParams)
 	checkAddressPrefixesAreConsistent(t, "Pt", testNet3Params)
 	checkAddressPrefixesAreConsistent(t, "Ps", simNetParams)
 	checkAddressPrefixesAreConsistent(t, "Pr", regNetParams)
 }
+
+// TestTreasuryKeys ensures the network parameters are only accepted when they
+// have an organization script and Vigiliteia keys, which the main network does
+// not have until the mainnet key ceremony.
+func TestTreasuryKeys(t *testing.T) {
+	if err := checkTreasuryKeys(chaincfg.MainNetParams()); err == nil {
+		t.Fatal("main network parameters without keys were accepted")
+	}
+	for _, params := range []*chaincfg.Params{chaincfg.TestNet3Params(),
+		chaincfg.SimNetParams(), chaincfg.RegNetParams()} {
+
+		if err := checkTreasuryKeys(params); err != nil {
+			t.Fatalf("%s: unexpected error: %v", params.Name, err)
+		}
+	}
+}
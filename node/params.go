@@ -6,6 +6,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/kdsmith18542/vigil/chaincfg/v3"
 )
 
@@ -47,3 +49,15 @@ var regNetParams = params{
 	Params:  chaincfg.RegNetParams(),
 	rpcPort: "18656",
 }
+
+// checkTreasuryKeys ensures the organization script and Vigiliteia keys of the
+// provided network parameters are set.  The main network values are unset
+// until they are produced by the mainnet key ceremony, so this prevents running
+// on it while the treasury rules can't be enforced.
+func checkTreasuryKeys(params *chaincfg.Params) error {
+	if len(params.OrganizationPkScript) == 0 || len(params.PiKeys) == 0 {
+		return fmt.Errorf("the %s network organization script and Vigiliteia "+
+			"keys have not been set", params.Name)
+	}
+	return nil
+}
@@ -336,6 +336,12 @@ func TestPeerListeners(t *testing.T) {
 			OnAddr: func(p *Peer, msg *wire.MsgAddr) {
 				ok <- msg
 			},
+			OnGetAddrV2: func(p *Peer, msg *wire.MsgGetAddrV2) {
+				ok <- msg
+			},
+			OnAddrV2: func(p *Peer, msg *wire.MsgAddrV2) {
+				ok <- msg
+			},
 			OnPing: func(p *Peer, msg *wire.MsgPing) {
 				ok <- msg
 			},
@@ -351,6 +357,15 @@ func TestPeerListeners(t *testing.T) {
 			OnBlock: func(p *Peer, msg *wire.MsgBlock, buf []byte) {
 				ok <- msg
 			},
+			OnCmpctBlock: func(p *Peer, msg *wire.MsgCmpctBlock) {
+				ok <- msg
+			},
+			OnGetBlockTxn: func(p *Peer, msg *wire.MsgGetBlockTxn) {
+				ok <- msg
+			},
+			OnBlockTxn: func(p *Peer, msg *wire.MsgBlockTxn) {
+				ok <- msg
+			},
 			OnInv: func(p *Peer, msg *wire.MsgInv) {
 				ok <- msg
 			},
@@ -470,6 +485,14 @@ func TestPeerListeners(t *testing.T) {
 			"OnAddr",
 			wire.NewMsgAddr(),
 		},
+		{
+			"OnGetAddrV2",
+			wire.NewMsgGetAddrV2(),
+		},
+		{
+			"OnAddrV2",
+			wire.NewMsgAddrV2(),
+		},
 		{
 			"OnPing",
 			wire.NewMsgPing(42),
@@ -493,6 +516,21 @@ func TestPeerListeners(t *testing.T) {
 				1, 1, 1, 1, 1, 1, 1, 1, 1, [32]byte{},
 				binary.LittleEndian.Uint32([]byte{0xb0, 0x1d, 0xfa, 0xce}))),
 		},
+		{
+			"OnCmpctBlock",
+			wire.NewMsgCmpctBlock(wire.NewBlockHeader(0, &chainhash.Hash{},
+				&chainhash.Hash{}, &chainhash.Hash{}, 1, [6]byte{},
+				1, 1, 1, 1, 1, 1, 1, 1, 1, [32]byte{},
+				binary.LittleEndian.Uint32([]byte{0xb0, 0x1d, 0xfa, 0xce})), 1),
+		},
+		{
+			"OnGetBlockTxn",
+			wire.NewMsgGetBlockTxn(&chainhash.Hash{}),
+		},
+		{
+			"OnBlockTxn",
+			wire.NewMsgBlockTxn(&chainhash.Hash{}),
+		},
 		{
 			"OnInv",
 			wire.NewMsgInv(),
@@ -760,6 +798,18 @@ func TestOutboundPeer(t *testing.T) {
 		t.Errorf("PushAddrMsg: unexpected err %v\n", err)
 		return
 	}
+	var addrsV2 []*wire.NetAddressV2
+	for i := 0; i < 5; i++ {
+		na := wire.NetAddressV2{
+			Type:        wire.TORv3Address,
+			EncodedAddr: make([]byte, 32),
+		}
+		addrsV2 = append(addrsV2, &na)
+	}
+	if _, err := p2.PushAddrV2Msg(addrsV2); err != nil {
+		t.Errorf("PushAddrV2Msg: unexpected err %v\n", err)
+		return
+	}
 	if err := p2.PushGetBlocksMsg(nil, &chainhash.Hash{}); err != nil {
 		t.Errorf("PushGetBlocksMsg: unexpected err %v\n", err)
 		return
@@ -335,6 +335,54 @@ func NewGetAddedNodeInfoCmd(dns bool, node *string) *GetAddedNodeInfoCmd {
 	}
 }
 
+// GetAddressBalanceCmd defines the getaddressbalance JSON-RPC command.
+type GetAddressBalanceCmd struct {
+	Address string
+}
+
+// NewGetAddressBalanceCmd returns a new instance which can be used to issue a
+// getaddressbalance JSON-RPC command.
+func NewGetAddressBalanceCmd(address string) *GetAddressBalanceCmd {
+	return &GetAddressBalanceCmd{
+		Address: address,
+	}
+}
+
+// GetAddressTxIDsCmd defines the getaddresstxids JSON-RPC command.
+type GetAddressTxIDsCmd struct {
+	Address string
+	Skip    *int  `jsonrpcdefault:"0"`
+	Count   *int  `jsonrpcdefault:"100"`
+	Reverse *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetAddressTxIDsCmd returns a new instance which can be used to issue a
+// getaddresstxids JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetAddressTxIDsCmd(address string, skip, count *int, reverse *bool) *GetAddressTxIDsCmd {
+	return &GetAddressTxIDsCmd{
+		Address: address,
+		Skip:    skip,
+		Count:   count,
+		Reverse: reverse,
+	}
+}
+
+// GetAddressUtxosCmd defines the getaddressutxos JSON-RPC command.
+type GetAddressUtxosCmd struct {
+	Address string
+}
+
+// NewGetAddressUtxosCmd returns a new instance which can be used to issue a
+// getaddressutxos JSON-RPC command.
+func NewGetAddressUtxosCmd(address string) *GetAddressUtxosCmd {
+	return &GetAddressUtxosCmd{
+		Address: address,
+	}
+}
+
 // GetBestBlockCmd defines the getbestblock JSON-RPC command.
 type GetBestBlockCmd struct{}
 
@@ -434,6 +482,37 @@ func NewGetBlockSubsidyCmd(height int64, voters uint16) *GetBlockSubsidyCmd {
 	}
 }
 
+// TemplateRequest is a request object as defined in BIP22.  It is optionally
+// provided as a pointer argument to GetBlockTemplateCmd.
+type TemplateRequest struct {
+	Mode         string   `json:"mode,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// Optional long polling.  When set, the request blocks until a template
+	// that differs from the one identified by the long poll id is available.
+	LongPollID string `json:"longpollid,omitempty"`
+
+	// Optional block proposal data.  It is the hex-encoded serialized block
+	// to validate and must be set when the mode is "proposal".
+	Data string `json:"data,omitempty"`
+}
+
+// GetBlockTemplateCmd defines the getblocktemplate JSON-RPC command.
+type GetBlockTemplateCmd struct {
+	Request *TemplateRequest
+}
+
+// NewGetBlockTemplateCmd returns a new instance which can be used to issue a
+// getblocktemplate JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetBlockTemplateCmd(request *TemplateRequest) *GetBlockTemplateCmd {
+	return &GetBlockTemplateCmd{
+		Request: request,
+	}
+}
+
 // GetCFilterV2Cmd defines the getcfilterv2 JSON-RPC command.
 type GetCFilterV2Cmd struct {
 	BlockHash string
@@ -839,6 +918,38 @@ func NewRegenTemplateCmd() *RegenTemplateCmd {
 	return &RegenTemplateCmd{}
 }
 
+// SearchRawTransactionsCmd defines the searchrawtransactions JSON-RPC
+// command.
+//
+// NOTE: The verbose and vinextra fields are ints versus bools to remain
+// compatible with Bitcoin Core even though they really should be bools.
+type SearchRawTransactionsCmd struct {
+	Address     string
+	Verbose     *int  `jsonrpcdefault:"1"`
+	Skip        *int  `jsonrpcdefault:"0"`
+	Count       *int  `jsonrpcdefault:"100"`
+	VinExtra    *int  `jsonrpcdefault:"0"`
+	Reverse     *bool `jsonrpcdefault:"false"`
+	FilterAddrs *[]string
+}
+
+// NewSearchRawTransactionsCmd returns a new instance which can be used to
+// issue a searchrawtransactions JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSearchRawTransactionsCmd(address string, verbose, skip, count *int, vinExtra *int, reverse *bool, filterAddrs *[]string) *SearchRawTransactionsCmd {
+	return &SearchRawTransactionsCmd{
+		Address:     address,
+		Verbose:     verbose,
+		Skip:        skip,
+		Count:       count,
+		VinExtra:    vinExtra,
+		Reverse:     reverse,
+		FilterAddrs: filterAddrs,
+	}
+}
+
 // HelpCmd defines the help JSON-RPC command.
 type HelpCmd struct {
 	Command *string
@@ -1163,6 +1274,9 @@ func init() {
 	VGLjson.MustRegister(Method("existsmempooltxs"), (*ExistsMempoolTxsCmd)(nil), flags)
 	VGLjson.MustRegister(Method("generate"), (*GenerateCmd)(nil), flags)
 	VGLjson.MustRegister(Method("getaddednodeinfo"), (*GetAddedNodeInfoCmd)(nil), flags)
+	VGLjson.MustRegister(Method("getaddressbalance"), (*GetAddressBalanceCmd)(nil), flags)
+	VGLjson.MustRegister(Method("getaddresstxids"), (*GetAddressTxIDsCmd)(nil), flags)
+	VGLjson.MustRegister(Method("getaddressutxos"), (*GetAddressUtxosCmd)(nil), flags)
 	VGLjson.MustRegister(Method("getbestblock"), (*GetBestBlockCmd)(nil), flags)
 	VGLjson.MustRegister(Method("getbestblockhash"), (*GetBestBlockHashCmd)(nil), flags)
 	VGLjson.MustRegister(Method("getblock"), (*GetBlockCmd)(nil), flags)
@@ -1171,6 +1285,7 @@ func init() {
 	VGLjson.MustRegister(Method("getblockhash"), (*GetBlockHashCmd)(nil), flags)
 	VGLjson.MustRegister(Method("getblockheader"), (*GetBlockHeaderCmd)(nil), flags)
 	VGLjson.MustRegister(Method("getblocksubsidy"), (*GetBlockSubsidyCmd)(nil), flags)
+	VGLjson.MustRegister(Method("getblocktemplate"), (*GetBlockTemplateCmd)(nil), flags)
 	VGLjson.MustRegister(Method("getcfilterv2"), (*GetCFilterV2Cmd)(nil), flags)
 	VGLjson.MustRegister(Method("getchaintips"), (*GetChainTipsCmd)(nil), flags)
 	VGLjson.MustRegister(Method("getcoinsupply"), (*GetCoinSupplyCmd)(nil), flags)
@@ -1208,6 +1323,7 @@ func init() {
 	VGLjson.MustRegister(Method("ping"), (*PingCmd)(nil), flags)
 	VGLjson.MustRegister(Method("reconsiderblock"), (*ReconsiderBlockCmd)(nil), flags)
 	VGLjson.MustRegister(Method("regentemplate"), (*RegenTemplateCmd)(nil), flags)
+	VGLjson.MustRegister(Method("searchrawtransactions"), (*SearchRawTransactionsCmd)(nil), flags)
 	VGLjson.MustRegister(Method("sendrawmixmessage"), (*SendRawMixMessageCmd)(nil), flags)
 	VGLjson.MustRegister(Method("sendrawtransaction"), (*SendRawTransactionCmd)(nil), flags)
 	VGLjson.MustRegister(Method("setgenerate"), (*SetGenerateCmd)(nil), flags)
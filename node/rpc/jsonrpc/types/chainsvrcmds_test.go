@@ -209,6 +209,62 @@ func TestChainSvrCmds(t *testing.T) {
 				Node: VGLjson.String("127.0.0.1"),
 			},
 		},
+		{
+			name: "getaddressbalance",
+			newCmd: func() (interface{}, error) {
+				return VGLjson.NewCmd(Method("getaddressbalance"), "1Address")
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressBalanceCmd("1Address")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getaddressbalance","params":["1Address"],"id":1}`,
+			unmarshalled: &GetAddressBalanceCmd{Address: "1Address"},
+		},
+		{
+			name: "getaddresstxids",
+			newCmd: func() (interface{}, error) {
+				return VGLjson.NewCmd(Method("getaddresstxids"), "1Address")
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressTxIDsCmd("1Address", nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddresstxids","params":["1Address"],"id":1}`,
+			unmarshalled: &GetAddressTxIDsCmd{
+				Address: "1Address",
+				Skip:    VGLjson.Int(0),
+				Count:   VGLjson.Int(100),
+				Reverse: VGLjson.Bool(false),
+			},
+		},
+		{
+			name: "getaddresstxids optional",
+			newCmd: func() (interface{}, error) {
+				return VGLjson.NewCmd(Method("getaddresstxids"), "1Address", 5,
+					10, true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressTxIDsCmd("1Address", VGLjson.Int(5),
+					VGLjson.Int(10), VGLjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddresstxids","params":["1Address",5,10,true],"id":1}`,
+			unmarshalled: &GetAddressTxIDsCmd{
+				Address: "1Address",
+				Skip:    VGLjson.Int(5),
+				Count:   VGLjson.Int(10),
+				Reverse: VGLjson.Bool(true),
+			},
+		},
+		{
+			name: "getaddressutxos",
+			newCmd: func() (interface{}, error) {
+				return VGLjson.NewCmd(Method("getaddressutxos"), "1Address")
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressUtxosCmd("1Address")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getaddressutxos","params":["1Address"],"id":1}`,
+			unmarshalled: &GetAddressUtxosCmd{Address: "1Address"},
+		},
 		{
 			name: "getbestblock",
 			newCmd: func() (interface{}, error) {
@@ -341,6 +397,61 @@ func TestChainSvrCmds(t *testing.T) {
 				Voters: 256,
 			},
 		},
+		{
+			name: "getblocktemplate",
+			newCmd: func() (interface{}, error) {
+				return VGLjson.NewCmd(Method("getblocktemplate"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockTemplateCmd(nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getblocktemplate","params":[],"id":1}`,
+			unmarshalled: &GetBlockTemplateCmd{Request: nil},
+		},
+		{
+			name: "getblocktemplate optional - template request",
+			newCmd: func() (interface{}, error) {
+				return VGLjson.NewCmd(Method("getblocktemplate"),
+					`{"mode":"template","capabilities":["longpoll","proposal"],"longpollid":"abc"}`)
+			},
+			staticCmd: func() interface{} {
+				template := TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"longpoll", "proposal"},
+					LongPollID:   "abc",
+				}
+				return NewGetBlockTemplateCmd(&template)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocktemplate","params":[{"mode":"template","capabilities":["longpoll","proposal"],"longpollid":"abc"}],"id":1}`,
+			unmarshalled: &GetBlockTemplateCmd{
+				Request: &TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"longpoll", "proposal"},
+					LongPollID:   "abc",
+				},
+			},
+		},
+		{
+			name: "getblocktemplate optional - proposal",
+			newCmd: func() (interface{}, error) {
+				return VGLjson.NewCmd(Method("getblocktemplate"),
+					`{"mode":"proposal","data":"0102"}`)
+			},
+			staticCmd: func() interface{} {
+				template := TemplateRequest{
+					Mode: "proposal",
+					Data: "0102",
+				}
+				return NewGetBlockTemplateCmd(&template)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocktemplate","params":[{"mode":"proposal","data":"0102"}],"id":1}`,
+			unmarshalled: &GetBlockTemplateCmd{
+				Request: &TemplateRequest{
+					Mode: "proposal",
+					Data: "0102",
+				},
+			},
+		},
 		{
 			name: "getcfilterv2",
 			newCmd: func() (interface{}, error) {
@@ -825,6 +936,49 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"ping","params":[],"id":1}`,
 			unmarshalled: &PingCmd{},
 		},
+		{
+			name: "searchrawtransactions",
+			newCmd: func() (interface{}, error) {
+				return VGLjson.NewCmd(Method("searchrawtransactions"), "1Address")
+			},
+			staticCmd: func() interface{} {
+				return NewSearchRawTransactionsCmd("1Address", nil, nil, nil,
+					nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address"],"id":1}`,
+			unmarshalled: &SearchRawTransactionsCmd{
+				Address:     "1Address",
+				Verbose:     VGLjson.Int(1),
+				Skip:        VGLjson.Int(0),
+				Count:       VGLjson.Int(100),
+				VinExtra:    VGLjson.Int(0),
+				Reverse:     VGLjson.Bool(false),
+				FilterAddrs: nil,
+			},
+		},
+		{
+			name: "searchrawtransactions optional",
+			newCmd: func() (interface{}, error) {
+				return VGLjson.NewCmd(Method("searchrawtransactions"),
+					"1Address", 0, 5, 10, 1, true, []string{"1Address"})
+			},
+			staticCmd: func() interface{} {
+				return NewSearchRawTransactionsCmd("1Address",
+					VGLjson.Int(0), VGLjson.Int(5), VGLjson.Int(10),
+					VGLjson.Int(1), VGLjson.Bool(true),
+					&[]string{"1Address"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,1,true,["1Address"]],"id":1}`,
+			unmarshalled: &SearchRawTransactionsCmd{
+				Address:     "1Address",
+				Verbose:     VGLjson.Int(0),
+				Skip:        VGLjson.Int(5),
+				Count:       VGLjson.Int(10),
+				VinExtra:    VGLjson.Int(1),
+				Reverse:     VGLjson.Bool(true),
+				FilterAddrs: &[]string{"1Address"},
+			},
+		},
 		{
 			name: "sendrawmixmessage",
 			newCmd: func() (interface{}, error) {
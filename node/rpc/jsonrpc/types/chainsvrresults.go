@@ -173,6 +173,55 @@ type GetBlockSubsidyResult struct {
 	Total     int64 `json:"total"`
 }
 
+// GetBlockTemplateResultTx models the transactions field of the
+// getblocktemplate command.
+type GetBlockTemplateResultTx struct {
+	Data    string  `json:"data"`
+	Hash    string  `json:"hash"`
+	Depends []int64 `json:"depends"`
+	Fee     int64   `json:"fee"`
+	SigOps  int64   `json:"sigops"`
+	TxType  string  `json:"txtype"`
+}
+
+// GetBlockTemplateResult models the data returned from the getblocktemplate
+// command.
+type GetBlockTemplateResult struct {
+	// Base fields from BIP 0022.
+	Header        string                     `json:"header"`
+	Version       int32                      `json:"version"`
+	PreviousHash  string                     `json:"previousblockhash"`
+	Height        int64                      `json:"height"`
+	CurTime       int64                      `json:"curtime"`
+	Bits          string                     `json:"bits"`
+	SizeLimit     int64                      `json:"sizelimit"`
+	CoinbaseTxn   *GetBlockTemplateResultTx  `json:"coinbasetxn"`
+	Transactions  []GetBlockTemplateResultTx `json:"transactions"`
+	STransactions []GetBlockTemplateResultTx `json:"stransactions"`
+
+	// Stake and subsidy fields.  The coinbase value is the proof-of-work
+	// subsidy plus the fees of all transactions in the template.
+	StakeVersion  uint32                `json:"stakeversion"`
+	SBits         int64                 `json:"sbits"`
+	Voters        uint16                `json:"voters"`
+	CoinbaseValue int64                 `json:"coinbasevalue"`
+	Fees          int64                 `json:"fees"`
+	Subsidy       GetBlockSubsidyResult `json:"subsidy"`
+
+	// KawPoW fields.
+	KawPoWEpoch      uint64 `json:"kawpowepoch"`
+	KawPoWSeedHash   string `json:"kawpowseedhash"`
+	KawPoWHeaderHash string `json:"kawpowheaderhash"`
+
+	// Block proposal and long polling fields from BIP 0023.
+	Capabilities []string `json:"capabilities"`
+	LongPollID   string   `json:"longpollid"`
+	Target       string   `json:"target"`
+	MinTime      int64    `json:"mintime"`
+	MaxTime      int64    `json:"maxtime"`
+	Mutable      []string `json:"mutable"`
+}
+
 // GetChainTipsResult models the data returns from the getchaintips command.
 type GetChainTipsResult struct {
 	Height    int64  `json:"height"`
@@ -213,8 +262,9 @@ type InfoChainResult struct {
 // GetMempoolInfoResult models the data returned from the getmempoolinfo
 // command.
 type GetMempoolInfoResult struct {
-	Size  int64 `json:"size"`
-	Bytes int64 `json:"bytes"`
+	Size          int64   `json:"size"`
+	Bytes         int64   `json:"bytes"`
+	MempoolMinFee float64 `json:"mempoolminfee"`
 }
 
 // GetMiningInfoResult models the data from the getmininginfo command.
@@ -334,6 +384,43 @@ type TxRawResult struct {
 	Blocktime     int64  `json:"blocktime,omitempty"`
 }
 
+// SearchRawTransactionsResult models the data from the searchrawtransactions
+// command.
+type SearchRawTransactionsResult struct {
+	Hex           string       `json:"hex,omitempty"`
+	Txid          string       `json:"txid"`
+	Version       int32        `json:"version"`
+	LockTime      uint32       `json:"locktime"`
+	Expiry        uint32       `json:"expiry"`
+	Vin           []VinPrevOut `json:"vin"`
+	Vout          []Vout       `json:"vout"`
+	BlockHash     string       `json:"blockhash,omitempty"`
+	BlockHeight   int64        `json:"blockheight,omitempty"`
+	BlockIndex    uint32       `json:"blockindex,omitempty"`
+	Confirmations int64        `json:"confirmations,omitempty"`
+	Time          int64        `json:"time,omitempty"`
+	Blocktime     int64        `json:"blocktime,omitempty"`
+}
+
+// GetAddressBalanceResult models the data returned from the getaddressbalance
+// command.
+type GetAddressBalanceResult struct {
+	Balance   float64 `json:"balance"`
+	UtxoCount int64   `json:"utxocount"`
+}
+
+// GetAddressUtxosResult models a single unspent output returned from the
+// getaddressutxos command.
+type GetAddressUtxosResult struct {
+	TxID          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	Tree          int8    `json:"tree"`
+	Amount        float64 `json:"amount"`
+	Height        int64   `json:"height"`
+	ScriptVersion uint16  `json:"scriptversion"`
+	ScriptPubKey  string  `json:"scriptPubKey"`
+}
+
 // GetStakeDifficultyResult models the data returned from the
 // getstakedifficulty command.
 type GetStakeDifficultyResult struct {
@@ -713,6 +800,89 @@ func (v *Vin) MarshalJSON() ([]byte, error) {
 	return json.Marshal(txStruct)
 }
 
+// PrevOut represents the previous output spent by a transaction input.
+type PrevOut struct {
+	Addresses []string `json:"addresses,omitempty"`
+	Value     float64  `json:"value"`
+}
+
+// VinPrevOut is like Vin except it includes the previous output spent by the
+// input.  It is used by searchrawtransactions.
+type VinPrevOut struct {
+	Coinbase      string     `json:"coinbase"`
+	Stakebase     string     `json:"stakebase"`
+	Treasurybase  bool       `json:"treasurybase"`
+	TreasurySpend string     `json:"treasuryspend"`
+	Txid          string     `json:"txid"`
+	Vout          uint32     `json:"vout"`
+	Tree          int8       `json:"tree"`
+	Sequence      uint32     `json:"sequence"`
+	AmountIn      float64    `json:"amountin"`
+	BlockHeight   uint32     `json:"blockheight"`
+	BlockIndex    uint32     `json:"blockindex"`
+	ScriptSig     *ScriptSig `json:"scriptSig"`
+	PrevOut       *PrevOut   `json:"prevOut,omitempty"`
+}
+
+// IsCoinBase returns whether or not an input is a coinbase input.
+func (v *VinPrevOut) IsCoinBase() bool {
+	return len(v.Coinbase) > 0
+}
+
+// IsStakeBase returns whether or not an input is a stakebase input.
+func (v *VinPrevOut) IsStakeBase() bool {
+	return len(v.Stakebase) > 0
+}
+
+// IsTreasurySpend returns whether or not an input is a treasury spend input.
+func (v *VinPrevOut) IsTreasurySpend() bool {
+	return len(v.TreasurySpend) > 0
+}
+
+// MarshalJSON provides a custom Marshal method for VinPrevOut.
+func (v *VinPrevOut) MarshalJSON() ([]byte, error) {
+	// The special inputs do not spend a previous output, so they are
+	// represented the same way as they are for a Vin.
+	if v.IsCoinBase() || v.IsStakeBase() || v.Treasurybase ||
+		v.IsTreasurySpend() {
+
+		vin := Vin{
+			Coinbase:      v.Coinbase,
+			Stakebase:     v.Stakebase,
+			Treasurybase:  v.Treasurybase,
+			TreasurySpend: v.TreasurySpend,
+			Sequence:      v.Sequence,
+			AmountIn:      v.AmountIn,
+			BlockHeight:   v.BlockHeight,
+			BlockIndex:    v.BlockIndex,
+		}
+		return json.Marshal(&vin)
+	}
+
+	txStruct := struct {
+		Txid        string     `json:"txid"`
+		Vout        uint32     `json:"vout"`
+		Tree        int8       `json:"tree"`
+		Sequence    uint32     `json:"sequence"`
+		AmountIn    float64    `json:"amountin"`
+		BlockHeight uint32     `json:"blockheight"`
+		BlockIndex  uint32     `json:"blockindex"`
+		ScriptSig   *ScriptSig `json:"scriptSig"`
+		PrevOut     *PrevOut   `json:"prevOut,omitempty"`
+	}{
+		Txid:        v.Txid,
+		Vout:        v.Vout,
+		Tree:        v.Tree,
+		Sequence:    v.Sequence,
+		AmountIn:    v.AmountIn,
+		BlockHeight: v.BlockHeight,
+		BlockIndex:  v.BlockIndex,
+		ScriptSig:   v.ScriptSig,
+		PrevOut:     v.PrevOut,
+	}
+	return json.Marshal(txStruct)
+}
+
 // Vout models parts of the tx data.  It is defined separately since both
 // getrawtransaction and decoderawtransaction use the same structure.
 type Vout struct {
@@ -59,6 +59,46 @@ func TestChainSvrCustomResults(t *testing.T) {
 			},
 			expected: `{"treasuryspend":"0000c2","sequence":4294967295,"amountin":0,"blockheight":0,"blockindex":0}`,
 		},
+		{
+			name: "custom vinprevout marshal with coinbase",
+			result: &VinPrevOut{
+				Coinbase: "021234",
+				Sequence: 4294967295,
+			},
+			expected: `{"coinbase":"021234","sequence":4294967295,"amountin":0,"blockheight":0,"blockindex":0}`,
+		},
+		{
+			name: "custom vinprevout marshal without coinbase",
+			result: &VinPrevOut{
+				Txid: "123",
+				Vout: 1,
+				Tree: 0,
+				ScriptSig: &ScriptSig{
+					Asm: "0",
+					Hex: "00",
+				},
+				PrevOut: &PrevOut{
+					Addresses: []string{"addr1"},
+					Value:     0,
+				},
+				Sequence: 4294967295,
+			},
+			expected: `{"txid":"123","vout":1,"tree":0,"sequence":4294967295,"amountin":0,"blockheight":0,"blockindex":0,"scriptSig":{"asm":"0","hex":"00"},"prevOut":{"addresses":["addr1"],"value":0}}`,
+		},
+		{
+			name: "custom vinprevout marshal without prevout",
+			result: &VinPrevOut{
+				Txid: "123",
+				Vout: 1,
+				Tree: 0,
+				ScriptSig: &ScriptSig{
+					Asm: "0",
+					Hex: "00",
+				},
+				Sequence: 4294967295,
+			},
+			expected: `{"txid":"123","vout":1,"tree":0,"sequence":4294967295,"amountin":0,"blockheight":0,"blockindex":0,"scriptSig":{"asm":"0","hex":"00"}}`,
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
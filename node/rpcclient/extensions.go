@@ -149,6 +149,134 @@ func (c *Client) ExistsAddress(ctx context.Context, address stdaddr.Address) (bo
 	return c.ExistsAddressAsync(ctx, address).Receive()
 }
 
+// FutureGetAddressBalanceResult is a future promise to deliver the result of a
+// GetAddressBalanceAsync RPC invocation (or an applicable error).
+type FutureGetAddressBalanceResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// confirmed balance and unspent output count of an address.
+func (r *FutureGetAddressBalanceResult) Receive() (*chainjson.GetAddressBalanceResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a getaddressbalance result object.
+	var balance chainjson.GetAddressBalanceResult
+	err = json.Unmarshal(res, &balance)
+	if err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// GetAddressBalanceAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetAddressBalance for the blocking version and more details.
+func (c *Client) GetAddressBalanceAsync(ctx context.Context, address stdaddr.Address) *FutureGetAddressBalanceResult {
+	cmd := chainjson.NewGetAddressBalanceCmd(address.String())
+	return (*FutureGetAddressBalanceResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetAddressBalance returns the confirmed balance of the passed address along
+// with the number of unspent outputs that make it up.
+//
+// NOTE: This is a vgld extension and requires the address index.
+func (c *Client) GetAddressBalance(ctx context.Context, address stdaddr.Address) (*chainjson.GetAddressBalanceResult, error) {
+	return c.GetAddressBalanceAsync(ctx, address).Receive()
+}
+
+// FutureGetAddressTxIDsResult is a future promise to deliver the result of a
+// GetAddressTxIDsAsync RPC invocation (or an applicable error).
+type FutureGetAddressTxIDsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the hashes
+// of the transactions that involve an address.
+func (r *FutureGetAddressTxIDsResult) Receive() ([]*chainhash.Hash, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as an array of strings.
+	var txIDs []string
+	err = json.Unmarshal(res, &txIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert each string into a hash.
+	hashes := make([]*chainhash.Hash, 0, len(txIDs))
+	for _, txID := range txIDs {
+		hash, err := chainhash.NewHashFromStr(txID)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+// GetAddressTxIDsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetAddressTxIDs for the blocking version and more details.
+func (c *Client) GetAddressTxIDsAsync(ctx context.Context, address stdaddr.Address, skip, count int, reverse bool) *FutureGetAddressTxIDsResult {
+	cmd := chainjson.NewGetAddressTxIDsCmd(address.String(), &skip, &count,
+		&reverse)
+	return (*FutureGetAddressTxIDsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetAddressTxIDs returns the hashes of the transactions that involve the
+// passed address.
+//
+// NOTE: This is a vgld extension and requires the address index.
+func (c *Client) GetAddressTxIDs(ctx context.Context, address stdaddr.Address, skip, count int, reverse bool) ([]*chainhash.Hash, error) {
+	return c.GetAddressTxIDsAsync(ctx, address, skip, count, reverse).Receive()
+}
+
+// FutureGetAddressUtxosResult is a future promise to deliver the result of a
+// GetAddressUtxosAsync RPC invocation (or an applicable error).
+type FutureGetAddressUtxosResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// confirmed unspent outputs paying to an address.
+func (r *FutureGetAddressUtxosResult) Receive() ([]chainjson.GetAddressUtxosResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as an array of getaddressutxos result objects.
+	var utxos []chainjson.GetAddressUtxosResult
+	err = json.Unmarshal(res, &utxos)
+	if err != nil {
+		return nil, err
+	}
+	return utxos, nil
+}
+
+// GetAddressUtxosAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetAddressUtxos for the blocking version and more details.
+func (c *Client) GetAddressUtxosAsync(ctx context.Context, address stdaddr.Address) *FutureGetAddressUtxosResult {
+	cmd := chainjson.NewGetAddressUtxosCmd(address.String())
+	return (*FutureGetAddressUtxosResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetAddressUtxos returns the confirmed unspent outputs paying to the passed
+// address.
+//
+// NOTE: This is a vgld extension and requires the address index.
+func (c *Client) GetAddressUtxos(ctx context.Context, address stdaddr.Address) ([]chainjson.GetAddressUtxosResult, error) {
+	return c.GetAddressUtxosAsync(ctx, address).Receive()
+}
+
 // FutureExistsAddressesResult is a future promise to deliver the result
 // of a FutureExistsAddressesResultAsync RPC invocation (or an
 // applicable error).
@@ -415,6 +415,44 @@ func (c *Client) SubmitBlock(ctx context.Context, block *VGLutil.Block, options
 	return c.SubmitBlockAsync(ctx, block, options).Receive()
 }
 
+// FutureGetBlockTemplateResult is a future promise to deliver the result of a
+// GetBlockTemplateAsync RPC invocation (or an applicable error).
+type FutureGetBlockTemplateResult cmdRes
+
+// Receive waits for the response promised by the future and returns a block
+// template for external mining.
+func (r *FutureGetBlockTemplateResult) Receive() (*chainjson.GetBlockTemplateResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getblocktemplate result object.
+	var result chainjson.GetBlockTemplateResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBlockTemplateAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetBlockTemplate for the blocking version and more details.
+func (c *Client) GetBlockTemplateAsync(ctx context.Context, request *chainjson.TemplateRequest) *FutureGetBlockTemplateResult {
+	cmd := chainjson.NewGetBlockTemplateCmd(request)
+	return (*FutureGetBlockTemplateResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetBlockTemplate returns a block template for external mining.  Passing a
+// request with the long poll id of a previously returned template blocks until
+// a template that differs from it is available.
+func (c *Client) GetBlockTemplate(ctx context.Context, request *chainjson.TemplateRequest) (*chainjson.GetBlockTemplateResult, error) {
+	return c.GetBlockTemplateAsync(ctx, request).Receive()
+}
+
 // FutureRegenTemplateResult is a future promise to deliver the result of a
 // RegenTemplate RPC invocation (or an applicable error).
 type FutureRegenTemplateResult cmdRes
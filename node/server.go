@@ -0,0 +1,2576 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2015-2024 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/elliptic"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/addrmgr/v3"
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/blockchain/standalone/v2"
+	"github.com/kdsmith18542/vigil/certgen"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/connmgr/v3"
+	"github.com/kdsmith18542/vigil/container/apbf"
+	"github.com/kdsmith18542/vigil/crypto/rand"
+	"github.com/kdsmith18542/vigil/database/v3"
+	"github.com/kdsmith18542/vigil/internal/blockchain"
+	"github.com/kdsmith18542/vigil/internal/blockchain/indexers"
+	"github.com/kdsmith18542/vigil/internal/fees"
+	"github.com/kdsmith18542/vigil/internal/mempool"
+	"github.com/kdsmith18542/vigil/internal/mining"
+	"github.com/kdsmith18542/vigil/internal/mining/cpuminer"
+	"github.com/kdsmith18542/vigil/internal/mining/stratum"
+	"github.com/kdsmith18542/vigil/internal/netsync"
+	"github.com/kdsmith18542/vigil/internal/rpcserver"
+	"github.com/kdsmith18542/vigil/internal/version"
+	"github.com/kdsmith18542/vigil/mixing"
+	"github.com/kdsmith18542/vigil/mixing/mixpool"
+	"github.com/kdsmith18542/vigil/peer/v3"
+	"github.com/kdsmith18542/vigil/txscript/v4"
+	"github.com/kdsmith18542/vigil/wire"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	// defaultServices describes the default services that are supported by
+	// the server.
+	defaultServices = wire.SFNodeNetwork | wire.SFNodeCF
+
+	// defaultRequiredServices describes the default services that are
+	// required to be supported by outbound peers.
+	defaultRequiredServices = wire.SFNodeNetwork
+
+	// defaultTargetOutbound is the default number of outbound peers to
+	// target.
+	defaultTargetOutbound = 8
+
+	// defaultMaximumVoteAge is the threshold of blocks before the tip
+	// that can be voted on.
+	defaultMaximumVoteAge = 1440
+
+	// connectionRetryInterval is the base amount of time to wait in between
+	// retries when connecting to persistent peers.  It is adjusted by the
+	// number of retries such that there is a retry backoff.
+	connectionRetryInterval = time.Second * 5
+
+	// maxProtocolVersion is the max protocol version the server supports.
+	maxProtocolVersion = wire.CompactBlocksVersion
+
+	// maxCmpctBlockDepth is the maximum depth from the current best chain tip
+	// a block may be at in order to be served as a compact block or to have
+	// its transactions served via blocktxn.  Full blocks are sent for requests
+	// of deeper blocks since peers are unlikely to have their transactions
+	// and serving them is expensive.
+	maxCmpctBlockDepth = 10
+
+	// maxKnownAddrsPerPeer is the maximum number of items to keep in the
+	// per-peer known address cache.
+	maxKnownAddrsPerPeer = 10000
+
+	// maxCachedNaSubmissions is the maximum number of network address
+	// submissions cached.
+	maxCachedNaSubmissions = 20
+
+	// minNaSubmissionScore is the minimum number of distinct outbound peers
+	// that must report the same external address before it is advertised as
+	// a local address.
+	minNaSubmissionScore = 3
+
+	// maxReorgDepthNotify specifies the maximum reorganization depth for
+	// which winning ticket notifications will be sent over RPC.  The reorg
+	// depth is the number of blocks that would be reorganized out of the
+	// current best chain if a side chain being considered for notifications
+	// were to ultimately be extended to be longer than the current one.
+	//
+	// In effect, this helps to prevent large reorgs by refusing to send the
+	// winning ticket information to RPC clients, such as voting wallets,
+	// which depend on it to cast votes.
+	//
+	// This check also doubles to help reduce exhaustion attacks that could
+	// otherwise arise from sending old orphan blocks and forcing nodes to
+	// do expensive lottery data calculations for them.
+	maxReorgDepthNotify = 6
+
+	// maxRecentlyConfirmedTxns specifies the maximum number of recently
+	// confirmed transactions to track.  This value is set to target tracking
+	// the maximum number transactions of the minimum realistic size (~206
+	// bytes) in approximately one hour of blocks on the main network.
+	maxRecentlyConfirmedTxns = 23000
+
+	// recentlyConfirmedTxnsFPRate is the false positive rate for the apbf
+	// used to track recently confirmed transactions.  It is set to a rate of
+	// 1 per 1 million to make it highly unlikely that any given recently
+	// confirmed transaction is falsely reported as confirmed.
+	recentlyConfirmedTxnsFPRate = 0.000001
+
+	// mempoolFileName is the name of the file in the data directory the
+	// transaction memory pool is saved to on shutdown and reloaded from on
+	// startup.
+	mempoolFileName = "mempool.dat"
+)
+
+var (
+	// userAgentName is the user agent name and is used to help identify
+	// ourselves to other peers.
+	userAgentName = "vgld"
+
+	// userAgentVersion is the user agent version and is used to help
+	// identify ourselves to other peers.
+	userAgentVersion = fmt.Sprintf("%d.%d.%d", version.Major, version.Minor,
+		version.Patch)
+)
+
+// simpleAddr implements the net.Addr interface with two struct fields.
+type simpleAddr struct {
+	net, addr string
+}
+
+// String returns the address.
+//
+// This is part of the net.Addr interface.
+func (a simpleAddr) String() string {
+	return a.addr
+}
+
+// Network returns the network.
+//
+// This is part of the net.Addr interface.
+func (a simpleAddr) Network() string {
+	return a.net
+}
+
+// Ensure simpleAddr implements the net.Addr interface.
+var _ net.Addr = simpleAddr{}
+
+// broadcastInventoryAdd is a type used to declare that the InvVect it contains
+// needs to be added to the rebroadcast map.
+type broadcastInventoryAdd relayMsg
+
+// broadcastInventoryDel is a type used to declare that the InvVect it contains
+// needs to be removed from the rebroadcast map.
+type broadcastInventoryDel *wire.InvVect
+
+// broadcastPruneInventory is a type used to declare that rebroadcast
+// inventory entries need to be filtered and removed where necessary.
+type broadcastPruneInventory struct{}
+
+// relayMsg packages an inventory vector along with the newly discovered
+// inventory and a flag that determines if the relay should happen immediately
+// (it will be put into a trickle queue if false) so the relay has access to
+// that information.
+type relayMsg struct {
+	invVect   *wire.InvVect
+	data      interface{}
+	immediate bool
+}
+
+// naSubmission represents a network address submission from an outbound peer.
+type naSubmission struct {
+	na           *addrmgr.NetAddress
+	reach        addrmgr.NetAddressReach
+	score        uint32
+	lastAccessed int64
+	submitters   map[string]struct{}
+}
+
+// naSubmissionCache represents a bounded map for network address submissions.
+type naSubmissionCache struct {
+	cache map[string]*naSubmission
+	limit int
+	mtx   sync.Mutex
+}
+
+// incrementScore increases the score of the provided network address
+// submission on behalf of the provided submitter, creating the submission when
+// it does not already exist.  Each submitter is only counted once.  The
+// resulting submission score is returned.
+//
+// This function is safe for concurrent access.
+func (sc *naSubmissionCache) incrementScore(na *addrmgr.NetAddress, reach addrmgr.NetAddressReach, submitter string) uint32 {
+	key := na.Key()
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+
+	sub, ok := sc.cache[key]
+	if !ok {
+		// Evict the least recently accessed entry when the cache is full.
+		if len(sc.cache) >= sc.limit {
+			var oldestKey string
+			var oldest int64
+			for k, s := range sc.cache {
+				if oldestKey == "" || s.lastAccessed < oldest {
+					oldestKey, oldest = k, s.lastAccessed
+				}
+			}
+			delete(sc.cache, oldestKey)
+		}
+
+		sub = &naSubmission{
+			na:         na,
+			reach:      reach,
+			submitters: make(map[string]struct{}),
+		}
+		sc.cache[key] = sub
+	}
+
+	sub.lastAccessed = time.Now().Unix()
+	if _, ok := sub.submitters[submitter]; !ok {
+		sub.submitters[submitter] = struct{}{}
+		sub.score++
+	}
+	return sub.score
+}
+
+// peerState houses state of inbound, persistent, and outbound peers as well
+// as banned peers and outbound groups.
+type peerState struct {
+	sync.Mutex
+
+	// The following fields are protected by the embedded mutex.
+	inboundPeers    map[int32]*serverPeer
+	outboundPeers   map[int32]*serverPeer
+	persistentPeers map[int32]*serverPeer
+	banned          map[string]time.Time
+	outboundGroups  map[string]int
+}
+
+// makePeerState returns a peer state instance that is used to maintain the
+// state of inbound, persistent, and outbound peers as well as banned peers and
+// outbound groups.
+func makePeerState() peerState {
+	return peerState{
+		inboundPeers:    make(map[int32]*serverPeer),
+		persistentPeers: make(map[int32]*serverPeer),
+		outboundPeers:   make(map[int32]*serverPeer),
+		banned:          make(map[string]time.Time),
+		outboundGroups:  make(map[string]int),
+	}
+}
+
+// count returns the count of all known peers.
+//
+// This function MUST be called with the embedded mutex locked (for reads).
+func (ps *peerState) count() int {
+	return len(ps.inboundPeers) + len(ps.outboundPeers) +
+		len(ps.persistentPeers)
+}
+
+// forAllOutboundPeers is a helper function that runs closure on all outbound
+// peers known to peerState.
+//
+// This function MUST be called with the embedded mutex locked (for reads).
+func (ps *peerState) forAllOutboundPeers(closure func(sp *serverPeer)) {
+	for _, e := range ps.outboundPeers {
+		closure(e)
+	}
+	for _, e := range ps.persistentPeers {
+		closure(e)
+	}
+}
+
+// forAllPeers is a helper function that runs closure on all peers known to
+// peerState.
+//
+// This function MUST be called with the embedded mutex locked (for reads).
+func (ps *peerState) forAllPeers(closure func(sp *serverPeer)) {
+	for _, e := range ps.inboundPeers {
+		closure(e)
+	}
+	ps.forAllOutboundPeers(closure)
+}
+
+// connectionsWithIP returns the number of connections with the given IP.
+//
+// This function MUST be called with the embedded mutex locked (for reads).
+func (ps *peerState) connectionsWithIP(ip net.IP) int {
+	var total int
+	ps.forAllPeers(func(sp *serverPeer) {
+		if ip.Equal(sp.NA().IP) {
+			total++
+		}
+	})
+	return total
+}
+
+// server provides a Vigil server for handling communications to and from
+// Vigil peers.
+type server struct {
+	bytesReceived atomic.Uint64 // Total bytes received from all peers since start.
+	bytesSent     atomic.Uint64 // Total bytes sent by all peers since start.
+	shutdown      atomic.Bool
+	mempoolLoaded atomic.Bool // Saved mempool has been reloaded.
+
+	// These fields are set at creation time and never modified.
+	chainParams  *chaincfg.Params
+	services     wire.ServiceFlag
+	db           database.DB
+	timeSource   blockchain.MedianTimeSource
+	subsidyCache *standalone.SubsidyCache
+	sigCache     *txscript.SigCache
+	nat          *upnpNAT
+	mempoolFile  string
+
+	addrManager          *addrmgr.AddrManager
+	connManager          *connmgr.ConnManager
+	syncManager          *netsync.SyncManager
+	chain                *blockchain.BlockChain
+	txMemPool            *mempool.TxPool
+	feeEstimator         *fees.Estimator
+	mixMsgPool           *mixpool.Pool
+	bg                   *mining.BgBlkTmplGenerator
+	cpuMiner             *cpuminer.CPUMiner
+	stratumServer        *stratum.Server
+	rpcServer            *rpcserver.Server
+	modifyRebroadcastInv chan interface{}
+	persistentPeerAddrs  []net.Addr
+	peerState            peerState
+	naSubmissionCache    naSubmissionCache
+	quit                 chan struct{}
+
+	// The following fields are used for optional indexes.  They will be nil
+	// if the associated index is not enabled.  These fields are set during
+	// initial creation of the server and never changed afterwards, so they
+	// do not need to be protected for concurrent access.
+	indexSubscriber *indexers.IndexSubscriber
+	txIndex         *indexers.TxIndex
+	addrIndex       *indexers.AddrIndex
+	existsAddrIndex *indexers.ExistsAddrIndex
+
+	// lotteryDataBroadcast tracks which blocks have had their winning
+	// tickets notified to RPC clients along with the height of the block so
+	// stale entries can be pruned.
+	lotteryDataBroadcastMtx sync.Mutex
+	lotteryDataBroadcast    map[chainhash.Hash]int64
+
+	// recentlyConfirmedTxns tracks transactions that have been confirmed in
+	// the most recent blocks.
+	recentlyConfirmedTxns *apbf.Filter
+}
+
+// considerExternalAddr records the local address reported by an outbound peer
+// as a candidate external address and adds it to the address manager as a
+// local address once enough distinct outbound peers agree on it.
+func (s *server) considerExternalAddr(addrYou *wire.NetAddress, remoteAddr *addrmgr.NetAddress) {
+	if addrYou == nil || addrYou.IP == nil {
+		return
+	}
+
+	// Use the default port for the network since the port reported by the
+	// remote peer is the ephemeral port of the outbound connection.
+	port, err := strconv.ParseUint(s.chainParams.DefaultPort, 10, 16)
+	if err != nil {
+		return
+	}
+	lna := addrmgr.NewNetAddressFromIPPort(addrYou.IP, uint16(port),
+		s.services)
+	valid, reach := s.addrManager.IsExternalAddrCandidate(lna, remoteAddr)
+	if !valid || s.addrManager.HasLocalAddress(lna) {
+		return
+	}
+
+	score := s.naSubmissionCache.incrementScore(lna, reach, remoteAddr.Key())
+	if score < minNaSubmissionScore {
+		return
+	}
+
+	err = s.addrManager.AddLocalAddress(lna, addrmgr.BoundPrio)
+	if err != nil {
+		amgrLog.Debugf("Unable to add discovered external address %s: %v",
+			lna, err)
+		return
+	}
+	amgrLog.Infof("Discovered external address %s", lna)
+}
+
+// pushTxMsg sends a tx message for the provided transaction hash to the
+// connected peer.  An error is returned if the transaction hash is not known.
+func (s *server) pushTxMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<- struct{}, waitChan <-chan struct{}) error {
+	// Attempt to fetch the requested transaction from the pool.  A call could
+	// be made to check for existence first, but simply trying to fetch a
+	// missing transaction results in the same behavior.  Do not allow peers
+	// to request transactions already in a block but are unconfirmed, as
+	// they may be expensive.  Restrict that to the authenticated RPC only.
+	tx, err := s.txMemPool.FetchTransaction(hash)
+	if err != nil {
+		peerLog.Tracef("Unable to fetch tx %v from transaction pool: %v",
+			hash, err)
+
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		return err
+	}
+
+	// Once we have fetched data wait for any previous operation to finish.
+	if waitChan != nil {
+		<-waitChan
+	}
+
+	sp.QueueMessage(tx.MsgTx(), doneChan)
+
+	return nil
+}
+
+// pushBlockMsg sends a block message for the provided block hash to the
+// connected peer.  An error is returned if the block hash is not known.
+func (s *server) pushBlockMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<- struct{}, waitChan <-chan struct{}) error {
+	block, err := s.chain.BlockByHash(hash)
+	if err != nil {
+		peerLog.Tracef("Unable to fetch requested block hash %v: %v", hash,
+			err)
+
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		return err
+	}
+
+	// Once we have fetched data wait for any previous operation to finish.
+	if waitChan != nil {
+		<-waitChan
+	}
+
+	// We only send the channel for this message if we aren't sending an inv
+	// straight after.
+	var dc chan<- struct{}
+	continueHash := sp.continueHash.Load()
+	sendInv := continueHash != nil && *continueHash == *hash
+	if !sendInv {
+		dc = doneChan
+	}
+	sp.QueueMessage(block.MsgBlock(), dc)
+
+	// When the peer requests the final block that was advertised in response
+	// to a getblocks message which requested more blocks than would fit into
+	// a single message, send it a new inventory message to trigger it to
+	// issue another getblocks message for the next batch of inventory.
+	if sendInv {
+		best := s.chain.BestSnapshot()
+		invMsg := wire.NewMsgInvSizeHint(1)
+		iv := wire.NewInvVect(wire.InvTypeBlock, &best.Hash)
+		invMsg.AddInvVect(iv)
+		sp.QueueMessage(invMsg, doneChan)
+		sp.continueHash.Store(nil)
+	}
+	return nil
+}
+
+// pushCmpctBlockMsg sends a compact block message for the provided block hash
+// to the connected peer.  The full block is sent instead when the block is too
+// deep in the chain or the peer does not support compact blocks.  An error is
+// returned if the block hash is not known.
+func (s *server) pushCmpctBlockMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<- struct{}, waitChan <-chan struct{}) error {
+	if sp.ProtocolVersion() < wire.CompactBlocksVersion {
+		return s.pushBlockMsg(sp, hash, doneChan, waitChan)
+	}
+
+	block, err := s.chain.BlockByHash(hash)
+	if err != nil {
+		peerLog.Tracef("Unable to fetch requested block hash %v: %v", hash,
+			err)
+
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		return err
+	}
+
+	// Send the full block when it is too deep in the chain.
+	best := s.chain.BestSnapshot()
+	var msg wire.Message = block.MsgBlock()
+	if best.Height-block.Height() <= maxCmpctBlockDepth {
+		cmpctBlock, err := netsync.NewCompactBlock(block.MsgBlock(),
+			rand.Uint64())
+		if err != nil {
+			peerLog.Errorf("Unable to create compact block %v: %v", hash,
+				err)
+		} else {
+			msg = cmpctBlock
+		}
+	}
+
+	// Once we have fetched data wait for any previous operation to finish.
+	if waitChan != nil {
+		<-waitChan
+	}
+
+	sp.QueueMessage(msg, doneChan)
+	return nil
+}
+
+// pushMixMsg sends a mix message for the provided mix message hash to the
+// connected peer.  An error is returned if the mix message hash is not known.
+func (s *server) pushMixMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<- struct{}, waitChan <-chan struct{}) error {
+	msg, err := s.mixMsgPool.Message(hash)
+	if err != nil {
+		// Also consider messages that were recently removed from the pool
+		// since peers may still be requesting them.
+		var ok bool
+		msg, ok = s.mixMsgPool.RecentMessage(hash)
+		if !ok {
+			peerLog.Tracef("Unable to fetch requested mix message %v: %v",
+				hash, err)
+
+			if doneChan != nil {
+				doneChan <- struct{}{}
+			}
+			return err
+		}
+	}
+
+	// Once we have fetched data wait for any previous operation to finish.
+	if waitChan != nil {
+		<-waitChan
+	}
+
+	sp.QueueMessage(msg, doneChan)
+
+	return nil
+}
+
+// handleAddPeer deals with adding new peers.  It returns whether or not the
+// peer was added.
+//
+// This function is safe for concurrent access.
+func (s *server) handleAddPeer(sp *serverPeer) bool {
+	if sp == nil || !sp.Connected() {
+		return false
+	}
+
+	state := &s.peerState
+	state.Lock()
+	defer state.Unlock()
+
+	// Ignore new peers if we're shutting down.
+	if s.shutdown.Load() {
+		srvrLog.Infof("New peer %s ignored - server is shutting down", sp)
+		sp.Disconnect()
+		return false
+	}
+
+	// Disconnect banned peers.
+	host, _, err := net.SplitHostPort(sp.Addr())
+	if err != nil {
+		srvrLog.Debugf("can't split host/port: %s", err)
+		sp.Disconnect()
+		return false
+	}
+	if banEnd, ok := state.banned[host]; ok {
+		if time.Now().Before(banEnd) {
+			srvrLog.Debugf("Peer %s is banned for another %v - disconnecting",
+				host, time.Until(banEnd))
+			sp.Disconnect()
+			return false
+		}
+
+		srvrLog.Infof("Peer %s is no longer banned", host)
+		delete(state.banned, host)
+	}
+
+	// Limit max number of total peers.
+	if state.count() >= cfg.MaxPeers {
+		srvrLog.Infof("Max peers reached [%d] - disconnecting peer %s",
+			cfg.MaxPeers, sp)
+		sp.Disconnect()
+		return false
+	}
+
+	// Limit max number of connections from a single IP.  However, allow
+	// whitelisted inbound peers and localhost connections regardless.
+	isInboundWhitelisted := sp.isWhitelisted && sp.Inbound()
+	peerIP := sp.NA().IP
+	if cfg.MaxSameIP > 0 && !isInboundWhitelisted && !peerIP.IsLoopback() &&
+		state.connectionsWithIP(peerIP)+1 > cfg.MaxSameIP {
+
+		srvrLog.Infof("Max connections with %s reached [%d] - "+
+			"disconnecting peer", sp, cfg.MaxSameIP)
+		sp.Disconnect()
+		return false
+	}
+
+	// Add the new peer.
+	srvrLog.Debugf("New peer %s", sp)
+	if sp.Inbound() {
+		state.inboundPeers[sp.ID()] = sp
+	} else {
+		remoteAddr := sp.remoteNetAddress()
+		state.outboundGroups[remoteAddr.GroupKey()]++
+		if sp.persistent {
+			state.persistentPeers[sp.ID()] = sp
+		} else {
+			state.outboundPeers[sp.ID()] = sp
+		}
+	}
+	peersGauge.With(directionString(sp.Inbound())).Inc()
+
+	return true
+}
+
+// handleDonePeer deals with peers that have signalled they are done.
+//
+// This function is safe for concurrent access.
+func (s *server) handleDonePeer(sp *serverPeer) {
+	// Update the address' last seen time if the peer has acknowledged our
+	// version and has sent us its version as well.
+	if sp.VerAckReceived() && sp.VersionKnown() && sp.NA() != nil {
+		remoteAddr := sp.remoteNetAddress()
+		err := s.addrManager.Connected(remoteAddr)
+		if err != nil {
+			srvrLog.Debugf("Marking address as connected failed: %v", err)
+		}
+	}
+
+	state := &s.peerState
+	state.Lock()
+	var list map[int32]*serverPeer
+	if sp.persistent {
+		list = state.persistentPeers
+	} else if sp.Inbound() {
+		list = state.inboundPeers
+	} else {
+		list = state.outboundPeers
+	}
+	if _, ok := list[sp.ID()]; ok {
+		if !sp.Inbound() && sp.VersionKnown() {
+			remoteAddr := sp.remoteNetAddress()
+			state.outboundGroups[remoteAddr.GroupKey()]--
+		}
+		delete(list, sp.ID())
+		peersGauge.With(directionString(sp.Inbound())).Dec()
+		srvrLog.Debugf("Removed peer %s", sp)
+	}
+	state.Unlock()
+
+	// Notify the connection manager so it can potentially reconnect to
+	// persistent peers and otherwise make room for new outbound connections.
+	//
+	// Note that the connection request is cleared when a persistent peer is
+	// removed via RPC to prevent it from scheduling a reconnect.
+	if connReq := sp.connReq.Load(); connReq != nil {
+		s.connManager.Disconnect(connReq.ID())
+	}
+}
+
+// BanPeer bans a peer that has already been connected to the server by ip for
+// the configured ban duration and disconnects it.
+//
+// This function is safe for concurrent access.
+func (s *server) BanPeer(sp *serverPeer) {
+	host, _, err := net.SplitHostPort(sp.Addr())
+	if err != nil {
+		srvrLog.Debugf("can't split ban peer %s: %v", sp.Addr(), err)
+		sp.Disconnect()
+		return
+	}
+	direction := directionString(sp.Inbound())
+	srvrLog.Infof("Banned peer %s (%s) for %v", host, direction,
+		cfg.BanDuration)
+
+	s.peerState.Lock()
+	s.peerState.banned[host] = time.Now().Add(cfg.BanDuration)
+	s.peerState.Unlock()
+	sp.Disconnect()
+}
+
+// handleRelayInvMsg deals with relaying inventory to peers that are not already
+// known to have it.
+//
+// This function is safe for concurrent access.
+func (s *server) handleRelayInvMsg(msg relayMsg) {
+	s.peerState.Lock()
+	defer s.peerState.Unlock()
+
+	iv := msg.invVect
+	s.peerState.forAllPeers(func(sp *serverPeer) {
+		if !sp.Connected() {
+			return
+		}
+
+		switch iv.Type {
+		case wire.InvTypeBlock:
+			// Generate and send a headers message instead of an inventory
+			// message for block announcements when the peer prefers headers.
+			if sp.WantsHeaders() {
+				if sp.IsKnownInventory(iv) {
+					return
+				}
+				blockHeader, ok := msg.data.(wire.BlockHeader)
+				if !ok {
+					peerLog.Warnf("Underlying data for headers is not a " +
+						"block header")
+					return
+				}
+				msgHeaders := wire.NewMsgHeaders()
+				if err := msgHeaders.AddBlockHeader(&blockHeader); err != nil {
+					peerLog.Errorf("Failed to add block header: %v", err)
+					return
+				}
+				sp.AddKnownInventory(iv)
+				sp.QueueMessage(msgHeaders, nil)
+				return
+			}
+
+		case wire.InvTypeTx:
+			// Don't relay the transaction to the peer when it has
+			// transaction relaying disabled.
+			if sp.disableRelayTx.Load() {
+				return
+			}
+
+			// Don't relay the transaction if the transaction fee-per-kb is
+			// less than the peer's feefilter.
+			feeFilter := sp.feeFilter.Load()
+			if txD, ok := msg.data.(*mempool.TxDesc); ok && feeFilter > 0 &&
+				txD.TxSize > 0 && txD.Fee*1000/txD.TxSize < feeFilter {
+
+				return
+			}
+
+		case wire.InvTypeMix:
+			// Don't relay mix messages to peers that do not support them.
+			if sp.ProtocolVersion() < wire.MixVersion {
+				return
+			}
+		}
+
+		// Either queue the inventory to be relayed immediately or with the
+		// next batch depending on the immediate flag.
+		//
+		// It will be ignored in either case if the peer is already known to
+		// have the inventory.
+		if msg.immediate {
+			sp.QueueInventoryImmediate(iv)
+		} else {
+			sp.QueueInventory(iv)
+		}
+	})
+}
+
+// BroadcastMessage sends msg to all peers currently connected to the server
+// except those in the passed peers to exclude.
+//
+// This function is safe for concurrent access.
+func (s *server) BroadcastMessage(msg wire.Message, exclPeers ...*serverPeer) {
+	s.peerState.Lock()
+	s.peerState.forAllPeers(func(sp *serverPeer) {
+		if !sp.Connected() {
+			return
+		}
+		for _, ep := range exclPeers {
+			if sp == ep {
+				return
+			}
+		}
+		sp.QueueMessage(msg, nil)
+	})
+	s.peerState.Unlock()
+}
+
+// ConnectedCount returns the number of currently connected peers.
+//
+// This function is safe for concurrent access.
+func (s *server) ConnectedCount() int32 {
+	var numConnected int32
+	s.peerState.Lock()
+	s.peerState.forAllPeers(func(sp *serverPeer) {
+		if sp.Connected() {
+			numConnected++
+		}
+	})
+	s.peerState.Unlock()
+	return numConnected
+}
+
+// OutboundGroupCount returns the number of peers connected to the given
+// outbound group key.
+//
+// This function is safe for concurrent access.
+func (s *server) OutboundGroupCount(key string) int {
+	s.peerState.Lock()
+	count := s.peerState.outboundGroups[key]
+	s.peerState.Unlock()
+	return count
+}
+
+// NetTotals returns the sum of all bytes received and sent across the network
+// for all peers.
+//
+// This function is safe for concurrent access.
+func (s *server) NetTotals() (uint64, uint64) {
+	return s.bytesReceived.Load(), s.bytesSent.Load()
+}
+
+// AddRebroadcastInventory adds 'iv' to the list of inventories to be
+// rebroadcasted at random intervals until they show up in a block.
+//
+// This function is safe for concurrent access.
+func (s *server) AddRebroadcastInventory(iv *wire.InvVect, data interface{}) {
+	select {
+	case s.modifyRebroadcastInv <- broadcastInventoryAdd{invVect: iv, data: data}:
+	case <-s.quit:
+	}
+}
+
+// RemoveRebroadcastInventory removes 'iv' from the list of items to be
+// rebroadcasted if present.
+//
+// This function is safe for concurrent access.
+func (s *server) RemoveRebroadcastInventory(iv *wire.InvVect) {
+	select {
+	case s.modifyRebroadcastInv <- broadcastInventoryDel(iv):
+	case <-s.quit:
+	}
+}
+
+// PruneRebroadcastInventory filters and removes rebroadcast inventory entries
+// where necessary.
+//
+// This function is safe for concurrent access.
+func (s *server) PruneRebroadcastInventory() {
+	select {
+	case s.modifyRebroadcastInv <- broadcastPruneInventory{}:
+	case <-s.quit:
+	}
+}
+
+// RelayInventory relays the passed inventory vector to all connected peers
+// that are not already known to have it.
+//
+// This function is safe for concurrent access.
+func (s *server) RelayInventory(invVect *wire.InvVect, data interface{}, immediate bool) {
+	s.handleRelayInvMsg(relayMsg{
+		invVect:   invVect,
+		data:      data,
+		immediate: immediate,
+	})
+}
+
+// relayTransactions generates and relays inventory vectors for all of the
+// passed transactions to all connected peers.
+//
+// The transaction descriptors from the memory pool are relayed when available
+// so peers that requested a minimum fee rate via feefilter are only sent the
+// transactions that meet it.
+func (s *server) relayTransactions(txns []*VGLutil.Tx) {
+	if len(txns) == 0 {
+		return
+	}
+
+	descs := make(map[chainhash.Hash]*mempool.TxDesc, len(txns))
+	for _, txD := range s.txMemPool.TxDescs() {
+		descs[*txD.Tx.Hash()] = txD
+	}
+	for _, tx := range txns {
+		var data interface{} = tx
+		if txD, ok := descs[*tx.Hash()]; ok {
+			data = txD
+		}
+		iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
+		s.RelayInventory(iv, data, false)
+	}
+}
+
+// AnnounceNewTransactions generates and relays inventory vectors and notifies
+// websocket clients of the passed transactions.  This function should be
+// called whenever new transactions are added to the mempool.
+//
+// This is part of the netsync.PeerNotifier interface implementation.
+func (s *server) AnnounceNewTransactions(txns []*VGLutil.Tx) {
+	// Generate and relay inventory vectors for all newly accepted
+	// transactions.
+	s.relayTransactions(txns)
+
+	// Notify websocket clients of all newly accepted transactions.
+	if s.rpcServer != nil {
+		s.rpcServer.NotifyNewTransactions(txns)
+	}
+}
+
+// relayMixMessages generates and relays inventory vectors for all of the
+// passed mixing messages to all connected peers.
+func (s *server) relayMixMessages(msgs []mixing.Message) {
+	for _, m := range msgs {
+		hash := m.Hash()
+		iv := wire.NewInvVect(wire.InvTypeMix, &hash)
+		s.RelayInventory(iv, m, false)
+	}
+}
+
+// AnnounceMixMessages generates and relays inventory vectors of the passed
+// mixing messages.  This function should be called whenever new messages are
+// accepted to the mixpool.
+//
+// This is part of the netsync.PeerNotifier interface implementation.
+func (s *server) AnnounceMixMessages(msgs []mixing.Message) {
+	s.relayMixMessages(msgs)
+
+	if s.rpcServer != nil {
+		s.rpcServer.NotifyMixMessages(msgs)
+	}
+}
+
+// peerDoneHandler handles peer disconnects by notifying the server that it's
+// done along with other performing other desirable cleanup.
+func (s *server) peerDoneHandler(sp *serverPeer) {
+	sp.WaitForDisconnect()
+	s.handleDonePeer(sp)
+	s.syncManager.PeerDisconnected(sp.syncMgrPeer)
+	close(sp.quit)
+}
+
+// newPeerConfig returns the configuration for the given serverPeer.
+func newPeerConfig(sp *serverPeer) *peer.Config {
+	return &peer.Config{
+		Listeners: peer.MessageListeners{
+			OnVersion:         sp.OnVersion,
+			OnVerAck:          sp.OnVerAck,
+			OnMemPool:         sp.OnMemPool,
+			OnGetMiningState:  sp.OnGetMiningState,
+			OnMiningState:     sp.OnMiningState,
+			OnGetInitState:    sp.OnGetInitState,
+			OnInitState:       sp.OnInitState,
+			OnTx:              sp.OnTx,
+			OnBlock:           sp.OnBlock,
+			OnCmpctBlock:      sp.OnCmpctBlock,
+			OnGetBlockTxn:     sp.OnGetBlockTxn,
+			OnBlockTxn:        sp.OnBlockTxn,
+			OnInv:             sp.OnInv,
+			OnHeaders:         sp.OnHeaders,
+			OnNotFound:        sp.OnNotFound,
+			OnGetData:         sp.OnGetData,
+			OnGetBlocks:       sp.OnGetBlocks,
+			OnGetHeaders:      sp.OnGetHeaders,
+			OnGetCFilterV2:    sp.OnGetCFilterV2,
+			OnGetCFiltersV2:   sp.OnGetCFiltersV2,
+			OnFeeFilter:       sp.OnFeeFilter,
+			OnGetAddr:         sp.OnGetAddr,
+			OnAddr:            sp.OnAddr,
+			OnGetAddrV2:       sp.OnGetAddrV2,
+			OnAddrV2:          sp.OnAddrV2,
+			OnRead:            sp.OnRead,
+			OnWrite:           sp.OnWrite,
+			OnMixPairReq:      sp.OnMixPairReq,
+			OnMixKeyExchange:  sp.OnMixKeyExchange,
+			OnMixCiphertexts:  sp.OnMixCiphertexts,
+			OnMixSlotReserve:  sp.OnMixSlotReserve,
+			OnMixFactoredPoly: sp.OnMixFactoredPoly,
+			OnMixDCNet:        sp.OnMixDCNet,
+			OnMixConfirm:      sp.OnMixConfirm,
+			OnMixSecrets:      sp.OnMixSecrets,
+		},
+		NewestBlock:      sp.newestBlock,
+		HostToNetAddress: hostToWireNetAddress,
+		Proxy:            cfg.Proxy,
+		UserAgentName:    userAgentName,
+		UserAgentVersion: userAgentVersion,
+		Net:              sp.server.chainParams.Net,
+		Services:         sp.server.services,
+		DisableRelayTx:   cfg.BlocksOnly,
+		ProtocolVersion:  maxProtocolVersion,
+		IdleTimeout:      cfg.PeerIdleTimeout,
+	}
+}
+
+// inboundPeerConnected is invoked by the connection manager when a new inbound
+// connection is established.  It initializes a new inbound server peer
+// instance, associates it with the connection, and starts a goroutine to wait
+// for disconnection.
+func (s *server) inboundPeerConnected(conn net.Conn) {
+	sp := newServerPeer(s, false)
+	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
+	sp.Peer = peer.NewInboundPeer(newPeerConfig(sp))
+	sp.syncMgrPeer = netsync.NewPeer(sp.Peer)
+	sp.AssociateConnection(conn)
+	go s.peerDoneHandler(sp)
+}
+
+// outboundPeerConnected is invoked by the connection manager when a new
+// outbound connection is established.  It initializes a new outbound server
+// peer instance, associates it with the relevant state such as the connection
+// request instance and the connection itself, and finally notifies the address
+// manager of the attempt.
+func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
+	sp := newServerPeer(s, c.Permanent)
+	p, err := peer.NewOutboundPeer(newPeerConfig(sp), c.Addr.String())
+	if err != nil {
+		srvrLog.Debugf("Cannot create outbound peer %s: %v", c.Addr, err)
+		s.connManager.Disconnect(c.ID())
+		return
+	}
+	sp.Peer = p
+	sp.connReq.Store(c)
+	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
+	sp.syncMgrPeer = netsync.NewPeer(sp.Peer)
+	sp.AssociateConnection(conn)
+	go s.peerDoneHandler(sp)
+
+	remoteAddr := sp.remoteNetAddress()
+	err = s.addrManager.Attempt(remoteAddr)
+	if err != nil {
+		srvrLog.Debugf("Marking address as attempted failed: %v", err)
+	}
+}
+
+// rebroadcastHandler keeps track of user submitted inventories that we have
+// sent out but have not yet made it into a block.  We periodically rebroadcast
+// them in case our peers restarted or otherwise lost track of them.
+func (s *server) rebroadcastHandler(ctx context.Context) {
+	// Wait 5 min before first tx rebroadcast.
+	timer := time.NewTimer(5 * time.Minute)
+	pendingInvs := make(map[wire.InvVect]interface{})
+
+out:
+	for {
+		select {
+		case riv := <-s.modifyRebroadcastInv:
+			switch msg := riv.(type) {
+			// Incoming InvVects are added to our map of RPC txs.
+			case broadcastInventoryAdd:
+				pendingInvs[*msg.invVect] = msg.data
+
+			// When an InvVect has been added to a block, we can now remove
+			// it, if it was present.
+			case broadcastInventoryDel:
+				delete(pendingInvs, *msg)
+
+			case broadcastPruneInventory:
+				best := s.chain.BestSnapshot()
+				for iv, data := range pendingInvs {
+					tx, ok := data.(*VGLutil.Tx)
+					if !ok {
+						continue
+					}
+
+					txType := stake.DetermineTxType(tx.MsgTx())
+
+					// Remove the ticket rebroadcast if the amount not equal
+					// to the current stake difficulty.
+					if txType == stake.TxTypeSStx &&
+						tx.MsgTx().TxOut[0].Value != best.NextStakeDiff {
+
+						delete(pendingInvs, iv)
+						srvrLog.Debugf("Pending ticket purchase broadcast "+
+							"inventory for tx %v removed. Ticket value not "+
+							"equal to stake difficulty.", tx.Hash())
+						continue
+					}
+
+					// Remove the ticket rebroadcast if it has already
+					// expired.
+					if txType == stake.TxTypeSStx &&
+						blockchain.IsExpired(tx, best.Height) {
+
+						delete(pendingInvs, iv)
+						srvrLog.Debugf("Pending ticket purchase broadcast "+
+							"inventory for tx %v removed. Transaction "+
+							"expired.", tx.Hash())
+						continue
+					}
+
+					// Remove the revocation rebroadcast if the associated
+					// ticket has been revived.
+					if txType == stake.TxTypeSSRtx {
+						refSStxHash := tx.MsgTx().TxIn[0].PreviousOutPoint.Hash
+						if s.chain.CheckLiveTicket(refSStxHash) {
+							delete(pendingInvs, iv)
+							srvrLog.Debugf("Pending revocation broadcast "+
+								"inventory for tx %v removed. "+
+								"Associated ticket was revived.", tx.Hash())
+							continue
+						}
+					}
+				}
+			}
+
+		case <-timer.C:
+			// Any inventory we have has not made it into a block yet.  We
+			// periodically resubmit them until they have.
+			for iv, data := range pendingInvs {
+				ivCopy := iv
+				s.RelayInventory(&ivCopy, data, false)
+			}
+
+			// Process at a random time up to 30mins (in seconds) in the
+			// future.
+			timer.Reset(rand.Duration(30 * time.Minute))
+
+		case <-ctx.Done():
+			break out
+		}
+	}
+
+	timer.Stop()
+}
+
+// querySeeders queries the configured seeders to discover peers that supported
+// the required services and adds the discovered peers to the address manager.
+// Each seeder is contacted in a separate goroutine.
+func (s *server) querySeeders(ctx context.Context) {
+	for _, seeder := range s.chainParams.Seeders() {
+		go func(seeder string) {
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+
+			addrs, err := connmgr.SeedAddrs(ctx, seeder, vgldDial,
+				connmgr.SeedFilterServices(defaultRequiredServices))
+			if err != nil {
+				srvrLog.Infof("seeder '%s' error: %v", seeder, err)
+				return
+			}
+
+			// Nothing to do if the seeder didn't return any addresses.
+			if len(addrs) == 0 {
+				return
+			}
+
+			// Lookup the IP of the https seeder to use as the source of the
+			// seed addresses.  In the incredibly rare event that the lookup
+			// fails after it just succeeded, fall back to using the first
+			// returned address as the source.
+			srcAddr := wireToAddrmgrNetAddress(addrs[0])
+			srcIPs, err := vgldLookup(seeder)
+			if err == nil && len(srcIPs) > 0 {
+				const httpsPort = 443
+				srcAddr = addrmgr.NewNetAddressFromIPPort(srcIPs[0],
+					httpsPort, 0)
+			}
+			addresses := wireToAddrmgrNetAddresses(addrs)
+			s.addrManager.AddAddresses(addresses, srcAddr)
+		}(seeder)
+	}
+}
+
+// upnpUpdateThread maintains the UPnP port mapping for the P2P listener and
+// advertises the external address discovered via UPnP.  It must be run as a
+// goroutine.
+func (s *server) upnpUpdateThread(ctx context.Context) {
+	// Go off immediately to prevent code duplication, thereafter we renew
+	// lease every 15 minutes.
+	timer := time.NewTimer(0 * time.Second)
+	lport, _ := strconv.ParseInt(s.chainParams.DefaultPort, 10, 16)
+	first := true
+out:
+	for {
+		select {
+		case <-timer.C:
+			// Note that the lease timeout is in seconds.
+			listenPort, err := s.nat.AddPortMapping("tcp", int(lport),
+				int(lport), "vgld listen port", 20*60)
+			if err != nil {
+				srvrLog.Warnf("can't add UPnP port mapping: %v", err)
+			}
+			if first && err == nil {
+				externalip, err := s.nat.GetExternalAddress()
+				if err != nil {
+					srvrLog.Warnf("UPnP can't get external address: %v", err)
+					timer.Reset(time.Minute * 15)
+					continue out
+				}
+				localAddr := addrmgr.NewNetAddressFromIPPort(externalip,
+					uint16(listenPort), s.services)
+				err = s.addrManager.AddLocalAddress(localAddr,
+					addrmgr.UpnpPrio)
+				if err != nil {
+					srvrLog.Warnf("Failed to add UPnP local address %s: %v",
+						localAddr, err)
+				} else {
+					srvrLog.Warnf("Successfully bound via UPnP to %s",
+						localAddr)
+					first = false
+				}
+			}
+			timer.Reset(time.Minute * 15)
+
+		case <-ctx.Done():
+			break out
+		}
+	}
+
+	timer.Stop()
+
+	err := s.nat.DeletePortMapping("tcp", int(lport), int(lport))
+	if err != nil {
+		srvrLog.Warnf("unable to remove UPnP port mapping: %v", err)
+	} else {
+		srvrLog.Debugf("successfully disestablished UPnP port mapping")
+	}
+}
+
+// headerApprovesParent returns whether or not the vote bits in the passed
+// header indicate the regular transaction tree of the parent block should be
+// considered valid.
+func headerApprovesParent(header *wire.BlockHeader) bool {
+	return VGLutil.IsFlagSet16(header.VoteBits, VGLutil.BlockValid)
+}
+
+// isDoubleSpendOrDuplicateError returns whether or not the passed error, which
+// is expected to have come from mempool, indicates a transaction was rejected
+// either due to containing a double spend or already existing in the pool.
+func isDoubleSpendOrDuplicateError(err error) bool {
+	return errors.Is(err, mempool.ErrDuplicate) ||
+		errors.Is(err, mempool.ErrAlreadyExists) ||
+		errors.Is(err, mempool.ErrMempoolDoubleSpend) ||
+		errors.Is(err, blockchain.ErrMissingTxOut)
+}
+
+// notifyWinningTickets notifies RPC clients of the winning tickets for the
+// passed accepted block when it is at or near the tip of the best chain.
+func (s *server) notifyWinningTickets(block *VGLutil.Block, bestHeight int64, forkLen int64) {
+	// Nothing to do when the RPC server is disabled, the chain is not yet at
+	// the point where votes are required, or the block is not on or near the
+	// tip.
+	blockHeight := block.Height()
+	if s.rpcServer == nil ||
+		blockHeight < s.chainParams.StakeValidationHeight-1 ||
+		forkLen > maxReorgDepthNotify || blockHeight < bestHeight {
+
+		return
+	}
+
+	// Only notify once per block and prune entries that can no longer be
+	// relevant.
+	blockHash := block.Hash()
+	s.lotteryDataBroadcastMtx.Lock()
+	if _, ok := s.lotteryDataBroadcast[*blockHash]; ok {
+		s.lotteryDataBroadcastMtx.Unlock()
+		return
+	}
+	s.lotteryDataBroadcast[*blockHash] = blockHeight
+	for hash, height := range s.lotteryDataBroadcast {
+		if height < bestHeight-maxReorgDepthNotify {
+			delete(s.lotteryDataBroadcast, hash)
+		}
+	}
+	s.lotteryDataBroadcastMtx.Unlock()
+
+	// Obtain the winning tickets for this block.
+	wt, _, _, err := s.chain.LotteryDataForBlock(blockHash)
+	if err != nil {
+		srvrLog.Errorf("Couldn't calculate winning tickets for accepted "+
+			"block %v: %v", blockHash, err)
+		return
+	}
+
+	// Notify registered websocket clients of newly eligible tickets to vote
+	// on.
+	s.rpcServer.NotifyWinningTickets(&rpcserver.WinningTicketsNtfnData{
+		BlockHash:   *blockHash,
+		BlockHeight: blockHeight,
+		Tickets:     wt,
+	})
+}
+
+// handleBlockchainNotification handles notifications from blockchain.  It does
+// things such as request orphan block parents and relay accepted blocks to
+// connected peers.
+func (s *server) handleBlockchainNotification(notification *blockchain.Notification) {
+	switch notification.Type {
+	// A block that intends to extend the main chain has passed all sanity and
+	// contextual checks and the chain is believed to be current.  Relay it to
+	// other peers.
+	case blockchain.NTNewTipBlockChecked:
+		// WARNING: The chain lock is not released before sending this
+		// notification, so care must be taken to avoid calling chain
+		// functions which could result in a deadlock.
+		block, ok := notification.Data.(*VGLutil.Block)
+		if !ok {
+			syncLog.Warnf("New tip block checked notification is not a " +
+				"block.")
+			break
+		}
+
+		// Generate the inventory vector and relay it immediately.
+		iv := wire.NewInvVect(wire.InvTypeBlock, block.Hash())
+		s.RelayInventory(iv, block.MsgBlock().Header, true)
+
+	// A block has been accepted into the block chain.  Relay it to other
+	// peers (will be ignored if already relayed via NTNewTipBlockChecked)
+	// and possibly notify RPC clients with the winning tickets.
+	case blockchain.NTBlockAccepted:
+		// Don't relay or notify RPC clients with winning tickets if we are
+		// not current.  Other peers that are current should already know
+		// about it and clients, such as wallets, shouldn't be voting on old
+		// blocks.
+		if !s.syncManager.IsCurrent() {
+			return
+		}
+
+		band, ok := notification.Data.(*blockchain.BlockAcceptedNtfnsData)
+		if !ok {
+			syncLog.Warnf("Chain accepted notification is not " +
+				"BlockAcceptedNtfnsData.")
+			break
+		}
+		block := band.Block
+
+		// Account for the newly accepted block in the background template
+		// generator.
+		if s.bg != nil {
+			s.bg.BlockAccepted(block)
+		}
+
+		// Generate the inventory vector and relay it immediately.
+		iv := wire.NewInvVect(wire.InvTypeBlock, block.Hash())
+		s.RelayInventory(iv, block.MsgBlock().Header, true)
+
+		s.notifyWinningTickets(block, band.BestHeight, band.ForkLen)
+
+	// A block has been connected to the main block chain.
+	case blockchain.NTBlockConnected:
+		ntfn, ok := notification.Data.(*blockchain.BlockConnectedNtfnsData)
+		if !ok {
+			syncLog.Warnf("Block connected notification is not " +
+				"BlockConnectedNtfnsData.")
+			break
+		}
+		block := ntfn.Block
+		parentBlock := ntfn.ParentBlock
+		isTreasuryEnabled := ntfn.CheckTxFlags.IsTreasuryEnabled()
+
+		// Account for transactions mined in the newly connected block for
+		// fee estimation.  This must be done before attempting to remove
+		// transactions from the mempool because the mempool will alert the
+		// estimator of the txs that are leaving.
+		//
+		// The estimator is enabled once the chain is believed to be current
+		// since it is only useful for recent blocks.
+		fe := s.feeEstimator
+		if !fe.IsEnabled() {
+			if s.syncManager != nil && s.syncManager.IsCurrent() {
+				fe.Enable(block.Height())
+			}
+		} else if err := fe.ProcessBlock(block); err != nil {
+			srvrLog.Errorf("Unable to process block %v for fee estimation: %v",
+				block.Hash(), err)
+		}
+
+		// TODO: In the case the new tip disapproves the previous block, any
+		// transactions the previous block contains in its regular tree which
+		// double spend the same inputs as transactions in either tree of the
+		// current tip should ideally be tracked in the pool as eligible for
+		// inclusion in an alternative tip (side chain block) in case the
+		// current tip block does not get enough votes.  However, the
+		// transaction pool currently does not provide any way to distinguish
+		// this condition and thus only provides tracking based on the current
+		// tip.
+
+		// Remove all of the regular and stake transactions in the connected
+		// block from the transaction pool.  Also, remove any transactions
+		// which are now double spends as a result of these new transactions.
+		// Finally, remove any transaction that is no longer an orphan.
+		// Transactions which depend on a confirmed transaction are NOT
+		// removed recursively because they are still valid.  Also, the
+		// coinbase of the regular tx tree is skipped because the transaction
+		// pool doesn't (and can't) have regular tree coinbase transactions
+		// in it.
+		//
+		// Also, stop rebroadcasting any transactions in the block that were
+		// setup to be rebroadcast and keep track of them as recently
+		// confirmed.
+		txMemPool := s.txMemPool
+		handleConnectedBlockTxns := func(txns []*VGLutil.Tx) {
+			for _, tx := range txns {
+				txMemPool.RemoveTransaction(tx, false)
+				txMemPool.MaybeAcceptDependents(tx, isTreasuryEnabled)
+				txMemPool.RemoveDoubleSpends(tx)
+				txMemPool.RemoveOrphan(tx)
+				acceptedTxs := txMemPool.ProcessOrphans(tx, ntfn.CheckTxFlags)
+				s.AnnounceNewTransactions(acceptedTxs)
+
+				// Now that this block is in the blockchain, mark the
+				// transaction (except the coinbase) as no longer needing
+				// rebroadcasting and keep track of it as recently confirmed.
+				iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
+				s.RemoveRebroadcastInventory(iv)
+				s.recentlyConfirmedTxns.Add(tx.Hash()[:])
+			}
+		}
+		handleConnectedBlockTxns(block.Transactions()[1:])
+		handleConnectedBlockTxns(block.STransactions())
+
+		// In the case the regular tree of the previous block was
+		// disapproved, add all of the its transactions, with the exception
+		// of the coinbase, back to the transaction pool to be mined in a
+		// future block.
+		//
+		// Notice that some of those transactions might have been included in
+		// the current block and others might also be spending some of the
+		// same outputs that transactions in the previous originally block
+		// spent.  This is the expected behavior because disapproval of the
+		// regular tree of the previous block essentially makes it as if those
+		// transactions never happened.
+		//
+		// Finally, if transactions fail to add to the pool for some reason
+		// other than the pool already having it (a duplicate) or now being a
+		// double spend, remove all transactions that depend on it as well.
+		// The dependencies are not removed for double spends because the
+		// only way a transaction which was not a double spend in the previous
+		// block to now be one is due to some transaction in the current block
+		// (probably the same one) also spending those outputs, and, in that
+		// case, anything that happens to be in the pool which depends on the
+		// transaction is still valid.
+		if !headerApprovesParent(&block.MsgBlock().Header) {
+			for _, tx := range parentBlock.Transactions()[1:] {
+				_, err := txMemPool.MaybeAcceptTransaction(tx, false)
+				if err != nil && !isDoubleSpendOrDuplicateError(err) {
+					txMemPool.RemoveTransaction(tx, true)
+				}
+			}
+		}
+
+		// Remove the mixing pool sessions that resulted in transactions that
+		// are now mined.
+		regularTxns := block.Transactions()
+		minedHashes := make([]chainhash.Hash, 0, len(regularTxns))
+		for _, tx := range regularTxns {
+			minedHashes = append(minedHashes, *tx.Hash())
+		}
+		s.mixMsgPool.RemoveConfirmedMixes(minedHashes)
+
+		if r := s.rpcServer; r != nil {
+			// Filter and update the rebroadcast inventory.
+			s.PruneRebroadcastInventory()
+
+			// Notify registered websocket clients of incoming block.
+			r.NotifyBlockConnected(block)
+		}
+
+		if s.bg != nil {
+			s.bg.BlockConnected(block)
+		}
+
+	// Stake tickets are spent or missed from the most recently connected
+	// block.
+	case blockchain.NTNewTickets:
+		tnd, ok := notification.Data.(*blockchain.TicketNotificationsData)
+		if !ok {
+			syncLog.Warnf("Tickets connected notification is not " +
+				"TicketNotificationsData")
+			break
+		}
+
+		if r := s.rpcServer; r != nil {
+			r.NotifyNewTickets(tnd)
+		}
+
+	// A block has been disconnected from the main block chain.
+	case blockchain.NTBlockDisconnected:
+		ntfn, ok := notification.Data.(*blockchain.BlockDisconnectedNtfnsData)
+		if !ok {
+			syncLog.Warnf("Block disconnected notification is not " +
+				"BlockDisconnectedNtfnsData.")
+			break
+		}
+		block := ntfn.Block
+		parentBlock := ntfn.ParentBlock
+		isTreasuryEnabled := ntfn.CheckTxFlags.IsTreasuryEnabled()
+
+		// In the case the regular tree of the previous block was
+		// disapproved, disconnecting the current block makes all of those
+		// transactions valid again.  Thus, with the exception of the
+		// coinbase, remove all of those transactions and any that are now
+		// double spends from the transaction pool.  Transactions which depend
+		// on a confirmed transaction are NOT removed recursively because they
+		// are still valid.
+		txMemPool := s.txMemPool
+		if !headerApprovesParent(&block.MsgBlock().Header) {
+			for _, tx := range parentBlock.Transactions()[1:] {
+				txMemPool.RemoveTransaction(tx, false)
+				txMemPool.MaybeAcceptDependents(tx, isTreasuryEnabled)
+				txMemPool.RemoveDoubleSpends(tx)
+				txMemPool.RemoveOrphan(tx)
+				txMemPool.ProcessOrphans(tx, ntfn.CheckTxFlags)
+			}
+		}
+
+		// Add all of the regular and stake transactions in the disconnected
+		// block, with the exception of the regular tree coinbase, back to the
+		// transaction pool to be mined in a future block.
+		//
+		// Notice that, in the case the previous block was disapproved, some
+		// of the transactions in the block being disconnected might have been
+		// included in the previous block and others might also have been
+		// spending some of the same outputs.  However, since the previous
+		// block is no longer disapproved, those transactions are no longer
+		// valid as of the new tip and therefore will not be accepted.
+		//
+		// Finally, if transactions fail to add to the pool for some reason
+		// other than the pool already having it (a duplicate) or now being a
+		// double spend, remove all transactions that depend on it as well.
+		handleDisconnectedBlockTxns := func(txns []*VGLutil.Tx) {
+			for _, tx := range txns {
+				_, err := txMemPool.MaybeAcceptTransaction(tx, false)
+				if err != nil && !isDoubleSpendOrDuplicateError(err) {
+					txMemPool.RemoveTransaction(tx, true)
+				}
+			}
+		}
+		handleDisconnectedBlockTxns(block.Transactions()[1:])
+		if isTreasuryEnabled {
+			// Skip the treasurybase.
+			handleDisconnectedBlockTxns(block.STransactions()[1:])
+		} else {
+			handleDisconnectedBlockTxns(block.STransactions())
+		}
+
+		if s.bg != nil {
+			s.bg.BlockDisconnected(block)
+		}
+
+		// Notify registered websocket clients.
+		if r := s.rpcServer; r != nil {
+			// Filter and update the rebroadcast inventory.
+			s.PruneRebroadcastInventory()
+
+			// Notify registered websocket clients.
+			r.NotifyBlockDisconnected(block)
+		}
+
+	// Chain reorganization has commenced.
+	case blockchain.NTChainReorgStarted:
+		if s.bg != nil {
+			s.bg.ChainReorgStarted()
+		}
+
+	// Chain reorganization has concluded.
+	case blockchain.NTChainReorgDone:
+		if s.bg != nil {
+			s.bg.ChainReorgDone()
+		}
+
+	// The blockchain is reorganizing.
+	case blockchain.NTReorganization:
+		rd, ok := notification.Data.(*blockchain.ReorganizationNtfnsData)
+		if !ok {
+			syncLog.Warnf("Chain reorganization notification is malformed")
+			break
+		}
+
+		// Notify registered websocket clients.
+		if r := s.rpcServer; r != nil {
+			r.NotifyReorganization(rd)
+		}
+	}
+}
+
+// Run starts the server and blocks until the provided context is cancelled.
+// This entails accepting connections from peers.
+func (s *server) Run(ctx context.Context) {
+	srvrLog.Trace("Starting server")
+
+	// Start the address manager which is needed by peers.  This is done here
+	// since its lifecycle is closely tied to this server.
+	s.addrManager.Start()
+
+	// Start all of the subsystems and wait for them to shutdown.
+	var wg sync.WaitGroup
+	runSubsystem := func(run func(ctx context.Context)) {
+		wg.Add(1)
+		go func() {
+			run(ctx)
+			wg.Done()
+		}()
+	}
+	runSubsystem(s.indexSubscriber.Run)
+	runSubsystem(s.syncManager.Run)
+	runSubsystem(s.connManager.Run)
+	runSubsystem(s.rebroadcastHandler)
+	if s.nat != nil {
+		runSubsystem(s.upnpUpdateThread)
+	}
+	if s.bg != nil {
+		runSubsystem(s.bg.Run)
+	}
+	if s.cpuMiner != nil {
+		runSubsystem(s.cpuMiner.Run)
+
+		// Start the CPU miner if generation is enabled.
+		if cfg.Generate {
+			s.cpuMiner.SetNumWorkers(-1)
+		}
+	}
+	if s.stratumServer != nil {
+		runSubsystem(s.stratumServer.Run)
+	}
+	if s.rpcServer != nil {
+		runSubsystem(s.rpcServer.Run)
+	}
+	if !cfg.NoPersistMempool {
+		runSubsystem(s.loadMempool)
+	}
+
+	// Query the seeders for peers and connect to the persistent peers.
+	if len(cfg.ConnectPeers) == 0 && !cfg.DisableSeeders && !cfg.SimNet &&
+		!cfg.RegNet {
+
+		s.querySeeders(ctx)
+	}
+	for _, addr := range s.persistentPeerAddrs {
+		go s.connManager.Connect(ctx, &connmgr.ConnReq{
+			Addr:      addr,
+			Permanent: true,
+		})
+	}
+
+	// Wait until the server is signalled to shutdown.
+	<-ctx.Done()
+	s.shutdown.Store(true)
+	close(s.quit)
+	srvrLog.Warnf("Server shutting down")
+
+	// Disconnect all peers.
+	s.peerState.Lock()
+	s.peerState.forAllPeers(func(sp *serverPeer) {
+		sp.Disconnect()
+	})
+	s.peerState.Unlock()
+
+	// Wait for all subsystems to shutdown.
+	wg.Wait()
+
+	// Save the mempool so it can be reloaded on the next startup.  This is
+	// skipped when reloading the previously saved mempool was interrupted to
+	// avoid overwriting it with a partial one.
+	if !cfg.NoPersistMempool && s.mempoolLoaded.Load() {
+		s.saveMempool()
+	}
+
+	s.feeEstimator.Close()
+	if err := s.addrManager.Stop(); err != nil {
+		srvrLog.Errorf("Failed to stop address manager: %v", err)
+	}
+	s.chain.ShutdownUtxoCache()
+
+	srvrLog.Trace("Server stopped")
+}
+
+// loadMempool reloads the transactions saved to the mempool file during the
+// previous shutdown into the mempool.  Every transaction is revalidated against
+// the current state of the chain, so any that were mined or otherwise became
+// invalid while the server was not running are discarded.
+//
+// This must be run as a goroutine.
+func (s *server) loadMempool(ctx context.Context) {
+	f, err := os.Open(s.mempoolFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			srvrLog.Errorf("Unable to open saved mempool: %v", err)
+		}
+		s.mempoolLoaded.Store(true)
+		return
+	}
+	defer f.Close()
+
+	srvrLog.Infof("Loading saved mempool from %s", s.mempoolFile)
+	accepted, rejected, err := s.txMemPool.Load(ctx, bufio.NewReader(f))
+	switch {
+	case ctx.Err() != nil:
+		return
+	case err != nil:
+		srvrLog.Errorf("Unable to load saved mempool: %v", err)
+	default:
+		srvrLog.Infof("Loaded %d saved mempool transactions (%d no longer "+
+			"valid)", accepted, rejected)
+	}
+	s.mempoolLoaded.Store(true)
+}
+
+// saveMempool saves the transactions in the mempool to the mempool file so they
+// can be reloaded on the next startup.  The transactions are written to a
+// temporary file which is then moved into place to avoid leaving a partially
+// written file behind.
+func (s *server) saveMempool() {
+	tmpFile := s.mempoolFile + ".new"
+	f, err := os.Create(tmpFile)
+	if err != nil {
+		srvrLog.Errorf("Unable to create file %s: %v", tmpFile, err)
+		return
+	}
+	w := bufio.NewWriter(f)
+	numTxns, err := s.txMemPool.Dump(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(tmpFile)
+		srvrLog.Errorf("Unable to save mempool: %v", err)
+		return
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpFile)
+		srvrLog.Errorf("Unable to save mempool: %v", err)
+		return
+	}
+	if err := os.Rename(tmpFile, s.mempoolFile); err != nil {
+		srvrLog.Errorf("Unable to save mempool: %v", err)
+		return
+	}
+	srvrLog.Infof("Saved %d mempool transactions to %s", numTxns,
+		s.mempoolFile)
+}
+
+// parseListeners determines whether each listen address is IPv4 and IPv6 and
+// returns a slice of appropriate net.Addrs to listen on with TCP.  It also
+// properly detects addresses which apply to "all interfaces" and adds the
+// address as both IPv4 and IPv6.
+func parseListeners(addrs []string) ([]net.Addr, error) {
+	netAddrs := make([]net.Addr, 0, len(addrs)*2)
+	for _, addr := range addrs {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			// Shouldn't happen due to already being normalized.
+			return nil, err
+		}
+
+		// Empty host or host of * on plan9 is both IPv4 and IPv6.
+		if host == "" || (host == "*" && runtime.GOOS == "plan9") {
+			netAddrs = append(netAddrs, simpleAddr{net: "tcp4", addr: addr})
+			netAddrs = append(netAddrs, simpleAddr{net: "tcp6", addr: addr})
+			continue
+		}
+
+		// Strip IPv6 zone id if present since net.ParseIP does not handle
+		// it.
+		zoneIndex := strings.LastIndex(host, "%")
+		if zoneIndex > 0 {
+			host = host[:zoneIndex]
+		}
+
+		// Parse the IP.
+		ip := net.ParseIP(host)
+		if ip == nil {
+			hostAddrs, err := net.LookupHost(host)
+			if err != nil {
+				return nil, err
+			}
+			ip = net.ParseIP(hostAddrs[0])
+			if ip == nil {
+				return nil, fmt.Errorf("cannot resolve IP address for host %q",
+					host)
+			}
+		}
+
+		// To4 returns nil when the IP is not an IPv4 address, so use this to
+		// determine the address type.
+		if ip.To4() == nil {
+			netAddrs = append(netAddrs, simpleAddr{net: "tcp6", addr: addr})
+		} else {
+			netAddrs = append(netAddrs, simpleAddr{net: "tcp4", addr: addr})
+		}
+	}
+	return netAddrs, nil
+}
+
+// genCertPair generates a key/cert pair to the paths provided.
+func genCertPair(certFile, keyFile string, altDNSNames []string, curve elliptic.Curve) error {
+	rpcsLog.Infof("Generating TLS certificates...")
+
+	org := "vgld autogenerated cert"
+	validUntil := time.Now().Add(10 * 365 * 24 * time.Hour)
+	cert, key, err := certgen.NewTLSCertPair(curve, org, validUntil,
+		altDNSNames)
+	if err != nil {
+		return err
+	}
+
+	// Write cert and key files.
+	if err = os.WriteFile(certFile, cert, 0644); err != nil {
+		return err
+	}
+	if err = os.WriteFile(keyFile, key, 0600); err != nil {
+		os.Remove(certFile)
+		return err
+	}
+
+	rpcsLog.Infof("Done generating TLS certificates")
+	return nil
+}
+
+// setupRPCListeners returns a slice of listeners that are configured for use
+// with the RPC server depending on the configuration settings for listen
+// addresses and TLS.
+func setupRPCListeners() ([]net.Listener, error) {
+	// Setup TLS if not disabled.
+	listenFunc := net.Listen
+	if !cfg.DisableTLS {
+		// Generate the TLS cert and key file if both don't already exist.
+		if !fileExists(cfg.RPCKey) && !fileExists(cfg.RPCCert) {
+			curve, err := tlsCurve(cfg.TLSCurve)
+			if err != nil {
+				return nil, err
+			}
+			err = genCertPair(cfg.RPCCert, cfg.RPCKey, cfg.AltDNSNames, curve)
+			if err != nil {
+				return nil, err
+			}
+		}
+		keypair, err := tls.LoadX509KeyPair(cfg.RPCCert, cfg.RPCKey)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig := tls.Config{
+			Certificates: []tls.Certificate{keypair},
+			MinVersion:   tls.VersionTLS12,
+		}
+
+		// Require and verify client certificates signed by the configured
+		// certificate authorities when client certificate authentication is
+		// enabled.
+		if cfg.RPCAuthType == authTypeClientCert {
+			pemCerts, err := os.ReadFile(cfg.RPCClientCAs)
+			if err != nil {
+				return nil, err
+			}
+			clientCAs := x509.NewCertPool()
+			if !clientCAs.AppendCertsFromPEM(pemCerts) {
+				return nil, fmt.Errorf("no certificates found in %q",
+					cfg.RPCClientCAs)
+			}
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+			tlsConfig.ClientCAs = clientCAs
+		}
+
+		// Change the standard net.Listen function to the tls one.
+		listenFunc = func(net string, laddr string) (net.Listener, error) {
+			return tls.Listen(net, laddr, &tlsConfig)
+		}
+	}
+
+	netAddrs, err := parseListeners(cfg.RPCListeners)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(netAddrs))
+	for _, addr := range netAddrs {
+		listener, err := listenFunc(addr.Network(), addr.String())
+		if err != nil {
+			rpcsLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// setupStratumListeners returns a slice of listeners that are configured for
+// use with the stratum server depending on the configured listen addresses.
+func setupStratumListeners() ([]net.Listener, error) {
+	netAddrs, err := parseListeners(cfg.StratumListeners)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(netAddrs))
+	for _, addr := range netAddrs {
+		listener, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			minrLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// standardScriptVerifyFlags returns the script flags that should be used when
+// executing transaction scripts to enforce additional checks which are required
+// for the script to be considered standard.  Note these flags are different
+// than what is required for the consensus rules in that they are more strict.
+func standardScriptVerifyFlags(chain *blockchain.BlockChain) (txscript.ScriptFlags, error) {
+	scriptFlags := mempool.BaseStandardVerifyFlags
+
+	// Enable validation of OP_SHA256 when the associated agenda is active.
+	tipHash := &chain.BestSnapshot().Hash
+	isActive, err := chain.IsLNFeaturesAgendaActive(tipHash)
+	if err != nil {
+		return 0, err
+	}
+	if isActive {
+		scriptFlags |= txscript.ScriptVerifySHA256
+	}
+
+	// Enable validation of treasury-related opcodes when the associated
+	// agenda is active.
+	isActive, err = chain.IsTreasuryAgendaActive(tipHash)
+	if err != nil {
+		return 0, err
+	}
+	if isActive {
+		scriptFlags |= txscript.ScriptVerifyTreasury
+	}
+	return scriptFlags, nil
+}
+
+// mixpoolChain adapts a block chain instance to the mixpool.BlockChain
+// interface.
+type mixpoolChain struct {
+	chain *blockchain.BlockChain
+}
+
+// Ensure mixpoolChain implements the mixpool.BlockChain interface.
+var _ mixpool.BlockChain = (*mixpoolChain)(nil)
+
+// ChainParams returns the chain parameters the block chain is associated with.
+//
+// This is part of the mixpool.BlockChain interface implementation.
+func (m *mixpoolChain) ChainParams() *chaincfg.Params {
+	return m.chain.ChainParams()
+}
+
+// CurrentTip returns the hash and height of the current tip block.
+//
+// This is part of the mixpool.BlockChain interface implementation.
+func (m *mixpoolChain) CurrentTip() (chainhash.Hash, int64) {
+	best := m.chain.BestSnapshot()
+	return best.Hash, best.Height
+}
+
+// newServer returns a new vgld server configured to listen on addr for the
+// network type specified by chainParams.  Use Run to begin accepting
+// connections from peers.
+func newServer(ctx context.Context, profiler *profileServer, listenAddrs []string, db database.DB, utxoDb *leveldb.DB, chainParams *chaincfg.Params, dataDir string) (*server, error) {
+	services := defaultServices
+
+	// Pruned nodes are not able to serve the full block chain, so advertise
+	// the limited network service in place of the full network service.
+	if cfg.Prune != 0 {
+		services &^= wire.SFNodeNetwork
+		services |= wire.SFNodeNetworkLimited
+	}
+
+	amgr := addrmgr.New(dataDir)
+	var listeners []net.Listener
+	var nat *upnpNAT
+	if !cfg.DisableListen {
+		var err error
+		listeners, nat, err = initListeners(ctx, chainParams, amgr,
+			listenAddrs, services)
+		if err != nil {
+			return nil, err
+		}
+		if len(listeners) == 0 {
+			return nil, errors.New("no valid listen address")
+		}
+	}
+
+	sigCache, err := txscript.NewSigCache(cfg.SigCacheMaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	s := server{
+		chainParams:          chainParams,
+		services:             services,
+		db:                   db,
+		timeSource:           blockchain.NewMedianTime(),
+		subsidyCache:         standalone.NewSubsidyCache(chainParams),
+		sigCache:             sigCache,
+		nat:                  nat,
+		addrManager:          amgr,
+		mempoolFile:          filepath.Join(dataDir, mempoolFileName),
+		modifyRebroadcastInv: make(chan interface{}),
+		peerState:            makePeerState(),
+		naSubmissionCache: naSubmissionCache{
+			cache: make(map[string]*naSubmission, maxCachedNaSubmissions),
+			limit: maxCachedNaSubmissions,
+		},
+		quit:                  make(chan struct{}),
+		indexSubscriber:       indexers.NewIndexSubscriber(ctx),
+		lotteryDataBroadcast:  make(map[chainhash.Hash]int64),
+		recentlyConfirmedTxns: apbf.NewFilter(maxRecentlyConfirmedTxns, recentlyConfirmedTxnsFPRate),
+	}
+
+	feC := fees.EstimatorConfig{
+		MinBucketFee: cfg.minRelayTxFee,
+		MaxBucketFee: VGLutil.Amount(fees.DefaultMaxBucketFeeMultiplier) *
+			cfg.minRelayTxFee,
+		MaxConfirms:  fees.DefaultMaxConfirmations,
+		FeeRateStep:  fees.DefaultFeeRateStep,
+		DatabaseFile: filepath.Join(dataDir, "feesdb"),
+
+		// 1e5 is the previous mempool.DefaultMinRelayTxFee that wallets which
+		// have not been upgraded will be using, so track this particular rate
+		// explicitly.  Note that bumping this value will cause the existing
+		// fees database to become invalid and will force nodes to explicitly
+		// delete it.
+		ExtraBucketFee: 1e5,
+	}
+	s.feeEstimator, err = fees.NewEstimator(&feC)
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine the block to assume as valid.  It defaults to the value
+	// specified by the network parameters, may be overridden via the
+	// configuration, and is disabled when the configured value is "0".
+	assumeValid := chainParams.AssumeValid
+	switch cfg.AssumeValid {
+	case "":
+	case "0":
+		assumeValid = chainhash.Hash{}
+	default:
+		hash, err := chainhash.NewHashFromStr(cfg.AssumeValid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid assumevalid %q: %w",
+				cfg.AssumeValid, err)
+		}
+		assumeValid = *hash
+	}
+
+	// Create a new block chain instance with the appropriate configuration.
+	utxoBackend := blockchain.NewLevelDbUtxoBackend(utxoDb)
+	utxoCache := blockchain.NewUtxoCache(&blockchain.UtxoCacheConfig{
+		Backend:      utxoBackend,
+		FlushBlockDB: s.db.Flush,
+		MaxSize:      uint64(cfg.UtxoCacheMaxSize) * 1024 * 1024,
+	})
+	s.chain, err = blockchain.New(ctx, &blockchain.Config{
+		DB:              s.db,
+		UtxoBackend:     utxoBackend,
+		ChainParams:     s.chainParams,
+		AllowOldForks:   cfg.AllowOldForks,
+		AssumeValid:     assumeValid,
+		TimeSource:      s.timeSource,
+		Notifications:   s.handleBlockchainNotification,
+		SigCache:        s.sigCache,
+		SubsidyCache:    s.subsidyCache,
+		IndexSubscriber: s.indexSubscriber,
+		UtxoCache:       utxoCache,
+		PruneTarget:     uint64(cfg.Prune) * 1024 * 1024,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the transaction, address, and exists address indexes if needed.
+	queryer := &blockchain.ChainQueryerAdapter{BlockChain: s.chain}
+	if cfg.TxIndex {
+		indxLog.Info("Transaction index is enabled")
+		s.txIndex, err = indexers.NewTxIndex(s.indexSubscriber, db, queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.AddrIndex {
+		indxLog.Info("Address index is enabled")
+		s.addrIndex, err = indexers.NewAddrIndex(s.indexSubscriber, db,
+			queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !cfg.NoExistsAddrIndex {
+		indxLog.Info("Exists address index is enabled")
+		s.existsAddrIndex, err = indexers.NewExistsAddrIndex(s.indexSubscriber,
+			db, queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Ensure all indexes are caught up before proceeding.
+	err = s.indexSubscriber.CatchUp(ctx, s.db, queryer)
+	if err != nil {
+		return nil, err
+	}
+
+	txC := mempool.Config{
+		Policy: mempool.Policy{
+			EnableAncestorTracking: len(cfg.miningAddrs) > 0,
+			AcceptNonStd:           cfg.AcceptNonStd,
+			MaxOrphanTxs:           cfg.MaxOrphanTxs,
+			MaxOrphanTxSize:        mempool.MaxStandardTxSize,
+			MaxSigOpsPerTx:         blockchain.MaxSigOpsPerBlock / 5,
+			MinRelayTxFee:          cfg.minRelayTxFee,
+			MaxPoolSize:            int64(cfg.MaxMempool) * 1000 * 1000,
+			AllowOldVotes:          cfg.AllowOldVotes,
+			MaxVoteAge: func() uint16 {
+				switch chainParams.Net {
+				case wire.MainNet, wire.SimNet, wire.RegNet:
+					return chainParams.CoinbaseMaturity
+
+				default:
+					return defaultMaximumVoteAge
+				}
+			}(),
+			StandardVerifyFlags: func() (txscript.ScriptFlags, error) {
+				return standardScriptVerifyFlags(s.chain)
+			},
+		},
+		ChainParams: chainParams,
+		NextStakeDifficulty: func() (int64, error) {
+			return s.chain.BestSnapshot().NextStakeDiff, nil
+		},
+		FetchUtxoView:    s.chain.FetchUtxoView,
+		BlockByHash:      s.chain.BlockByHash,
+		BestHash:         func() *chainhash.Hash { return &s.chain.BestSnapshot().Hash },
+		BestHeight:       func() int64 { return s.chain.BestSnapshot().Height },
+		HeaderByHash:     s.chain.HeaderByHash,
+		CalcSequenceLock: s.chain.CalcSequenceLock,
+		SubsidyCache:     s.subsidyCache,
+		SigCache:         s.sigCache,
+		PastMedianTime: func() time.Time {
+			return s.chain.BestSnapshot().MedianTime
+		},
+		AddTxToFeeEstimation:      s.feeEstimator.AddMemPoolTransaction,
+		RemoveTxFromFeeEstimation: s.feeEstimator.RemoveMemPoolTransaction,
+		OnVoteReceived: func(voteTx *VGLutil.Tx) {
+			if s.bg != nil {
+				s.bg.VoteReceived(voteTx)
+			}
+		},
+		IsTreasuryAgendaActive: func() (bool, error) {
+			tipHash := &s.chain.BestSnapshot().Hash
+			return s.chain.IsTreasuryAgendaActive(tipHash)
+		},
+		IsAutoRevocationsAgendaActive: func() (bool, error) {
+			tipHash := &s.chain.BestSnapshot().Hash
+			return s.chain.IsAutoRevocationsAgendaActive(tipHash)
+		},
+		IsSubsidySplitAgendaActive: func() (bool, error) {
+			tipHash := &s.chain.BestSnapshot().Hash
+			return s.chain.IsSubsidySplitAgendaActive(tipHash)
+		},
+		IsSubsidySplitR2AgendaActive: func() (bool, error) {
+			tipHash := &s.chain.BestSnapshot().Hash
+			return s.chain.IsSubsidySplitR2AgendaActive(tipHash)
+		},
+		OnTSpendReceived: func(voteTx *VGLutil.Tx) {
+			if s.rpcServer != nil {
+				s.rpcServer.NotifyTSpend(voteTx)
+			}
+		},
+		TSpendMinedOnAncestor: func(tspend chainhash.Hash) error {
+			tipHash := s.chain.BestSnapshot().Hash
+			return s.chain.CheckTSpendExists(tipHash, tspend)
+		},
+	}
+	if s.existsAddrIndex != nil {
+		txC.ExistsAddrIndex = s.existsAddrIndex
+	}
+	if s.addrIndex != nil {
+		txC.AddrIndex = s.addrIndex
+	}
+	s.txMemPool = mempool.New(&txC)
+
+	s.mixMsgPool = mixpool.NewPool(&mixpoolChain{chain: s.chain})
+
+	s.syncManager = netsync.New(&netsync.Config{
+		PeerNotifier:          &s,
+		ChainParams:           s.chainParams,
+		Chain:                 s.chain,
+		TimeSource:            s.timeSource,
+		TxMemPool:             s.txMemPool,
+		NoMiningStateSync:     cfg.NoMiningStateSync,
+		MaxPeers:              cfg.MaxPeers,
+		MaxOrphanTxs:          cfg.MaxOrphanTxs,
+		RecentlyConfirmedTxns: s.recentlyConfirmedTxns,
+		MixPool:               s.mixMsgPool,
+	})
+
+	// Create the background block template generator, CPU miner, and stratum
+	// server if the config has a mining address.
+	if len(cfg.miningAddrs) > 0 {
+		tg := mining.NewBlkTmplGenerator(&mining.Config{
+			Policy: &mining.Policy{
+				BlockMaxSize:     cfg.BlockMaxSize,
+				TxMinFreeFee:     cfg.minRelayTxFee,
+				AggressiveMining: !cfg.NonAggressive,
+				StandardVerifyFlags: func() (txscript.ScriptFlags, error) {
+					return standardScriptVerifyFlags(s.chain)
+				},
+			},
+			TxSource:                   s.txMemPool,
+			TimeSource:                 s.timeSource,
+			SubsidyCache:               s.subsidyCache,
+			ChainParams:                s.chainParams,
+			MiningTimeOffset:           cfg.MiningTimeOffset,
+			BestSnapshot:               s.chain.BestSnapshot,
+			BlockByHash:                s.chain.BlockByHash,
+			CalcNextRequiredDifficulty: s.chain.CalcNextRequiredDifficulty,
+			CalcStakeVersionByHash:     s.chain.CalcStakeVersionByHash,
+			CheckTransactionInputs: func(tx *VGLutil.Tx, txHeight int64,
+				view *blockchain.UtxoViewpoint, checkFraudProof bool,
+				prevHeader *wire.BlockHeader, isTreasuryEnabled,
+				isAutoRevocationsEnabled bool,
+				subsidySplitVariant standalone.SubsidySplitVariant) (int64, error) {
+
+				return blockchain.CheckTransactionInputs(s.subsidyCache, tx,
+					txHeight, view, checkFraudProof, s.chainParams,
+					prevHeader, isTreasuryEnabled, isAutoRevocationsEnabled,
+					subsidySplitVariant)
+			},
+			CheckTSpendHasVotes:             s.chain.CheckTSpendHasVotes,
+			CountSigOps:                     blockchain.CountSigOps,
+			FetchUtxoEntry:                  s.chain.FetchUtxoEntry,
+			FetchUtxoView:                   s.chain.FetchUtxoView,
+			FetchUtxoViewParentTemplate:     s.chain.FetchUtxoViewParentTemplate,
+			ForceHeadReorganization:         s.chain.ForceHeadReorganization,
+			HeaderByHash:                    s.chain.HeaderByHash,
+			IsFinalizedTransaction:          blockchain.IsFinalizedTransaction,
+			IsHeaderCommitmentsAgendaActive: s.chain.IsHeaderCommitmentsAgendaActive,
+			IsTreasuryAgendaActive:          s.chain.IsTreasuryAgendaActive,
+			IsAutoRevocationsAgendaActive:   s.chain.IsAutoRevocationsAgendaActive,
+			IsSubsidySplitAgendaActive:      s.chain.IsSubsidySplitAgendaActive,
+			IsSubsidySplitR2AgendaActive:    s.chain.IsSubsidySplitR2AgendaActive,
+			MaxTreasuryExpenditure:          s.chain.MaxTreasuryExpenditure,
+			NewUtxoViewpoint: func() *blockchain.UtxoViewpoint {
+				return blockchain.NewUtxoViewpoint(utxoCache)
+			},
+			TipGeneration: s.chain.TipGeneration,
+			ValidateTransactionScripts: func(tx *VGLutil.Tx,
+				utxoView *blockchain.UtxoViewpoint, flags txscript.ScriptFlags,
+				isAutoRevocationsEnabled bool) error {
+
+				return blockchain.ValidateTransactionScripts(tx, utxoView,
+					flags, s.sigCache, isAutoRevocationsEnabled)
+			},
+		})
+
+		s.bg = mining.NewBgBlkTmplGenerator(&mining.BgBlkTmplConfig{
+			TemplateGenerator:   tg,
+			MiningAddrs:         cfg.miningAddrs,
+			AllowUnsyncedMining: cfg.AllowUnsyncedMining,
+			IsCurrent:           s.syncManager.IsCurrent,
+		})
+
+		s.cpuMiner = cpuminer.New(&cpuminer.Config{
+			ChainParams:                s.chainParams,
+			PermitConnectionlessMining: cfg.SimNet || cfg.RegNet,
+			BgBlkTmplGenerator:         s.bg,
+			ProcessBlock:               s.syncManager.ProcessBlock,
+			ConnectedCount:             s.ConnectedCount,
+			IsCurrent:                  s.syncManager.IsCurrent,
+			IsBlake3PowAgendaActive:    s.chain.IsBlake3PowAgendaActive,
+			IsKawpowAgendaActive:       s.chain.IsKawpowAgendaActive,
+		})
+
+		if len(cfg.StratumListeners) > 0 {
+			stratumListeners, err := setupStratumListeners()
+			if err != nil {
+				return nil, err
+			}
+			if len(stratumListeners) == 0 {
+				return nil, errors.New("no usable stratum listen addresses")
+			}
+
+			s.stratumServer, err = stratum.New(&stratum.Config{
+				ChainParams:       s.chainParams,
+				Listeners:         stratumListeners,
+				BlockTemplater:    s.bg,
+				ProcessBlock:      s.syncManager.ProcessBlock,
+				InitialDifficulty: cfg.StratumDiff,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Only setup a function to return new addresses to connect to when not
+	// running in connect-only mode.  The simulation and regression test
+	// networks are always in connect-only mode since they are only intended
+	// to connect to specified peers and actively avoid advertising and
+	// connecting to discovered peers in order to prevent it from becoming a
+	// public test network.
+	var newAddressFunc func() (net.Addr, error)
+	if !cfg.SimNet && !cfg.RegNet && len(cfg.ConnectPeers) == 0 {
+		newAddressFunc = func() (net.Addr, error) {
+			for tries := 0; tries < 100; tries++ {
+				addr := s.addrManager.GetAddress()
+				if addr == nil {
+					break
+				}
+
+				// Address will not be invalid, local or unroutable because
+				// addrmanager rejects those on addition.  Just check that we
+				// don't already have an address in the same group so that we
+				// are not connecting to the same network segment at the
+				// expense of others.
+				netAddr := addr.NetAddress()
+				if !isDialableNetAddressType(netAddr.Type) {
+					continue
+				}
+				key := netAddr.GroupKey()
+				if s.OutboundGroupCount(key) != 0 {
+					continue
+				}
+
+				// only allow recent nodes (10mins) after we failed 30 times
+				if tries < 30 && time.Since(addr.LastAttempt()) < 10*time.Minute {
+					continue
+				}
+
+				// allow nondefault ports after 50 failed tries.
+				if fmt.Sprintf("%d", netAddr.Port) !=
+					s.chainParams.DefaultPort && tries < 50 {
+					continue
+				}
+
+				return addrStringToNetAddr(netAddr.Key())
+			}
+
+			return nil, errors.New("no valid connect address")
+		}
+	}
+
+	// Create a connection manager.
+	targetOutbound := defaultTargetOutbound
+	if cfg.MaxPeers < targetOutbound {
+		targetOutbound = cfg.MaxPeers
+	}
+	cmgr, err := connmgr.New(&connmgr.Config{
+		Listeners:      listeners,
+		OnAccept:       s.inboundPeerConnected,
+		RetryDuration:  connectionRetryInterval,
+		TargetOutbound: uint32(targetOutbound),
+		Dial:           vgldDial,
+		Timeout:        cfg.DialTimeout,
+		OnConnection:   s.outboundPeerConnected,
+		GetNewAddress:  newAddressFunc,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.connManager = cmgr
+
+	// Resolve the persistent peers to connect to once the server is running.
+	permanentPeers := cfg.ConnectPeers
+	if len(permanentPeers) == 0 {
+		permanentPeers = cfg.AddPeers
+	}
+	for _, addr := range permanentPeers {
+		netAddr, err := addrStringToNetAddr(addr)
+		if err != nil {
+			return nil, err
+		}
+		s.persistentPeerAddrs = append(s.persistentPeerAddrs, netAddr)
+	}
+
+	if !cfg.DisableRPC {
+		// Setup listeners for the configured RPC listen addresses and TLS
+		// settings.
+		rpcListeners, err := setupRPCListeners()
+		if err != nil {
+			return nil, err
+		}
+		if len(rpcListeners) == 0 {
+			return nil, errors.New("no usable rpc listen addresses")
+		}
+
+		if cfg.BoundAddrEvents {
+			notifyAddrServer := newBoundAddrEventServer(outgoingPipeMessages)
+			for _, listener := range rpcListeners {
+				notifyAddrServer.notifyRPCAddress(listener.Addr().String())
+			}
+		}
+
+		rpcsConfig := rpcserver.Config{
+			Listeners:    rpcListeners,
+			ConnMgr:      &rpcConnManager{&s},
+			SyncMgr:      &rpcSyncMgr{server: &s, syncMgr: s.syncManager},
+			FeeEstimator: s.feeEstimator,
+			TimeSource:   s.timeSource,
+			Services:     s.services,
+			AddrManager:  s.addrManager,
+			Clock:        &rpcClock{},
+			SubsidyCache: s.subsidyCache,
+			Chain:        &rpcChain{s.chain},
+			ChainParams:  chainParams,
+			SanityChecker: &rpcSanityChecker{
+				chain:       s.chain,
+				chainParams: chainParams,
+			},
+			DB:                   db,
+			TxMempooler:          s.txMemPool,
+			CPUMiner:             &rpcCPUMiner{s.cpuMiner},
+			NetInfo:              cfg.generateNetworkInfo(),
+			MinRelayTxFee:        cfg.minRelayTxFee,
+			Proxy:                cfg.Proxy,
+			RPCUser:              cfg.RPCUser,
+			RPCPass:              cfg.RPCPass,
+			RPCLimitUser:         cfg.RPCLimitUser,
+			RPCLimitPass:         cfg.RPCLimitPass,
+			RPCMaxClients:        cfg.RPCMaxClients,
+			RPCMaxConcurrentReqs: cfg.RPCMaxConcurrentReqs,
+			RPCMaxWebsockets:     cfg.RPCMaxWebsockets,
+			REST:                 cfg.REST,
+			RESTRateLimit:        cfg.RESTRateLimit,
+			TestNet:              cfg.TestNet,
+			MiningAddrs:          cfg.miningAddrs,
+			AllowUnsyncedMining:  cfg.AllowUnsyncedMining,
+			MaxProtocolVersion:   maxProtocolVersion,
+			UserAgentVersion:     userAgentVersion,
+			LogManager:           &rpcLogManager{},
+			FiltererV2:           s.chain,
+			MixPooler:            s.mixMsgPool,
+			StartupTime:          time.Now().Unix(),
+			ProfilerMgr:          profiler,
+		}
+		if s.bg != nil {
+			rpcsConfig.BlockTemplater = &rpcBlockTemplater{s.bg}
+		}
+		if s.txIndex != nil {
+			rpcsConfig.TxIndexer = s.txIndex
+		}
+		if s.addrIndex != nil {
+			rpcsConfig.AddrIndexer = s.addrIndex
+		}
+		if s.existsAddrIndex != nil {
+			rpcsConfig.ExistsAddresser = s.existsAddrIndex
+		}
+
+		s.rpcServer, err = rpcserver.New(&rpcsConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		// Signal process shutdown when the RPC server requests it.
+		go func() {
+			<-s.rpcServer.RequestedProcessShutdown()
+			shutdownRequestChannel <- struct{}{}
+		}()
+	}
+
+	// Register the metrics that describe the state owned by the server.
+	registerServerMetrics(&s)
+
+	return &s, nil
+}
+
+// initListeners initializes the configured net listeners and adds any bound
+// addresses to the address manager.  Returns the listeners and a upnpNAT
+// interface, which is non-nil if UPnP is in use.
+func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.AddrManager, listenAddrs []string, services wire.ServiceFlag) ([]net.Listener, *upnpNAT, error) {
+	// Listen for TCP connections at the configured addresses.
+	netAddrs, err := parseListeners(listenAddrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var notifyAddrServer boundAddrEventServer
+	if cfg.BoundAddrEvents {
+		notifyAddrServer = newBoundAddrEventServer(outgoingPipeMessages)
+	}
+
+	listeners := make([]net.Listener, 0, len(netAddrs))
+	for _, addr := range netAddrs {
+		listener, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			srvrLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+
+		if notifyAddrServer != nil {
+			notifyAddrServer.notifyP2PAddress(listener.Addr().String())
+		}
+	}
+
+	var nat *upnpNAT
+	if len(cfg.ExternalIPs) != 0 {
+		defaultPort, err := strconv.ParseUint(params.DefaultPort, 10, 16)
+		if err != nil {
+			srvrLog.Errorf("Can not parse default port %s for active chain: %v",
+				params.DefaultPort, err)
+			return nil, nil, err
+		}
+
+		for _, sip := range cfg.ExternalIPs {
+			eport := uint16(defaultPort)
+			host, portstr, err := net.SplitHostPort(sip)
+			if err != nil {
+				// no port, use default.
+				host = sip
+			} else {
+				port, err := strconv.ParseUint(portstr, 10, 16)
+				if err != nil {
+					srvrLog.Warnf("Can not parse port from %s for "+
+						"externalip: %v", sip, err)
+					continue
+				}
+				eport = uint16(port)
+			}
+			na, err := hostToNetAddress(host, eport, services, vgldLookup)
+			if err != nil {
+				srvrLog.Warnf("Not adding %s as externalip: %v", sip, err)
+				continue
+			}
+
+			err = amgr.AddLocalAddress(na, addrmgr.ManualPrio)
+			if err != nil {
+				amgrLog.Warnf("Skipping specified external IP: %v", err)
+			}
+		}
+	} else {
+		if cfg.Upnp {
+			var err error
+			nat, err = discover(ctx)
+			if err != nil {
+				srvrLog.Warnf("Can't discover upnp: %v", err)
+			}
+			// nil nat here is fine, just means no upnp on network.
+		}
+
+		// Add bound addresses to address manager to be advertised to peers.
+		for _, listener := range listeners {
+			addr := listener.Addr().String()
+			err := addLocalAddress(amgr, addr, services)
+			if err != nil {
+				amgrLog.Warnf("Skipping bound address %s: %v", addr, err)
+			}
+		}
+	}
+
+	return listeners, nat, nil
+}
+
+// disconnectPeer attempts to drop the connection of a targeted peer in the
+// passed peer list.  Targets are identified via usage of the passed
+// `compareFunc`, which should return `true` if the passed peer is the target
+// peer.  This function returns true on success and false if the peer is unable
+// to be located.  If the peer is found, and the passed callback: `whenFound'
+// isn't nil, we call it with the peer as the argument before it is removed
+// from the peerList, and is disconnected from the server.
+func disconnectPeer(peerList map[int32]*serverPeer, compareFunc func(*serverPeer) bool, whenFound func(*serverPeer)) bool {
+	for addr, peer := range peerList {
+		if compareFunc(peer) {
+			if whenFound != nil {
+				whenFound(peer)
+			}
+
+			// This is ok because we are not continuing to iterate so won't
+			// corrupt the loop.
+			delete(peerList, addr)
+			peer.Disconnect()
+			return true
+		}
+	}
+	return false
+}
@@ -5,12 +5,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/kdsmith18542/vigil/addrmgr/v3"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
 	"github.com/kdsmith18542/vigil/wire"
 )
 
@@ -102,3 +105,117 @@ func TestHostToNetAddress(t *testing.T) {
 		}
 	}
 }
+
+// TestParseListeners ensures listen addresses are mapped to the expected
+// networks, including addresses that apply to all interfaces.
+func TestParseListeners(t *testing.T) {
+	tests := []struct {
+		name    string
+		addrs   []string
+		want    []net.Addr
+		wantErr bool
+	}{{
+		name:  "all interfaces",
+		addrs: []string{":9508"},
+		want: []net.Addr{
+			simpleAddr{net: "tcp4", addr: ":9508"},
+			simpleAddr{net: "tcp6", addr: ":9508"},
+		},
+	}, {
+		name:  "IPv4 and IPv6",
+		addrs: []string{"127.0.0.1:9508", "[::1]:9508"},
+		want: []net.Addr{
+			simpleAddr{net: "tcp4", addr: "127.0.0.1:9508"},
+			simpleAddr{net: "tcp6", addr: "[::1]:9508"},
+		},
+	}, {
+		name:  "IPv6 with zone",
+		addrs: []string{"[fe80::1%eth0]:9508"},
+		want: []net.Addr{
+			simpleAddr{net: "tcp6", addr: "[fe80::1%eth0]:9508"},
+		},
+	}, {
+		name:    "missing port",
+		addrs:   []string{"127.0.0.1"},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		got, err := parseListeners(test.addrs)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestInitListeners ensures the server listeners are started on the
+// configured addresses and the configured external addresses are added to the
+// address manager as local addresses.
+func TestInitListeners(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+	cfg = &config{
+		ExternalIPs: []string{"12.1.2.3", "12.1.2.4:19000", "12.1.2.5:bad"},
+	}
+
+	params := chaincfg.RegNetParams()
+	amgr := addrmgr.New(t.TempDir())
+	listeners, nat, err := initListeners(context.Background(), params, amgr,
+		[]string{"127.0.0.1:0"}, defaultServices)
+	if err != nil {
+		t.Fatalf("unexpected error initializing listeners: %v", err)
+	}
+	defer func() {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+	}()
+	if nat != nil {
+		t.Fatal("unexpected UPnP NAT without UPnP enabled")
+	}
+	if len(listeners) != 1 {
+		t.Fatalf("unexpected number of listeners -- got %d, want 1",
+			len(listeners))
+	}
+
+	// Ensure the listener accepts connections.
+	conn, err := net.Dial("tcp", listeners[0].Addr().String())
+	if err != nil {
+		t.Fatalf("unable to connect to listener: %v", err)
+	}
+	defer conn.Close()
+	accepted, err := listeners[0].Accept()
+	if err != nil {
+		t.Fatalf("unable to accept connection: %v", err)
+	}
+	accepted.Close()
+
+	// Ensure the external addresses are added with the default port of the
+	// network when they do not specify one and the invalid one is skipped.
+	defaultPort, err := strconv.ParseUint(params.DefaultPort, 10, 16)
+	if err != nil {
+		t.Fatalf("unable to parse default port: %v", err)
+	}
+	localAddrs := amgr.LocalAddresses()
+	want := map[string]uint16{
+		"12.1.2.3": uint16(defaultPort),
+		"12.1.2.4": 19000,
+	}
+	if len(localAddrs) != len(want) {
+		t.Fatalf("unexpected number of local addresses -- got %d, want %d",
+			len(localAddrs), len(want))
+	}
+	for _, localAddr := range localAddrs {
+		port, ok := want[localAddr.Address]
+		if !ok || localAddr.Port != port {
+			t.Errorf("unexpected local address %s:%d", localAddr.Address,
+				localAddr.Port)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2015-2024 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kdsmith18542/vigil/addrmgr/v3"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// addLocalAddress adds an address that this node is listening on to the
+// address manager so that it may be relayed to peers.
+func addLocalAddress(addrMgr *addrmgr.AddrManager, addr string, services wire.ServiceFlag) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		// If bound to unspecified address, advertise all local interfaces.
+		addrs, err := net.InterfaceAddrs()
+		if err != nil {
+			return err
+		}
+
+		for _, addr := range addrs {
+			ifaceIP, _, err := net.ParseCIDR(addr.String())
+			if err != nil {
+				continue
+			}
+
+			// If bound to 0.0.0.0, do not add IPv6 interfaces and if bound to
+			// ::, do not add IPv4 interfaces.
+			if (ip.To4() == nil) != (ifaceIP.To4() == nil) {
+				continue
+			}
+
+			netAddr := addrmgr.NewNetAddressFromIPPort(ifaceIP, uint16(port),
+				services)
+			addrMgr.AddLocalAddress(netAddr, addrmgr.BoundPrio)
+		}
+	} else {
+		netAddr, err := hostToNetAddress(host, uint16(port), services,
+			vgldLookup)
+		if err != nil {
+			return err
+		}
+
+		addrMgr.AddLocalAddress(netAddr, addrmgr.BoundPrio)
+	}
+
+	return nil
+}
+
+// hostToNetAddress parses and returns an address manager network address given
+// a hostname in a supported format (IPv4, IPv6).  If the hostname cannot be
+// immediately converted from a known address format, it will be resolved using
+// the provided DNS lookup function.  If it cannot be resolved, an error is
+// returned.
+func hostToNetAddress(host string, port uint16, services wire.ServiceFlag, lookupFunc func(string) ([]net.IP, error)) (*addrmgr.NetAddress, error) {
+	addrType, addrBytes := addrmgr.EncodeHost(host)
+	if addrType != addrmgr.UnknownAddressType {
+		now := time.Unix(time.Now().Unix(), 0)
+		return addrmgr.NewNetAddressFromParams(addrType, addrBytes, port, now,
+			services)
+	}
+
+	// Cannot determine the host address type.  Must use DNS.
+	ips, err := lookupFunc(host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+	return addrmgr.NewNetAddressFromIPPort(ips[0], port, services), nil
+}
+
+// hostToWireNetAddress parses and returns a wire network address given a
+// hostname in a supported format.  It is used by peers to construct the network
+// address of the remote end of connections.
+func hostToWireNetAddress(host string, port uint16, services wire.ServiceFlag) (*wire.NetAddress, error) {
+	na, err := hostToNetAddress(host, port, services, vgldLookup)
+	if err != nil {
+		return nil, err
+	}
+
+	// Overlay network addresses can't be represented by version 1 wire
+	// network addresses, so use the unspecified address in their place.
+	if isOverlayNetAddressType(na.Type) {
+		return wire.NewNetAddressTimestamp(na.Timestamp, services,
+			net.IPv6zero, port), nil
+	}
+	return addrmgrToWireNetAddress(na), nil
+}
+
+// addrStringToNetAddr takes an address in the form of 'host:port' and returns
+// a net.Addr which maps to the original address with any host names resolved
+// to IP addresses.
+func addrStringToNetAddr(addr string) (net.Addr, error) {
+	host, strPort, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tor addresses cannot be resolved to an IP, so just return the address
+	// as is and let the proxy handle it.
+	if strings.HasSuffix(host, ".onion") {
+		if cfg.NoOnion {
+			return nil, errors.New("tor has been disabled")
+		}
+		return simpleAddr{net: "tcp", addr: addr}, nil
+	}
+
+	// Attempt to look up an IP address associated with the parsed host.
+	ips, err := vgldLookup(host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+
+	port, err := strconv.Atoi(strPort)
+	if err != nil {
+		return nil, err
+	}
+
+	return &net.TCPAddr{
+		IP:   ips[0],
+		Port: port,
+	}, nil
+}
+
+// isWhitelisted returns whether the IP address is included in the whitelisted
+// networks and IPs.
+func isWhitelisted(addr net.Addr) bool {
+	if len(cfg.whitelists) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		srvrLog.Warnf("Unable to SplitHostPort on '%s': %v", addr, err)
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		srvrLog.Warnf("Unable to parse IP '%s'", addr)
+		return false
+	}
+
+	for _, ipnet := range cfg.whitelists {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSupportedNetAddressTypeV1 returns whether the provided address manager
+// network address type is supported by the version 1 addr wire message.
+func isSupportedNetAddressTypeV1(netAddressType addrmgr.NetAddressType) bool {
+	switch netAddressType {
+	case addrmgr.IPv4Address, addrmgr.IPv6Address:
+		return true
+	}
+	return false
+}
+
+// isSupportedNetAddressTypeV2 returns whether the provided address manager
+// network address type is supported by the addrv2 wire message.
+func isSupportedNetAddressTypeV2(netAddressType addrmgr.NetAddressType) bool {
+	switch netAddressType {
+	case addrmgr.IPv4Address, addrmgr.IPv6Address, addrmgr.TORv3Address,
+		addrmgr.I2PAddress:
+		return true
+	}
+	return false
+}
+
+// isOverlayNetAddressType returns whether the provided address manager network
+// address type identifies an address on an overlay network such as Tor or I2P.
+func isOverlayNetAddressType(netAddressType addrmgr.NetAddressType) bool {
+	return netAddressType == addrmgr.TORv3Address ||
+		netAddressType == addrmgr.I2PAddress
+}
+
+// isDialableNetAddressType returns whether outbound connections can be made to
+// addresses of the provided address manager network address type given the
+// current configuration.  Tor v3 onion services require a proxy and I2P
+// destinations are only relayed since connecting to them is not supported.
+func isDialableNetAddressType(netAddressType addrmgr.NetAddressType) bool {
+	switch netAddressType {
+	case addrmgr.IPv4Address, addrmgr.IPv6Address:
+		return true
+	case addrmgr.TORv3Address:
+		return !cfg.NoOnion && (cfg.OnionProxy != "" || cfg.Proxy != "")
+	}
+	return false
+}
+
+// addrmgrToWireNetAddress converts an address manager network address to a
+// wire network address.
+func addrmgrToWireNetAddress(netAddr *addrmgr.NetAddress) *wire.NetAddress {
+	return wire.NewNetAddressTimestamp(netAddr.Timestamp, netAddr.Services,
+		netAddr.IP, netAddr.Port)
+}
+
+// wireToAddrmgrNetAddress converts a wire network address to an address
+// manager network address.
+func wireToAddrmgrNetAddress(netAddr *wire.NetAddress) *addrmgr.NetAddress {
+	newNetAddr := addrmgr.NewNetAddressFromIPPort(netAddr.IP, netAddr.Port,
+		netAddr.Services)
+	newNetAddr.Timestamp = netAddr.Timestamp
+	return newNetAddr
+}
+
+// wireToAddrmgrNetAddresses converts a collection of wire network addresses to
+// a collection of address manager network addresses.
+func wireToAddrmgrNetAddresses(netAddr []*wire.NetAddress) []*addrmgr.NetAddress {
+	addrs := make([]*addrmgr.NetAddress, len(netAddr))
+	for i, wireAddr := range netAddr {
+		addrs[i] = wireToAddrmgrNetAddress(wireAddr)
+	}
+	return addrs
+}
+
+// addrmgrToWireNetAddressV2 converts an address manager network address to a
+// version 2 wire network address.
+func addrmgrToWireNetAddressV2(netAddr *addrmgr.NetAddress) *wire.NetAddressV2 {
+	// The address types of both packages share the same values.
+	return wire.NewNetAddressV2(netAddr.Timestamp, netAddr.Services,
+		wire.NetAddressType(netAddr.Type), netAddr.IP, netAddr.Port)
+}
+
+// wireToAddrmgrNetAddressV2 converts a version 2 wire network address to an
+// address manager network address.  An error is returned when the encoded
+// address is not valid for its type.
+func wireToAddrmgrNetAddressV2(netAddr *wire.NetAddressV2) (*addrmgr.NetAddress, error) {
+	// The address types of both packages share the same values.
+	return addrmgr.NewNetAddressFromParams(addrmgr.NetAddressType(netAddr.Type),
+		netAddr.EncodedAddr, netAddr.Port, netAddr.Timestamp, netAddr.Services)
+}
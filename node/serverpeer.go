@@ -0,0 +1,1259 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2015-2024 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/addrmgr/v3"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/connmgr/v3"
+	"github.com/kdsmith18542/vigil/container/apbf"
+	"github.com/kdsmith18542/vigil/internal/blockchain"
+	"github.com/kdsmith18542/vigil/internal/mining"
+	"github.com/kdsmith18542/vigil/internal/netsync"
+	"github.com/kdsmith18542/vigil/mixing"
+	"github.com/kdsmith18542/vigil/mixing/mixpool"
+	"github.com/kdsmith18542/vigil/peer/v3"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// serverPeer extends the peer to maintain state shared by the server.
+type serverPeer struct {
+	*peer.Peer
+
+	// These fields are set at creation time and never modified afterwards, so
+	// they do not need to be protected for concurrent access.
+	server        *server
+	persistent    bool
+	isWhitelisted bool
+	quit          chan struct{}
+
+	// syncMgrPeer houses the network sync manager peer instance that wraps
+	// the underlying peer similar to the way this server peer itself wraps
+	// it.
+	syncMgrPeer *netsync.Peer
+
+	// All fields below this point are either not set at creation time or are
+	// otherwise modified during operation and thus need to consider whether
+	// or not they need to be protected for concurrent access.
+
+	connReq        atomic.Pointer[connmgr.ConnReq]
+	continueHash   atomic.Pointer[chainhash.Hash]
+	disableRelayTx atomic.Bool
+	knownAddresses *apbf.Filter
+	banScore       connmgr.DynamicBanScore
+
+	// feeFilter is the minimum fee rate, in atoms/kB, the remote peer has
+	// requested for transaction inventory announcements.
+	feeFilter atomic.Int64
+
+	// addrsSent, getMiningStateSent, and initStateSent track whether or not
+	// the peer has already sent the respective request.  They are used to
+	// prevent more than one response of each respective request per
+	// connection.
+	//
+	// They are only accessed directly in callbacks which all run in the same
+	// peer input handler goroutine and thus do not need to be protected for
+	// concurrent access.
+	addrsSent          bool
+	getMiningStateSent bool
+	initStateSent      bool
+
+	// The following chans are used to sync blocks, txns, and mix messages
+	// from the sync manager with the peer input handler.
+	txProcessed     chan struct{}
+	blockProcessed  chan struct{}
+	mixMsgProcessed chan error
+}
+
+// newServerPeer returns a new serverPeer instance.  The peer needs to be set by
+// the caller.
+func newServerPeer(s *server, isPersistent bool) *serverPeer {
+	return &serverPeer{
+		server:          s,
+		persistent:      isPersistent,
+		knownAddresses:  apbf.NewFilter(maxKnownAddrsPerPeer, 0.001),
+		quit:            make(chan struct{}),
+		txProcessed:     make(chan struct{}, 1),
+		blockProcessed:  make(chan struct{}, 1),
+		mixMsgProcessed: make(chan error, 1),
+	}
+}
+
+// newestBlock returns the current best block hash and height using the format
+// required by the configuration for the peer package.
+func (sp *serverPeer) newestBlock() (*chainhash.Hash, int64, error) {
+	best := sp.server.chain.BestSnapshot()
+	return &best.Hash, best.Height, nil
+}
+
+// addKnownAddress adds the given address to the set of known addresses to
+// the peer to prevent sending duplicate addresses.
+func (sp *serverPeer) addKnownAddress(na *addrmgr.NetAddress) {
+	sp.knownAddresses.Add([]byte(na.Key()))
+}
+
+// addKnownAddresses adds the given addresses to the set of known addresses to
+// the peer to prevent sending duplicate addresses.
+func (sp *serverPeer) addKnownAddresses(addresses []*addrmgr.NetAddress) {
+	for _, na := range addresses {
+		sp.addKnownAddress(na)
+	}
+}
+
+// addressKnown true if the given address is already known to the peer.
+func (sp *serverPeer) addressKnown(na *addrmgr.NetAddress) bool {
+	return sp.knownAddresses.Contains([]byte(na.Key()))
+}
+
+// remoteNetAddress returns the address manager network address of the remote
+// peer.  Peers connected via Tor v3 onion services or I2P are identified by the
+// address that was dialed since those addresses can't be represented by the
+// version 1 wire network address associated with the peer.
+func (sp *serverPeer) remoteNetAddress() *addrmgr.NetAddress {
+	na := sp.NA()
+	host, portStr, err := net.SplitHostPort(sp.Addr())
+	if err == nil {
+		addrType, addrBytes := addrmgr.EncodeHost(host)
+		if isOverlayNetAddressType(addrType) {
+			port, err := strconv.ParseUint(portStr, 10, 16)
+			if err == nil {
+				netAddr, err := addrmgr.NewNetAddressFromParams(addrType,
+					addrBytes, uint16(port), na.Timestamp, na.Services)
+				if err == nil {
+					return netAddr
+				}
+			}
+		}
+	}
+	return wireToAddrmgrNetAddress(na)
+}
+
+// pushAddrMsg sends an addr message to the connected peer using the provided
+// addresses.  Addresses that are not supported by the version 1 addr message
+// are not sent.
+func (sp *serverPeer) pushAddrMsg(addresses []*addrmgr.NetAddress) {
+	// Filter addresses already known to the peer.
+	addrs := make([]*wire.NetAddress, 0, len(addresses))
+	for _, addr := range addresses {
+		if !sp.addressKnown(addr) && isSupportedNetAddressTypeV1(addr.Type) {
+			addrs = append(addrs, addrmgrToWireNetAddress(addr))
+		}
+	}
+	known, err := sp.PushAddrMsg(addrs)
+	if err != nil {
+		peerLog.Errorf("Can't push address message to %s: %v", sp.Peer, err)
+		sp.Disconnect()
+		return
+	}
+
+	// Add addresses to known addresses for this peer.
+	for _, na := range known {
+		sp.addKnownAddress(wireToAddrmgrNetAddress(na))
+	}
+}
+
+// pushAddrV2Msg sends an addrv2 message to the connected peer using the
+// provided addresses.
+func (sp *serverPeer) pushAddrV2Msg(addresses []*addrmgr.NetAddress) {
+	// Filter addresses already known to the peer.
+	addrs := make([]*wire.NetAddressV2, 0, len(addresses))
+	for _, addr := range addresses {
+		if !sp.addressKnown(addr) && isSupportedNetAddressTypeV2(addr.Type) {
+			addrs = append(addrs, addrmgrToWireNetAddressV2(addr))
+		}
+	}
+	known, err := sp.PushAddrV2Msg(addrs)
+	if err != nil {
+		peerLog.Errorf("Can't push address message to %s: %v", sp.Peer, err)
+		sp.Disconnect()
+		return
+	}
+
+	// Add addresses to known addresses for this peer.
+	for _, na := range known {
+		netAddr, err := wireToAddrmgrNetAddressV2(na)
+		if err != nil {
+			continue
+		}
+		sp.addKnownAddress(netAddr)
+	}
+}
+
+// supportsAddrV2 returns whether or not the negotiated protocol version with
+// the peer supports the getaddrv2 and addrv2 messages.
+func (sp *serverPeer) supportsAddrV2() bool {
+	return sp.ProtocolVersion() >= wire.AddrV2Version
+}
+
+// addBanScore increases the persistent and decaying ban score fields by the
+// values passed as parameters.  If the resulting score exceeds half of the ban
+// threshold, a warning is logged including the reason provided.  Further, if
+// the score is above the ban threshold, the peer will be banned and
+// disconnected.
+//
+// It returns whether or not the peer was banned.
+func (sp *serverPeer) addBanScore(persistent, transient uint32, reason string) bool {
+	// No warning is logged and no score is calculated if banning is disabled.
+	if cfg.DisableBanning {
+		return false
+	}
+	if sp.isWhitelisted {
+		peerLog.Debugf("Misbehaving whitelisted peer %s: %s", sp, reason)
+		return false
+	}
+
+	warnThreshold := cfg.BanThreshold >> 1
+	if transient == 0 && persistent == 0 {
+		// The score is not being increased, but a warning message is still
+		// logged if the score is above the warn threshold.
+		score := sp.banScore.Int()
+		if score > warnThreshold {
+			peerLog.Warnf("Misbehaving peer %s: %s -- ban score is %d, "+
+				"it was not increased this time", sp, reason, score)
+		}
+		return false
+	}
+
+	score := sp.banScore.Increase(persistent, transient)
+	if score > warnThreshold {
+		peerLog.Warnf("Misbehaving peer %s: %s -- ban score increased to %d",
+			sp, reason, score)
+		if score > cfg.BanThreshold {
+			peerLog.Warnf("Misbehaving peer %s -- banning and disconnecting",
+				sp)
+			sp.server.BanPeer(sp)
+			return true
+		}
+	}
+	return false
+}
+
+// hasServices returns whether or not the provided advertised service flags
+// have all of the provided desired service flags set.
+func hasServices(advertised, desired wire.ServiceFlag) bool {
+	return advertised&desired == desired
+}
+
+// OnVersion is invoked when a peer receives a version wire message and is
+// used to negotiate the protocol version details as well as kick start the
+// communications.
+func (sp *serverPeer) OnVersion(_ *peer.Peer, msg *wire.MsgVersion) {
+	// Update the address manager with the advertised services for outbound
+	// connections in case they have changed.  This is not done for inbound
+	// connections to help prevent malicious behavior and is skipped when
+	// running on the simulation and regression test networks since they are
+	// only intended to connect to specified peers and actively avoid
+	// advertising and connecting to discovered peers.
+	//
+	// NOTE: This is done before rejecting peers that are too old to ensure
+	// it is updated regardless in the case a new minimum protocol version is
+	// enforced and the remote node has not upgraded yet.
+	isInbound := sp.Inbound()
+	remoteAddr := sp.remoteNetAddress()
+	addrManager := sp.server.addrManager
+	if !cfg.SimNet && !cfg.RegNet && !isInbound {
+		err := addrManager.SetServices(remoteAddr, msg.Services)
+		if err != nil {
+			srvrLog.Errorf("Setting services for address failed: %v", err)
+		}
+	}
+
+	// Reject peers that have a protocol version that is too old.
+	const reqProtocolVersion = int32(wire.RemoveRejectVersion)
+	if msg.ProtocolVersion < reqProtocolVersion {
+		srvrLog.Debugf("Rejecting peer %s with protocol version %d prior to "+
+			"the required version %d", sp.Peer, msg.ProtocolVersion,
+			reqProtocolVersion)
+		sp.Disconnect()
+		return
+	}
+
+	// Reject outbound peers that are not full nodes.
+	wantServices := defaultRequiredServices
+	if !isInbound && !hasServices(msg.Services, wantServices) {
+		missingServices := wantServices & ^msg.Services
+		srvrLog.Debugf("Rejecting peer %s with services %v due to not "+
+			"providing desired services %v", sp.Peer, msg.Services,
+			missingServices)
+		sp.Disconnect()
+		return
+	}
+
+	// Consider the address the remote peer reports seeing for the local node
+	// as a candidate external address when automatic discovery is enabled.
+	// Only outbound peers are considered since inbound peers are trivially
+	// able to lie about it.
+	if !cfg.SimNet && !cfg.RegNet && !isInbound && !cfg.NoDiscoverIP &&
+		len(cfg.ExternalIPs) == 0 {
+
+		sp.server.considerExternalAddr(&msg.AddrYou, remoteAddr)
+	}
+
+	// Choose whether or not to relay transactions.
+	sp.disableRelayTx.Store(msg.DisableRelayTx)
+
+	// Add the remote peer time as a sample for creating an offset against
+	// the local clock to keep the network time in sync.
+	sp.server.timeSource.AddTimeSample(sp.Addr(), msg.Timestamp)
+}
+
+// OnVerAck is invoked when a peer receives a verack wire message.  It is used
+// to add the peer to the server once the version negotiation has fully
+// completed.
+func (sp *serverPeer) OnVerAck(_ *peer.Peer, _ *wire.MsgVerAck) {
+	// Add valid peer to the server and notify the sync manager about it.
+	if !sp.server.handleAddPeer(sp) {
+		return
+	}
+	sp.server.syncManager.PeerConnected(sp.syncMgrPeer)
+
+	// Update the address manager and request known addresses from the
+	// remote peer for outbound connections.  This is skipped when running on
+	// the simulation and regression test networks since they are only
+	// intended to connect to specified peers and actively avoid advertising
+	// and connecting to discovered peers.
+	if cfg.SimNet || cfg.RegNet || sp.Inbound() {
+		return
+	}
+
+	// Advertise the local address when the server accepts incoming
+	// connections and it believes itself to be close to the best known
+	// tip.
+	addrManager := sp.server.addrManager
+	remoteAddr := sp.remoteNetAddress()
+	if !cfg.DisableListen && sp.server.syncManager.IsCurrent() {
+		// Get address that best matches.
+		if sp.supportsAddrV2() {
+			lna := addrManager.GetBestLocalAddress(remoteAddr,
+				isSupportedNetAddressTypeV2)
+			if lna.IsRoutable() {
+				sp.pushAddrV2Msg([]*addrmgr.NetAddress{lna})
+			}
+		} else {
+			lna := addrManager.GetBestLocalAddress(remoteAddr,
+				isSupportedNetAddressTypeV1)
+			if lna.IsRoutable() {
+				sp.pushAddrMsg([]*addrmgr.NetAddress{lna})
+			}
+		}
+	}
+
+	// Request known addresses if the server address manager needs more.
+	if addrManager.NeedMoreAddresses() {
+		if sp.supportsAddrV2() {
+			sp.QueueMessage(wire.NewMsgGetAddrV2(), nil)
+		} else {
+			sp.QueueMessage(wire.NewMsgGetAddr(), nil)
+		}
+	}
+
+	// Mark the address as a known good address.
+	err := addrManager.Good(remoteAddr)
+	if err != nil {
+		srvrLog.Errorf("Marking address as good failed: %v", err)
+	}
+}
+
+// OnMemPool is invoked when a peer receives a mempool wire message.  It creates
+// and sends an inventory message with the contents of the memory pool up to the
+// maximum inventory allowed per message.
+func (sp *serverPeer) OnMemPool(_ *peer.Peer, _ *wire.MsgMemPool) {
+	// A decaying ban score increase is applied to prevent flooding.
+	// The ban score accumulates and passes the ban threshold if a burst of
+	// mempool messages comes from a peer. The score decays each minute to
+	// half of its value.
+	if sp.addBanScore(0, 33, "mempool") {
+		return
+	}
+
+	// Generate inventory message with the available transactions in the
+	// transaction memory pool.  Limit it to the max allowed inventory
+	// per message.  The NewMsgInvSizeHint function automatically limits
+	// the passed hint to the maximum allowed, so it's safe to pass it
+	// without double checking it here.
+	txMemPool := sp.server.txMemPool
+	txDescs := txMemPool.TxDescs()
+	feeFilter := sp.feeFilter.Load()
+	invMsg := wire.NewMsgInvSizeHint(uint(len(txDescs)))
+	for _, txDesc := range txDescs {
+		// Another thread might have removed the transaction from the
+		// pool since the initial query.
+		hash := txDesc.Tx.Hash()
+		if !txMemPool.HaveTransaction(hash) {
+			continue
+		}
+
+		// Don't announce transactions that pay less than the fee rate the
+		// peer requested.
+		if feeFilter > 0 && txDesc.TxSize > 0 &&
+			txDesc.Fee*1000/txDesc.TxSize < feeFilter {
+
+			continue
+		}
+
+		iv := wire.NewInvVect(wire.InvTypeTx, hash)
+		invMsg.AddInvVect(iv)
+		if len(invMsg.InvList) >= wire.MaxInvPerMsg {
+			break
+		}
+	}
+
+	// Send the inventory message if there is anything to send.
+	if len(invMsg.InvList) > 0 {
+		sp.QueueMessage(invMsg, nil)
+	}
+}
+
+// pushMiningStateMsg pushes a mining state message to the queue for a
+// requesting peer.
+func (sp *serverPeer) pushMiningStateMsg(height uint32, blockHashes []chainhash.Hash, voteHashes []chainhash.Hash) error {
+	// Nothing to send, abort.
+	if len(blockHashes) == 0 {
+		return nil
+	}
+
+	// Construct the mining state request and queue it to be sent.
+	msg := wire.NewMsgMiningState()
+	msg.Height = height
+	for i := range blockHashes {
+		err := msg.AddBlockHash(&blockHashes[i])
+		if err != nil {
+			return err
+		}
+	}
+	for i := range voteHashes {
+		err := msg.AddVoteHash(&voteHashes[i])
+		if err != nil {
+			return err
+		}
+		if i+1 >= wire.MaxMSVotesAtHeadPerMsg {
+			break
+		}
+	}
+
+	sp.QueueMessage(msg, nil)
+
+	return nil
+}
+
+// OnGetMiningState is invoked when a peer receives a getminingstate wire
+// message.  It constructs a list of the current best blocks and votes that
+// should be mined on and pushes a miningstate wire message back to the
+// requesting peer.
+func (sp *serverPeer) OnGetMiningState(_ *peer.Peer, _ *wire.MsgGetMiningState) {
+	if sp.getMiningStateSent {
+		peerLog.Tracef("Ignoring getminingstate from %v - already sent",
+			sp.Peer)
+		return
+	}
+	sp.getMiningStateSent = true
+
+	// Send out blank mining states if we're early in the blockchain.
+	chain := sp.server.chain
+	best := chain.BestSnapshot()
+	if best.Height < sp.server.chainParams.StakeValidationHeight-1 {
+		err := sp.pushMiningStateMsg(0, nil, nil)
+		if err != nil {
+			peerLog.Errorf("unexpected error while pushing data for "+
+				"mining state request: %v", err.Error())
+		}
+		return
+	}
+
+	// Obtain the entire generation of blocks stemming from the parent of
+	// the current tip.
+	children := chain.TipGeneration()
+
+	// Get the list of blocks that are eligible to build on and limit the
+	// list to the maximum number of allowed eligible block hashes per
+	// mining state message.  There is nothing to send when there are no
+	// eligible blocks.
+	mp := sp.server.txMemPool
+	blockHashes := mining.SortParentsByVotes(mp, best.Hash, children,
+		sp.server.chainParams)
+	numBlocks := len(blockHashes)
+	if numBlocks == 0 {
+		return
+	}
+	if numBlocks > wire.MaxMSBlocksAtHeadPerMsg {
+		blockHashes = blockHashes[:wire.MaxMSBlocksAtHeadPerMsg]
+	}
+
+	// Construct the set of votes to send.
+	voteHashes := make([]chainhash.Hash, 0, wire.MaxMSVotesAtHeadPerMsg)
+	for i := range blockHashes {
+		// Fetch the vote hashes themselves and append them.
+		bh := &blockHashes[i]
+		vhsForBlock := mp.VoteHashesForBlock(bh)
+		if len(vhsForBlock) == 0 {
+			peerLog.Warnf("unexpected error while fetching vote hashes "+
+				"for block %v for a mining state request: no vote "+
+				"metadata for block", bh)
+			return
+		}
+		voteHashes = append(voteHashes, vhsForBlock...)
+	}
+
+	err := sp.pushMiningStateMsg(uint32(best.Height), blockHashes, voteHashes)
+	if err != nil {
+		peerLog.Warnf("unexpected error while pushing data for "+
+			"mining state request: %v", err.Error())
+	}
+}
+
+// OnMiningState is invoked when a peer receives a miningstate wire message.  It
+// requests the data advertised in the message from the peer.
+func (sp *serverPeer) OnMiningState(_ *peer.Peer, msg *wire.MsgMiningState) {
+	var blockHashes, voteHashes []chainhash.Hash
+	if len(msg.BlockHashes) > 0 {
+		blockHashes = make([]chainhash.Hash, 0, len(msg.BlockHashes))
+		for _, hash := range msg.BlockHashes {
+			blockHashes = append(blockHashes, *hash)
+		}
+	}
+	if len(msg.VoteHashes) > 0 {
+		voteHashes = make([]chainhash.Hash, 0, len(msg.VoteHashes))
+		for _, hash := range msg.VoteHashes {
+			voteHashes = append(voteHashes, *hash)
+		}
+	}
+
+	// Nothing to do when there is no data advertised.
+	if len(blockHashes) == 0 && len(voteHashes) == 0 {
+		return
+	}
+
+	err := sp.server.syncManager.RequestFromPeer(sp.syncMgrPeer, blockHashes,
+		voteHashes, nil, nil)
+	if err != nil {
+		peerLog.Warnf("couldn't handle mining state message: %v",
+			err.Error())
+	}
+}
+
+// OnGetInitState is invoked when a peer receives a getinitstate wire message.
+// It sends the available requested info to the remote peer.
+func (sp *serverPeer) OnGetInitState(_ *peer.Peer, msg *wire.MsgGetInitState) {
+	if sp.initStateSent {
+		peerLog.Tracef("Ignoring getinitstate from %v - already sent", sp.Peer)
+		return
+	}
+	sp.initStateSent = true
+
+	// Send out blank init state if we're early in the blockchain.
+	chain := sp.server.chain
+	best := chain.BestSnapshot()
+	if best.Height < sp.server.chainParams.StakeValidationHeight-1 {
+		sp.QueueMessage(wire.NewMsgInitState(), nil)
+		return
+	}
+
+	// Determine which types of data were requested.
+	var wantBlocks, wantVotes, wantTSpends bool
+	for _, typ := range msg.Types {
+		switch typ {
+		case wire.InitStateHeadBlocks:
+			wantBlocks = true
+		case wire.InitStateHeadBlockVotes:
+			wantVotes = true
+		case wire.InitStateTSpends:
+			wantTSpends = true
+		}
+	}
+
+	// Fetch the head blocks when either they or their votes were requested
+	// since the votes are determined from the blocks.
+	mp := sp.server.txMemPool
+	var blockHashes, voteHashes, tspendHashes []chainhash.Hash
+	if wantBlocks || wantVotes {
+		children := chain.TipGeneration()
+		blockHashes = mining.SortParentsByVotes(mp, best.Hash, children,
+			sp.server.chainParams)
+		if len(blockHashes) > wire.MaxISBlocksAtHeadPerMsg {
+			blockHashes = blockHashes[:wire.MaxISBlocksAtHeadPerMsg]
+		}
+	}
+
+	if wantVotes {
+		voteHashes = make([]chainhash.Hash, 0, wire.MaxISVotesAtHeadPerMsg)
+		for i := range blockHashes {
+			vhsForBlock := mp.VoteHashesForBlock(&blockHashes[i])
+			voteHashes = append(voteHashes, vhsForBlock...)
+		}
+		if len(voteHashes) > wire.MaxISVotesAtHeadPerMsg {
+			voteHashes = voteHashes[:wire.MaxISVotesAtHeadPerMsg]
+		}
+	}
+
+	if wantTSpends {
+		tspendHashes = mp.TSpendHashes()
+		if len(tspendHashes) > wire.MaxISTSpendsAtHeadPerMsg {
+			tspendHashes = tspendHashes[:wire.MaxISTSpendsAtHeadPerMsg]
+		}
+	}
+
+	// Don't send the head blocks unless they were explicitly requested.
+	if !wantBlocks {
+		blockHashes = nil
+	}
+
+	initMsg, err := wire.NewMsgInitStateFilled(blockHashes, voteHashes,
+		tspendHashes)
+	if err != nil {
+		peerLog.Warnf("Unexpected error while building initstate msg: %v",
+			err)
+		return
+	}
+
+	sp.QueueMessage(initMsg, nil)
+}
+
+// OnInitState is invoked when a peer receives an initstate wire message.  It
+// requests the data advertised in the message from the peer.
+func (sp *serverPeer) OnInitState(_ *peer.Peer, msg *wire.MsgInitState) {
+	// Nothing to do when there is no data advertised.
+	if len(msg.BlockHashes) == 0 && len(msg.VoteHashes) == 0 &&
+		len(msg.TSpendHashes) == 0 {
+
+		return
+	}
+
+	err := sp.server.syncManager.RequestFromPeer(sp.syncMgrPeer,
+		msg.BlockHashes, msg.VoteHashes, msg.TSpendHashes, nil)
+	if err != nil {
+		peerLog.Warnf("couldn't handle init state message: %v", err)
+	}
+}
+
+// OnTx is invoked when a peer receives a tx wire message.  It blocks until the
+// transaction has been fully processed.  Unlock the block handler this does not
+// serialize all transactions through a single thread transactions don't rely
+// on the previous one in a linear fashion like blocks.
+func (sp *serverPeer) OnTx(_ *peer.Peer, msg *wire.MsgTx) {
+	if cfg.BlocksOnly {
+		peerLog.Tracef("Ignoring tx %v from %v - blocksonly enabled",
+			msg.TxHash(), sp)
+		return
+	}
+
+	// Add the transaction to the known inventory for the peer.
+	// Convert the raw MsgTx to a VGLutil.Tx which provides some convenience
+	// methods and things such as hash caching.
+	tx := VGLutil.NewTx(msg)
+	iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
+	sp.AddKnownInventory(iv)
+
+	// Queue the transaction up to be handled by the net sync manager and
+	// intentionally block further receives until the transaction is fully
+	// processed and known good or bad.  This helps prevent a malicious peer
+	// from queuing up a bunch of bad transactions before disconnecting (or
+	// being disconnected) and wasting memory.
+	sp.server.syncManager.OnTx(tx, sp.syncMgrPeer, sp.txProcessed)
+	<-sp.txProcessed
+}
+
+// OnBlock is invoked when a peer receives a block wire message.  It blocks
+// until the network block has been fully processed.
+func (sp *serverPeer) OnBlock(_ *peer.Peer, msg *wire.MsgBlock, buf []byte) {
+	// Convert the raw MsgBlock to a VGLutil.Block which provides some
+	// convenience methods and things such as hash caching.
+	block := VGLutil.NewBlockFromBlockAndBytes(msg, buf)
+
+	// Add the block to the known inventory for the peer.
+	iv := wire.NewInvVect(wire.InvTypeBlock, block.Hash())
+	sp.AddKnownInventory(iv)
+
+	// Queue the block up to be handled by the net sync manager and
+	// intentionally block further receives until the network block is fully
+	// processed and known good or bad.  This helps prevent a malicious peer
+	// from queuing up a bunch of bad blocks before disconnecting (or being
+	// disconnected) and wasting memory.  Additionally, this behavior is
+	// depended on by at least the block acceptance test tool as the reference
+	// implementation processes blocks in the same thread and therefore blocks
+	// further messages until the network block has been fully processed.
+	sp.server.syncManager.OnBlock(block, sp.syncMgrPeer, sp.blockProcessed)
+	<-sp.blockProcessed
+}
+
+// OnCmpctBlock is invoked when a peer receives a cmpctblock wire message.  It
+// blocks until the block has either been reconstructed and fully processed or
+// the missing transactions have been requested.
+func (sp *serverPeer) OnCmpctBlock(_ *peer.Peer, msg *wire.MsgCmpctBlock) {
+	// Add the block to the known inventory for the peer.
+	blockHash := msg.Header.BlockHash()
+	iv := wire.NewInvVect(wire.InvTypeBlock, &blockHash)
+	sp.AddKnownInventory(iv)
+
+	// Queue the compact block up to be handled by the net sync manager and
+	// intentionally block further receives until it is processed for the same
+	// reasons described for full blocks.
+	sp.server.syncManager.OnCmpctBlock(msg, sp.syncMgrPeer, sp.blockProcessed)
+	<-sp.blockProcessed
+}
+
+// OnBlockTxn is invoked when a peer receives a blocktxn wire message.  It
+// blocks until the associated compact block has been reconstructed and fully
+// processed.
+func (sp *serverPeer) OnBlockTxn(_ *peer.Peer, msg *wire.MsgBlockTxn) {
+	sp.server.syncManager.OnBlockTxn(msg, sp.syncMgrPeer, sp.blockProcessed)
+	<-sp.blockProcessed
+}
+
+// OnGetBlockTxn is invoked when a peer receives a getblocktxn wire message and
+// is used to deliver the requested transactions of a recent block.  The full
+// block is sent instead when the block is too deep in the chain.
+func (sp *serverPeer) OnGetBlockTxn(_ *peer.Peer, msg *wire.MsgGetBlockTxn) {
+	chain := sp.server.chain
+	block, err := chain.BlockByHash(&msg.BlockHash)
+	if err != nil {
+		peerLog.Debugf("Unable to fetch requested block %v for getblocktxn "+
+			"from %s: %v", msg.BlockHash, sp, err)
+		sp.addBanScore(0, 10, "getblocktxn for unknown block")
+		return
+	}
+
+	best := chain.BestSnapshot()
+	if best.Height-block.Height() > maxCmpctBlockDepth {
+		sp.QueueMessage(block.MsgBlock(), nil)
+		return
+	}
+
+	// The wire package ensures the indices are ascending, so only the final
+	// index of each tree needs to be checked against the block.
+	msgBlock := block.MsgBlock()
+	numIndexes, numStakeIndexes := len(msg.Indexes), len(msg.StakeIndexes)
+	if (numIndexes > 0 && int(msg.Indexes[numIndexes-1]) >=
+		len(msgBlock.Transactions)) || (numStakeIndexes > 0 &&
+		int(msg.StakeIndexes[numStakeIndexes-1]) >=
+			len(msgBlock.STransactions)) {
+
+		sp.addBanScore(100, 0, "getblocktxn with out of range indexes")
+		return
+	}
+
+	blockTxn := wire.NewMsgBlockTxn(&msg.BlockHash)
+	if numIndexes > 0 {
+		blockTxn.Transactions = make([]*wire.MsgTx, 0, numIndexes)
+	}
+	for _, index := range msg.Indexes {
+		blockTxn.Transactions = append(blockTxn.Transactions,
+			msgBlock.Transactions[index])
+	}
+	if numStakeIndexes > 0 {
+		blockTxn.STransactions = make([]*wire.MsgTx, 0, numStakeIndexes)
+	}
+	for _, index := range msg.StakeIndexes {
+		blockTxn.STransactions = append(blockTxn.STransactions,
+			msgBlock.STransactions[index])
+	}
+	sp.QueueMessage(blockTxn, nil)
+}
+
+// OnInv is invoked when a peer receives an inv wire message and is used to
+// examine the inventory being advertised by the remote peer and react
+// accordingly.  We pass the message down to the net sync manager which will
+// call QueueMessage with any appropriate responses.
+func (sp *serverPeer) OnInv(_ *peer.Peer, msg *wire.MsgInv) {
+	// Ban peers sending empty inventory announcements.
+	if len(msg.InvList) == 0 {
+		sp.addBanScore(0, 20, "empty inv")
+		return
+	}
+
+	if !cfg.BlocksOnly {
+		sp.server.syncManager.OnInv(msg, sp.syncMgrPeer)
+		return
+	}
+
+	newInv := wire.NewMsgInvSizeHint(uint(len(msg.InvList)))
+	for _, invVect := range msg.InvList {
+		if invVect.Type == wire.InvTypeTx {
+			peerLog.Infof("Peer %v is announcing transactions -- "+
+				"disconnecting", sp)
+			sp.Disconnect()
+			return
+		}
+		err := newInv.AddInvVect(invVect)
+		if err != nil {
+			peerLog.Errorf("Failed to add inventory vector: %v", err)
+			break
+		}
+	}
+
+	if len(newInv.InvList) > 0 {
+		sp.server.syncManager.OnInv(newInv, sp.syncMgrPeer)
+	}
+}
+
+// OnHeaders is invoked when a peer receives a headers wire message.  The
+// message is passed down to the net sync manager.
+func (sp *serverPeer) OnHeaders(_ *peer.Peer, msg *wire.MsgHeaders) {
+	sp.server.syncManager.OnHeaders(msg, sp.syncMgrPeer)
+}
+
+// OnNotFound is invoked when a peer receives a notfound wire message.  The
+// message is passed down to the net sync manager.
+func (sp *serverPeer) OnNotFound(_ *peer.Peer, msg *wire.MsgNotFound) {
+	sp.server.syncManager.OnNotFound(msg, sp.syncMgrPeer)
+}
+
+// OnGetData is invoked when a peer receives a getdata wire message and is used
+// to deliver block and transaction information.
+func (sp *serverPeer) OnGetData(_ *peer.Peer, msg *wire.MsgGetData) {
+	// Ban peers sending empty getdata requests.
+	length := len(msg.InvList)
+	if length == 0 {
+		sp.addBanScore(0, 20, "empty getdata")
+		return
+	}
+
+	// A decaying ban score increase is applied to prevent exhausting
+	// resources with unusually large inventory queries.
+	//
+	// Requesting more than the maximum inventory vector length within a short
+	// period of time yields a score above the default ban threshold.
+	// Sustained bursts of small requests are not penalized as that would
+	// potentially ban peers performing IBD.
+	//
+	// This incremental score decays each minute to half of its value.
+	if sp.addBanScore(0, uint32(length)*99/wire.MaxInvPerMsg, "getdata") {
+		return
+	}
+
+	// We wait on this wait channel periodically to prevent queuing far more
+	// data than we can send in a reasonable time, wasting memory.  The
+	// waiting occurs after the database fetch for the next one to provide a
+	// little pipelining.
+	var waitChan chan struct{}
+	doneChan := make(chan struct{}, 1)
+	notFound := wire.NewMsgNotFound()
+	for i, iv := range msg.InvList {
+		var c chan struct{}
+		// If this will be the last message we send.
+		if i == length-1 && len(notFound.InvList) == 0 {
+			c = doneChan
+		} else if (i+1)%3 == 0 {
+			// Buffered so as to not make the send goroutine block.
+			c = make(chan struct{}, 1)
+		}
+
+		var err error
+		switch iv.Type {
+		case wire.InvTypeTx:
+			err = sp.server.pushTxMsg(sp, &iv.Hash, c, waitChan)
+		case wire.InvTypeBlock:
+			err = sp.server.pushBlockMsg(sp, &iv.Hash, c, waitChan)
+		case wire.InvTypeCompactBlock:
+			err = sp.server.pushCmpctBlockMsg(sp, &iv.Hash, c, waitChan)
+		case wire.InvTypeMix:
+			err = sp.server.pushMixMsg(sp, &iv.Hash, c, waitChan)
+		default:
+			peerLog.Warnf("Unknown type '%d' in inventory request from %s",
+				iv.Type, sp)
+			err = fmt.Errorf("unknown inventory type %d", iv.Type)
+			if c != nil {
+				c <- struct{}{}
+			}
+		}
+		if err != nil {
+			notFound.AddInvVect(iv)
+
+			// When there is a failure fetching the final entry and the done
+			// channel was sent in due to there being no outstanding not
+			// found inventory, consume it here because there is now not
+			// found inventory that will use the channel momentarily.
+			if i == length-1 && c != nil {
+				<-c
+			}
+		}
+		waitChan = c
+	}
+	if len(notFound.InvList) != 0 {
+		sp.QueueMessage(notFound, doneChan)
+	}
+
+	// Wait for messages to be sent.  We can send quite a lot of data at this
+	// point and this will keep the peer busy for a decent amount of time.
+	// We don't process anything else by them in this time so that we have an
+	// idea of when we should hear back from them - else the idle timeout
+	// could fire when we were only half done sending the blocks.
+	select {
+	case <-doneChan:
+	case <-sp.quit:
+	}
+}
+
+// OnGetBlocks is invoked when a peer receives a getblocks wire message.
+func (sp *serverPeer) OnGetBlocks(_ *peer.Peer, msg *wire.MsgGetBlocks) {
+	// Find the most recent known block in the best chain based on the block
+	// locator and fetch all of the block hashes after it until either
+	// wire.MaxBlocksPerMsg have been fetched or the provided stop hash is
+	// encountered.
+	//
+	// Use the block after the genesis block if no other blocks in the
+	// provided locator are known.  This does mean the client will start
+	// over with the genesis block if unknown block locators are provided.
+	chain := sp.server.chain
+	locator := blockchain.BlockLocator(msg.BlockLocatorHashes)
+	hashList := chain.LocateBlocks(locator, &msg.HashStop,
+		wire.MaxBlocksPerMsg)
+
+	// Generate inventory message.
+	invMsg := wire.NewMsgInvSizeHint(uint(len(hashList)))
+	for i := range hashList {
+		iv := wire.NewInvVect(wire.InvTypeBlock, &hashList[i])
+		invMsg.AddInvVect(iv)
+	}
+
+	// Send the inventory message if there is anything to send.
+	if len(invMsg.InvList) > 0 {
+		invListLen := len(invMsg.InvList)
+		if invListLen == wire.MaxBlocksPerMsg {
+			// Intentionally use a copy of the final hash so there is not a
+			// reference into the inventory slice which would prevent the
+			// entire slice from being eligible for GC as soon as it's sent.
+			continueHash := invMsg.InvList[invListLen-1].Hash
+			sp.continueHash.Store(&continueHash)
+		}
+		sp.QueueMessage(invMsg, nil)
+	}
+}
+
+// OnGetHeaders is invoked when a peer receives a getheaders wire message.
+func (sp *serverPeer) OnGetHeaders(_ *peer.Peer, msg *wire.MsgGetHeaders) {
+	// Ignore getheaders requests if not in sync.
+	if !sp.server.syncManager.IsCurrent() {
+		return
+	}
+
+	// Find the most recent known block in the best chain based on the block
+	// locator and fetch all of the headers after it until either
+	// wire.MaxBlockHeadersPerMsg have been fetched or the provided stop hash
+	// is encountered.
+	//
+	// Use the block after the genesis block if no other blocks in the
+	// provided locator are known.  This does mean the client will start
+	// over with the genesis block if unknown block locators are provided.
+	chain := sp.server.chain
+	locator := blockchain.BlockLocator(msg.BlockLocatorHashes)
+	headers := chain.LocateHeaders(locator, &msg.HashStop)
+
+	// Send found headers to the requesting peer.
+	blockHeaders := make([]*wire.BlockHeader, len(headers))
+	for i := range headers {
+		blockHeaders[i] = &headers[i]
+	}
+	sp.QueueMessage(&wire.MsgHeaders{Headers: blockHeaders}, nil)
+}
+
+// OnGetCFilterV2 is invoked when a peer receives a getcfilterv2 wire message.
+func (sp *serverPeer) OnGetCFilterV2(_ *peer.Peer, msg *wire.MsgGetCFilterV2) {
+	// Attempt to obtain the requested filter.
+	//
+	// Ignore request for unknown block or otherwise missing filters.
+	chain := sp.server.chain
+	filter, proof, err := chain.FilterByBlockHash(&msg.BlockHash)
+	if err != nil {
+		if !errors.Is(err, blockchain.ErrNoFilter) &&
+			!errors.Is(err, blockchain.ErrUnknownBlock) {
+
+			peerLog.Errorf("Unable to obtain version 2 filter for block %v "+
+				"requested by %s: %v", msg.BlockHash, sp, err)
+		}
+		return
+	}
+
+	filterMsg := wire.NewMsgCFilterV2(&msg.BlockHash, filter.Bytes(),
+		proof.ProofIndex, proof.ProofHashes)
+	sp.QueueMessage(filterMsg, nil)
+}
+
+// OnGetCFiltersV2 is invoked when a peer receives a getcfsv2 wire message.
+func (sp *serverPeer) OnGetCFiltersV2(_ *peer.Peer, msg *wire.MsgGetCFsV2) {
+	// Ignore request for unknown blocks or otherwise invalid ranges.
+	chain := sp.server.chain
+	filtersMsg, err := chain.LocateCFiltersV2(&msg.StartHash, &msg.EndHash)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrRequestTooLarge) {
+			sp.addBanScore(0, 50, "getcfsv2 request too large")
+			return
+		}
+		peerLog.Debugf("Unable to obtain batched version 2 filters from %v "+
+			"to %v requested by %s: %v", msg.StartHash, msg.EndHash, sp, err)
+		return
+	}
+
+	sp.QueueMessage(filtersMsg, nil)
+}
+
+// OnFeeFilter is invoked when a peer receives a feefilter wire message and is
+// used by remote peers to request that no transactions which have a fee rate
+// lower than provided value are inventoried to them.  The peer will be
+// disconnected if an invalid fee filter value is provided.
+func (sp *serverPeer) OnFeeFilter(_ *peer.Peer, msg *wire.MsgFeeFilter) {
+	// Check that the passed minimum fee is a valid amount.
+	if msg.MinFee < 0 || msg.MinFee > VGLutil.MaxAmount {
+		peerLog.Debugf("Peer %v sent an invalid feefilter '%v' -- "+
+			"disconnecting", sp, VGLutil.Amount(msg.MinFee))
+		sp.Disconnect()
+		return
+	}
+
+	sp.feeFilter.Store(msg.MinFee)
+}
+
+// shouldRespondToGetAddr returns whether or not the server should respond to a
+// request for known addresses with the provided command from the peer.  It
+// records that addresses were sent when it returns true.
+func (sp *serverPeer) shouldRespondToGetAddr(cmd string) bool {
+	// Don't return any addresses when running on the simulation and
+	// regression test networks.  This helps prevent the networks from
+	// accidentally trying to connect to discovered peers.
+	if cfg.SimNet || cfg.RegNet {
+		return false
+	}
+
+	// Do not accept getaddr requests from outbound peers.  This reduces
+	// fingerprinting attacks.
+	if !sp.Inbound() {
+		return false
+	}
+
+	// Only respond with addresses once per connection regardless of the
+	// version of the request.  This helps reduce traffic and further reduces
+	// fingerprinting attacks.
+	if sp.addrsSent {
+		peerLog.Tracef("Ignoring %s from %v - already sent", cmd, sp.Peer)
+		return false
+	}
+	sp.addrsSent = true
+	return true
+}
+
+// OnGetAddr is invoked when a peer receives a getaddr wire message and is used
+// to provide the peer with known addresses from the address manager.
+func (sp *serverPeer) OnGetAddr(_ *peer.Peer, msg *wire.MsgGetAddr) {
+	if !sp.shouldRespondToGetAddr(msg.Command()) {
+		return
+	}
+
+	// Get the current known addresses from the address manager.
+	addrCache := sp.server.addrManager.AddressCache(isSupportedNetAddressTypeV1)
+
+	// Push the addresses.
+	sp.pushAddrMsg(addrCache)
+}
+
+// OnGetAddrV2 is invoked when a peer receives a getaddrv2 wire message and is
+// used to provide the peer with known addresses, including those on overlay
+// networks such as Tor, from the address manager.
+func (sp *serverPeer) OnGetAddrV2(_ *peer.Peer, msg *wire.MsgGetAddrV2) {
+	if !sp.shouldRespondToGetAddr(msg.Command()) {
+		return
+	}
+
+	// Get the current known addresses from the address manager.
+	addrCache := sp.server.addrManager.AddressCache(isSupportedNetAddressTypeV2)
+
+	// Push the addresses.
+	sp.pushAddrV2Msg(addrCache)
+}
+
+// OnAddr is invoked when a peer receives an addr wire message and is used to
+// notify the server about advertised addresses.
+func (sp *serverPeer) OnAddr(_ *peer.Peer, msg *wire.MsgAddr) {
+	// Ignore addresses when running on the simulation and regression test
+	// networks.  This helps prevent the networks from accidentally trying
+	// to connect to discovered peers.
+	if cfg.SimNet || cfg.RegNet {
+		return
+	}
+
+	// A message that has no addresses is invalid.
+	if len(msg.AddrList) == 0 {
+		peerLog.Errorf("Command [%s] from %s does not contain any "+
+			"addresses", msg.Command(), sp.Peer)
+		sp.Disconnect()
+		return
+	}
+
+	now := time.Now()
+	addrList := wireToAddrmgrNetAddresses(msg.AddrList)
+	for _, na := range addrList {
+		// Don't add more address if we're disconnecting.
+		if !sp.Connected() {
+			return
+		}
+
+		// Set the timestamp to 5 days ago if it's more than 10 minutes in
+		// the future so this address is one of the first to be removed
+		// when space is needed.
+		if na.Timestamp.After(now.Add(time.Minute * 10)) {
+			na.Timestamp = now.Add(-1 * time.Hour * 24 * 5)
+		}
+	}
+
+	// Add addresses to known addresses for this peer.
+	sp.addKnownAddresses(addrList)
+
+	// Add addresses to server address manager.  The address manager handles
+	// the details of things such as preventing duplicate addresses, max
+	// addresses, and last seen updates.
+	remoteAddr := sp.remoteNetAddress()
+	sp.server.addrManager.AddAddresses(addrList, remoteAddr)
+}
+
+// OnAddrV2 is invoked when a peer receives an addrv2 wire message and is used
+// to notify the server about advertised addresses.
+func (sp *serverPeer) OnAddrV2(_ *peer.Peer, msg *wire.MsgAddrV2) {
+	// Ignore addresses when running on the simulation and regression test
+	// networks.  This helps prevent the networks from accidentally trying
+	// to connect to discovered peers.
+	if cfg.SimNet || cfg.RegNet {
+		return
+	}
+
+	// A message that has no addresses is invalid.
+	if len(msg.AddrList) == 0 {
+		peerLog.Errorf("Command [%s] from %s does not contain any "+
+			"addresses", msg.Command(), sp.Peer)
+		sp.Disconnect()
+		return
+	}
+
+	now := time.Now()
+	addrList := make([]*addrmgr.NetAddress, 0, len(msg.AddrList))
+	for _, wireAddr := range msg.AddrList {
+		// Don't add more address if we're disconnecting.
+		if !sp.Connected() {
+			return
+		}
+
+		// Skip addresses that are not valid for their claimed type.
+		na, err := wireToAddrmgrNetAddressV2(wireAddr)
+		if err != nil {
+			peerLog.Debugf("Ignoring address from %s: %v", sp.Peer, err)
+			continue
+		}
+
+		// Set the timestamp to 5 days ago if it's more than 10 minutes in
+		// the future so this address is one of the first to be removed
+		// when space is needed.
+		if na.Timestamp.After(now.Add(time.Minute * 10)) {
+			na.Timestamp = now.Add(-1 * time.Hour * 24 * 5)
+		}
+		addrList = append(addrList, na)
+	}
+
+	// Add addresses to known addresses for this peer.
+	sp.addKnownAddresses(addrList)
+
+	// Add addresses to server address manager.  The address manager handles
+	// the details of things such as preventing duplicate addresses, max
+	// addresses, and last seen updates.
+	remoteAddr := sp.remoteNetAddress()
+	sp.server.addrManager.AddAddresses(addrList, remoteAddr)
+}
+
+// onMixMessage is the generic handler for all mix messages handler callbacks.
+func (sp *serverPeer) onMixMessage(msg mixing.Message) {
+	if cfg.BlocksOnly {
+		peerLog.Tracef("Ignoring mix message %v from %v - blocksonly "+
+			"enabled", msg.Hash(), sp)
+		return
+	}
+
+	// Add the message to the known inventory for the peer.
+	hash := msg.Hash()
+	iv := wire.NewInvVect(wire.InvTypeMix, &hash)
+	sp.AddKnownInventory(iv)
+
+	// Queue the message to be handled by the net sync manager.
+	//
+	// Note that the sync manager does not respond on the reply channel when
+	// it is shutting down, so the peer quit channel must be selected as well.
+	sp.server.syncManager.OnMixMsg(msg, sp.syncMgrPeer, sp.mixMsgProcessed)
+	var err error
+	select {
+	case err = <-sp.mixMsgProcessed:
+	case <-sp.quit:
+		return
+	}
+
+	// Increase the ban score of peers sending mix messages that are
+	// rejected by the mixing pool rules.
+	var missingPRErr *mixpool.MissingOwnPRError
+	var rErr *mixpool.RuleError
+	if err != nil && !errors.As(err, &missingPRErr) && errors.As(err, &rErr) {
+		sp.addBanScore(0, 10, fmt.Sprintf("rejected mix message: %v", err))
+	}
+}
+
+// OnMixPairReq submits a received mixing pair request message to the mixpool.
+func (sp *serverPeer) OnMixPairReq(_ *peer.Peer, msg *wire.MsgMixPairReq) {
+	sp.onMixMessage(msg)
+}
+
+// OnMixKeyExchange submits a received mixing key exchange message to the
+// mixpool.
+func (sp *serverPeer) OnMixKeyExchange(_ *peer.Peer, msg *wire.MsgMixKeyExchange) {
+	sp.onMixMessage(msg)
+}
+
+// OnMixCiphertexts submits a received mixing ciphertext exchange message to the
+// mixpool.
+func (sp *serverPeer) OnMixCiphertexts(_ *peer.Peer, msg *wire.MsgMixCiphertexts) {
+	sp.onMixMessage(msg)
+}
+
+// OnMixSlotReserve submits a received mixing slot reservation message to the
+// mixpool.
+func (sp *serverPeer) OnMixSlotReserve(_ *peer.Peer, msg *wire.MsgMixSlotReserve) {
+	sp.onMixMessage(msg)
+}
+
+// OnMixFactoredPoly submits a received mixing factored polynomial message to
+// the mixpool.
+func (sp *serverPeer) OnMixFactoredPoly(_ *peer.Peer, msg *wire.MsgMixFactoredPoly) {
+	sp.onMixMessage(msg)
+}
+
+// OnMixDCNet submits a received mixing XOR DC-net message to the mixpool.
+func (sp *serverPeer) OnMixDCNet(_ *peer.Peer, msg *wire.MsgMixDCNet) {
+	sp.onMixMessage(msg)
+}
+
+// OnMixConfirm submits a received mixing confirmation message to the mixpool.
+func (sp *serverPeer) OnMixConfirm(_ *peer.Peer, msg *wire.MsgMixConfirm) {
+	sp.onMixMessage(msg)
+}
+
+// OnMixSecrets submits a received mixing reveal secrets message to the mixpool.
+func (sp *serverPeer) OnMixSecrets(_ *peer.Peer, msg *wire.MsgMixSecrets) {
+	sp.onMixMessage(msg)
+}
+
+// OnRead is invoked when a peer receives a message and it is used to update
+// the bytes received by the server.
+func (sp *serverPeer) OnRead(_ *peer.Peer, bytesRead int, _ wire.Message, _ error) {
+	sp.server.bytesReceived.Add(uint64(bytesRead))
+	bytesReceivedCounter.Add(float64(bytesRead))
+}
+
+// OnWrite is invoked when a peer sends a message and it is used to update
+// the bytes sent by the server.
+func (sp *serverPeer) OnWrite(_ *peer.Peer, bytesWritten int, _ wire.Message, _ error) {
+	sp.server.bytesSent.Add(uint64(bytesWritten))
+	bytesSentCounter.Add(float64(bytesWritten))
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/peer/v3"
+)
+
+// newTestServer returns a server with the state needed to manage peers and
+// a listener on the loopback address that test peers connect through.
+func newTestServer(t *testing.T) (*server, net.Listener) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to create listener: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	s := &server{
+		chainParams: chaincfg.RegNetParams(),
+		services:    defaultServices,
+		peerState:   makePeerState(),
+		quit:        make(chan struct{}),
+	}
+	return s, listener
+}
+
+// newTestInboundPeer returns a server peer for the provided server that is
+// associated with a new inbound connection through the provided listener.  The
+// remote end of the connection is left open until the test finishes so the
+// peer remains connected while it waits for the version negotiation.
+func newTestInboundPeer(t *testing.T, s *server, listener net.Listener) *serverPeer {
+	t.Helper()
+
+	remote, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to connect to listener: %v", err)
+	}
+	conn, err := listener.Accept()
+	if err != nil {
+		remote.Close()
+		t.Fatalf("unable to accept connection: %v", err)
+	}
+
+	sp := newServerPeer(s, false)
+	sp.Peer = peer.NewInboundPeer(&peer.Config{
+		Net:      s.chainParams.Net,
+		Services: s.services,
+	})
+	sp.AssociateConnection(conn)
+	t.Cleanup(func() {
+		sp.Disconnect()
+		remote.Close()
+		sp.WaitForDisconnect()
+	})
+	return sp
+}
+
+// TestHandleAddPeer ensures new peers are added to the peer state and that
+// peers are rejected when the server is shutting down, the maximum number of
+// peers is reached, or they are banned.  It also ensures peers are removed
+// from the peer state once they are done.
+func TestHandleAddPeer(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+	cfg = &config{
+		MaxPeers:     2,
+		MaxSameIP:    1,
+		BanDuration:  time.Hour,
+		BanThreshold: 100,
+	}
+
+	s, listener := newTestServer(t)
+
+	// Ensure multiple inbound peers are added from the loopback address even
+	// though the maximum connections per IP is exceeded.
+	sp1 := newTestInboundPeer(t, s, listener)
+	sp2 := newTestInboundPeer(t, s, listener)
+	for _, sp := range []*serverPeer{sp1, sp2} {
+		if !s.handleAddPeer(sp) {
+			t.Fatalf("peer %s was not added", sp)
+		}
+	}
+	s.peerState.Lock()
+	count := s.peerState.count()
+	_, ok := s.peerState.inboundPeers[sp1.ID()]
+	s.peerState.Unlock()
+	if count != 2 || !ok {
+		t.Fatalf("unexpected peer state -- got %d peers (peer 1 known %v), "+
+			"want 2 peers", count, ok)
+	}
+
+	// Ensure peers are rejected and disconnected once the maximum number of
+	// peers is reached.
+	sp3 := newTestInboundPeer(t, s, listener)
+	if s.handleAddPeer(sp3) {
+		t.Fatal("peer was added beyond the maximum number of peers")
+	}
+	sp3.WaitForDisconnect()
+
+	// Ensure done peers are removed from the peer state.
+	s.handleDonePeer(sp2)
+	s.peerState.Lock()
+	count = s.peerState.count()
+	s.peerState.Unlock()
+	if count != 1 {
+		t.Fatalf("unexpected number of peers -- got %d, want 1", count)
+	}
+
+	// Ensure a banned peer is disconnected and peers from the banned address
+	// are rejected.
+	s.BanPeer(sp1)
+	sp1.WaitForDisconnect()
+	s.peerState.Lock()
+	banEnd, ok := s.peerState.banned["127.0.0.1"]
+	s.peerState.Unlock()
+	if !ok || !banEnd.After(time.Now()) {
+		t.Fatalf("peer was not banned -- got (%v, %v)", banEnd, ok)
+	}
+	sp4 := newTestInboundPeer(t, s, listener)
+	if s.handleAddPeer(sp4) {
+		t.Fatal("peer from banned address was added")
+	}
+
+	// Ensure peers from an address with an expired ban are added and the ban
+	// is removed.
+	s.peerState.Lock()
+	s.peerState.banned["127.0.0.1"] = time.Now().Add(-time.Second)
+	s.peerState.Unlock()
+	sp5 := newTestInboundPeer(t, s, listener)
+	if !s.handleAddPeer(sp5) {
+		t.Fatal("peer from address with expired ban was not added")
+	}
+	s.peerState.Lock()
+	_, ok = s.peerState.banned["127.0.0.1"]
+	s.peerState.Unlock()
+	if ok {
+		t.Fatal("expired ban was not removed")
+	}
+
+	// Ensure new peers are rejected once the server is shutting down.
+	s.shutdown.Store(true)
+	sp6 := newTestInboundPeer(t, s, listener)
+	if s.handleAddPeer(sp6) {
+		t.Fatal("peer was added while the server is shutting down")
+	}
+}
+
+// TestAddBanScore ensures misbehaving peers are banned once their ban score
+// exceeds the ban threshold unless banning is disabled or they are
+// whitelisted.
+func TestAddBanScore(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+	cfg = &config{
+		MaxPeers:     10,
+		BanDuration:  time.Hour,
+		BanThreshold: 100,
+	}
+
+	s, listener := newTestServer(t)
+	sp := newTestInboundPeer(t, s, listener)
+
+	// Ensure whitelisted peers and peers when banning is disabled are never
+	// banned.
+	sp.isWhitelisted = true
+	if sp.addBanScore(cfg.BanThreshold+1, 0, "test") {
+		t.Fatal("whitelisted peer was banned")
+	}
+	sp.isWhitelisted = false
+	cfg.DisableBanning = true
+	if sp.addBanScore(cfg.BanThreshold+1, 0, "test") {
+		t.Fatal("peer was banned with banning disabled")
+	}
+	cfg.DisableBanning = false
+	if score := sp.banScore.Int(); score != 0 {
+		t.Fatalf("unexpected ban score -- got %d, want 0", score)
+	}
+
+	// Ensure the peer is not banned while the score is at or below the
+	// threshold and is banned and disconnected once it exceeds it.
+	if sp.addBanScore(cfg.BanThreshold, 0, "test") {
+		t.Fatal("peer was banned at the ban threshold")
+	}
+	if sp.addBanScore(0, 0, "test") {
+		t.Fatal("peer was banned without increasing the ban score")
+	}
+	if !sp.addBanScore(1, 0, "test") {
+		t.Fatal("peer was not banned above the ban threshold")
+	}
+	sp.WaitForDisconnect()
+	s.peerState.Lock()
+	_, ok := s.peerState.banned["127.0.0.1"]
+	s.peerState.Unlock()
+	if !ok {
+		t.Fatal("banned peer address was not recorded")
+	}
+}
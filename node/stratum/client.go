@@ -0,0 +1,487 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stratum
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/kdsmith18542/vigil/kawpow"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+const (
+	// extraNonceSize is the number of bytes of the nonce that are assigned to
+	// a client by the server.  The extra nonce forms the most significant
+	// bytes of the 64-bit nonce so that clients never search overlapping
+	// nonces.
+	extraNonceSize = 2
+
+	// maxMessageSize is the maximum size of a single message from a client.
+	maxMessageSize = 16384
+
+	// sendQueueSize is the maximum number of messages that may be queued for
+	// a client before it is disconnected for being too slow.
+	sendQueueSize = 32
+
+	// idleTimeout is the amount of time a client may go without sending any
+	// messages before it is disconnected.
+	idleTimeout = time.Minute * 10
+
+	// writeTimeout is the amount of time allowed to write a message to a
+	// client.
+	writeTimeout = time.Second * 10
+
+	// maxInvalidShares is the maximum number of consecutive invalid shares a
+	// client may submit before it is disconnected.  Verifying a share is
+	// comparatively expensive, so clients that repeatedly submit shares that
+	// fail verification are not allowed to continue doing so.
+	maxInvalidShares = 20
+)
+
+// client houses the state of a single connected stratum client.
+type client struct {
+	server     *Server
+	conn       net.Conn
+	extraNonce uint16
+	sendQueue  chan interface{}
+	quit       chan struct{}
+	quitOnce   sync.Once
+
+	// The following fields are protected by the mutex.
+	mtx        sync.Mutex
+	subscribed bool
+	worker     string
+	account    string
+	vardiff    *vardiff
+	target     *big.Int
+	prevTarget *big.Int
+	prevDiff   float64
+
+	// invalidShares is the number of consecutive invalid shares submitted by
+	// the client.
+	invalidShares int
+}
+
+// newClient returns a new client for the provided connection that is assigned
+// the provided extra nonce.
+func newClient(s *Server, conn net.Conn, extraNonce uint16) *client {
+	v := newVardiff(&s.cfg, time.Now())
+	target := difficultyToTarget(s.cfg.DiffOne, v.difficulty)
+	return &client{
+		server:     s,
+		conn:       conn,
+		extraNonce: extraNonce,
+		sendQueue:  make(chan interface{}, sendQueueSize),
+		quit:       make(chan struct{}),
+		vardiff:    v,
+		target:     target,
+		prevTarget: target,
+		prevDiff:   v.difficulty,
+	}
+}
+
+// String returns the remote address of the client along with the worker name
+// once it is authorized.
+func (c *client) String() string {
+	c.mtx.Lock()
+	worker := c.worker
+	c.mtx.Unlock()
+	if worker == "" {
+		return c.conn.RemoteAddr().String()
+	}
+	return fmt.Sprintf("%s (%s)", worker, c.conn.RemoteAddr())
+}
+
+// disconnect closes the connection to the client.  It is safe to call multiple
+// times.
+func (c *client) disconnect() {
+	c.quitOnce.Do(func() {
+		close(c.quit)
+		c.conn.Close()
+	})
+}
+
+// send queues the provided message to be written to the client.  The client
+// is disconnected when its send queue is full.
+func (c *client) send(msg interface{}) {
+	select {
+	case c.sendQueue <- msg:
+	case <-c.quit:
+	default:
+		log.Warnf("Disconnecting stratum client %s: send queue full", c)
+		c.disconnect()
+	}
+}
+
+// writeHandler writes queued messages to the client and periodically adjusts
+// its share difficulty.  It must be run as a goroutine.
+func (c *client) writeHandler(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	retargetTicker := time.NewTicker(c.server.cfg.RetargetInterval)
+	defer retargetTicker.Stop()
+	for {
+		select {
+		case msg := <-c.sendQueue:
+			b, err := json.Marshal(msg)
+			if err != nil {
+				log.Errorf("Unable to marshal stratum message: %v", err)
+				continue
+			}
+			b = append(b, '\n')
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if _, err := c.conn.Write(b); err != nil {
+				log.Debugf("Unable to write to stratum client %s: %v", c, err)
+				c.disconnect()
+				return
+			}
+
+		case now := <-retargetTicker.C:
+			c.handleRetarget(now)
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// run reads and handles requests from the client until it disconnects or the
+// provided context is cancelled.
+func (c *client) run(ctx context.Context) {
+	log.Debugf("New stratum client %s", c)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go c.writeHandler(&wg)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.disconnect()
+		case <-c.quit:
+		}
+	}()
+
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 0, 1024), maxMessageSize)
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				log.Debugf("Unable to read from stratum client %s: %v", c,
+					err)
+			}
+			break
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			log.Debugf("Disconnecting stratum client %s: malformed "+
+				"request: %v", c, err)
+			break
+		}
+		result, err := c.handleRequest(&req)
+		resp := &response{ID: req.ID, Result: result}
+		if err != nil {
+			var sErr *stratumError
+			if !errors.As(err, &sErr) {
+				sErr = newError(errCodeOther, "%v", err)
+			}
+			resp.Result = nil
+			resp.Error = sErr
+		}
+		c.send(resp)
+
+		// Send newly authorized clients their share target and the current
+		// job after the response so they know they are authorized before
+		// receiving work.
+		if req.Method == methodAuthorize && resp.Error == nil {
+			c.sendTarget()
+			if j := c.server.currentJob(); j != nil {
+				c.notifyJob(j, true)
+			}
+		}
+	}
+
+	c.disconnect()
+	wg.Wait()
+	log.Debugf("Stratum client %s disconnected", c)
+}
+
+// handleRequest dispatches the provided request to the handler for its method.
+func (c *client) handleRequest(req *request) (interface{}, error) {
+	switch req.Method {
+	case methodSubscribe:
+		return c.handleSubscribe()
+	case methodAuthorize:
+		return c.handleAuthorize(req.Params)
+	case methodSubmit:
+		return c.handleSubmit(req.Params)
+	case methodExtraNonceSubscribe:
+		// The extra nonce of a client never changes, so there is nothing
+		// to do beyond acknowledging the request.
+		return true, nil
+	}
+	return nil, newError(errCodeOther, "unsupported method %q", req.Method)
+}
+
+// handleSubscribe handles the mining.subscribe method.  The result consists
+// of an unused session id and the hex-encoded extra nonce of the client.
+func (c *client) handleSubscribe() (interface{}, error) {
+	c.mtx.Lock()
+	c.subscribed = true
+	c.mtx.Unlock()
+	return []interface{}{nil, fmt.Sprintf("%0*x", extraNonceSize*2,
+		c.extraNonce)}, nil
+}
+
+// handleAuthorize handles the mining.authorize method.  The worker name
+// determines the account shares are credited to.  Any worker is authorized
+// with its name as the account when the server is not configured with an
+// authorization function.
+func (c *client) handleAuthorize(params []json.RawMessage) (interface{}, error) {
+	worker, err := stringParam(params, 0, "worker")
+	if err != nil {
+		return nil, err
+	}
+
+	c.mtx.Lock()
+	subscribed := c.subscribed
+	c.mtx.Unlock()
+	if !subscribed {
+		return nil, newError(errCodeNotSubscribed, "not subscribed")
+	}
+	account := worker
+	if c.server.cfg.Authorize != nil {
+		account, err = c.server.cfg.Authorize(worker)
+		if err != nil {
+			return nil, newError(errCodeUnauthorized, "unauthorized "+
+				"worker: %v", err)
+		}
+	}
+
+	c.mtx.Lock()
+	c.worker = worker
+	c.account = account
+	c.mtx.Unlock()
+	log.Infof("Authorized stratum worker %s", c)
+	return true, nil
+}
+
+// authorized returns whether or not the client has been authorized.
+func (c *client) authorized() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.worker != ""
+}
+
+// sendTarget sends the current share target to the client.
+func (c *client) sendTarget() {
+	c.mtx.Lock()
+	target := c.target
+	c.mtx.Unlock()
+	c.send(&notification{
+		Method: methodSetTarget,
+		Params: []interface{}{fmt.Sprintf("%064x", target)},
+	})
+}
+
+// notifyJob sends the provided job to the client when it is authorized.  The
+// job target is the easier of the share target of the client and the block
+// target so that every solved block is also submitted as a share.
+func (c *client) notifyJob(j *job, cleanJobs bool) {
+	if !c.authorized() {
+		return
+	}
+
+	c.mtx.Lock()
+	target := c.target
+	c.mtx.Unlock()
+	if j.target.Cmp(target) > 0 {
+		target = j.target
+	}
+	c.send(&notification{
+		Method: methodNotify,
+		Params: []interface{}{
+			j.id,
+			hex.EncodeToString(j.headerHash[:]),
+			hex.EncodeToString(j.seedHash[:]),
+			fmt.Sprintf("%064x", target),
+			cleanJobs,
+			j.header.Height,
+			fmt.Sprintf("%08x", j.header.Bits),
+		},
+	})
+}
+
+// handleRetarget adjusts the share difficulty of the client when needed and
+// sends it the new target along with the current job.
+func (c *client) handleRetarget(now time.Time) {
+	c.mtx.Lock()
+	prevDiff := c.vardiff.difficulty
+	difficulty, changed := c.vardiff.retarget(now)
+	if changed {
+		c.prevTarget = c.target
+		c.prevDiff = prevDiff
+		c.target = difficultyToTarget(c.server.cfg.DiffOne, difficulty)
+	}
+	c.mtx.Unlock()
+	if !changed || !c.authorized() {
+		return
+	}
+
+	log.Debugf("Adjusted share difficulty of stratum client %s to %g", c,
+		difficulty)
+	c.sendTarget()
+	if j := c.server.currentJob(); j != nil {
+		c.notifyJob(j, false)
+	}
+}
+
+// handleSubmit handles the mining.submit method.  The parameters consist of
+// the worker name, the job id, the nonce, the header hash, and the mix hash.
+//
+// Shares are validated with the KawPoW light cache.  Any share that also
+// satisfies the block target is submitted to the network as a block.
+func (c *client) handleSubmit(params []json.RawMessage) (interface{}, error) {
+	if !c.authorized() {
+		return nil, newError(errCodeUnauthorized, "unauthorized worker")
+	}
+
+	jobID, err := stringParam(params, 1, "job id")
+	if err != nil {
+		return nil, err
+	}
+	nonceStr, err := stringParam(params, 2, "nonce")
+	if err != nil {
+		return nil, err
+	}
+	headerHashStr, err := stringParam(params, 3, "header hash")
+	if err != nil {
+		return nil, err
+	}
+	mixHashStr, err := stringParam(params, 4, "mix hash")
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := parseNonce(nonceStr)
+	if err != nil {
+		return nil, err
+	}
+	headerHash, err := parseHash(headerHashStr, "header hash")
+	if err != nil {
+		return nil, err
+	}
+	mixHash, err := parseHash(mixHashStr, "mix hash")
+	if err != nil {
+		return nil, err
+	}
+
+	j := c.server.lookupJob(jobID)
+	if j == nil {
+		return nil, newError(errCodeJobNotFound, "job not found")
+	}
+	if headerHash != j.headerHash {
+		return nil, c.rejectShare(newError(errCodeOther, "header hash "+
+			"does not match job"))
+	}
+	if uint16(nonce>>((8-extraNonceSize)*8)) != c.extraNonce {
+		return nil, c.rejectShare(newError(errCodeOther, "nonce does not "+
+			"start with extra nonce %0*x", extraNonceSize*2, c.extraNonce))
+	}
+
+	// Reject duplicates before performing the more expensive verification.
+	// Note that the nonce is only recorded once the share is verified so that
+	// an invalid submission does not prevent the valid share for the same
+	// nonce from being accepted.
+	if c.server.isSubmitted(j, nonce) {
+		return nil, newError(errCodeDuplicate, "duplicate share")
+	}
+
+	header := j.header
+	header.Nonce = nonce
+	header.MixHash = wire.KawPowToChainHash((*kawpow.Hash)(&mixHash))
+
+	// Determine the share target and the difficulty it is credited with.  The
+	// previous share target is also accepted since clients may still be
+	// working on jobs sent prior to the most recent difficulty adjustment.
+	c.mtx.Lock()
+	worker, account := c.worker, c.account
+	target, difficulty := c.target, c.vardiff.difficulty
+	if c.prevTarget.Cmp(target) > 0 {
+		target, difficulty = c.prevTarget, c.prevDiff
+	}
+	c.mtx.Unlock()
+
+	// Determine whether the solution satisfies the block target and
+	// otherwise validate it as a share.
+	err = header.VerifyKawPow(j.target)
+	isBlock := err == nil
+	if errors.Is(err, kawpow.ErrHighHash) {
+		err = header.VerifyKawPow(target)
+	}
+	switch {
+	case errors.Is(err, kawpow.ErrHighHash):
+		return nil, c.rejectShare(newError(errCodeLowDiffShare, "low "+
+			"difficulty share"))
+	case errors.Is(err, kawpow.ErrBadMixHash):
+		return nil, c.rejectShare(newError(errCodeOther, "invalid mix hash"))
+	case err != nil:
+		log.Errorf("Unexpected error verifying stratum share: %v", err)
+		return nil, newError(errCodeOther, "unable to verify share")
+	}
+	if !c.server.markSubmitted(j, nonce) {
+		return nil, newError(errCodeDuplicate, "duplicate share")
+	}
+
+	// Submit the block when the solution satisfies the block target.  Such a
+	// share is credited with at least the share difficulty of the client.
+	if isBlock {
+		c.server.submitBlock(&header, worker)
+	}
+	c.acceptShare(account, difficulty)
+	return true, nil
+}
+
+// rejectShare records an invalid share submitted by the client and returns the
+// provided error.  The client is disconnected once it has submitted more than
+// maxInvalidShares consecutive invalid shares.
+func (c *client) rejectShare(sErr *stratumError) error {
+	c.mtx.Lock()
+	c.invalidShares++
+	invalidShares := c.invalidShares
+	c.mtx.Unlock()
+	if invalidShares > maxInvalidShares {
+		log.Warnf("Disconnecting stratum client %s: %d consecutive invalid "+
+			"shares", c, invalidShares)
+		c.disconnect()
+	}
+	return sErr
+}
+
+// acceptShare records an accepted share for the purposes of adjusting the
+// share difficulty of the client, resets its consecutive invalid shares, and
+// reports it to the server owner when requested.
+func (c *client) acceptShare(account string, difficulty float64) {
+	c.mtx.Lock()
+	c.vardiff.addShare()
+	c.invalidShares = 0
+	c.mtx.Unlock()
+	if c.server.cfg.ShareAccepted != nil {
+		c.server.cfg.ShareAccepted(account, difficulty)
+	}
+}
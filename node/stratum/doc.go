@@ -0,0 +1,27 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package stratum provides a stratum v1 server for KawPoW mining software.
+
+It is shared by vgld, which serves the block templates it generates directly
+to miners, and vglpool, which serves the work it obtains from vgld.  The owner
+of the server provides work in the form of block headers via SetWork, and each
+job sent to clients consists of the KawPoW header hash, the seed hash of the
+epoch, the share target, the block height, and the target difficulty bits,
+which is the form expected by common KawPoW miners.
+
+Every client is assigned a unique nonzero 2-byte extra nonce that forms the
+most significant bytes of the nonces it searches, so a single job is shared by
+all clients without any of them duplicating work.  Since the zero extra nonce
+is reserved, at most 65535 clients may be connected at once.  The share
+difficulty of each client is adjusted over time to achieve a target share rate.
+
+Submitted shares are validated with the KawPoW light cache, so the full dataset
+is never required.  Accepted shares are reported to the owner of the server
+along with the account and difficulty they are credited with, and any share
+that also satisfies the block target is handed to the owner to submit to the
+network as a block.
+*/
+package stratum
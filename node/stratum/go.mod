@@ -0,0 +1,21 @@
+module github.com/kdsmith18542/vigil/stratum
+
+go 1.21
+
+require (
+	github.com/kdsmith18542/vigil/blockchain/standalone/v2 v2.2.1
+	github.com/kdsmith18542/vigil/kawpow v0.0.0-00010101000000-000000000000
+	github.com/kdsmith18542/vigil/slog v1.2.0
+	github.com/kdsmith18542/vigil/wire v1.7.0
+)
+
+require (
+	github.com/kdsmith18542/vigil/chaincfg/chainhash v1.0.4 // indirect
+	github.com/kdsmith18542/vigil/crypto/blake256 v1.0.1 // indirect
+	github.com/klauspost/cpuid/v2 v2.0.9 // indirect
+	golang.org/x/crypto v0.17.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+	lukechampine.com/blake3 v1.3.0 // indirect
+)
+
+replace github.com/kdsmith18542/vigil/kawpow => ../kawpow
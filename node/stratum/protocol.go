@@ -0,0 +1,132 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stratum
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// These constants define the stratum methods supported by the server.
+const (
+	methodSubscribe           = "mining.subscribe"
+	methodAuthorize           = "mining.authorize"
+	methodSubmit              = "mining.submit"
+	methodExtraNonceSubscribe = "mining.extranonce.subscribe"
+	methodSetTarget           = "mining.set_target"
+	methodNotify              = "mining.notify"
+)
+
+// errorCode identifies a kind of stratum error.  The values are the ones
+// commonly used by stratum pools and understood by mining software.
+type errorCode int
+
+// These constants define the stratum error codes.
+const (
+	errCodeOther         errorCode = 20
+	errCodeJobNotFound   errorCode = 21
+	errCodeDuplicate     errorCode = 22
+	errCodeLowDiffShare  errorCode = 23
+	errCodeUnauthorized  errorCode = 24
+	errCodeNotSubscribed errorCode = 25
+)
+
+// stratumError describes an error returned to a stratum client.
+type stratumError struct {
+	Code    errorCode
+	Message string
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *stratumError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// MarshalJSON marshals the error in the array form used by the stratum
+// protocol, which consists of the code, the message, and an unused traceback.
+func (e *stratumError) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.Code, e.Message, nil})
+}
+
+// newError returns a stratum error with the provided code and message.
+func newError(code errorCode, format string, args ...interface{}) *stratumError {
+	return &stratumError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// request is a request sent by a stratum client.
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// response is the response to a request sent by a stratum client.
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  *stratumError   `json:"error"`
+}
+
+// notification is a message sent to a stratum client that is not in response
+// to a request.
+type notification struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// stringParam returns the string request parameter at the provided index.
+func stringParam(params []json.RawMessage, index int, name string) (string, error) {
+	if index >= len(params) {
+		return "", newError(errCodeOther, "missing %s parameter", name)
+	}
+	var s string
+	if err := json.Unmarshal(params[index], &s); err != nil {
+		return "", newError(errCodeOther, "%s parameter is not a string",
+			name)
+	}
+	return s, nil
+}
+
+// trimHexPrefix removes the optional 0x prefix from the provided hex string.
+func trimHexPrefix(s string) string {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return s[2:]
+	}
+	return s
+}
+
+// parseHash decodes the provided hex string, which may have a 0x prefix, into
+// a 32-byte hash in the same byte order as the string.
+func parseHash(s, name string) ([32]byte, error) {
+	var hash [32]byte
+	s = trimHexPrefix(s)
+	if hex.DecodedLen(len(s)) != len(hash) {
+		return hash, newError(errCodeOther, "%s must be %d hex characters",
+			name, len(hash)*2)
+	}
+	if _, err := hex.Decode(hash[:], []byte(s)); err != nil {
+		return hash, newError(errCodeOther, "%s is not valid hex", name)
+	}
+	return hash, nil
+}
+
+// parseNonce decodes the provided hex string, which may have a 0x prefix, into
+// a 64-bit nonce.  The string is the big-endian representation of the nonce as
+// sent by KawPoW mining software.
+func parseNonce(s string) (uint64, error) {
+	s = trimHexPrefix(s)
+	if len(s) != 16 {
+		return 0, newError(errCodeOther, "nonce must be 16 hex characters")
+	}
+	nonce, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, newError(errCodeOther, "nonce is not valid hex")
+	}
+	return nonce, nil
+}
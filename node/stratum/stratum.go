@@ -0,0 +1,408 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stratum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kdsmith18542/vigil/blockchain/standalone/v2"
+	"github.com/kdsmith18542/vigil/kawpow"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+const (
+	// DefaultInitialDifficulty is the default share difficulty assigned to
+	// new clients.
+	DefaultInitialDifficulty = 1
+
+	// DefaultTargetShareTime is the default amount of time the vardiff
+	// algorithm targets between shares from a single client.
+	DefaultTargetShareTime = time.Second * 10
+
+	// DefaultRetargetInterval is the default amount of time between share
+	// difficulty adjustments for a client.
+	DefaultRetargetInterval = time.Second * 90
+
+	// DefaultMaxClients is the default maximum number of clients that may be
+	// connected at once.
+	DefaultMaxClients = 100
+
+	// MaxClientsLimit is the largest allowed maximum number of clients.  Every
+	// client is assigned a unique 2-byte extra nonce and the zero extra nonce
+	// is reserved, so there can not be more clients than there are other
+	// extra nonces.
+	MaxClientsLimit = math.MaxUint16
+
+	// reservedExtraNonce is the extra nonce that is never assigned to a
+	// client.  It is skipped so that a client always has a nonzero extra
+	// nonce, which avoids handing out the nonce space that is searched by
+	// default by mining software that ignores the assigned extra nonce.
+	reservedExtraNonce = 0
+
+	// maxJobs is the maximum number of jobs for the current chain tip that
+	// shares are accepted for.
+	maxJobs = 8
+)
+
+// Config is a descriptor containing the stratum server configuration.
+type Config struct {
+	// DiffOne is the target that corresponds to a share difficulty of one.
+	// It is typically the proof of work limit of the network and must be
+	// set.
+	DiffOne *big.Int
+
+	// Listeners defines a slice of listeners for which the server will take
+	// ownership of and accept connections.
+	Listeners []net.Listener
+
+	// Authorize returns the account that shares submitted by the provided
+	// worker are credited to or an error when the worker is not allowed to
+	// mine.  It is optional and every worker is authorized with its name as
+	// the account when it is nil.
+	Authorize func(worker string) (string, error)
+
+	// SubmitBlock submits the provided solved block header found by the
+	// provided worker to the network and must be set.  It returns whether or
+	// not the block was accepted.
+	SubmitBlock func(header *wire.BlockHeader, worker string) (bool, error)
+
+	// ShareAccepted is invoked with the account and difficulty of every
+	// accepted share.  It is optional.
+	ShareAccepted func(account string, difficulty float64)
+
+	// InitialDifficulty is the share difficulty assigned to new clients.  It
+	// defaults to DefaultInitialDifficulty when zero.
+	InitialDifficulty float64
+
+	// MinDifficulty and MaxDifficulty bound the share difficulty of clients.
+	// They default to the initial difficulty divided by and multiplied by
+	// 2^32, respectively, when zero.
+	MinDifficulty float64
+	MaxDifficulty float64
+
+	// TargetShareTime is the amount of time the vardiff algorithm targets
+	// between shares from a single client.  It defaults to
+	// DefaultTargetShareTime when zero.
+	TargetShareTime time.Duration
+
+	// RetargetInterval is the amount of time between share difficulty
+	// adjustments for a client.  It defaults to DefaultRetargetInterval when
+	// zero.
+	RetargetInterval time.Duration
+
+	// MaxClients is the maximum number of clients that may be connected at
+	// once.  It defaults to DefaultMaxClients when zero and may not exceed
+	// MaxClientsLimit.
+	MaxClients int
+}
+
+var (
+	// errMaxClients is returned when a client can not be added because the
+	// maximum number of clients are already connected.
+	errMaxClients = errors.New("maximum clients reached")
+
+	// errExtraNoncesExhausted is returned when a client can not be added
+	// because every extra nonce is in use.
+	errExtraNoncesExhausted = errors.New("no extra nonces available")
+)
+
+// job houses the work associated with a block header that is sent to
+// clients.  Since the KawPoW header hash commits to every header field other
+// than the nonce and mix hash, a single job is shared by all clients, which
+// each search a distinct portion of the nonce space determined by their extra
+// nonce.
+type job struct {
+	id         string
+	header     wire.BlockHeader
+	headerHash kawpow.Hash
+	seedHash   kawpow.Hash
+	target     *big.Int
+
+	// submitted tracks the nonces of accepted shares for the job in order to
+	// reject duplicates.  It is protected by the server mutex.
+	submitted map[uint64]struct{}
+}
+
+// Server provides a stratum server for KawPoW mining software such as
+// kawpowminer, T-Rex, and NBMiner.  It hands out work based on the block
+// headers provided via SetWork, adjusts the share difficulty of each client to
+// achieve a target share rate, validates shares with the KawPoW light cache,
+// and submits any solved blocks.
+type Server struct {
+	cfg Config
+
+	// submitBlockLock serializes the submission of solved blocks.
+	submitBlockLock sync.Mutex
+
+	mtx            sync.Mutex
+	clients        map[*client]struct{}
+	extraNonces    map[uint16]struct{}
+	nextExtraNonce uint16
+	jobs           map[string]*job
+	jobOrder       []string
+	curJob         *job
+	nextJobID      uint64
+
+	wg sync.WaitGroup
+}
+
+// New returns a new stratum server with the provided configuration.  Zero
+// values in the configuration are replaced by their defaults.  An error is
+// returned when the configuration is invalid.
+func New(cfg *Config) (*Server, error) {
+	if cfg.DiffOne == nil || cfg.DiffOne.Sign() <= 0 {
+		return nil, errors.New("stratum difficulty one target must be " +
+			"positive")
+	}
+	if cfg.SubmitBlock == nil {
+		return nil, errors.New("stratum block submission function is not " +
+			"set")
+	}
+	if cfg.MaxClients < 0 || cfg.MaxClients > MaxClientsLimit {
+		return nil, fmt.Errorf("maximum stratum clients %d is not in the "+
+			"range [0, %d]", cfg.MaxClients, MaxClientsLimit)
+	}
+	c := *cfg
+	if c.InitialDifficulty == 0 {
+		c.InitialDifficulty = DefaultInitialDifficulty
+	}
+	if c.MinDifficulty == 0 {
+		c.MinDifficulty = c.InitialDifficulty / (1 << 32)
+	}
+	if c.MaxDifficulty == 0 {
+		c.MaxDifficulty = c.InitialDifficulty * (1 << 32)
+	}
+	if c.TargetShareTime == 0 {
+		c.TargetShareTime = DefaultTargetShareTime
+	}
+	if c.RetargetInterval == 0 {
+		c.RetargetInterval = DefaultRetargetInterval
+	}
+	if c.MaxClients == 0 {
+		c.MaxClients = DefaultMaxClients
+	}
+	s := &Server{
+		cfg:         c,
+		clients:     make(map[*client]struct{}),
+		extraNonces: make(map[uint16]struct{}),
+		jobs:        make(map[string]*job),
+	}
+	return s, nil
+}
+
+// NumClients returns the number of connected clients.
+func (s *Server) NumClients() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.clients)
+}
+
+// acceptConnections accepts connections from the provided listener until it
+// is closed.  It must be run as a goroutine.
+func (s *Server) acceptConnections(ctx context.Context, listener net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Errorf("Unable to accept stratum connection: %v", err)
+			continue
+		}
+
+		c, err := s.addClient(conn)
+		if err != nil {
+			log.Warnf("Rejecting stratum connection from %s: %v",
+				conn.RemoteAddr(), err)
+			conn.Close()
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			c.run(ctx)
+			s.removeClient(c)
+		}()
+	}
+}
+
+// addClient creates a client for the provided connection with a unique extra
+// nonce and adds it to the set of connected clients.  An error is returned
+// when the maximum number of clients are already connected or there are no
+// unused extra nonces.
+func (s *Server) addClient(conn net.Conn) (*client, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if len(s.clients) >= s.cfg.MaxClients {
+		return nil, fmt.Errorf("%w (%d)", errMaxClients, s.cfg.MaxClients)
+	}
+
+	// Search for an unused extra nonce starting after the most recently
+	// assigned one.  At most one full cycle through the extra nonces is
+	// needed to find one when any are unused.
+	extraNonce := s.nextExtraNonce
+	found := false
+	for i := 0; i <= math.MaxUint16; i++ {
+		extraNonce++
+		if extraNonce == reservedExtraNonce {
+			continue
+		}
+		if _, ok := s.extraNonces[extraNonce]; !ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errExtraNoncesExhausted
+	}
+	s.nextExtraNonce = extraNonce
+	s.extraNonces[extraNonce] = struct{}{}
+	c := newClient(s, conn, extraNonce)
+	s.clients[c] = struct{}{}
+	return c, nil
+}
+
+// removeClient removes the provided client from the set of connected clients
+// and releases its extra nonce.
+func (s *Server) removeClient(c *client) {
+	s.mtx.Lock()
+	delete(s.clients, c)
+	delete(s.extraNonces, c.extraNonce)
+	s.mtx.Unlock()
+}
+
+// currentJob returns the most recent job, if any.
+func (s *Server) currentJob() *job {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.curJob
+}
+
+// lookupJob returns the job with the provided id when it is still valid.
+func (s *Server) lookupJob(id string) *job {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.jobs[id]
+}
+
+// isSubmitted returns whether or not a share with the provided nonce was
+// already accepted for the job.
+func (s *Server) isSubmitted(j *job, nonce uint64) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	_, ok := j.submitted[nonce]
+	return ok
+}
+
+// markSubmitted records the provided nonce of an accepted share as submitted
+// for the job and returns false when it was already submitted.
+func (s *Server) markSubmitted(j *job, nonce uint64) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := j.submitted[nonce]; ok {
+		return false
+	}
+	j.submitted[nonce] = struct{}{}
+	return true
+}
+
+// SetWork creates a new job for the provided block header and sends it to all
+// clients.  All previous jobs are invalidated when the header builds on a
+// different parent.
+//
+// This function is safe for concurrent access.
+func (s *Server) SetWork(header *wire.BlockHeader) {
+	j := &job{
+		header:     *header,
+		headerHash: kawpow.Hash(header.KawPowHeaderHash()),
+		seedHash:   kawpow.SeedHash(kawpow.EpochForHeight(uint64(header.Height))),
+		target:     standalone.CompactToBig(header.Bits),
+		submitted:  make(map[uint64]struct{}),
+	}
+
+	s.mtx.Lock()
+	s.nextJobID++
+	j.id = strconv.FormatUint(s.nextJobID, 16)
+	cleanJobs := s.curJob == nil || s.curJob.header.PrevBlock != header.PrevBlock
+	if cleanJobs {
+		s.jobs = make(map[string]*job)
+		s.jobOrder = s.jobOrder[:0]
+	}
+	s.jobs[j.id] = j
+	s.jobOrder = append(s.jobOrder, j.id)
+	if len(s.jobOrder) > maxJobs {
+		delete(s.jobs, s.jobOrder[0])
+		s.jobOrder = s.jobOrder[1:]
+	}
+	s.curJob = j
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mtx.Unlock()
+
+	log.Debugf("New stratum job %s for block height %d (clean %v)", j.id,
+		header.Height, cleanJobs)
+	for _, c := range clients {
+		c.notifyJob(j, cleanJobs)
+	}
+}
+
+// submitBlock submits the provided solved block header to the network and
+// returns whether or not it was accepted.
+func (s *Server) submitBlock(header *wire.BlockHeader, worker string) bool {
+	s.submitBlockLock.Lock()
+	defer s.submitBlockLock.Unlock()
+
+	accepted, err := s.cfg.SubmitBlock(header, worker)
+	if err != nil {
+		log.Errorf("Unable to submit block found by %s: %v", worker, err)
+		return false
+	}
+	if !accepted {
+		log.Infof("Block %s found by %s was rejected", header.BlockHash(),
+			worker)
+		return false
+	}
+	log.Infof("Block %s found by %s accepted (height %d)", header.BlockHash(),
+		worker, header.Height)
+	return true
+}
+
+// Run starts the stratum server and blocks until the provided context is
+// cancelled.  It accepts connections on the configured listeners and sends
+// clients the jobs created for the work provided via SetWork.
+func (s *Server) Run(ctx context.Context) {
+	log.Trace("Starting stratum server")
+	for _, listener := range s.cfg.Listeners {
+		log.Infof("Stratum server listening on %s", listener.Addr())
+		s.wg.Add(1)
+		go s.acceptConnections(ctx, listener)
+	}
+
+	<-ctx.Done()
+
+	// Stop accepting connections and disconnect all clients.
+	for _, listener := range s.cfg.Listeners {
+		listener.Close()
+	}
+	s.mtx.Lock()
+	for c := range s.clients {
+		c.disconnect()
+	}
+	s.mtx.Unlock()
+	s.wg.Wait()
+	log.Trace("Stratum server stopped")
+}
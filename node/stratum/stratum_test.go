@@ -0,0 +1,403 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stratum
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// TestVardiff ensures the share difficulty is adjusted toward the target share
+// time, is limited by the maximum factor and the configured bounds, and is not
+// changed for small deviations.
+func TestVardiff(t *testing.T) {
+	cfg := &Config{
+		InitialDifficulty: 16,
+		MinDifficulty:     2,
+		MaxDifficulty:     100,
+		TargetShareTime:   time.Second * 10,
+		RetargetInterval:  time.Second * 100,
+	}
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		shares  uint32
+		want    float64
+		changed bool
+	}{{
+		name:    "interval not elapsed",
+		elapsed: time.Second * 99,
+		shares:  100,
+		want:    16,
+	}, {
+		name:    "on target",
+		elapsed: time.Second * 100,
+		shares:  10,
+		want:    16,
+	}, {
+		name:    "within threshold",
+		elapsed: time.Second * 105,
+		shares:  10,
+		want:    16,
+	}, {
+		name:    "twice as fast",
+		elapsed: time.Second * 100,
+		shares:  20,
+		want:    32,
+		changed: true,
+	}, {
+		name:    "half as fast",
+		elapsed: time.Second * 100,
+		shares:  5,
+		want:    8,
+		changed: true,
+	}, {
+		name:    "limited by max factor",
+		elapsed: time.Second * 100,
+		shares:  70,
+		want:    64,
+		changed: true,
+	}, {
+		name:    "no shares",
+		elapsed: time.Second * 100,
+		want:    4,
+		changed: true,
+	}}
+
+	for _, test := range tests {
+		now := time.Unix(1700000000, 0)
+		v := newVardiff(cfg, now)
+		for i := uint32(0); i < test.shares; i++ {
+			v.addShare()
+		}
+		got, changed := v.retarget(now.Add(test.elapsed))
+		if got != test.want || changed != test.changed {
+			t.Errorf("%s: unexpected retarget -- got (%v, %v), want "+
+				"(%v, %v)", test.name, got, changed, test.want,
+				test.changed)
+		}
+	}
+
+	// Ensure the bounds are respected over repeated retargets.
+	now := time.Unix(1700000000, 0)
+	v := newVardiff(cfg, now)
+	for i := 0; i < 10; i++ {
+		now = now.Add(cfg.RetargetInterval)
+		v.retarget(now)
+	}
+	if v.difficulty != cfg.MinDifficulty {
+		t.Fatalf("unexpected difficulty -- got %v, want %v", v.difficulty,
+			cfg.MinDifficulty)
+	}
+}
+
+// TestDifficultyToTarget ensures share difficulties are converted to the
+// expected targets.
+func TestDifficultyToTarget(t *testing.T) {
+	diff1 := new(big.Int).Lsh(big.NewInt(1), 224)
+	maxTarget := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256),
+		big.NewInt(1))
+
+	tests := []struct {
+		name       string
+		difficulty float64
+		want       *big.Int
+	}{{
+		name:       "difficulty one",
+		difficulty: 1,
+		want:       diff1,
+	}, {
+		name:       "difficulty 256",
+		difficulty: 256,
+		want:       new(big.Int).Lsh(big.NewInt(1), 216),
+	}, {
+		name:       "fractional difficulty",
+		difficulty: 0.5,
+		want:       new(big.Int).Lsh(big.NewInt(1), 225),
+	}, {
+		name:       "clamped to max target",
+		difficulty: 1.0 / (1 << 40),
+		want:       maxTarget,
+	}, {
+		name:       "zero difficulty",
+		difficulty: 0,
+		want:       maxTarget,
+	}, {
+		name:       "clamped to one",
+		difficulty: 1e80,
+		want:       big.NewInt(1),
+	}}
+
+	for _, test := range tests {
+		got := difficultyToTarget(diff1, test.difficulty)
+		if got.Cmp(test.want) != 0 {
+			t.Errorf("%s: unexpected target -- got %x, want %x", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestParseParams ensures the request parameter parsing functions handle
+// valid and invalid parameters as expected.
+func TestParseParams(t *testing.T) {
+	var params []json.RawMessage
+	err := json.Unmarshal([]byte(`["worker", 5]`), &params)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling params: %v", err)
+	}
+	if s, err := stringParam(params, 0, "worker"); err != nil || s != "worker" {
+		t.Fatalf("unexpected string param -- got (%q, %v)", s, err)
+	}
+	var sErr *stratumError
+	if _, err := stringParam(params, 1, "job id"); !errors.As(err, &sErr) {
+		t.Fatalf("unexpected error for non-string param: %v", err)
+	}
+	if _, err := stringParam(params, 2, "nonce"); !errors.As(err, &sErr) {
+		t.Fatalf("unexpected error for missing param: %v", err)
+	}
+
+	nonce, err := parseNonce("0x0001020304050607")
+	if err != nil || nonce != 0x0001020304050607 {
+		t.Fatalf("unexpected nonce -- got (%x, %v)", nonce, err)
+	}
+	if _, err := parseNonce("01020304"); err == nil {
+		t.Fatal("expected error for short nonce")
+	}
+	if _, err := parseNonce("zz01020304050607"); err == nil {
+		t.Fatal("expected error for invalid nonce")
+	}
+
+	hash, err := parseHash("01000000000000000000000000000000000000000000"+
+		"000000000000000000ff", "mix hash")
+	if err != nil || hash[0] != 0x01 || hash[31] != 0xff {
+		t.Fatalf("unexpected hash -- got (%x, %v)", hash, err)
+	}
+	if _, err := parseHash("0x0100", "mix hash"); err == nil {
+		t.Fatal("expected error for short hash")
+	}
+
+	b, err := json.Marshal(newError(errCodeDuplicate, "duplicate share"))
+	if err != nil || string(b) != `[22,"duplicate share",null]` {
+		t.Fatalf("unexpected marshalled error -- got (%s, %v)", b, err)
+	}
+}
+
+// TestNewConfig ensures invalid configurations are rejected.
+func TestNewConfig(t *testing.T) {
+	diff1 := new(big.Int).Lsh(big.NewInt(1), 224)
+	submitBlock := func(*wire.BlockHeader, string) (bool, error) {
+		return true, nil
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{{
+		name: "valid",
+		cfg:  Config{DiffOne: diff1, SubmitBlock: submitBlock},
+	}, {
+		name:    "missing difficulty one target",
+		cfg:     Config{SubmitBlock: submitBlock},
+		wantErr: true,
+	}, {
+		name:    "zero difficulty one target",
+		cfg:     Config{DiffOne: new(big.Int), SubmitBlock: submitBlock},
+		wantErr: true,
+	}, {
+		name:    "missing block submission function",
+		cfg:     Config{DiffOne: diff1},
+		wantErr: true,
+	}, {
+		name: "maximum clients over the limit",
+		cfg: Config{DiffOne: diff1, SubmitBlock: submitBlock,
+			MaxClients: MaxClientsLimit + 1},
+		wantErr: true,
+	}, {
+		name: "negative maximum clients",
+		cfg: Config{DiffOne: diff1, SubmitBlock: submitBlock,
+			MaxClients: -1},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		s, err := New(&test.cfg)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if err == nil && s.cfg.MaxClients != DefaultMaxClients {
+			t.Errorf("%s: unexpected maximum clients -- got %d, want %d",
+				test.name, s.cfg.MaxClients, DefaultMaxClients)
+		}
+	}
+}
+
+// TestAddClientExtraNonces ensures the reserved extra nonce is skipped when
+// the extra nonces wrap around, an error is returned once every extra nonce is
+// in use, and the maximum clients are enforced.
+func TestAddClientExtraNonces(t *testing.T) {
+	s, err := New(&Config{
+		DiffOne: new(big.Int).Lsh(big.NewInt(1), 224),
+		SubmitBlock: func(*wire.BlockHeader, string) (bool, error) {
+			return true, nil
+		},
+		MaxClients: MaxClientsLimit,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	conn, peer := net.Pipe()
+	defer conn.Close()
+	defer peer.Close()
+
+	// Ensure the search wraps around past the reserved extra nonce.
+	s.nextExtraNonce = 65534
+	s.extraNonces[65535] = struct{}{}
+	c, err := s.addClient(conn)
+	if err != nil {
+		t.Fatalf("unexpected error adding client: %v", err)
+	}
+	if c.extraNonce != 1 {
+		t.Fatalf("unexpected extra nonce -- got %d, want 1", c.extraNonce)
+	}
+
+	// Ensure an error is returned when every extra nonce is in use.
+	for i := 1; i <= 65535; i++ {
+		s.extraNonces[uint16(i)] = struct{}{}
+	}
+	if _, err := s.addClient(conn); !errors.Is(err, errExtraNoncesExhausted) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			errExtraNoncesExhausted)
+	}
+	if _, ok := s.extraNonces[reservedExtraNonce]; ok {
+		t.Fatal("reserved extra nonce was assigned")
+	}
+
+	// Ensure an error is returned when the maximum clients are connected.
+	s.cfg.MaxClients = 1
+	if _, err := s.addClient(conn); !errors.Is(err, errMaxClients) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, errMaxClients)
+	}
+}
+
+// TestHandleSubmit ensures shares are only recorded as submitted once they are
+// verified, duplicates of accepted shares are rejected, and clients are
+// disconnected once they submit too many consecutive invalid shares.
+func TestHandleSubmit(t *testing.T) {
+	maxTarget := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256),
+		big.NewInt(1))
+	var blocksSubmitted int
+	s, err := New(&Config{
+		DiffOne:           maxTarget,
+		InitialDifficulty: 1,
+		SubmitBlock: func(*wire.BlockHeader, string) (bool, error) {
+			blocksSubmitted++
+			return true, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	conn, peer := net.Pipe()
+	defer conn.Close()
+	defer peer.Close()
+	c, err := s.addClient(conn)
+	if err != nil {
+		t.Fatalf("unexpected error adding client: %v", err)
+	}
+
+	// Create a job with a block target that is not met by any of the shares
+	// and authorize the client afterwards so it is not sent any messages.
+	header := wire.BlockHeader{Height: 1, Bits: 0x1d00ffff}
+	s.SetWork(&header)
+	j := s.currentJob()
+	c.worker, c.account = "worker", "account"
+
+	// submit submits a share for the job with the provided nonce, header hash,
+	// and mix hash and returns the stratum error code, if any.
+	submit := func(nonce uint64, headerHash, mixHash string) errorCode {
+		t.Helper()
+		params := make([]json.RawMessage, 0, 5)
+		for _, param := range []string{"worker", j.id,
+			fmt.Sprintf("%016x", nonce), headerHash, mixHash} {
+			params = append(params, json.RawMessage(`"`+param+`"`))
+		}
+		result, err := c.handleSubmit(params)
+		if err != nil {
+			var sErr *stratumError
+			if !errors.As(err, &sErr) {
+				t.Fatalf("unexpected error type %T", err)
+			}
+			return sErr.Code
+		}
+		if result != true {
+			t.Fatalf("unexpected result %v", result)
+		}
+		return 0
+	}
+	disconnected := func() bool {
+		select {
+		case <-c.quit:
+			return true
+		default:
+			return false
+		}
+	}
+
+	headerHash := hex.EncodeToString(j.headerHash[:])
+	badHash := hex.EncodeToString(make([]byte, 32))
+	nonce := uint64(c.extraNonce)<<((8-extraNonceSize)*8) | 1
+	solved := j.header
+	solved.Nonce = nonce
+	mixHash, _, err := solved.PowHashKawPow()
+	if err != nil {
+		t.Fatalf("unexpected error calculating mix hash: %v", err)
+	}
+
+	// Ensure a share with an invalid mix hash is rejected and does not prevent
+	// the valid share with the same nonce from being accepted.
+	if code := submit(nonce, headerHash, badHash); code != errCodeOther {
+		t.Fatalf("unexpected code for invalid mix hash -- got %d, want %d",
+			code, errCodeOther)
+	}
+	if code := submit(nonce, headerHash, mixHash.String()); code != 0 {
+		t.Fatalf("valid share was rejected with code %d", code)
+	}
+	if blocksSubmitted != 0 {
+		t.Fatal("share that does not meet the block target was submitted")
+	}
+
+	// Ensure the accepted share is rejected as a duplicate.
+	if code := submit(nonce, headerHash, mixHash.String()); code != errCodeDuplicate {
+		t.Fatalf("unexpected code for duplicate share -- got %d, want %d",
+			code, errCodeDuplicate)
+	}
+
+	// Ensure the client remains connected through the maximum number of
+	// consecutive invalid shares and is disconnected after one more.
+	for i := 0; i < maxInvalidShares; i++ {
+		submit(nonce+uint64(i)+1, badHash, badHash)
+	}
+	if disconnected() {
+		t.Fatal("client disconnected at the maximum invalid shares")
+	}
+	submit(nonce+maxInvalidShares+1, badHash, badHash)
+	if !disconnected() {
+		t.Fatal("client not disconnected after too many invalid shares")
+	}
+}
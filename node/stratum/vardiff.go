@@ -0,0 +1,107 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stratum
+
+import (
+	"math"
+	"math/big"
+	"time"
+)
+
+const (
+	// maxRetargetFactor is the maximum factor the share difficulty of a
+	// client is adjusted by in a single retarget.
+	maxRetargetFactor = 4
+
+	// retargetThreshold is the minimum relative change in share difficulty
+	// that results in a new difficulty.  It prevents constantly sending new
+	// targets to clients due to normal variance.
+	retargetThreshold = 0.1
+)
+
+// vardiff tracks the share rate of a client in order to adjust its share
+// difficulty such that it submits shares at roughly the target share time.
+type vardiff struct {
+	targetShareTime  time.Duration
+	retargetInterval time.Duration
+	minDiff, maxDiff float64
+
+	difficulty   float64
+	lastRetarget time.Time
+	shares       uint32
+}
+
+// newVardiff returns a vardiff instance that starts at the provided difficulty
+// clamped to the provided range.
+func newVardiff(cfg *Config, now time.Time) *vardiff {
+	return &vardiff{
+		targetShareTime:  cfg.TargetShareTime,
+		retargetInterval: cfg.RetargetInterval,
+		minDiff:          cfg.MinDifficulty,
+		maxDiff:          cfg.MaxDifficulty,
+		difficulty: math.Min(math.Max(cfg.InitialDifficulty,
+			cfg.MinDifficulty), cfg.MaxDifficulty),
+		lastRetarget: now,
+	}
+}
+
+// addShare records an accepted share.
+func (v *vardiff) addShare() {
+	v.shares++
+}
+
+// retarget calculates a new share difficulty once the retarget interval has
+// elapsed since the previous retarget.  The returned bool is true when the
+// difficulty changed.
+//
+// When no shares were submitted during the interval, the difficulty is reduced
+// by the maximum factor.  Otherwise, it is scaled by the ratio of the target
+// share time to the observed average share time, limited to the maximum
+// factor.
+func (v *vardiff) retarget(now time.Time) (float64, bool) {
+	elapsed := now.Sub(v.lastRetarget)
+	if elapsed < v.retargetInterval {
+		return v.difficulty, false
+	}
+
+	factor := 1.0 / maxRetargetFactor
+	if v.shares > 0 {
+		avgShareTime := elapsed.Seconds() / float64(v.shares)
+		factor = v.targetShareTime.Seconds() / avgShareTime
+		factor = math.Min(math.Max(factor, 1.0/maxRetargetFactor),
+			maxRetargetFactor)
+	}
+	v.lastRetarget = now
+	v.shares = 0
+
+	newDiff := math.Min(math.Max(v.difficulty*factor, v.minDiff), v.maxDiff)
+	if math.Abs(newDiff-v.difficulty)/v.difficulty < retargetThreshold {
+		return v.difficulty, false
+	}
+	v.difficulty = newDiff
+	return newDiff, true
+}
+
+// difficultyToTarget converts the provided share difficulty to a target where a
+// difficulty of one is the provided difficulty one target.  The target is
+// limited to the maximum 256-bit value and is at least one.
+func difficultyToTarget(diff1 *big.Int, difficulty float64) *big.Int {
+	maxTarget := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256),
+		big.NewInt(1))
+	if difficulty <= 0 {
+		return maxTarget
+	}
+
+	quo := new(big.Float).SetInt(diff1)
+	quo.Quo(quo, big.NewFloat(difficulty))
+	target, _ := quo.Int(nil)
+	if target.Cmp(maxTarget) > 0 {
+		return maxTarget
+	}
+	if target.Sign() <= 0 {
+		return big.NewInt(1)
+	}
+	return target
+}
@@ -75,12 +75,12 @@ func (p *mockAddrParams) AddrIDScriptHashV0() [2]byte {
 // was written.
 func mockMainNetParams() *mockAddrParams {
 	return &mockAddrParams{
-		pubKeyID:     [2]byte{0x13, 0x86}, // starts with Dk
-		pkhEcdsaID:   [2]byte{0x07, 0x3f}, // starts with Ds
-		pkhEd25519ID: [2]byte{0x07, 0x1f}, // starts with De
-		pkhSchnorrID: [2]byte{0x07, 0x01}, // starts with DS
-		scriptHashID: [2]byte{0x07, 0x1a}, // starts with Dc
-		privKeyID:    [2]byte{0x22, 0xde}, // starts with Pm
+		pubKeyID:     [2]byte{0x2c, 0x08}, // starts with Vk
+		pkhEcdsaID:   [2]byte{0x10, 0x41}, // starts with Vs
+		pkhEd25519ID: [2]byte{0x10, 0x21}, // starts with Ve
+		pkhSchnorrID: [2]byte{0x10, 0x03}, // starts with VS
+		scriptHashID: [2]byte{0x10, 0x1c}, // starts with Vc
+		privKeyID:    [2]byte{0x23, 0x1b}, // starts with Pv
 	}
 }
 
@@ -165,17 +165,17 @@ func TestAddresses(t *testing.T) {
 		decodeErr: ErrBadAddressChecksum,
 	}, {
 		name:      "parse valid mainnet address with testnet rejected",
-		addr:      "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+		addr:      "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin",
 		net:       testNetParams,
 		decodeErr: ErrUnsupportedAddress,
 	}, {
 		name:      "mainnet p2pk with no data for pubkey",
-		addr:      "Aiz5jz1s",
+		addr:      "NvjWSDcY",
 		net:       mainNetParams,
 		decodeErr: ErrUnsupportedAddress,
 	}, {
 		name:      "invalid base58 (l not in base58 alphabet)",
-		addr:      "DsUZxxoHlSty8DCfwfartwTYbuhmVct7tJu",
+		addr:      "VsUZxxoHlSty8DCfwfartwTYbuhmVct7tJu",
 		net:       mainNetParams,
 		decodeErr: ErrUnsupportedAddress,
 	}, {
@@ -194,7 +194,7 @@ func TestAddresses(t *testing.T) {
 		makeErr: ErrInvalidPubKeyFormat,
 	}, {
 		name:      "mainnet p2pk-ecdsa-secp256k1 uncompressed (0x04) rejected via decode",
-		addr:      "HiQeNVx8PNYP8ysyunUoicyNdfRUrEu1kzPE6v5gECBHBYgDzXCg8BsDGjmaHCpV97ytaQGHz5XDMJgJVHjv9YeSXWkHfwmBJj",
+		addr:      "ehJpD6Cy36yzHtPbJpHfdgGM6SdceYZZPfMvvNSKkcQ1rqTUe1EqErbW1D3BrkTn8EzrHyipzh15ikYwacZdUXZCR3rkHyrSe7",
 		net:       mainNetParams,
 		decodeErr: ErrUnsupportedAddress,
 	}, {
@@ -246,7 +246,7 @@ func TestAddresses(t *testing.T) {
 		makeErr: ErrInvalidPubKey,
 	}, {
 		name:      "p2pk-ecdsa-secp256k1 malformed pubkey via decode",
-		addr:      "3tWTcxjUnAKTzHh8pHPYpSsUKVbTvziNGHtbBFQkY12khQWuW83p",
+		addr:      "7WnJ7mXR8dcgpEf7mxDfpcq6xyFBKYmN1BvvbsCSriJhsW8jBTSZ",
 		net:       mainNetParams,
 		decodeErr: ErrUnsupportedAddress,
 	}, {
@@ -261,7 +261,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyEcdsaSecp256k1Raw(0, pk, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DkM3ZigNyiwHrsXRjkDQ8t8tW6uKGW9g61qEkG3bMqQPQWYEf5X3J",
+		addr:      "VkSwcsD8t5RJUW8X9Tbo3e6SFWwmx3ctwMXYUv1HLRanw5LBFju52",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -275,7 +275,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyEcdsaSecp256k1Raw(0, pk, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DkRM4ZcdejbYRu4AbcEdfDLzU9w1ZTqPXatXvL1g8Q77ibDjz7gwF",
+		addr:      "VkXF7i9PZ65Z3XfG1Kd2ZyJYDZyUF1JcNvaqeyyN6zHXFA1q3W1b7",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -292,7 +292,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyEcdsaSecp256k1(0, pk, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DkM3ZigNyiwHrsXRjkDQ8t8tW6uKGW9g61qEkG3bMqQPQWYEf5X3J",
+		addr:      "VkSwcsD8t5RJUW8X9Tbo3e6SFWwmx3ctwMXYUv1HLRanw5LBFju52",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -311,7 +311,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyEcdsaSecp256k1(0, pk, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DkM3EyZ546GghVSkvzb6J47PvGDyntqiDtFgipQhNj78Xm2mUYRpf",
+		addr:      "VkSwJ85pxSkhK83rLhyVCp4wfgGSUSJw5DwzTUNPMKHY4KphbxzRQ",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -428,7 +428,7 @@ func TestAddresses(t *testing.T) {
 		makeErr: ErrInvalidPubKey,
 	}, {
 		name:      "p2pk-ed25519 malformed pubkey (only 31 bytes) via decode",
-		addr:      "3tWUQtEa3P4SDQwjER81wkTxe4kiYLgNAso3pt2X5k3NFHRVQeNv",
+		addr:      "7WnJuh2WPrMf3MuiC5x8wvRbHYQRvtjMumqPFVpDQTKKRP3vec1J",
 		net:       mainNetParams,
 		decodeErr: ErrUnsupportedAddress,
 	}, {
@@ -444,7 +444,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyEd25519Raw(0, pk, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DkM5zR8tqWNAHngZQDTyAeqzabZxMKrkSbCFULDhmvySn3uHmm221",
+		addr:      "VkSz3ZfejrrAuRHeovrN5QoYL1cR2sKyHvtZCzBPkX9rJchHkpfNW",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -462,7 +462,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyEd25519(0, pk, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DkM5zR8tqWNAHngZQDTyAeqzabZxMKrkSbCFULDhmvySn3uHmm221",
+		addr:      "VkSz3ZfejrrAuRHeovrN5QoYL1cR2sKyHvtZCzBPkX9rJchHkpfNW",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -532,7 +532,7 @@ func TestAddresses(t *testing.T) {
 		makeErr: ErrInvalidPubKeyFormat,
 	}, {
 		name:      "mainnet p2pk-schnorr-secp256k1 uncompressed (0x04) rejected via decode",
-		addr:      "HiQjU9uCJtiQD7osQuYHWJRFiBCTuqtaTw8QFMtMgAW2ny4nUENeXDiV5VxfVZrK6PZynKPDpL7bwc6XLFNpV8k7ePDJmkkVCh",
+		addr:      "ehJuJkA2xdA1N2KUowM9RMiEAxQbi9Z86c774pF1CaimUFr37iQodtSmoyEH57Vc5WawVtqkpwbUK3yARaCXp7esXvKmPFofHo",
 		net:       mainNetParams,
 		decodeErr: ErrUnsupportedAddress,
 	}, {
@@ -584,7 +584,7 @@ func TestAddresses(t *testing.T) {
 		makeErr: ErrInvalidPubKey,
 	}, {
 		name:      "p2pk-schnorr-secp256k1 malformed pubkey via decode",
-		addr:      "3tWUW3oD87XtmFVGnLX4Z3Hdesm2qRvvN8H5kq3yXxJumCxbvCpo",
+		addr:      "7WnJzrb9Uaq7bCTFk1MBZDFGJMQkDyyv72KRBSqfrfarwJanR69Z",
 		net:       mainNetParams,
 		decodeErr: ErrUnsupportedAddress,
 	}, {
@@ -593,7 +593,7 @@ func TestAddresses(t *testing.T) {
 		// ---------------------------------------------------------------------
 
 		name:      "mainnet p2pk-schnorr-secp256k1 compressed (0x02)",
-		addr:      "DkM7TD2qsne9DKo4uA2ZNt3XhejYVwT5mmQWtUXtjdPhRHXTSKxN4",
+		addr:      "VkT1WMZbn989pxQAJsQxHe15T4n1BUvJd76pd8VaiDa6wrKUA9mtj",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -601,7 +601,7 @@ func TestAddresses(t *testing.T) {
 		pubKey:    "028f53838b7639563f27c94845549a41e5146bcd52e7fef0ea6da143a02b0fe2ed",
 	}, {
 		name:      "mainnet p2pk-schnorr-secp256k1 compressed (0x03)",
-		addr:      "DkRQx3y6YoJPnMKom23nuDFdfhmEnu8oDLTp4YVyWC6RjND19UxHk",
+		addr:      "VkXK1CVrT9nQPyvuAjSBoyDBR7ohUSc24gA7oCTfUnGqFvzzLVFX3",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -618,7 +618,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeySchnorrSecp256k1(0, pk, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DkM7TD2qsne9DKo4uA2ZNt3XhejYVwT5mmQWtUXtjdPhRHXTSKxN4",
+		addr:      "VkT1WMZbn989pxQAJsQxHe15T4n1BUvJd76pd8VaiDa6wrKUA9mtj",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -637,7 +637,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeySchnorrSecp256k1(0, pk, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DkM7TD2qsne9DKo4uA2ZNt3XhejYVwT5mmQWtUXtjdPhRHXTSKxN4",
+		addr:      "VkT1WMZbn989pxQAJsQxHe15T4n1BUvJd76pd8VaiDa6wrKUA9mtj",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -704,7 +704,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyHashEcdsaSecp256k1(0, hash, mainNetParams)
 		},
 		makeErr:      nil,
-		addr:         "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+		addr:         "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin",
 		net:          mainNetParams,
 		decodeErr:    nil,
 		version:      0,
@@ -724,7 +724,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyHashEcdsaSecp256k1(0, hash, mainNetParams)
 		},
 		makeErr:      nil,
-		addr:         "DsU7xcg53nxaKLLcAUSKyRndjG78Z2VZnX9",
+		addr:         "VsMWuB5RR4y8mDr7vutQuevHtdMFnYRYHFi",
 		net:          mainNetParams,
 		decodeErr:    nil,
 		version:      0,
@@ -807,7 +807,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyHashEd25519(0, hash, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DeeUhrRoTp4DftsqddVW96yMGMW4sgQFYUE",
+		addr:      "VeXseQq9q64n7nPMQ4wb5L71RikC72b7MG8",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -820,7 +820,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyHashEd25519(0, hash, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DeZ1gU2ta8auk5et79R74GYR3pnF31KXbFo",
+		addr:      "VeSQd2SEwQbUByAPsasBzVg5DC2NGPgpavv",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -880,7 +880,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyHashSchnorrSecp256k1(0, hash, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DSXcZv4oSRiEoWL2a9aD8sgfptRo1YEXNKj",
+		addr:      "VSR1WUU9ohioFPqYLb2J56pKzFfvEscYtkj",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -892,7 +892,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressPubKeyHashSchnorrSecp256k1(0, hash, mainNetParams)
 		},
 		makeErr:   nil,
-		addr:      "DSXAZZwbBmmqzdTxnxRgDN1kxEqA4xJfufA",
+		addr:      "VSQZW8LwZ3nQSWyUZPsm9b9R7c5HJNA46KN",
 		net:       mainNetParams,
 		decodeErr: nil,
 		version:   0,
@@ -958,7 +958,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressScriptHash(0, script, mainNetParams)
 		},
 		makeErr:      nil,
-		addr:         "DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS",
+		addr:         "VcnoGWXXbiAVuzhcAWwrGQsbDQLyFWYnHpE",
 		net:          mainNetParams,
 		decodeErr:    nil,
 		version:      0,
@@ -978,7 +978,7 @@ func TestAddresses(t *testing.T) {
 			return NewAddressScriptHashFromHash(0, hash, mainNetParams)
 		},
 		makeErr:      nil,
-		addr:         "DcqgK4N4Ccucu2Sq4VDAdu4wH4LASLhzLVp",
+		addr:         "Vcj5FcmQZtvBLuxLpvfFa8CbSRaHfhYRE7s",
 		net:          mainNetParams,
 		decodeErr:    nil,
 		version:      0,
@@ -1292,17 +1292,17 @@ func TestDecodeAddressV0Corners(t *testing.T) {
 		// ---------------------------------------------------------------------
 
 		name:      "mainnet p2pk with no data for pubkey",
-		addr:      "Aiz5jz1s",
+		addr:      "NvjWSDcY",
 		net:       mainNetParams,
 		decodeErr: ErrMalformedAddressData,
 	}, {
 		name:      "invalid base58 (l not in base58 alphabet)",
-		addr:      "DsUZxxoHlSty8DCfwfartwTYbuhmVct7tJu",
+		addr:      "VsUZxxoHlSty8DCfwfartwTYbuhmVct7tJu",
 		net:       mainNetParams,
 		decodeErr: ErrMalformedAddress,
 	}, {
 		name:      "exceeds max version 0 address size",
-		addr:      "5HLcANeEU3u2pxrCZFmLoyY4bAH7sgEuiTGcionhA7tzHdecFmgg1LJk",
+		addr:      "Af5V48ND7GrV8zzukDQ2GmpqmsvY9LoHtwevdZbKX7ezfHXQVdWERuka",
 		net:       mainNetParams,
 		decodeErr: ErrMalformedAddress,
 	}, {
@@ -1311,12 +1311,12 @@ func TestDecodeAddressV0Corners(t *testing.T) {
 		// ---------------------------------------------------------------------
 
 		name:      "mainnet p2pk-ecdsa-secp256k1 uncompressed (0x04) rejected via decode",
-		addr:      "HiQeNVx8PNYP8ysyunUoicyNdfRUrEu1kzPE6v5gECBHBYgDzXCg8BsDGjmaHCpV97ytaQGHz5XDMJgJVHjv9YeSXWkHfwmBJj",
+		addr:      "ehJpD6Cy36yzHtPbJpHfdgGM6SdceYZZPfMvvNSKkcQ1rqTUe1EqErbW1D3BrkTn8EzrHyipzh15ikYwacZdUXZCR3rkHyrSe7",
 		net:       mainNetParams,
 		decodeErr: ErrMalformedAddress,
 	}, {
 		name:      "p2pk-ecdsa-secp256k1 malformed pubkey via decode",
-		addr:      "3tWTcxjUnAKTzHh8pHPYpSsUKVbTvziNGHtbBFQkY12khQWuW83p",
+		addr:      "7WnJ7mXR8dcgpEf7mxDfpcq6xyFBKYmN1BvvbsCSriJhsW8jBTSZ",
 		net:       mainNetParams,
 		decodeErr: ErrMalformedAddressData,
 	}, {
@@ -1325,7 +1325,7 @@ func TestDecodeAddressV0Corners(t *testing.T) {
 		// ---------------------------------------------------------------------
 
 		name:      "p2pk-ed25519 malformed pubkey (only 31 bytes) via decode",
-		addr:      "3tWUQtEa3P4SDQwjER81wkTxe4kiYLgNAso3pt2X5k3NFHRVQeNv",
+		addr:      "7WnJuh2WPrMf3MuiC5x8wvRbHYQRvtjMumqPFVpDQTKKRP3vec1J",
 		net:       mainNetParams,
 		decodeErr: ErrMalformedAddressData,
 	}, {
@@ -1334,12 +1334,12 @@ func TestDecodeAddressV0Corners(t *testing.T) {
 		// ---------------------------------------------------------------------
 
 		name:      "mainnet p2pk-schnorr-secp256k1 uncompressed (0x04) rejected via decode",
-		addr:      "HiQjU9uCJtiQD7osQuYHWJRFiBCTuqtaTw8QFMtMgAW2ny4nUENeXDiV5VxfVZrK6PZynKPDpL7bwc6XLFNpV8k7ePDJmkkVCh",
+		addr:      "ehJuJkA2xdA1N2KUowM9RMiEAxQbi9Z86c774pF1CaimUFr37iQodtSmoyEH57Vc5WawVtqkpwbUK3yARaCXp7esXvKmPFofHo",
 		net:       mainNetParams,
 		decodeErr: ErrMalformedAddress,
 	}, {
 		name:      "p2pk-schnorr-secp256k1 malformed pubkey via decode",
-		addr:      "3tWUW3oD87XtmFVGnLX4Z3Hdesm2qRvvN8H5kq3yXxJumCxbvCpo",
+		addr:      "7WnJzrb9Uaq7bCTFk1MBZDFGJMQkDyyv72KRBSqfrfarwJanR69Z",
 		net:       mainNetParams,
 		decodeErr: ErrMalformedAddressData,
 	}}
@@ -1372,39 +1372,39 @@ func TestProbablyV0Base58Addr(t *testing.T) {
 		want: true,
 	}, {
 		name: "invalid base58 (0 not in base58 alphabet, one less than '1')",
-		str:  "DsUZxxoH0Sty8DCfwfartwTYbuhmVct7tJu",
+		str:  "VsUZxxoH0Sty8DCfwfartwTYbuhmVct7tJu",
 		want: false,
 	}, {
 		name: "invalid base58 ({ not in base58 alphabet, one more than 'z')",
-		str:  "DsUZxxoH{Sty8DCfwfartwTYbuhmVct7tJu",
+		str:  "VsUZxxoH{Sty8DCfwfartwTYbuhmVct7tJu",
 		want: false,
 	}, {
 		name: "invalid base58 (I not in base58 alphabet)",
-		str:  "DsUZxxoHISty8DCfwfartwTYbuhmVct7tJu",
+		str:  "VsUZxxoHISty8DCfwfartwTYbuhmVct7tJu",
 		want: false,
 	}, {
 		name: "invalid base58 (O not in base58 alphabet)",
-		str:  "DsUZxxoHOSty8DCfwfartwTYbuhmVct7tJu",
+		str:  "VsUZxxoHOSty8DCfwfartwTYbuhmVct7tJu",
 		want: false,
 	}, {
 		name: "invalid base58 (l not in base58 alphabet)",
-		str:  "DsUZxxoHlSty8DCfwfartwTYbuhmVct7tJu",
+		str:  "VsUZxxoHlSty8DCfwfartwTYbuhmVct7tJu",
 		want: false,
 	}, {
 		name: "invalid base58 (: not in base58 alphabet, one more than '9')",
-		str:  "DsUZxxoH:Sty8DCfwfartwTYbuhmVct7tJu",
+		str:  "VsUZxxoH:Sty8DCfwfartwTYbuhmVct7tJu",
 		want: false,
 	}, {
 		name: "invalid base58 (@ not in base58 alphabet, one less than 'A')",
-		str:  "DsUZxxoH@Sty8DCfwfartwTYbuhmVct7tJu",
+		str:  "VsUZxxoH@Sty8DCfwfartwTYbuhmVct7tJu",
 		want: false,
 	}, {
 		name: "invalid base58 ([ not in base58 alphabet, one more than 'Z')",
-		str:  "DsUZxxoH[Sty8DCfwfartwTYbuhmVct7tJu",
+		str:  "VsUZxxoH[Sty8DCfwfartwTYbuhmVct7tJu",
 		want: false,
 	}, {
 		name: "invalid base58 (` not in base58 alphabet, one less than 'a')",
-		str:  "DsUZxxoH`Sty8DCfwfartwTYbuhmVct7tJu",
+		str:  "VsUZxxoH`Sty8DCfwfartwTYbuhmVct7tJu",
 		want: false,
 	}}
 
@@ -21,31 +21,31 @@ func BenchmarkDecode(b *testing.B) {
 		params AddressParams
 	}{{
 		name:   "v0 p2sh",
-		addr:   "DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS",
+		addr:   "VcnoGWXXbiAVuzhcAWwrGQsbDQLyFWYnHpE",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pkh-ecdsa-secp256k1",
-		addr:   "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+		addr:   "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pkh-ed25519",
-		addr:   "DeeUhrRoTp4DftsqddVW96yMGMW4sgQFYUE",
+		addr:   "VeXseQq9q64n7nPMQ4wb5L71RikC72b7MG8",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pkh-schnorr-secp256k1",
-		addr:   "DSXcZv4oSRiEoWL2a9aD8sgfptRo1YEXNKj",
+		addr:   "VSR1WUU9ohioFPqYLb2J56pKzFfvEscYtkj",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pk-ecdsa-secp256k1",
-		addr:   "DkM3ZigNyiwHrsXRjkDQ8t8tW6uKGW9g61qEkG3bMqQPQWYEf5X3J",
+		addr:   "VkSwcsD8t5RJUW8X9Tbo3e6SFWwmx3ctwMXYUv1HLRanw5LBFju52",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pk-ed25519",
-		addr:   "DkM5zR8tqWNAHngZQDTyAeqzabZxMKrkSbCFULDhmvySn3uHmm221",
+		addr:   "VkSz3ZfejrrAuRHeovrN5QoYL1cR2sKyHvtZCzBPkX9rJchHkpfNW",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pk-schnorr-secp256k1",
-		addr:   "DkM7TD2qsne9DKo4uA2ZNt3XhejYVwT5mmQWtUXtjdPhRHXTSKxN4",
+		addr:   "VkT1WMZbn989pxQAJsQxHe15T4n1BUvJd76pd8VaiDa6wrKUA9mtj",
 		params: mainNetParams,
 	}}
 
@@ -74,31 +74,31 @@ func BenchmarkPaymentScript(b *testing.B) {
 		params AddressParams
 	}{{
 		name:   "v0 p2sh",
-		addr:   "DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS",
+		addr:   "VcnoGWXXbiAVuzhcAWwrGQsbDQLyFWYnHpE",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pkh-ecdsa-secp256k1",
-		addr:   "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+		addr:   "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pkh-ed25519",
-		addr:   "DeeUhrRoTp4DftsqddVW96yMGMW4sgQFYUE",
+		addr:   "VeXseQq9q64n7nPMQ4wb5L71RikC72b7MG8",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pkh-schnorr-secp256k1",
-		addr:   "DSXcZv4oSRiEoWL2a9aD8sgfptRo1YEXNKj",
+		addr:   "VSR1WUU9ohioFPqYLb2J56pKzFfvEscYtkj",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pk-ecdsa-secp256k1",
-		addr:   "DkM3ZigNyiwHrsXRjkDQ8t8tW6uKGW9g61qEkG3bMqQPQWYEf5X3J",
+		addr:   "VkSwcsD8t5RJUW8X9Tbo3e6SFWwmx3ctwMXYUv1HLRanw5LBFju52",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pk-ed25519",
-		addr:   "DkM5zR8tqWNAHngZQDTyAeqzabZxMKrkSbCFULDhmvySn3uHmm221",
+		addr:   "VkSz3ZfejrrAuRHeovrN5QoYL1cR2sKyHvtZCzBPkX9rJchHkpfNW",
 		params: mainNetParams,
 	}, {
 		name:   "v0 p2pk-schnorr-secp256k1",
-		addr:   "DkM7TD2qsne9DKo4uA2ZNt3XhejYVwT5mmQWtUXtjdPhRHXTSKxN4",
+		addr:   "VkT1WMZbn989pxQAJsQxHe15T4n1BUvJd76pd8VaiDa6wrKUA9mtj",
 		params: mainNetParams,
 	}}
 
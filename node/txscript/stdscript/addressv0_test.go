@@ -65,12 +65,12 @@ func (p *mockAddrParams) AddrIDScriptHashV0() [2]byte {
 // was written.
 func mockMainNetParams() *mockAddrParams {
 	return &mockAddrParams{
-		pubKeyID:     [2]byte{0x13, 0x86}, // starts with Dk
-		pkhEcdsaID:   [2]byte{0x07, 0x3f}, // starts with Ds
-		pkhEd25519ID: [2]byte{0x07, 0x1f}, // starts with De
-		pkhSchnorrID: [2]byte{0x07, 0x01}, // starts with DS
-		scriptHashID: [2]byte{0x07, 0x1a}, // starts with Dc
-		privKeyID:    [2]byte{0x22, 0xde}, // starts with Pm
+		pubKeyID:     [2]byte{0x2c, 0x08}, // starts with Vk
+		pkhEcdsaID:   [2]byte{0x10, 0x41}, // starts with Vs
+		pkhEd25519ID: [2]byte{0x10, 0x21}, // starts with Ve
+		pkhSchnorrID: [2]byte{0x10, 0x03}, // starts with VS
+		scriptHashID: [2]byte{0x10, 0x1c}, // starts with Vc
+		privKeyID:    [2]byte{0x23, 0x1b}, // starts with Pv
 	}
 }
 
@@ -180,19 +180,19 @@ var addressV0Tests = func() []addressTest {
 		script:    p("DATA_65 0x%s CHECKSIG", pkUE),
 		params:    mainNetParams,
 		wantType:  STPubKeyEcdsaSecp256k1,
-		wantAddrs: []string{"DkM3QDPFSVxAsDpbP9e3fMCDFThsBbAtRsjkwcAeAfsfNKMJYwhz9"},
+		wantAddrs: []string{"VkSwTMv1LrSBUrRgns2Sa79kzskKs8e7HDS4gG8L9G44tt9EG9vQs"},
 	}, {
 		name:      "mainnet v0 p2pk-ecdsa-secp256k1 compressed even",
 		script:    p("DATA_33 0x%s CHECKSIG", pkCE),
 		params:    mainNetParams,
 		wantType:  STPubKeyEcdsaSecp256k1,
-		wantAddrs: []string{"DkM3QDPFSVxAsDpbP9e3fMCDFThsBbAtRsjkwcAeAfsfNKMJYwhz9"},
+		wantAddrs: []string{"VkSwTMv1LrSBUrRgns2Sa79kzskKs8e7HDS4gG8L9G44tt9EG9vQs"},
 	}, {
 		name:      "mainnet v0 p2pk-ecdsa-secp256k1 compressed odd",
 		script:    p("DATA_33 0x%s CHECKSIG", pkCO),
 		params:    mainNetParams,
 		wantType:  STPubKeyEcdsaSecp256k1,
-		wantAddrs: []string{"DkRMEciAhaj4W5XHTPoEEUCiXFw3SQjKayHGdJBtAEjNArqzGujEz"},
+		wantAddrs: []string{"VkXFHmEvbwD57i8Ns7Bd9EAGGfyW7xCYSJyaMx9a8pumhRdzqG866"},
 	}, {
 		name:      "testnet v0 p2pk-ecdsa-secp256k1 uncompressed",
 		script:    p("DATA_65 0x%s CHECKSIG", pkUE),
@@ -263,7 +263,7 @@ var addressV0Tests = func() []addressTest {
 		script:    p("DATA_32 0x%s 1 CHECKSIGALT", pkEd),
 		params:    mainNetParams,
 		wantType:  STPubKeyEd25519,
-		wantAddrs: []string{"DkM5zR8tqWNAHngZQDTyAeqzabZxMKrkSbCFULDhmvySn3uHmm221"},
+		wantAddrs: []string{"VkSz3ZfejrrAuRHeovrN5QoYL1cR2sKyHvtZCzBPkX9rJchHkpfNW"},
 	}, {
 		name:      "testnet v0 p2pk-ed25519",
 		script:    p("DATA_32 0x%s 1 CHECKSIGALT", pkEd),
@@ -313,13 +313,13 @@ var addressV0Tests = func() []addressTest {
 		script:    p("DATA_33 0x%s 2 CHECKSIGALT", pkCE),
 		params:    mainNetParams,
 		wantType:  STPubKeySchnorrSecp256k1,
-		wantAddrs: []string{"DkM7HhjiLZf2Dg6EYZTCuM6rT1Y6R2UJ7dK35pewYTryP6LYfaAB7"},
+		wantAddrs: []string{"VkT1LrGUEv92qJhKxGqbp74QCRaZ6ZwWxy1LpUcdX43Nuf8VW7UHA"},
 	}, {
 		name:      "mainnet v0 p2pk-schnorr-secp256k1 compressed odd",
 		script:    p("DATA_33 0x%s 2 CHECKSIGALT", pkCO),
 		params:    mainNetParams,
 		wantType:  STPubKeySchnorrSecp256k1,
-		wantAddrs: []string{"DkRR874dbeRurXnvcocPUU7MiomGfr2jGirYmWgBY2igBdqAjz4Rs"},
+		wantAddrs: []string{"VkXKBFbPVzuvUAQ22WznPE4uUDojMPVx84YrWAdsWcu5iCdEk4oBh"},
 	}, {
 		name:      "testnet v0 p2pk-schnorr-secp256k1 compressed even",
 		script:    p("DATA_33 0x%s 2 CHECKSIGALT", pkCE),
@@ -350,7 +350,7 @@ var addressV0Tests = func() []addressTest {
 		script:    p("DUP HASH160 DATA_20 0x%s EQUALVERIFY CHECKSIG", h160CE),
 		params:    mainNetParams,
 		wantType:  STPubKeyHashEcdsaSecp256k1,
-		wantAddrs: []string{"DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"},
+		wantAddrs: []string{"Vsf1V3zjNen97unZvsTCQh4Rfu1PsYshD3E"},
 	}, {
 		name:      "testnet v0 p2pkh-ecdsa-secp256k1",
 		script:    p("DUP HASH160 DATA_20 0x%s EQUALVERIFY CHECKSIG", h160CE),
@@ -405,7 +405,7 @@ var addressV0Tests = func() []addressTest {
 			h160Ed),
 		params:    mainNetParams,
 		wantType:  STPubKeyHashEd25519,
-		wantAddrs: []string{"DeeUhrRoTp4DftsqddVW96yMGMW4sgQFYUE"},
+		wantAddrs: []string{"VeXseQq9q64n7nPMQ4wb5L71RikC72b7MG8"},
 	}, {
 		name: "testnet v0 p2pkh-ed25519",
 		script: p("DUP HASH160 DATA_20 0x%s EQUALVERIFY 1 CHECKSIGALT",
@@ -433,14 +433,14 @@ var addressV0Tests = func() []addressTest {
 			h160CE),
 		params:    mainNetParams,
 		wantType:  STPubKeyHashSchnorrSecp256k1,
-		wantAddrs: []string{"DSpf9Sru9MarMKQQnuzTiQ9tjWVJA3KSm2d"},
+		wantAddrs: []string{"VSi461GFWdbQoCuvZMSYedHYtsjRPVG8J9U"},
 	}, {
 		name: "mainnetv0 p2pkh-schnorr-secp256k1 2",
 		script: p("DUP HASH160 DATA_20 0x%s EQUALVERIFY 2 CHECKSIGALT",
 			h160CE2),
 		params:    mainNetParams,
 		wantType:  STPubKeyHashSchnorrSecp256k1,
-		wantAddrs: []string{"DSU8ZWCPmHeSBPmaMQvErRMJ6g3YiuHUKqa"},
+		wantAddrs: []string{"VSMXW4bk8ZezdHH67rNKneUxG3HfxLVoWw4"},
 	}, {
 		name: "testnet v0 p2pkh-schnorr-secp256k1",
 		script: p("DUP HASH160 DATA_20 0x%s EQUALVERIFY 2 CHECKSIGALT",
@@ -478,7 +478,7 @@ var addressV0Tests = func() []addressTest {
 		script:    p("HASH160 DATA_20 0x%s EQUAL", p2sh),
 		params:    mainNetParams,
 		wantType:  STScriptHash,
-		wantAddrs: []string{"Dcv77F33B5PvGxAT8FynsydN7V2eXy6Sw7u"},
+		wantAddrs: []string{"VcoW3oSPYMQUiqfxthRspCm2GrGmmJuqi99"},
 	}, {
 		name:      "testnet v0 p2sh",
 		script:    p("HASH160 DATA_20 0x%s EQUAL", p2sh),
@@ -573,15 +573,15 @@ var addressV0Tests = func() []addressTest {
 		script:    p("1 DATA_33 0x%s 1 CHECKMULTISIG", pkCE),
 		params:    mainNetParams,
 		wantType:  STMultiSig,
-		wantAddrs: []string{"DkM3QDPFSVxAsDpbP9e3fMCDFThsBbAtRsjkwcAeAfsfNKMJYwhz9"},
+		wantAddrs: []string{"VkSwTMv1LrSBUrRgns2Sa79kzskKs8e7HDS4gG8L9G44tt9EG9vQs"},
 	}, {
 		name:     "mainnet v0 multisig 1-of-2 compressed pubkeys",
 		script:   p("1 DATA_33 0x%s DATA_33 0x%s 2 CHECKMULTISIG", pkCE, pkCE2),
 		params:   mainNetParams,
 		wantType: STMultiSig,
 		wantAddrs: []string{
-			"DkM3QDPFSVxAsDpbP9e3fMCDFThsBbAtRsjkwcAeAfsfNKMJYwhz9",
-			"DkM4NLpP4HKMcYqWa6YbhD2G96kbFyZe5JnGxrcC9hLF9w9AC5QBJ",
+			"VkSwTMv1LrSBUrRgns2Sa79kzskKs8e7HDS4gG8L9G44tt9EG9vQs",
+			"VkSxRVM8xdoNEBSbyovzbxyotWo3wX2rveUahWZt8HWegVw8ZxGAp",
 		},
 	}, {
 		name: "mainnet v0 multisig 2-of-3 compressed pubkeys",
@@ -590,9 +590,9 @@ var addressV0Tests = func() []addressTest {
 		params:   mainNetParams,
 		wantType: STMultiSig,
 		wantAddrs: []string{
-			"DkM3QDPFSVxAsDpbP9e3fMCDFThsBbAtRsjkwcAeAfsfNKMJYwhz9",
-			"DkM4NLpP4HKMcYqWa6YbhD2G96kbFyZe5JnGxrcC9hLF9w9AC5QBJ",
-			"DkRMEciAhaj4W5XHTPoEEUCiXFw3SQjKayHGdJBtAEjNArqzGujEz",
+			"VkSwTMv1LrSBUrRgns2Sa79kzskKs8e7HDS4gG8L9G44tt9EG9vQs",
+			"VkSxRVM8xdoNEBSbyovzbxyotWo3wX2rveUahWZt8HWegVw8ZxGAp",
+			"VkXFHmEvbwD57i8Ns7Bd9EAGGfyW7xCYSJyaMx9a8pumhRdzqG866",
 		},
 	}, {
 		name:      "testnet v0 multisig 1-of-1 compressed pubkey",
@@ -726,7 +726,7 @@ var addressV0Tests = func() []addressTest {
 		script:    p("SSTX DUP HASH160 DATA_20 0x%s EQUALVERIFY CHECKSIG", h160CE),
 		params:    mainNetParams,
 		wantType:  STStakeSubmissionPubKeyHash,
-		wantAddrs: []string{"DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"},
+		wantAddrs: []string{"Vsf1V3zjNen97unZvsTCQh4Rfu1PsYshD3E"},
 	}, {
 		name:      "testnet v0 stake submission p2pkh-ecdsa-secp256k1",
 		script:    p("SSTX DUP HASH160 DATA_20 0x%s EQUALVERIFY CHECKSIG", h160CE),
@@ -751,7 +751,7 @@ var addressV0Tests = func() []addressTest {
 		script:    p("SSTX HASH160 DATA_20 0x%s EQUAL", p2sh),
 		params:    mainNetParams,
 		wantType:  STStakeSubmissionScriptHash,
-		wantAddrs: []string{"Dcv77F33B5PvGxAT8FynsydN7V2eXy6Sw7u"},
+		wantAddrs: []string{"VcoW3oSPYMQUiqfxthRspCm2GrGmmJuqi99"},
 	}, {
 		name:      "testnet v0 stake submission p2sh",
 		script:    p("SSTX HASH160 DATA_20 0x%s EQUAL", p2sh),
@@ -778,7 +778,7 @@ var addressV0Tests = func() []addressTest {
 			h160CE),
 		params:    mainNetParams,
 		wantType:  STStakeGenPubKeyHash,
-		wantAddrs: []string{"DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"},
+		wantAddrs: []string{"Vsf1V3zjNen97unZvsTCQh4Rfu1PsYshD3E"},
 	}, {
 		name: "testnet v0 stake gen p2pkh-ecdsa-secp256k1",
 		script: p("SSGEN DUP HASH160 DATA_20 0x%s EQUALVERIFY CHECKSIG",
@@ -804,7 +804,7 @@ var addressV0Tests = func() []addressTest {
 		script:    p("SSGEN HASH160 DATA_20 0x%s EQUAL", p2sh),
 		params:    mainNetParams,
 		wantType:  STStakeGenScriptHash,
-		wantAddrs: []string{"Dcv77F33B5PvGxAT8FynsydN7V2eXy6Sw7u"},
+		wantAddrs: []string{"VcoW3oSPYMQUiqfxthRspCm2GrGmmJuqi99"},
 	}, {
 		name:      "testnet v0 stake gen p2sh",
 		script:    p("SSGEN HASH160 DATA_20 0x%s EQUAL", p2sh),
@@ -831,7 +831,7 @@ var addressV0Tests = func() []addressTest {
 			h160CE),
 		params:    mainNetParams,
 		wantType:  STStakeRevocationPubKeyHash,
-		wantAddrs: []string{"DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"},
+		wantAddrs: []string{"Vsf1V3zjNen97unZvsTCQh4Rfu1PsYshD3E"},
 	}, {
 		name: "testnet v0 stake revoke p2pkh-ecdsa-secp256k1",
 		script: p("SSRTX DUP HASH160 DATA_20 0x%s EQUALVERIFY CHECKSIG",
@@ -857,7 +857,7 @@ var addressV0Tests = func() []addressTest {
 		script:    p("SSRTX HASH160 DATA_20 0x%s EQUAL", p2sh),
 		params:    mainNetParams,
 		wantType:  STStakeRevocationScriptHash,
-		wantAddrs: []string{"Dcv77F33B5PvGxAT8FynsydN7V2eXy6Sw7u"},
+		wantAddrs: []string{"VcoW3oSPYMQUiqfxthRspCm2GrGmmJuqi99"},
 	}, {
 		name:      "testnet v0 stake revoke p2sh",
 		script:    p("SSRTX HASH160 DATA_20 0x%s EQUAL", p2sh),
@@ -884,7 +884,7 @@ var addressV0Tests = func() []addressTest {
 			h160CE),
 		params:    mainNetParams,
 		wantType:  STStakeChangePubKeyHash,
-		wantAddrs: []string{"DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"},
+		wantAddrs: []string{"Vsf1V3zjNen97unZvsTCQh4Rfu1PsYshD3E"},
 	}, {
 		name: "testnet v0 stake change p2pkh-ecdsa-secp256k1",
 		script: p("SSTXCHANGE DUP HASH160 DATA_20 0x%s EQUALVERIFY CHECKSIG",
@@ -910,7 +910,7 @@ var addressV0Tests = func() []addressTest {
 		script:    p("SSTXCHANGE HASH160 DATA_20 0x%s EQUAL", p2sh),
 		params:    mainNetParams,
 		wantType:  STStakeChangeScriptHash,
-		wantAddrs: []string{"Dcv77F33B5PvGxAT8FynsydN7V2eXy6Sw7u"},
+		wantAddrs: []string{"VcoW3oSPYMQUiqfxthRspCm2GrGmmJuqi99"},
 	}, {
 		name:      "testnet v0 stake change p2sh",
 		script:    p("SSTXCHANGE HASH160 DATA_20 0x%s EQUAL", p2sh),
@@ -965,7 +965,7 @@ var addressV0Tests = func() []addressTest {
 			h160CE),
 		params:    mainNetParams,
 		wantType:  STTreasuryGenPubKeyHash,
-		wantAddrs: []string{"DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"},
+		wantAddrs: []string{"Vsf1V3zjNen97unZvsTCQh4Rfu1PsYshD3E"},
 	}, {
 		name: "testnet v0 treasury generation p2pkh-ecdsa-secp256k1",
 		script: p("TGEN DUP HASH160 DATA_20 0x%s EQUALVERIFY CHECKSIG",
@@ -991,7 +991,7 @@ var addressV0Tests = func() []addressTest {
 		script:    p("TGEN HASH160 DATA_20 0x%s EQUAL", p2sh),
 		params:    mainNetParams,
 		wantType:  STTreasuryGenScriptHash,
-		wantAddrs: []string{"Dcv77F33B5PvGxAT8FynsydN7V2eXy6Sw7u"},
+		wantAddrs: []string{"VcoW3oSPYMQUiqfxthRspCm2GrGmmJuqi99"},
 	}, {
 		name:      "testnet v0 treasury generation p2sh",
 		script:    p("TGEN HASH160 DATA_20 0x%s EQUAL", p2sh),
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"io"
+	"math/big"
 	"time"
 
 	"github.com/kdsmith18542/vigil-Labs/vgl/node/chaincfg/chainhash"
@@ -127,46 +128,127 @@ func (h *BlockHeader) PowHashV2() chainhash.Hash {
 	return blake3.Sum256(buf.Bytes())
 }
 
-// PowHashKawPow calculates and returns the KawPoW proof of work hash for the
-// block header. It returns the mix hash and the final hash.
-func (h *BlockHeader) PowHashKawPow() (chainhash.Hash, chainhash.Hash, error) {
-	// Encode the header for hashing (excluding MixHash and FinalHash)
-	buf := bytes.NewBuffer(make([]byte, 0, MaxBlockHeaderPayload-64)) // Subtract 64 bytes for MixHash and FinalHash
-	err := writeElements(buf, h.Version, &h.PrevBlock, &h.MerkleRoot,
+// KawPowHeaderHash returns the header hash that is used as the input to the
+// KawPoW proof of work hash.  It commits to every header field except the
+// nonce, which is a separate input to KawPoW, and the KawPoW solution fields.
+func (h *BlockHeader) KawPowHeaderHash() chainhash.Hash {
+	// Encode the header and hash everything prior to the nonce along with the
+	// fields following the KawPoW solution.  Ignore the error returns since
+	// there is no way the encode could fail except being out of memory which
+	// would cause a run-time panic.
+	buf := bytes.NewBuffer(make([]byte, 0, MaxBlockHeaderPayload))
+	_ = writeElements(buf, h.Version, &h.PrevBlock, &h.MerkleRoot,
 		&h.StakeRoot, h.VoteBits, h.FinalState, h.Voters, h.FreshStake,
 		h.Revocations, h.PoolSize, h.Bits, h.SBits, h.Height, h.Size,
-		uint32(h.Timestamp.Unix()), h.Nonce, &h.ExtraData, h.StakeVersion)
-	if err != nil {
-		return chainhash.Hash{}, chainhash.Hash{}, err
+		uint32(h.Timestamp.Unix()), &h.ExtraData, h.StakeVersion)
+
+	return chainhash.HashH(buf.Bytes())
+}
+
+// KawPowToChainHash converts a KawPoW hash to a chainhash.Hash.  KawPoW hashes
+// are treated as big-endian numbers when compared against targets while
+// chainhash.Hash is little endian, so the bytes are reversed.
+func KawPowToChainHash(h *kawpow.Hash) chainhash.Hash {
+	var result chainhash.Hash
+	for i := 0; i < kawpow.HashSize; i++ {
+		result[i] = h[kawpow.HashSize-1-i]
 	}
+	return result
+}
 
-	// Calculate the epoch from block height
-	epoch := uint64(h.Height) / 30000 // KawPoW epoch length is 30000 blocks
+// kawPowMixHash returns the mix hash of the block header in the byte order of
+// the KawPoW output.
+func (h *BlockHeader) kawPowMixHash() kawpow.Hash {
+	var mixHash kawpow.Hash
+	for i := 0; i < kawpow.HashSize; i++ {
+		mixHash[i] = h.MixHash[chainhash.HashSize-1-i]
+	}
+	return mixHash
+}
 
-	// Get or generate the DAG for this epoch
-	dag := kawpow.NewDAG(epoch)
-	err = dag.Generate()
+// kawPowCache returns the light cache for the epoch of the block height from
+// the process-wide KawPoW epoch manager.  An error of type kawpow.Error is
+// returned without requesting the light cache when the epoch is not supported.
+func (h *BlockHeader) kawPowCache() (*kawpow.Cache, error) {
+	height := uint64(h.Height)
+	if err := kawpow.CheckEpoch(kawpow.EpochForHeight(height)); err != nil {
+		return nil, err
+	}
+	return kawpow.DefaultEpochManager().CacheForHeight(height), nil
+}
+
+// PowHashKawPow calculates and returns the KawPoW proof of work hash for the
+// block header.  It returns the mix hash and the final hash.
+//
+// The hash is calculated with the light cache for the epoch of the block
+// height from the process-wide KawPoW epoch manager, so the full dataset is
+// never generated.  Note that the light cache is generated when it is not
+// already resident, so callers must ensure the height is not arbitrarily far
+// ahead of the chain.
+//
+// The returned hashes are byte reversed with respect to the KawPoW output so
+// that the final hash may be compared against the target difficulty with the
+// same semantics as the other proof of work hashes and both hashes have the
+// same string representation that stock KawPoW miners use.
+func (h *BlockHeader) PowHashKawPow() (chainhash.Hash, chainhash.Hash, error) {
+	cache, err := h.kawPowCache()
 	if err != nil {
 		return chainhash.Hash{}, chainhash.Hash{}, err
 	}
-
-	// Create header hash from the encoded data
-	headerHash := kawpow.Keccak256(buf.Bytes())
-	var headerHashFixed kawpow.Hash
-	copy(headerHashFixed[:], headerHash)
-
-	// Calculate KawPoW hash
-	finalHashBytes, mixHashBytes, err := kawpow.KawPowHash(headerHashFixed, h.Nonce, int64(h.Height), dag)
+	defer cache.Release()
+	headerHash := kawpow.Hash(h.KawPowHeaderHash())
+	mixHash, finalHash, err := kawpow.LightHash(cache, headerHash, h.Nonce,
+		uint64(h.Height))
 	if err != nil {
 		return chainhash.Hash{}, chainhash.Hash{}, err
 	}
 
-	// Convert byte slices to chainhash.Hash
-	var mixHash, finalHash chainhash.Hash
-	copy(mixHash[:], mixHashBytes)
-	copy(finalHash[:], finalHashBytes)
+	return KawPowToChainHash(&mixHash), KawPowToChainHash(&finalHash), nil
+}
+
+// VerifyKawPowTarget ensures the final hash calculated from the nonce and the
+// claimed mix hash of the block header is less than or equal to the provided
+// target difficulty.  This is cheap and does not require a light cache, so it
+// may be used to reject headers before their height has been validated.
+//
+// Note that passing this check does not mean the solution is valid since the
+// mix hash has not been verified.  See VerifyKawPow for the complete
+// verification.
+//
+// The returned error is of type kawpow.Error when the solution does not
+// satisfy the target.
+func (h *BlockHeader) VerifyKawPowTarget(target *big.Int) error {
+	headerHash := kawpow.Hash(h.KawPowHeaderHash())
+	return kawpow.VerifyTarget(headerHash, h.Nonce, h.kawPowMixHash(), target)
+}
+
+// VerifyKawPow verifies the KawPoW solution of the block header, which consists
+// of the nonce and the mix hash, against the provided target difficulty.  The
+// solution is verified with the light cache for the epoch of the block height
+// from the process-wide KawPoW epoch manager, so the full dataset is never
+// generated.
+//
+// The cheap target check of VerifyKawPowTarget and the check that the epoch is
+// supported are performed before the light cache is requested.  However, the
+// light cache is generated when it is not already resident, so callers must
+// ensure the height is not arbitrarily far ahead of the chain.
+//
+// The returned error is of type kawpow.Error when the solution is invalid.
+func (h *BlockHeader) VerifyKawPow(target *big.Int) error {
+	headerHash := kawpow.Hash(h.KawPowHeaderHash())
+	mixHash := h.kawPowMixHash()
+	err := kawpow.VerifyTarget(headerHash, h.Nonce, mixHash, target)
+	if err != nil {
+		return err
+	}
 
-	return mixHash, finalHash, nil
+	cache, err := h.kawPowCache()
+	if err != nil {
+		return err
+	}
+	defer cache.Release()
+	return kawpow.VerifyLight(cache, headerHash, h.Nonce, uint64(h.Height),
+		mixHash, target)
 }
 
 // BtcDecode decodes r using the Vigil protocol encoding into the receiver.
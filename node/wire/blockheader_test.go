@@ -8,11 +8,15 @@ package wire
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
+	"math"
+	"math/big"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/kdsmith18542/vigil-Labs/vgl/node/kawpow"
 	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
 )
 
@@ -389,3 +393,54 @@ func TestBlockHeaderHashing(t *testing.T) {
 			hash2)
 	}
 }
+
+// TestVerifyKawPowNoCache ensures KawPoW solutions that do not satisfy the
+// target and solutions for heights in unsupported epochs are rejected without
+// generating a light cache.
+func TestVerifyKawPowNoCache(t *testing.T) {
+	maxTarget := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256),
+		big.NewInt(1))
+
+	tests := []struct {
+		name   string   // test description
+		height uint32   // block height of the header
+		target *big.Int // target difficulty
+		err    error    // expected error
+	}{{
+		name:   "final hash above target",
+		height: kawpow.EpochLength,
+		target: big.NewInt(1),
+		err:    kawpow.ErrHighHash,
+	}, {
+		name:   "height in first unsupported epoch",
+		height: (kawpow.MaxEpoch + 1) * kawpow.EpochLength,
+		target: maxTarget,
+		err:    kawpow.ErrEpochTooHigh,
+	}, {
+		name:   "max height",
+		height: math.MaxUint32,
+		target: maxTarget,
+		err:    kawpow.ErrEpochTooHigh,
+	}}
+
+	manager := kawpow.DefaultEpochManager()
+	for _, test := range tests {
+		header := BlockHeader{Height: test.height, Nonce: 1}
+		generations := manager.Stats().CacheGenerations
+		err := header.VerifyKawPow(test.target)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.err)
+		}
+		if test.target == maxTarget {
+			_, _, err := header.PowHashKawPow()
+			if !errors.Is(err, test.err) {
+				t.Errorf("%q: unexpected hash error -- got %v, want %v",
+					test.name, err, test.err)
+			}
+		}
+		if got := manager.Stats().CacheGenerations; got != generations {
+			t.Errorf("%q: light cache was generated", test.name)
+		}
+	}
+}
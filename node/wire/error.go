@@ -153,6 +153,14 @@ const (
 	// ErrTooManyCFilters is returned when the number of committed filters
 	// exceeds the maximum allowed in a batch.
 	ErrTooManyCFilters
+
+	// ErrUnknownNetAddrType is returned when a network address is of a type
+	// that is not known to the protocol.
+	ErrUnknownNetAddrType
+
+	// ErrInvalidNetAddrLen is returned when the encoded address of a network
+	// address does not have the length required by its address type.
+	ErrInvalidNetAddrLen
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
@@ -193,6 +201,8 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrTooManyMixPairReqUTXOs:        "ErrTooManyMixPairReqUTXOs",
 	ErrTooManyPrevMixMsgs:            "ErrTooManyPrevMixMsgs",
 	ErrTooManyCFilters:               "ErrTooManyCFilters",
+	ErrUnknownNetAddrType:            "ErrUnknownNetAddrType",
+	ErrInvalidNetAddrLen:             "ErrInvalidNetAddrLen",
 }
 
 // String returns the ErrorCode as a human-readable name.
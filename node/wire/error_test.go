@@ -55,6 +55,8 @@ func TestMessageErrorCodeStringer(t *testing.T) {
 		{ErrTooManyMixPairReqUTXOs, "ErrTooManyMixPairReqUTXOs"},
 		{ErrTooManyPrevMixMsgs, "ErrTooManyPrevMixMsgs"},
 		{ErrTooManyCFilters, "ErrTooManyCFilters"},
+		{ErrUnknownNetAddrType, "ErrUnknownNetAddrType"},
+		{ErrInvalidNetAddrLen, "ErrInvalidNetAddrLen"},
 
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
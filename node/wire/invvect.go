@@ -31,6 +31,11 @@ const (
 	InvTypeBlock         InvType = 2
 	InvTypeFilteredBlock InvType = 3
 	InvTypeMix           InvType = 4
+
+	// InvTypeCompactBlock is only used in getdata messages to request a
+	// block be sent as a cmpctblock message.  It was not added until
+	// protocol version CompactBlocksVersion.
+	InvTypeCompactBlock InvType = 5
 )
 
 // Map of service flags back to their constant names for pretty printing.
@@ -40,6 +45,7 @@ var ivStrings = map[InvType]string{
 	InvTypeBlock:         "MSG_BLOCK",
 	InvTypeFilteredBlock: "MSG_FILTERED_BLOCK",
 	InvTypeMix:           "MSG_MIX",
+	InvTypeCompactBlock:  "MSG_CMPCT_BLOCK",
 }
 
 // String returns the InvType in human-readable form.
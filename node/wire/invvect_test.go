@@ -24,6 +24,7 @@ func TestInvTypeStringer(t *testing.T) {
 		{InvTypeTx, "MSG_TX"},
 		{InvTypeBlock, "MSG_BLOCK"},
 		{InvTypeMix, "MSG_MIX"},
+		{InvTypeCompactBlock, "MSG_CMPCT_BLOCK"},
 		{0xffffffff, "Unknown InvType (4294967295)"},
 	}
 
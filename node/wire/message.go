@@ -62,6 +62,11 @@ const (
 	CmdMixSecrets      = "mixsecrets"
 	CmdGetCFiltersV2   = "getcfsv2"
 	CmdCFiltersV2      = "cfiltersv2"
+	CmdGetAddrV2       = "getaddrv2"
+	CmdAddrV2          = "addrv2"
+	CmdCmpctBlock      = "cmpctblock"
+	CmdGetBlockTxn     = "getblocktxn"
+	CmdBlockTxn        = "blocktxn"
 )
 
 const (
@@ -225,6 +230,21 @@ func makeEmptyMessage(command string) (Message, error) {
 	case CmdCFiltersV2:
 		msg = &MsgCFiltersV2{}
 
+	case CmdGetAddrV2:
+		msg = &MsgGetAddrV2{}
+
+	case CmdAddrV2:
+		msg = &MsgAddrV2{}
+
+	case CmdCmpctBlock:
+		msg = &MsgCmpctBlock{}
+
+	case CmdGetBlockTxn:
+		msg = &MsgGetBlockTxn{}
+
+	case CmdBlockTxn:
+		msg = &MsgBlockTxn{}
+
 	default:
 		str := fmt.Sprintf("unhandled command [%s]", command)
 		return nil, messageError(op, ErrUnknownCmd, str)
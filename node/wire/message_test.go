@@ -90,6 +90,8 @@ func TestMessage(t *testing.T) {
 	msgMixDC := NewMsgMixDCNet([33]byte{}, [32]byte{}, 1, []MixVect{make(MixVect, 1)}, []chainhash.Hash{})
 	msgMixCM := NewMsgMixConfirm([33]byte{}, [32]byte{}, 1, NewMsgTx(), []chainhash.Hash{})
 	msgMixRS := NewMsgMixSecrets([33]byte{}, [32]byte{}, 1, [32]byte{}, [][]byte{}, MixVect{})
+	msgGetAddrV2 := NewMsgGetAddrV2()
+	msgAddrV2 := NewMsgAddrV2()
 
 	tests := []struct {
 		in     Message     // Value to encode
@@ -129,6 +131,8 @@ func TestMessage(t *testing.T) {
 		{msgMixDC, msgMixDC, pver, MainNet, 181},
 		{msgMixCM, msgMixCM, pver, MainNet, 173},
 		{msgMixRS, msgMixRS, pver, MainNet, 192},
+		{msgGetAddrV2, msgGetAddrV2, pver, MainNet, 24},
+		{msgAddrV2, msgAddrV2, pver, MainNet, 25},
 	}
 
 	t.Logf("Running %d tests", len(tests))
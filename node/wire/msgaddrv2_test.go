@@ -0,0 +1,328 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// baseNetAddressesV2 returns an IPv4 and a Tor v3 NetAddressV2 populated with
+// mock values that are used throughout tests.  Note that the tests will need
+// to be updated if these values are changed since they rely on the current
+// values.
+func baseNetAddressesV2() (*NetAddressV2, *NetAddressV2) {
+	ipv4 := &NetAddressV2{
+		Timestamp:   time.Unix(0x495fab29, 0), // 2009-01-03 12:15:05 -0600 CST
+		Services:    SFNodeNetwork,
+		Type:        IPv4Address,
+		EncodedAddr: []byte{0x7f, 0x00, 0x00, 0x01},
+		Port:        8333,
+	}
+	torv3PubKey := make([]byte, 32)
+	for i := range torv3PubKey {
+		torv3PubKey[i] = byte(i + 1)
+	}
+	torv3 := &NetAddressV2{
+		Timestamp:   time.Unix(0x495fab29, 0), // 2009-01-03 12:15:05 -0600 CST
+		Services:    SFNodeNetwork,
+		Type:        TORv3Address,
+		EncodedAddr: torv3PubKey,
+		Port:        8334,
+	}
+	return ipv4, torv3
+}
+
+// baseAddrV2Encoded is the wire encoding of an addrv2 message that contains
+// the addresses returned by baseNetAddressesV2.
+var baseAddrV2Encoded = []byte{
+	0x02,                   // Varint for number of addresses
+	0x29, 0xab, 0x5f, 0x49, // Timestamp
+	0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // SFNodeNetwork
+	0x01,                   // IPv4Address
+	0x7f, 0x00, 0x00, 0x01, // IP 127.0.0.1
+	0x20, 0x8d, // Port 8333 in big-endian
+	0x29, 0xab, 0x5f, 0x49, // Timestamp
+	0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // SFNodeNetwork
+	0x04,                                           // TORv3Address
+	0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, // Tor v3 public key
+	0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+	0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18,
+	0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20,
+	0x20, 0x8e, // Port 8334 in big-endian
+}
+
+// TestAddrV2 tests the MsgAddrV2 API.
+func TestAddrV2(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "addrv2"
+	msg := NewMsgAddrV2()
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgAddrV2: wrong command - got %v want %v",
+			cmd, wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Num addresses (size of varInt for max address) + max allowed addresses.
+	wantPayload := uint32(47003)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for "+
+			"protocol version %d - got %v, want %v", pver,
+			maxPayload, wantPayload)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol "+
+			"version %d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+
+	// Ensure addresses are added properly.
+	_, na := baseNetAddressesV2()
+	err := msg.AddAddress(na)
+	if err != nil {
+		t.Errorf("AddAddress: %v", err)
+	}
+	if msg.AddrList[0] != na {
+		t.Errorf("AddAddress: wrong address added - got %v, want %v",
+			spew.Sprint(msg.AddrList[0]), spew.Sprint(na))
+	}
+
+	// Ensure the address list is cleared properly.
+	msg.ClearAddresses()
+	if len(msg.AddrList) != 0 {
+		t.Errorf("ClearAddresses: address list is not empty - "+
+			"got %v [%v], want %v", len(msg.AddrList),
+			spew.Sprint(msg.AddrList[0]), 0)
+	}
+
+	// Ensure adding more than the max allowed addresses per message returns
+	// error.
+	for i := 0; i < MaxAddrPerV2Msg+1; i++ {
+		err = msg.AddAddress(na)
+	}
+	if !errors.Is(err, ErrTooManyAddrs) {
+		t.Errorf("AddAddress: wrong error on too many addresses - got %v, "+
+			"want %v", err, ErrTooManyAddrs)
+	}
+	err = msg.AddAddresses(na)
+	if !errors.Is(err, ErrTooManyAddrs) {
+		t.Errorf("AddAddresses: wrong error on too many addresses - got %v, "+
+			"want %v", err, ErrTooManyAddrs)
+	}
+}
+
+// TestAddrV2PreviousProtocol ensures the MsgAddrV2 API rejects encoding and
+// decoding for protocol versions prior to AddrV2Version.
+func TestAddrV2PreviousProtocol(t *testing.T) {
+	pver := AddrV2Version - 1
+
+	ipv4, torv3 := baseNetAddressesV2()
+	msg := NewMsgAddrV2()
+	msg.AddAddresses(ipv4, torv3)
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgAddrV2
+	err = readmsg.BtcDecode(bytes.NewReader(baseAddrV2Encoded), pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestAddrV2Wire tests the MsgAddrV2 wire encode and decode for various
+// numbers of addresses.
+func TestAddrV2Wire(t *testing.T) {
+	// Empty address message.
+	noAddr := NewMsgAddrV2()
+	noAddrEncoded := []byte{
+		0x00, // Varint for number of addresses
+	}
+
+	// Address message with multiple addresses of different types.
+	ipv4, torv3 := baseNetAddressesV2()
+	multiAddr := NewMsgAddrV2()
+	multiAddr.AddAddresses(ipv4, torv3)
+
+	tests := []struct {
+		in   *MsgAddrV2 // Message to encode
+		out  *MsgAddrV2 // Expected decoded message
+		buf  []byte     // Wire encoding
+		pver uint32     // Protocol version for wire encoding
+	}{
+		// Latest protocol version with no addresses.
+		{
+			noAddr,
+			noAddr,
+			noAddrEncoded,
+			ProtocolVersion,
+		},
+
+		// Latest protocol version with multiple addresses.
+		{
+			multiAddr,
+			multiAddr,
+			baseAddrV2Encoded,
+			ProtocolVersion,
+		},
+
+		// Protocol version AddrV2Version with multiple addresses.
+		{
+			multiAddr,
+			multiAddr,
+			baseAddrV2Encoded,
+			AddrV2Version,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgAddrV2
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestAddrV2WireErrors performs negative tests against wire encode and decode
+// of MsgAddrV2 to confirm error paths work correctly.
+func TestAddrV2WireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	ipv4, torv3 := baseNetAddressesV2()
+	baseAddr := NewMsgAddrV2()
+	baseAddr.AddAddresses(ipv4, torv3)
+
+	// Message that forces an error by having more than the max allowed
+	// addresses.
+	maxAddr := NewMsgAddrV2()
+	for i := 0; i < MaxAddrPerV2Msg; i++ {
+		maxAddr.AddAddress(ipv4)
+	}
+	maxAddr.AddrList = append(maxAddr.AddrList, ipv4)
+	maxAddrEncoded := []byte{
+		0xfd, 0xe9, 0x03, // Varint for number of addresses (1001)
+	}
+
+	// Message that forces an error by having an address of an unknown type.
+	unknownType := *ipv4
+	unknownType.Type = NetAddressType(3)
+	unknownTypeAddr := NewMsgAddrV2()
+	unknownTypeAddr.AddAddress(&unknownType)
+	unknownTypeEncoded := []byte{
+		0x01,                   // Varint for number of addresses
+		0x29, 0xab, 0x5f, 0x49, // Timestamp
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // SFNodeNetwork
+		0x03, // Unknown address type
+	}
+
+	// Message that forces an error by having an encoded address with a
+	// length that does not match its type.
+	badLen := *torv3
+	badLen.EncodedAddr = badLen.EncodedAddr[:16]
+	badLenAddr := NewMsgAddrV2()
+	badLenAddr.AddAddress(&badLen)
+
+	tests := []struct {
+		in       *MsgAddrV2 // Value to encode
+		buf      []byte     // Wire encoding
+		pver     uint32     // Protocol version for wire encoding
+		max      int        // Max size of fixed buffer to induce errors
+		writeErr error      // Expected write error
+		readErr  error      // Expected read error
+	}{
+		// Latest protocol version with intentional read/write errors.
+		// Force error in addresses count.
+		{baseAddr, baseAddrV2Encoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in address timestamp.
+		{baseAddr, baseAddrV2Encoded, pver, 1, io.ErrShortWrite, io.EOF},
+		// Force error in address type.
+		{baseAddr, baseAddrV2Encoded, pver, 13, io.ErrShortWrite, io.EOF},
+		// Force error in encoded address.
+		{baseAddr, baseAddrV2Encoded, pver, 14, io.ErrShortWrite, io.EOF},
+		// Force error in port.
+		{baseAddr, baseAddrV2Encoded, pver, 18, io.ErrShortWrite, io.EOF},
+		// Force error in second encoded address.
+		{baseAddr, baseAddrV2Encoded, pver, 33, io.ErrShortWrite, io.EOF},
+		// Force error with greater than max addresses.
+		{maxAddr, maxAddrEncoded, pver, 3, ErrTooManyAddrs, ErrTooManyAddrs},
+		// Force error with unknown address type.
+		{unknownTypeAddr, unknownTypeEncoded, pver, 14, ErrUnknownNetAddrType,
+			ErrUnknownNetAddrType},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgAddrV2
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+
+	// Ensure encoding an address with a length that does not match its type
+	// is rejected.
+	var buf bytes.Buffer
+	err := badLenAddr.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrInvalidNetAddrLen) {
+		t.Errorf("BtcEncode wrong error got: %v, want: %v", err,
+			ErrInvalidNetAddrLen)
+	}
+}
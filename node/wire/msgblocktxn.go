@@ -0,0 +1,148 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+)
+
+// MsgBlockTxn implements the Message interface and represents a Vigil
+// blocktxn message.  It is used to deliver the transactions of a block
+// requested via a getblocktxn message (MsgGetBlockTxn) in the same order they
+// were requested.
+//
+// This message was not added until protocol version CompactBlocksVersion.
+type MsgBlockTxn struct {
+	BlockHash     chainhash.Hash
+	Transactions  []*MsgTx
+	STransactions []*MsgTx
+}
+
+// readBlockTxns reads a list of transactions for a single transaction tree of
+// a block from r.
+func readBlockTxns(op string, r io.Reader, pver uint32) ([]*MsgTx, error) {
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prevent more transactions than could possibly fit into a transaction
+	// tree.  It would be possible to cause memory exhaustion and panics
+	// without a sane upper bound on this count.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return nil, messageError(op, ErrTooManyTxs, msg)
+	}
+
+	var txns []*MsgTx
+	if count > 0 {
+		txns = make([]*MsgTx, 0, count)
+	}
+	for i := uint64(0); i < count; i++ {
+		var tx MsgTx
+		if err := tx.BtcDecode(r, pver); err != nil {
+			return nil, err
+		}
+		txns = append(txns, &tx)
+	}
+	return txns, nil
+}
+
+// writeBlockTxns writes the provided transactions for a single transaction tree
+// of a block to w.
+func writeBlockTxns(op string, w io.Writer, pver uint32, txns []*MsgTx) error {
+	count := uint64(len(txns))
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	if err := WriteVarInt(w, pver, count); err != nil {
+		return err
+	}
+	for _, tx := range txns {
+		if err := tx.BtcEncode(w, pver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BtcDecode decodes r using the Vigil protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgBlockTxn) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgBlockTxn.BtcDecode"
+	if pver < CompactBlocksVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readElement(r, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	msg.Transactions, err = readBlockTxns(op, r, pver)
+	if err != nil {
+		return err
+	}
+	msg.STransactions, err = readBlockTxns(op, r, pver)
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the Vigil protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgBlockTxn) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgBlockTxn.BtcEncode"
+	if pver < CompactBlocksVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := writeElement(w, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	err = writeBlockTxns(op, w, pver, msg.Transactions)
+	if err != nil {
+		return err
+	}
+	return writeBlockTxns(op, w, pver, msg.STransactions)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgBlockTxn) Command() string {
+	return CmdBlockTxn
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgBlockTxn) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlocksVersion {
+		return 0
+	}
+
+	// Block hash + the transactions which can't exceed the max block
+	// payload.
+	return chainhash.HashSize + MaxBlockPayload
+}
+
+// NewMsgBlockTxn returns a new Vigil blocktxn message that conforms to the
+// Message interface using the passed parameters and defaults for the
+// remaining fields.  See MsgBlockTxn for details.
+func NewMsgBlockTxn(blockHash *chainhash.Hash) *MsgBlockTxn {
+	return &MsgBlockTxn{
+		BlockHash: *blockHash,
+	}
+}
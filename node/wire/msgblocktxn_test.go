@@ -0,0 +1,131 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+)
+
+// TestBlockTxn tests the MsgBlockTxn API.
+func TestBlockTxn(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "blocktxn"
+	hash := chainhash.Hash{0x01}
+	msg := NewMsgBlockTxn(&hash)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgBlockTxn: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+	if msg.BlockHash != hash {
+		t.Errorf("NewMsgBlockTxn: wrong block hash - got %v, want %v",
+			msg.BlockHash, hash)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	wantPayload := uint32(chainhash.HashSize + MaxBlockPayload)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for "+
+			"protocol version %d - got %v, want %v", pver, maxPayload,
+			wantPayload)
+	}
+}
+
+// TestBlockTxnPreviousProtocol ensures the MsgBlockTxn API rejects encoding
+// and decoding for protocol versions prior to CompactBlocksVersion.
+func TestBlockTxnPreviousProtocol(t *testing.T) {
+	pver := CompactBlocksVersion - 1
+	msg := NewMsgBlockTxn(&chainhash.Hash{})
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver, err,
+			ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgBlockTxn
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver, err,
+			ErrMsgInvalidForPVer)
+	}
+}
+
+// TestBlockTxnWire tests the MsgBlockTxn wire encode and decode.
+func TestBlockTxnWire(t *testing.T) {
+	hash := chainhash.Hash{0x01, 0x02, 0x03}
+
+	noTxns := NewMsgBlockTxn(&hash)
+	withTxns := NewMsgBlockTxn(&hash)
+	withTxns.Transactions = []*MsgTx{multiTx, multiTx}
+	withTxns.STransactions = []*MsgTx{multiTx}
+
+	tests := []struct {
+		name string
+		msg  *MsgBlockTxn
+	}{
+		{"no transactions", noTxns},
+		{"with transactions", withTxns},
+	}
+
+	pver := ProtocolVersion
+	for _, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.msg.BtcEncode(&buf, pver)
+		if err != nil {
+			t.Errorf("%q: BtcEncode error %v", test.name, err)
+			continue
+		}
+		wantLen := chainhash.HashSize + 2 + multiTx.SerializeSize()*
+			(len(test.msg.Transactions)+len(test.msg.STransactions))
+		if buf.Len() != wantLen {
+			t.Errorf("%q: wrong encoded length - got %d, want %d",
+				test.name, buf.Len(), wantLen)
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgBlockTxn
+		err = msg.BtcDecode(bytes.NewReader(buf.Bytes()), pver)
+		if err != nil {
+			t.Errorf("%q: BtcDecode error %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.msg) {
+			t.Errorf("%q: mismatched message - got %v, want %v", test.name,
+				spew.Sdump(&msg), spew.Sdump(test.msg))
+			continue
+		}
+	}
+}
+
+// TestBlockTxnOverflowErrors ensures decoding a blocktxn message that claims
+// more transactions than could fit into a block is rejected.
+func TestBlockTxnOverflowErrors(t *testing.T) {
+	pver := ProtocolVersion
+	hash := chainhash.Hash{0x01}
+	encoded := append(hash[:], 0xfe, 0xff, 0xff, 0xff, 0x7f)
+
+	var msg MsgBlockTxn
+	err := msg.BtcDecode(bytes.NewReader(encoded), pver)
+	if !errors.Is(err, ErrTooManyTxs) {
+		t.Errorf("BtcDecode: wrong error - got %v, want %v", err,
+			ErrTooManyTxs)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// ShortTxIDSize is the number of bytes used to encode each short transaction
+// ID in a cmpctblock message.
+const ShortTxIDSize = 6
+
+// MaxShortTxID is the maximum value of a short transaction ID.
+const MaxShortTxID = 1<<(ShortTxIDSize*8) - 1
+
+// PrefilledTx houses a transaction that is included in full in a cmpctblock
+// message along with its index in the transaction tree of the block.
+type PrefilledTx struct {
+	Index uint32
+	Tx    *MsgTx
+}
+
+// MsgCmpctBlock implements the Message interface and represents a Vigil
+// cmpctblock message.  It is used to deliver a block in a compact form in
+// response to a getdata message (MsgGetData) for a given block hash with an
+// inventory type of InvTypeCompactBlock.
+//
+// The transactions in each of the regular and stake transaction trees are
+// either provided in full via the prefilled transactions, such as the coinbase
+// and votes which the receiver is unlikely to already have, or identified by
+// a short transaction ID derived from the block header and nonce.  The receiver
+// reconstructs the block from the transactions it already knows about and
+// requests any that are missing via a getblocktxn message (MsgGetBlockTxn).
+//
+// The short IDs and prefilled transactions of each tree together describe all
+// of the transactions in the tree.  The prefilled transactions must be ordered
+// by their index and the short IDs fill the remaining positions in order.
+//
+// This message was not added until protocol version CompactBlocksVersion.
+type MsgCmpctBlock struct {
+	Header        BlockHeader
+	Nonce         uint64
+	ShortIDs      []uint64
+	PrefilledTxs  []PrefilledTx
+	StakeShortIDs []uint64
+	PrefilledSTxs []PrefilledTx
+}
+
+// NumTransactions returns the total number of transactions in the regular
+// transaction tree described by the message.
+func (msg *MsgCmpctBlock) NumTransactions() int {
+	return len(msg.ShortIDs) + len(msg.PrefilledTxs)
+}
+
+// NumSTransactions returns the total number of transactions in the stake
+// transaction tree described by the message.
+func (msg *MsgCmpctBlock) NumSTransactions() int {
+	return len(msg.StakeShortIDs) + len(msg.PrefilledSTxs)
+}
+
+// readCompactTxTree reads the short IDs and prefilled transactions for a
+// single transaction tree of a cmpctblock message from r.
+func readCompactTxTree(op string, r io.Reader, pver uint32) ([]uint64, []PrefilledTx, error) {
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many short IDs to fit into a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return nil, nil, messageError(op, ErrTooManyTxs, msg)
+	}
+
+	var shortIDs []uint64
+	if count > 0 {
+		shortIDs = make([]uint64, count)
+	}
+	var buf [8]byte
+	for i := range shortIDs {
+		if _, err := io.ReadFull(r, buf[:ShortTxIDSize]); err != nil {
+			return nil, nil, err
+		}
+		shortIDs[i] = littleEndian.Uint64(buf[:])
+	}
+
+	prefilledCount, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if count+prefilledCount > maxTxPerTree {
+		msg := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", count+prefilledCount, maxTxPerTree)
+		return nil, nil, messageError(op, ErrTooManyTxs, msg)
+	}
+
+	// The prefilled transactions must be in ascending order of their index
+	// and the indices must refer to a position within the tree.
+	var prefilled []PrefilledTx
+	if prefilledCount > 0 {
+		prefilled = make([]PrefilledTx, prefilledCount)
+	}
+	numTxns := count + prefilledCount
+	for i := range prefilled {
+		index, err := ReadVarInt(r, pver)
+		if err != nil {
+			return nil, nil, err
+		}
+		if index >= numTxns || (i > 0 &&
+			index <= uint64(prefilled[i-1].Index)) {
+
+			msg := fmt.Sprintf("prefilled transaction index %d is out of "+
+				"order or exceeds the number of transactions %d", index,
+				numTxns)
+			return nil, nil, messageError(op, ErrInvalidMsg, msg)
+		}
+
+		var tx MsgTx
+		if err := tx.BtcDecode(r, pver); err != nil {
+			return nil, nil, err
+		}
+		prefilled[i] = PrefilledTx{Index: uint32(index), Tx: &tx}
+	}
+
+	return shortIDs, prefilled, nil
+}
+
+// writeCompactTxTree writes the short IDs and prefilled transactions for a
+// single transaction tree of a cmpctblock message to w.
+func writeCompactTxTree(op string, w io.Writer, pver uint32, shortIDs []uint64, prefilled []PrefilledTx) error {
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	numTxns := uint64(len(shortIDs) + len(prefilled))
+	if numTxns > maxTxPerTree {
+		msg := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", numTxns, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	err := WriteVarInt(w, pver, uint64(len(shortIDs)))
+	if err != nil {
+		return err
+	}
+	var buf [8]byte
+	for _, shortID := range shortIDs {
+		if shortID > MaxShortTxID {
+			msg := fmt.Sprintf("short ID %x exceeds the max allowed value",
+				shortID)
+			return messageError(op, ErrInvalidMsg, msg)
+		}
+		littleEndian.PutUint64(buf[:], shortID)
+		if _, err := w.Write(buf[:ShortTxIDSize]); err != nil {
+			return err
+		}
+	}
+
+	err = WriteVarInt(w, pver, uint64(len(prefilled)))
+	if err != nil {
+		return err
+	}
+	for i := range prefilled {
+		index := uint64(prefilled[i].Index)
+		if index >= numTxns || (i > 0 && prefilled[i].Index <=
+			prefilled[i-1].Index) {
+
+			msg := fmt.Sprintf("prefilled transaction index %d is out of "+
+				"order or exceeds the number of transactions %d", index,
+				numTxns)
+			return messageError(op, ErrInvalidMsg, msg)
+		}
+		if err := WriteVarInt(w, pver, index); err != nil {
+			return err
+		}
+		if err := prefilled[i].Tx.BtcEncode(w, pver); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BtcDecode decodes r using the Vigil protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgCmpctBlock.BtcDecode"
+	if pver < CompactBlocksVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readBlockHeader(r, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+	err = readElement(r, &msg.Nonce)
+	if err != nil {
+		return err
+	}
+
+	msg.ShortIDs, msg.PrefilledTxs, err = readCompactTxTree(op, r, pver)
+	if err != nil {
+		return err
+	}
+	msg.StakeShortIDs, msg.PrefilledSTxs, err = readCompactTxTree(op, r, pver)
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the Vigil protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgCmpctBlock.BtcEncode"
+	if pver < CompactBlocksVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := writeBlockHeader(w, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+	err = writeElement(w, msg.Nonce)
+	if err != nil {
+		return err
+	}
+
+	err = writeCompactTxTree(op, w, pver, msg.ShortIDs, msg.PrefilledTxs)
+	if err != nil {
+		return err
+	}
+	return writeCompactTxTree(op, w, pver, msg.StakeShortIDs,
+		msg.PrefilledSTxs)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgCmpctBlock) Command() string {
+	return CmdCmpctBlock
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlocksVersion {
+		return 0
+	}
+
+	// The max block payload accounts for the header, the prefilled
+	// transactions, and short IDs since they are always smaller than the
+	// transactions they replace.  On top of that, there is the nonce, the
+	// additional pair of counts, and the index of every possible prefilled
+	// transaction in both trees.
+	return MaxBlockPayload + 8 + (MaxVarIntPayload * 2) +
+		uint32(MaxTxPerTxTree(pver)*2*MaxVarIntPayload)
+}
+
+// NewMsgCmpctBlock returns a new Vigil cmpctblock message that conforms to
+// the Message interface using the passed parameters and defaults for the
+// remaining fields.  See MsgCmpctBlock for details.
+func NewMsgCmpctBlock(header *BlockHeader, nonce uint64) *MsgCmpctBlock {
+	return &MsgCmpctBlock{
+		Header: *header,
+		Nonce:  nonce,
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// testCmpctBlockHeader is a block header used throughout the compact block
+// tests.
+var testCmpctBlockHeader = BlockHeader{
+	Version:      1,
+	PrevBlock:    [32]byte{0x01},
+	MerkleRoot:   [32]byte{0x02},
+	StakeRoot:    [32]byte{0x03},
+	VoteBits:     1,
+	Voters:       5,
+	PoolSize:     40960,
+	Bits:         0x1b01ffff,
+	SBits:        200000000,
+	Height:       100,
+	Size:         1000,
+	Timestamp:    time.Unix(0x5c1b5f40, 0),
+	Nonce:        0x0102030405060708,
+	StakeVersion: 7,
+}
+
+// TestCmpctBlock tests the MsgCmpctBlock API.
+func TestCmpctBlock(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "cmpctblock"
+	msg := NewMsgCmpctBlock(&testCmpctBlockHeader, 0x1122334455667788)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgCmpctBlock: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure the header and nonce are set.
+	if !reflect.DeepEqual(msg.Header, testCmpctBlockHeader) {
+		t.Errorf("NewMsgCmpctBlock: wrong header - got %v, want %v",
+			spew.Sdump(msg.Header), spew.Sdump(testCmpctBlockHeader))
+	}
+	if msg.Nonce != 0x1122334455667788 {
+		t.Errorf("NewMsgCmpctBlock: wrong nonce - got %x", msg.Nonce)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	wantPayload := uint32(MaxBlockPayload + 8 + MaxVarIntPayload*2 +
+		MaxTxPerTxTree(pver)*2*MaxVarIntPayload)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for "+
+			"protocol version %d - got %v, want %v", pver, maxPayload,
+			wantPayload)
+	}
+
+	// Ensure the max payload length is within the max message payload.
+	if maxPayload > MaxMessagePayload {
+		t.Errorf("MaxPayloadLength: max payload length %d exceeds max "+
+			"message payload %d", maxPayload, MaxMessagePayload)
+	}
+
+	// Ensure the number of transactions accounts for both the short IDs and
+	// the prefilled transactions.
+	msg.ShortIDs = []uint64{1, 2}
+	msg.PrefilledTxs = []PrefilledTx{{Index: 0, Tx: multiTx}}
+	msg.PrefilledSTxs = []PrefilledTx{{Index: 0, Tx: multiTx}}
+	if got := msg.NumTransactions(); got != 3 {
+		t.Errorf("NumTransactions: wrong count - got %d, want 3", got)
+	}
+	if got := msg.NumSTransactions(); got != 1 {
+		t.Errorf("NumSTransactions: wrong count - got %d, want 1", got)
+	}
+}
+
+// TestCmpctBlockPreviousProtocol ensures the MsgCmpctBlock API rejects
+// encoding and decoding for protocol versions prior to CompactBlocksVersion.
+func TestCmpctBlockPreviousProtocol(t *testing.T) {
+	pver := CompactBlocksVersion - 1
+	msg := NewMsgCmpctBlock(&testCmpctBlockHeader, 1)
+
+	// Ensure max payload is zero for old protocol versions.
+	if maxPayload := msg.MaxPayloadLength(pver); maxPayload != 0 {
+		t.Errorf("MaxPayloadLength: wrong max payload length for "+
+			"protocol version %d - got %v, want 0", pver, maxPayload)
+	}
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver, err,
+			ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgCmpctBlock
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver, err,
+			ErrMsgInvalidForPVer)
+	}
+}
+
+// TestCmpctBlockWire tests the MsgCmpctBlock wire encode and decode for
+// various combinations of short IDs and prefilled transactions.
+func TestCmpctBlockWire(t *testing.T) {
+	pver := ProtocolVersion
+
+	noTxns := NewMsgCmpctBlock(&testCmpctBlockHeader, 2)
+
+	shortIDsOnly := NewMsgCmpctBlock(&testCmpctBlockHeader, 3)
+	shortIDsOnly.ShortIDs = []uint64{0, 0x010203040506, MaxShortTxID}
+	shortIDsOnly.StakeShortIDs = []uint64{0xabcdef}
+
+	mixed := NewMsgCmpctBlock(&testCmpctBlockHeader, 4)
+	mixed.ShortIDs = []uint64{0x1111, 0x2222}
+	mixed.PrefilledTxs = []PrefilledTx{{Index: 0, Tx: multiTx},
+		{Index: 2, Tx: multiTx}}
+	mixed.StakeShortIDs = []uint64{0x3333}
+	mixed.PrefilledSTxs = []PrefilledTx{{Index: 1, Tx: multiTx}}
+
+	tests := []struct {
+		name string
+		msg  *MsgCmpctBlock
+	}{
+		{"no transactions", noTxns},
+		{"short IDs only", shortIDsOnly},
+		{"short IDs and prefilled", mixed},
+	}
+
+	for _, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.msg.BtcEncode(&buf, pver)
+		if err != nil {
+			t.Errorf("%q: BtcEncode error %v", test.name, err)
+			continue
+		}
+
+		// Ensure the short IDs are encoded with the expected size.
+		var hdrBuf bytes.Buffer
+		if err := writeBlockHeader(&hdrBuf, pver, &test.msg.Header); err != nil {
+			t.Fatalf("%q: unexpected header encode error %v", test.name, err)
+		}
+		minLen := hdrBuf.Len() + 8 + 4 + ShortTxIDSize*(len(test.msg.ShortIDs)+
+			len(test.msg.StakeShortIDs))
+		if buf.Len() < minLen {
+			t.Errorf("%q: encoded length %d is less than the min expected %d",
+				test.name, buf.Len(), minLen)
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgCmpctBlock
+		if err := msg.BtcDecode(bytes.NewReader(buf.Bytes()), pver); err != nil {
+			t.Errorf("%q: BtcDecode error %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.msg) {
+			t.Errorf("%q: mismatched message - got %v, want %v", test.name,
+				spew.Sdump(&msg), spew.Sdump(test.msg))
+			continue
+		}
+	}
+}
+
+// TestCmpctBlockWireErrors performs negative tests against wire encode and
+// decode of MsgCmpctBlock to confirm malformed messages are rejected.
+func TestCmpctBlockWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Create a valid message with a prefilled transaction to use as the
+	// basis for the decode tests.
+	valid := NewMsgCmpctBlock(&testCmpctBlockHeader, 5)
+	valid.ShortIDs = []uint64{0x01}
+	valid.PrefilledTxs = []PrefilledTx{{Index: 1, Tx: multiTx}}
+	var validBuf bytes.Buffer
+	if err := valid.BtcEncode(&validBuf, pver); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	validBytes := validBuf.Bytes()
+
+	// Determine the offset of the prefilled transaction index.
+	var hdrBuf bytes.Buffer
+	if err := writeBlockHeader(&hdrBuf, pver, &valid.Header); err != nil {
+		t.Fatalf("unexpected header encode error: %v", err)
+	}
+	prefilledIdxOffset := hdrBuf.Len() + 8 + 1 + ShortTxIDSize + 1
+
+	// Decode errors.
+	outOfRange := append([]byte(nil), validBytes...)
+	outOfRange[prefilledIdxOffset] = 0x02
+	shortCountOffset := hdrBuf.Len() + 8
+	tooManyShortIDs := append([]byte(nil), validBytes[:shortCountOffset]...)
+	tooManyShortIDs = append(tooManyShortIDs, 0xfe, 0xff, 0xff, 0xff, 0x7f)
+	decodeTests := []struct {
+		name string
+		buf  []byte
+		err  error
+	}{
+		{"prefilled index out of range", outOfRange, ErrInvalidMsg},
+		{"too many short IDs", tooManyShortIDs, ErrTooManyTxs},
+	}
+	for _, test := range decodeTests {
+		var msg MsgCmpctBlock
+		err := msg.BtcDecode(bytes.NewReader(test.buf), pver)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: wrong error - got %v, want %v", test.name, err,
+				test.err)
+		}
+	}
+
+	// Encode errors.
+	unordered := NewMsgCmpctBlock(&testCmpctBlockHeader, 6)
+	unordered.ShortIDs = []uint64{0x01}
+	unordered.PrefilledTxs = []PrefilledTx{{Index: 1, Tx: multiTx},
+		{Index: 0, Tx: multiTx}}
+	shortIDTooLarge := NewMsgCmpctBlock(&testCmpctBlockHeader, 7)
+	shortIDTooLarge.StakeShortIDs = []uint64{MaxShortTxID + 1}
+	encodeTests := []struct {
+		name string
+		msg  *MsgCmpctBlock
+		err  error
+	}{
+		{"unordered prefilled transactions", unordered, ErrInvalidMsg},
+		{"short ID too large", shortIDTooLarge, ErrInvalidMsg},
+	}
+	for _, test := range encodeTests {
+		var buf bytes.Buffer
+		err := test.msg.BtcEncode(&buf, pver)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: wrong error - got %v, want %v", test.name, err,
+				test.err)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+)
+
+// MsgGetBlockTxn implements the Message interface and represents a Vigil
+// getblocktxn message.  It is used to request the transactions of a block that
+// were not available to reconstruct it from a cmpctblock message
+// (MsgCmpctBlock).  The transactions are identified by their indices in the
+// regular and stake transaction trees of the block and are returned via a
+// blocktxn message (MsgBlockTxn).
+//
+// The indices of each tree must be in ascending order.
+//
+// This message was not added until protocol version CompactBlocksVersion.
+type MsgGetBlockTxn struct {
+	BlockHash    chainhash.Hash
+	Indexes      []uint32
+	StakeIndexes []uint32
+}
+
+// readTxIndexes reads a list of ascending transaction indices from r.
+func readTxIndexes(op string, r io.Reader, pver uint32) ([]uint32, error) {
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, err
+	}
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transaction indexes for a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return nil, messageError(op, ErrTooManyTxs, msg)
+	}
+
+	var indexes []uint32
+	if count > 0 {
+		indexes = make([]uint32, count)
+	}
+	for i := range indexes {
+		index, err := ReadVarInt(r, pver)
+		if err != nil {
+			return nil, err
+		}
+		if index >= maxTxPerTree || (i > 0 && index <= uint64(indexes[i-1])) {
+			msg := fmt.Sprintf("transaction index %d is out of order or "+
+				"exceeds the max allowed", index)
+			return nil, messageError(op, ErrInvalidMsg, msg)
+		}
+		indexes[i] = uint32(index)
+	}
+	return indexes, nil
+}
+
+// writeTxIndexes writes the provided list of ascending transaction indices to
+// w.
+func writeTxIndexes(op string, w io.Writer, pver uint32, indexes []uint32) error {
+	count := uint64(len(indexes))
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transaction indexes for a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	if err := WriteVarInt(w, pver, count); err != nil {
+		return err
+	}
+	for i, index := range indexes {
+		if uint64(index) >= maxTxPerTree || (i > 0 && index <= indexes[i-1]) {
+			msg := fmt.Sprintf("transaction index %d is out of order or "+
+				"exceeds the max allowed", index)
+			return messageError(op, ErrInvalidMsg, msg)
+		}
+		if err := WriteVarInt(w, pver, uint64(index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BtcDecode decodes r using the Vigil protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgGetBlockTxn.BtcDecode"
+	if pver < CompactBlocksVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readElement(r, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	msg.Indexes, err = readTxIndexes(op, r, pver)
+	if err != nil {
+		return err
+	}
+	msg.StakeIndexes, err = readTxIndexes(op, r, pver)
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the Vigil protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgGetBlockTxn.BtcEncode"
+	if pver < CompactBlocksVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := writeElement(w, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	err = writeTxIndexes(op, w, pver, msg.Indexes)
+	if err != nil {
+		return err
+	}
+	return writeTxIndexes(op, w, pver, msg.StakeIndexes)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgGetBlockTxn) Command() string {
+	return CmdGetBlockTxn
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlocksVersion {
+		return 0
+	}
+
+	// Block hash + a count and the max number of indexes for each of the
+	// two transaction trees.
+	return chainhash.HashSize + 2*(MaxVarIntPayload+
+		uint32(MaxTxPerTxTree(pver))*MaxVarIntPayload)
+}
+
+// NewMsgGetBlockTxn returns a new Vigil getblocktxn message that conforms to
+// the Message interface using the passed parameters and defaults for the
+// remaining fields.  See MsgGetBlockTxn for details.
+func NewMsgGetBlockTxn(blockHash *chainhash.Hash) *MsgGetBlockTxn {
+	return &MsgGetBlockTxn{
+		BlockHash: *blockHash,
+	}
+}
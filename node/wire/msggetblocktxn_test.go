@@ -0,0 +1,146 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+)
+
+// TestGetBlockTxn tests the MsgGetBlockTxn API.
+func TestGetBlockTxn(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "getblocktxn"
+	hash := chainhash.Hash{0x01}
+	msg := NewMsgGetBlockTxn(&hash)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgGetBlockTxn: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+	if msg.BlockHash != hash {
+		t.Errorf("NewMsgGetBlockTxn: wrong block hash - got %v, want %v",
+			msg.BlockHash, hash)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	wantPayload := uint32(chainhash.HashSize + 2*(MaxVarIntPayload+
+		MaxTxPerTxTree(pver)*MaxVarIntPayload))
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for "+
+			"protocol version %d - got %v, want %v", pver, maxPayload,
+			wantPayload)
+	}
+}
+
+// TestGetBlockTxnPreviousProtocol ensures the MsgGetBlockTxn API rejects
+// encoding and decoding for protocol versions prior to CompactBlocksVersion.
+func TestGetBlockTxnPreviousProtocol(t *testing.T) {
+	pver := CompactBlocksVersion - 1
+	msg := NewMsgGetBlockTxn(&chainhash.Hash{})
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver, err,
+			ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgGetBlockTxn
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver, err,
+			ErrMsgInvalidForPVer)
+	}
+}
+
+// TestGetBlockTxnWire tests the MsgGetBlockTxn wire encode and decode.
+func TestGetBlockTxnWire(t *testing.T) {
+	hash := chainhash.Hash{0x01, 0x02, 0x03}
+
+	noIndexes := NewMsgGetBlockTxn(&hash)
+	noIndexesEncoded := append(hash[:], 0x00, 0x00)
+
+	withIndexes := NewMsgGetBlockTxn(&hash)
+	withIndexes.Indexes = []uint32{1, 2, 0xfd}
+	withIndexes.StakeIndexes = []uint32{0}
+	withIndexesEncoded := append(hash[:], 0x03, 0x01, 0x02, 0xfd, 0xfd,
+		0x00, 0x01, 0x00)
+
+	tests := []struct {
+		in  *MsgGetBlockTxn // Message to encode
+		out *MsgGetBlockTxn // Expected decoded message
+		buf []byte          // Wire encoding
+	}{
+		{noIndexes, noIndexes, noIndexesEncoded},
+		{withIndexes, withIndexes, withIndexesEncoded},
+	}
+
+	pver := ProtocolVersion
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgGetBlockTxn
+		err = msg.BtcDecode(bytes.NewReader(test.buf), pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(&msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestGetBlockTxnWireErrors performs negative tests against wire encode and
+// decode of MsgGetBlockTxn to confirm unordered indexes are rejected.
+func TestGetBlockTxnWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+	hash := chainhash.Hash{0x01}
+
+	// Encode with unordered indexes.
+	msg := NewMsgGetBlockTxn(&hash)
+	msg.StakeIndexes = []uint32{2, 2}
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrInvalidMsg) {
+		t.Errorf("BtcEncode: wrong error - got %v, want %v", err,
+			ErrInvalidMsg)
+	}
+
+	// Decode with unordered indexes.
+	encoded := append(hash[:], 0x02, 0x05, 0x04, 0x00)
+	var readmsg MsgGetBlockTxn
+	err = readmsg.BtcDecode(bytes.NewReader(encoded), pver)
+	if !errors.Is(err, ErrInvalidMsg) {
+		t.Errorf("BtcDecode: wrong error - got %v, want %v", err,
+			ErrInvalidMsg)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// NetAddressType identifies the network a NetAddressV2 belongs to and
+// therefore how its encoded address is to be interpreted.
+type NetAddressType uint8
+
+// NOTE: This specifically does not use iota since the values are part of the
+// wire protocol.  These constants cannot be changed or re-used if new items
+// are added.
+const (
+	// UnknownAddressType is the zero value and is never valid on the wire.
+	UnknownAddressType NetAddressType = 0
+
+	// IPv4Address identifies a 4-byte IPv4 address.
+	IPv4Address NetAddressType = 1
+
+	// IPv6Address identifies a 16-byte IPv6 address.
+	IPv6Address NetAddressType = 2
+
+	// NetAddressType 3 identified Tor v2 onion services which are no longer
+	// supported by the Tor network.
+
+	// TORv3Address identifies a Tor v3 onion service by its 32-byte ed25519
+	// public key.
+	TORv3Address NetAddressType = 4
+
+	// I2PAddress identifies an I2P destination by the 32-byte SHA-256 hash
+	// that makes up its .b32.i2p address.
+	I2PAddress NetAddressType = 5
+)
+
+// Map of network address types back to their constant names for pretty
+// printing.
+var netAddressTypeStrings = map[NetAddressType]string{
+	UnknownAddressType: "UnknownAddressType",
+	IPv4Address:        "IPv4Address",
+	IPv6Address:        "IPv6Address",
+	TORv3Address:       "TORv3Address",
+	I2PAddress:         "I2PAddress",
+}
+
+// String returns the NetAddressType in human-readable form.
+func (t NetAddressType) String() string {
+	if s, ok := netAddressTypeStrings[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown NetAddressType (%d)", uint8(t))
+}
+
+// netAddressTypeSize returns the size of the encoded address for the provided
+// network address type along with whether or not the type is known.
+func netAddressTypeSize(addrType NetAddressType) (int, bool) {
+	switch addrType {
+	case IPv4Address:
+		return 4, true
+	case IPv6Address:
+		return 16, true
+	case TORv3Address, I2PAddress:
+		return 32, true
+	}
+	return 0, false
+}
+
+// maxNetAddressV2Payload returns the max payload size for a NetAddressV2
+// based on the protocol version.
+func maxNetAddressV2Payload(pver uint32) uint32 {
+	// Timestamp 4 bytes + services 8 bytes + address type 1 byte + largest
+	// encoded address 32 bytes + port 2 bytes.
+	return 4 + 8 + 1 + 32 + 2
+}
+
+// NetAddressV2 defines information about a peer on the network including the
+// time it was last seen, the services it supports, the network it is on, its
+// address on that network, and port.
+type NetAddressV2 struct {
+	// Last time the address was seen.  This is encoded as a uint32 on the
+	// wire and therefore is limited to 2106.
+	Timestamp time.Time
+
+	// Bitfield which identifies the services supported by the address.
+	Services ServiceFlag
+
+	// Type identifies the network the address belongs to.
+	Type NetAddressType
+
+	// EncodedAddr is the address of the peer encoded according to Type.  Its
+	// length must match the size required by Type.
+	EncodedAddr []byte
+
+	// Port the peer is using.  This is encoded in big endian on the wire
+	// which differs from most everything else.
+	Port uint16
+}
+
+// HasService returns whether the specified service is supported by the address.
+func (na *NetAddressV2) HasService(service ServiceFlag) bool {
+	return na.Services&service == service
+}
+
+// AddService adds service as a supported service by the peer generating the
+// message.
+func (na *NetAddressV2) AddService(service ServiceFlag) {
+	na.Services |= service
+}
+
+// NewNetAddressV2 returns a new NetAddressV2 using the provided timestamp,
+// services, network address type, encoded address, and port.  The timestamp
+// is rounded to single second precision.
+func NewNetAddressV2(timestamp time.Time, services ServiceFlag,
+	addrType NetAddressType, encodedAddr []byte, port uint16) *NetAddressV2 {
+
+	return &NetAddressV2{
+		Timestamp:   time.Unix(timestamp.Unix(), 0),
+		Services:    services,
+		Type:        addrType,
+		EncodedAddr: encodedAddr,
+		Port:        port,
+	}
+}
+
+// readNetAddressV2 reads an encoded NetAddressV2 from r.  An error is returned
+// when the address type is unknown.
+func readNetAddressV2(op string, r io.Reader, pver uint32, na *NetAddressV2) error {
+	err := readElements(r, (*uint32Time)(&na.Timestamp), &na.Services)
+	if err != nil {
+		return err
+	}
+
+	addrType, err := binarySerializer.Uint8(r)
+	if err != nil {
+		return err
+	}
+	na.Type = NetAddressType(addrType)
+	addrLen, ok := netAddressTypeSize(na.Type)
+	if !ok {
+		msg := fmt.Sprintf("unknown network address type %d", addrType)
+		return messageError(op, ErrUnknownNetAddrType, msg)
+	}
+	na.EncodedAddr = make([]byte, addrLen)
+	if _, err := io.ReadFull(r, na.EncodedAddr); err != nil {
+		return err
+	}
+
+	// Sigh.  Vigil protocol mixes little and big endian.
+	na.Port, err = binarySerializer.Uint16(r, bigEndian)
+	return err
+}
+
+// writeNetAddressV2 serializes a NetAddressV2 to w.  An error is returned when
+// the address type is unknown or the encoded address does not have the length
+// required by the address type.
+func writeNetAddressV2(op string, w io.Writer, pver uint32, na *NetAddressV2) error {
+	addrLen, ok := netAddressTypeSize(na.Type)
+	if !ok {
+		msg := fmt.Sprintf("unknown network address type %d", na.Type)
+		return messageError(op, ErrUnknownNetAddrType, msg)
+	}
+	if len(na.EncodedAddr) != addrLen {
+		msg := fmt.Sprintf("encoded %v is %d bytes instead of the required "+
+			"%d bytes", na.Type, len(na.EncodedAddr), addrLen)
+		return messageError(op, ErrInvalidNetAddrLen, msg)
+	}
+
+	err := writeElements(w, uint32(na.Timestamp.Unix()), na.Services,
+		uint8(na.Type))
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(na.EncodedAddr); err != nil {
+		return err
+	}
+
+	// Sigh.  Vigil protocol mixes little and big endian.
+	return binary.Write(w, bigEndian, na.Port)
+}
@@ -17,7 +17,7 @@ const (
 	InitialProcotolVersion uint32 = 1
 
 	// ProtocolVersion is the latest protocol version this package supports.
-	ProtocolVersion uint32 = 11
+	ProtocolVersion uint32 = 13
 
 	// NodeBloomVersion is the protocol version which added the SFNodeBloom
 	// service flag (unused).
@@ -58,6 +58,17 @@ const (
 	// BatchedCFiltersV2Version is the protocol version which adds support
 	// for the batched getcfsv2 and cfiltersv2 messages.
 	BatchedCFiltersV2Version uint32 = 11
+
+	// AddrV2Version is the protocol version which adds the getaddrv2 and
+	// addrv2 messages that support network address types other than IPv4
+	// and IPv6, such as Tor v3 onion services and I2P.
+	AddrV2Version uint32 = 12
+
+	// CompactBlocksVersion is the protocol version which adds the compact
+	// block inventory type along with the cmpctblock, getblocktxn, and
+	// blocktxn messages used to relay blocks without resending transactions
+	// the receiver already has.
+	CompactBlocksVersion uint32 = 13
 )
 
 // ServiceFlag identifies services supported by a Vigil peer.
@@ -74,13 +85,19 @@ const (
 	// SFNodeCF is a flag used to indicate a peer supports v1 gcs filters
 	// (CFs).
 	SFNodeCF
+
+	// SFNodeNetworkLimited is a flag used to indicate a peer is a pruned node
+	// that is only capable of serving the most recent blocks.  Pruned nodes do
+	// not set SFNodeNetwork.
+	SFNodeNetworkLimited
 )
 
 // Map of service flags back to their constant names for pretty printing.
 var sfStrings = map[ServiceFlag]string{
-	SFNodeNetwork: "SFNodeNetwork",
-	SFNodeBloom:   "SFNodeBloom",
-	SFNodeCF:      "SFNodeCF",
+	SFNodeNetwork:        "SFNodeNetwork",
+	SFNodeBloom:          "SFNodeBloom",
+	SFNodeCF:             "SFNodeCF",
+	SFNodeNetworkLimited: "SFNodeNetworkLimited",
 }
 
 // orderedSFStrings is an ordered list of service flags from highest to
@@ -89,6 +106,7 @@ var orderedSFStrings = []ServiceFlag{
 	SFNodeNetwork,
 	SFNodeBloom,
 	SFNodeCF,
+	SFNodeNetworkLimited,
 }
 
 // String returns the ServiceFlag in human-readable form.
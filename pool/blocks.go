@@ -0,0 +1,191 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/pool/internal/payout"
+	"github.com/kdsmith18542/vigil/pool/internal/sharedb"
+)
+
+// activeTipHeight returns the height of the tip of the main chain as reported
+// by the getchaintips RPC.
+func (p *pool) activeTipHeight(ctx context.Context) (int64, error) {
+	tips, err := p.node.GetChainTips(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, tip := range tips {
+		if tip.Status == "active" {
+			return tip.Height, nil
+		}
+	}
+	return 0, fmt.Errorf("getchaintips did not report an active tip")
+}
+
+// blockReward returns the total amount, in atoms, paid to the reward addresses
+// of the pool by the coinbase of the block with the provided hash.
+func (p *pool) blockReward(ctx context.Context, hash *chainhash.Hash) (int64, error) {
+	block, err := p.node.GetBlock(ctx, hash)
+	if err != nil {
+		return 0, err
+	}
+	if len(block.Transactions) == 0 {
+		return 0, fmt.Errorf("block %s has no coinbase", hash)
+	}
+
+	var reward int64
+	for _, txOut := range block.Transactions[0].TxOut {
+		for _, addr := range p.cfg.rewardAddrs {
+			version, script := addr.PaymentScript()
+			if txOut.Version == version && bytes.Equal(txOut.PkScript, script) {
+				reward += txOut.Value
+				break
+			}
+		}
+	}
+	return reward, nil
+}
+
+// creditBlock distributes the reward of the provided mature block among the
+// accounts that submitted shares during its payout window according to the
+// configured payout scheme.  The provided previous found time is the time the
+// block prior to it was found, if any, which begins the window for the PROP
+// scheme.
+func (p *pool) creditBlock(ctx context.Context, block *sharedb.Block, prevFoundAt time.Time) error {
+	hash, err := chainhash.NewHashFromStr(block.Hash)
+	if err != nil {
+		return err
+	}
+	reward, err := p.blockReward(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	start := payout.WindowStart(p.cfg.paymentScheme, block.FoundAt,
+		prevFoundAt, p.cfg.LastNPeriod)
+	shares, err := p.db.Shares(start, block.FoundAt)
+	if err != nil {
+		return err
+	}
+	weights := make(map[string]float64)
+	for _, share := range shares {
+		weights[share.Account] += share.Weight
+	}
+	credits := payout.Credits(reward, p.cfg.PoolFee, p.cfg.FeeAddr, weights)
+	if err := p.db.CreditBlock(block.Hash, reward, credits); err != nil {
+		return err
+	}
+
+	poolLog.Infof("Credited %d atoms from block %s (height %d) to %d "+
+		"accounts from %d shares", reward, block.Hash, block.Height,
+		len(credits), len(shares))
+	return nil
+}
+
+// processBlocks checks the blocks found by the pool that have not reached
+// coinbase maturity.  Blocks that are no longer part of the main chain once
+// they would have reached maturity are marked orphaned, and the rewards of
+// mature blocks are credited to the participating accounts.  Shares that are
+// no longer needed for any payouts are pruned afterwards.
+func (p *pool) processBlocks(ctx context.Context) {
+	allBlocks, err := p.db.Blocks("")
+	if err != nil {
+		poolLog.Errorf("Unable to load found blocks: %v", err)
+		return
+	}
+
+	var tipHeight int64
+	var havePending bool
+	maturity := int64(p.cfg.params.CoinbaseMaturity)
+	var prevFoundAt time.Time
+	for _, block := range allBlocks {
+		blockPrevFoundAt := prevFoundAt
+		prevFoundAt = block.FoundAt
+		if block.Status != sharedb.BlockPending {
+			continue
+		}
+
+		// Determine the main chain tip once there are pending blocks.
+		if !havePending {
+			havePending = true
+			tipHeight, err = p.activeTipHeight(ctx)
+			if err != nil {
+				poolLog.Errorf("Unable to determine chain tip: %v", err)
+				return
+			}
+		}
+		if tipHeight-block.Height < maturity {
+			continue
+		}
+
+		// The block is orphaned when a different block is at its height in
+		// the main chain.
+		mainHash, err := p.node.GetBlockHash(ctx, block.Height)
+		if err != nil {
+			poolLog.Errorf("Unable to get block hash at height %d: %v",
+				block.Height, err)
+			return
+		}
+		if mainHash.String() != block.Hash {
+			err := p.db.SetBlockStatus(block.Hash, sharedb.BlockOrphaned)
+			if err != nil {
+				poolLog.Errorf("Unable to mark block %s orphaned: %v",
+					block.Hash, err)
+				return
+			}
+			poolLog.Infof("Block %s (height %d) found by %s was orphaned",
+				block.Hash, block.Height, block.Worker)
+			continue
+		}
+
+		if err := p.creditBlock(ctx, block, blockPrevFoundAt); err != nil {
+			poolLog.Errorf("Unable to credit block %s: %v", block.Hash, err)
+			return
+		}
+	}
+
+	p.pruneShares(allBlocks)
+}
+
+// pruneShares removes shares that are no longer needed to credit any of the
+// provided found blocks that are still pending or any block found in the
+// future.
+func (p *pool) pruneShares(allBlocks []*sharedb.Block) {
+	pruneBefore := time.Now().Add(-p.cfg.LastNPeriod)
+	if len(allBlocks) > 0 {
+		// PROP rewards all shares since the most recently found block.
+		last := allBlocks[len(allBlocks)-1].FoundAt
+		if p.cfg.paymentScheme == payout.PROP && last.Before(pruneBefore) {
+			pruneBefore = last
+		}
+	}
+	var prevFoundAt time.Time
+	for _, block := range allBlocks {
+		if block.Status == sharedb.BlockPending {
+			start := payout.WindowStart(p.cfg.paymentScheme, block.FoundAt,
+				prevFoundAt, p.cfg.LastNPeriod)
+			if start.Before(pruneBefore) {
+				pruneBefore = start
+			}
+		}
+		prevFoundAt = block.FoundAt
+	}
+
+	n, err := p.db.PruneShares(pruneBefore)
+	if err != nil {
+		poolLog.Errorf("Unable to prune shares: %v", err)
+		return
+	}
+	if n > 0 {
+		poolLog.Debugf("Pruned %d shares submitted before %v", n,
+			pruneBefore)
+	}
+}
@@ -0,0 +1,459 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/pool/internal/payout"
+	"github.com/kdsmith18542/vigil/slog"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+)
+
+const (
+	appName                  = "vglpool"
+	defaultConfigFilename    = "vglpool.conf"
+	defaultDataDirname       = "data"
+	defaultLogDirname        = "logs"
+	defaultLogFilename       = "vglpool.log"
+	defaultDBFilename        = "pool.db"
+	defaultLogLevel          = "info"
+	defaultStratumPort       = "3333"
+	defaultStratumDiff       = 1
+	defaultMaxClients        = 1000
+	defaultPoolFee           = 1.0
+	defaultPaymentScheme     = string(payout.PPLNS)
+	defaultLastNPeriod       = time.Hour * 24
+	defaultPaymentInterval   = time.Hour
+	defaultMinPayment        = 0.1
+	defaultMaxPaymentOutputs = 100
+	defaultWalletAccount     = "default"
+)
+
+var (
+	defaultHomeDir           = VGLutil.AppDataDir(appName, false)
+	defaultConfigFile        = filepath.Join(defaultHomeDir, defaultConfigFilename)
+	defaultNodeRPCCertFile   = filepath.Join(VGLutil.AppDataDir("vgld", false), "rpc.cert")
+	defaultWalletRPCCertFile = filepath.Join(VGLutil.AppDataDir("vglwallet", false), "rpc.cert")
+)
+
+// config defines the configuration options for vglpool.
+//
+// See loadConfig for details on the configuration load process.
+type config struct {
+	// General application behavior.
+	HomeDir    string `short:"A" long:"appdata" description:"Path to application home directory"`
+	ConfigFile string `short:"C" long:"configfile" description:"Path to configuration file"`
+	DataDir    string `short:"b" long:"datadir" description:"Directory to store data"`
+	LogDir     string `long:"logdir" description:"Directory to log output"`
+	TestNet    bool   `long:"testnet" description:"Use the test network"`
+	SimNet     bool   `long:"simnet" description:"Use the simulation test network"`
+	RegNet     bool   `long:"regnet" description:"Use the regression test network"`
+	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+
+	// Node RPC options.
+	NodeRPCConnect string `long:"noderpcconnect" description:"Hostname/IP and port of the vgld RPC server (default port: 9109, testnet: 19109)"`
+	NodeRPCUser    string `long:"noderpcuser" description:"Username for vgld RPC connections"`
+	NodeRPCPass    string `long:"noderpcpass" default-mask:"-" description:"Password for vgld RPC connections"`
+	NodeRPCCert    string `long:"noderpccert" description:"File containing the vgld RPC certificate"`
+
+	// Wallet RPC options.
+	WalletRPCConnect string `long:"walletrpcconnect" description:"Hostname/IP and port of the wallet JSON-RPC server used for payments (default port: 9110, testnet: 19110)"`
+	WalletRPCUser    string `long:"walletrpcuser" description:"Username for wallet RPC connections"`
+	WalletRPCPass    string `long:"walletrpcpass" default-mask:"-" description:"Password for wallet RPC connections"`
+	WalletRPCCert    string `long:"walletrpccert" description:"File containing the wallet RPC certificate"`
+	WalletAccount    string `long:"walletaccount" description:"Wallet account that payments are sent from"`
+
+	// Stratum options.
+	StratumListeners []string `long:"stratumlisten" description:"Add an interface/port to listen for stratum mining connections (default port: 3333)"`
+	StratumDiff      float64  `long:"stratumdiff" description:"Initial share difficulty for stratum mining clients"`
+	MaxClients       int      `long:"maxclients" description:"Maximum number of stratum mining clients"`
+
+	// Accounting and payment options.
+	RewardAddrs       []string      `long:"rewardaddr" description:"Address that the node pays mined block rewards to.  It must match the --miningaddr option of vgld.  At least one address is required"`
+	FeeAddr           string        `long:"feeaddr" description:"Address that pool fees are paid to"`
+	PoolFee           float64       `long:"poolfee" description:"Percentage of block rewards retained by the pool"`
+	PaymentScheme     string        `long:"paymentscheme" description:"Payout scheme used to distribute block rewards {pplns, prop}"`
+	LastNPeriod       time.Duration `long:"lastnperiod" description:"Period of shares prior to finding a block that are rewarded with the pplns scheme.  Shares older than this are pruned.  Valid time units are {s, m, h}"`
+	PaymentInterval   time.Duration `long:"paymentinterval" description:"How often payments are sent.  Valid time units are {s, m, h}"`
+	MinPayment        float64       `long:"minpayment" description:"Minimum balance in VGL before an account is paid"`
+	MaxPaymentOutputs int           `long:"maxpaymentoutputs" description:"Maximum number of accounts paid in a single transaction"`
+
+	// Cooked options ready for use.
+	params        *params
+	rewardAddrs   []stdaddr.Address
+	feeAddr       stdaddr.Address
+	paymentScheme payout.Scheme
+	minPayment    VGLutil.Amount
+}
+
+// cleanAndExpandPath expands environment variables and leading ~ in the
+// passed path, cleans the result, and returns it.
+func cleanAndExpandPath(path string) string {
+	// Nothing to do when no path is given.
+	if path == "" {
+		return path
+	}
+
+	// NOTE: The os.ExpandEnv doesn't work with Windows cmd.exe-style
+	// %VARIABLE%, but the variables can still be expanded via POSIX-style
+	// $VARIABLE.
+	path = os.ExpandEnv(path)
+
+	if !strings.HasPrefix(path, "~") {
+		return filepath.Clean(path)
+	}
+
+	// Expand initial ~ to the current user's home directory, or ~otheruser
+	// to otheruser's home directory.  On Windows, both forward and backward
+	// slashes can be used.
+	path = path[1:]
+
+	var pathSeparators string
+	if runtime.GOOS == "windows" {
+		pathSeparators = string(os.PathSeparator) + "/"
+	} else {
+		pathSeparators = string(os.PathSeparator)
+	}
+
+	userName := ""
+	if i := strings.IndexAny(path, pathSeparators); i != -1 {
+		userName = path[:i]
+		path = path[i:]
+	}
+
+	homeDir := ""
+	var u *user.User
+	var err error
+	if userName == "" {
+		u, err = user.Current()
+	} else {
+		u, err = user.Lookup(userName)
+	}
+	if err == nil {
+		homeDir = u.HomeDir
+	}
+	// Fallback to CWD if user lookup fails or user has no home directory.
+	if homeDir == "" {
+		homeDir = "."
+	}
+
+	return filepath.Join(homeDir, path)
+}
+
+// validLogLevel returns whether or not logLevel is a valid debug log level.
+func validLogLevel(logLevel string) bool {
+	_, ok := slog.LevelFromString(logLevel)
+	return ok
+}
+
+// supportedSubsystems returns a sorted slice of the supported subsystems for
+// logging purposes.
+func supportedSubsystems() []string {
+	// Convert the subsystemLoggers map keys to a slice.
+	subsystems := make([]string, 0, len(subsystemLoggers))
+	for subsysID := range subsystemLoggers {
+		subsystems = append(subsystems, subsysID)
+	}
+
+	// Sort the subsystems for stable display.
+	sort.Strings(subsystems)
+	return subsystems
+}
+
+// parseAndSetDebugLevels attempts to parse the specified debug level and set
+// the levels accordingly.  An appropriate error is returned if anything is
+// invalid.
+func parseAndSetDebugLevels(debugLevel string) error {
+	// When the specified string doesn't have any delimiters, treat it as
+	// the log level for all subsystems.
+	if !strings.Contains(debugLevel, ",") && !strings.Contains(debugLevel, "=") {
+		// Validate debug log level.
+		if !validLogLevel(debugLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, debugLevel)
+		}
+
+		// Change the logging level for all subsystems.
+		setLogLevels(debugLevel)
+
+		return nil
+	}
+
+	// Split the specified string into subsystem/level pairs while detecting
+	// issues and update the log levels accordingly.
+	for _, logLevelPair := range strings.Split(debugLevel, ",") {
+		if !strings.Contains(logLevelPair, "=") {
+			str := "the specified debug level contains an invalid " +
+				"subsystem/level pair [%v]"
+			return fmt.Errorf(str, logLevelPair)
+		}
+
+		// Extract the specified subsystem and log level.
+		fields := strings.Split(logLevelPair, "=")
+		subsysID, logLevel := fields[0], fields[1]
+
+		// Validate subsystem.
+		if _, exists := subsystemLoggers[subsysID]; !exists {
+			str := "the specified subsystem [%v] is invalid -- " +
+				"supported subsystems %v"
+			return fmt.Errorf(str, subsysID, supportedSubsystems())
+		}
+
+		// Validate log level.
+		if !validLogLevel(logLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, logLevel)
+		}
+
+		setLogLevel(subsysID, logLevel)
+	}
+
+	return nil
+}
+
+// normalizeAddress returns addr with the passed default port appended if there
+// is not already a port specified.
+func normalizeAddress(addr, defaultPort string) string {
+	_, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return net.JoinHostPort(addr, defaultPort)
+	}
+	return addr
+}
+
+// fileExists reports whether the named file or directory exists.
+func fileExists(name string) bool {
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadConfig initializes and parses the config using a config file and command
+// line options.
+//
+// The configuration proceeds as follows:
+//  1. Start with a default config with sane settings
+//  2. Pre-parse the command line to check for an alternative config file
+//  3. Load configuration file overwriting defaults with any specified options
+//  4. Parse CLI options and overwrite/add any specified options
+//
+// The above results in vglpool functioning properly without any config
+// settings while still allowing the user to override settings with config
+// files and command line options.  Command line options always take
+// precedence.
+func loadConfig() (*config, error) {
+	// Default config.
+	cfg := config{
+		HomeDir:           defaultHomeDir,
+		ConfigFile:        defaultConfigFile,
+		DebugLevel:        defaultLogLevel,
+		NodeRPCCert:       defaultNodeRPCCertFile,
+		WalletRPCCert:     defaultWalletRPCCertFile,
+		WalletAccount:     defaultWalletAccount,
+		StratumDiff:       defaultStratumDiff,
+		MaxClients:        defaultMaxClients,
+		PoolFee:           defaultPoolFee,
+		PaymentScheme:     defaultPaymentScheme,
+		LastNPeriod:       defaultLastNPeriod,
+		PaymentInterval:   defaultPaymentInterval,
+		MinPayment:        defaultMinPayment,
+		MaxPaymentOutputs: defaultMaxPaymentOutputs,
+	}
+
+	// Pre-parse the command line options to see if an alternative config
+	// file or home directory was specified.  Any errors aside from the help
+	// message error can be ignored here since they will be caught by the
+	// final parse below.
+	preCfg := cfg
+	preParser := flags.NewParser(&preCfg, flags.HelpFlag)
+	_, err := preParser.Parse()
+	if err != nil {
+		var e *flags.Error
+		if errors.As(err, &e) && e.Type == flags.ErrHelp {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+	}
+
+	// Update the home directory and config file location if specified.
+	if preCfg.HomeDir != defaultHomeDir {
+		cfg.HomeDir = cleanAndExpandPath(preCfg.HomeDir)
+		if preCfg.ConfigFile == defaultConfigFile {
+			preCfg.ConfigFile = filepath.Join(cfg.HomeDir,
+				defaultConfigFilename)
+		}
+	}
+
+	// Load additional config from file.
+	parser := flags.NewParser(&cfg, flags.Default)
+	configFile := cleanAndExpandPath(preCfg.ConfigFile)
+	if fileExists(configFile) {
+		err := flags.NewIniParser(parser).ParseFile(configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+			return nil, err
+		}
+	}
+
+	// Parse command line options again to ensure they take precedence.
+	_, err = parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// Multiple networks can't be selected simultaneously.  Count the number
+	// of network flags passed and assign the active network params.
+	funcName := "loadConfig"
+	numNets := 0
+	cfg.params = &mainNetParams
+	if cfg.TestNet {
+		numNets++
+		cfg.params = &testNet3Params
+	}
+	if cfg.SimNet {
+		numNets++
+		cfg.params = &simNetParams
+	}
+	if cfg.RegNet {
+		numNets++
+		cfg.params = &regNetParams
+	}
+	if numNets > 1 {
+		str := "%s: the testnet, regnet, and simnet params can't be " +
+			"used together -- choose one of the three"
+		return nil, fmt.Errorf(str, funcName)
+	}
+
+	// Namespace the data and log directories per network.
+	if cfg.DataDir == "" {
+		cfg.DataDir = filepath.Join(cfg.HomeDir, defaultDataDirname)
+	}
+	cfg.DataDir = filepath.Join(cleanAndExpandPath(cfg.DataDir),
+		cfg.params.Name)
+	if cfg.LogDir == "" {
+		cfg.LogDir = filepath.Join(cfg.HomeDir, defaultLogDirname)
+	}
+	cfg.LogDir = filepath.Join(cleanAndExpandPath(cfg.LogDir), cfg.params.Name)
+
+	// Special show command to list supported subsystems and exit.
+	if cfg.DebugLevel == "show" {
+		fmt.Println("Supported subsystems", supportedSubsystems())
+		os.Exit(0)
+	}
+
+	// Initialize log rotation.  After log rotation has been initialized, the
+	// logger variables may be used.
+	initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename))
+
+	// Parse, validate, and set debug log level(s).
+	if err := parseAndSetDebugLevels(cfg.DebugLevel); err != nil {
+		return nil, fmt.Errorf("%s: %w", funcName, err)
+	}
+
+	// Connect to the node and wallet on localhost with the default ports for
+	// the active network by default.
+	if cfg.NodeRPCConnect == "" {
+		cfg.NodeRPCConnect = "localhost"
+	}
+	cfg.NodeRPCConnect = normalizeAddress(cfg.NodeRPCConnect,
+		cfg.params.nodeRPCPort)
+	if cfg.WalletRPCConnect == "" {
+		cfg.WalletRPCConnect = "localhost"
+	}
+	cfg.WalletRPCConnect = normalizeAddress(cfg.WalletRPCConnect,
+		cfg.params.walletRPCPort)
+	cfg.NodeRPCCert = cleanAndExpandPath(cfg.NodeRPCCert)
+	cfg.WalletRPCCert = cleanAndExpandPath(cfg.WalletRPCCert)
+
+	// Listen for stratum connections on all interfaces by default.
+	if len(cfg.StratumListeners) == 0 {
+		cfg.StratumListeners = []string{""}
+	}
+	for i, addr := range cfg.StratumListeners {
+		cfg.StratumListeners[i] = normalizeAddress(addr, defaultStratumPort)
+	}
+	if cfg.StratumDiff <= 0 {
+		str := "%s: the stratumdiff option must be greater than 0 " +
+			"-- parsed [%v]"
+		return nil, fmt.Errorf(str, funcName, cfg.StratumDiff)
+	}
+	if cfg.MaxClients < 1 {
+		str := "%s: the maxclients option may not be less than 1 " +
+			"-- parsed [%d]"
+		return nil, fmt.Errorf(str, funcName, cfg.MaxClients)
+	}
+
+	// Check the reward and fee addresses are valid and save parsed versions.
+	if len(cfg.RewardAddrs) == 0 {
+		str := "%s: at least one reward address must be specified"
+		return nil, fmt.Errorf(str, funcName)
+	}
+	for _, strAddr := range cfg.RewardAddrs {
+		addr, err := stdaddr.DecodeAddress(strAddr, cfg.params.Params)
+		if err != nil {
+			str := "%s: reward address '%s' failed to decode: %w"
+			return nil, fmt.Errorf(str, funcName, strAddr, err)
+		}
+		cfg.rewardAddrs = append(cfg.rewardAddrs, addr)
+	}
+	if cfg.FeeAddr == "" {
+		str := "%s: a fee address must be specified"
+		return nil, fmt.Errorf(str, funcName)
+	}
+	cfg.feeAddr, err = stdaddr.DecodeAddress(cfg.FeeAddr, cfg.params.Params)
+	if err != nil {
+		str := "%s: fee address '%s' failed to decode: %w"
+		return nil, fmt.Errorf(str, funcName, cfg.FeeAddr, err)
+	}
+
+	// Validate the accounting and payment options.
+	if cfg.PoolFee < 0 || cfg.PoolFee > 100 {
+		str := "%s: the poolfee option must be between 0 and 100 " +
+			"-- parsed [%v]"
+		return nil, fmt.Errorf(str, funcName, cfg.PoolFee)
+	}
+	cfg.paymentScheme, err = payout.ParseScheme(cfg.PaymentScheme)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", funcName, err)
+	}
+	if cfg.LastNPeriod < time.Minute {
+		str := "%s: the lastnperiod option may not be less than 1m " +
+			"-- parsed [%v]"
+		return nil, fmt.Errorf(str, funcName, cfg.LastNPeriod)
+	}
+	if cfg.PaymentInterval < time.Minute {
+		str := "%s: the paymentinterval option may not be less than 1m " +
+			"-- parsed [%v]"
+		return nil, fmt.Errorf(str, funcName, cfg.PaymentInterval)
+	}
+	cfg.minPayment, err = VGLutil.NewAmount(cfg.MinPayment)
+	if err != nil || cfg.minPayment <= 0 {
+		str := "%s: the minpayment option must be a positive amount " +
+			"-- parsed [%v]"
+		return nil, fmt.Errorf(str, funcName, cfg.MinPayment)
+	}
+	if cfg.MaxPaymentOutputs < 1 {
+		str := "%s: the maxpaymentoutputs option may not be less than 1 " +
+			"-- parsed [%d]"
+		return nil, fmt.Errorf(str, funcName, cfg.MaxPaymentOutputs)
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+vglpool is a KawPoW mining pool for Vigil.
+
+It obtains work from vgld via websocket work notifications, hands the work out
+to mining software through a stratum v1 front-end, and submits solved blocks
+back to vgld with the getwork RPC.  Accepted shares are weighted by their
+difficulty and recorded in a share database.  Once a block found by the pool
+reaches coinbase maturity, its reward is credited to the accounts that
+contributed shares according to the configured payout scheme, and balances
+that reach the minimum payment are paid in batches with the sendmany RPC of
+the wallet.  Blocks that are no longer in the main chain reported by
+getchaintips by the time they mature are marked orphaned and not credited.
+
+Miners authorize with a worker name that consists of the payment address of
+their account, optionally followed by a period and a name for the individual
+worker, such as Rs...abc.rig1.  The password is ignored.
+
+Since vgld creates the block templates, it must be run with --miningaddr set to
+the address(es) given to vglpool with --rewardaddr, and the wallet configured
+with --walletrpcconnect must control them so that rewards can be paid out.
+
+The following payout schemes are supported:
+
+	pplns  Pay per last N shares.  Rewards are split among the shares
+	       submitted during the --lastnperiod window prior to the block.
+	prop   Proportional.  Rewards are split among the shares submitted since
+	       the previous block found by the pool, limited to the
+	       --lastnperiod window.
+
+Usage:
+
+	vglpool [OPTIONS]
+
+Application Options:
+
+	-A, --appdata=             Path to application home directory
+	-C, --configfile=          Path to configuration file
+	-b, --datadir=             Directory to store data
+	    --logdir=              Directory to log output
+	    --testnet              Use the test network
+	    --simnet               Use the simulation test network
+	    --regnet               Use the regression test network
+	-d, --debuglevel=          Logging level for all subsystems {trace, debug,
+	                           info, warn, error, critical} (info)
+	    --noderpcconnect=      Hostname/IP and port of the vgld RPC server
+	                           (default port: 9109, testnet: 19109)
+	    --noderpcuser=         Username for vgld RPC connections
+	    --noderpcpass=         Password for vgld RPC connections
+	    --noderpccert=         File containing the vgld RPC certificate
+	    --walletrpcconnect=    Hostname/IP and port of the wallet JSON-RPC
+	                           server used for payments (default port: 9110,
+	                           testnet: 19110)
+	    --walletrpcuser=       Username for wallet RPC connections
+	    --walletrpcpass=       Password for wallet RPC connections
+	    --walletrpccert=       File containing the wallet RPC certificate
+	    --walletaccount=       Wallet account that payments are sent from
+	                           (default)
+	    --stratumlisten=       Add an interface/port to listen for stratum
+	                           mining connections (default port: 3333)
+	    --stratumdiff=         Initial share difficulty for stratum mining
+	                           clients (1)
+	    --maxclients=          Maximum number of stratum mining clients (1000)
+	    --rewardaddr=          Address that the node pays mined block rewards
+	                           to.  It must match the --miningaddr option of
+	                           vgld.  At least one address is required
+	    --feeaddr=             Address that pool fees are paid to
+	    --poolfee=             Percentage of block rewards retained by the
+	                           pool (1)
+	    --paymentscheme=       Payout scheme used to distribute block rewards
+	                           {pplns, prop} (pplns)
+	    --lastnperiod=         Period of shares prior to finding a block that
+	                           are rewarded with the pplns scheme (24h0m0s)
+	    --paymentinterval=     How often payments are sent (1h0m0s)
+	    --minpayment=          Minimum balance in VGL before an account is
+	                           paid (0.1)
+	    --maxpaymentoutputs=   Maximum number of accounts paid in a single
+	                           transaction (100)
+
+Help Options:
+
+	-h, --help                 Show this help message
+*/
+package main
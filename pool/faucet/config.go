@@ -0,0 +1,413 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/slog"
+)
+
+const (
+	appName                = "vglfaucet"
+	defaultConfigFilename  = "vglfaucet.conf"
+	defaultDataDirname     = "data"
+	defaultLogDirname      = "logs"
+	defaultLogFilename     = "vglfaucet.log"
+	defaultDBFilename      = "ratelimit.db"
+	defaultLogLevel        = "info"
+	defaultListen          = ":5000"
+	defaultWalletAccount   = "default"
+	defaultAmount          = 10
+	defaultAddressCooldown = time.Hour * 24
+	defaultIPRequests      = 5
+	defaultIPPeriod        = time.Hour * 24
+	defaultLowBalance      = 1000
+)
+
+var (
+	defaultHomeDir           = VGLutil.AppDataDir(appName, false)
+	defaultConfigFile        = filepath.Join(defaultHomeDir, defaultConfigFilename)
+	defaultWalletRPCCertFile = filepath.Join(VGLutil.AppDataDir("vglwallet", false), "rpc.cert")
+)
+
+// params is used to group parameters for the test networks the faucet may be
+// run on.
+type params struct {
+	*chaincfg.Params
+	walletRPCPort string
+}
+
+// testNet3Params contains parameters specific to the test network (version 3)
+// (wire.TestNet3).
+var testNet3Params = params{
+	Params:        chaincfg.TestNet3Params(),
+	walletRPCPort: "19110",
+}
+
+// simNetParams contains parameters specific to the simulation test network
+// (wire.SimNet).
+var simNetParams = params{
+	Params:        chaincfg.SimNetParams(),
+	walletRPCPort: "19557",
+}
+
+// regNetParams contains parameters specific to the regression test
+// network (wire.RegNet).
+var regNetParams = params{
+	Params:        chaincfg.RegNetParams(),
+	walletRPCPort: "18657",
+}
+
+// config defines the configuration options for vglfaucet.
+//
+// See loadConfig for details on the configuration load process.
+type config struct {
+	// General application behavior.
+	HomeDir    string `short:"A" long:"appdata" description:"Path to application home directory"`
+	ConfigFile string `short:"C" long:"configfile" description:"Path to configuration file"`
+	DataDir    string `short:"b" long:"datadir" description:"Directory to store data"`
+	LogDir     string `long:"logdir" description:"Directory to log output"`
+	SimNet     bool   `long:"simnet" description:"Use the simulation test network instead of the test network"`
+	RegNet     bool   `long:"regnet" description:"Use the regression test network instead of the test network"`
+	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+
+	// HTTP server options.
+	Listeners  []string `long:"listen" description:"Add an interface/port to listen for HTTP connections (default: :5000)"`
+	TrustProxy bool     `long:"trustproxy" description:"Use the client address from the X-Forwarded-For header for rate limiting.  Only enable this when running behind a reverse proxy that sets the header"`
+
+	// Wallet RPC options.
+	WalletRPCConnect string `long:"walletrpcconnect" description:"Hostname/IP and port of the wallet JSON-RPC server (default port: 19110)"`
+	WalletRPCUser    string `long:"walletrpcuser" description:"Username for wallet RPC connections"`
+	WalletRPCPass    string `long:"walletrpcpass" default-mask:"-" description:"Password for wallet RPC connections"`
+	WalletRPCCert    string `long:"walletrpccert" description:"File containing the wallet RPC certificate"`
+	WalletAccount    string `long:"walletaccount" description:"Wallet account whose spendable balance is reported.  Payments are sent with sendtoaddress which spends from the default account"`
+
+	// Payout and rate limiting options.
+	Amount          float64       `long:"amount" description:"Amount in VGL sent per request"`
+	AddressCooldown time.Duration `long:"addresscooldown" description:"Minimum time between payments to the same address.  Valid time units are {s, m, h}"`
+	IPRequests      int           `long:"iprequests" description:"Maximum number of requests per client IP address within the --ipperiod"`
+	IPPeriod        time.Duration `long:"ipperiod" description:"Period over which --iprequests is enforced.  Valid time units are {s, m, h}"`
+	LowBalance      float64       `long:"lowbalance" description:"Spendable balance in VGL below which the faucet reports a low balance"`
+
+	// Cooked options ready for use.
+	params     *params
+	amount     VGLutil.Amount
+	lowBalance VGLutil.Amount
+}
+
+// cleanAndExpandPath expands environment variables and leading ~ in the
+// passed path, cleans the result, and returns it.
+func cleanAndExpandPath(path string) string {
+	// Nothing to do when no path is given.
+	if path == "" {
+		return path
+	}
+
+	// NOTE: The os.ExpandEnv doesn't work with Windows cmd.exe-style
+	// %VARIABLE%, but the variables can still be expanded via POSIX-style
+	// $VARIABLE.
+	path = os.ExpandEnv(path)
+
+	if !strings.HasPrefix(path, "~") {
+		return filepath.Clean(path)
+	}
+
+	// Expand initial ~ to the current user's home directory, or ~otheruser
+	// to otheruser's home directory.  On Windows, both forward and backward
+	// slashes can be used.
+	path = path[1:]
+
+	var pathSeparators string
+	if runtime.GOOS == "windows" {
+		pathSeparators = string(os.PathSeparator) + "/"
+	} else {
+		pathSeparators = string(os.PathSeparator)
+	}
+
+	userName := ""
+	if i := strings.IndexAny(path, pathSeparators); i != -1 {
+		userName = path[:i]
+		path = path[i:]
+	}
+
+	homeDir := ""
+	var u *user.User
+	var err error
+	if userName == "" {
+		u, err = user.Current()
+	} else {
+		u, err = user.Lookup(userName)
+	}
+	if err == nil {
+		homeDir = u.HomeDir
+	}
+	// Fallback to CWD if user lookup fails or user has no home directory.
+	if homeDir == "" {
+		homeDir = "."
+	}
+
+	return filepath.Join(homeDir, path)
+}
+
+// validLogLevel returns whether or not logLevel is a valid debug log level.
+func validLogLevel(logLevel string) bool {
+	_, ok := slog.LevelFromString(logLevel)
+	return ok
+}
+
+// supportedSubsystems returns a sorted slice of the supported subsystems for
+// logging purposes.
+func supportedSubsystems() []string {
+	// Convert the subsystemLoggers map keys to a slice.
+	subsystems := make([]string, 0, len(subsystemLoggers))
+	for subsysID := range subsystemLoggers {
+		subsystems = append(subsystems, subsysID)
+	}
+
+	// Sort the subsystems for stable display.
+	sort.Strings(subsystems)
+	return subsystems
+}
+
+// parseAndSetDebugLevels attempts to parse the specified debug level and set
+// the levels accordingly.  An appropriate error is returned if anything is
+// invalid.
+func parseAndSetDebugLevels(debugLevel string) error {
+	// When the specified string doesn't have any delimiters, treat it as
+	// the log level for all subsystems.
+	if !strings.Contains(debugLevel, ",") && !strings.Contains(debugLevel, "=") {
+		// Validate debug log level.
+		if !validLogLevel(debugLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, debugLevel)
+		}
+
+		// Change the logging level for all subsystems.
+		setLogLevels(debugLevel)
+
+		return nil
+	}
+
+	// Split the specified string into subsystem/level pairs while detecting
+	// issues and update the log levels accordingly.
+	for _, logLevelPair := range strings.Split(debugLevel, ",") {
+		if !strings.Contains(logLevelPair, "=") {
+			str := "the specified debug level contains an invalid " +
+				"subsystem/level pair [%v]"
+			return fmt.Errorf(str, logLevelPair)
+		}
+
+		// Extract the specified subsystem and log level.
+		fields := strings.Split(logLevelPair, "=")
+		subsysID, logLevel := fields[0], fields[1]
+
+		// Validate subsystem.
+		if _, exists := subsystemLoggers[subsysID]; !exists {
+			str := "the specified subsystem [%v] is invalid -- " +
+				"supported subsystems %v"
+			return fmt.Errorf(str, subsysID, supportedSubsystems())
+		}
+
+		// Validate log level.
+		if !validLogLevel(logLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, logLevel)
+		}
+
+		setLogLevel(subsysID, logLevel)
+	}
+
+	return nil
+}
+
+// normalizeAddress returns addr with the passed default port appended if there
+// is not already a port specified.
+func normalizeAddress(addr, defaultPort string) string {
+	_, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return net.JoinHostPort(addr, defaultPort)
+	}
+	return addr
+}
+
+// fileExists reports whether the named file or directory exists.
+func fileExists(name string) bool {
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadConfig initializes and parses the config using a config file and command
+// line options.
+//
+// The configuration proceeds as follows:
+//  1. Start with a default config with sane settings
+//  2. Pre-parse the command line to check for an alternative config file
+//  3. Load configuration file overwriting defaults with any specified options
+//  4. Parse CLI options and overwrite/add any specified options
+//
+// The above results in vglfaucet functioning properly without any config
+// settings while still allowing the user to override settings with config
+// files and command line options.  Command line options always take
+// precedence.
+func loadConfig() (*config, error) {
+	// Default config.
+	cfg := config{
+		HomeDir:         defaultHomeDir,
+		ConfigFile:      defaultConfigFile,
+		DebugLevel:      defaultLogLevel,
+		WalletRPCCert:   defaultWalletRPCCertFile,
+		WalletAccount:   defaultWalletAccount,
+		Amount:          defaultAmount,
+		AddressCooldown: defaultAddressCooldown,
+		IPRequests:      defaultIPRequests,
+		IPPeriod:        defaultIPPeriod,
+		LowBalance:      defaultLowBalance,
+	}
+
+	// Pre-parse the command line options to see if an alternative config
+	// file or home directory was specified.  Any errors aside from the help
+	// message error can be ignored here since they will be caught by the
+	// final parse below.
+	preCfg := cfg
+	preParser := flags.NewParser(&preCfg, flags.HelpFlag)
+	_, err := preParser.Parse()
+	if err != nil {
+		var e *flags.Error
+		if errors.As(err, &e) && e.Type == flags.ErrHelp {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+	}
+
+	// Update the home directory and config file location if specified.
+	if preCfg.HomeDir != defaultHomeDir {
+		cfg.HomeDir = cleanAndExpandPath(preCfg.HomeDir)
+		if preCfg.ConfigFile == defaultConfigFile {
+			preCfg.ConfigFile = filepath.Join(cfg.HomeDir,
+				defaultConfigFilename)
+		}
+	}
+
+	// Load additional config from file.
+	parser := flags.NewParser(&cfg, flags.Default)
+	configFile := cleanAndExpandPath(preCfg.ConfigFile)
+	if fileExists(configFile) {
+		err := flags.NewIniParser(parser).ParseFile(configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+			return nil, err
+		}
+	}
+
+	// Parse command line options again to ensure they take precedence.
+	_, err = parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// The faucet only runs on the test networks, defaulting to testnet.
+	// Multiple networks can't be selected simultaneously.
+	funcName := "loadConfig"
+	cfg.params = &testNet3Params
+	if cfg.SimNet && cfg.RegNet {
+		str := "%s: the regnet and simnet params can't be used together " +
+			"-- choose one of the two"
+		return nil, fmt.Errorf(str, funcName)
+	}
+	if cfg.SimNet {
+		cfg.params = &simNetParams
+	}
+	if cfg.RegNet {
+		cfg.params = &regNetParams
+	}
+
+	// Namespace the data and log directories per network.
+	if cfg.DataDir == "" {
+		cfg.DataDir = filepath.Join(cfg.HomeDir, defaultDataDirname)
+	}
+	cfg.DataDir = filepath.Join(cleanAndExpandPath(cfg.DataDir),
+		cfg.params.Name)
+	if cfg.LogDir == "" {
+		cfg.LogDir = filepath.Join(cfg.HomeDir, defaultLogDirname)
+	}
+	cfg.LogDir = filepath.Join(cleanAndExpandPath(cfg.LogDir), cfg.params.Name)
+
+	// Special show command to list supported subsystems and exit.
+	if cfg.DebugLevel == "show" {
+		fmt.Println("Supported subsystems", supportedSubsystems())
+		os.Exit(0)
+	}
+
+	// Initialize log rotation.  After log rotation has been initialized, the
+	// logger variables may be used.
+	initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename))
+
+	// Parse, validate, and set debug log level(s).
+	if err := parseAndSetDebugLevels(cfg.DebugLevel); err != nil {
+		return nil, fmt.Errorf("%s: %w", funcName, err)
+	}
+
+	// Listen on all interfaces on the default port by default.
+	if len(cfg.Listeners) == 0 {
+		cfg.Listeners = []string{defaultListen}
+	}
+
+	// Connect to the wallet on localhost with the default port for the
+	// active network by default.
+	if cfg.WalletRPCConnect == "" {
+		cfg.WalletRPCConnect = "localhost"
+	}
+	cfg.WalletRPCConnect = normalizeAddress(cfg.WalletRPCConnect,
+		cfg.params.walletRPCPort)
+	cfg.WalletRPCCert = cleanAndExpandPath(cfg.WalletRPCCert)
+
+	// Validate the payout and rate limiting options.
+	cfg.amount, err = VGLutil.NewAmount(cfg.Amount)
+	if err != nil || cfg.amount <= 0 {
+		str := "%s: the amount option must be a positive amount " +
+			"-- parsed [%v]"
+		return nil, fmt.Errorf(str, funcName, cfg.Amount)
+	}
+	cfg.lowBalance, err = VGLutil.NewAmount(cfg.LowBalance)
+	if err != nil || cfg.lowBalance < 0 {
+		str := "%s: the lowbalance option may not be negative " +
+			"-- parsed [%v]"
+		return nil, fmt.Errorf(str, funcName, cfg.LowBalance)
+	}
+	if cfg.AddressCooldown < 0 {
+		str := "%s: the addresscooldown option may not be negative " +
+			"-- parsed [%v]"
+		return nil, fmt.Errorf(str, funcName, cfg.AddressCooldown)
+	}
+	if cfg.IPRequests < 1 {
+		str := "%s: the iprequests option may not be less than 1 " +
+			"-- parsed [%d]"
+		return nil, fmt.Errorf(str, funcName, cfg.IPRequests)
+	}
+	if cfg.IPPeriod < 0 {
+		str := "%s: the ipperiod option may not be negative " +
+			"-- parsed [%v]"
+		return nil, fmt.Errorf(str, funcName, cfg.IPPeriod)
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+vglfaucet is a web faucet that pays testnet VGL to requested addresses.
+
+It serves a web page for requesting coins and sends payments with the
+sendtoaddress RPC of a wallet.  Requested addresses are validated for the
+active test network, and requests are limited per address and per client IP
+address.  The times of recent requests are stored in a database in the data
+directory so the limits persist across restarts.  IPv6 clients are limited by
+their /64 prefix.
+
+The faucet only runs on the test networks.  It defaults to testnet and can be
+used with simnet or regnet for development.
+
+The following endpoints are provided:
+
+	GET  /             The faucet web page.
+	GET  /api/status   The payout amount, rate limits, and spendable balance
+	                   of the wallet account as JSON.  The status code is 503
+	                   when the balance is below --lowbalance or the payout
+	                   amount, or when it can't be determined.
+	POST /api/request  Sends a payment to the address given by the address
+	                   field of a JSON body or form.  Responds with the txid
+	                   and amount as JSON, or with status 429 and the number of
+	                   seconds until another request is allowed when rate
+	                   limited.
+
+Usage:
+
+	vglfaucet [OPTIONS]
+
+Application Options:
+
+	-A, --appdata=           Path to application home directory
+	-C, --configfile=        Path to configuration file
+	-b, --datadir=           Directory to store data
+	    --logdir=            Directory to log output
+	    --simnet             Use the simulation test network instead of the
+	                         test network
+	    --regnet             Use the regression test network instead of the
+	                         test network
+	-d, --debuglevel=        Logging level for all subsystems {trace, debug,
+	                         info, warn, error, critical} (info)
+	    --listen=            Add an interface/port to listen for HTTP
+	                         connections (default: :5000)
+	    --trustproxy         Use the client address from the X-Forwarded-For
+	                         header for rate limiting.  Only enable this when
+	                         running behind a reverse proxy that sets the
+	                         header
+	    --walletrpcconnect=  Hostname/IP and port of the wallet JSON-RPC
+	                         server (default port: 19110)
+	    --walletrpcuser=     Username for wallet RPC connections
+	    --walletrpcpass=     Password for wallet RPC connections
+	    --walletrpccert=     File containing the wallet RPC certificate
+	    --walletaccount=     Wallet account whose spendable balance is
+	                         reported (default)
+	    --amount=            Amount in VGL sent per request (10)
+	    --addresscooldown=   Minimum time between payments to the same
+	                         address (24h0m0s)
+	    --iprequests=        Maximum number of requests per client IP address
+	                         within the --ipperiod (5)
+	    --ipperiod=          Period over which --iprequests is enforced
+	                         (24h0m0s)
+	    --lowbalance=        Spendable balance in VGL below which the faucet
+	                         reports a low balance (1000)
+
+Help Options:
+
+	-h, --help               Show this help message
+*/
+package main
@@ -0,0 +1,176 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/binary"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// ipBucketName is the name of the bucket that houses the times of recent
+	// requests keyed by client IP address.
+	ipBucketName = []byte("ip")
+
+	// addrBucketName is the name of the bucket that houses the times of
+	// recent requests keyed by payment address.
+	addrBucketName = []byte("address")
+)
+
+// limit describes the maximum number of requests allowed per period.
+type limit struct {
+	requests int
+	period   time.Duration
+}
+
+// rateLimiter enforces per IP and per address request limits.  The times of
+// recent requests are stored in a bolt database so the limits persist across
+// restarts.
+type rateLimiter struct {
+	db        *bolt.DB
+	ipLimit   limit
+	addrLimit limit
+}
+
+// openRateLimiter opens the rate limit database at the provided path, creating
+// it if needed, and returns a rate limiter that enforces the provided limits.
+func openRateLimiter(path string, ipLimit, addrLimit limit) (*rateLimiter, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{ipBucketName, addrBucketName} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &rateLimiter{db: db, ipLimit: ipLimit, addrLimit: addrLimit}, nil
+}
+
+// close closes the rate limit database.
+func (l *rateLimiter) close() error {
+	return l.db.Close()
+}
+
+// decodeTimes decodes the request times serialized by encodeTimes.
+func decodeTimes(b []byte) []time.Time {
+	times := make([]time.Time, 0, len(b)/8)
+	for ; len(b) >= 8; b = b[8:] {
+		nanos := int64(binary.BigEndian.Uint64(b))
+		times = append(times, time.Unix(0, nanos))
+	}
+	return times
+}
+
+// encodeTimes serializes the provided request times.
+func encodeTimes(times []time.Time) []byte {
+	b := make([]byte, 0, len(times)*8)
+	for _, t := range times {
+		b = binary.BigEndian.AppendUint64(b, uint64(t.UnixNano()))
+	}
+	return b
+}
+
+// recentTimes returns the provided request times that count towards the limit
+// at the provided time.
+func recentTimes(times []time.Time, lim limit, now time.Time) []time.Time {
+	recent := times[:0]
+	for _, t := range times {
+		if now.Sub(t) < lim.period {
+			recent = append(recent, t)
+		}
+	}
+	return recent
+}
+
+// waitTime returns how long until another request is allowed given the
+// provided times of previous requests, or zero when one is allowed now.
+func waitTime(times []time.Time, lim limit, now time.Time) time.Duration {
+	recent := recentTimes(times, lim, now)
+	if len(recent) < lim.requests {
+		return 0
+	}
+
+	// The request that frees up a slot is the one that makes the number of
+	// remaining recent requests drop below the limit once it expires.
+	return recent[len(recent)-lim.requests].Add(lim.period).Sub(now)
+}
+
+// check returns how long the client with the provided IP address must wait
+// before requesting a payment to the provided address, or zero when the
+// request is allowed at the provided time.
+func (l *rateLimiter) check(ip, addr string, now time.Time) (time.Duration, error) {
+	var wait time.Duration
+	err := l.db.View(func(tx *bolt.Tx) error {
+		ipTimes := decodeTimes(tx.Bucket(ipBucketName).Get([]byte(ip)))
+		addrTimes := decodeTimes(tx.Bucket(addrBucketName).Get([]byte(addr)))
+		wait = waitTime(ipTimes, l.ipLimit, now)
+		if addrWait := waitTime(addrTimes, l.addrLimit, now); addrWait > wait {
+			wait = addrWait
+		}
+		return nil
+	})
+	return wait, err
+}
+
+// record records a request by the client with the provided IP address for a
+// payment to the provided address at the provided time.  Request times that
+// no longer count towards the limits are removed.
+func (l *rateLimiter) record(ip, addr string, now time.Time) error {
+	return l.db.Update(func(tx *bolt.Tx) error {
+		update := func(bucketName []byte, key string, lim limit) error {
+			bucket := tx.Bucket(bucketName)
+			times := decodeTimes(bucket.Get([]byte(key)))
+			times = append(recentTimes(times, lim, now), now)
+			return bucket.Put([]byte(key), encodeTimes(times))
+		}
+		if err := update(ipBucketName, ip, l.ipLimit); err != nil {
+			return err
+		}
+		return update(addrBucketName, addr, l.addrLimit)
+	})
+}
+
+// prune removes all entries whose requests no longer count towards the limits
+// at the provided time.  It returns the number of removed entries.
+func (l *rateLimiter) prune(now time.Time) (int, error) {
+	var n int
+	err := l.db.Update(func(tx *bolt.Tx) error {
+		prune := func(bucketName []byte, lim limit) error {
+			bucket := tx.Bucket(bucketName)
+			var stale [][]byte
+			err := bucket.ForEach(func(k, v []byte) error {
+				if len(recentTimes(decodeTimes(v), lim, now)) == 0 {
+					stale = append(stale, append([]byte(nil), k...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range stale {
+				if err := bucket.Delete(k); err != nil {
+					return err
+				}
+			}
+			n += len(stale)
+			return nil
+		}
+		if err := prune(ipBucketName, l.ipLimit); err != nil {
+			return err
+		}
+		return prune(addrBucketName, l.addrLimit)
+	})
+	return n, err
+}
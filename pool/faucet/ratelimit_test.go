@@ -0,0 +1,118 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRateLimiter ensures the per IP and per address limits are enforced with
+// the expected wait times and persist across reopening the database.
+func TestRateLimiter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.db")
+	ipLimit := limit{requests: 2, period: time.Hour}
+	addrLimit := limit{requests: 1, period: time.Hour * 24}
+	l, err := openRateLimiter(path, ipLimit, addrLimit)
+	if err != nil {
+		t.Fatalf("unable to open rate limiter: %v", err)
+	}
+	defer func() { l.close() }()
+
+	base := time.Unix(1700000000, 0)
+	tests := []struct {
+		name   string
+		ip     string
+		addr   string
+		offset time.Duration
+		record bool
+		wait   time.Duration
+	}{{
+		name:   "first request",
+		ip:     "1",
+		addr:   "a",
+		record: true,
+	}, {
+		name:   "same address from another ip",
+		ip:     "2",
+		addr:   "a",
+		offset: time.Minute,
+		wait:   time.Hour*24 - time.Minute,
+	}, {
+		name:   "second request from ip",
+		ip:     "1",
+		addr:   "b",
+		offset: time.Minute * 10,
+		record: true,
+	}, {
+		name:   "ip limit reached",
+		ip:     "1",
+		addr:   "c",
+		offset: time.Minute * 20,
+		wait:   time.Minute * 40,
+	}, {
+		name:   "first ip request expired",
+		ip:     "1",
+		addr:   "c",
+		offset: time.Hour,
+		record: true,
+	}, {
+		name:   "ip limit reached again",
+		ip:     "1",
+		addr:   "d",
+		offset: time.Hour + time.Minute,
+		wait:   time.Minute * 9,
+	}, {
+		name:   "address cooldown expired",
+		ip:     "3",
+		addr:   "a",
+		offset: time.Hour * 24,
+	}}
+
+	for _, test := range tests {
+		now := base.Add(test.offset)
+		wait, err := l.check(test.ip, test.addr, now)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		if wait != test.wait {
+			t.Fatalf("%q: unexpected wait -- got %v, want %v", test.name,
+				wait, test.wait)
+		}
+		if test.record {
+			if err := l.record(test.ip, test.addr, now); err != nil {
+				t.Fatalf("%q: unexpected error recording: %v", test.name,
+					err)
+			}
+		}
+	}
+
+	// Ensure the limits persist across reopening the database.
+	if err := l.close(); err != nil {
+		t.Fatalf("unable to close rate limiter: %v", err)
+	}
+	l, err = openRateLimiter(path, ipLimit, addrLimit)
+	if err != nil {
+		t.Fatalf("unable to reopen rate limiter: %v", err)
+	}
+	now := base.Add(time.Hour * 2)
+	wait, err := l.check("4", "c", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Hour * 23; wait != want {
+		t.Fatalf("unexpected wait after reopen -- got %v, want %v", wait, want)
+	}
+
+	// Only the entries for address a and ip 1 have expired after a day.
+	n, err := l.prune(base.Add(time.Hour*24 + time.Minute*5))
+	if err != nil {
+		t.Fatalf("unexpected error pruning: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("unexpected number of pruned entries -- got %d, want 2", n)
+	}
+}
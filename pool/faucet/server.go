@@ -0,0 +1,374 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/rpcclient/v8"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+)
+
+const (
+	// maxRequestBodySize is the maximum size of the body of a payment
+	// request.
+	maxRequestBodySize = 1024
+
+	// pruneInterval is how often rate limit entries that no longer count
+	// towards the limits are removed.
+	pruneInterval = time.Hour
+
+	// shutdownTimeout is the maximum amount of time to wait for in-flight
+	// HTTP requests to complete on shutdown.
+	shutdownTimeout = time.Second * 10
+)
+
+// faucetPage is the web page served by the faucet.
+//
+//go:embed faucet.html
+var faucetPage []byte
+
+// statusResponse is the response to a status request.
+type statusResponse struct {
+	Network         string  `json:"network"`
+	Amount          float64 `json:"amount"`
+	AddressCooldown int64   `json:"addresscooldown"`
+	IPRequests      int     `json:"iprequests"`
+	IPPeriod        int64   `json:"ipperiod"`
+	Balance         float64 `json:"balance"`
+	LowBalance      float64 `json:"lowbalance"`
+	BalanceLow      bool    `json:"balancelow"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// payRequest is the body of a payment request.
+type payRequest struct {
+	Address string `json:"address"`
+}
+
+// payResponse is the response to a successful payment request.
+type payResponse struct {
+	TxID   string  `json:"txid"`
+	Amount float64 `json:"amount"`
+}
+
+// errorResponse is the response to a failed request.  RetryAfter is the number
+// of seconds until the request is allowed when it was rate limited.
+type errorResponse struct {
+	Error      string `json:"error"`
+	RetryAfter int64  `json:"retryafter,omitempty"`
+}
+
+// faucet houses the state of the faucet server.
+type faucet struct {
+	cfg     *config
+	wallet  *rpcclient.Client
+	limiter *rateLimiter
+
+	// payMtx serializes payments so concurrent requests can't exceed the
+	// rate limits between checking and recording them.
+	payMtx sync.Mutex
+}
+
+// newFaucet returns a new faucet that sends payments with the configured
+// wallet and enforces rate limits with the provided rate limiter.
+func newFaucet(cfg *config, limiter *rateLimiter) (*faucet, error) {
+	cert, err := os.ReadFile(cfg.WalletRPCCert)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read wallet RPC certificate: %w",
+			err)
+	}
+	wallet, err := rpcclient.New(&rpcclient.ConnConfig{
+		Host:         cfg.WalletRPCConnect,
+		User:         cfg.WalletRPCUser,
+		Pass:         cfg.WalletRPCPass,
+		Certificates: cert,
+		HTTPPostMode: true,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create wallet RPC client: %w", err)
+	}
+	return &faucet{cfg: cfg, wallet: wallet, limiter: limiter}, nil
+}
+
+// rawRequest performs the provided wallet JSON-RPC request with the provided
+// parameters and unmarshals the result into the provided result.
+func (f *faucet) rawRequest(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	rawParams := make([]json.RawMessage, 0, len(params))
+	for _, param := range params {
+		b, err := json.Marshal(param)
+		if err != nil {
+			return err
+		}
+		rawParams = append(rawParams, b)
+	}
+	rawResult, err := f.wallet.RawRequest(ctx, method, rawParams)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(rawResult, result); err != nil {
+		return fmt.Errorf("unexpected %s result %s: %w", method, rawResult,
+			err)
+	}
+	return nil
+}
+
+// spendableBalance returns the spendable balance of the configured wallet
+// account.
+func (f *faucet) spendableBalance(ctx context.Context) (VGLutil.Amount, error) {
+	var result struct {
+		Balances []struct {
+			AccountName string  `json:"accountname"`
+			Spendable   float64 `json:"spendable"`
+		} `json:"balances"`
+	}
+	err := f.rawRequest(ctx, "getbalance", &result, f.cfg.WalletAccount, 1)
+	if err != nil {
+		return 0, err
+	}
+	for _, balance := range result.Balances {
+		if balance.AccountName == f.cfg.WalletAccount {
+			return VGLutil.NewAmount(balance.Spendable)
+		}
+	}
+	return 0, fmt.Errorf("wallet account %q not found", f.cfg.WalletAccount)
+}
+
+// clientIP returns the IP address of the client that made the provided
+// request for the purposes of rate limiting.  IPv6 addresses are truncated to
+// their /64 prefix since that is typically assigned to a single client.
+func (f *faucet) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if f.cfg.TrustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			host = strings.TrimSpace(first)
+		}
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host
+	}
+	if ip.To4() == nil {
+		ip = ip.Mask(net.CIDRMask(64, 128))
+	}
+	return ip.String()
+}
+
+// writeJSON writes the provided value as a JSON response with the provided
+// HTTP status code.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		faucetLog.Debugf("Unable to write response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response with the provided HTTP status code
+// and message.
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, &errorResponse{Error: msg})
+}
+
+// handlePage serves the faucet web page.
+func (f *faucet) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(faucetPage)
+}
+
+// handleStatus reports the faucet settings and whether the spendable balance
+// of the wallet is low.  The response has status 503 when the balance is low
+// or can't be determined so it can be used directly by monitoring services.
+func (f *faucet) handleStatus(w http.ResponseWriter, r *http.Request) {
+	resp := &statusResponse{
+		Network:         f.cfg.params.Name,
+		Amount:          f.cfg.amount.ToCoin(),
+		AddressCooldown: int64(f.cfg.AddressCooldown / time.Second),
+		IPRequests:      f.cfg.IPRequests,
+		IPPeriod:        int64(f.cfg.IPPeriod / time.Second),
+		LowBalance:      f.cfg.lowBalance.ToCoin(),
+	}
+	code := http.StatusOK
+	balance, err := f.spendableBalance(r.Context())
+	if err != nil {
+		faucetLog.Errorf("Unable to get wallet balance: %v", err)
+		resp.Error = "unable to determine faucet balance"
+		resp.BalanceLow = true
+		code = http.StatusServiceUnavailable
+	} else {
+		resp.Balance = balance.ToCoin()
+		if balance < f.cfg.lowBalance || balance < f.cfg.amount {
+			resp.BalanceLow = true
+			code = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, code, resp)
+}
+
+// handleRequest sends a payment to the requested address subject to the rate
+// limits.  The address may be provided as a JSON body or a form value.
+func (f *faucet) handleRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	var req payRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "malformed request")
+			return
+		}
+	} else {
+		req.Address = r.FormValue("address")
+	}
+	req.Address = strings.TrimSpace(req.Address)
+	_, err := stdaddr.DecodeAddress(req.Address, f.cfg.params.Params)
+	if err != nil {
+		msg := fmt.Sprintf("invalid %s address", f.cfg.params.Name)
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	ip := f.clientIP(r)
+	f.payMtx.Lock()
+	defer f.payMtx.Unlock()
+
+	now := time.Now()
+	wait, err := f.limiter.check(ip, req.Address, now)
+	if err != nil {
+		faucetLog.Errorf("Unable to check rate limits: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if wait > 0 {
+		retryAfter := int64(math.Ceil(wait.Seconds()))
+		w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+		writeJSON(w, http.StatusTooManyRequests, &errorResponse{
+			Error:      "rate limit exceeded",
+			RetryAfter: retryAfter,
+		})
+		return
+	}
+
+	balance, err := f.spendableBalance(r.Context())
+	if err != nil {
+		faucetLog.Errorf("Unable to get wallet balance: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "faucet unavailable")
+		return
+	}
+	if balance < f.cfg.amount {
+		faucetLog.Warnf("Faucet balance %v is too low to pay %v", balance,
+			f.cfg.amount)
+		writeError(w, http.StatusServiceUnavailable, "faucet balance low")
+		return
+	}
+
+	var txid string
+	err = f.rawRequest(r.Context(), "sendtoaddress", &txid, req.Address,
+		f.cfg.amount.ToCoin())
+	if err != nil {
+		faucetLog.Errorf("Unable to send %v to %s: %v", f.cfg.amount,
+			req.Address, err)
+		writeError(w, http.StatusServiceUnavailable, "faucet unavailable")
+		return
+	}
+	if err := f.limiter.record(ip, req.Address, now); err != nil {
+		faucetLog.Errorf("Unable to record request: %v", err)
+	}
+
+	faucetLog.Infof("Sent %v to %s for %s in transaction %s", f.cfg.amount,
+		req.Address, ip, txid)
+	writeJSON(w, http.StatusOK, &payResponse{
+		TxID:   txid,
+		Amount: f.cfg.amount.ToCoin(),
+	})
+}
+
+// run serves the faucet on the provided listeners until the provided context
+// is cancelled.
+func (f *faucet) run(ctx context.Context, listeners []net.Listener) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", f.handlePage)
+	mux.HandleFunc("/api/status", f.handleStatus)
+	mux.HandleFunc("/api/request", f.handleRequest)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: time.Second * 10,
+		ReadTimeout:       time.Second * 30,
+		WriteTimeout:      time.Second * 60,
+	}
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		faucetLog.Infof("Faucet listening on %s", listener.Addr())
+		wg.Add(1)
+		go func(listener net.Listener) {
+			defer wg.Done()
+			err := srv.Serve(listener)
+			if !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}(listener)
+	}
+
+	pruneTicker := time.NewTicker(pruneInterval)
+	defer pruneTicker.Stop()
+	var runErr error
+out:
+	for {
+		select {
+		case <-pruneTicker.C:
+			n, err := f.limiter.prune(time.Now())
+			if err != nil {
+				faucetLog.Errorf("Unable to prune rate limits: %v", err)
+				continue
+			}
+			faucetLog.Debugf("Pruned %d expired rate limit entries", n)
+
+		case err := <-errCh:
+			runErr = err
+			break out
+
+		case <-ctx.Done():
+			break out
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(),
+		shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		faucetLog.Warnf("Unable to gracefully stop HTTP server: %v", err)
+	}
+	wg.Wait()
+	f.wallet.Shutdown()
+	f.wallet.WaitForShutdown()
+	return runErr
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// run is the real main function for vglfaucet.  It is necessary to work around
+// the fact that deferred functions do not run when os.Exit() is called.
+func run() error {
+	// Load configuration and parse command line.  This function also
+	// initializes logging and configures it accordingly.
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if logRotator != nil {
+			logRotator.Close()
+		}
+	}()
+
+	// Get a context that will be canceled when a shutdown signal has been
+	// triggered from an OS signal such as SIGINT (Ctrl+C).
+	ctx := shutdownListener()
+	defer faucetLog.Info("Shutdown complete")
+
+	faucetLog.Infof("Home dir: %s", cfg.HomeDir)
+	faucetLog.Infof("Paying %v per request on %s", cfg.amount,
+		cfg.params.Name)
+	if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+		faucetLog.Errorf("Unable to create data directory: %v", err)
+		return err
+	}
+
+	// Open the rate limit database.
+	ipLimit := limit{requests: cfg.IPRequests, period: cfg.IPPeriod}
+	addrLimit := limit{requests: 1, period: cfg.AddressCooldown}
+	dbPath := filepath.Join(cfg.DataDir, defaultDBFilename)
+	limiter, err := openRateLimiter(dbPath, ipLimit, addrLimit)
+	if err != nil {
+		faucetLog.Errorf("Unable to open rate limit database: %v", err)
+		return err
+	}
+	defer limiter.close()
+
+	// Return now if a shutdown signal was triggered.
+	if shutdownRequested(ctx) {
+		return nil
+	}
+
+	f, err := newFaucet(cfg, limiter)
+	if err != nil {
+		faucetLog.Errorf("%v", err)
+		return err
+	}
+	listeners := make([]net.Listener, 0, len(cfg.Listeners))
+	for _, addr := range cfg.Listeners {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			faucetLog.Errorf("Unable to listen on %s: %v", addr, err)
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
+	if err := f.run(ctx, listeners); err != nil {
+		faucetLog.Errorf("%v", err)
+		return err
+	}
+	return nil
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package payout implements the payout schemes used to distribute the rewards
+// of blocks found by the pool among the accounts that contributed shares.
+package payout
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Scheme identifies a payout scheme.
+type Scheme string
+
+// These constants define the supported payout schemes.
+const (
+	// PPLNS (pay per last N shares) distributes the reward of a block among
+	// the shares submitted during a fixed period prior to finding the block,
+	// regardless of when the previous block was found.  This discourages
+	// pool hopping since shares continue to be rewarded across blocks.
+	PPLNS Scheme = "pplns"
+
+	// PROP (proportional) distributes the reward of a block among the shares
+	// submitted since the previous block was found.
+	PROP Scheme = "prop"
+)
+
+// ParseScheme returns the payout scheme identified by the provided string.
+func ParseScheme(s string) (Scheme, error) {
+	switch scheme := Scheme(s); scheme {
+	case PPLNS, PROP:
+		return scheme, nil
+	}
+	return "", fmt.Errorf("unknown payout scheme %q", s)
+}
+
+// WindowStart returns the start of the period of shares that are rewarded for
+// a block found at the provided time.  Only shares submitted after the start
+// of the period up to and including the time the block was found are
+// rewarded.
+//
+// For PPLNS, the period is the provided last N period prior to finding the
+// block.  For PROP, the period begins when the previous block was found, or
+// the last N period prior to finding the block when no previous block was
+// found, since shares older than that are not retained.
+func WindowStart(scheme Scheme, foundAt, prevFoundAt time.Time, lastNPeriod time.Duration) time.Time {
+	start := foundAt.Add(-lastNPeriod)
+	if scheme == PROP && prevFoundAt.After(start) {
+		return prevFoundAt
+	}
+	return start
+}
+
+// Credits distributes the provided reward, in atoms, among the accounts
+// proportionally to their provided total share weights after deducting the
+// provided pool fee percentage.  The fee, as well as any remainder due to
+// rounding, is credited to the provided fee account.  The entire reward is
+// credited to the fee account when there are no shares.
+//
+// The sum of the returned credits is always equal to the reward.
+func Credits(reward int64, feePercent float64, feeAccount string, weights map[string]float64) map[string]int64 {
+	credits := make(map[string]int64, len(weights)+1)
+	if reward <= 0 {
+		return credits
+	}
+
+	var totalWeight float64
+	accounts := make([]string, 0, len(weights))
+	for account, weight := range weights {
+		if weight > 0 {
+			accounts = append(accounts, account)
+			totalWeight += weight
+		}
+	}
+	sort.Strings(accounts)
+
+	// Calculate the fee with integer math on the percentage in hundredths of
+	// a percent to avoid rounding surprises.
+	feeBasisPoints := int64(feePercent*100 + 0.5)
+	fee := new(big.Int).Mul(big.NewInt(reward), big.NewInt(feeBasisPoints))
+	fee.Quo(fee, big.NewInt(100*100))
+	remaining := reward - fee.Int64()
+
+	var distributed int64
+	if totalWeight > 0 {
+		total := new(big.Float).SetFloat64(totalWeight)
+		for _, account := range accounts {
+			share := new(big.Float).SetInt64(remaining)
+			share.Mul(share, new(big.Float).SetFloat64(weights[account]))
+			share.Quo(share, total)
+			amount, _ := share.Int64()
+			if amount > remaining-distributed {
+				amount = remaining - distributed
+			}
+			if amount <= 0 {
+				continue
+			}
+			credits[account] += amount
+			distributed += amount
+		}
+	}
+	credits[feeAccount] += reward - distributed
+	return credits
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package payout
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestCredits ensures rewards are distributed proportionally to share weights
+// with the fee and rounding remainder credited to the fee account.
+func TestCredits(t *testing.T) {
+	tests := []struct {
+		name       string
+		reward     int64
+		feePercent float64
+		weights    map[string]float64
+		want       map[string]int64
+	}{{
+		name:       "no shares",
+		reward:     1000,
+		feePercent: 1,
+		want:       map[string]int64{"fee": 1000},
+	}, {
+		name:       "single account no fee",
+		reward:     1000,
+		feePercent: 0,
+		weights:    map[string]float64{"a": 5},
+		want:       map[string]int64{"a": 1000, "fee": 0},
+	}, {
+		name:       "proportional with fee",
+		reward:     10000,
+		feePercent: 2,
+		weights:    map[string]float64{"a": 3, "b": 1},
+		want:       map[string]int64{"a": 7350, "b": 2450, "fee": 200},
+	}, {
+		name:       "rounding remainder to fee account",
+		reward:     100,
+		feePercent: 0,
+		weights:    map[string]float64{"a": 1, "b": 1, "c": 1},
+		want:       map[string]int64{"a": 33, "b": 33, "c": 33, "fee": 1},
+	}, {
+		name:       "fractional fee percent",
+		reward:     100000,
+		feePercent: 0.5,
+		weights:    map[string]float64{"a": 1},
+		want:       map[string]int64{"a": 99500, "fee": 500},
+	}, {
+		name:       "zero weights ignored",
+		reward:     100,
+		feePercent: 0,
+		weights:    map[string]float64{"a": 1, "b": 0},
+		want:       map[string]int64{"a": 100, "fee": 0},
+	}}
+
+	for _, test := range tests {
+		got := Credits(test.reward, test.feePercent, "fee", test.weights)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: unexpected credits -- got %v, want %v", test.name,
+				got, test.want)
+			continue
+		}
+		var sum int64
+		for _, amount := range got {
+			sum += amount
+		}
+		if sum != test.reward {
+			t.Errorf("%s: credits sum to %d, want %d", test.name, sum,
+				test.reward)
+		}
+	}
+}
+
+// TestWindowStart ensures the start of the rewarded share period is calculated
+// as expected for each payout scheme.
+func TestWindowStart(t *testing.T) {
+	foundAt := time.Unix(1700000000, 0)
+	lastN := time.Hour
+	recent := foundAt.Add(-time.Minute * 10)
+	old := foundAt.Add(-time.Hour * 2)
+
+	tests := []struct {
+		name        string
+		scheme      Scheme
+		prevFoundAt time.Time
+		want        time.Time
+	}{{
+		name:        "pplns ignores previous block",
+		scheme:      PPLNS,
+		prevFoundAt: recent,
+		want:        foundAt.Add(-lastN),
+	}, {
+		name:        "prop since recent previous block",
+		scheme:      PROP,
+		prevFoundAt: recent,
+		want:        recent,
+	}, {
+		name:        "prop limited to last n period",
+		scheme:      PROP,
+		prevFoundAt: old,
+		want:        foundAt.Add(-lastN),
+	}, {
+		name:   "prop without previous block",
+		scheme: PROP,
+		want:   foundAt.Add(-lastN),
+	}}
+
+	for _, test := range tests {
+		got := WindowStart(test.scheme, foundAt, test.prevFoundAt, lastN)
+		if !got.Equal(test.want) {
+			t.Errorf("%s: unexpected window start -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+
+	if _, err := ParseScheme("pps"); err == nil {
+		t.Fatal("expected error for unknown scheme")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package sharedb
+
+// ErrorKind identifies a kind of error.  It has full support for errors.Is and
+// errors.As, so the caller can directly check against an error kind when
+// determining the reason for an error.
+type ErrorKind string
+
+// These constants are used to identify a specific Error.
+const (
+	// ErrBlockNotFound indicates a block is not recorded in the database.
+	ErrBlockNotFound = ErrorKind("ErrBlockNotFound")
+
+	// ErrBlockExists indicates an attempt to record a block that is already
+	// recorded in the database.
+	ErrBlockExists = ErrorKind("ErrBlockExists")
+
+	// ErrBlockCredited indicates an attempt to credit or change the status of
+	// a block that has already been credited.
+	ErrBlockCredited = ErrorKind("ErrBlockCredited")
+
+	// ErrInsufficientBalance indicates an attempt to record a payment that
+	// exceeds the balance of an account.
+	ErrInsufficientBalance = ErrorKind("ErrInsufficientBalance")
+
+	// ErrBadVersion indicates the database was created by a newer version of
+	// the software.
+	ErrBadVersion = ErrorKind("ErrBadVersion")
+)
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e ErrorKind) Error() string {
+	return string(e)
+}
+
+// Error identifies an error related to the share database.  It has full
+// support for errors.Is and errors.As, so the caller can ascertain the specific
+// reason for the error by checking the underlying error.
+type Error struct {
+	Description string
+	Err         error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e Error) Error() string {
+	return e.Description
+}
+
+// Unwrap returns the underlying wrapped error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// makeError creates an Error given a set of arguments.
+func makeError(kind ErrorKind, desc string) Error {
+	return Error{Err: kind, Description: desc}
+}
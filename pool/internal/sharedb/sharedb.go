@@ -0,0 +1,393 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package sharedb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// dbVersion is the current version of the database layout.
+	dbVersion = 1
+
+	// shareKeySize is the size of the keys in the shares and payments
+	// buckets.  They consist of the big-endian time in nanoseconds since the
+	// unix epoch followed by a big-endian sequence number so that iteration
+	// is in time order and keys are unique.
+	shareKeySize = 16
+)
+
+var (
+	metaBucket     = []byte("meta")
+	sharesBucket   = []byte("shares")
+	blocksBucket   = []byte("blocks")
+	balanceBucket  = []byte("balances")
+	paymentsBucket = []byte("payments")
+	versionKey     = []byte("version")
+)
+
+// BlockStatus identifies the state of a block found by the pool.
+type BlockStatus string
+
+// These constants define the possible block statuses.
+const (
+	// BlockPending indicates the block has not yet reached coinbase maturity.
+	BlockPending BlockStatus = "pending"
+
+	// BlockOrphaned indicates the block is no longer part of the main chain.
+	BlockOrphaned BlockStatus = "orphaned"
+
+	// BlockCredited indicates the block reached coinbase maturity and its
+	// reward was credited to the balances of the participating accounts.
+	BlockCredited BlockStatus = "credited"
+)
+
+// Share is a share submitted by a miner.  The weight is the difficulty of the
+// share.
+type Share struct {
+	Account string    `json:"account"`
+	Weight  float64   `json:"weight"`
+	Time    time.Time `json:"-"`
+}
+
+// Block is a block found by the pool.
+type Block struct {
+	Hash    string      `json:"hash"`
+	Height  int64       `json:"height"`
+	Worker  string      `json:"worker"`
+	FoundAt time.Time   `json:"foundat"`
+	Status  BlockStatus `json:"status"`
+	Reward  int64       `json:"reward"`
+}
+
+// Payment is a payment sent to one or more accounts in a single transaction.
+// The amounts are in atoms and keyed by account.
+type Payment struct {
+	TxID    string           `json:"txid"`
+	Amounts map[string]int64 `json:"amounts"`
+	Time    time.Time        `json:"-"`
+}
+
+// DB houses the shares, found blocks, balances, and payments of the pool.
+type DB struct {
+	bdb *bolt.DB
+}
+
+// Open opens the database at the provided path, creating it when it does not
+// exist.
+func Open(path string) (*DB, error) {
+	bdb, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = bdb.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{metaBucket, sharesBucket,
+			blocksBucket, balanceBucket, paymentsBucket} {
+
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		meta := tx.Bucket(metaBucket)
+		v := meta.Get(versionKey)
+		if v == nil {
+			var b [4]byte
+			binary.BigEndian.PutUint32(b[:], dbVersion)
+			return meta.Put(versionKey, b[:])
+		}
+		if len(v) != 4 || binary.BigEndian.Uint32(v) > dbVersion {
+			str := fmt.Sprintf("share database version %x is newer than "+
+				"the supported version %d", v, dbVersion)
+			return makeError(ErrBadVersion, str)
+		}
+		return nil
+	})
+	if err != nil {
+		bdb.Close()
+		return nil, err
+	}
+	return &DB{bdb: bdb}, nil
+}
+
+// Close closes the database.
+func (db *DB) Close() error {
+	return db.bdb.Close()
+}
+
+// timeKey returns a key for the shares and payments buckets for the provided
+// time and sequence number.
+func timeKey(t time.Time, seq uint64) []byte {
+	key := make([]byte, shareKeySize)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	binary.BigEndian.PutUint64(key[8:], seq)
+	return key
+}
+
+// keyTime returns the time encoded in the provided shares or payments bucket
+// key.
+func keyTime(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key)))
+}
+
+// AddShare records the provided share.
+func (db *DB) AddShare(share *Share) error {
+	v, err := json.Marshal(share)
+	if err != nil {
+		return err
+	}
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sharesBucket)
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		return bucket.Put(timeKey(share.Time, seq), v)
+	})
+}
+
+// Shares returns all shares submitted after the provided start time up to and
+// including the provided end time in the order they were submitted.
+func (db *DB) Shares(start, end time.Time) ([]Share, error) {
+	var shares []Share
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(sharesBucket).Cursor()
+		startKey := timeKey(start, ^uint64(0))
+		for k, v := c.Seek(startKey); k != nil; k, v = c.Next() {
+			t := keyTime(k)
+			if t.After(end) {
+				break
+			}
+			var share Share
+			if err := json.Unmarshal(v, &share); err != nil {
+				return err
+			}
+			share.Time = t
+			shares = append(shares, share)
+		}
+		return nil
+	})
+	return shares, err
+}
+
+// PruneShares removes all shares submitted before the provided time and
+// returns the number of removed shares.
+func (db *DB) PruneShares(before time.Time) (int, error) {
+	var n int
+	err := db.bdb.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(sharesBucket).Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.First() {
+			if !keyTime(k).Before(before) {
+				break
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// fetchBlock loads the block with the provided hash from the blocks bucket.
+func fetchBlock(bucket *bolt.Bucket, hash string) (*Block, error) {
+	v := bucket.Get([]byte(hash))
+	if v == nil {
+		str := fmt.Sprintf("block %s is not recorded", hash)
+		return nil, makeError(ErrBlockNotFound, str)
+	}
+	var block Block
+	if err := json.Unmarshal(v, &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// putBlock stores the provided block in the blocks bucket.
+func putBlock(bucket *bolt.Bucket, block *Block) error {
+	v, err := json.Marshal(block)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(block.Hash), v)
+}
+
+// AddBlock records the provided block found by the pool.
+func (db *DB) AddBlock(block *Block) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(blocksBucket)
+		if bucket.Get([]byte(block.Hash)) != nil {
+			str := fmt.Sprintf("block %s is already recorded", block.Hash)
+			return makeError(ErrBlockExists, str)
+		}
+		return putBlock(bucket, block)
+	})
+}
+
+// Blocks returns all blocks found by the pool with the provided status, or all
+// blocks when the status is empty, in the order they were found.
+func (db *DB) Blocks(status BlockStatus) ([]*Block, error) {
+	var blocks []*Block
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(blocksBucket).ForEach(func(_, v []byte) error {
+			var block Block
+			if err := json.Unmarshal(v, &block); err != nil {
+				return err
+			}
+			if status == "" || block.Status == status {
+				blocks = append(blocks, &block)
+			}
+			return nil
+		})
+	})
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].FoundAt.Before(blocks[j].FoundAt)
+	})
+	return blocks, err
+}
+
+// SetBlockStatus updates the status of the block with the provided hash.
+// Credited blocks may not be changed since their reward has already been
+// distributed.
+func (db *DB) SetBlockStatus(hash string, status BlockStatus) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(blocksBucket)
+		block, err := fetchBlock(bucket, hash)
+		if err != nil {
+			return err
+		}
+		if block.Status == BlockCredited {
+			str := fmt.Sprintf("block %s is already credited", hash)
+			return makeError(ErrBlockCredited, str)
+		}
+		block.Status = status
+		return putBlock(bucket, block)
+	})
+}
+
+// CreditBlock marks the block with the provided hash as credited with the
+// provided reward and adds the provided credits, in atoms keyed by account,
+// to the account balances.  This is done atomically so a block is never
+// credited more than once.
+func (db *DB) CreditBlock(hash string, reward int64, credits map[string]int64) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		blocks := tx.Bucket(blocksBucket)
+		block, err := fetchBlock(blocks, hash)
+		if err != nil {
+			return err
+		}
+		if block.Status == BlockCredited {
+			str := fmt.Sprintf("block %s is already credited", hash)
+			return makeError(ErrBlockCredited, str)
+		}
+		block.Status = BlockCredited
+		block.Reward = reward
+		if err := putBlock(blocks, block); err != nil {
+			return err
+		}
+
+		balances := tx.Bucket(balanceBucket)
+		for account, amount := range credits {
+			balance := getBalance(balances, account) + amount
+			if err := putBalance(balances, account, balance); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// getBalance returns the balance of the provided account from the balances
+// bucket.
+func getBalance(bucket *bolt.Bucket, account string) int64 {
+	v := bucket.Get([]byte(account))
+	if len(v) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(v))
+}
+
+// putBalance stores the balance of the provided account in the balances
+// bucket.  Accounts with a zero balance are removed.
+func putBalance(bucket *bolt.Bucket, account string, balance int64) error {
+	if balance == 0 {
+		return bucket.Delete([]byte(account))
+	}
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], uint64(balance))
+	return bucket.Put([]byte(account), v[:])
+}
+
+// Balances returns the balances, in atoms, of all accounts with a non-zero
+// balance.
+func (db *DB) Balances() (map[string]int64, error) {
+	balances := make(map[string]int64)
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(balanceBucket).ForEach(func(k, v []byte) error {
+			if len(v) == 8 {
+				balances[string(k)] = int64(binary.BigEndian.Uint64(v))
+			}
+			return nil
+		})
+	})
+	return balances, err
+}
+
+// RecordPayment records the provided payment and debits the paid amounts from
+// the account balances.  This is done atomically and no changes are made when
+// any amount exceeds the balance of its account.
+func (db *DB) RecordPayment(payment *Payment) error {
+	v, err := json.Marshal(payment)
+	if err != nil {
+		return err
+	}
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		balances := tx.Bucket(balanceBucket)
+		for account, amount := range payment.Amounts {
+			balance := getBalance(balances, account)
+			if amount > balance {
+				str := fmt.Sprintf("payment of %d atoms to %s exceeds its "+
+					"balance of %d atoms", amount, account, balance)
+				return makeError(ErrInsufficientBalance, str)
+			}
+			err := putBalance(balances, account, balance-amount)
+			if err != nil {
+				return err
+			}
+		}
+
+		payments := tx.Bucket(paymentsBucket)
+		seq, err := payments.NextSequence()
+		if err != nil {
+			return err
+		}
+		return payments.Put(timeKey(payment.Time, seq), v)
+	})
+}
+
+// Payments returns all recorded payments in the order they were made.
+func (db *DB) Payments() ([]*Payment, error) {
+	var payments []*Payment
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(paymentsBucket).ForEach(func(k, v []byte) error {
+			var payment Payment
+			if err := json.Unmarshal(v, &payment); err != nil {
+				return err
+			}
+			payment.Time = keyTime(k)
+			payments = append(payments, &payment)
+			return nil
+		})
+	})
+	return payments, err
+}
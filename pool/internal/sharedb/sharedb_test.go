@@ -0,0 +1,178 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package sharedb
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// openTestDB opens a new database in a temporary directory that is closed when
+// the test finishes.
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "pool.db"))
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// TestShares ensures shares are returned for the requested period in the order
+// they were submitted and are pruned as expected.
+func TestShares(t *testing.T) {
+	db := openTestDB(t)
+	base := time.Unix(1700000000, 0)
+	for i, account := range []string{"a", "b", "a", "c", "b"} {
+		share := &Share{
+			Account: account,
+			Weight:  float64(i + 1),
+			Time:    base.Add(time.Duration(i) * time.Second),
+		}
+		if err := db.AddShare(share); err != nil {
+			t.Fatalf("unexpected error adding share: %v", err)
+		}
+	}
+
+	// Add a second share at the same time to ensure it is not overwritten.
+	dupTime := &Share{Account: "d", Weight: 1, Time: base.Add(time.Second * 2)}
+	if err := db.AddShare(dupTime); err != nil {
+		t.Fatalf("unexpected error adding share: %v", err)
+	}
+
+	// The start is exclusive and the end is inclusive.
+	shares, err := db.Shares(base.Add(time.Second), base.Add(time.Second*3))
+	if err != nil {
+		t.Fatalf("unexpected error fetching shares: %v", err)
+	}
+	var got []string
+	for _, share := range shares {
+		got = append(got, share.Account)
+	}
+	want := []string{"a", "d", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected shares -- got %v, want %v", got, want)
+	}
+	if !shares[2].Time.Equal(base.Add(time.Second*3)) || shares[2].Weight != 4 {
+		t.Fatalf("unexpected share %+v", shares[2])
+	}
+
+	n, err := db.PruneShares(base.Add(time.Second * 2))
+	if err != nil {
+		t.Fatalf("unexpected error pruning shares: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("unexpected number of pruned shares -- got %d, want 2", n)
+	}
+	shares, err = db.Shares(time.Unix(0, 0), base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error fetching shares: %v", err)
+	}
+	if len(shares) != 4 {
+		t.Fatalf("unexpected number of shares -- got %d, want 4", len(shares))
+	}
+}
+
+// TestBlocksAndPayments ensures found blocks are tracked through their
+// statuses, credited exactly once, and that payments debit balances.
+func TestBlocksAndPayments(t *testing.T) {
+	db := openTestDB(t)
+	base := time.Unix(1700000000, 0)
+
+	blocks := []*Block{
+		{Hash: "b2", Height: 11, FoundAt: base.Add(time.Minute), Status: BlockPending},
+		{Hash: "b1", Height: 10, FoundAt: base, Status: BlockPending},
+	}
+	for _, block := range blocks {
+		if err := db.AddBlock(block); err != nil {
+			t.Fatalf("unexpected error adding block: %v", err)
+		}
+	}
+	if err := db.AddBlock(blocks[0]); !errors.Is(err, ErrBlockExists) {
+		t.Fatalf("unexpected error adding duplicate block: %v", err)
+	}
+
+	pending, err := db.Blocks(BlockPending)
+	if err != nil {
+		t.Fatalf("unexpected error fetching blocks: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Hash != "b1" || pending[1].Hash != "b2" {
+		t.Fatalf("unexpected pending blocks %+v", pending)
+	}
+
+	if err := db.SetBlockStatus("b2", BlockOrphaned); err != nil {
+		t.Fatalf("unexpected error setting status: %v", err)
+	}
+	if err := db.SetBlockStatus("b3", BlockOrphaned); !errors.Is(err, ErrBlockNotFound) {
+		t.Fatalf("unexpected error setting status of unknown block: %v", err)
+	}
+
+	credits := map[string]int64{"a": 700, "b": 200, "fee": 100}
+	if err := db.CreditBlock("b1", 1000, credits); err != nil {
+		t.Fatalf("unexpected error crediting block: %v", err)
+	}
+	if err := db.CreditBlock("b1", 1000, credits); !errors.Is(err, ErrBlockCredited) {
+		t.Fatalf("unexpected error crediting block twice: %v", err)
+	}
+	if err := db.SetBlockStatus("b1", BlockOrphaned); !errors.Is(err, ErrBlockCredited) {
+		t.Fatalf("unexpected error orphaning credited block: %v", err)
+	}
+	credited, err := db.Blocks(BlockCredited)
+	if err != nil {
+		t.Fatalf("unexpected error fetching blocks: %v", err)
+	}
+	if len(credited) != 1 || credited[0].Reward != 1000 {
+		t.Fatalf("unexpected credited blocks %+v", credited)
+	}
+
+	balances, err := db.Balances()
+	if err != nil {
+		t.Fatalf("unexpected error fetching balances: %v", err)
+	}
+	if !reflect.DeepEqual(balances, credits) {
+		t.Fatalf("unexpected balances -- got %v, want %v", balances, credits)
+	}
+
+	// A payment that exceeds any balance must not change any balances.
+	overpay := &Payment{
+		TxID:    "tx0",
+		Amounts: map[string]int64{"a": 700, "b": 201},
+		Time:    base,
+	}
+	if err := db.RecordPayment(overpay); !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("unexpected error recording overpayment: %v", err)
+	}
+
+	payment := &Payment{
+		TxID:    "tx1",
+		Amounts: map[string]int64{"a": 700, "b": 150},
+		Time:    base.Add(time.Hour),
+	}
+	if err := db.RecordPayment(payment); err != nil {
+		t.Fatalf("unexpected error recording payment: %v", err)
+	}
+	balances, err = db.Balances()
+	if err != nil {
+		t.Fatalf("unexpected error fetching balances: %v", err)
+	}
+	want := map[string]int64{"b": 50, "fee": 100}
+	if !reflect.DeepEqual(balances, want) {
+		t.Fatalf("unexpected balances -- got %v, want %v", balances, want)
+	}
+
+	payments, err := db.Payments()
+	if err != nil {
+		t.Fatalf("unexpected error fetching payments: %v", err)
+	}
+	if len(payments) != 1 || payments[0].TxID != "tx1" ||
+		!payments[0].Time.Equal(payment.Time) {
+
+		t.Fatalf("unexpected payments %+v", payments)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/pool/internal/sharedb"
+)
+
+// paymentBatches returns the accounts with balances of at least the provided
+// minimum payment grouped into batches of at most the provided maximum number
+// of accounts.  Accounts are sorted so that batches are deterministic.
+func paymentBatches(balances map[string]int64, minPayment VGLutil.Amount, maxOutputs int) []map[string]int64 {
+	accounts := make([]string, 0, len(balances))
+	for account, balance := range balances {
+		if balance >= int64(minPayment) {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Strings(accounts)
+
+	var batches []map[string]int64
+	for len(accounts) > 0 {
+		n := len(accounts)
+		if n > maxOutputs {
+			n = maxOutputs
+		}
+		batch := make(map[string]int64, n)
+		for _, account := range accounts[:n] {
+			batch[account] = balances[account]
+		}
+		batches = append(batches, batch)
+		accounts = accounts[n:]
+	}
+	return batches
+}
+
+// sendMany pays the provided amounts, in atoms keyed by address, from the
+// configured wallet account with the sendmany RPC of the wallet and returns
+// the transaction hash.
+func (p *pool) sendMany(ctx context.Context, amounts map[string]int64) (string, error) {
+	coins := make(map[string]float64, len(amounts))
+	for addr, amount := range amounts {
+		coins[addr] = VGLutil.Amount(amount).ToCoin()
+	}
+	params := make([]json.RawMessage, 0, 3)
+	for _, param := range []interface{}{p.cfg.WalletAccount, coins, 1} {
+		b, err := json.Marshal(param)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, b)
+	}
+
+	result, err := p.wallet.RawRequest(ctx, "sendmany", params)
+	if err != nil {
+		return "", err
+	}
+	var txid string
+	if err := json.Unmarshal(result, &txid); err != nil {
+		return "", fmt.Errorf("unexpected sendmany result %s: %w", result, err)
+	}
+	return txid, nil
+}
+
+// payBalances pays all account balances that reached the minimum payment in
+// batched transactions and debits the paid amounts from the balances.
+func (p *pool) payBalances(ctx context.Context) {
+	balances, err := p.db.Balances()
+	if err != nil {
+		paymLog.Errorf("Unable to load balances: %v", err)
+		return
+	}
+
+	batches := paymentBatches(balances, p.cfg.minPayment,
+		p.cfg.MaxPaymentOutputs)
+	for _, batch := range batches {
+		txid, err := p.sendMany(ctx, batch)
+		if err != nil {
+			paymLog.Errorf("Unable to send payment to %d accounts: %v",
+				len(batch), err)
+			return
+		}
+
+		// The payment was sent, so failing to record it would result in
+		// paying the same balances again.  Stop all further payments in
+		// that case so the operator can reconcile the balances.
+		payment := &sharedb.Payment{
+			TxID:    txid,
+			Amounts: batch,
+			Time:    time.Now(),
+		}
+		if err := p.db.RecordPayment(payment); err != nil {
+			paymLog.Criticalf("Unable to record payment %s: %v -- "+
+				"stopping payments", txid, err)
+			p.paymentsHalted = true
+			return
+		}
+
+		var total int64
+		for _, amount := range batch {
+			total += amount
+		}
+		paymLog.Infof("Paid %v to %d accounts in transaction %s",
+			VGLutil.Amount(total), len(batch), txid)
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kdsmith18542/vigil/pool/internal/sharedb"
+	"github.com/kdsmith18542/vigil/rpcclient/v8"
+	"github.com/kdsmith18542/vigil/stratum"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+const (
+	// blockCheckInterval is how often found blocks are checked for coinbase
+	// maturity and orphaning in addition to whenever a block is connected.
+	blockCheckInterval = time.Minute
+
+	// getworkDataLen is the length of the data field of the getwork RPC and
+	// notifywork notification for KawPoW.  It consists of the serialized
+	// block header followed by the mix hash and the little-endian nonce.
+	getworkDataLen = wire.MaxBlockHeaderPayload + 32 + 8
+)
+
+// pool houses the state of the mining pool.  It receives work from the node
+// and hands it out to miners via the stratum server, records shares, tracks
+// found blocks through coinbase maturity, and pays out balances via the
+// wallet.
+type pool struct {
+	cfg     *config
+	db      *sharedb.DB
+	node    *rpcclient.Client
+	wallet  *rpcclient.Client
+	stratum *stratum.Server
+
+	// workCh receives the data of new work from the node and blockCh is
+	// signalled when a block is connected to the main chain.
+	workCh  chan []byte
+	blockCh chan struct{}
+
+	// paymentsHalted is set when a sent payment could not be recorded in
+	// the database to prevent paying the same balances again.
+	paymentsHalted bool
+}
+
+// newPool returns a new pool with connections to the node and wallet RPC
+// servers and the stratum server listening on the configured interfaces.
+func newPool(cfg *config, db *sharedb.DB) (*pool, error) {
+	p := &pool{
+		cfg:     cfg,
+		db:      db,
+		workCh:  make(chan []byte, 1),
+		blockCh: make(chan struct{}, 1),
+	}
+
+	nodeCert, err := os.ReadFile(cfg.NodeRPCCert)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read vgld RPC certificate: %w", err)
+	}
+	ntfnHandlers := &rpcclient.NotificationHandlers{
+		OnClientConnected: func() {
+			// Register for notifications again on reconnect.  This is
+			// done in a goroutine since the handlers must not block.
+			go p.registerNotifications()
+		},
+		OnBlockConnected: func(_ []byte, _ [][]byte) {
+			select {
+			case p.blockCh <- struct{}{}:
+			default:
+			}
+		},
+		OnWork: func(data []byte, _ []byte, reason string) {
+			poolLog.Debugf("Received new work (reason %s)", reason)
+			p.queueWork(data)
+		},
+	}
+	p.node, err = rpcclient.New(&rpcclient.ConnConfig{
+		Host:                cfg.NodeRPCConnect,
+		Endpoint:            "ws",
+		User:                cfg.NodeRPCUser,
+		Pass:                cfg.NodeRPCPass,
+		Certificates:        nodeCert,
+		DisableConnectOnNew: true,
+	}, ntfnHandlers)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create vgld RPC client: %w", err)
+	}
+
+	walletCert, err := os.ReadFile(cfg.WalletRPCCert)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read wallet RPC certificate: %w",
+			err)
+	}
+	p.wallet, err = rpcclient.New(&rpcclient.ConnConfig{
+		Host:         cfg.WalletRPCConnect,
+		User:         cfg.WalletRPCUser,
+		Pass:         cfg.WalletRPCPass,
+		Certificates: walletCert,
+		HTTPPostMode: true,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create wallet RPC client: %w", err)
+	}
+
+	listeners := make([]net.Listener, 0, len(cfg.StratumListeners))
+	for _, addr := range cfg.StratumListeners {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("unable to listen on %s: %w", addr, err)
+		}
+		listeners = append(listeners, listener)
+	}
+	p.stratum, err = stratum.New(&stratum.Config{
+		DiffOne:           cfg.params.PowLimit,
+		Listeners:         listeners,
+		Authorize:         p.authorize,
+		SubmitBlock:       p.submitBlock,
+		ShareAccepted:     p.shareAccepted,
+		InitialDifficulty: cfg.StratumDiff,
+		MaxClients:        cfg.MaxClients,
+	})
+	if err != nil {
+		for _, l := range listeners {
+			l.Close()
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// registerNotifications registers for new work and block connected
+// notifications from the node and requests the current work.
+func (p *pool) registerNotifications() {
+	ctx := context.Background()
+	if err := p.node.NotifyWork(ctx); err != nil {
+		poolLog.Errorf("Unable to register for work notifications: %v", err)
+	}
+	if err := p.node.NotifyBlocks(ctx); err != nil {
+		poolLog.Errorf("Unable to register for block notifications: %v", err)
+	}
+
+	work, err := p.node.GetWork(ctx)
+	if err != nil {
+		poolLog.Errorf("Unable to get work: %v", err)
+		return
+	}
+	data, err := hex.DecodeString(work.Data)
+	if err != nil {
+		poolLog.Errorf("Unable to decode work: %v", err)
+		return
+	}
+	p.queueWork(data)
+}
+
+// queueWork queues the provided work data to be sent to miners.  Work that has
+// not yet been handled is replaced since only the most recent work matters.
+func (p *pool) queueWork(data []byte) {
+	for {
+		select {
+		case p.workCh <- data:
+			return
+		default:
+		}
+		select {
+		case <-p.workCh:
+		default:
+		}
+	}
+}
+
+// handleWork decodes the block header from the provided work data and sends it
+// to miners.
+func (p *pool) handleWork(data []byte) {
+	if len(data) < wire.MaxBlockHeaderPayload {
+		poolLog.Errorf("Received work with invalid length %d", len(data))
+		return
+	}
+	var header wire.BlockHeader
+	err := header.FromBytes(data[:wire.MaxBlockHeaderPayload])
+	if err != nil {
+		poolLog.Errorf("Unable to decode work header: %v", err)
+		return
+	}
+	p.stratum.SetWork(&header)
+}
+
+// authorize returns the account that shares from the provided worker are
+// credited to.  Workers are named by the address that is paid for their
+// shares, optionally followed by a period and a worker name.
+func (p *pool) authorize(worker string) (string, error) {
+	account, _, _ := strings.Cut(worker, ".")
+	_, err := stdaddr.DecodeAddress(account, p.cfg.params.Params)
+	if err != nil {
+		return "", fmt.Errorf("worker name must start with a %s payment "+
+			"address: %w", p.cfg.params.Name, err)
+	}
+	return account, nil
+}
+
+// shareAccepted records an accepted share for the provided account.
+func (p *pool) shareAccepted(account string, difficulty float64) {
+	share := &sharedb.Share{
+		Account: account,
+		Weight:  difficulty,
+		Time:    time.Now(),
+	}
+	if err := p.db.AddShare(share); err != nil {
+		poolLog.Errorf("Unable to record share for %s: %v", account, err)
+	}
+}
+
+// submitBlock submits the provided solved block header to the node via the
+// getwork RPC and records the block when it is accepted.
+func (p *pool) submitBlock(header *wire.BlockHeader, worker string) (bool, error) {
+	data := make([]byte, getworkDataLen)
+	if err := header.Serialize(bytes.NewBuffer(data[:0])); err != nil {
+		return false, err
+	}
+	copy(data[wire.MaxBlockHeaderPayload:], header.MixHash[:])
+	binary.LittleEndian.PutUint64(data[wire.MaxBlockHeaderPayload+32:],
+		header.Nonce)
+
+	ctx := context.Background()
+	accepted, err := p.node.GetWorkSubmit(ctx, hex.EncodeToString(data))
+	if err != nil || !accepted {
+		return accepted, err
+	}
+
+	block := &sharedb.Block{
+		Hash:    header.BlockHash().String(),
+		Height:  int64(header.Height),
+		Worker:  worker,
+		FoundAt: time.Now(),
+		Status:  sharedb.BlockPending,
+	}
+	if err := p.db.AddBlock(block); err != nil {
+		poolLog.Errorf("Unable to record block %s: %v", block.Hash, err)
+	}
+	return true, nil
+}
+
+// run connects to the node and runs the pool until the provided context is
+// cancelled.
+func (p *pool) run(ctx context.Context) error {
+	if err := p.node.Connect(ctx, true); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return fmt.Errorf("unable to connect to vgld: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.stratum.Run(ctx)
+	}()
+
+	poolLog.Infof("Pool started with the %s payout scheme and a %v%% fee",
+		p.cfg.paymentScheme, p.cfg.PoolFee)
+	blockTicker := time.NewTicker(blockCheckInterval)
+	defer blockTicker.Stop()
+	paymentTicker := time.NewTicker(p.cfg.PaymentInterval)
+	defer paymentTicker.Stop()
+	for {
+		select {
+		case data := <-p.workCh:
+			p.handleWork(data)
+
+		case <-p.blockCh:
+			p.processBlocks(ctx)
+
+		case <-blockTicker.C:
+			p.processBlocks(ctx)
+
+		case <-paymentTicker.C:
+			if !p.paymentsHalted {
+				p.payBalances(ctx)
+			}
+
+		case <-ctx.Done():
+			p.node.Shutdown()
+			p.wallet.Shutdown()
+			wg.Wait()
+			p.node.WaitForShutdown()
+			p.wallet.WaitForShutdown()
+			return nil
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kdsmith18542/vigil/pool/internal/sharedb"
+)
+
+// run is the real main function for vglpool.  It is necessary to work around
+// the fact that deferred functions do not run when os.Exit() is called.
+func run() error {
+	// Load configuration and parse command line.  This function also
+	// initializes logging and configures it accordingly.
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if logRotator != nil {
+			logRotator.Close()
+		}
+	}()
+
+	// Get a context that will be canceled when a shutdown signal has been
+	// triggered from an OS signal such as SIGINT (Ctrl+C).
+	ctx := shutdownListener()
+	defer poolLog.Info("Shutdown complete")
+
+	poolLog.Infof("Home dir: %s", cfg.HomeDir)
+	if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+		poolLog.Errorf("Unable to create data directory: %v", err)
+		return err
+	}
+
+	// Open the share database.
+	dbPath := filepath.Join(cfg.DataDir, defaultDBFilename)
+	db, err := sharedb.Open(dbPath)
+	if err != nil {
+		poolLog.Errorf("Unable to open share database: %v", err)
+		return err
+	}
+	defer func() {
+		poolLog.Infof("Gracefully shutting down the share database...")
+		db.Close()
+	}()
+
+	// Return now if a shutdown signal was triggered.
+	if shutdownRequested(ctx) {
+		return nil
+	}
+
+	p, err := newPool(cfg, db)
+	if err != nil {
+		poolLog.Errorf("%v", err)
+		return err
+	}
+	if err := p.run(ctx); err != nil {
+		poolLog.Errorf("%v", err)
+		return err
+	}
+	return nil
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+)
+
+const (
+	defaultConfigFilename = "vglctl.conf"
+	defaultRPCServer      = "localhost"
+
+	// usageArgs is the usage shown after the application name in the help
+	// message.
+	usageArgs = "[OPTIONS] <command> <args...>"
+)
+
+var (
+	vgldHomeDir           = VGLutil.AppDataDir("vgld", false)
+	vglctlHomeDir         = VGLutil.AppDataDir("vglctl", false)
+	vglwalletHomeDir      = VGLutil.AppDataDir("vglwallet", false)
+	defaultConfigFile     = filepath.Join(vglctlHomeDir, defaultConfigFilename)
+	defaultRPCCertFile    = filepath.Join(vgldHomeDir, "rpc.cert")
+	defaultWalletCertFile = filepath.Join(vglwalletHomeDir, "rpc.cert")
+)
+
+// config defines the configuration options for vglctl.
+//
+// See loadConfig for details on the configuration load process.
+type config struct {
+	ListCommands    bool   `short:"l" long:"listcommands" description:"List all of the supported commands and exit"`
+	ConfigFile      string `short:"C" long:"configfile" description:"Path to configuration file"`
+	RPCUser         string `short:"u" long:"rpcuser" description:"RPC username"`
+	RPCPassword     string `short:"P" long:"rpcpass" default-mask:"-" description:"RPC password"`
+	RPCServer       string `short:"s" long:"rpcserver" description:"vgld RPC server to connect to (default port: 9109, testnet: 19109, simnet: 19556, regnet: 18656)"`
+	WalletRPCServer string `short:"w" long:"walletrpcserver" description:"vglwallet RPC server to connect to (default port: 9110, testnet: 19110, simnet: 19557)"`
+	RPCCert         string `short:"c" long:"rpccert" description:"RPC server certificate chain for validation"`
+	NoTLS           bool   `long:"notls" description:"Disable TLS"`
+	TLSSkipVerify   bool   `long:"tlsskipverify" description:"Do not verify TLS certificates (not recommended!)"`
+	TestNet         bool   `long:"testnet" description:"Connect to testnet"`
+	SimNet          bool   `long:"simnet" description:"Connect to the simulation test network"`
+	RegNet          bool   `long:"regnet" description:"Connect to the regression test network"`
+	Wallet          bool   `long:"wallet" description:"Connect to wallet RPC server instead"`
+
+	// The credentials options of vglwallet configuration files are accepted
+	// as well so the same file may be used with --configfile.
+	Username string `long:"username" hidden:"true"`
+	Password string `long:"password" default-mask:"-" hidden:"true"`
+
+	// Cooked options ready for use.
+	params *params
+}
+
+// cleanAndExpandPath expands environment variables and leading ~ in the
+// passed path, cleans the result, and returns it.
+func cleanAndExpandPath(path string) string {
+	// Nothing to do when no path is given.
+	if path == "" {
+		return path
+	}
+
+	// NOTE: The os.ExpandEnv doesn't work with Windows cmd.exe-style
+	// %VARIABLE%, but the variables can still be expanded via POSIX-style
+	// $VARIABLE.
+	path = os.ExpandEnv(path)
+
+	if !strings.HasPrefix(path, "~") {
+		return filepath.Clean(path)
+	}
+
+	// Expand initial ~ to the current user's home directory, or ~otheruser
+	// to otheruser's home directory.  On Windows, both forward and backward
+	// slashes can be used.
+	path = path[1:]
+
+	var pathSeparators string
+	if runtime.GOOS == "windows" {
+		pathSeparators = string(os.PathSeparator) + "/"
+	} else {
+		pathSeparators = string(os.PathSeparator)
+	}
+
+	userName := ""
+	if i := strings.IndexAny(path, pathSeparators); i != -1 {
+		userName = path[:i]
+		path = path[i:]
+	}
+
+	homeDir := ""
+	var u *user.User
+	var err error
+	if userName == "" {
+		u, err = user.Current()
+	} else {
+		u, err = user.Lookup(userName)
+	}
+	if err == nil {
+		homeDir = u.HomeDir
+	}
+	// Fallback to CWD if user lookup fails or user has no home directory.
+	if homeDir == "" {
+		homeDir = "."
+	}
+
+	return filepath.Join(homeDir, path)
+}
+
+// normalizeAddress returns addr with the passed default port appended if there
+// is not already a port specified.
+func normalizeAddress(addr, defaultPort string) string {
+	_, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return net.JoinHostPort(addr, defaultPort)
+	}
+	return addr
+}
+
+// fileExists reports whether the named file or directory exists.
+func fileExists(name string) bool {
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadConfig initializes and parses the config using a config file and command
+// line options.
+//
+// The configuration proceeds as follows:
+//  1. Start with a default config with sane settings
+//  2. Pre-parse the command line to check for an alternative config file
+//  3. Load configuration file overwriting defaults with any specified options
+//  4. Parse CLI options and overwrite/add any specified options
+//
+// Options in the configuration file that vglctl does not know about are
+// ignored so the configuration files of vgld and vglwallet may be used
+// directly.
+//
+// The above results in functioning properly without any config settings while
+// still allowing the user to override settings with config files and command
+// line options.  Command line options always take precedence.  The arguments
+// that remain after the options are returned along with the config.
+func loadConfig() (*config, []string, error) {
+	// Default config.
+	cfg := config{
+		ConfigFile: defaultConfigFile,
+		RPCCert:    defaultRPCCertFile,
+	}
+
+	// Pre-parse the command line options to see if an alternative config
+	// file or the list commands option was specified.  Any errors aside from
+	// the help message error can be ignored here since they will be caught
+	// by the final parse below.
+	preCfg := cfg
+	preParser := flags.NewParser(&preCfg, flags.HelpFlag|
+		flags.PassAfterNonOption)
+	preParser.Usage = usageArgs
+	_, err := preParser.Parse()
+	if err != nil {
+		var e *flags.Error
+		if errors.As(err, &e) && e.Type == flags.ErrHelp {
+			fmt.Fprintln(os.Stdout, err)
+			fmt.Fprintln(os.Stdout, "")
+			fmt.Fprintln(os.Stdout, "The special parameter `-` "+
+				"indicates that a parameter should be read from the\n"+
+				"next unread line from standard input.")
+			os.Exit(0)
+		}
+	}
+
+	// Show the available commands and exit if the associated flag was
+	// specified.
+	if preCfg.ListCommands {
+		listCommands(os.Stdout)
+		os.Exit(0)
+	}
+
+	// Load additional config from file.
+	configFile := cleanAndExpandPath(preCfg.ConfigFile)
+	if fileExists(configFile) {
+		fileParser := flags.NewParser(&cfg, flags.IgnoreUnknown)
+		err := flags.NewIniParser(fileParser).ParseFile(configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+			return nil, nil, err
+		}
+	}
+
+	// Parse command line options again to ensure they take precedence.
+	parser := flags.NewParser(&cfg, flags.Default|flags.PassAfterNonOption)
+	parser.Usage = usageArgs
+	remainingArgs, err := parser.Parse()
+	if err != nil {
+		var e *flags.Error
+		if !errors.As(err, &e) || e.Type != flags.ErrHelp {
+			parser.WriteHelp(os.Stderr)
+		}
+		return nil, nil, err
+	}
+
+	// Multiple networks can't be selected simultaneously.  Count the number
+	// of network flags passed and assign the active network params.
+	funcName := "loadConfig"
+	numNets := 0
+	cfg.params = &mainNetParams
+	if cfg.TestNet {
+		numNets++
+		cfg.params = &testNet3Params
+	}
+	if cfg.SimNet {
+		numNets++
+		cfg.params = &simNetParams
+	}
+	if cfg.RegNet {
+		numNets++
+		cfg.params = &regNetParams
+	}
+	if numNets > 1 {
+		str := "%s: the testnet, regnet, and simnet params can't be " +
+			"used together -- choose one of the three"
+		return nil, nil, fmt.Errorf(str, funcName)
+	}
+
+	// Fall back to the credentials of a vglwallet configuration file when
+	// the RPC credentials were not specified.
+	if cfg.RPCUser == "" {
+		cfg.RPCUser = cfg.Username
+	}
+	if cfg.RPCPassword == "" {
+		cfg.RPCPassword = cfg.Password
+	}
+
+	// Use the vglwallet certificate by default when connecting to the
+	// wallet.
+	if cfg.Wallet && cfg.RPCCert == defaultRPCCertFile {
+		cfg.RPCCert = defaultWalletCertFile
+	}
+	cfg.RPCCert = cleanAndExpandPath(cfg.RPCCert)
+
+	// Add default ports for the active network when there are no ports
+	// specified.
+	if cfg.RPCServer == "" {
+		cfg.RPCServer = defaultRPCServer
+	}
+	cfg.RPCServer = normalizeAddress(cfg.RPCServer, cfg.params.rpcServerPort)
+	if cfg.WalletRPCServer == "" {
+		cfg.WalletRPCServer = defaultRPCServer
+	}
+	if cfg.params.walletRPCServerPort != "" {
+		cfg.WalletRPCServer = normalizeAddress(cfg.WalletRPCServer,
+			cfg.params.walletRPCServerPort)
+	} else if _, _, err := net.SplitHostPort(cfg.WalletRPCServer); cfg.Wallet && err != nil {
+		str := "%s: vglwallet has no default port for %s -- the port " +
+			"must be specified with --walletrpcserver"
+		return nil, nil, fmt.Errorf(str, funcName, cfg.params.name)
+	}
+
+	return &cfg, remainingArgs, nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+vglctl is a command-line client for the JSON-RPC servers of vgld and
+vglwallet.
+
+The supported commands are the methods registered with the VGLjson package by
+the chain server types (rpc/jsonrpc/types) and, when the --wallet flag is
+specified, by the wallet server types (wallet/rpc/jsonrpc/types).  Commands
+that can only be used via websockets and notifications are not supported.  The
+command line arguments are converted into the parameters of the command and it
+is sent to the server with an HTTP-POST request.
+
+The detailed help text of a command is provided by the server via the help
+command:
+
+	vglctl help <command>
+	vglctl --wallet help <command>
+
+Options in the configuration file that vglctl does not know about are ignored,
+so the configuration files of vgld and vglwallet may be passed with
+--configfile to reuse their credentials and network.
+
+Usage:
+
+	vglctl [OPTIONS] <command> <args...>
+
+Application Options:
+
+	-l, --listcommands     List all of the supported commands and exit
+	-C, --configfile=      Path to configuration file
+	-u, --rpcuser=         RPC username
+	-P, --rpcpass=         RPC password
+	-s, --rpcserver=       vgld RPC server to connect to (default port: 9109,
+	                       testnet: 19109, simnet: 19556, regnet: 18656)
+	-w, --walletrpcserver= vglwallet RPC server to connect to (default port:
+	                       9110, testnet: 19110, simnet: 19557)
+	-c, --rpccert=         RPC server certificate chain for validation
+	    --notls            Disable TLS
+	    --tlsskipverify    Do not verify TLS certificates (not recommended!)
+	    --testnet          Connect to testnet
+	    --simnet           Connect to the simulation test network
+	    --regnet           Connect to the regression test network
+	    --wallet           Connect to wallet RPC server instead
+
+Help Options:
+
+	-h, --help             Show this help message
+
+The special parameter `-` indicates that a parameter should be read from the
+next unread line from standard input.
+*/
+package main
@@ -0,0 +1,28 @@
+module github.com/kdsmith18542/vigil/vglctl
+
+go 1.23.0
+
+require (
+	github.com/jessevdk/go-flags v1.5.0
+	github.com/kdsmith18542/vigil/dcrjson/v4 v4.1.0
+	github.com/kdsmith18542/vigil/dcrutil/v4 v4.0.2
+	github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4 v4.2.0
+	github.com/kdsmith18542/vigil/wallet v0.0.0-00010101000000-000000000000
+)
+
+require golang.org/x/sys v0.0.0-20210320140829-1e4c9ba3b0c4 // indirect
+
+replace (
+	github.com/kdsmith18542/vigil/addrmgr/v3 => ../node/addrmgr
+	github.com/kdsmith18542/vigil/blockchain/v5 => ../node/blockchain
+	github.com/kdsmith18542/vigil/chaincfg/v3 => ../node/chaincfg
+	github.com/kdsmith18542/vigil/dcrec/secp256k1/v4 => ../node/dcrec/secp256k1
+	github.com/kdsmith18542/vigil/dcrjson/v4 => ../node/dcrjson
+	github.com/kdsmith18542/vigil/dcrutil/v4 => ../node/dcrutil
+	github.com/kdsmith18542/vigil/kawpow => ../node/kawpow
+	github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4 => ../node/rpc/jsonrpc/types
+	github.com/kdsmith18542/vigil/rpcclient/v8 => ../node/rpcclient
+	github.com/kdsmith18542/vigil/txscript/v4 => ../node/txscript
+	github.com/kdsmith18542/vigil/wallet => ../wallet
+	github.com/kdsmith18542/vigil/wire => ../node/wire
+)
@@ -0,0 +1,45 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+// params is used to group the default RPC server ports for various networks
+// such as the main network and test networks.
+type params struct {
+	name                string
+	rpcServerPort       string
+	walletRPCServerPort string
+}
+
+// mainNetParams contains parameters specific to the main network
+// (wire.MainNet).
+var mainNetParams = params{
+	name:                "mainnet",
+	rpcServerPort:       "9109",
+	walletRPCServerPort: "9110",
+}
+
+// testNet3Params contains parameters specific to the test network (version 3)
+// (wire.TestNet3).
+var testNet3Params = params{
+	name:                "testnet3",
+	rpcServerPort:       "19109",
+	walletRPCServerPort: "19110",
+}
+
+// simNetParams contains parameters specific to the simulation test network
+// (wire.SimNet).
+var simNetParams = params{
+	name:                "simnet",
+	rpcServerPort:       "19556",
+	walletRPCServerPort: "19557",
+}
+
+// regNetParams contains parameters specific to the regression test network
+// (wire.RegNet).  vglwallet does not support the regression test network, so
+// there is no default wallet RPC server port.
+var regNetParams = params{
+	name:          "regnet",
+	rpcServerPort: "18656",
+}
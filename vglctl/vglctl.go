@@ -0,0 +1,274 @@
+// Copyright (c) 2013-2015 The btcsuite developers
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kdsmith18542/vigil/VGLjson/v4"
+	chainjson "github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4"
+	wallettypes "github.com/kdsmith18542/vigil/wallet/rpc/jsonrpc/types"
+)
+
+const (
+	showHelpMessage = "Specify -h to show available options"
+	listCmdMessage  = "Specify -l to list available commands"
+)
+
+// unusableFlags are the command usage flags which this utility is not able to
+// use.  In particular it doesn't support websockets and consequently
+// notifications.
+const unusableFlags = VGLjson.UFWebsocketOnly | VGLjson.UFNotification
+
+// commandCategory describes a set of commands registered with VGLjson under
+// the same method type.
+type commandCategory struct {
+	title      string
+	methodType interface{}
+}
+
+// commandCategories are the categories of commands listed by --listcommands in
+// display order.
+var commandCategories = []commandCategory{
+	{"Chain Server Commands", chainjson.Method("")},
+	{"Wallet Server Commands (--wallet)", wallettypes.Method("")},
+}
+
+// usableMethods returns a sorted list of the methods registered under the
+// passed method type that can be used via HTTP-POST requests.
+func usableMethods(methodType interface{}) []string {
+	var usable []string
+	for _, method := range VGLjson.RegisteredMethods(methodType) {
+		flags, err := VGLjson.MethodUsageFlags(methodKey(methodType, method))
+		if err != nil || flags&unusableFlags != 0 {
+			continue
+		}
+		usable = append(usable, method)
+	}
+	return usable
+}
+
+// methodKey returns the method registered with VGLjson for the passed name
+// using the same type as methodType.
+func methodKey(methodType interface{}, method string) interface{} {
+	switch methodType.(type) {
+	case wallettypes.Method:
+		return wallettypes.Method(method)
+	default:
+		return chainjson.Method(method)
+	}
+}
+
+// listCommands writes the usage of all commands supported by the chain and
+// wallet servers that can be used by this utility to w.
+func listCommands(w io.Writer) {
+	for i, category := range commandCategories {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s:\n", category.title)
+		for _, method := range usableMethods(category.methodType) {
+			usage, err := VGLjson.MethodUsageText(methodKey(
+				category.methodType, method))
+			if err != nil {
+				// This should never happen since the method was
+				// just returned from the package, but be safe.
+				fmt.Fprintln(w, "Failed to obtain command usage:", err)
+				continue
+			}
+			fmt.Fprintln(w, usage)
+		}
+	}
+}
+
+// commandParams converts the passed command line arguments into the parameters
+// of a command.  The special parameter "-" is replaced by the next unread line
+// of r so that sensitive or very long parameters do not need to be specified on
+// the command line.
+func commandParams(args []string, r io.Reader) ([]interface{}, error) {
+	var bio *bufio.Reader
+	params := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if arg != "-" {
+			params = append(params, arg)
+			continue
+		}
+
+		if bio == nil {
+			bio = bufio.NewReader(r)
+		}
+		param, err := bio.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || param == "") {
+			return nil, fmt.Errorf("failed to read data from stdin: %w",
+				err)
+		}
+		params = append(params, strings.TrimRight(param, "\r\n"))
+	}
+	return params, nil
+}
+
+// formatResult returns the passed JSON-RPC result in a human-readable form.
+// Strings are unquoted, objects and arrays are indented, and null results are
+// empty.
+func formatResult(result []byte) (string, error) {
+	switch {
+	case len(result) == 0 || string(result) == "null":
+		return "", nil
+
+	case result[0] == '{' || result[0] == '[':
+		var dst bytes.Buffer
+		if err := json.Indent(&dst, result, "", "  "); err != nil {
+			return "", fmt.Errorf("failed to format result: %w", err)
+		}
+		return dst.String(), nil
+
+	case result[0] == '"':
+		var str string
+		if err := json.Unmarshal(result, &str); err != nil {
+			return "", fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+		return str, nil
+
+	default:
+		return string(result), nil
+	}
+}
+
+// usage displays the general usage when the help flag is not displayed and
+// an invalid command was specified.  The commandUsage function is used
+// instead when a valid command was specified.
+func usage(errorMessage string) {
+	appName := "vglctl"
+	fmt.Fprintln(os.Stderr, errorMessage)
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintf(os.Stderr, "  %s %s\n\n", appName, usageArgs)
+	fmt.Fprintln(os.Stderr, showHelpMessage)
+	fmt.Fprintln(os.Stderr, listCmdMessage)
+}
+
+// commandUsage displays the usage for a specific command along with how to
+// obtain the detailed help text from the server.
+func commandUsage(method interface{}, wallet bool) {
+	usage, err := VGLjson.MethodUsageText(method)
+	if err != nil {
+		// This should never happen since the method was already checked
+		// before calling this function, but be safe.
+		fmt.Fprintln(os.Stderr, "Failed to obtain command usage:", err)
+		return
+	}
+
+	helpCmd := "vglctl help"
+	if wallet {
+		helpCmd = "vglctl --wallet help"
+	}
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintf(os.Stderr, "  %s\n\n", usage)
+	fmt.Fprintf(os.Stderr, "Run '%s %s' for details\n", helpCmd, method)
+}
+
+func main() {
+	cfg, args, err := loadConfig()
+	if err != nil {
+		os.Exit(1)
+	}
+	if len(args) < 1 {
+		usage("No command specified")
+		os.Exit(1)
+	}
+
+	// Ensure the specified method identifies a valid registered command and
+	// is one of the usable types.
+	var methodType interface{} = chainjson.Method("")
+	if cfg.Wallet {
+		methodType = wallettypes.Method("")
+	}
+	method := methodKey(methodType, args[0])
+	usageFlags, err := VGLjson.MethodUsageFlags(method)
+	if err != nil {
+		str := fmt.Sprintf("Unrecognized command %q", args[0])
+		if !cfg.Wallet {
+			walletMethod := wallettypes.Method(args[0])
+			if _, err := VGLjson.MethodUsageFlags(walletMethod); err == nil {
+				str += " -- specify --wallet to send it to vglwallet"
+			}
+		}
+		fmt.Fprintln(os.Stderr, str)
+		fmt.Fprintln(os.Stderr, listCmdMessage)
+		os.Exit(1)
+	}
+	if usageFlags&unusableFlags != 0 {
+		fmt.Fprintf(os.Stderr, "The '%s' command can only be used via "+
+			"websockets\n", args[0])
+		fmt.Fprintln(os.Stderr, listCmdMessage)
+		os.Exit(1)
+	}
+
+	// Convert the remaining command line args to a slice of interface
+	// values to be passed along as parameters to new command creation
+	// function.  Parameters specified as "-" are read from stdin.
+	params, err := commandParams(args[1:], os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Attempt to create the appropriate command using the arguments
+	// provided by the user.
+	cmd, err := VGLjson.NewCmd(method, params...)
+	if err != nil {
+		// Show the error along with its error kind when it's a
+		// VGLjson.Error as it realistically will always be since the
+		// NewCmd function is only supposed to return errors of that
+		// type.
+		var jerr VGLjson.Error
+		if errors.As(err, &jerr) {
+			fmt.Fprintf(os.Stderr, "%s command: %v (code: %s)\n",
+				args[0], err, jerr.Err)
+			commandUsage(method, cfg.Wallet)
+			os.Exit(1)
+		}
+
+		// The error is not a VGLjson.Error and this really should not
+		// happen.  Nevertheless, fallback to just showing the error
+		// if it should happen due to a bug in the package.
+		fmt.Fprintf(os.Stderr, "%s command: %v\n", args[0], err)
+		commandUsage(method, cfg.Wallet)
+		os.Exit(1)
+	}
+
+	// Marshal the command into a JSON-RPC byte slice in preparation for
+	// sending it to the RPC server.
+	marshalledJSON, err := VGLjson.MarshalCmd("1.0", 1, cmd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Send the JSON-RPC request to the server using the user-specified
+	// connection configuration.
+	result, err := sendPostRequest(marshalledJSON, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Display the result in a human-readable form.
+	str, err := formatResult(result)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if str != "" {
+		fmt.Println(str)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestListCommands ensures the listed commands include the chain and wallet
+// server commands and exclude the commands that can only be used via
+// websockets.
+func TestListCommands(t *testing.T) {
+	var buf bytes.Buffer
+	listCommands(&buf)
+	sections := strings.Split(buf.String(), "\n\n")
+	if len(sections) != len(commandCategories) {
+		t.Fatalf("unexpected number of sections -- got %d, want %d",
+			len(sections), len(commandCategories))
+	}
+
+	tests := []struct {
+		section  int
+		listed   []string
+		unlisted []string
+	}{{
+		section:  0,
+		listed:   []string{"getblockcount", "getblock", "help"},
+		unlisted: []string{"notifyblocks", "getbalance", "blockconnected"},
+	}, {
+		section:  1,
+		listed:   []string{"getbalance", "getblockcount", "walletlock"},
+		unlisted: []string{"authenticate", "notifyblocks"},
+	}}
+	for _, test := range tests {
+		lines := strings.Split(sections[test.section], "\n")
+		hasCommand := func(command string) bool {
+			for _, line := range lines {
+				if line == command || strings.HasPrefix(line, command+" ") {
+					return true
+				}
+			}
+			return false
+		}
+		for _, command := range test.listed {
+			if !hasCommand(command) {
+				t.Errorf("%q: command %q is not listed",
+					commandCategories[test.section].title, command)
+			}
+		}
+		for _, command := range test.unlisted {
+			if hasCommand(command) {
+				t.Errorf("%q: command %q is listed",
+					commandCategories[test.section].title, command)
+			}
+		}
+	}
+}
+
+// TestCommandParams ensures the special "-" parameter is replaced by the next
+// unread line of the reader.
+func TestCommandParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		want    []interface{}
+		wantErr bool
+	}{{
+		name: "no stdin params",
+		args: []string{"a", "1"},
+		want: []interface{}{"a", "1"},
+	}, {
+		name:  "stdin params",
+		args:  []string{"-", "b", "-"},
+		stdin: "first line\r\nsecond line",
+		want:  []interface{}{"first line", "b", "second line"},
+	}, {
+		name:    "stdin exhausted",
+		args:    []string{"-", "-"},
+		stdin:   "only line\n",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		params, err := commandParams(test.args, strings.NewReader(test.stdin))
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !reflect.DeepEqual(params, test.want) {
+			t.Errorf("%q: unexpected params -- got %q, want %q", test.name,
+				params, test.want)
+		}
+	}
+}
+
+// TestFormatResult ensures results are formatted as expected.
+func TestFormatResult(t *testing.T) {
+	tests := []struct {
+		result string
+		want   string
+	}{
+		{result: "null", want: ""},
+		{result: "", want: ""},
+		{result: "123", want: "123"},
+		{result: `"a\nb"`, want: "a\nb"},
+		{result: `{"a":[1,2]}`, want: "{\n  \"a\": [\n    1,\n    2\n  ]\n}"},
+	}
+	for _, test := range tests {
+		got, err := formatResult([]byte(test.result))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.result, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q: unexpected result -- got %q, want %q",
+				test.result, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kdsmith18542/vigil/blockchain/standalone/v2"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/vigiliteia/internal/database"
+	"github.com/kdsmith18542/vigil/vigiliteia/internal/rpc"
+	"github.com/kdsmith18542/vigil/vigiliteia/record"
+)
+
+// requiredConfs is the number of confirmations required before an anchor
+// transaction is considered confirmed.
+const requiredConfs = 6
+
+// vigiliteia houses the state of the proposal server.  It periodically anchors
+// new records into the chain and follows the best block of the node to confirm
+// the anchor transactions, to finish votes, and to create the treasury spends
+// of approved proposals.
+type vigiliteia struct {
+	db     *database.DB
+	node   *rpc.Node
+	wallet *rpc.Wallet
+
+	// treasury creates the treasury spends of approved proposals.  It is
+	// nil when treasury spends are not created.
+	treasury *rpc.Treasury
+
+	anchorInterval time.Duration
+
+	// blockCh is signalled when the node connects a block or the
+	// connection to the node is (re)established.
+	blockCh chan struct{}
+}
+
+// signalBlock signals the update loop to update the proposals without
+// blocking.
+func (v *vigiliteia) signalBlock() {
+	select {
+	case v.blockCh <- struct{}{}:
+	default:
+	}
+}
+
+// run anchors new records at the anchor interval and updates the proposals
+// whenever a block is connected until the provided context is cancelled.
+// Failures are logged and retried on the next interval or block.
+func (v *vigiliteia) run(ctx context.Context) error {
+	ticker := time.NewTicker(v.anchorInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			err = v.anchor(ctx)
+		case <-v.blockCh:
+			err = v.update(ctx)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			vgtaLog.Errorf("%v", err)
+		}
+	}
+}
+
+// anchor publishes a transaction which anchors the records that are not yet
+// anchored into the chain.
+func (v *vigiliteia) anchor(ctx context.Context) error {
+	records, err := v.db.Unanchored()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	leaves := make([]chainhash.Hash, len(records))
+	for i, hash := range records {
+		leaf, err := chainhash.NewHashFromStr(hash)
+		if err != nil {
+			return err
+		}
+		leaves[i] = *leaf
+	}
+	root := standalone.CalcMerkleRoot(leaves)
+	txHash, err := v.wallet.PublishAnchor(ctx, record.AnchorScript(&root))
+	if err != nil {
+		return fmt.Errorf("unable to anchor records: %w", err)
+	}
+	err = v.db.InsertAnchor(&database.Anchor{
+		TxHash:    txHash.String(),
+		Root:      root.String(),
+		Records:   records,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	vgtaLog.Infof("Anchored %d records with merkle root %s in transaction "+
+		"%s", len(records), root, txHash)
+	return nil
+}
+
+// confirmAnchors marks the anchor transactions which have enough
+// confirmations as confirmed.  Anchor transactions which are no longer known
+// to the node are dropped so their records are anchored again.
+func (v *vigiliteia) confirmAnchors(ctx context.Context) error {
+	anchors, err := v.db.FilterAnchors(func(a *database.Anchor) bool {
+		return !a.Confirmed
+	})
+	if err != nil {
+		return err
+	}
+	for _, anchor := range anchors {
+		hash, err := chainhash.NewHashFromStr(anchor.TxHash)
+		if err != nil {
+			return err
+		}
+		tx, err := v.node.Transaction(ctx, hash)
+		if err != nil {
+			return err
+		}
+		if tx == nil {
+			vgtaLog.Warnf("Anchor transaction %s is no longer known to "+
+				"the node; its records will be anchored again",
+				anchor.TxHash)
+			if err := v.db.DropAnchor(anchor.TxHash); err != nil {
+				return err
+			}
+			continue
+		}
+		if tx.Confirmations < requiredConfs {
+			continue
+		}
+		anchor.Confirmed = true
+		anchor.Height = tx.BlockHeight
+		anchor.BlockHash = tx.BlockHash
+		if err := v.db.UpdateAnchor(anchor); err != nil {
+			return err
+		}
+		vgtaLog.Infof("Anchor transaction %s confirmed at height %d",
+			anchor.TxHash, anchor.Height)
+	}
+	return nil
+}
+
+// finishVotes ends the votes on proposals which reached their end height at
+// the provided height.
+func (v *vigiliteia) finishVotes(height int64) error {
+	proposals, err := v.db.FilterProposals(func(p *database.Proposal) bool {
+		return p.Status == database.StatusVoting && height >= p.EndHeight
+	})
+	if err != nil {
+		return err
+	}
+	for _, p := range proposals {
+		finished, err := v.db.FinishVote(p.Token)
+		if err != nil {
+			return err
+		}
+		vgtaLog.Infof("Proposal %s %s with %d yes and %d no votes of %d "+
+			"eligible tickets", finished.Token, finished.Status,
+			finished.Yes, finished.No, finished.EligibleTickets)
+	}
+	return nil
+}
+
+// fundProposals creates the treasury spends paying out the approved proposals
+// which request funds.
+func (v *vigiliteia) fundProposals(ctx context.Context) error {
+	if v.treasury == nil {
+		return nil
+	}
+	proposals, err := v.db.FilterProposals(func(p *database.Proposal) bool {
+		return p.Status == database.StatusApproved && len(p.Payouts) > 0 &&
+			p.TSpendHash == ""
+	})
+	if err != nil {
+		return err
+	}
+	for _, p := range proposals {
+		hash, err := v.treasury.SendFromTreasury(ctx, p.Payouts)
+		if err != nil {
+			return fmt.Errorf("unable to create treasury spend for "+
+				"proposal %s: %w", p.Token, err)
+		}
+		p.TSpendHash = hash.String()
+		if err := v.db.UpdateProposal(p); err != nil {
+			return err
+		}
+		vgtaLog.Infof("Created treasury spend %s for proposal %s",
+			p.TSpendHash, p.Token)
+	}
+	return nil
+}
+
+// update brings the proposals in line with the best block of the node.
+func (v *vigiliteia) update(ctx context.Context) error {
+	header, err := v.node.BestBlock(ctx)
+	if err != nil {
+		return err
+	}
+	vgtaLog.Debugf("Updating at height %d", header.Height)
+
+	if err := v.confirmAnchors(ctx); err != nil {
+		return fmt.Errorf("unable to confirm anchors: %w", err)
+	}
+	if err := v.finishVotes(int64(header.Height)); err != nil {
+		return fmt.Errorf("unable to finish votes: %w", err)
+	}
+	if err := v.fundProposals(ctx); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,442 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/slog"
+)
+
+const (
+	appName                 = "vigiliteia"
+	defaultConfigFilename   = "vigiliteia.conf"
+	defaultDataDirname      = "data"
+	defaultLogDirname       = "logs"
+	defaultLogFilename      = "vigiliteia.log"
+	defaultDBFilename       = "vigiliteia.db"
+	defaultLogLevel         = "info"
+	defaultListenPort       = "8820"
+	defaultAnchorInterval   = time.Hour
+	defaultQuorumPercentage = 20
+	defaultPassPercentage   = 60
+)
+
+var (
+	defaultHomeDir          = VGLutil.AppDataDir(appName, false)
+	defaultConfigFile       = filepath.Join(defaultHomeDir, defaultConfigFilename)
+	defaultNodeRPCCertFile  = filepath.Join(VGLutil.AppDataDir("vgld", false), "rpc.cert")
+	defaultWalletClientCert = filepath.Join(defaultHomeDir, "client.pem")
+	defaultWalletClientKey  = filepath.Join(defaultHomeDir, "client-key.pem")
+	defaultWalletCertFile   = filepath.Join(VGLutil.AppDataDir("vglwallet", false), "rpc.cert")
+)
+
+// config defines the configuration options for vigiliteia.
+//
+// See loadConfig for details on the configuration load process.
+type config struct {
+	// General application behavior.
+	HomeDir    string `short:"A" long:"appdata" description:"Path to application home directory"`
+	ConfigFile string `short:"C" long:"configfile" description:"Path to configuration file"`
+	DataDir    string `short:"b" long:"datadir" description:"Directory to store data"`
+	LogDir     string `long:"logdir" description:"Directory to log output"`
+	TestNet    bool   `long:"testnet" description:"Use the test network"`
+	SimNet     bool   `long:"simnet" description:"Use the simulation test network"`
+	RegNet     bool   `long:"regnet" description:"Use the regression test network"`
+	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+
+	// Node RPC options.
+	NodeRPCConnect string `long:"noderpcconnect" description:"Hostname/IP and port of the vgld RPC server (default port: 9109, testnet: 19109)"`
+	NodeRPCUser    string `long:"noderpcuser" description:"Username for vgld RPC connections"`
+	NodeRPCPass    string `long:"noderpcpass" default-mask:"-" description:"Password for vgld RPC connections"`
+	NodeRPCCert    string `long:"noderpccert" description:"File containing the vgld RPC certificate"`
+
+	// Wallet options.
+	WalletHost       string `long:"wallethost" description:"Hostname/IP and port of the gRPC server of the wallet which pays for the anchor transactions (default port: 9111, testnet: 19111)"`
+	WalletCert       string `long:"walletcert" description:"File containing the certificate of the wallet RPC servers"`
+	WalletClientCert string `long:"walletclientcert" description:"File containing the client certificate to authenticate with the wallet gRPC server"`
+	WalletClientKey  string `long:"walletclientkey" description:"File containing the client key to authenticate with the wallet gRPC server"`
+	WalletPass       string `long:"walletpass" default-mask:"-" description:"Private passphrase of the wallet used to sign the anchor transactions"`
+	WalletAccount    uint32 `long:"walletaccount" description:"Account of the wallet which funds the anchor transactions"`
+
+	// Treasury options.
+	TreasuryKey      string `long:"treasurykey" description:"Vigiliteia public key used by the wallet to create treasury spends for approved proposals -- Treasury spends are not created when not set"`
+	WalletRPCConnect string `long:"walletrpcconnect" description:"Hostname/IP and port of the wallet JSON-RPC server (default: the host of wallethost with port 9110, testnet: 19110)"`
+	WalletRPCUser    string `long:"walletrpcuser" description:"Username for wallet JSON-RPC connections"`
+	WalletRPCPass    string `long:"walletrpcpass" default-mask:"-" description:"Password for wallet JSON-RPC connections"`
+
+	// Governance options.
+	AnchorInterval   time.Duration `long:"anchorinterval" description:"Interval at which new records are anchored into the chain"`
+	VoteDuration     int64         `long:"voteduration" description:"Number of blocks a vote on a proposal lasts (default: 4032, testnet: 720)"`
+	QuorumPercentage uint32        `long:"quorumpercentage" description:"Percentage of the eligible tickets which must vote on a proposal"`
+	PassPercentage   uint32        `long:"passpercentage" description:"Percentage of yes votes required to approve a proposal"`
+
+	// HTTP server options.
+	Listeners []string `long:"listen" description:"Add an interface/port to serve the API on (default: localhost:8820)"`
+
+	// Cooked options ready for use.
+	params *params
+}
+
+// cleanAndExpandPath expands environment variables and leading ~ in the
+// passed path, cleans the result, and returns it.
+func cleanAndExpandPath(path string) string {
+	// Nothing to do when no path is given.
+	if path == "" {
+		return path
+	}
+
+	// NOTE: The os.ExpandEnv doesn't work with Windows cmd.exe-style
+	// %VARIABLE%, but the variables can still be expanded via POSIX-style
+	// $VARIABLE.
+	path = os.ExpandEnv(path)
+
+	if !strings.HasPrefix(path, "~") {
+		return filepath.Clean(path)
+	}
+
+	// Expand initial ~ to the current user's home directory, or ~otheruser
+	// to otheruser's home directory.  On Windows, both forward and backward
+	// slashes can be used.
+	path = path[1:]
+
+	var pathSeparators string
+	if runtime.GOOS == "windows" {
+		pathSeparators = string(os.PathSeparator) + "/"
+	} else {
+		pathSeparators = string(os.PathSeparator)
+	}
+
+	userName := ""
+	if i := strings.IndexAny(path, pathSeparators); i != -1 {
+		userName = path[:i]
+		path = path[i:]
+	}
+
+	homeDir := ""
+	var u *user.User
+	var err error
+	if userName == "" {
+		u, err = user.Current()
+	} else {
+		u, err = user.Lookup(userName)
+	}
+	if err == nil {
+		homeDir = u.HomeDir
+	}
+	// Fallback to CWD if user lookup fails or user has no home directory.
+	if homeDir == "" {
+		homeDir = "."
+	}
+
+	return filepath.Join(homeDir, path)
+}
+
+// validLogLevel returns whether or not logLevel is a valid debug log level.
+func validLogLevel(logLevel string) bool {
+	_, ok := slog.LevelFromString(logLevel)
+	return ok
+}
+
+// supportedSubsystems returns a sorted slice of the supported subsystems for
+// logging purposes.
+func supportedSubsystems() []string {
+	// Convert the subsystemLoggers map keys to a slice.
+	subsystems := make([]string, 0, len(subsystemLoggers))
+	for subsysID := range subsystemLoggers {
+		subsystems = append(subsystems, subsysID)
+	}
+
+	// Sort the subsystems for stable display.
+	sort.Strings(subsystems)
+	return subsystems
+}
+
+// parseAndSetDebugLevels attempts to parse the specified debug level and set
+// the levels accordingly.  An appropriate error is returned if anything is
+// invalid.
+func parseAndSetDebugLevels(debugLevel string) error {
+	// When the specified string doesn't have any delimiters, treat it as
+	// the log level for all subsystems.
+	if !strings.Contains(debugLevel, ",") && !strings.Contains(debugLevel, "=") {
+		// Validate debug log level.
+		if !validLogLevel(debugLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, debugLevel)
+		}
+
+		// Change the logging level for all subsystems.
+		setLogLevels(debugLevel)
+
+		return nil
+	}
+
+	// Split the specified string into subsystem/level pairs while detecting
+	// issues and update the log levels accordingly.
+	for _, logLevelPair := range strings.Split(debugLevel, ",") {
+		if !strings.Contains(logLevelPair, "=") {
+			str := "the specified debug level contains an invalid " +
+				"subsystem/level pair [%v]"
+			return fmt.Errorf(str, logLevelPair)
+		}
+
+		// Extract the specified subsystem and log level.
+		fields := strings.Split(logLevelPair, "=")
+		subsysID, logLevel := fields[0], fields[1]
+
+		// Validate subsystem.
+		if _, exists := subsystemLoggers[subsysID]; !exists {
+			str := "the specified subsystem [%v] is invalid -- " +
+				"supported subsystems %v"
+			return fmt.Errorf(str, subsysID, supportedSubsystems())
+		}
+
+		// Validate log level.
+		if !validLogLevel(logLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, logLevel)
+		}
+
+		setLogLevel(subsysID, logLevel)
+	}
+
+	return nil
+}
+
+// normalizeAddress returns addr with the passed default port appended if there
+// is not already a port specified.
+func normalizeAddress(addr, defaultPort string) string {
+	_, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return net.JoinHostPort(addr, defaultPort)
+	}
+	return addr
+}
+
+// fileExists reports whether the named file or directory exists.
+func fileExists(name string) bool {
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadConfig initializes and parses the config using a config file and command
+// line options.
+//
+// The configuration proceeds as follows:
+//  1. Start with a default config with sane settings
+//  2. Pre-parse the command line to check for an alternative config file
+//  3. Load configuration file overwriting defaults with any specified options
+//  4. Parse CLI options and overwrite/add any specified options
+//
+// The above results in vigiliteia functioning properly without any config
+// settings while still allowing the user to override settings with config
+// files and command line options.  Command line options always take
+// precedence.
+func loadConfig() (*config, error) {
+	// Default config.
+	cfg := config{
+		HomeDir:          defaultHomeDir,
+		ConfigFile:       defaultConfigFile,
+		DebugLevel:       defaultLogLevel,
+		NodeRPCCert:      defaultNodeRPCCertFile,
+		WalletCert:       defaultWalletCertFile,
+		WalletClientCert: defaultWalletClientCert,
+		WalletClientKey:  defaultWalletClientKey,
+		AnchorInterval:   defaultAnchorInterval,
+		QuorumPercentage: defaultQuorumPercentage,
+		PassPercentage:   defaultPassPercentage,
+	}
+
+	// Pre-parse the command line options to see if an alternative config
+	// file or home directory was specified.  Any errors aside from the help
+	// message error can be ignored here since they will be caught by the
+	// final parse below.
+	preCfg := cfg
+	preParser := flags.NewParser(&preCfg, flags.HelpFlag)
+	_, err := preParser.Parse()
+	if err != nil {
+		var e *flags.Error
+		if errors.As(err, &e) && e.Type == flags.ErrHelp {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+	}
+
+	// Update the home directory and config file location if specified.
+	if preCfg.HomeDir != defaultHomeDir {
+		cfg.HomeDir = cleanAndExpandPath(preCfg.HomeDir)
+		if preCfg.ConfigFile == defaultConfigFile {
+			preCfg.ConfigFile = filepath.Join(cfg.HomeDir,
+				defaultConfigFilename)
+		}
+	}
+
+	// Load additional config from file.
+	parser := flags.NewParser(&cfg, flags.Default)
+	configFile := cleanAndExpandPath(preCfg.ConfigFile)
+	if fileExists(configFile) {
+		err := flags.NewIniParser(parser).ParseFile(configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+			return nil, err
+		}
+	}
+
+	// Parse command line options again to ensure they take precedence.
+	_, err = parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// Multiple networks can't be selected simultaneously.  Count the number
+	// of network flags passed and assign the active network params.
+	funcName := "loadConfig"
+	numNets := 0
+	cfg.params = &mainNetParams
+	if cfg.TestNet {
+		numNets++
+		cfg.params = &testNet3Params
+	}
+	if cfg.SimNet {
+		numNets++
+		cfg.params = &simNetParams
+	}
+	if cfg.RegNet {
+		numNets++
+		cfg.params = &regNetParams
+	}
+	if numNets > 1 {
+		str := "%s: the testnet, regnet, and simnet params can't be " +
+			"used together -- choose one of the three"
+		return nil, fmt.Errorf(str, funcName)
+	}
+
+	// Namespace the data and log directories per network.
+	if cfg.DataDir == "" {
+		cfg.DataDir = filepath.Join(cfg.HomeDir, defaultDataDirname)
+	}
+	cfg.DataDir = filepath.Join(cleanAndExpandPath(cfg.DataDir),
+		cfg.params.Name)
+	if cfg.LogDir == "" {
+		cfg.LogDir = filepath.Join(cfg.HomeDir, defaultLogDirname)
+	}
+	cfg.LogDir = filepath.Join(cleanAndExpandPath(cfg.LogDir), cfg.params.Name)
+
+	// Special show command to list supported subsystems and exit.
+	if cfg.DebugLevel == "show" {
+		fmt.Println("Supported subsystems", supportedSubsystems())
+		os.Exit(0)
+	}
+
+	// Initialize log rotation.  After log rotation has been initialized, the
+	// logger variables may be used.
+	initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename))
+
+	// Parse, validate, and set debug log level(s).
+	if err := parseAndSetDebugLevels(cfg.DebugLevel); err != nil {
+		return nil, fmt.Errorf("%s: %w", funcName, err)
+	}
+
+	// Connect to the node on localhost with the default port for the active
+	// network by default.
+	if cfg.NodeRPCConnect == "" {
+		cfg.NodeRPCConnect = "localhost"
+	}
+	cfg.NodeRPCConnect = normalizeAddress(cfg.NodeRPCConnect,
+		cfg.params.nodeRPCPort)
+	cfg.NodeRPCCert = cleanAndExpandPath(cfg.NodeRPCCert)
+
+	// The wallet is required to anchor the records.  The port of the wallet
+	// must be specified on networks without a default port.
+	if cfg.WalletHost == "" {
+		str := "%s: the wallethost option is required"
+		return nil, fmt.Errorf(str, funcName)
+	}
+	if cfg.params.walletGRPCPort == "" {
+		if _, _, err := net.SplitHostPort(cfg.WalletHost); err != nil {
+			str := "%s: the port of wallethost %s must be specified on %s"
+			return nil, fmt.Errorf(str, funcName, cfg.WalletHost,
+				cfg.params.Name)
+		}
+	} else {
+		cfg.WalletHost = normalizeAddress(cfg.WalletHost,
+			cfg.params.walletGRPCPort)
+	}
+	cfg.WalletCert = cleanAndExpandPath(cfg.WalletCert)
+	cfg.WalletClientCert = cleanAndExpandPath(cfg.WalletClientCert)
+	cfg.WalletClientKey = cleanAndExpandPath(cfg.WalletClientKey)
+	if cfg.AnchorInterval < time.Minute {
+		str := "%s: the anchorinterval option must be at least one minute"
+		return nil, fmt.Errorf(str, funcName)
+	}
+
+	// Connect to the JSON-RPC server of the wallet on the host of its gRPC
+	// server by default when treasury spends are created.
+	if cfg.TreasuryKey != "" {
+		if cfg.WalletRPCConnect == "" {
+			host, _, err := net.SplitHostPort(cfg.WalletHost)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", funcName, err)
+			}
+			cfg.WalletRPCConnect = host
+		}
+		if cfg.params.walletRPCPort == "" {
+			_, _, err := net.SplitHostPort(cfg.WalletRPCConnect)
+			if err != nil {
+				str := "%s: the port of walletrpcconnect %s must be " +
+					"specified on %s"
+				return nil, fmt.Errorf(str, funcName,
+					cfg.WalletRPCConnect, cfg.params.Name)
+			}
+		} else {
+			cfg.WalletRPCConnect = normalizeAddress(cfg.WalletRPCConnect,
+				cfg.params.walletRPCPort)
+		}
+	}
+
+	// Use the default vote duration of the active network unless one was
+	// specified.
+	if cfg.VoteDuration == 0 {
+		cfg.VoteDuration = cfg.params.voteDuration
+	}
+	if cfg.VoteDuration < 0 {
+		str := "%s: the voteduration option must be positive"
+		return nil, fmt.Errorf(str, funcName)
+	}
+	if cfg.QuorumPercentage < 1 || cfg.QuorumPercentage > 100 {
+		str := "%s: the quorumpercentage option must be between 1 and 100"
+		return nil, fmt.Errorf(str, funcName)
+	}
+	if cfg.PassPercentage < 1 || cfg.PassPercentage > 100 {
+		str := "%s: the passpercentage option must be between 1 and 100"
+		return nil, fmt.Errorf(str, funcName)
+	}
+
+	// Serve the API on localhost by default since it is typically run
+	// behind a reverse proxy.
+	if len(cfg.Listeners) == 0 {
+		cfg.Listeners = []string{"localhost"}
+	}
+	for i, addr := range cfg.Listeners {
+		cfg.Listeners[i] = normalizeAddress(addr, defaultListenPort)
+	}
+
+	return &cfg, nil
+}
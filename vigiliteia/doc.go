@@ -0,0 +1,94 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+vigiliteia is the proposal server of the Vigiliteia governance system.
+
+Proposals are signed records which are stored off-chain and voted on by the
+stakeholders with their tickets.  Every record -- a proposal, the
+authorization of the vote on it by its author, and every ticket vote -- is
+identified by its hash and signed over the message "Vigiliteia record <hash>"
+with the message signing of the node.  Proposals and vote authorizations are
+signed by the address of the author of the proposal and votes are signed by
+the commitment address of the ticket.
+
+The tickets which are live when the author starts the vote are eligible to
+vote on the proposal until the vote ends a configured number of blocks later.
+A proposal is approved when enough eligible tickets voted to reach the quorum
+and enough of the votes are yes.  The treasury spend paying out an approved
+proposal is created with the sendfromtreasury method of a wallet which holds
+the treasury key.
+
+The hashes of new records are periodically anchored into the chain by a
+transaction which commits to their merkle root in a null data output.  Once
+the transaction is confirmed, the inclusion of every record can be proven
+against the root to show that it existed at the time of the block.
+
+The API provides the following endpoints:
+
+	GET  /api/v1/proposals               Proposals, newest first
+	POST /api/v1/proposals               Submit a new proposal
+	GET  /api/v1/proposals/{token}       Proposal and the state of its vote
+	GET  /api/v1/proposals/{token}/votes Votes cast on a proposal
+	POST /api/v1/startvote               Start the vote on a proposal
+	POST /api/v1/castvotes               Cast ticket votes
+	GET  /api/v1/proofs/{hash}           Proof that a record was anchored
+
+Usage:
+
+	vigiliteia [OPTIONS]
+
+Application Options:
+
+	-A, --appdata=             Path to application home directory
+	-C, --configfile=          Path to configuration file
+	-b, --datadir=             Directory to store data
+	    --logdir=              Directory to log output
+	    --testnet              Use the test network
+	    --simnet               Use the simulation test network
+	    --regnet               Use the regression test network
+	-d, --debuglevel=          Logging level for all subsystems {trace, debug,
+	                           info, warn, error, critical} (info)
+	    --noderpcconnect=      Hostname/IP and port of the vgld RPC server
+	                           (default port: 9109, testnet: 19109)
+	    --noderpcuser=         Username for vgld RPC connections
+	    --noderpcpass=         Password for vgld RPC connections
+	    --noderpccert=         File containing the vgld RPC certificate
+	    --wallethost=          Hostname/IP and port of the gRPC server of the
+	                           wallet which pays for the anchor transactions
+	                           (default port: 9111, testnet: 19111)
+	    --walletcert=          File containing the certificate of the wallet
+	                           RPC servers
+	    --walletclientcert=    File containing the client certificate to
+	                           authenticate with the wallet gRPC server
+	    --walletclientkey=     File containing the client key to authenticate
+	                           with the wallet gRPC server
+	    --walletpass=          Private passphrase of the wallet used to sign
+	                           the anchor transactions
+	    --walletaccount=       Account of the wallet which funds the anchor
+	                           transactions (0)
+	    --treasurykey=         Vigiliteia public key used by the wallet to
+	                           create treasury spends for approved proposals
+	                           -- Treasury spends are not created when not set
+	    --walletrpcconnect=    Hostname/IP and port of the wallet JSON-RPC
+	                           server (default: the host of wallethost with
+	                           port 9110, testnet: 19110)
+	    --walletrpcuser=       Username for wallet JSON-RPC connections
+	    --walletrpcpass=       Password for wallet JSON-RPC connections
+	    --anchorinterval=      Interval at which new records are anchored into
+	                           the chain (1h)
+	    --voteduration=        Number of blocks a vote on a proposal lasts
+	                           (default: 4032, testnet: 720)
+	    --quorumpercentage=    Percentage of the eligible tickets which must
+	                           vote on a proposal (20)
+	    --passpercentage=      Percentage of yes votes required to approve a
+	                           proposal (60)
+	    --listen=              Add an interface/port to serve the API on
+	                           (default: localhost:8820)
+
+Help Options:
+
+	-h, --help                 Show this help message
+*/
+package main
@@ -0,0 +1,549 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package database stores the proposals, vote authorizations, and votes of the
+// proposal system along with the transactions anchoring them into the chain in
+// an embedded database.
+package database
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kdsmith18542/vigil/vigiliteia/record"
+	bolt "go.etcd.io/bbolt"
+)
+
+// dbVersion is the current version of the database layout.
+const dbVersion = 1
+
+var (
+	metaBucket       = []byte("meta")
+	proposalsBucket  = []byte("proposals")
+	eligibleBucket   = []byte("eligible")
+	votesBucket      = []byte("votes")
+	recordsBucket    = []byte("records")
+	unanchoredBucket = []byte("unanchored")
+	anchorsBucket    = []byte("anchors")
+	versionKey       = []byte("version")
+)
+
+// Status identifies the state of a proposal.  The values are part of the API.
+type Status string
+
+// These constants define the possible proposal statuses.
+const (
+	// StatusPreVote indicates the author has not yet authorized the vote on
+	// the proposal.
+	StatusPreVote Status = "prevote"
+
+	// StatusVoting indicates the vote on the proposal is in progress.
+	StatusVoting Status = "voting"
+
+	// StatusApproved indicates the vote on the proposal reached the quorum
+	// and the required percentage of yes votes.
+	StatusApproved Status = "approved"
+
+	// StatusRejected indicates the vote on the proposal failed to reach the
+	// quorum or the required percentage of yes votes.
+	StatusRejected Status = "rejected"
+)
+
+// Proposal is a proposal along with the state of the vote on it.
+type Proposal struct {
+	record.Proposal
+	Token  string `json:"token"`
+	Status Status `json:"status"`
+
+	// The following fields are set once the vote is started.  The eligible
+	// tickets are the tickets that were live at the start height and the
+	// percentages are fixed for the duration of the vote.
+	StartVote        *record.StartVote `json:"startvote,omitempty"`
+	StartHeight      int64             `json:"startheight,omitempty"`
+	EndHeight        int64             `json:"endheight,omitempty"`
+	EligibleTickets  int64             `json:"eligibletickets,omitempty"`
+	QuorumPercentage uint32            `json:"quorumpercentage,omitempty"`
+	PassPercentage   uint32            `json:"passpercentage,omitempty"`
+	Yes              int64             `json:"yes"`
+	No               int64             `json:"no"`
+
+	// TSpendHash is the hash of the treasury spend which pays out an
+	// approved proposal.
+	TSpendHash string `json:"tspendhash,omitempty"`
+}
+
+// QuorumReached returns whether enough of the eligible tickets voted on the
+// proposal.
+func (p *Proposal) QuorumReached() bool {
+	total := p.Yes + p.No
+	return total > 0 &&
+		total*100 >= p.EligibleTickets*int64(p.QuorumPercentage)
+}
+
+// Approved returns whether the quorum was reached and enough of the votes are
+// yes votes for the proposal to be approved.
+func (p *Proposal) Approved() bool {
+	return p.QuorumReached() &&
+		p.Yes*100 >= (p.Yes+p.No)*int64(p.PassPercentage)
+}
+
+// Anchor is a transaction which commits to the merkle root of a set of record
+// hashes in a null data output.  The position of a record hash in Records is
+// its leaf index in the merkle tree.
+type Anchor struct {
+	TxHash    string   `json:"txhash"`
+	Root      string   `json:"root"`
+	Records   []string `json:"records"`
+	Timestamp int64    `json:"timestamp"`
+
+	// The following fields are set once the transaction has enough
+	// confirmations.
+	Confirmed bool   `json:"confirmed"`
+	Height    int64  `json:"height,omitempty"`
+	BlockHash string `json:"blockhash,omitempty"`
+}
+
+// DB is the proposal database.
+type DB struct {
+	bdb *bolt.DB
+}
+
+// Open opens the database at the provided path and creates it when it does not
+// exist.
+func Open(path string) (*DB, error) {
+	bdb, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = bdb.Update(func(tx *bolt.Tx) error {
+		buckets := [][]byte{metaBucket, proposalsBucket, eligibleBucket,
+			votesBucket, recordsBucket, unanchoredBucket, anchorsBucket}
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		meta := tx.Bucket(metaBucket)
+		v := meta.Get(versionKey)
+		if v == nil {
+			var b [4]byte
+			binary.BigEndian.PutUint32(b[:], dbVersion)
+			return meta.Put(versionKey, b[:])
+		}
+		if len(v) != 4 || binary.BigEndian.Uint32(v) > dbVersion {
+			str := fmt.Sprintf("proposal database version %x is newer "+
+				"than the supported version %d", v, dbVersion)
+			return makeError(ErrBadVersion, str)
+		}
+		return nil
+	})
+	if err != nil {
+		bdb.Close()
+		return nil, err
+	}
+	return &DB{bdb: bdb}, nil
+}
+
+// Close closes the database.
+func (db *DB) Close() error {
+	return db.bdb.Close()
+}
+
+// addRecord stores the hash of a new record which is not yet anchored.
+func addRecord(tx *bolt.Tx, hash string) error {
+	if err := tx.Bucket(recordsBucket).Put([]byte(hash), []byte{}); err != nil {
+		return err
+	}
+	return tx.Bucket(unanchoredBucket).Put([]byte(hash), []byte{})
+}
+
+// putProposal stores the proposal in the proposals bucket.
+func putProposal(tx *bolt.Tx, p *Proposal) error {
+	v, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(proposalsBucket).Put([]byte(p.Token), v)
+}
+
+// getProposal returns the proposal with the provided token from the proposals
+// bucket.
+func getProposal(tx *bolt.Tx, token string) (*Proposal, error) {
+	v := tx.Bucket(proposalsBucket).Get([]byte(token))
+	if v == nil {
+		str := fmt.Sprintf("proposal %s does not exist", token)
+		return nil, makeError(ErrProposalNotFound, str)
+	}
+	var p Proposal
+	if err := json.Unmarshal(v, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// InsertProposal stores a new proposal.  The token of the proposal is stored as
+// a record hash to be anchored.  ErrProposalExists is returned when the
+// proposal is already stored.
+func (db *DB) InsertProposal(p *Proposal) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(proposalsBucket).Get([]byte(p.Token)) != nil {
+			str := fmt.Sprintf("proposal %s already exists", p.Token)
+			return makeError(ErrProposalExists, str)
+		}
+		if err := addRecord(tx, p.Token); err != nil {
+			return err
+		}
+		return putProposal(tx, p)
+	})
+}
+
+// UpdateProposal replaces a stored proposal.  ErrProposalNotFound is returned
+// when the proposal is not stored.
+func (db *DB) UpdateProposal(p *Proposal) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		if _, err := getProposal(tx, p.Token); err != nil {
+			return err
+		}
+		return putProposal(tx, p)
+	})
+}
+
+// GetProposal returns the proposal with the provided token.
+// ErrProposalNotFound is returned when the proposal is not stored.
+func (db *DB) GetProposal(token string) (*Proposal, error) {
+	var p *Proposal
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		var err error
+		p, err = getProposal(tx, token)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// FilterProposals returns all proposals for which the provided filter returns
+// true, newest first.
+func (db *DB) FilterProposals(filter func(*Proposal) bool) ([]*Proposal, error) {
+	var proposals []*Proposal
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(proposalsBucket).ForEach(func(_, v []byte) error {
+			var p Proposal
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			if filter(&p) {
+				proposals = append(proposals, &p)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		if proposals[i].Timestamp != proposals[j].Timestamp {
+			return proposals[i].Timestamp > proposals[j].Timestamp
+		}
+		return proposals[i].Token < proposals[j].Token
+	})
+	return proposals, nil
+}
+
+// StartVote replaces the stored proposal, which must have its vote
+// authorization set, and stores the tickets which are eligible to vote on it.
+// The hash of the vote authorization is stored as a record hash to be
+// anchored.  ErrWrongStatus is returned when the vote on the stored proposal
+// was already started.
+func (db *DB) StartVote(p *Proposal, tickets []string) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		stored, err := getProposal(tx, p.Token)
+		if err != nil {
+			return err
+		}
+		if stored.Status != StatusPreVote {
+			str := fmt.Sprintf("the vote on proposal %s was already "+
+				"started", p.Token)
+			return makeError(ErrWrongStatus, str)
+		}
+		eligible, err := tx.Bucket(eligibleBucket).CreateBucket([]byte(p.Token))
+		if err != nil {
+			return err
+		}
+		for _, ticket := range tickets {
+			if err := eligible.Put([]byte(ticket), []byte{}); err != nil {
+				return err
+			}
+		}
+		hash := p.StartVote.Hash()
+		if err := addRecord(tx, hash.String()); err != nil {
+			return err
+		}
+		return putProposal(tx, p)
+	})
+}
+
+// IsEligible returns whether the provided ticket is eligible to vote on the
+// proposal with the provided token.
+func (db *DB) IsEligible(token, ticket string) (bool, error) {
+	var eligible bool
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(eligibleBucket).Bucket([]byte(token))
+		eligible = b != nil && b.Get([]byte(ticket)) != nil
+		return nil
+	})
+	return eligible, err
+}
+
+// InsertVote stores a vote and adds it to the tally of the proposal.  The hash
+// of the vote is stored as a record hash to be anchored.  ErrWrongStatus is
+// returned when the proposal is not being voted on and ErrDuplicateVote when
+// the ticket already voted on the proposal.
+func (db *DB) InsertVote(vote *record.Vote) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		p, err := getProposal(tx, vote.Token)
+		if err != nil {
+			return err
+		}
+		if p.Status != StatusVoting {
+			str := fmt.Sprintf("proposal %s is not being voted on",
+				vote.Token)
+			return makeError(ErrWrongStatus, str)
+		}
+		votes, err := tx.Bucket(votesBucket).CreateBucketIfNotExists(
+			[]byte(vote.Token))
+		if err != nil {
+			return err
+		}
+		if votes.Get([]byte(vote.Ticket)) != nil {
+			str := fmt.Sprintf("ticket %s already voted on proposal %s",
+				vote.Ticket, vote.Token)
+			return makeError(ErrDuplicateVote, str)
+		}
+		v, err := json.Marshal(vote)
+		if err != nil {
+			return err
+		}
+		if err := votes.Put([]byte(vote.Ticket), v); err != nil {
+			return err
+		}
+		hash := vote.Hash()
+		if err := addRecord(tx, hash.String()); err != nil {
+			return err
+		}
+		if vote.Choice == record.VoteYes {
+			p.Yes++
+		} else {
+			p.No++
+		}
+		return putProposal(tx, p)
+	})
+}
+
+// FinishVote ends the vote on the proposal with the provided token and marks
+// it as approved or rejected according to its tally.  ErrWrongStatus is
+// returned when the proposal is not being voted on.
+func (db *DB) FinishVote(token string) (*Proposal, error) {
+	var p *Proposal
+	err := db.bdb.Update(func(tx *bolt.Tx) error {
+		var err error
+		p, err = getProposal(tx, token)
+		if err != nil {
+			return err
+		}
+		if p.Status != StatusVoting {
+			str := fmt.Sprintf("proposal %s is not being voted on", token)
+			return makeError(ErrWrongStatus, str)
+		}
+		p.Status = StatusRejected
+		if p.Approved() {
+			p.Status = StatusApproved
+		}
+		return putProposal(tx, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Votes returns the votes on the proposal with the provided token ordered by
+// ticket.
+func (db *DB) Votes(token string) ([]*record.Vote, error) {
+	var votes []*record.Vote
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		if _, err := getProposal(tx, token); err != nil {
+			return err
+		}
+		b := tx.Bucket(votesBucket).Bucket([]byte(token))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var vote record.Vote
+			if err := json.Unmarshal(v, &vote); err != nil {
+				return err
+			}
+			votes = append(votes, &vote)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// Unanchored returns the hashes of the records which are not included in any
+// anchor transaction in ascending order.
+func (db *DB) Unanchored() ([]string, error) {
+	var hashes []string
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(unanchoredBucket).ForEach(func(k, _ []byte) error {
+			hashes = append(hashes, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// putAnchor stores the anchor in the anchors bucket.
+func putAnchor(tx *bolt.Tx, anchor *Anchor) error {
+	v, err := json.Marshal(anchor)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(anchorsBucket).Put([]byte(anchor.TxHash), v)
+}
+
+// getAnchor returns the anchor with the provided transaction hash from the
+// anchors bucket.
+func getAnchor(tx *bolt.Tx, txHash string) (*Anchor, error) {
+	v := tx.Bucket(anchorsBucket).Get([]byte(txHash))
+	if v == nil {
+		str := fmt.Sprintf("anchor %s does not exist", txHash)
+		return nil, makeError(ErrAnchorNotFound, str)
+	}
+	var anchor Anchor
+	if err := json.Unmarshal(v, &anchor); err != nil {
+		return nil, err
+	}
+	return &anchor, nil
+}
+
+// InsertAnchor stores a new anchor transaction and marks its records as
+// anchored by it.
+func (db *DB) InsertAnchor(anchor *Anchor) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		records := tx.Bucket(recordsBucket)
+		unanchored := tx.Bucket(unanchoredBucket)
+		for _, hash := range anchor.Records {
+			if records.Get([]byte(hash)) == nil {
+				str := fmt.Sprintf("record %s does not exist", hash)
+				return makeError(ErrRecordNotFound, str)
+			}
+			err := records.Put([]byte(hash), []byte(anchor.TxHash))
+			if err != nil {
+				return err
+			}
+			if err := unanchored.Delete([]byte(hash)); err != nil {
+				return err
+			}
+		}
+		return putAnchor(tx, anchor)
+	})
+}
+
+// UpdateAnchor replaces a stored anchor transaction.  ErrAnchorNotFound is
+// returned when the anchor is not stored.
+func (db *DB) UpdateAnchor(anchor *Anchor) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		if _, err := getAnchor(tx, anchor.TxHash); err != nil {
+			return err
+		}
+		return putAnchor(tx, anchor)
+	})
+}
+
+// DropAnchor removes an anchor transaction which will never be mined and
+// marks its records as unanchored again so they are included in the next
+// anchor.
+func (db *DB) DropAnchor(txHash string) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		anchor, err := getAnchor(tx, txHash)
+		if err != nil {
+			return err
+		}
+		for _, hash := range anchor.Records {
+			if err := addRecord(tx, hash); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(anchorsBucket).Delete([]byte(txHash))
+	})
+}
+
+// FilterAnchors returns all anchor transactions for which the provided filter
+// returns true.
+func (db *DB) FilterAnchors(filter func(*Anchor) bool) ([]*Anchor, error) {
+	var anchors []*Anchor
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(anchorsBucket).ForEach(func(_, v []byte) error {
+			var anchor Anchor
+			if err := json.Unmarshal(v, &anchor); err != nil {
+				return err
+			}
+			if filter(&anchor) {
+				anchors = append(anchors, &anchor)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return anchors, nil
+}
+
+// RecordAnchor returns the confirmed anchor transaction which includes the
+// record with the provided hash.  ErrRecordNotFound is returned when the record
+// does not exist and ErrRecordNotAnchored when it is not yet included in a
+// confirmed anchor transaction.
+func (db *DB) RecordAnchor(hash string) (*Anchor, error) {
+	var anchor *Anchor
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		txHash := tx.Bucket(recordsBucket).Get([]byte(hash))
+		if txHash == nil {
+			str := fmt.Sprintf("record %s does not exist", hash)
+			return makeError(ErrRecordNotFound, str)
+		}
+		notAnchored := makeError(ErrRecordNotAnchored,
+			fmt.Sprintf("record %s is not anchored yet", hash))
+		if len(txHash) == 0 {
+			return notAnchored
+		}
+		var err error
+		anchor, err = getAnchor(tx, string(txHash))
+		if err != nil {
+			return err
+		}
+		if !anchor.Confirmed {
+			return notAnchored
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return anchor, nil
+}
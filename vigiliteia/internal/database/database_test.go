@@ -0,0 +1,249 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kdsmith18542/vigil/vigiliteia/record"
+)
+
+// TestProposalLifecycle ensures a proposal can be stored, voted on by its
+// eligible tickets only once per ticket, and finished according to its tally,
+// and that every record is tracked for anchoring.
+func TestProposalLifecycle(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "vigiliteia.db"))
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.GetProposal("p1"); !errors.Is(err, ErrProposalNotFound) {
+		t.Fatalf("unexpected error for unknown proposal -- got %v, want %v",
+			err, ErrProposalNotFound)
+	}
+	p := &Proposal{
+		Proposal:         record.Proposal{Name: "p1", Timestamp: 1},
+		Token:            "p1",
+		Status:           StatusPreVote,
+		QuorumPercentage: 50,
+		PassPercentage:   60,
+	}
+	if err := db.InsertProposal(p); err != nil {
+		t.Fatalf("unexpected error inserting proposal: %v", err)
+	}
+	if err := db.InsertProposal(p); !errors.Is(err, ErrProposalExists) {
+		t.Fatalf("unexpected error inserting proposal twice -- got %v, "+
+			"want %v", err, ErrProposalExists)
+	}
+	other := &Proposal{Token: "p2", Status: StatusPreVote,
+		Proposal: record.Proposal{Timestamp: 2}}
+	if err := db.InsertProposal(other); err != nil {
+		t.Fatalf("unexpected error inserting proposal: %v", err)
+	}
+
+	// Votes are only accepted while the proposal is being voted on.
+	vote := &record.Vote{Token: "p1", Ticket: "t1", Choice: record.VoteYes}
+	if err := db.InsertVote(vote); !errors.Is(err, ErrWrongStatus) {
+		t.Fatalf("unexpected error voting before the vote started -- got "+
+			"%v, want %v", err, ErrWrongStatus)
+	}
+
+	p.Status = StatusVoting
+	p.StartVote = &record.StartVote{Token: "p1", Timestamp: 3}
+	p.EligibleTickets = 4
+	if err := db.StartVote(p, []string{"t1", "t2", "t3", "t4"}); err != nil {
+		t.Fatalf("unexpected error starting vote: %v", err)
+	}
+	if err := db.StartVote(p, nil); !errors.Is(err, ErrWrongStatus) {
+		t.Fatalf("unexpected error starting vote twice -- got %v, want %v",
+			err, ErrWrongStatus)
+	}
+	for ticket, want := range map[string]bool{"t1": true, "t5": false} {
+		eligible, err := db.IsEligible("p1", ticket)
+		if err != nil {
+			t.Fatalf("unexpected error checking eligibility: %v", err)
+		}
+		if eligible != want {
+			t.Fatalf("unexpected eligibility of %s -- got %v, want %v",
+				ticket, eligible, want)
+		}
+	}
+
+	votes := []*record.Vote{
+		vote,
+		{Token: "p1", Ticket: "t2", Choice: record.VoteYes},
+		{Token: "p1", Ticket: "t3", Choice: record.VoteNo},
+	}
+	for _, v := range votes {
+		if err := db.InsertVote(v); err != nil {
+			t.Fatalf("unexpected error inserting vote: %v", err)
+		}
+	}
+	dup := &record.Vote{Token: "p1", Ticket: "t1", Choice: record.VoteNo}
+	if err := db.InsertVote(dup); !errors.Is(err, ErrDuplicateVote) {
+		t.Fatalf("unexpected error voting twice -- got %v, want %v", err,
+			ErrDuplicateVote)
+	}
+	gotVotes, err := db.Votes("p1")
+	if err != nil {
+		t.Fatalf("unexpected error fetching votes: %v", err)
+	}
+	if !reflect.DeepEqual(gotVotes, votes) {
+		t.Fatalf("unexpected votes -- got %+v, want %+v", gotVotes, votes)
+	}
+
+	// Two of three votes are yes with three of four tickets voting, which
+	// reaches both the quorum and the pass percentage.
+	finished, err := db.FinishVote("p1")
+	if err != nil {
+		t.Fatalf("unexpected error finishing vote: %v", err)
+	}
+	if finished.Yes != 2 || finished.No != 1 {
+		t.Fatalf("unexpected tally -- got %d/%d, want 2/1", finished.Yes,
+			finished.No)
+	}
+	if finished.Status != StatusApproved {
+		t.Fatalf("unexpected status -- got %s, want %s", finished.Status,
+			StatusApproved)
+	}
+	if _, err := db.FinishVote("p1"); !errors.Is(err, ErrWrongStatus) {
+		t.Fatalf("unexpected error finishing vote twice -- got %v, want %v",
+			err, ErrWrongStatus)
+	}
+
+	// Proposals are returned newest first.
+	all, err := db.FilterProposals(func(*Proposal) bool { return true })
+	if err != nil {
+		t.Fatalf("unexpected error filtering proposals: %v", err)
+	}
+	if len(all) != 2 || all[0].Token != "p2" || all[1].Token != "p1" {
+		t.Fatalf("unexpected proposals -- got %+v", all)
+	}
+
+	// The proposals, the vote authorization, and the votes are records.
+	unanchored, err := db.Unanchored()
+	if err != nil {
+		t.Fatalf("unexpected error fetching unanchored records: %v", err)
+	}
+	if len(unanchored) != 6 {
+		t.Fatalf("unexpected number of unanchored records -- got %d, "+
+			"want 6", len(unanchored))
+	}
+}
+
+// TestQuorum ensures proposals are only approved when enough tickets voted and
+// enough of the votes are yes.
+func TestQuorum(t *testing.T) {
+	tests := []struct {
+		name             string
+		eligible         int64
+		yes, no          int64
+		quorum, approved bool
+	}{
+		{"no votes", 10, 0, 0, false, false},
+		{"below quorum", 10, 1, 0, false, false},
+		{"quorum exactly", 10, 2, 0, true, true},
+		{"pass exactly", 10, 3, 2, true, true},
+		{"below pass", 10, 2, 2, true, false},
+		{"no eligible tickets", 0, 0, 0, false, false},
+	}
+	for _, test := range tests {
+		p := &Proposal{
+			EligibleTickets:  test.eligible,
+			QuorumPercentage: 20,
+			PassPercentage:   60,
+			Yes:              test.yes,
+			No:               test.no,
+		}
+		if got := p.QuorumReached(); got != test.quorum {
+			t.Errorf("%s: unexpected quorum -- got %v, want %v", test.name,
+				got, test.quorum)
+		}
+		if got := p.Approved(); got != test.approved {
+			t.Errorf("%s: unexpected approval -- got %v, want %v",
+				test.name, got, test.approved)
+		}
+	}
+}
+
+// TestAnchors ensures records are tracked through their anchor transactions
+// and become unanchored again when their anchor is dropped.
+func TestAnchors(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "vigiliteia.db"))
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer db.Close()
+
+	for _, token := range []string{"p1", "p2"} {
+		p := &Proposal{Token: token, Status: StatusPreVote}
+		if err := db.InsertProposal(p); err != nil {
+			t.Fatalf("unexpected error inserting proposal: %v", err)
+		}
+	}
+	if _, err := db.RecordAnchor("p3"); !errors.Is(err, ErrRecordNotFound) {
+		t.Fatalf("unexpected error for unknown record -- got %v, want %v",
+			err, ErrRecordNotFound)
+	}
+	if _, err := db.RecordAnchor("p1"); !errors.Is(err, ErrRecordNotAnchored) {
+		t.Fatalf("unexpected error for unanchored record -- got %v, want %v",
+			err, ErrRecordNotAnchored)
+	}
+
+	unknown := &Anchor{TxHash: "a0", Records: []string{"p3"}}
+	if err := db.InsertAnchor(unknown); !errors.Is(err, ErrRecordNotFound) {
+		t.Fatalf("unexpected error anchoring unknown record -- got %v, "+
+			"want %v", err, ErrRecordNotFound)
+	}
+	anchor := &Anchor{TxHash: "a1", Root: "root", Records: []string{"p1", "p2"}}
+	if err := db.InsertAnchor(anchor); err != nil {
+		t.Fatalf("unexpected error inserting anchor: %v", err)
+	}
+	unanchored, err := db.Unanchored()
+	if err != nil {
+		t.Fatalf("unexpected error fetching unanchored records: %v", err)
+	}
+	if len(unanchored) != 0 {
+		t.Fatalf("unexpected unanchored records %v", unanchored)
+	}
+
+	// Records are only anchored once the anchor is confirmed.
+	if _, err := db.RecordAnchor("p1"); !errors.Is(err, ErrRecordNotAnchored) {
+		t.Fatalf("unexpected error for unconfirmed anchor -- got %v, want %v",
+			err, ErrRecordNotAnchored)
+	}
+	anchor.Confirmed = true
+	anchor.Height = 10
+	if err := db.UpdateAnchor(anchor); err != nil {
+		t.Fatalf("unexpected error updating anchor: %v", err)
+	}
+	got, err := db.RecordAnchor("p2")
+	if err != nil {
+		t.Fatalf("unexpected error fetching record anchor: %v", err)
+	}
+	if !reflect.DeepEqual(got, anchor) {
+		t.Fatalf("unexpected anchor -- got %+v, want %+v", got, anchor)
+	}
+
+	if err := db.DropAnchor("a1"); err != nil {
+		t.Fatalf("unexpected error dropping anchor: %v", err)
+	}
+	if err := db.UpdateAnchor(anchor); !errors.Is(err, ErrAnchorNotFound) {
+		t.Fatalf("unexpected error updating dropped anchor -- got %v, "+
+			"want %v", err, ErrAnchorNotFound)
+	}
+	unanchored, err = db.Unanchored()
+	if err != nil {
+		t.Fatalf("unexpected error fetching unanchored records: %v", err)
+	}
+	if !reflect.DeepEqual(unanchored, []string{"p1", "p2"}) {
+		t.Fatalf("unexpected unanchored records -- got %v, want [p1 p2]",
+			unanchored)
+	}
+}
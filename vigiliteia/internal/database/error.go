@@ -0,0 +1,74 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+// ErrorKind identifies a kind of error.  It has full support for errors.Is and
+// errors.As, so the caller can directly check against an error kind when
+// determining the reason for an error.
+type ErrorKind string
+
+// These constants are used to identify a specific Error.
+const (
+	// ErrProposalNotFound indicates a proposal is not stored in the
+	// database.
+	ErrProposalNotFound = ErrorKind("ErrProposalNotFound")
+
+	// ErrProposalExists indicates an attempt to store a proposal that is
+	// already stored in the database.
+	ErrProposalExists = ErrorKind("ErrProposalExists")
+
+	// ErrWrongStatus indicates an operation on a proposal which is not in
+	// the status required by the operation, such as a vote on a proposal
+	// which is not being voted on.
+	ErrWrongStatus = ErrorKind("ErrWrongStatus")
+
+	// ErrDuplicateVote indicates an attempt to store a second vote of a
+	// ticket on the same proposal.
+	ErrDuplicateVote = ErrorKind("ErrDuplicateVote")
+
+	// ErrRecordNotFound indicates a record hash is not stored in the
+	// database.
+	ErrRecordNotFound = ErrorKind("ErrRecordNotFound")
+
+	// ErrRecordNotAnchored indicates a record is not yet anchored into the
+	// chain by a confirmed anchor transaction.
+	ErrRecordNotAnchored = ErrorKind("ErrRecordNotAnchored")
+
+	// ErrAnchorNotFound indicates an anchor transaction is not stored in the
+	// database.
+	ErrAnchorNotFound = ErrorKind("ErrAnchorNotFound")
+
+	// ErrBadVersion indicates the database was created by a newer version of
+	// the software.
+	ErrBadVersion = ErrorKind("ErrBadVersion")
+)
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e ErrorKind) Error() string {
+	return string(e)
+}
+
+// Error identifies an error related to the proposal database.  It has full
+// support for errors.Is and errors.As, so the caller can ascertain the specific
+// reason for the error by checking the underlying error.
+type Error struct {
+	Description string
+	Err         error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e Error) Error() string {
+	return e.Description
+}
+
+// Unwrap returns the underlying wrapped error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// makeError creates an Error given a set of arguments.
+func makeError(kind ErrorKind, desc string) Error {
+	return Error{Err: kind, Description: desc}
+}
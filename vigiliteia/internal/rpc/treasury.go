@@ -0,0 +1,69 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/rpcclient/v8"
+	"github.com/kdsmith18542/vigil/vigiliteia/record"
+)
+
+// Treasury creates treasury spends with the JSON-RPC server of a wallet that
+// holds the private key of a sanctioned Vigiliteia key.
+type Treasury struct {
+	client *rpcclient.Client
+	key    string
+}
+
+// NewTreasury returns a connection to the wallet JSON-RPC server described by
+// the provided connection config which creates treasury spends signed by the
+// provided Vigiliteia key.
+func NewTreasury(connCfg *rpcclient.ConnConfig, key string) (*Treasury, error) {
+	cfg := *connCfg
+	cfg.HTTPPostMode = true
+	client, err := rpcclient.New(&cfg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create wallet RPC client: %w", err)
+	}
+	return &Treasury{client: client, key: key}, nil
+}
+
+// Close shuts down the connection to the wallet.
+func (t *Treasury) Close() {
+	t.client.Shutdown()
+}
+
+// SendFromTreasury creates and publishes a treasury spend paying the provided
+// payouts and returns its hash.  The treasury spend is included in a block
+// once the stakeholders approve it in the treasury vote.
+func (t *Treasury) SendFromTreasury(ctx context.Context, payouts []record.Payout) (*chainhash.Hash, error) {
+	amounts := make(map[string]float64, len(payouts))
+	for _, payout := range payouts {
+		amounts[payout.Address] += VGLutil.Amount(payout.Amount).ToCoin()
+	}
+	key, err := json.Marshal(t.key)
+	if err != nil {
+		return nil, err
+	}
+	params, err := json.Marshal(amounts)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.client.RawRequest(ctx, "sendfromtreasury",
+		[]json.RawMessage{key, params})
+	if err != nil {
+		return nil, fmt.Errorf("unable to send from treasury: %w", err)
+	}
+	var txHash string
+	if err := json.Unmarshal(resp, &txHash); err != nil {
+		return nil, err
+	}
+	return chainhash.NewHashFromStr(txHash)
+}
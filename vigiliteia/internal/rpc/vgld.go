@@ -0,0 +1,136 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package rpc provides the connections of the proposal server to the node and
+// to the wallet which anchors records and creates treasury spends.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kdsmith18542/vigil/VGLjson/v4"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	chainjson "github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4"
+	"github.com/kdsmith18542/vigil/rpcclient/v8"
+	"github.com/kdsmith18542/vigil/slog"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// log is a logger that is initialized with no output filters.  This means the
+// package will not perform any logging by default until the caller requests
+// it.
+var log = slog.Disabled
+
+// UseLogger uses a specified Logger to output package logging info.
+func UseLogger(logger slog.Logger) {
+	log = logger
+}
+
+// Node is a websocket connection to the RPC server of a vgld node.
+type Node struct {
+	client *rpcclient.Client
+}
+
+// NewNode returns a new connection to the node RPC server described by the
+// provided connection config.  The connection is not established until Connect
+// is called.  The onBlock callback is invoked whenever the node connects a
+// block and after every (re)connection so the caller can catch up with blocks
+// it missed while disconnected.  It must not block.
+func NewNode(connCfg *rpcclient.ConnConfig, onBlock func()) (*Node, error) {
+	n := new(Node)
+	ntfnHandlers := &rpcclient.NotificationHandlers{
+		OnClientConnected: func() {
+			// Register for notifications again on reconnect.  This is
+			// done in a goroutine since the handlers must not block.
+			go func() {
+				err := n.client.NotifyBlocks(context.Background())
+				if err != nil {
+					log.Errorf("Unable to register for block "+
+						"notifications: %v", err)
+				}
+				onBlock()
+			}()
+		},
+		OnBlockConnected: func(_ []byte, _ [][]byte) {
+			onBlock()
+		},
+	}
+	cfg := *connCfg
+	cfg.Endpoint = "ws"
+	cfg.DisableConnectOnNew = true
+	client, err := rpcclient.New(&cfg, ntfnHandlers)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create vgld RPC client: %w", err)
+	}
+	n.client = client
+	return n, nil
+}
+
+// Connect establishes the connection to the node and keeps reconnecting when
+// it is lost until Close is called.
+func (n *Node) Connect(ctx context.Context) error {
+	return n.client.Connect(ctx, true)
+}
+
+// Close shuts down the connection to the node.
+func (n *Node) Close() {
+	n.client.Shutdown()
+	n.client.WaitForShutdown()
+}
+
+// BestBlock returns the header of the best block of the node.
+func (n *Node) BestBlock(ctx context.Context) (*wire.BlockHeader, error) {
+	hash, _, err := n.client.GetBestBlock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get best block: %w", err)
+	}
+	header, err := n.client.GetBlockHeader(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get block header %s: %w", hash, err)
+	}
+	return header, nil
+}
+
+// isRPCError returns whether err is an error of the node RPC server with the
+// passed code.
+func isRPCError(err error, code VGLjson.RPCErrorCode) bool {
+	var rpcErr *VGLjson.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == code
+}
+
+// Transaction returns the transaction with the passed hash from the mempool or
+// the main chain of the node.  Nil is returned without an error when the node
+// does not know the transaction.
+func (n *Node) Transaction(ctx context.Context, hash *chainhash.Hash) (*chainjson.TxRawResult, error) {
+	tx, err := n.client.GetRawTransactionVerbose(ctx, hash)
+	if isRPCError(err, VGLjson.ErrRPCNoTxInfo) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get transaction %s: %w", hash, err)
+	}
+	return tx, nil
+}
+
+// LiveTickets returns the hashes of all tickets which are currently live.
+func (n *Node) LiveTickets(ctx context.Context) ([]*chainhash.Hash, error) {
+	tickets, err := n.client.LiveTickets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get live tickets: %w", err)
+	}
+	return tickets, nil
+}
+
+// VerifyMessage returns whether the base64 encoded signature is a valid
+// signature of message by the passed address.
+func (n *Node) VerifyMessage(ctx context.Context, addr stdaddr.Address, signature, message string) (bool, error) {
+	valid, err := n.client.VerifyMessage(ctx, addr, signature, message)
+	if err != nil {
+		return false, fmt.Errorf("unable to verify message: %w", err)
+	}
+	return valid, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	pb "github.com/kdsmith18542/vigil/wallet/rpc/walletrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// WalletConfig describes the connection to the wallet which pays for the
+// anchor transactions.
+type WalletConfig struct {
+	// Host is the address of the gRPC server of the wallet.
+	Host string
+
+	// Cert is the PEM encoded TLS certificate of the gRPC server.
+	Cert []byte
+
+	// ClientCert authenticates the proposal server with the wallet.
+	ClientCert tls.Certificate
+
+	// Account is the account which funds the anchor transactions.
+	Account uint32
+
+	// Passphrase unlocks the wallet to sign the anchor transactions.
+	Passphrase []byte
+}
+
+// Wallet is a gRPC connection to the wallet which pays for the anchor
+// transactions.
+type Wallet struct {
+	conn       *grpc.ClientConn
+	wallet     pb.WalletServiceClient
+	account    uint32
+	passphrase []byte
+}
+
+// NewWallet returns a connection to the gRPC server of the wallet described by
+// the provided config.  The connection is established lazily so a wallet that
+// is offline does not prevent the creation of the connection.
+func NewWallet(cfg *WalletConfig) (*Wallet, error) {
+	serverCAs := x509.NewCertPool()
+	if !serverCAs.AppendCertsFromPEM(cfg.Cert) {
+		return nil, fmt.Errorf("no certificates found for wallet %s",
+			cfg.Host)
+	}
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cfg.ClientCert},
+		RootCAs:      serverCAs,
+		MinVersion:   tls.VersionTLS12,
+	})
+	conn, err := grpc.NewClient(cfg.Host, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection to wallet %s: %w",
+			cfg.Host, err)
+	}
+	return &Wallet{
+		conn:       conn,
+		wallet:     pb.NewWalletServiceClient(conn),
+		account:    cfg.Account,
+		passphrase: cfg.Passphrase,
+	}, nil
+}
+
+// Close closes the connection to the wallet.
+func (w *Wallet) Close() {
+	w.conn.Close()
+}
+
+// PublishAnchor creates, signs, and publishes a transaction with a zero value
+// output paying to the provided null data script and returns its hash.
+func (w *Wallet) PublishAnchor(ctx context.Context, script []byte) (*chainhash.Hash, error) {
+	constructed, err := w.wallet.ConstructTransaction(ctx,
+		&pb.ConstructTransactionRequest{
+			SourceAccount:         w.account,
+			RequiredConfirmations: 1,
+			NonChangeOutputs: []*pb.ConstructTransactionRequest_Output{{
+				Destination: &pb.ConstructTransactionRequest_OutputDestination{
+					Script: script,
+				},
+			}},
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct anchor transaction: %w",
+			err)
+	}
+	signed, err := w.wallet.SignTransaction(ctx, &pb.SignTransactionRequest{
+		Passphrase:            w.passphrase,
+		SerializedTransaction: constructed.UnsignedTransaction,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign anchor transaction: %w", err)
+	}
+	if len(signed.UnsignedInputIndexes) != 0 {
+		return nil, errors.New("unable to sign all inputs of the anchor " +
+			"transaction")
+	}
+	published, err := w.wallet.PublishTransaction(ctx,
+		&pb.PublishTransactionRequest{
+			SignedTransaction: signed.Transaction,
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to publish anchor transaction: %w",
+			err)
+	}
+	return chainhash.NewHash(published.TransactionHash)
+}
@@ -0,0 +1,497 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/blockchain/standalone/v2"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/vigiliteia/internal/database"
+	"github.com/kdsmith18542/vigil/vigiliteia/record"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+const (
+	// maxRequestSize is the maximum size of a request body.
+	maxRequestSize = 1 << 20
+
+	// maxVotesPerRequest is the maximum number of votes that can be cast
+	// with a single request.
+	maxVotesPerRequest = 1000
+
+	// maxClockSkew is the maximum difference between the timestamp of a
+	// signed record and the time it is received.  It prevents records from
+	// being backdated.
+	maxClockSkew = time.Minute * 10
+)
+
+// submitResponse is the response to a proposal submission.
+type submitResponse struct {
+	Token string `json:"token"`
+}
+
+// castVotesRequest is the request to cast ticket votes.
+type castVotesRequest struct {
+	Votes []record.Vote `json:"votes"`
+}
+
+// voteReceipt is the result of a single vote of a castvotes request.  The
+// error is empty when the vote was accepted.
+type voteReceipt struct {
+	Ticket string `json:"ticket"`
+	Hash   string `json:"hash,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// castVotesResponse is the response to a castvotes request.
+type castVotesResponse struct {
+	Receipts []voteReceipt `json:"receipts"`
+}
+
+// proofResponse proves that a record was anchored into the chain.  Hashing
+// the record hash with the path up to the root as described by the
+// VerifyInclusionProof function of the standalone package yields the merkle
+// root, which is committed to by the null data output of the anchor
+// transaction.
+type proofResponse struct {
+	Record    string   `json:"record"`
+	Index     uint32   `json:"index"`
+	Path      []string `json:"path"`
+	Root      string   `json:"root"`
+	TxHash    string   `json:"txhash"`
+	BlockHash string   `json:"blockhash"`
+	Height    int64    `json:"height"`
+}
+
+// readRequest decodes the JSON body of the provided request into req.
+func readRequest(r *http.Request, req interface{}) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize+1))
+	if err != nil {
+		return fmt.Errorf("%w: unable to read request: %v",
+			errInvalidRequest, err)
+	}
+	if len(body) > maxRequestSize {
+		return fmt.Errorf("%w: request too large", errInvalidRequest)
+	}
+	if err := json.Unmarshal(body, req); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidRequest, err)
+	}
+	return nil
+}
+
+// parseHash parses the provided hex encoded hash of a request.
+func parseHash(s string) (*chainhash.Hash, error) {
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil || len(s) != chainhash.MaxHashStringSize {
+		return nil, fmt.Errorf("%w: invalid hash %q", errInvalidRequest, s)
+	}
+	return hash, nil
+}
+
+// checkTimestamp ensures the provided unix timestamp of a signed record is
+// close to the current time.
+func checkTimestamp(timestamp int64) error {
+	skew := time.Since(time.Unix(timestamp, 0))
+	if skew > maxClockSkew || skew < -maxClockSkew {
+		return fmt.Errorf("%w: timestamp %d is too far from the current "+
+			"time", errInvalidRequest, timestamp)
+	}
+	return nil
+}
+
+// verifySignature ensures the provided signature is a valid signature of the
+// record with the provided hash by the provided address.
+func (s *Server) verifySignature(ctx context.Context, addr stdaddr.Address,
+	signature string, hash *chainhash.Hash) error {
+
+	if _, err := base64.StdEncoding.DecodeString(signature); err != nil {
+		return fmt.Errorf("%w: malformed signature", errInvalidRequest)
+	}
+	valid, err := s.node.VerifyMessage(ctx, addr, signature,
+		record.Message(hash))
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("%w: invalid signature", errInvalidRequest)
+	}
+	return nil
+}
+
+// validateProposal ensures the fields of the provided proposal are valid.
+func (s *Server) validateProposal(p *record.Proposal) error {
+	switch {
+	case strings.TrimSpace(p.Name) == "":
+		return fmt.Errorf("%w: empty name", errInvalidRequest)
+	case len(p.Name) > record.MaxNameLength:
+		return fmt.Errorf("%w: name longer than %d bytes",
+			errInvalidRequest, record.MaxNameLength)
+	case strings.TrimSpace(p.Description) == "":
+		return fmt.Errorf("%w: empty description", errInvalidRequest)
+	case len(p.Description) > record.MaxDescriptionLength:
+		return fmt.Errorf("%w: description longer than %d bytes",
+			errInvalidRequest, record.MaxDescriptionLength)
+	case len(p.Payouts) > record.MaxPayouts:
+		return fmt.Errorf("%w: more than %d payouts", errInvalidRequest,
+			record.MaxPayouts)
+	}
+
+	// Payouts are paid by treasury spends, which only pay to stake
+	// addresses.
+	seen := make(map[string]struct{}, len(p.Payouts))
+	for _, payout := range p.Payouts {
+		addr, err := stdaddr.DecodeAddress(payout.Address, s.cfg.Params)
+		if err != nil {
+			return fmt.Errorf("%w: invalid payout address %q: %v",
+				errInvalidRequest, payout.Address, err)
+		}
+		if _, ok := addr.(stdaddr.StakeAddress); !ok {
+			return fmt.Errorf("%w: payout address %s can not be paid "+
+				"by the treasury", errInvalidRequest, payout.Address)
+		}
+		if _, ok := seen[payout.Address]; ok {
+			return fmt.Errorf("%w: duplicate payout address %s",
+				errInvalidRequest, payout.Address)
+		}
+		seen[payout.Address] = struct{}{}
+		if payout.Amount <= 0 || payout.Amount > VGLutil.MaxAmount {
+			return fmt.Errorf("%w: invalid payout amount %d",
+				errInvalidRequest, payout.Amount)
+		}
+	}
+	return checkTimestamp(p.Timestamp)
+}
+
+// submitProposal stores the provided proposal after ensuring it is valid and
+// signed by its author.
+func (s *Server) submitProposal(ctx context.Context, p *record.Proposal) (*database.Proposal, error) {
+	if err := s.validateProposal(p); err != nil {
+		return nil, err
+	}
+	author, err := stdaddr.DecodeAddress(p.Author, s.cfg.Params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid author address %q: %v",
+			errInvalidRequest, p.Author, err)
+	}
+	hash := p.Hash()
+	if err := s.verifySignature(ctx, author, p.Signature, &hash); err != nil {
+		return nil, err
+	}
+	proposal := &database.Proposal{
+		Proposal: *p,
+		Token:    hash.String(),
+		Status:   database.StatusPreVote,
+	}
+	if err := s.db.InsertProposal(proposal); err != nil {
+		return nil, err
+	}
+	log.Infof("Proposal %s (%q) submitted", proposal.Token, p.Name)
+	return proposal, nil
+}
+
+// handleSubmitProposal handles the submission of a new proposal.
+func (s *Server) handleSubmitProposal(w http.ResponseWriter, r *http.Request) {
+	var p record.Proposal
+	if err := readRequest(r, &p); err != nil {
+		writeError(w, err)
+		return
+	}
+	proposal, err := s.submitProposal(r.Context(), &p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &submitResponse{Token: proposal.Token})
+}
+
+// handleProposals serves all proposals, newest first.  The optional status
+// query parameter selects the proposals with the provided status.
+func (s *Server) handleProposals(w http.ResponseWriter, r *http.Request) {
+	status := database.Status(r.URL.Query().Get("status"))
+	proposals, err := s.db.FilterProposals(func(p *database.Proposal) bool {
+		return status == "" || p.Status == status
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if proposals == nil {
+		proposals = []*database.Proposal{}
+	}
+	writeJSON(w, http.StatusOK, proposals)
+}
+
+// handleProposal serves a proposal along with the state of the vote on it.
+func (s *Server) handleProposal(w http.ResponseWriter, r *http.Request) {
+	proposal, err := s.db.GetProposal(r.PathValue("token"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, proposal)
+}
+
+// handleVotes serves the votes cast on a proposal.
+func (s *Server) handleVotes(w http.ResponseWriter, r *http.Request) {
+	votes, err := s.db.Votes(r.PathValue("token"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if votes == nil {
+		votes = []*record.Vote{}
+	}
+	writeJSON(w, http.StatusOK, votes)
+}
+
+// startVote starts the vote on a proposal after ensuring the provided vote
+// authorization was signed by the author of the proposal.  The tickets which
+// are live at the current best block are eligible to vote.
+func (s *Server) startVote(ctx context.Context, sv *record.StartVote) (*database.Proposal, error) {
+	if err := checkTimestamp(sv.Timestamp); err != nil {
+		return nil, err
+	}
+	proposal, err := s.db.GetProposal(sv.Token)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Status != database.StatusPreVote {
+		return nil, fmt.Errorf("%w: the vote on proposal %s was already "+
+			"started", errConflict, sv.Token)
+	}
+	author, err := stdaddr.DecodeAddress(proposal.Author, s.cfg.Params)
+	if err != nil {
+		return nil, err
+	}
+	hash := sv.Hash()
+	if err := s.verifySignature(ctx, author, sv.Signature, &hash); err != nil {
+		return nil, err
+	}
+
+	header, err := s.node.BestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	live, err := s.node.LiveTickets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tickets := make([]string, 0, len(live))
+	for _, ticket := range live {
+		tickets = append(tickets, ticket.String())
+	}
+
+	proposal.Status = database.StatusVoting
+	proposal.StartVote = sv
+	proposal.StartHeight = int64(header.Height)
+	proposal.EndHeight = proposal.StartHeight + s.cfg.VoteDuration
+	proposal.EligibleTickets = int64(len(tickets))
+	proposal.QuorumPercentage = s.cfg.QuorumPercentage
+	proposal.PassPercentage = s.cfg.PassPercentage
+	if err := s.db.StartVote(proposal, tickets); err != nil {
+		return nil, err
+	}
+	log.Infof("Vote on proposal %s started at height %d with %d eligible "+
+		"tickets", proposal.Token, proposal.StartHeight, len(tickets))
+	return proposal, nil
+}
+
+// handleStartVote handles the authorization of the vote on a proposal by its
+// author.
+func (s *Server) handleStartVote(w http.ResponseWriter, r *http.Request) {
+	var sv record.StartVote
+	if err := readRequest(r, &sv); err != nil {
+		writeError(w, err)
+		return
+	}
+	proposal, err := s.startVote(r.Context(), &sv)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, proposal)
+}
+
+// decodeTx decodes the provided hex encoded transaction.
+func decodeTx(s string) (*wire.MsgTx, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// commitmentAddress returns the address of the first commitment output of the
+// provided ticket.  The ticket must be known to the node.
+func (s *Server) commitmentAddress(ctx context.Context, ticketHash *chainhash.Hash) (stdaddr.Address, error) {
+	txResult, err := s.node.Transaction(ctx, ticketHash)
+	if err != nil {
+		return nil, err
+	}
+	if txResult == nil {
+		return nil, fmt.Errorf("%w: unknown ticket %s", errInvalidRequest,
+			ticketHash)
+	}
+	ticketTx, err := decodeTx(txResult.Hex)
+	if err != nil {
+		return nil, err
+	}
+	if !stake.IsSStx(ticketTx) {
+		return nil, fmt.Errorf("%w: %s is not a ticket", errInvalidRequest,
+			ticketHash)
+	}
+	return stake.AddrFromSStxPkScrCommitment(ticketTx.TxOut[1].PkScript,
+		s.cfg.Params)
+}
+
+// castVote stores the provided vote after ensuring the ticket is eligible to
+// vote on the proposal and the vote was signed by the commitment address of
+// the ticket.  The best height is the height of the current best block.
+func (s *Server) castVote(ctx context.Context, vote *record.Vote, bestHeight int64) error {
+	if vote.Choice != record.VoteYes && vote.Choice != record.VoteNo {
+		return fmt.Errorf("%w: invalid choice %q", errInvalidRequest,
+			vote.Choice)
+	}
+	ticketHash, err := parseHash(vote.Ticket)
+	if err != nil {
+		return err
+	}
+	proposal, err := s.db.GetProposal(vote.Token)
+	if err != nil {
+		return err
+	}
+	if proposal.Status != database.StatusVoting ||
+		bestHeight >= proposal.EndHeight {
+
+		return fmt.Errorf("%w: proposal %s is not being voted on",
+			errConflict, vote.Token)
+	}
+	eligible, err := s.db.IsEligible(vote.Token, vote.Ticket)
+	if err != nil {
+		return err
+	}
+	if !eligible {
+		return fmt.Errorf("%w: ticket %s is not eligible to vote on "+
+			"proposal %s", errInvalidRequest, vote.Ticket, vote.Token)
+	}
+	addr, err := s.commitmentAddress(ctx, ticketHash)
+	if err != nil {
+		return err
+	}
+	hash := vote.Hash()
+	if err := s.verifySignature(ctx, addr, vote.Signature, &hash); err != nil {
+		return err
+	}
+	return s.db.InsertVote(vote)
+}
+
+// handleCastVotes handles ticket votes on proposals.  Every vote is handled
+// independently and the response contains a receipt for each of them in the
+// order of the request.
+func (s *Server) handleCastVotes(w http.ResponseWriter, r *http.Request) {
+	var req castVotesRequest
+	if err := readRequest(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(req.Votes) == 0 || len(req.Votes) > maxVotesPerRequest {
+		writeError(w, fmt.Errorf("%w: between 1 and %d votes must be cast",
+			errInvalidRequest, maxVotesPerRequest))
+		return
+	}
+	ctx := r.Context()
+	header, err := s.node.BestBlock(ctx)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := &castVotesResponse{
+		Receipts: make([]voteReceipt, 0, len(req.Votes)),
+	}
+	for i := range req.Votes {
+		vote := &req.Votes[i]
+		receipt := voteReceipt{Ticket: vote.Ticket}
+		err := s.castVote(ctx, vote, int64(header.Height))
+		if err != nil {
+			code := errorCode(err)
+			receipt.Error = errorMessage(code, err)
+		} else {
+			hash := vote.Hash()
+			receipt.Hash = hash.String()
+		}
+		resp.Receipts = append(resp.Receipts, receipt)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// proof returns the proof that the record with the provided hash was anchored
+// into the chain.
+func (s *Server) proof(hash string) (*proofResponse, error) {
+	if _, err := parseHash(hash); err != nil {
+		return nil, err
+	}
+	anchor, err := s.db.RecordAnchor(hash)
+	if err != nil {
+		return nil, err
+	}
+	leaves := make([]chainhash.Hash, len(anchor.Records))
+	index := -1
+	for i, recordHash := range anchor.Records {
+		leaf, err := chainhash.NewHashFromStr(recordHash)
+		if err != nil {
+			return nil, err
+		}
+		leaves[i] = *leaf
+		if recordHash == hash {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, errors.New("record is missing from its anchor")
+	}
+	path := standalone.GenerateInclusionProof(leaves, uint32(index))
+	resp := &proofResponse{
+		Record:    hash,
+		Index:     uint32(index),
+		Path:      make([]string, 0, len(path)),
+		Root:      anchor.Root,
+		TxHash:    anchor.TxHash,
+		BlockHash: anchor.BlockHash,
+		Height:    anchor.Height,
+	}
+	for i := range path {
+		resp.Path = append(resp.Path, path[i].String())
+	}
+	return resp, nil
+}
+
+// handleProof serves the proof that a record was anchored into the chain.
+func (s *Server) handleProof(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.proof(r.PathValue("hash"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package webapi implements the HTTP API of the proposal server which is used
+// to submit proposals, to start the votes on them, and to cast ticket votes.
+package webapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	chainjson "github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4"
+	"github.com/kdsmith18542/vigil/slog"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/vigiliteia/internal/database"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// log is a logger that is initialized with no output filters.  This means the
+// package will not perform any logging by default until the caller requests
+// it.
+var log = slog.Disabled
+
+// UseLogger uses a specified Logger to output package logging info.
+func UseLogger(logger slog.Logger) {
+	log = logger
+}
+
+var (
+	// errInvalidRequest is wrapped by errors that are the result of invalid
+	// requests, including requests with invalid signatures.
+	errInvalidRequest = errors.New("invalid request")
+
+	// errConflict is wrapped by errors that are the result of requests
+	// which conflict with the state of a proposal.
+	errConflict = errors.New("conflict")
+)
+
+// Node defines the node RPC methods used by the API.
+type Node interface {
+	BestBlock(ctx context.Context) (*wire.BlockHeader, error)
+	Transaction(ctx context.Context, hash *chainhash.Hash) (*chainjson.TxRawResult, error)
+	LiveTickets(ctx context.Context) ([]*chainhash.Hash, error)
+	VerifyMessage(ctx context.Context, addr stdaddr.Address, signature, message string) (bool, error)
+}
+
+// Config houses the governance parameters of the API.
+type Config struct {
+	Params *chaincfg.Params
+
+	// VoteDuration is the number of blocks a vote lasts.
+	VoteDuration int64
+
+	// QuorumPercentage is the percentage of the eligible tickets which
+	// must vote on a proposal for the vote to be valid.
+	QuorumPercentage uint32
+
+	// PassPercentage is the percentage of yes votes required to approve a
+	// proposal.
+	PassPercentage uint32
+}
+
+// Server serves the API of the proposal server.
+type Server struct {
+	cfg  Config
+	db   *database.DB
+	node Node
+}
+
+// New returns a new API server.
+func New(cfg Config, db *database.DB, node Node) *Server {
+	return &Server{cfg: cfg, db: db, node: node}
+}
+
+// errorResponse is the response to a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// errorCode returns the HTTP status code for the provided error.
+func errorCode(err error) int {
+	switch {
+	case errors.Is(err, errInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, database.ErrProposalNotFound),
+		errors.Is(err, database.ErrRecordNotFound),
+		errors.Is(err, database.ErrRecordNotAnchored):
+		return http.StatusNotFound
+	case errors.Is(err, errConflict),
+		errors.Is(err, database.ErrProposalExists),
+		errors.Is(err, database.ErrWrongStatus),
+		errors.Is(err, database.ErrDuplicateVote):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// errorMessage returns the message to show for the provided error.  Internal
+// errors are logged and replaced by a generic message.
+func errorMessage(code int, err error) string {
+	if code == http.StatusInternalServerError {
+		log.Errorf("Unable to serve request: %v", err)
+		return "internal error"
+	}
+	return err.Error()
+}
+
+// writeJSON writes the provided value as a JSON response with the provided
+// HTTP status code.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debugf("Unable to write response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response for the provided error.
+func writeError(w http.ResponseWriter, err error) {
+	code := errorCode(err)
+	writeJSON(w, code, &errorResponse{Error: errorMessage(code, err)})
+}
+
+// Handler returns the HTTP handler of the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/proposals", s.handleProposals)
+	mux.HandleFunc("POST /api/v1/proposals", s.handleSubmitProposal)
+	mux.HandleFunc("GET /api/v1/proposals/{token}", s.handleProposal)
+	mux.HandleFunc("GET /api/v1/proposals/{token}/votes", s.handleVotes)
+	mux.HandleFunc("POST /api/v1/startvote", s.handleStartVote)
+	mux.HandleFunc("POST /api/v1/castvotes", s.handleCastVotes)
+	mux.HandleFunc("GET /api/v1/proofs/{hash}", s.handleProof)
+	return mux
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kdsmith18542/vigil/blockchain/standalone/v2"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	chainjson "github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/vigiliteia/internal/database"
+	"github.com/kdsmith18542/vigil/vigiliteia/record"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// fakeNode implements the Node interface for the tests.  Signatures are the
+// base64 encoding of the address followed by the signed message.
+type fakeNode struct {
+	height uint32
+	txs    map[chainhash.Hash]*chainjson.TxRawResult
+	live   []*chainhash.Hash
+}
+
+func (n *fakeNode) BestBlock(context.Context) (*wire.BlockHeader, error) {
+	return &wire.BlockHeader{Height: n.height}, nil
+}
+
+func (n *fakeNode) Transaction(_ context.Context, hash *chainhash.Hash) (*chainjson.TxRawResult, error) {
+	return n.txs[*hash], nil
+}
+
+func (n *fakeNode) LiveTickets(context.Context) ([]*chainhash.Hash, error) {
+	return n.live, nil
+}
+
+func (n *fakeNode) VerifyMessage(_ context.Context, addr stdaddr.Address, signature, message string) (bool, error) {
+	want := base64.StdEncoding.EncodeToString([]byte(addr.String() + message))
+	return signature == want, nil
+}
+
+// fakeSign signs the record with the provided hash the way fakeNode verifies
+// signatures.
+func fakeSign(addr stdaddr.Address, hash chainhash.Hash) string {
+	msg := addr.String() + record.Message(&hash)
+	return base64.StdEncoding.EncodeToString([]byte(msg))
+}
+
+// testTicket returns a ticket which commits to the provided commitment hash.
+func testTicket(commitmentHash []byte) *wire.MsgTx {
+	const ticketPrice = 100e8
+
+	commitment := []byte{0x6a, 0x1e} // OP_RETURN DATA_30
+	commitment = append(commitment, commitmentHash...)
+	commitment = binary.LittleEndian.AppendUint64(commitment, ticketPrice)
+	commitment = append(commitment, 0x00, 0x58)
+
+	p2pkh := func(stakeOpcode byte, pkHash []byte) []byte {
+		script := []byte{stakeOpcode, 0x76, 0xa9, 0x14}
+		script = append(script, pkHash...)
+		return append(script, 0x88, 0xac)
+	}
+	ticket := wire.NewMsgTx()
+	ticket.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0,
+		wire.TxTreeRegular), ticketPrice, nil))
+	ticket.AddTxOut(wire.NewTxOut(ticketPrice, p2pkh(0xba,
+		bytes.Repeat([]byte{9}, 20))))
+	ticket.AddTxOut(wire.NewTxOut(0, commitment))
+	ticket.AddTxOut(wire.NewTxOut(0, p2pkh(0xbd, commitmentHash)))
+	return ticket
+}
+
+// request performs an API request and decodes the response into resp.  It
+// returns the HTTP status code of the response.
+func request(t *testing.T, method, url string, req, resp interface{}) int {
+	t.Helper()
+	var body bytes.Buffer
+	if req != nil {
+		if err := json.NewEncoder(&body).Encode(req); err != nil {
+			t.Fatalf("unable to encode request: %v", err)
+		}
+	}
+	httpReq, err := http.NewRequest(method, url, &body)
+	if err != nil {
+		t.Fatalf("unable to create request: %v", err)
+	}
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		t.Fatalf("unable to %s %s: %v", method, url, err)
+	}
+	defer httpResp.Body.Close()
+	if resp != nil && httpResp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(httpResp.Body).Decode(resp); err != nil {
+			t.Fatalf("unable to decode response: %v", err)
+		}
+	}
+	return httpResp.StatusCode
+}
+
+// TestAPI ensures a proposal can be submitted, voted on by eligible tickets,
+// and have its records proven once they are anchored.
+func TestAPI(t *testing.T) {
+	params := chaincfg.SimNetParams()
+	db, err := database.Open(filepath.Join(t.TempDir(), "vigiliteia.db"))
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer db.Close()
+
+	newAddr := func(b byte) stdaddr.StakeAddress {
+		addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(
+			bytes.Repeat([]byte{b}, 20), params)
+		if err != nil {
+			t.Fatalf("unable to create address: %v", err)
+		}
+		return addr
+	}
+	author, other, payee := newAddr(1), newAddr(2), newAddr(3)
+	commitmentHash := bytes.Repeat([]byte{4}, 20)
+	commitmentAddr := newAddr(4)
+
+	ticket := testTicket(commitmentHash)
+	ticketHash := ticket.TxHash()
+	ticketBytes, err := ticket.Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize ticket: %v", err)
+	}
+	ineligible := chainhash.Hash{5}
+	node := &fakeNode{
+		height: 1000,
+		txs: map[chainhash.Hash]*chainjson.TxRawResult{
+			ticketHash: {Hex: hex.EncodeToString(ticketBytes)},
+		},
+		live: []*chainhash.Hash{&ticketHash},
+	}
+	srv := New(Config{
+		Params:           params,
+		VoteDuration:     10,
+		QuorumPercentage: 20,
+		PassPercentage:   60,
+	}, db, node)
+	httpSrv := httptest.NewServer(srv.Handler())
+	defer httpSrv.Close()
+	api := httpSrv.URL + "/api/v1"
+
+	// Proposals must be signed by their author.
+	p := record.Proposal{
+		Name:        "Proposal",
+		Description: "Description",
+		Payouts:     []record.Payout{{Address: payee.String(), Amount: 1e8}},
+		Author:      author.String(),
+		Timestamp:   time.Now().Unix(),
+	}
+	p.Signature = fakeSign(other, p.Hash())
+	code := request(t, http.MethodPost, api+"/proposals", &p, nil)
+	if code != http.StatusBadRequest {
+		t.Fatalf("unexpected status for bad signature -- got %d, want %d",
+			code, http.StatusBadRequest)
+	}
+	p.Signature = fakeSign(author, p.Hash())
+	var submitResp submitResponse
+	code = request(t, http.MethodPost, api+"/proposals", &p, &submitResp)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status submitting proposal -- got %d", code)
+	}
+	token := submitResp.Token
+	if token != p.Token() {
+		t.Fatalf("unexpected token -- got %s, want %s", token, p.Token())
+	}
+	code = request(t, http.MethodPost, api+"/proposals", &p, nil)
+	if code != http.StatusConflict {
+		t.Fatalf("unexpected status submitting proposal twice -- got %d, "+
+			"want %d", code, http.StatusConflict)
+	}
+
+	// The vote must be started by the author and makes the live tickets
+	// eligible.
+	vote := record.Vote{Token: token, Ticket: ticketHash.String(),
+		Choice: record.VoteYes}
+	vote.Signature = fakeSign(commitmentAddr, vote.Hash())
+	var votesResp castVotesResponse
+	request(t, http.MethodPost, api+"/castvotes",
+		&castVotesRequest{Votes: []record.Vote{vote}}, &votesResp)
+	if votesResp.Receipts[0].Error == "" {
+		t.Fatal("vote accepted before the vote started")
+	}
+	sv := record.StartVote{Token: token, Timestamp: time.Now().Unix()}
+	sv.Signature = fakeSign(other, sv.Hash())
+	code = request(t, http.MethodPost, api+"/startvote", &sv, nil)
+	if code != http.StatusBadRequest {
+		t.Fatalf("unexpected status for bad signature -- got %d, want %d",
+			code, http.StatusBadRequest)
+	}
+	sv.Signature = fakeSign(author, sv.Hash())
+	var proposal database.Proposal
+	code = request(t, http.MethodPost, api+"/startvote", &sv, &proposal)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status starting vote -- got %d", code)
+	}
+	if proposal.Status != database.StatusVoting ||
+		proposal.EligibleTickets != 1 || proposal.EndHeight != 1010 {
+
+		t.Fatalf("unexpected proposal -- got %+v", proposal)
+	}
+
+	// Votes must be cast by eligible tickets and signed by their commitment
+	// address.
+	badSig := vote
+	badSig.Signature = fakeSign(other, vote.Hash())
+	notEligible := record.Vote{Token: token, Ticket: ineligible.String(),
+		Choice: record.VoteNo}
+	notEligible.Signature = fakeSign(commitmentAddr, notEligible.Hash())
+	badChoice := vote
+	badChoice.Choice = "maybe"
+	votes := []record.Vote{badSig, notEligible, badChoice, vote, vote}
+	request(t, http.MethodPost, api+"/castvotes",
+		&castVotesRequest{Votes: votes}, &votesResp)
+	if len(votesResp.Receipts) != len(votes) {
+		t.Fatalf("unexpected number of receipts -- got %d, want %d",
+			len(votesResp.Receipts), len(votes))
+	}
+	for i, receipt := range votesResp.Receipts {
+		accepted := receipt.Error == ""
+		if accepted != (i == 3) {
+			t.Fatalf("unexpected receipt %d -- got %+v", i, receipt)
+		}
+	}
+	voteHash := vote.Hash()
+	if votesResp.Receipts[3].Hash != voteHash.String() {
+		t.Fatalf("unexpected vote hash -- got %s, want %s",
+			votesResp.Receipts[3].Hash, voteHash)
+	}
+	var gotVotes []record.Vote
+	request(t, http.MethodGet, api+"/proposals/"+token+"/votes", nil,
+		&gotVotes)
+	if len(gotVotes) != 1 || gotVotes[0] != vote {
+		t.Fatalf("unexpected votes -- got %+v", gotVotes)
+	}
+	request(t, http.MethodGet, api+"/proposals/"+token, nil, &proposal)
+	if proposal.Yes != 1 || proposal.No != 0 {
+		t.Fatalf("unexpected tally -- got %d/%d, want 1/0", proposal.Yes,
+			proposal.No)
+	}
+
+	// Votes are rejected once the vote ended.
+	node.height = 1010
+	again := record.Vote{Token: token, Ticket: ticketHash.String(),
+		Choice: record.VoteNo}
+	again.Signature = fakeSign(commitmentAddr, again.Hash())
+	request(t, http.MethodPost, api+"/castvotes",
+		&castVotesRequest{Votes: []record.Vote{again}}, &votesResp)
+	if votesResp.Receipts[0].Error == "" {
+		t.Fatal("vote accepted after the vote ended")
+	}
+
+	// Records can only be proven once their anchor is confirmed.
+	code = request(t, http.MethodGet, api+"/proofs/"+voteHash.String(), nil,
+		nil)
+	if code != http.StatusNotFound {
+		t.Fatalf("unexpected status for unanchored record -- got %d, "+
+			"want %d", code, http.StatusNotFound)
+	}
+	records, err := db.Unanchored()
+	if err != nil {
+		t.Fatalf("unable to fetch unanchored records: %v", err)
+	}
+	leaves := make([]chainhash.Hash, 0, len(records))
+	for _, r := range records {
+		leaf, err := chainhash.NewHashFromStr(r)
+		if err != nil {
+			t.Fatalf("invalid record hash %s: %v", r, err)
+		}
+		leaves = append(leaves, *leaf)
+	}
+	root := standalone.CalcMerkleRoot(leaves)
+	err = db.InsertAnchor(&database.Anchor{
+		TxHash:    chainhash.Hash{6}.String(),
+		Root:      root.String(),
+		Records:   records,
+		Confirmed: true,
+		Height:    1005,
+	})
+	if err != nil {
+		t.Fatalf("unable to insert anchor: %v", err)
+	}
+	for _, hash := range records {
+		var proof proofResponse
+		code := request(t, http.MethodGet, api+"/proofs/"+hash, nil, &proof)
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status fetching proof -- got %d", code)
+		}
+		leaf, _ := chainhash.NewHashFromStr(proof.Record)
+		gotRoot, _ := chainhash.NewHashFromStr(proof.Root)
+		path := make([]chainhash.Hash, 0, len(proof.Path))
+		for _, s := range proof.Path {
+			h, err := chainhash.NewHashFromStr(s)
+			if err != nil {
+				t.Fatalf("invalid proof hash %s: %v", s, err)
+			}
+			path = append(path, *h)
+		}
+		if !standalone.VerifyInclusionProof(gotRoot, leaf, proof.Index, path) {
+			t.Fatalf("invalid proof for record %s", hash)
+		}
+	}
+}
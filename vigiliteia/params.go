@@ -0,0 +1,60 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+)
+
+// params is used to group parameters for various networks such as the main
+// network and test networks.
+type params struct {
+	*chaincfg.Params
+	nodeRPCPort    string
+	walletGRPCPort string
+	walletRPCPort  string
+	voteDuration   int64
+}
+
+// mainNetParams contains parameters specific to the main network
+// (wire.MainNet).  Votes last about a week.
+var mainNetParams = params{
+	Params:         chaincfg.MainNetParams(),
+	nodeRPCPort:    "9109",
+	walletGRPCPort: "9111",
+	walletRPCPort:  "9110",
+	voteDuration:   4032,
+}
+
+// testNet3Params contains parameters specific to the test network (version 3)
+// (wire.TestNet3).  Votes last about a day.
+var testNet3Params = params{
+	Params:         chaincfg.TestNet3Params(),
+	nodeRPCPort:    "19109",
+	walletGRPCPort: "19111",
+	walletRPCPort:  "19110",
+	voteDuration:   720,
+}
+
+// simNetParams contains parameters specific to the simulation test network
+// (wire.SimNet).
+var simNetParams = params{
+	Params:         chaincfg.SimNetParams(),
+	nodeRPCPort:    "19556",
+	walletGRPCPort: "19558",
+	walletRPCPort:  "19557",
+	voteDuration:   64,
+}
+
+// regNetParams contains parameters specific to the regression test
+// network (wire.RegNet).  The wallet does not support the regression test
+// network, so there are no default wallet ports.
+var regNetParams = params{
+	Params:         chaincfg.RegNetParams(),
+	nodeRPCPort:    "18656",
+	walletGRPCPort: "",
+	walletRPCPort:  "",
+	voteDuration:   64,
+}
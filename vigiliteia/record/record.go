@@ -0,0 +1,184 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package record defines the signed records of the Vigiliteia proposal system
+// along with how they are hashed, signed, and anchored into the chain.
+//
+// Every record is identified by its hash, which commits to all fields of the
+// record except for the signature.  The signature is a compact signature as
+// created by the signmessage RPC of the wallet over the message returned by
+// Message for the hash of the record.  Proposals and vote authorizations are
+// signed by the author of the proposal and votes are signed by the commitment
+// address of the voting ticket.
+package record
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/txscript/v4"
+)
+
+const (
+	// MaxNameLength is the maximum length of the name of a proposal.
+	MaxNameLength = 80
+
+	// MaxDescriptionLength is the maximum length of the description of a
+	// proposal.
+	MaxDescriptionLength = 64 * 1024
+
+	// MaxPayouts is the maximum number of payouts a proposal may request
+	// from the treasury.
+	MaxPayouts = 16
+)
+
+// These constants define the possible choices of a vote.
+const (
+	VoteYes = "yes"
+	VoteNo  = "no"
+)
+
+// Tags that domain separate the hashes of the record types.
+const (
+	proposalTag  = "vigiliteia-proposal"
+	startVoteTag = "vigiliteia-startvote"
+	voteTag      = "vigiliteia-vote"
+)
+
+// anchorMarker identifies the null data outputs which anchor records into the
+// chain.
+var anchorMarker = [4]byte{'V', 'G', 'L', 'A'}
+
+// Payout is an amount of atoms requested from the treasury by a proposal.
+type Payout struct {
+	Address string `json:"address"`
+	Amount  int64  `json:"amount"`
+}
+
+// Proposal is a request to the stakeholders to fund work from the treasury or
+// to approve a change without funding.  The hash of the proposal is its token.
+type Proposal struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Payouts     []Payout `json:"payouts"`
+	Author      string   `json:"author"`
+	Timestamp   int64    `json:"timestamp"`
+	Signature   string   `json:"signature"`
+}
+
+// StartVote is the authorization of the author of a proposal to start the
+// ticket vote on it.
+type StartVote struct {
+	Token     string `json:"token"`
+	Timestamp int64  `json:"timestamp"`
+	Signature string `json:"signature"`
+}
+
+// Vote is the choice of a ticket on a proposal.
+type Vote struct {
+	Token     string `json:"token"`
+	Ticket    string `json:"ticket"`
+	Choice    string `json:"choice"`
+	Signature string `json:"signature"`
+}
+
+// hasher serializes the fields of a record for hashing.  Strings are prefixed
+// with their length so that the serialization is unambiguous.
+type hasher struct {
+	buf bytes.Buffer
+}
+
+func (h *hasher) writeUint32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	h.buf.Write(b[:])
+}
+
+func (h *hasher) writeInt64(v int64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(v))
+	h.buf.Write(b[:])
+}
+
+func (h *hasher) writeString(s string) {
+	h.writeUint32(uint32(len(s)))
+	h.buf.WriteString(s)
+}
+
+func (h *hasher) sum() chainhash.Hash {
+	return chainhash.HashH(h.buf.Bytes())
+}
+
+// Hash returns the hash of the proposal.
+func (p *Proposal) Hash() chainhash.Hash {
+	var h hasher
+	h.writeString(proposalTag)
+	h.writeString(p.Name)
+	h.writeString(p.Description)
+	h.writeUint32(uint32(len(p.Payouts)))
+	for _, payout := range p.Payouts {
+		h.writeString(payout.Address)
+		h.writeInt64(payout.Amount)
+	}
+	h.writeString(p.Author)
+	h.writeInt64(p.Timestamp)
+	return h.sum()
+}
+
+// Token returns the token which identifies the proposal.
+func (p *Proposal) Token() string {
+	hash := p.Hash()
+	return hash.String()
+}
+
+// Hash returns the hash of the vote authorization.
+func (s *StartVote) Hash() chainhash.Hash {
+	var h hasher
+	h.writeString(startVoteTag)
+	h.writeString(s.Token)
+	h.writeInt64(s.Timestamp)
+	return h.sum()
+}
+
+// Hash returns the hash of the vote.
+func (v *Vote) Hash() chainhash.Hash {
+	var h hasher
+	h.writeString(voteTag)
+	h.writeString(v.Token)
+	h.writeString(v.Ticket)
+	h.writeString(v.Choice)
+	return h.sum()
+}
+
+// Message returns the message that is signed for the record with the provided
+// hash.
+func Message(hash *chainhash.Hash) string {
+	return "Vigiliteia record " + hash.String()
+}
+
+// AnchorScript returns the null data script which anchors the provided merkle
+// root of record hashes into the chain.
+func AnchorScript(root *chainhash.Hash) []byte {
+	script := make([]byte, 0, 2+len(anchorMarker)+chainhash.HashSize)
+	script = append(script, txscript.OP_RETURN, txscript.OP_DATA_36)
+	script = append(script, anchorMarker[:]...)
+	return append(script, root[:]...)
+}
+
+// ParseAnchorScript returns the merkle root anchored by the provided script
+// and whether the script is an anchor script.
+func ParseAnchorScript(script []byte) (*chainhash.Hash, bool) {
+	const prefixLen = 2 + len(anchorMarker)
+	if len(script) != prefixLen+chainhash.HashSize ||
+		script[0] != txscript.OP_RETURN ||
+		script[1] != txscript.OP_DATA_36 ||
+		!bytes.Equal(script[2:prefixLen], anchorMarker[:]) {
+
+		return nil, false
+	}
+	var root chainhash.Hash
+	copy(root[:], script[prefixLen:])
+	return &root, true
+}
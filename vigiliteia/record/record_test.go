@@ -0,0 +1,97 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package record
+
+import (
+	"testing"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+)
+
+// TestHashes ensures the hash of a record commits to every field other than the
+// signature and that records of different types never share a hash.
+func TestHashes(t *testing.T) {
+	base := Proposal{
+		Name:        "name",
+		Description: "description",
+		Payouts:     []Payout{{Address: "addr", Amount: 1}},
+		Author:      "author",
+		Timestamp:   1,
+	}
+	mutations := []func(p *Proposal){
+		func(p *Proposal) { p.Name = "other" },
+		func(p *Proposal) { p.Description = "other" },
+		func(p *Proposal) { p.Payouts = nil },
+		func(p *Proposal) { p.Payouts = []Payout{{Address: "addr", Amount: 2}} },
+		func(p *Proposal) { p.Payouts = []Payout{{Address: "other", Amount: 1}} },
+		func(p *Proposal) { p.Author = "other" },
+		func(p *Proposal) { p.Timestamp = 2 },
+
+		// Moving bytes between adjacent fields must change the hash.
+		func(p *Proposal) { p.Name, p.Description = "namedesc", "ription" },
+	}
+	seen := map[chainhash.Hash]int{base.Hash(): -1}
+	for i, mutate := range mutations {
+		p := base
+		mutate(&p)
+		hash := p.Hash()
+		if j, ok := seen[hash]; ok {
+			t.Fatalf("mutation %d has the same hash as mutation %d", i, j)
+		}
+		seen[hash] = i
+	}
+
+	signed := base
+	signed.Signature = "signature"
+	if signed.Hash() != base.Hash() {
+		t.Fatal("hash of proposal commits to its signature")
+	}
+	if signed.Token() != base.Hash().String() {
+		t.Fatal("token of proposal is not its hash")
+	}
+
+	// The vote authorization and the vote share no fields with each other
+	// beyond the token, so equal field values must still hash differently.
+	sv := StartVote{Token: "token"}
+	vote := Vote{Token: "token"}
+	if sv.Hash() == vote.Hash() {
+		t.Fatal("vote authorization and vote have the same hash")
+	}
+	yes := Vote{Token: "token", Ticket: "ticket", Choice: VoteYes}
+	no := Vote{Token: "token", Ticket: "ticket", Choice: VoteNo}
+	if yes.Hash() == no.Hash() {
+		t.Fatal("hash of vote does not commit to its choice")
+	}
+}
+
+// TestAnchorScript ensures anchor scripts round trip and that other scripts are
+// not mistaken for anchor scripts.
+func TestAnchorScript(t *testing.T) {
+	root := chainhash.HashH([]byte("root"))
+	script := AnchorScript(&root)
+	got, ok := ParseAnchorScript(script)
+	if !ok {
+		t.Fatalf("anchor script %x not recognized", script)
+	}
+	if *got != root {
+		t.Fatalf("unexpected root -- got %v, want %v", got, root)
+	}
+
+	bad := [][]byte{
+		nil,
+		script[:len(script)-1],
+		append(append([]byte{}, script...), 0),
+		append([]byte{0x00}, script[1:]...),
+		append([]byte{script[0], 0x20}, script[2:]...),
+		append(append([]byte{}, script[:2]...), append([]byte("VGLB"),
+			script[6:]...)...),
+	}
+	for i, script := range bad {
+		if _, ok := ParseAnchorScript(script); ok {
+			t.Fatalf("script %d (%x) recognized as anchor script", i,
+				script)
+		}
+	}
+}
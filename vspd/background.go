@@ -0,0 +1,278 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/vspd/internal/database"
+	"github.com/kdsmith18542/vigil/vspd/internal/rpc"
+	"github.com/kdsmith18542/vigil/vspd/internal/webapi"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// requiredConfs is the number of confirmations required before a ticket or a
+// fee transaction is considered confirmed.
+const requiredConfs = 6
+
+// vspd houses the state of the VSP.  It follows the best block of the node to
+// confirm tickets and fees, adds tickets with confirmed fees to the voting
+// wallets, and records the outcome of the tickets.
+type vspd struct {
+	params  *chaincfg.Params
+	db      *database.DB
+	node    *rpc.Node
+	wallets *rpc.WalletPool
+	api     *webapi.Server
+
+	// blockCh is signalled when the node connects a block or the
+	// connection to the node is (re)established.
+	blockCh chan struct{}
+
+	// walletsOnline is the number of voting wallets that were online after
+	// the previous update.  All tickets are synced with the wallets again
+	// when more wallets are online than before since wallets which were
+	// offline may have missed tickets.
+	walletsOnline int
+}
+
+// signalBlock signals the update loop to update the VSP without blocking.
+func (v *vspd) signalBlock() {
+	select {
+	case v.blockCh <- struct{}{}:
+	default:
+	}
+}
+
+// run updates the VSP whenever a block is connected until the provided context
+// is cancelled.  Failed updates are logged and retried on the next block.
+func (v *vspd) run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-v.blockCh:
+		}
+		if err := v.update(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			vspdLog.Errorf("Unable to update: %v", err)
+		}
+	}
+}
+
+// transactionConfs returns the number of confirmations and the height of the
+// block of the transaction with the provided hash.  A negative number of
+// confirmations is returned when the node does not know the transaction.
+func (v *vspd) transactionConfs(ctx context.Context, txHash string) (int64, int64, error) {
+	hash, err := chainhash.NewHashFromStr(txHash)
+	if err != nil {
+		return 0, 0, err
+	}
+	tx, err := v.node.Transaction(ctx, hash)
+	if err != nil {
+		return 0, 0, err
+	}
+	if tx == nil {
+		return -1, 0, nil
+	}
+	return tx.Confirmations, tx.BlockHeight, nil
+}
+
+// confirmTickets marks the registered tickets which have enough confirmations
+// as confirmed.
+func (v *vspd) confirmTickets(ctx context.Context) error {
+	tickets, err := v.db.FilterTickets(func(t *database.Ticket) bool {
+		return !t.Confirmed
+	})
+	if err != nil {
+		return err
+	}
+	for _, ticket := range tickets {
+		confs, height, err := v.transactionConfs(ctx, ticket.Hash)
+		if err != nil {
+			return err
+		}
+		if confs < requiredConfs {
+			continue
+		}
+		ticket.Confirmed = true
+		ticket.PurchaseHeight = height
+		if err := v.db.UpdateTicket(ticket); err != nil {
+			return err
+		}
+		vspdLog.Infof("Ticket %s confirmed at height %d", ticket.Hash,
+			height)
+	}
+	return nil
+}
+
+// confirmFees marks the broadcast fees of confirmed tickets which have enough
+// confirmations as confirmed and returns the tickets.  Fee transactions which
+// are no longer known to the node are broadcast again and marked as failed
+// when that is not possible.
+func (v *vspd) confirmFees(ctx context.Context) ([]*database.Ticket, error) {
+	tickets, err := v.db.FilterTickets(func(t *database.Ticket) bool {
+		return t.FeeTxStatus == database.FeeBroadcast
+	})
+	if err != nil {
+		return nil, err
+	}
+	var confirmed []*database.Ticket
+	for _, ticket := range tickets {
+		confs, _, err := v.transactionConfs(ctx, ticket.FeeTxHash)
+		if err != nil {
+			return nil, err
+		}
+		if confs < 0 {
+			err := v.rebroadcastFee(ctx, ticket)
+			if err == nil {
+				continue
+			}
+			vspdLog.Warnf("Fee tx %s of ticket %s is no longer known to "+
+				"the node and could not be broadcast again: %v",
+				ticket.FeeTxHash, ticket.Hash, err)
+			ticket.FeeTxStatus = database.FeeError
+			if err := v.db.UpdateTicket(ticket); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if confs < requiredConfs || !ticket.Confirmed {
+			continue
+		}
+		ticket.FeeTxStatus = database.FeeConfirmed
+		if err := v.db.UpdateTicket(ticket); err != nil {
+			return nil, err
+		}
+		vspdLog.Infof("Fee tx %s of ticket %s confirmed", ticket.FeeTxHash,
+			ticket.Hash)
+		confirmed = append(confirmed, ticket)
+	}
+	return confirmed, nil
+}
+
+// decodeTx decodes the provided hex encoded transaction.
+func decodeTx(s string) (*wire.MsgTx, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// rebroadcastFee broadcasts the fee transaction of the provided ticket again.
+func (v *vspd) rebroadcastFee(ctx context.Context, ticket *database.Ticket) error {
+	feeTx, err := decodeTx(ticket.FeeTxHex)
+	if err != nil {
+		return err
+	}
+	return v.node.SendRawTransaction(ctx, feeTx)
+}
+
+// recordOutcomes records the outcome of the voting tickets which are no longer
+// live.
+func (v *vspd) recordOutcomes(ctx context.Context, height int64) error {
+	maturity := int64(v.params.TicketMaturity)
+	tickets, err := v.db.FilterTickets(func(t *database.Ticket) bool {
+		return t.FeeTxStatus == database.FeeConfirmed && t.Outcome == "" &&
+			height > t.PurchaseHeight+maturity
+	})
+	if err != nil {
+		return err
+	}
+	for _, ticket := range tickets {
+		hash, err := chainhash.NewHashFromStr(ticket.Hash)
+		if err != nil {
+			return err
+		}
+		live, err := v.node.ExistsLiveTicket(ctx, hash)
+		if err != nil {
+			return err
+		}
+		if live {
+			continue
+		}
+		outcome, err := v.wallets.TicketOutcome(ctx, ticket.Hash)
+		if err != nil {
+			return err
+		}
+		if outcome == "" {
+			continue
+		}
+		ticket.Outcome = outcome
+		if err := v.db.UpdateTicket(ticket); err != nil {
+			return err
+		}
+		vspdLog.Infof("Ticket %s %s", ticket.Hash, outcome)
+	}
+	return nil
+}
+
+// votingTickets returns the tickets with a confirmed fee which may still vote.
+func (v *vspd) votingTickets() ([]*database.Ticket, error) {
+	return v.db.FilterTickets(func(t *database.Ticket) bool {
+		return t.FeeTxStatus == database.FeeConfirmed && t.Outcome == ""
+	})
+}
+
+// update brings the VSP in line with the best block of the node.
+func (v *vspd) update(ctx context.Context) error {
+	header, err := v.node.BestBlock(ctx)
+	if err != nil {
+		return err
+	}
+	vspdLog.Debugf("Updating at height %d", header.Height)
+
+	if err := v.confirmTickets(ctx); err != nil {
+		return fmt.Errorf("unable to confirm tickets: %w", err)
+	}
+	confirmed, err := v.confirmFees(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to confirm fees: %w", err)
+	}
+
+	// Sync all voting tickets when wallets came online since the previous
+	// update and only the newly confirmed tickets otherwise.
+	var online int
+	for _, status := range v.wallets.Status(ctx) {
+		if status.Connected {
+			online++
+		}
+	}
+	if online > v.walletsOnline {
+		confirmed, err = v.votingTickets()
+		if err != nil {
+			return err
+		}
+	}
+	if len(confirmed) > 0 {
+		if err := v.wallets.SyncTickets(ctx, confirmed); err != nil {
+			// Retry all tickets on the next update.
+			online = 0
+			vspdLog.Errorf("Unable to sync tickets with the voting "+
+				"wallets: %v", err)
+		}
+	}
+	v.walletsOnline = online
+
+	if err := v.recordOutcomes(ctx, int64(header.Height)); err != nil {
+		return fmt.Errorf("unable to record ticket outcomes: %w", err)
+	}
+	if err := v.api.UpdateStats(ctx); err != nil {
+		return fmt.Errorf("unable to update statistics: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package client provides a client for the vspd API.
+//
+// The client signs the requests for ticket specific endpoints with the
+// commitment address of the ticket and verifies that every response is signed
+// by the configured VSP public key.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kdsmith18542/vigil/slog"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/vspd/types/v3"
+)
+
+// maxResponseSize is the maximum size of a response body read by the client.
+const maxResponseSize = 1 << 20
+
+// SignFunc signs the passed message with the private key of the passed address
+// and returns the signature in the compact format used by message signing.
+type SignFunc func(ctx context.Context, message string, addr stdaddr.Address) ([]byte, error)
+
+// Client is a client for the vspd API.  The embedded HTTP client may be
+// modified to configure the transport.
+type Client struct {
+	http.Client
+
+	// URL is the base URL of the VSP.
+	URL string
+
+	// PubKey is the ed25519 public key of the VSP which must have signed
+	// all responses.
+	PubKey []byte
+
+	// Sign signs the requests for ticket specific endpoints with the
+	// commitment address of the ticket.
+	Sign SignFunc
+
+	// Log is used to log the requests and responses.
+	Log slog.Logger
+}
+
+// errDifferentRequest is returned when the request echoed by the server does
+// not match the request that was sent.
+var errDifferentRequest = errors.New("server response contains differing request")
+
+// VspInfo returns the information and statistics of the VSP.
+func (c *Client) VspInfo(ctx context.Context) (*types.VspInfoResponse, error) {
+	var resp types.VspInfoResponse
+	err := c.get(ctx, "/api/v3/vspinfo", &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FeeAddress registers a ticket with the VSP and returns the address and
+// amount of the fee the VSP requires to vote the ticket.
+func (c *Client) FeeAddress(ctx context.Context, req types.FeeAddressRequest,
+	commitmentAddr stdaddr.Address) (*types.FeeAddressResponse, error) {
+
+	var resp types.FeeAddressResponse
+	body, err := c.post(ctx, "/api/v3/feeaddress", commitmentAddr, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(body, resp.Request) {
+		return nil, errDifferentRequest
+	}
+	return &resp, nil
+}
+
+// PayFee provides the fee transaction, the voting key, and the voting
+// preferences of a registered ticket to the VSP.
+func (c *Client) PayFee(ctx context.Context, req types.PayFeeRequest,
+	commitmentAddr stdaddr.Address) (*types.PayFeeResponse, error) {
+
+	var resp types.PayFeeResponse
+	body, err := c.post(ctx, "/api/v3/payfee", commitmentAddr, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(body, resp.Request) {
+		return nil, errDifferentRequest
+	}
+	return &resp, nil
+}
+
+// TicketStatus returns the status of a ticket registered with the VSP.
+func (c *Client) TicketStatus(ctx context.Context, req types.TicketStatusRequest,
+	commitmentAddr stdaddr.Address) (*types.TicketStatusResponse, error) {
+
+	var resp types.TicketStatusResponse
+	body, err := c.post(ctx, "/api/v3/ticketstatus", commitmentAddr, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(body, resp.Request) {
+		return nil, errDifferentRequest
+	}
+	return &resp, nil
+}
+
+// SetVoteChoices updates the voting preferences of a ticket registered with
+// the VSP.
+func (c *Client) SetVoteChoices(ctx context.Context, req types.SetVoteChoicesRequest,
+	commitmentAddr stdaddr.Address) (*types.SetVoteChoicesResponse, error) {
+
+	var resp types.SetVoteChoicesResponse
+	body, err := c.post(ctx, "/api/v3/setvotechoices", commitmentAddr, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(body, resp.Request) {
+		return nil, errDifferentRequest
+	}
+	return &resp, nil
+}
+
+// post marshals and signs the request with the passed address, sends it to the
+// endpoint at path, and unmarshals the response into resp.  The marshalled
+// request is returned so the caller can ensure it was echoed by the server.
+func (c *Client) post(ctx context.Context, path string, addr stdaddr.Address,
+	req, resp interface{}) ([]byte, error) {
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal request: %w", err)
+	}
+	sig, err := c.Sign(ctx, string(body), addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(types.ClientSignatureHeader,
+		base64.StdEncoding.EncodeToString(sig))
+	if c.Log != nil {
+		c.Log.Tracef("Sending request to %s: %s", path, body)
+	}
+	return body, c.do(httpReq, resp)
+}
+
+// get sends a GET request to the endpoint at path and unmarshals the response
+// into resp.
+func (c *Client) get(ctx context.Context, path string, resp interface{}) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.URL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.Log != nil {
+		c.Log.Tracef("Sending request to %s", path)
+	}
+	return c.do(httpReq, resp)
+}
+
+// do sends the passed request, verifies the signature of the response, and
+// unmarshals it into resp.  Error responses of the server are returned as
+// types.ErrorResponse.
+func (c *Client) do(httpReq *http.Request, resp interface{}) error {
+	httpResp, err := c.Client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", httpReq.Method, httpReq.URL.Path, err)
+	}
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("unable to read response body: %w", err)
+	}
+	if c.Log != nil {
+		c.Log.Tracef("Response from %s: %s %s", httpReq.URL.Path,
+			httpResp.Status, body)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr types.ErrorResponse
+		if err := json.Unmarshal(body, &apiErr); err == nil &&
+			apiErr.Message != "" {
+
+			return apiErr
+		}
+		return fmt.Errorf("%s %s: unexpected status %s", httpReq.Method,
+			httpReq.URL.Path, httpResp.Status)
+	}
+
+	// Ensure the response was signed by the VSP.
+	sig, err := base64.StdEncoding.DecodeString(
+		httpResp.Header.Get(types.ServerSignatureHeader))
+	if err != nil {
+		return fmt.Errorf("unable to decode server signature: %w", err)
+	}
+	if len(c.PubKey) != ed25519.PublicKeySize ||
+		!ed25519.Verify(c.PubKey, body, sig) {
+
+		return errors.New("server signature is invalid")
+	}
+
+	if err := json.Unmarshal(body, resp); err != nil {
+		return fmt.Errorf("unable to unmarshal response: %w", err)
+	}
+	return nil
+}
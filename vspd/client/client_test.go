@@ -0,0 +1,102 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/vspd/types/v3"
+)
+
+// TestClient ensures the client signs requests, verifies the signature of
+// responses and the echoed requests, and returns error responses as
+// types.ErrorResponse.
+func TestClient(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	const clientSig = "client signature"
+	var (
+		corruptSig  bool
+		echoRequest = true
+		feeExpired  bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqBody, _ := io.ReadAll(r.Body)
+		sig, _ := base64.StdEncoding.DecodeString(
+			r.Header.Get(types.ClientSignatureHeader))
+		if string(sig) != clientSig {
+			t.Errorf("unexpected client signature %q", sig)
+		}
+
+		status := http.StatusOK
+		var resp interface{}
+		switch {
+		case feeExpired:
+			status = types.ErrFeeExpired.HTTPStatus()
+			resp = types.ErrorResponse{
+				Code:    types.ErrFeeExpired,
+				Message: types.ErrFeeExpired.DefaultMessage(),
+			}
+		case echoRequest:
+			resp = types.PayFeeResponse{Timestamp: 1, Request: reqBody}
+		default:
+			resp = types.PayFeeResponse{Timestamp: 1}
+		}
+		body, _ := json.Marshal(resp)
+		respSig := ed25519.Sign(privKey, body)
+		if corruptSig {
+			respSig[0] ^= 0xff
+		}
+		w.Header().Set(types.ServerSignatureHeader,
+			base64.StdEncoding.EncodeToString(respSig))
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	c := &Client{
+		URL:    srv.URL,
+		PubKey: pubKey,
+		Sign: func(context.Context, string, stdaddr.Address) ([]byte, error) {
+			return []byte(clientSig), nil
+		},
+	}
+	ctx := context.Background()
+	req := types.PayFeeRequest{TicketHash: "ticket"}
+	if _, err := c.PayFee(ctx, req, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	echoRequest = false
+	if _, err := c.PayFee(ctx, req, nil); !errors.Is(err, errDifferentRequest) {
+		t.Fatalf("unexpected error for differing request -- got %v, want %v",
+			err, errDifferentRequest)
+	}
+
+	echoRequest, corruptSig = true, true
+	if _, err := c.PayFee(ctx, req, nil); err == nil {
+		t.Fatal("did not receive error for invalid server signature")
+	}
+
+	feeExpired = true
+	_, err = c.PayFee(ctx, req, nil)
+	var apiErr types.ErrorResponse
+	if !errors.As(err, &apiErr) || apiErr.Code != types.ErrFeeExpired {
+		t.Fatalf("unexpected error for error response -- got %v, want "+
+			"code %v", err, types.ErrFeeExpired)
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/kdsmith18542/vigil/vspd/client/v4
+
+go 1.23.0
+
+require (
+	github.com/kdsmith18542/vigil/slog v1.2.0
+	github.com/kdsmith18542/vigil/txscript/v4 v4.1.1
+	github.com/kdsmith18542/vigil/vspd/types/v3 v3.0.0
+)
+
+replace (
+	github.com/kdsmith18542/vigil/txscript/v4 => ../../node/txscript
+	github.com/kdsmith18542/vigil/vspd/types/v3 => ../types
+)
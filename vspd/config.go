@@ -0,0 +1,409 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/slog"
+)
+
+const (
+	appName               = "vspd"
+	defaultConfigFilename = "vspd.conf"
+	defaultDataDirname    = "data"
+	defaultLogDirname     = "logs"
+	defaultLogFilename    = "vspd.log"
+	defaultDBFilename     = "vspd.db"
+	defaultLogLevel       = "info"
+	defaultListenPort     = "8800"
+	defaultFeePercentage  = 3.0
+)
+
+var (
+	defaultHomeDir            = VGLutil.AppDataDir(appName, false)
+	defaultConfigFile         = filepath.Join(defaultHomeDir, defaultConfigFilename)
+	defaultNodeRPCCertFile    = filepath.Join(VGLutil.AppDataDir("vgld", false), "rpc.cert")
+	defaultWalletClientCert   = filepath.Join(defaultHomeDir, "client.pem")
+	defaultWalletClientKey    = filepath.Join(defaultHomeDir, "client-key.pem")
+	defaultWalletGRPCCertFile = filepath.Join(VGLutil.AppDataDir("vglwallet", false), "rpc.cert")
+)
+
+// config defines the configuration options for vspd.
+//
+// See loadConfig for details on the configuration load process.
+type config struct {
+	// General application behavior.
+	HomeDir    string `short:"A" long:"appdata" description:"Path to application home directory"`
+	ConfigFile string `short:"C" long:"configfile" description:"Path to configuration file"`
+	DataDir    string `short:"b" long:"datadir" description:"Directory to store data"`
+	LogDir     string `long:"logdir" description:"Directory to log output"`
+	TestNet    bool   `long:"testnet" description:"Use the test network"`
+	SimNet     bool   `long:"simnet" description:"Use the simulation test network"`
+	RegNet     bool   `long:"regnet" description:"Use the regression test network"`
+	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+
+	// Node RPC options.
+	NodeRPCConnect string `long:"noderpcconnect" description:"Hostname/IP and port of the vgld RPC server (default port: 9109, testnet: 19109)"`
+	NodeRPCUser    string `long:"noderpcuser" description:"Username for vgld RPC connections"`
+	NodeRPCPass    string `long:"noderpcpass" default-mask:"-" description:"Password for vgld RPC connections"`
+	NodeRPCCert    string `long:"noderpccert" description:"File containing the vgld RPC certificate"`
+
+	// Voting wallet options.
+	WalletHosts      []string `long:"wallethost" description:"Add the Hostname/IP and port of the gRPC server of a voting wallet (default port: 9111, testnet: 19111)"`
+	WalletCerts      []string `long:"walletcert" description:"Add the file containing the gRPC certificate of a voting wallet -- Specify once per wallethost in the same order or once for all wallets"`
+	WalletClientCert string   `long:"walletclientcert" description:"File containing the client certificate to authenticate with the voting wallets"`
+	WalletClientKey  string   `long:"walletclientkey" description:"File containing the client key to authenticate with the voting wallets"`
+	WalletPass       string   `long:"walletpass" default-mask:"-" description:"Private passphrase of the voting wallets used to import voting keys"`
+
+	// VSP options.
+	FeeXPub       string  `long:"feexpub" description:"Extended public key of the wallet account which receives the fees"`
+	FeePercentage float64 `long:"feepercentage" description:"Percentage of the vote reward charged as fee"`
+	VspClosed     bool    `long:"vspclosed" description:"Reject new tickets while still voting the registered tickets"`
+	VspClosedMsg  string  `long:"vspclosedmsg" description:"Message returned to clients while the VSP is closed"`
+	AdminPass     string  `long:"adminpass" default-mask:"-" description:"Password of the admin page -- The admin page is disabled when not set"`
+
+	// HTTP server options.
+	Listeners []string `long:"listen" description:"Add an interface/port to serve the API and admin page on (default: localhost:8800)"`
+
+	// Cooked options ready for use.
+	params *params
+}
+
+// cleanAndExpandPath expands environment variables and leading ~ in the
+// passed path, cleans the result, and returns it.
+func cleanAndExpandPath(path string) string {
+	// Nothing to do when no path is given.
+	if path == "" {
+		return path
+	}
+
+	// NOTE: The os.ExpandEnv doesn't work with Windows cmd.exe-style
+	// %VARIABLE%, but the variables can still be expanded via POSIX-style
+	// $VARIABLE.
+	path = os.ExpandEnv(path)
+
+	if !strings.HasPrefix(path, "~") {
+		return filepath.Clean(path)
+	}
+
+	// Expand initial ~ to the current user's home directory, or ~otheruser
+	// to otheruser's home directory.  On Windows, both forward and backward
+	// slashes can be used.
+	path = path[1:]
+
+	var pathSeparators string
+	if runtime.GOOS == "windows" {
+		pathSeparators = string(os.PathSeparator) + "/"
+	} else {
+		pathSeparators = string(os.PathSeparator)
+	}
+
+	userName := ""
+	if i := strings.IndexAny(path, pathSeparators); i != -1 {
+		userName = path[:i]
+		path = path[i:]
+	}
+
+	homeDir := ""
+	var u *user.User
+	var err error
+	if userName == "" {
+		u, err = user.Current()
+	} else {
+		u, err = user.Lookup(userName)
+	}
+	if err == nil {
+		homeDir = u.HomeDir
+	}
+	// Fallback to CWD if user lookup fails or user has no home directory.
+	if homeDir == "" {
+		homeDir = "."
+	}
+
+	return filepath.Join(homeDir, path)
+}
+
+// validLogLevel returns whether or not logLevel is a valid debug log level.
+func validLogLevel(logLevel string) bool {
+	_, ok := slog.LevelFromString(logLevel)
+	return ok
+}
+
+// supportedSubsystems returns a sorted slice of the supported subsystems for
+// logging purposes.
+func supportedSubsystems() []string {
+	// Convert the subsystemLoggers map keys to a slice.
+	subsystems := make([]string, 0, len(subsystemLoggers))
+	for subsysID := range subsystemLoggers {
+		subsystems = append(subsystems, subsysID)
+	}
+
+	// Sort the subsystems for stable display.
+	sort.Strings(subsystems)
+	return subsystems
+}
+
+// parseAndSetDebugLevels attempts to parse the specified debug level and set
+// the levels accordingly.  An appropriate error is returned if anything is
+// invalid.
+func parseAndSetDebugLevels(debugLevel string) error {
+	// When the specified string doesn't have any delimiters, treat it as
+	// the log level for all subsystems.
+	if !strings.Contains(debugLevel, ",") && !strings.Contains(debugLevel, "=") {
+		// Validate debug log level.
+		if !validLogLevel(debugLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, debugLevel)
+		}
+
+		// Change the logging level for all subsystems.
+		setLogLevels(debugLevel)
+
+		return nil
+	}
+
+	// Split the specified string into subsystem/level pairs while detecting
+	// issues and update the log levels accordingly.
+	for _, logLevelPair := range strings.Split(debugLevel, ",") {
+		if !strings.Contains(logLevelPair, "=") {
+			str := "the specified debug level contains an invalid " +
+				"subsystem/level pair [%v]"
+			return fmt.Errorf(str, logLevelPair)
+		}
+
+		// Extract the specified subsystem and log level.
+		fields := strings.Split(logLevelPair, "=")
+		subsysID, logLevel := fields[0], fields[1]
+
+		// Validate subsystem.
+		if _, exists := subsystemLoggers[subsysID]; !exists {
+			str := "the specified subsystem [%v] is invalid -- " +
+				"supported subsystems %v"
+			return fmt.Errorf(str, subsysID, supportedSubsystems())
+		}
+
+		// Validate log level.
+		if !validLogLevel(logLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, logLevel)
+		}
+
+		setLogLevel(subsysID, logLevel)
+	}
+
+	return nil
+}
+
+// normalizeAddress returns addr with the passed default port appended if there
+// is not already a port specified.
+func normalizeAddress(addr, defaultPort string) string {
+	_, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return net.JoinHostPort(addr, defaultPort)
+	}
+	return addr
+}
+
+// fileExists reports whether the named file or directory exists.
+func fileExists(name string) bool {
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadConfig initializes and parses the config using a config file and command
+// line options.
+//
+// The configuration proceeds as follows:
+//  1. Start with a default config with sane settings
+//  2. Pre-parse the command line to check for an alternative config file
+//  3. Load configuration file overwriting defaults with any specified options
+//  4. Parse CLI options and overwrite/add any specified options
+//
+// The above results in vspd functioning properly without any config
+// settings while still allowing the user to override settings with config
+// files and command line options.  Command line options always take
+// precedence.
+func loadConfig() (*config, error) {
+	// Default config.
+	cfg := config{
+		HomeDir:          defaultHomeDir,
+		ConfigFile:       defaultConfigFile,
+		DebugLevel:       defaultLogLevel,
+		NodeRPCCert:      defaultNodeRPCCertFile,
+		WalletClientCert: defaultWalletClientCert,
+		WalletClientKey:  defaultWalletClientKey,
+		FeePercentage:    defaultFeePercentage,
+	}
+
+	// Pre-parse the command line options to see if an alternative config
+	// file or home directory was specified.  Any errors aside from the help
+	// message error can be ignored here since they will be caught by the
+	// final parse below.
+	preCfg := cfg
+	preParser := flags.NewParser(&preCfg, flags.HelpFlag)
+	_, err := preParser.Parse()
+	if err != nil {
+		var e *flags.Error
+		if errors.As(err, &e) && e.Type == flags.ErrHelp {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+	}
+
+	// Update the home directory and config file location if specified.
+	if preCfg.HomeDir != defaultHomeDir {
+		cfg.HomeDir = cleanAndExpandPath(preCfg.HomeDir)
+		if preCfg.ConfigFile == defaultConfigFile {
+			preCfg.ConfigFile = filepath.Join(cfg.HomeDir,
+				defaultConfigFilename)
+		}
+	}
+
+	// Load additional config from file.
+	parser := flags.NewParser(&cfg, flags.Default)
+	configFile := cleanAndExpandPath(preCfg.ConfigFile)
+	if fileExists(configFile) {
+		err := flags.NewIniParser(parser).ParseFile(configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+			return nil, err
+		}
+	}
+
+	// Parse command line options again to ensure they take precedence.
+	_, err = parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// Multiple networks can't be selected simultaneously.  Count the number
+	// of network flags passed and assign the active network params.
+	funcName := "loadConfig"
+	numNets := 0
+	cfg.params = &mainNetParams
+	if cfg.TestNet {
+		numNets++
+		cfg.params = &testNet3Params
+	}
+	if cfg.SimNet {
+		numNets++
+		cfg.params = &simNetParams
+	}
+	if cfg.RegNet {
+		numNets++
+		cfg.params = &regNetParams
+	}
+	if numNets > 1 {
+		str := "%s: the testnet, regnet, and simnet params can't be " +
+			"used together -- choose one of the three"
+		return nil, fmt.Errorf(str, funcName)
+	}
+
+	// Namespace the data and log directories per network.
+	if cfg.DataDir == "" {
+		cfg.DataDir = filepath.Join(cfg.HomeDir, defaultDataDirname)
+	}
+	cfg.DataDir = filepath.Join(cleanAndExpandPath(cfg.DataDir),
+		cfg.params.Name)
+	if cfg.LogDir == "" {
+		cfg.LogDir = filepath.Join(cfg.HomeDir, defaultLogDirname)
+	}
+	cfg.LogDir = filepath.Join(cleanAndExpandPath(cfg.LogDir), cfg.params.Name)
+
+	// Special show command to list supported subsystems and exit.
+	if cfg.DebugLevel == "show" {
+		fmt.Println("Supported subsystems", supportedSubsystems())
+		os.Exit(0)
+	}
+
+	// Initialize log rotation.  After log rotation has been initialized, the
+	// logger variables may be used.
+	initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename))
+
+	// Parse, validate, and set debug log level(s).
+	if err := parseAndSetDebugLevels(cfg.DebugLevel); err != nil {
+		return nil, fmt.Errorf("%s: %w", funcName, err)
+	}
+
+	// Connect to the node on localhost with the default port for the active
+	// network by default.
+	if cfg.NodeRPCConnect == "" {
+		cfg.NodeRPCConnect = "localhost"
+	}
+	cfg.NodeRPCConnect = normalizeAddress(cfg.NodeRPCConnect,
+		cfg.params.nodeRPCPort)
+	cfg.NodeRPCCert = cleanAndExpandPath(cfg.NodeRPCCert)
+
+	// The fee xpub is required to derive the fee addresses and at least one
+	// voting wallet is required to vote the tickets.
+	if cfg.FeeXPub == "" {
+		return nil, fmt.Errorf("%s: the feexpub option is required", funcName)
+	}
+	if cfg.FeePercentage < 0.01 || cfg.FeePercentage > 100 {
+		str := "%s: the feepercentage option must be between 0.01 and 100"
+		return nil, fmt.Errorf(str, funcName)
+	}
+	if len(cfg.WalletHosts) == 0 {
+		str := "%s: at least one voting wallet must be specified with the " +
+			"wallethost option"
+		return nil, fmt.Errorf(str, funcName)
+	}
+	for i, addr := range cfg.WalletHosts {
+		if cfg.params.walletGRPCPort == "" {
+			if _, _, err := net.SplitHostPort(addr); err != nil {
+				str := "%s: the port of wallethost %s must be specified " +
+					"on %s"
+				return nil, fmt.Errorf(str, funcName, addr, cfg.params.Name)
+			}
+			continue
+		}
+		cfg.WalletHosts[i] = normalizeAddress(addr, cfg.params.walletGRPCPort)
+	}
+	switch len(cfg.WalletCerts) {
+	case 0:
+		cfg.WalletCerts = []string{defaultWalletGRPCCertFile}
+		fallthrough
+	case 1:
+		for len(cfg.WalletCerts) < len(cfg.WalletHosts) {
+			cfg.WalletCerts = append(cfg.WalletCerts, cfg.WalletCerts[0])
+		}
+	case len(cfg.WalletHosts):
+	default:
+		str := "%s: the walletcert option must be specified once or once " +
+			"per wallethost"
+		return nil, fmt.Errorf(str, funcName)
+	}
+	for i, path := range cfg.WalletCerts {
+		cfg.WalletCerts[i] = cleanAndExpandPath(path)
+	}
+	cfg.WalletClientCert = cleanAndExpandPath(cfg.WalletClientCert)
+	cfg.WalletClientKey = cleanAndExpandPath(cfg.WalletClientKey)
+
+	// Serve the API on localhost by default since it is typically run
+	// behind a reverse proxy.
+	if len(cfg.Listeners) == 0 {
+		cfg.Listeners = []string{"localhost"}
+	}
+	for i, addr := range cfg.Listeners {
+		cfg.Listeners[i] = normalizeAddress(addr, defaultListenPort)
+	}
+
+	return &cfg, nil
+}
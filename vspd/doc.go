@@ -0,0 +1,80 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+vspd is a voting service provider (VSP) for Vigil.
+
+A VSP votes tickets on behalf of ticket holders in exchange for a fee so the
+ticket holders do not have to keep a wallet online.  Ticket holder wallets
+register a ticket by requesting a fee address, paying the fee with the private
+key of the voting address of the ticket, and setting their voting preferences.
+Every request is signed with the commitment address of the ticket and every
+response is signed with the ed25519 key of the VSP.
+
+Fee transactions are verified and broadcast through vgld.  Once the ticket and
+the fee are confirmed, the voting key of the ticket is imported into every
+wallet of a pool of voting wallets over their gRPC servers and the voting
+preferences of the ticket are set in each of them.  The outcome of the tickets
+is recorded once they are no longer live.
+
+The API provides the following endpoints:
+
+	GET  /api/v3/vspinfo         Public key, fee, and statistics of the VSP
+	POST /api/v3/feeaddress      Fee address and amount of a ticket
+	POST /api/v3/payfee          Fee transaction, voting key, and preferences
+	POST /api/v3/ticketstatus    Fee status and preferences of a ticket
+	POST /api/v3/setvotechoices  Update the preferences of a ticket
+
+An admin page showing the status of the VSP and the voting wallets is served
+on /admin when an admin password is configured.
+
+Usage:
+
+	vspd [OPTIONS]
+
+Application Options:
+
+	-A, --appdata=             Path to application home directory
+	-C, --configfile=          Path to configuration file
+	-b, --datadir=             Directory to store data
+	    --logdir=              Directory to log output
+	    --testnet              Use the test network
+	    --simnet               Use the simulation test network
+	    --regnet               Use the regression test network
+	-d, --debuglevel=          Logging level for all subsystems {trace, debug,
+	                           info, warn, error, critical} (info)
+	    --noderpcconnect=      Hostname/IP and port of the vgld RPC server
+	                           (default port: 9109, testnet: 19109)
+	    --noderpcuser=         Username for vgld RPC connections
+	    --noderpcpass=         Password for vgld RPC connections
+	    --noderpccert=         File containing the vgld RPC certificate
+	    --wallethost=          Add the Hostname/IP and port of the gRPC server
+	                           of a voting wallet (default port: 9111,
+	                           testnet: 19111)
+	    --walletcert=          Add the file containing the gRPC certificate of
+	                           a voting wallet -- Specify once per wallethost in
+	                           the same order or once for all wallets
+	    --walletclientcert=    File containing the client certificate to
+	                           authenticate with the voting wallets
+	    --walletclientkey=     File containing the client key to authenticate
+	                           with the voting wallets
+	    --walletpass=          Private passphrase of the voting wallets used to
+	                           import voting keys
+	    --feexpub=             Extended public key of the wallet account which
+	                           receives the fees
+	    --feepercentage=       Percentage of the vote reward charged as fee (3)
+	    --vspclosed            Reject new tickets while still voting the
+	                           registered tickets
+	    --vspclosedmsg=        Message returned to clients while the VSP is
+	                           closed
+	    --adminpass=           Password of the admin page -- The admin page is
+	                           disabled when not set
+	    --listen=              Add an interface/port to serve the API and admin
+	                           page on (default: localhost:8800)
+
+Help Options:
+
+	-h, --help                 Show this help message
+*/
+package main
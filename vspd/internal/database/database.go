@@ -0,0 +1,300 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package database stores the tickets registered with the VSP along with the
+// keys of the VSP in an embedded database.
+package database
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// dbVersion is the current version of the database layout.
+const dbVersion = 1
+
+var (
+	metaBucket      = []byte("meta")
+	ticketsBucket   = []byte("tickets")
+	versionKey      = []byte("version")
+	signingKeyKey   = []byte("signingkey")
+	feeAddrIndexKey = []byte("feeaddrindex")
+)
+
+// FeeStatus identifies the state of the fee payment of a ticket.  The values
+// are part of the vspd API.
+type FeeStatus string
+
+// These constants define the possible fee statuses.
+const (
+	// NoFee indicates no fee transaction was received for the ticket.
+	NoFee FeeStatus = "none"
+
+	// FeeBroadcast indicates the fee transaction was received and
+	// broadcast to the network.
+	FeeBroadcast FeeStatus = "broadcast"
+
+	// FeeConfirmed indicates the fee transaction has enough confirmations
+	// for the ticket to be added to the voting wallets.
+	FeeConfirmed FeeStatus = "confirmed"
+
+	// FeeError indicates the fee transaction was broadcast but is no longer
+	// known to the network, for example because it was double spent.  A new
+	// fee transaction must be provided.
+	FeeError FeeStatus = "error"
+)
+
+// TicketOutcome identifies how a ticket with a confirmed fee was spent.
+type TicketOutcome string
+
+// These constants define the possible ticket outcomes.  Tickets that are still
+// able to vote have no outcome.
+const (
+	Voted   TicketOutcome = "voted"
+	Missed  TicketOutcome = "missed"
+	Expired TicketOutcome = "expired"
+
+	// Revoked indicates the ticket was revoked before it was known whether
+	// it missed its vote or expired.
+	Revoked TicketOutcome = "revoked"
+)
+
+// Ticket is a ticket registered with the VSP.
+type Ticket struct {
+	Hash              string `json:"hash"`
+	CommitmentAddress string `json:"commitmentaddress"`
+	VotingAddress     string `json:"votingaddress"`
+
+	// PurchaseHeight is the height of the block which mined the ticket.  It
+	// is only valid once the ticket is confirmed.
+	PurchaseHeight int64 `json:"purchaseheight"`
+	Confirmed      bool  `json:"confirmed"`
+
+	FeeAddressIndex uint32 `json:"feeaddressindex"`
+	FeeAddress      string `json:"feeaddress"`
+	FeeAmount       int64  `json:"feeamount"`
+	FeeExpiration   int64  `json:"feeexpiration"`
+
+	FeeTxHex    string    `json:"feetxhex,omitempty"`
+	FeeTxHash   string    `json:"feetxhash,omitempty"`
+	FeeTxStatus FeeStatus `json:"feetxstatus"`
+
+	// VotingWIF is the private key of the voting address provided with the
+	// fee payment.
+	VotingWIF string `json:"votingwif,omitempty"`
+
+	VoteChoices    map[string]string `json:"votechoices"`
+	TSpendPolicy   map[string]string `json:"tspendpolicy"`
+	TreasuryPolicy map[string]string `json:"treasurypolicy"`
+
+	// VoteChangeTime is the timestamp of the request which last changed the
+	// voting preferences.  Requests with older or equal timestamps are
+	// rejected to prevent replays.
+	VoteChangeTime int64 `json:"votechangetime"`
+
+	Outcome TicketOutcome `json:"outcome,omitempty"`
+}
+
+// FeeExpired returns whether the fee amount and address of the ticket are no
+// longer valid and must be renewed before a fee can be paid.
+func (t *Ticket) FeeExpired() bool {
+	return time.Now().Unix() > t.FeeExpiration
+}
+
+// TicketCounts houses the number of tickets with a confirmed fee by outcome.
+type TicketCounts struct {
+	Voting  int64
+	Voted   int64
+	Missed  int64
+	Expired int64
+}
+
+// DB is the VSP database.
+type DB struct {
+	bdb        *bolt.DB
+	signingKey ed25519.PrivateKey
+}
+
+// Open opens the database at the provided path and creates it along with the
+// signing key of the VSP when it does not exist.
+func Open(path string) (*DB, error) {
+	bdb, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	var signingKey ed25519.PrivateKey
+	err = bdb.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{metaBucket, ticketsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		meta := tx.Bucket(metaBucket)
+		v := meta.Get(versionKey)
+		if v == nil {
+			var b [4]byte
+			binary.BigEndian.PutUint32(b[:], dbVersion)
+			if err := meta.Put(versionKey, b[:]); err != nil {
+				return err
+			}
+		} else if len(v) != 4 || binary.BigEndian.Uint32(v) > dbVersion {
+			str := fmt.Sprintf("vspd database version %x is newer than "+
+				"the supported version %d", v, dbVersion)
+			return makeError(ErrBadVersion, str)
+		}
+
+		// Generate the signing key on creation.
+		if seed := meta.Get(signingKeyKey); seed != nil {
+			signingKey = ed25519.NewKeyFromSeed(seed)
+			return nil
+		}
+		_, signingKey, err = ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		return meta.Put(signingKeyKey, signingKey.Seed())
+	})
+	if err != nil {
+		bdb.Close()
+		return nil, err
+	}
+	return &DB{bdb: bdb, signingKey: signingKey}, nil
+}
+
+// Close closes the database.
+func (db *DB) Close() error {
+	return db.bdb.Close()
+}
+
+// SigningKey returns the ed25519 key the VSP signs its responses with.
+func (db *DB) SigningKey() ed25519.PrivateKey {
+	return db.signingKey
+}
+
+// NextFeeAddressIndex returns the index of the next unused fee address and
+// marks it as used.
+func (db *DB) NextFeeAddressIndex() (uint32, error) {
+	var index uint32
+	err := db.bdb.Update(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		if v := meta.Get(feeAddrIndexKey); len(v) == 4 {
+			index = binary.BigEndian.Uint32(v)
+		}
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], index+1)
+		return meta.Put(feeAddrIndexKey, b[:])
+	})
+	if err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// putTicket stores the ticket in the tickets bucket.
+func putTicket(tx *bolt.Tx, ticket *Ticket) error {
+	v, err := json.Marshal(ticket)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(ticketsBucket).Put([]byte(ticket.Hash), v)
+}
+
+// InsertNewTicket registers a new ticket.  ErrTicketExists is returned when
+// the ticket is already registered.
+func (db *DB) InsertNewTicket(ticket *Ticket) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(ticketsBucket).Get([]byte(ticket.Hash)) != nil {
+			str := fmt.Sprintf("ticket %s is already registered",
+				ticket.Hash)
+			return makeError(ErrTicketExists, str)
+		}
+		return putTicket(tx, ticket)
+	})
+}
+
+// UpdateTicket replaces a registered ticket.  ErrTicketNotFound is returned
+// when the ticket is not registered.
+func (db *DB) UpdateTicket(ticket *Ticket) error {
+	return db.bdb.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(ticketsBucket).Get([]byte(ticket.Hash)) == nil {
+			str := fmt.Sprintf("ticket %s is not registered", ticket.Hash)
+			return makeError(ErrTicketNotFound, str)
+		}
+		return putTicket(tx, ticket)
+	})
+}
+
+// GetTicket returns the registered ticket with the provided hash.
+// ErrTicketNotFound is returned when the ticket is not registered.
+func (db *DB) GetTicket(hash string) (*Ticket, error) {
+	var ticket Ticket
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(ticketsBucket).Get([]byte(hash))
+		if v == nil {
+			str := fmt.Sprintf("ticket %s is not registered", hash)
+			return makeError(ErrTicketNotFound, str)
+		}
+		return json.Unmarshal(v, &ticket)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// FilterTickets returns all registered tickets for which the provided filter
+// returns true.
+func (db *DB) FilterTickets(filter func(*Ticket) bool) ([]*Ticket, error) {
+	var tickets []*Ticket
+	err := db.bdb.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(ticketsBucket).ForEach(func(_, v []byte) error {
+			var ticket Ticket
+			if err := json.Unmarshal(v, &ticket); err != nil {
+				return err
+			}
+			if filter(&ticket) {
+				tickets = append(tickets, &ticket)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// CountTickets returns the number of tickets with a confirmed fee by outcome.
+// Revoked tickets are counted as missed.
+func (db *DB) CountTickets() (*TicketCounts, error) {
+	var counts TicketCounts
+	_, err := db.FilterTickets(func(t *Ticket) bool {
+		if t.FeeTxStatus != FeeConfirmed {
+			return false
+		}
+		switch t.Outcome {
+		case "":
+			counts.Voting++
+		case Voted:
+			counts.Voted++
+		case Expired:
+			counts.Expired++
+		case Missed, Revoked:
+			counts.Missed++
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestDatabase ensures tickets can be registered, updated, and filtered, that
+// fee address indexes are not reused, and that the signing key persists.
+func TestDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vspd.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+
+	for want := uint32(0); want < 3; want++ {
+		index, err := db.NextFeeAddressIndex()
+		if err != nil {
+			t.Fatalf("unexpected error fetching fee address index: %v", err)
+		}
+		if index != want {
+			t.Fatalf("unexpected fee address index -- got %d, want %d",
+				index, want)
+		}
+	}
+
+	if _, err := db.GetTicket("t1"); !errors.Is(err, ErrTicketNotFound) {
+		t.Fatalf("unexpected error for unknown ticket -- got %v, want %v",
+			err, ErrTicketNotFound)
+	}
+	ticket := &Ticket{
+		Hash:        "t1",
+		FeeAddress:  "f1",
+		FeeTxStatus: NoFee,
+		VoteChoices: map[string]string{"agenda": "yes"},
+	}
+	if err := db.UpdateTicket(ticket); !errors.Is(err, ErrTicketNotFound) {
+		t.Fatalf("unexpected error updating unknown ticket -- got %v, "+
+			"want %v", err, ErrTicketNotFound)
+	}
+	if err := db.InsertNewTicket(ticket); err != nil {
+		t.Fatalf("unexpected error inserting ticket: %v", err)
+	}
+	if err := db.InsertNewTicket(ticket); !errors.Is(err, ErrTicketExists) {
+		t.Fatalf("unexpected error inserting ticket twice -- got %v, want %v",
+			err, ErrTicketExists)
+	}
+	got, err := db.GetTicket("t1")
+	if err != nil {
+		t.Fatalf("unexpected error fetching ticket: %v", err)
+	}
+	if !reflect.DeepEqual(got, ticket) {
+		t.Fatalf("unexpected ticket -- got %+v, want %+v", got, ticket)
+	}
+
+	// Tickets are only counted once their fee is confirmed.
+	others := []*Ticket{
+		{Hash: "t2", FeeTxStatus: FeeConfirmed},
+		{Hash: "t3", FeeTxStatus: FeeConfirmed, Outcome: Voted},
+		{Hash: "t4", FeeTxStatus: FeeConfirmed, Outcome: Revoked},
+		{Hash: "t5", FeeTxStatus: FeeBroadcast},
+	}
+	for _, other := range others {
+		if err := db.InsertNewTicket(other); err != nil {
+			t.Fatalf("unexpected error inserting ticket: %v", err)
+		}
+	}
+	counts, err := db.CountTickets()
+	if err != nil {
+		t.Fatalf("unexpected error counting tickets: %v", err)
+	}
+	wantCounts := &TicketCounts{Voting: 1, Voted: 1, Missed: 1}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Fatalf("unexpected counts -- got %+v, want %+v", counts, wantCounts)
+	}
+
+	ticket.FeeTxStatus = FeeBroadcast
+	if err := db.UpdateTicket(ticket); err != nil {
+		t.Fatalf("unexpected error updating ticket: %v", err)
+	}
+	broadcast, err := db.FilterTickets(func(t *Ticket) bool {
+		return t.FeeTxStatus == FeeBroadcast
+	})
+	if err != nil {
+		t.Fatalf("unexpected error filtering tickets: %v", err)
+	}
+	if len(broadcast) != 2 {
+		t.Fatalf("unexpected number of filtered tickets -- got %d, want 2",
+			len(broadcast))
+	}
+
+	// The signing key and fee address index must persist.
+	signingKey := db.SigningKey()
+	db.Close()
+	db, err = Open(path)
+	if err != nil {
+		t.Fatalf("unable to reopen database: %v", err)
+	}
+	defer db.Close()
+	if !bytes.Equal(db.SigningKey(), signingKey) {
+		t.Fatal("signing key changed after reopening the database")
+	}
+	index, err := db.NextFeeAddressIndex()
+	if err != nil {
+		t.Fatalf("unexpected error fetching fee address index: %v", err)
+	}
+	if index != 3 {
+		t.Fatalf("unexpected fee address index after reopening -- got %d, "+
+			"want 3", index)
+	}
+}
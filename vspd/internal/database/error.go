@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+// ErrorKind identifies a kind of error.  It has full support for errors.Is and
+// errors.As, so the caller can directly check against an error kind when
+// determining the reason for an error.
+type ErrorKind string
+
+// These constants are used to identify a specific Error.
+const (
+	// ErrTicketNotFound indicates a ticket is not registered in the
+	// database.
+	ErrTicketNotFound = ErrorKind("ErrTicketNotFound")
+
+	// ErrTicketExists indicates an attempt to register a ticket that is
+	// already registered in the database.
+	ErrTicketExists = ErrorKind("ErrTicketExists")
+
+	// ErrBadVersion indicates the database was created by a newer version of
+	// the software.
+	ErrBadVersion = ErrorKind("ErrBadVersion")
+)
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e ErrorKind) Error() string {
+	return string(e)
+}
+
+// Error identifies an error related to the VSP database.  It has full support
+// for errors.Is and errors.As, so the caller can ascertain the specific reason
+// for the error by checking the underlying error.
+type Error struct {
+	Description string
+	Err         error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e Error) Error() string {
+	return e.Description
+}
+
+// Unwrap returns the underlying wrapped error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// makeError creates an Error given a set of arguments.
+func makeError(kind ErrorKind, desc string) Error {
+	return Error{Err: kind, Description: desc}
+}
@@ -0,0 +1,349 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/vspd/internal/database"
+	pb "github.com/kdsmith18542/vigil/wallet/rpc/walletrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// importedAccount is the special account of the wallet which holds imported
+// private keys.  The voting keys of the tickets are imported into it.
+const importedAccount = 1<<31 - 1
+
+// WalletConfig describes the connection to a voting wallet.
+type WalletConfig struct {
+	// Host is the address of the gRPC server of the wallet.
+	Host string
+
+	// Cert is the PEM encoded TLS certificate of the gRPC server.
+	Cert []byte
+}
+
+// WalletStatus describes the state of a voting wallet.
+type WalletStatus struct {
+	Host            string
+	Connected       bool
+	BestBlockHeight int64
+	Error           string
+}
+
+// votingWallet is a gRPC connection to a single voting wallet.
+type votingWallet struct {
+	host   string
+	conn   *grpc.ClientConn
+	wallet pb.WalletServiceClient
+	voting pb.VotingServiceClient
+}
+
+// WalletPool is the pool of voting wallets which vote the tickets registered
+// with the VSP.  Every ticket with a confirmed fee is added to all of the
+// wallets so that the tickets are still voted when some of the wallets are
+// offline.
+//
+// The voting keys provided by the ticket holders are imported into the imported
+// account of each wallet.  Voting accounts derived from a seed with
+// ImportVotingAccountFromSeed can not be used since the VSP does not know the
+// seed of the ticket holder wallets.
+type WalletPool struct {
+	wallets    []*votingWallet
+	passphrase []byte
+}
+
+// NewWalletPool returns a pool of voting wallets connecting to the gRPC servers
+// described by the provided configs.  The client certificate is used to
+// authenticate with the wallets and the passphrase to unlock them when
+// importing voting keys.  Connections are established lazily so wallets that
+// are offline do not prevent the creation of the pool.
+func NewWalletPool(cfgs []WalletConfig, clientCert tls.Certificate, passphrase []byte) (*WalletPool, error) {
+	p := &WalletPool{passphrase: passphrase}
+	for _, cfg := range cfgs {
+		serverCAs := x509.NewCertPool()
+		if !serverCAs.AppendCertsFromPEM(cfg.Cert) {
+			p.Close()
+			return nil, fmt.Errorf("no certificates found for wallet %s",
+				cfg.Host)
+		}
+		creds := credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{clientCert},
+			RootCAs:      serverCAs,
+			MinVersion:   tls.VersionTLS12,
+		})
+		conn, err := grpc.NewClient(cfg.Host,
+			grpc.WithTransportCredentials(creds))
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("unable to create connection to wallet "+
+				"%s: %w", cfg.Host, err)
+		}
+		p.wallets = append(p.wallets, &votingWallet{
+			host:   cfg.Host,
+			conn:   conn,
+			wallet: pb.NewWalletServiceClient(conn),
+			voting: pb.NewVotingServiceClient(conn),
+		})
+	}
+	return p, nil
+}
+
+// Close closes the connections to all wallets of the pool.
+func (p *WalletPool) Close() {
+	for _, w := range p.wallets {
+		w.conn.Close()
+	}
+}
+
+// Status returns the state of every wallet of the pool.
+func (p *WalletPool) Status(ctx context.Context) []WalletStatus {
+	statuses := make([]WalletStatus, 0, len(p.wallets))
+	for _, w := range p.wallets {
+		s := WalletStatus{Host: w.host}
+		resp, err := w.wallet.BestBlock(ctx, &pb.BestBlockRequest{})
+		if err != nil {
+			s.Error = status.Convert(err).Message()
+		} else {
+			s.Connected = true
+			s.BestBlockHeight = int64(resp.Height)
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// hashBytes returns the bytes of the passed hex encoded hash in the order
+// expected by the wallet gRPC server.
+func hashBytes(s string) ([]byte, error) {
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		return nil, err
+	}
+	return hash[:], nil
+}
+
+// hasTicket returns whether the wallet knows the passed ticket.
+func (w *votingWallet) hasTicket(ctx context.Context, ticketHash []byte) (bool, error) {
+	_, err := w.wallet.GetTicket(ctx, &pb.GetTicketRequest{
+		TicketHash: ticketHash,
+	})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// rescan rescans the chain of the wallet from the passed height and waits for
+// the rescan to finish.
+func (w *votingWallet) rescan(ctx context.Context, height int32) error {
+	stream, err := w.wallet.Rescan(ctx, &pb.RescanRequest{
+		BeginHeight: height,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		_, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// setVoteChoices sets the voting preferences of the ticket in the wallet.
+func (w *votingWallet) setVoteChoices(ctx context.Context, ticket *database.Ticket) error {
+	ticketHash, err := hashBytes(ticket.Hash)
+	if err != nil {
+		return err
+	}
+
+	// Set the choices in a deterministic order to simplify debugging.
+	agendas := make([]string, 0, len(ticket.VoteChoices))
+	for agenda := range ticket.VoteChoices {
+		agendas = append(agendas, agenda)
+	}
+	sort.Strings(agendas)
+	choices := make([]*pb.SetVoteChoicesRequest_Choice, 0, len(agendas))
+	for _, agenda := range agendas {
+		choices = append(choices, &pb.SetVoteChoicesRequest_Choice{
+			AgendaId: agenda,
+			ChoiceId: ticket.VoteChoices[agenda],
+		})
+	}
+	if len(choices) > 0 {
+		_, err = w.voting.SetVoteChoices(ctx, &pb.SetVoteChoicesRequest{
+			Choices:    choices,
+			TicketHash: ticketHash,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set vote choices: %w", err)
+		}
+	}
+
+	for tspend, policy := range ticket.TSpendPolicy {
+		hash, err := hashBytes(tspend)
+		if err != nil {
+			return err
+		}
+		_, err = w.voting.SetTSpendPolicy(ctx, &pb.SetTSpendPolicyRequest{
+			Hash:       hash,
+			Policy:     policy,
+			TicketHash: ticketHash,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set tspend policy: %w", err)
+		}
+	}
+
+	for key, policy := range ticket.TreasuryPolicy {
+		pubKey, err := hex.DecodeString(key)
+		if err != nil {
+			return err
+		}
+		_, err = w.voting.SetTreasuryPolicy(ctx, &pb.SetTreasuryPolicyRequest{
+			Key:        pubKey,
+			Policy:     policy,
+			TicketHash: ticketHash,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set treasury policy: %w", err)
+		}
+	}
+	return nil
+}
+
+// syncTickets adds the tickets that are not yet known to the wallet and sets
+// the voting preferences of all tickets.
+func (w *votingWallet) syncTickets(ctx context.Context, tickets []*database.Ticket,
+	passphrase []byte) error {
+
+	// Import the voting keys of all tickets that are missing from the
+	// wallet.  The keys are imported without a rescan so only a single
+	// rescan is required from the height of the oldest missing ticket.
+	rescanFrom := int64(-1)
+	for _, ticket := range tickets {
+		ticketHash, err := hashBytes(ticket.Hash)
+		if err != nil {
+			return err
+		}
+		known, err := w.hasTicket(ctx, ticketHash)
+		if err != nil {
+			return fmt.Errorf("unable to get ticket %s: %w", ticket.Hash,
+				err)
+		}
+		if known {
+			continue
+		}
+		_, err = w.wallet.ImportPrivateKey(ctx, &pb.ImportPrivateKeyRequest{
+			Passphrase:    passphrase,
+			Account:       importedAccount,
+			PrivateKeyWif: ticket.VotingWIF,
+		})
+		if err != nil && status.Code(err) != codes.AlreadyExists {
+			return fmt.Errorf("unable to import voting key of ticket "+
+				"%s: %w", ticket.Hash, err)
+		}
+		if rescanFrom == -1 || ticket.PurchaseHeight < rescanFrom {
+			rescanFrom = ticket.PurchaseHeight
+		}
+	}
+	if rescanFrom != -1 {
+		log.Infof("Rescanning wallet %s from height %d", w.host, rescanFrom)
+		if err := w.rescan(ctx, int32(rescanFrom)); err != nil {
+			return fmt.Errorf("unable to rescan: %w", err)
+		}
+	}
+
+	for _, ticket := range tickets {
+		if err := w.setVoteChoices(ctx, ticket); err != nil {
+			return fmt.Errorf("ticket %s: %w", ticket.Hash, err)
+		}
+	}
+	return nil
+}
+
+// SyncTickets adds the passed tickets to all wallets of the pool which do not
+// know them yet and sets their voting preferences.  All wallets are attempted
+// and the error of the first failing wallet is returned.
+func (p *WalletPool) SyncTickets(ctx context.Context, tickets []*database.Ticket) error {
+	var firstErr error
+	for _, w := range p.wallets {
+		err := w.syncTickets(ctx, tickets, p.passphrase)
+		if err != nil {
+			log.Errorf("Unable to sync tickets with wallet %s: %v",
+				w.host, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("wallet %s: %w", w.host, err)
+			}
+		}
+	}
+	return firstErr
+}
+
+// SetVoteChoices updates the voting preferences of the passed ticket in all
+// wallets of the pool.  All wallets are attempted and the error of the first
+// failing wallet is returned.
+func (p *WalletPool) SetVoteChoices(ctx context.Context, ticket *database.Ticket) error {
+	var firstErr error
+	for _, w := range p.wallets {
+		err := w.setVoteChoices(ctx, ticket)
+		if err != nil {
+			log.Errorf("Unable to set vote choices of ticket %s with "+
+				"wallet %s: %v", ticket.Hash, w.host, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("wallet %s: %w", w.host, err)
+			}
+		}
+	}
+	return firstErr
+}
+
+// TicketOutcome returns the outcome of the passed ticket according to the
+// first wallet of the pool which knows it.  An empty outcome is returned when
+// the ticket may still vote or no wallet is able to tell.
+func (p *WalletPool) TicketOutcome(ctx context.Context, ticketHash string) (database.TicketOutcome, error) {
+	hash, err := hashBytes(ticketHash)
+	if err != nil {
+		return "", err
+	}
+	for _, w := range p.wallets {
+		resp, err := w.wallet.GetTicket(ctx, &pb.GetTicketRequest{
+			TicketHash: hash,
+		})
+		if err != nil {
+			continue
+		}
+		switch resp.GetTicket().GetTicketStatus() {
+		case pb.GetTicketsResponse_TicketDetails_VOTED:
+			return database.Voted, nil
+		case pb.GetTicketsResponse_TicketDetails_MISSED:
+			return database.Missed, nil
+		case pb.GetTicketsResponse_TicketDetails_EXPIRED:
+			return database.Expired, nil
+		case pb.GetTicketsResponse_TicketDetails_REVOKED:
+			return database.Revoked, nil
+		}
+		return "", nil
+	}
+	return "", nil
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kdsmith18542/vigil/vspd/internal/database"
+	"github.com/kdsmith18542/vigil/vspd/internal/rpc"
+)
+
+const (
+	// sessionCookie is the name of the cookie which authenticates the
+	// admin session.
+	sessionCookie = "vspd-admin"
+
+	// sessionDuration is how long an admin session lasts.
+	sessionDuration = time.Hour * 12
+)
+
+// adminPage is the data of the admin page.
+type adminPage struct {
+	Network       string
+	FeePercentage float64
+	VspClosed     bool
+	PubKey        string
+	Counts        database.TicketCounts
+	BlockHeight   uint32
+	StatsUpdated  time.Time
+	Wallets       []rpc.WalletStatus
+	Search        string
+	Ticket        *database.Ticket
+	SearchError   string
+}
+
+// sessionMAC returns the authentication code of a session which expires at
+// the provided unix time.
+func (s *Server) sessionMAC(expiry string) string {
+	mac := hmac.New(sha256.New, s.cookieKey)
+	mac.Write([]byte(expiry))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// authenticated returns whether the provided request belongs to an admin
+// session that has not expired.
+func (s *Server) authenticated(r *http.Request) bool {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return false
+	}
+	expiry, mac, ok := strings.Cut(cookie.Value, ".")
+	if !ok || !hmac.Equal([]byte(mac), []byte(s.sessionMAC(expiry))) {
+		return false
+	}
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	return err == nil && time.Now().Unix() < unix
+}
+
+// render renders the named template with the provided data.
+func (s *Server) render(w http.ResponseWriter, code int, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if err := s.tmpl.ExecuteTemplate(w, name, data); err != nil {
+		log.Errorf("Unable to render %s: %v", name, err)
+	}
+}
+
+// handleAdmin serves the admin page with the status of the VSP and the voting
+// wallets.  A registered ticket can be looked up with the ticket query
+// parameter.  The login page is served when the request is not authenticated.
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	if !s.authenticated(r) {
+		s.render(w, http.StatusOK, "login.html", nil)
+		return
+	}
+
+	s.statsMtx.RLock()
+	stats := s.stats
+	s.statsMtx.RUnlock()
+	page := &adminPage{
+		Network:       s.cfg.Params.Name,
+		FeePercentage: s.cfg.FeePercentage,
+		VspClosed:     s.cfg.VspClosed,
+		PubKey:        hex.EncodeToString(s.PubKey()),
+		Counts:        stats.counts,
+		BlockHeight:   stats.blockHeight,
+		StatsUpdated:  stats.updated,
+		Wallets:       s.wallets.Status(r.Context()),
+		Search:        strings.TrimSpace(r.URL.Query().Get("ticket")),
+	}
+	if page.Search != "" {
+		ticket, err := s.db.GetTicket(page.Search)
+		switch {
+		case errors.Is(err, database.ErrTicketNotFound):
+			page.SearchError = "ticket is not registered"
+		case err != nil:
+			log.Errorf("Unable to get ticket %s: %v", page.Search, err)
+			page.SearchError = "internal error"
+		default:
+			page.Ticket = ticket
+		}
+	}
+	s.render(w, http.StatusOK, "admin.html", page)
+}
+
+// handleAdminLogin starts an admin session when the correct password is
+// provided.
+func (s *Server) handleAdminLogin(w http.ResponseWriter, r *http.Request) {
+	pass := r.PostFormValue("password")
+	if subtle.ConstantTimeCompare([]byte(pass), []byte(s.cfg.AdminPass)) != 1 {
+		log.Warnf("Failed admin login from %s", r.RemoteAddr)
+		s.render(w, http.StatusUnauthorized, "login.html", "Incorrect password")
+		return
+	}
+	expiry := strconv.FormatInt(time.Now().Add(sessionDuration).Unix(), 10)
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    expiry + "." + s.sessionMAC(expiry),
+		Path:     "/admin",
+		MaxAge:   int(sessionDuration / time.Second),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+// handleAdminLogout ends the admin session.
+func (s *Server) handleAdminLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Path:     "/admin",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
@@ -0,0 +1,709 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdscript"
+	"github.com/kdsmith18542/vigil/vspd/internal/database"
+	"github.com/kdsmith18542/vigil/vspd/types/v3"
+	"github.com/kdsmith18542/vigil/wallet/wallet/txrules"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+const (
+	// apiVersion is the version of the API served by vspd.
+	apiVersion = 3
+
+	// maxRequestSize is the maximum size of a request body.  It is large
+	// enough for a fee transaction with many inputs.
+	maxRequestSize = 1 << 20
+
+	// feeValidity is how long the fee amount and address returned by the
+	// feeaddress endpoint are valid.
+	feeValidity = time.Hour
+)
+
+// apiError is an error which is returned to the client with the provided
+// code.  The default message of the code is used when no message is provided.
+type apiError struct {
+	code types.ErrorCode
+	msg  string
+}
+
+func (e *apiError) Error() string {
+	if e.msg == "" {
+		return e.code.DefaultMessage()
+	}
+	return e.msg
+}
+
+// newAPIError returns an apiError with the provided code and an optional
+// formatted message.
+func newAPIError(code types.ErrorCode, format string, args ...interface{}) *apiError {
+	var msg string
+	if format != "" {
+		msg = fmt.Sprintf(format, args...)
+	}
+	return &apiError{code: code, msg: msg}
+}
+
+// writeError writes the response for the provided error.  Errors which are not
+// an apiError are logged and reported as internal errors.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		log.Errorf("%s: %v", r.URL.Path, err)
+		apiErr = newAPIError(types.ErrInternalError, "")
+	} else {
+		log.Debugf("%s from %s: %v", r.URL.Path, r.RemoteAddr, err)
+	}
+	resp := types.ErrorResponse{
+		Code:    apiErr.code,
+		Message: apiErr.Error(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.code.HTTPStatus())
+	if err := json.NewEncoder(w).Encode(&resp); err != nil {
+		log.Debugf("Unable to write response: %v", err)
+	}
+}
+
+// writeSigned writes the provided value as a JSON response which is signed
+// with the key of the VSP.
+func (s *Server) writeSigned(w http.ResponseWriter, r *http.Request, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	sig := ed25519.Sign(s.signKey, body)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(types.ServerSignatureHeader,
+		base64.StdEncoding.EncodeToString(sig))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.Debugf("Unable to write response: %v", err)
+	}
+}
+
+// readRequest reads the body of the provided request and unmarshals it into
+// req.  The raw body is returned since it is covered by the signature of the
+// client and echoed in the response.
+func readRequest(r *http.Request, req interface{}) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize+1))
+	if err != nil {
+		return nil, newAPIError(types.ErrBadRequest, "unable to read "+
+			"request: %v", err)
+	}
+	if len(body) > maxRequestSize {
+		return nil, newAPIError(types.ErrBadRequest, "request too large")
+	}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newAPIError(types.ErrBadRequest, "invalid request: %v",
+			err)
+	}
+	return body, nil
+}
+
+// verifySignature ensures the request body was signed by the provided
+// commitment address of a ticket.
+func (s *Server) verifySignature(ctx context.Context, r *http.Request, body []byte,
+	commitmentAddr string) error {
+
+	sig := r.Header.Get(types.ClientSignatureHeader)
+	if sig == "" {
+		return newAPIError(types.ErrBadSignature, "no request signature")
+	}
+	addr, err := stdaddr.DecodeAddress(commitmentAddr, s.cfg.Params)
+	if err != nil {
+		return err
+	}
+	valid, err := s.node.VerifyMessage(ctx, addr, sig, string(body))
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return newAPIError(types.ErrBadSignature, "")
+	}
+	return nil
+}
+
+// parseHash parses the provided hex encoded hash of a request.
+func parseHash(s string) (*chainhash.Hash, error) {
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		return nil, newAPIError(types.ErrBadRequest, "invalid hash %q", s)
+	}
+	return hash, nil
+}
+
+// decodeTx decodes the provided hex encoded transaction.
+func decodeTx(s string) (*wire.MsgTx, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// registeredTicket returns the registered ticket with the provided hash after
+// ensuring the request was signed by its commitment address.
+func (s *Server) registeredTicket(r *http.Request, body []byte, ticketHash string) (*database.Ticket, error) {
+	if _, err := parseHash(ticketHash); err != nil {
+		return nil, err
+	}
+	ticket, err := s.db.GetTicket(ticketHash)
+	if errors.Is(err, database.ErrTicketNotFound) {
+		return nil, newAPIError(types.ErrUnknownTicket, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.verifySignature(r.Context(), r, body, ticket.CommitmentAddress)
+	if err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// canVote returns an error when the provided ticket is mature but no longer
+// live and therefore can not vote anymore.  Tickets that are unknown to the
+// node or not yet mature are assumed to be able to vote.
+func (s *Server) canVote(ctx context.Context, hash *chainhash.Hash) error {
+	tx, err := s.node.Transaction(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if tx == nil || tx.Confirmations <= int64(s.cfg.Params.TicketMaturity) {
+		return nil
+	}
+	live, err := s.node.ExistsLiveTicket(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if !live {
+		return newAPIError(types.ErrTicketCannotVote, "")
+	}
+	return nil
+}
+
+// currentVoteVersion returns the deployments of the most recent stake version
+// of the network.
+func currentVoteVersion(params *chaincfg.Params) []chaincfg.ConsensusDeployment {
+	var latest uint32
+	for version := range params.Deployments {
+		if version > latest {
+			latest = version
+		}
+	}
+	return params.Deployments[latest]
+}
+
+// validPolicy returns whether the provided treasury or tspend policy is valid.
+func validPolicy(policy string) bool {
+	switch policy {
+	case "yes", "no", "abstain":
+		return true
+	}
+	return false
+}
+
+// validateVoteChoices ensures the provided vote choices refer to agendas of the
+// current stake version and the provided policies are valid.
+func validateVoteChoices(params *chaincfg.Params, choices, tspendPolicy,
+	treasuryPolicy map[string]string) error {
+
+	deployments := currentVoteVersion(params)
+	for agenda, choice := range choices {
+		var valid bool
+		for _, d := range deployments {
+			if d.Vote.Id != agenda {
+				continue
+			}
+			for _, c := range d.Vote.Choices {
+				if c.Id == choice {
+					valid = true
+					break
+				}
+			}
+			break
+		}
+		if !valid {
+			return newAPIError(types.ErrInvalidVoteChoices, "invalid "+
+				"choice %q for agenda %q", choice, agenda)
+		}
+	}
+	for tspend, policy := range tspendPolicy {
+		if _, err := chainhash.NewHashFromStr(tspend); err != nil ||
+			!validPolicy(policy) {
+
+			return newAPIError(types.ErrInvalidVoteChoices, "invalid "+
+				"policy %q for tspend %q", policy, tspend)
+		}
+	}
+	for key, policy := range treasuryPolicy {
+		pubKey, err := hex.DecodeString(key)
+		if err != nil || len(pubKey) != 33 || !validPolicy(policy) {
+			return newAPIError(types.ErrInvalidVoteChoices, "invalid "+
+				"policy %q for treasury key %q", policy, key)
+		}
+	}
+	return nil
+}
+
+// mergeChoices returns a copy of current updated with the entries of update.
+func mergeChoices(current, update map[string]string) map[string]string {
+	merged := make(map[string]string, len(current)+len(update))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range update {
+		merged[k] = v
+	}
+	return merged
+}
+
+// feeAmount returns the fee the VSP charges for voting the provided ticket.
+func (s *Server) feeAmount(ctx context.Context, ticket *wire.MsgTx) (VGLutil.Amount, error) {
+	header, err := s.node.BestBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	vglp0010, err := s.node.AgendaActive(ctx,
+		chaincfg.VoteIDChangeSubsidySplit)
+	if err != nil {
+		return 0, err
+	}
+	vglp0012, err := s.node.AgendaActive(ctx,
+		chaincfg.VoteIDChangeSubsidySplitR2)
+	if err != nil {
+		return 0, err
+	}
+	ticketPrice := VGLutil.Amount(ticket.TxOut[0].Value)
+	return txrules.StakePoolTicketFee(ticketPrice,
+		txrules.DefaultRelayFeePerKb, int32(header.Height),
+		s.cfg.FeePercentage, s.cfg.Params, vglp0010, vglp0012), nil
+}
+
+// handleVspInfo serves the information and statistics of the VSP.
+func (s *Server) handleVspInfo(w http.ResponseWriter, r *http.Request) {
+	s.statsMtx.RLock()
+	stats := s.stats
+	s.statsMtx.RUnlock()
+	s.writeSigned(w, r, &types.VspInfoResponse{
+		APIVersions:         []int64{apiVersion},
+		Timestamp:           time.Now().Unix(),
+		PubKey:              s.PubKey(),
+		FeePercentage:       s.cfg.FeePercentage,
+		VspClosed:           s.cfg.VspClosed,
+		VspClosedMsg:        s.cfg.VspClosedMsg,
+		Network:             s.cfg.Params.Name,
+		VspdVersion:         s.cfg.Version,
+		Voting:              stats.counts.Voting,
+		Voted:               stats.counts.Voted,
+		TotalVotingWallets:  stats.totalWallets,
+		VotingWalletsOnline: stats.walletsOnline,
+		Expired:             stats.counts.Expired,
+		Missed:              stats.counts.Missed,
+		BlockHeight:         stats.blockHeight,
+		NetworkProportion:   stats.networkFraction,
+	})
+}
+
+// ensureTicketBroadcast broadcasts the provided ticket along with its parent
+// transaction when the node does not know it yet.
+func (s *Server) ensureTicketBroadcast(ctx context.Context, ticket *wire.MsgTx,
+	parentHex string) error {
+
+	ticketHash := ticket.TxHash()
+	tx, err := s.node.Transaction(ctx, &ticketHash)
+	if err != nil || tx != nil {
+		return err
+	}
+
+	parent, err := decodeTx(parentHex)
+	if err != nil {
+		return newAPIError(types.ErrBadRequest, "invalid parent tx: %v", err)
+	}
+	parentHash := parent.TxHash()
+	var spendsParent bool
+	for _, in := range ticket.TxIn {
+		if in.PreviousOutPoint.Hash == parentHash {
+			spendsParent = true
+			break
+		}
+	}
+	if !spendsParent {
+		return newAPIError(types.ErrBadRequest, "ticket does not spend "+
+			"the provided parent tx")
+	}
+	if err := s.node.SendRawTransaction(ctx, parent); err != nil {
+		return newAPIError(types.ErrCannotBroadcastTicket, "%v", err)
+	}
+	if err := s.node.SendRawTransaction(ctx, ticket); err != nil {
+		return newAPIError(types.ErrCannotBroadcastTicket, "%v", err)
+	}
+	return nil
+}
+
+// feeAddressResponse registers the ticket of the provided request and returns
+// the fee address and amount.
+func (s *Server) feeAddressResponse(r *http.Request, body []byte,
+	req *types.FeeAddressRequest) (*types.FeeAddressResponse, error) {
+
+	ctx := r.Context()
+	if s.cfg.VspClosed {
+		return nil, newAPIError(types.ErrVspClosed, "%s", s.cfg.VspClosedMsg)
+	}
+	ticketHash, err := parseHash(req.TicketHash)
+	if err != nil {
+		return nil, err
+	}
+	ticketTx, err := decodeTx(req.TicketHex)
+	if err != nil {
+		return nil, newAPIError(types.ErrInvalidTicket, "unable to decode "+
+			"ticket: %v", err)
+	}
+	if ticketTx.TxHash() != *ticketHash || !stake.IsSStx(ticketTx) {
+		return nil, newAPIError(types.ErrInvalidTicket, "")
+	}
+	commitmentAddr, err := stake.AddrFromSStxPkScrCommitment(
+		ticketTx.TxOut[1].PkScript, s.cfg.Params)
+	if err != nil {
+		return nil, newAPIError(types.ErrInvalidTicket, "invalid "+
+			"commitment: %v", err)
+	}
+	err = s.verifySignature(ctx, r, body, commitmentAddr.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Only tickets which vote with a P2PKH address can be voted since the
+	// voting key is imported into the voting wallets.
+	pkHash := stdscript.ExtractStakeSubmissionPubKeyHashV0(
+		ticketTx.TxOut[0].PkScript)
+	if pkHash == nil {
+		return nil, newAPIError(types.ErrInvalidTicket, "voting address "+
+			"must be a P2PKH address")
+	}
+	votingAddr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash,
+		s.cfg.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ticketMtx.Lock()
+	defer s.ticketMtx.Unlock()
+
+	ticket, err := s.db.GetTicket(req.TicketHash)
+	switch {
+	case errors.Is(err, database.ErrTicketNotFound):
+		ticket = nil
+	case err != nil:
+		return nil, err
+	case ticket.FeeTxStatus == database.FeeBroadcast,
+		ticket.FeeTxStatus == database.FeeConfirmed:
+		return nil, newAPIError(types.ErrFeeAlreadyReceived, "")
+	case !ticket.FeeExpired():
+		// Return the current fee while it is still valid.
+		return &types.FeeAddressResponse{
+			Timestamp:  time.Now().Unix(),
+			FeeAddress: ticket.FeeAddress,
+			FeeAmount:  ticket.FeeAmount,
+			Expiration: ticket.FeeExpiration,
+			Request:    body,
+		}, nil
+	}
+
+	if err := s.ensureTicketBroadcast(ctx, ticketTx, req.ParentHex); err != nil {
+		return nil, err
+	}
+	if err := s.canVote(ctx, ticketHash); err != nil {
+		return nil, err
+	}
+	fee, err := s.feeAmount(ctx, ticketTx)
+	if err != nil {
+		return nil, err
+	}
+	expiration := time.Now().Add(feeValidity).Unix()
+
+	if ticket != nil {
+		// Renew the expired fee while keeping the fee address.
+		ticket.FeeAmount = int64(fee)
+		ticket.FeeExpiration = expiration
+		if err := s.db.UpdateTicket(ticket); err != nil {
+			return nil, err
+		}
+	} else {
+		index, feeAddr, err := s.feeAddress()
+		if err != nil {
+			return nil, err
+		}
+		ticket = &database.Ticket{
+			Hash:              req.TicketHash,
+			CommitmentAddress: commitmentAddr.String(),
+			VotingAddress:     votingAddr.String(),
+			FeeAddressIndex:   index,
+			FeeAddress:        feeAddr.String(),
+			FeeAmount:         int64(fee),
+			FeeExpiration:     expiration,
+			FeeTxStatus:       database.NoFee,
+		}
+		if err := s.db.InsertNewTicket(ticket); err != nil {
+			return nil, err
+		}
+		log.Infof("Registered ticket %s (fee %v to %s)", ticket.Hash, fee,
+			ticket.FeeAddress)
+	}
+
+	return &types.FeeAddressResponse{
+		Timestamp:  time.Now().Unix(),
+		FeeAddress: ticket.FeeAddress,
+		FeeAmount:  ticket.FeeAmount,
+		Expiration: ticket.FeeExpiration,
+		Request:    body,
+	}, nil
+}
+
+// handleFeeAddress serves the fee address and amount for a ticket.
+func (s *Server) handleFeeAddress(w http.ResponseWriter, r *http.Request) {
+	var req types.FeeAddressRequest
+	body, err := readRequest(r, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resp, err := s.feeAddressResponse(r, body, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	s.writeSigned(w, r, resp)
+}
+
+// payFee validates and broadcasts the fee transaction of the provided request
+// and stores the voting key and preferences of the ticket.
+func (s *Server) payFee(r *http.Request, body []byte, req *types.PayFeeRequest) error {
+	ctx := r.Context()
+	if s.cfg.VspClosed {
+		return newAPIError(types.ErrVspClosed, "%s", s.cfg.VspClosedMsg)
+	}
+
+	s.ticketMtx.Lock()
+	defer s.ticketMtx.Unlock()
+
+	ticket, err := s.registeredTicket(r, body, req.TicketHash)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ticket.FeeTxStatus == database.FeeBroadcast,
+		ticket.FeeTxStatus == database.FeeConfirmed:
+		return newAPIError(types.ErrFeeAlreadyReceived, "")
+	case ticket.FeeExpired():
+		return newAPIError(types.ErrFeeExpired, "")
+	}
+
+	// The voting key must belong to the voting address of the ticket.
+	wif, err := VGLutil.DecodeWIF(req.VotingKey, s.cfg.Params.PrivateKeyID)
+	if err != nil {
+		return newAPIError(types.ErrInvalidPrivKey, "")
+	}
+	votingAddr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(
+		stdaddr.Hash160(wif.PubKey()), s.cfg.Params)
+	if err != nil || votingAddr.String() != ticket.VotingAddress {
+		return newAPIError(types.ErrInvalidPrivKey, "voting key does not "+
+			"match the voting address of the ticket")
+	}
+
+	err = validateVoteChoices(s.cfg.Params, req.VoteChoices,
+		req.TSpendPolicy, req.TreasuryPolicy)
+	if err != nil {
+		return err
+	}
+
+	// Sum the outputs of the fee transaction which pay the fee address.
+	feeTx, err := decodeTx(req.FeeTx)
+	if err != nil {
+		return newAPIError(types.ErrInvalidFeeTx, "unable to decode fee "+
+			"tx: %v", err)
+	}
+	feeAddr, err := stdaddr.DecodeAddress(ticket.FeeAddress, s.cfg.Params)
+	if err != nil {
+		return err
+	}
+	scriptVersion, feeScript := feeAddr.PaymentScript()
+	var paid int64
+	for _, out := range feeTx.TxOut {
+		if out.Version == scriptVersion &&
+			bytes.Equal(out.PkScript, feeScript) {
+
+			paid += out.Value
+		}
+	}
+	if paid == 0 {
+		return newAPIError(types.ErrInvalidFeeTx, "fee tx does not pay "+
+			"the fee address")
+	}
+	if paid < ticket.FeeAmount {
+		return newAPIError(types.ErrFeeTooSmall, "fee too small: got %v, "+
+			"want %v", VGLutil.Amount(paid), VGLutil.Amount(ticket.FeeAmount))
+	}
+
+	ticketHash, err := parseHash(ticket.Hash)
+	if err != nil {
+		return err
+	}
+	if err := s.canVote(ctx, ticketHash); err != nil {
+		return err
+	}
+	if err := s.node.SendRawTransaction(ctx, feeTx); err != nil {
+		return newAPIError(types.ErrCannotBroadcastFee, "%v", err)
+	}
+
+	ticket.FeeTxHex = req.FeeTx
+	ticket.FeeTxHash = feeTx.TxHash().String()
+	ticket.FeeTxStatus = database.FeeBroadcast
+	ticket.VotingWIF = req.VotingKey
+	ticket.VoteChoices = req.VoteChoices
+	ticket.TSpendPolicy = req.TSpendPolicy
+	ticket.TreasuryPolicy = req.TreasuryPolicy
+	ticket.VoteChangeTime = req.Timestamp
+	if err := s.db.UpdateTicket(ticket); err != nil {
+		return err
+	}
+	log.Infof("Broadcast fee tx %s for ticket %s", ticket.FeeTxHash,
+		ticket.Hash)
+	return nil
+}
+
+// handlePayFee accepts the fee transaction of a ticket.
+func (s *Server) handlePayFee(w http.ResponseWriter, r *http.Request) {
+	var req types.PayFeeRequest
+	body, err := readRequest(r, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := s.payFee(r, body, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	s.writeSigned(w, r, &types.PayFeeResponse{
+		Timestamp: time.Now().Unix(),
+		Request:   body,
+	})
+}
+
+// handleTicketStatus serves the status of a registered ticket.
+func (s *Server) handleTicketStatus(w http.ResponseWriter, r *http.Request) {
+	var req types.TicketStatusRequest
+	body, err := readRequest(r, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	ticket, err := s.registeredTicket(r, body, req.TicketHash)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	s.writeSigned(w, r, &types.TicketStatusResponse{
+		Timestamp:       time.Now().Unix(),
+		TicketConfirmed: ticket.Confirmed,
+		FeeTxStatus:     string(ticket.FeeTxStatus),
+		FeeTxHash:       ticket.FeeTxHash,
+		VoteChoices:     ticket.VoteChoices,
+		TSpendPolicy:    ticket.TSpendPolicy,
+		TreasuryPolicy:  ticket.TreasuryPolicy,
+		Request:         body,
+	})
+}
+
+// setVoteChoices updates the voting preferences of the ticket of the provided
+// request.
+func (s *Server) setVoteChoices(r *http.Request, body []byte, req *types.SetVoteChoicesRequest) error {
+	s.ticketMtx.Lock()
+	defer s.ticketMtx.Unlock()
+
+	ticket, err := s.registeredTicket(r, body, req.TicketHash)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ticket.FeeTxStatus != database.FeeBroadcast &&
+		ticket.FeeTxStatus != database.FeeConfirmed:
+		return newAPIError(types.ErrFeeNotReceived, "")
+	case ticket.Outcome != "":
+		return newAPIError(types.ErrTicketCannotVote, "")
+	case req.Timestamp <= ticket.VoteChangeTime:
+		return newAPIError(types.ErrInvalidTimestamp, "")
+	}
+	err = validateVoteChoices(s.cfg.Params, req.VoteChoices,
+		req.TSpendPolicy, req.TreasuryPolicy)
+	if err != nil {
+		return err
+	}
+
+	ticket.VoteChoices = mergeChoices(ticket.VoteChoices, req.VoteChoices)
+	ticket.TSpendPolicy = mergeChoices(ticket.TSpendPolicy, req.TSpendPolicy)
+	ticket.TreasuryPolicy = mergeChoices(ticket.TreasuryPolicy,
+		req.TreasuryPolicy)
+	ticket.VoteChangeTime = req.Timestamp
+	if err := s.db.UpdateTicket(ticket); err != nil {
+		return err
+	}
+
+	// Tickets are only added to the voting wallets once the fee is
+	// confirmed and take the stored preferences with them.  Wallets which
+	// fail to update are brought in line on the next sync.
+	if ticket.FeeTxStatus == database.FeeConfirmed {
+		err := s.wallets.SetVoteChoices(r.Context(), ticket)
+		if err != nil {
+			log.Warnf("Unable to update vote choices of ticket %s: %v",
+				ticket.Hash, err)
+		}
+	}
+	return nil
+}
+
+// handleSetVoteChoices updates the voting preferences of a registered ticket.
+func (s *Server) handleSetVoteChoices(w http.ResponseWriter, r *http.Request) {
+	var req types.SetVoteChoicesRequest
+	body, err := readRequest(r, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := s.setVoteChoices(r, body, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	s.writeSigned(w, r, &types.SetVoteChoicesResponse{
+		Timestamp: time.Now().Unix(),
+		Request:   body,
+	})
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package webapi implements the HTTP API of vspd which is used by the VSP
+// client of the wallet along with the admin page of the VSP.
+package webapi
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/hdkeychain/v3"
+	chainjson "github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4"
+	"github.com/kdsmith18542/vigil/slog"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/vspd/internal/database"
+	"github.com/kdsmith18542/vigil/vspd/internal/rpc"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// log is a logger that is initialized with no output filters.  This means the
+// package will not perform any logging by default until the caller requests
+// it.
+var log = slog.Disabled
+
+// UseLogger uses a specified Logger to output package logging info.
+func UseLogger(logger slog.Logger) {
+	log = logger
+}
+
+// templateFS houses the HTML templates of the admin page.
+//
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Node defines the node RPC methods used by the API.
+type Node interface {
+	BestBlock(ctx context.Context) (*wire.BlockHeader, error)
+	Transaction(ctx context.Context, hash *chainhash.Hash) (*chainjson.TxRawResult, error)
+	SendRawTransaction(ctx context.Context, tx *wire.MsgTx) error
+	ExistsLiveTicket(ctx context.Context, hash *chainhash.Hash) (bool, error)
+	VerifyMessage(ctx context.Context, addr stdaddr.Address, signature, message string) (bool, error)
+	AgendaActive(ctx context.Context, voteID string) (bool, error)
+}
+
+// WalletPool defines the voting wallet methods used by the API.
+type WalletPool interface {
+	Status(ctx context.Context) []rpc.WalletStatus
+	SetVoteChoices(ctx context.Context, ticket *database.Ticket) error
+}
+
+// Config houses the configuration of the API.
+type Config struct {
+	// Params are the parameters of the network the VSP votes on.
+	Params *chaincfg.Params
+
+	// FeeXPub is the extended public key of the wallet account which
+	// receives the fees.  Fee addresses are derived from its external
+	// branch.
+	FeeXPub string
+
+	// FeePercentage is the percentage of the vote reward the VSP charges as
+	// its fee.
+	FeePercentage float64
+
+	// VspClosed prevents the registration of new tickets while the already
+	// registered tickets are still voted.
+	VspClosed    bool
+	VspClosedMsg string
+
+	// AdminPass is the password of the admin page.  The admin page is
+	// disabled when it is empty.
+	AdminPass string
+
+	// Version is the version of vspd reported by the vspinfo endpoint.
+	Version string
+}
+
+// vspStats houses the statistics reported by the vspinfo endpoint.  They are
+// updated whenever a block is connected since they are expensive to compute.
+type vspStats struct {
+	counts          database.TicketCounts
+	totalWallets    int64
+	walletsOnline   int64
+	blockHeight     uint32
+	networkFraction float32
+	updated         time.Time
+}
+
+// Server serves the API and the admin page of the VSP.
+type Server struct {
+	cfg       Config
+	db        *database.DB
+	node      Node
+	wallets   WalletPool
+	signKey   ed25519.PrivateKey
+	feeBranch *hdkeychain.ExtendedKey
+	tmpl      *template.Template
+
+	// cookieKey authenticates the session cookies of the admin page.  It
+	// is generated on startup so all sessions end on restart.
+	cookieKey []byte
+
+	// ticketMtx serializes the requests which modify tickets so concurrent
+	// requests for the same ticket can not overwrite each other.
+	ticketMtx sync.Mutex
+
+	statsMtx sync.RWMutex
+	stats    vspStats
+}
+
+// New returns a new server for the API and admin page.
+func New(cfg Config, db *database.DB, node Node, wallets WalletPool) (*Server, error) {
+	xpub, err := hdkeychain.NewKeyFromString(cfg.FeeXPub, cfg.Params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee xpub: %w", err)
+	}
+	if xpub.IsPrivate() {
+		return nil, errors.New("fee xpub must be an extended public key")
+	}
+	feeBranch, err := xpub.Child(0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive fee branch: %w", err)
+	}
+	tmpl, err := template.New("").Funcs(template.FuncMap{
+		"time": func(unix int64) string {
+			return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+		},
+	}).ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse templates: %w", err)
+	}
+	cookieKey := make([]byte, 32)
+	if _, err := rand.Read(cookieKey); err != nil {
+		return nil, err
+	}
+	return &Server{
+		cfg:       cfg,
+		db:        db,
+		node:      node,
+		wallets:   wallets,
+		signKey:   db.SigningKey(),
+		feeBranch: feeBranch,
+		tmpl:      tmpl,
+		cookieKey: cookieKey,
+	}, nil
+}
+
+// PubKey returns the public key the VSP signs its responses with.
+func (s *Server) PubKey() ed25519.PublicKey {
+	return s.signKey.Public().(ed25519.PublicKey)
+}
+
+// UpdateStats recomputes the statistics reported by the vspinfo endpoint.
+func (s *Server) UpdateStats(ctx context.Context) error {
+	counts, err := s.db.CountTickets()
+	if err != nil {
+		return err
+	}
+	header, err := s.node.BestBlock(ctx)
+	if err != nil {
+		return err
+	}
+	statuses := s.wallets.Status(ctx)
+	var online int64
+	for _, status := range statuses {
+		if status.Connected {
+			online++
+		}
+	}
+
+	stats := vspStats{
+		counts:        *counts,
+		totalWallets:  int64(len(statuses)),
+		walletsOnline: online,
+		blockHeight:   header.Height,
+		updated:       time.Now(),
+	}
+	if header.PoolSize > 0 {
+		stats.networkFraction = float32(counts.Voting) /
+			float32(header.PoolSize)
+	}
+	s.statsMtx.Lock()
+	s.stats = stats
+	s.statsMtx.Unlock()
+	return nil
+}
+
+// feeAddress derives the next unused fee address.
+func (s *Server) feeAddress() (uint32, stdaddr.Address, error) {
+	for {
+		index, err := s.db.NextFeeAddressIndex()
+		if err != nil {
+			return 0, nil, err
+		}
+		key, err := s.feeBranch.Child(index)
+		if errors.Is(err, hdkeychain.ErrInvalidChild) {
+			continue
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		pkHash := stdaddr.Hash160(key.SerializedPubKey())
+		addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash,
+			s.cfg.Params)
+		if err != nil {
+			return 0, nil, err
+		}
+		return index, addr, nil
+	}
+}
+
+// Handler returns the HTTP handler which serves the API and the admin page.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/vspinfo", s.handleVspInfo)
+	mux.HandleFunc("POST /api/v3/feeaddress", s.handleFeeAddress)
+	mux.HandleFunc("POST /api/v3/payfee", s.handlePayFee)
+	mux.HandleFunc("POST /api/v3/ticketstatus", s.handleTicketStatus)
+	mux.HandleFunc("POST /api/v3/setvotechoices", s.handleSetVoteChoices)
+	if s.cfg.AdminPass != "" {
+		mux.HandleFunc("GET /admin", s.handleAdmin)
+		mux.HandleFunc("POST /admin/login", s.handleAdminLogin)
+		mux.HandleFunc("POST /admin/logout", s.handleAdminLogout)
+	}
+	return mux
+}
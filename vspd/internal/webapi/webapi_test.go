@@ -0,0 +1,432 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kdsmith18542/vigil/VGLec"
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/hdkeychain/v3"
+	chainjson "github.com/kdsmith18542/vigil/rpc/jsonrpc/types/v4"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/vspd/client/v4"
+	"github.com/kdsmith18542/vigil/vspd/internal/database"
+	"github.com/kdsmith18542/vigil/vspd/internal/rpc"
+	"github.com/kdsmith18542/vigil/vspd/types/v3"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// fakeNode implements the Node interface for the tests.  Signatures are the
+// address followed by the signed message.
+type fakeNode struct {
+	mtx  sync.Mutex
+	txs  map[chainhash.Hash]*chainjson.TxRawResult
+	sent []*wire.MsgTx
+}
+
+func (n *fakeNode) BestBlock(context.Context) (*wire.BlockHeader, error) {
+	return &wire.BlockHeader{Height: 1000, PoolSize: 100}, nil
+}
+
+func (n *fakeNode) Transaction(_ context.Context, hash *chainhash.Hash) (*chainjson.TxRawResult, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.txs[*hash], nil
+}
+
+func (n *fakeNode) SendRawTransaction(_ context.Context, tx *wire.MsgTx) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	hash := tx.TxHash()
+	n.txs[hash] = &chainjson.TxRawResult{Txid: hash.String()}
+	n.sent = append(n.sent, tx)
+	return nil
+}
+
+func (n *fakeNode) ExistsLiveTicket(context.Context, *chainhash.Hash) (bool, error) {
+	return true, nil
+}
+
+func (n *fakeNode) VerifyMessage(_ context.Context, addr stdaddr.Address, signature, message string) (bool, error) {
+	want := base64.StdEncoding.EncodeToString([]byte(addr.String() + message))
+	return signature == want, nil
+}
+
+func (n *fakeNode) AgendaActive(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+// fakeWallets implements the WalletPool interface for the tests.
+type fakeWallets struct{}
+
+func (fakeWallets) Status(context.Context) []rpc.WalletStatus {
+	return []rpc.WalletStatus{{Host: "wallet", Connected: true,
+		BestBlockHeight: 1000}}
+}
+
+func (fakeWallets) SetVoteChoices(context.Context, *database.Ticket) error {
+	return nil
+}
+
+// fakeSign signs messages the way fakeNode verifies them.
+func fakeSign(_ context.Context, message string, addr stdaddr.Address) ([]byte, error) {
+	return []byte(addr.String() + message), nil
+}
+
+// p2pkhScript returns a pay-to-pubkey-hash script for the provided hash which
+// is optionally tagged with the provided stake opcode.
+func p2pkhScript(stakeOpcode byte, pkHash []byte) []byte {
+	var script []byte
+	if stakeOpcode != 0 {
+		script = append(script, stakeOpcode)
+	}
+	script = append(script, 0x76, 0xa9, 0x14) // OP_DUP OP_HASH160 DATA_20
+	script = append(script, pkHash...)
+	return append(script, 0x88, 0xac) // OP_EQUALVERIFY OP_CHECKSIG
+}
+
+// testTicket returns a ticket which votes with the provided voting key hash and
+// commits to the provided commitment hash along with its parent transaction.
+func testTicket(votingHash, commitmentHash []byte) (*wire.MsgTx, *wire.MsgTx) {
+	const ticketPrice = 100e8
+
+	parent := wire.NewMsgTx()
+	parent.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0,
+		wire.TxTreeRegular), ticketPrice+1e6, nil))
+	parent.AddTxOut(wire.NewTxOut(ticketPrice+1e5, p2pkhScript(0,
+		commitmentHash)))
+	parentHash := parent.TxHash()
+
+	commitment := []byte{0x6a, 0x1e} // OP_RETURN DATA_30
+	commitment = append(commitment, commitmentHash...)
+	commitment = binary.LittleEndian.AppendUint64(commitment, ticketPrice+1e5)
+	commitment = append(commitment, 0x00, 0x58)
+
+	ticket := wire.NewMsgTx()
+	ticket.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&parentHash, 0,
+		wire.TxTreeRegular), ticketPrice+1e5, nil))
+	ticket.AddTxOut(wire.NewTxOut(ticketPrice, p2pkhScript(0xba, votingHash)))
+	ticket.AddTxOut(wire.NewTxOut(0, commitment))
+	ticket.AddTxOut(wire.NewTxOut(0, p2pkhScript(0xbd, commitmentHash)))
+	return ticket, parent
+}
+
+// txHex returns the hex encoded serialization of the provided transaction.
+func txHex(t *testing.T, tx *wire.MsgTx) string {
+	t.Helper()
+	b, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize tx: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// wantErrorCode ensures the provided error is an API error with the provided
+// code.
+func wantErrorCode(t *testing.T, err error, code types.ErrorCode) {
+	t.Helper()
+	var apiErr types.ErrorResponse
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("unexpected error -- got %v, want code %d", err, code)
+	}
+	if apiErr.Code != code {
+		t.Fatalf("unexpected error code -- got %d (%v), want %d", apiErr.Code,
+			apiErr, code)
+	}
+}
+
+// TestAPI ensures a ticket can be registered, paid for, and have its voting
+// preferences updated through the API with the client of the wallet.
+func TestAPI(t *testing.T) {
+	params := chaincfg.SimNetParams()
+	db, err := database.Open(filepath.Join(t.TempDir(), "vspd.db"))
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer db.Close()
+
+	master, err := hdkeychain.NewMaster(bytes.Repeat([]byte{1}, 32), params)
+	if err != nil {
+		t.Fatalf("unable to create master key: %v", err)
+	}
+	node := &fakeNode{txs: make(map[chainhash.Hash]*chainjson.TxRawResult)}
+	srv, err := New(Config{
+		Params:        params,
+		FeeXPub:       master.Neuter().String(),
+		FeePercentage: 5,
+		AdminPass:     "adminpass",
+		Version:       "test",
+	}, db, node, fakeWallets{})
+	if err != nil {
+		t.Fatalf("unable to create server: %v", err)
+	}
+	httpSrv := httptest.NewServer(srv.Handler())
+	defer httpSrv.Close()
+	c := &client.Client{
+		URL:    httpSrv.URL,
+		PubKey: srv.PubKey(),
+		Sign:   fakeSign,
+	}
+	ctx := context.Background()
+
+	// The statistics must be reported once they are updated.
+	if err := srv.UpdateStats(ctx); err != nil {
+		t.Fatalf("unable to update stats: %v", err)
+	}
+	info, err := c.VspInfo(ctx)
+	if err != nil {
+		t.Fatalf("unexpected vspinfo error: %v", err)
+	}
+	if info.Network != params.Name || info.BlockHeight != 1000 ||
+		info.VotingWalletsOnline != 1 || info.FeePercentage != 5 {
+
+		t.Fatalf("unexpected vspinfo -- got %+v", info)
+	}
+
+	votingWIF, err := VGLutil.NewWIF(bytes.Repeat([]byte{2}, 32),
+		params.PrivateKeyID, VGLec.STEcdsaSecp256k1)
+	if err != nil {
+		t.Fatalf("unable to create voting key: %v", err)
+	}
+	commitmentHash := bytes.Repeat([]byte{3}, 20)
+	commitmentAddr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(
+		commitmentHash, params)
+	if err != nil {
+		t.Fatalf("unable to create commitment address: %v", err)
+	}
+	otherAddr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(
+		bytes.Repeat([]byte{4}, 20), params)
+	if err != nil {
+		t.Fatalf("unable to create address: %v", err)
+	}
+	ticket, parent := testTicket(stdaddr.Hash160(votingWIF.PubKey()),
+		commitmentHash)
+	ticketHash := ticket.TxHash().String()
+
+	// Requests must be signed by the commitment address.
+	feeReq := types.FeeAddressRequest{
+		Timestamp:  time.Now().Unix(),
+		TicketHash: ticketHash,
+		TicketHex:  txHex(t, ticket),
+		ParentHex:  txHex(t, parent),
+	}
+	_, err = c.FeeAddress(ctx, feeReq, otherAddr)
+	wantErrorCode(t, err, types.ErrBadSignature)
+
+	// Registering the ticket must broadcast it along with its parent.
+	feeResp, err := c.FeeAddress(ctx, feeReq, commitmentAddr)
+	if err != nil {
+		t.Fatalf("unexpected feeaddress error: %v", err)
+	}
+	if feeResp.FeeAmount <= 0 || feeResp.Expiration <= time.Now().Unix() {
+		t.Fatalf("unexpected fee -- got %+v", feeResp)
+	}
+	if len(node.sent) != 2 || node.sent[1].TxHash() != ticket.TxHash() {
+		t.Fatalf("ticket was not broadcast -- sent %d txs", len(node.sent))
+	}
+	again, err := c.FeeAddress(ctx, feeReq, commitmentAddr)
+	if err != nil {
+		t.Fatalf("unexpected feeaddress error: %v", err)
+	}
+	if again.FeeAddress != feeResp.FeeAddress {
+		t.Fatalf("unexpected fee address -- got %s, want %s",
+			again.FeeAddress, feeResp.FeeAddress)
+	}
+
+	// Vote choices can't be set before the fee is paid.
+	_, err = c.SetVoteChoices(ctx, types.SetVoteChoicesRequest{
+		Timestamp:  time.Now().Unix(),
+		TicketHash: ticketHash,
+	}, commitmentAddr)
+	wantErrorCode(t, err, types.ErrFeeNotReceived)
+
+	feeAddr, err := stdaddr.DecodeAddress(feeResp.FeeAddress, params)
+	if err != nil {
+		t.Fatalf("unable to decode fee address: %v", err)
+	}
+	feeTx := func(amount int64) string {
+		_, script := feeAddr.PaymentScript()
+		tx := wire.NewMsgTx()
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{5}, 0,
+			wire.TxTreeRegular), amount+1e5, nil))
+		tx.AddTxOut(wire.NewTxOut(amount, script))
+		return txHex(t, tx)
+	}
+	payReq := types.PayFeeRequest{
+		Timestamp:  time.Now().Unix(),
+		TicketHash: ticketHash,
+		FeeTx:      feeTx(feeResp.FeeAmount - 1),
+		VotingKey:  votingWIF.String(),
+	}
+	_, err = c.PayFee(ctx, payReq, commitmentAddr)
+	wantErrorCode(t, err, types.ErrFeeTooSmall)
+
+	otherWIF, err := VGLutil.NewWIF(bytes.Repeat([]byte{6}, 32),
+		params.PrivateKeyID, VGLec.STEcdsaSecp256k1)
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	payReq.FeeTx = feeTx(feeResp.FeeAmount)
+	payReq.VotingKey = otherWIF.String()
+	_, err = c.PayFee(ctx, payReq, commitmentAddr)
+	wantErrorCode(t, err, types.ErrInvalidPrivKey)
+
+	payReq.VotingKey = votingWIF.String()
+	payReq.VoteChoices = map[string]string{"unknownagenda": "yes"}
+	_, err = c.PayFee(ctx, payReq, commitmentAddr)
+	wantErrorCode(t, err, types.ErrInvalidVoteChoices)
+
+	payReq.VoteChoices = nil
+	payReq.TSpendPolicy = map[string]string{ticketHash: "no"}
+	if _, err := c.PayFee(ctx, payReq, commitmentAddr); err != nil {
+		t.Fatalf("unexpected payfee error: %v", err)
+	}
+	_, err = c.PayFee(ctx, payReq, commitmentAddr)
+	wantErrorCode(t, err, types.ErrFeeAlreadyReceived)
+
+	statusReq := types.TicketStatusRequest{TicketHash: ticketHash}
+	status, err := c.TicketStatus(ctx, statusReq, commitmentAddr)
+	if err != nil {
+		t.Fatalf("unexpected ticketstatus error: %v", err)
+	}
+	if status.FeeTxStatus != string(database.FeeBroadcast) ||
+		status.TSpendPolicy[ticketHash] != "no" {
+
+		t.Fatalf("unexpected ticket status -- got %+v", status)
+	}
+
+	// Vote choice changes must have increasing timestamps and are merged
+	// with the current choices.
+	_, err = c.SetVoteChoices(ctx, types.SetVoteChoicesRequest{
+		Timestamp:  payReq.Timestamp,
+		TicketHash: ticketHash,
+	}, commitmentAddr)
+	wantErrorCode(t, err, types.ErrInvalidTimestamp)
+	treasuryKey := hex.EncodeToString(votingWIF.PubKey())
+	_, err = c.SetVoteChoices(ctx, types.SetVoteChoicesRequest{
+		Timestamp:      payReq.Timestamp + 1,
+		TicketHash:     ticketHash,
+		TreasuryPolicy: map[string]string{treasuryKey: "abstain"},
+	}, commitmentAddr)
+	if err != nil {
+		t.Fatalf("unexpected setvotechoices error: %v", err)
+	}
+	status, err = c.TicketStatus(ctx, statusReq, commitmentAddr)
+	if err != nil {
+		t.Fatalf("unexpected ticketstatus error: %v", err)
+	}
+	if status.TSpendPolicy[ticketHash] != "no" ||
+		status.TreasuryPolicy[treasuryKey] != "abstain" {
+
+		t.Fatalf("unexpected ticket status -- got %+v", status)
+	}
+
+	// Unknown tickets must be rejected.
+	statusReq.TicketHash = chainhash.Hash{7}.String()
+	_, err = c.TicketStatus(ctx, statusReq, commitmentAddr)
+	wantErrorCode(t, err, types.ErrUnknownTicket)
+}
+
+// TestAdmin ensures the admin page requires logging in with the admin
+// password.
+func TestAdmin(t *testing.T) {
+	params := chaincfg.SimNetParams()
+	db, err := database.Open(filepath.Join(t.TempDir(), "vspd.db"))
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer db.Close()
+	master, err := hdkeychain.NewMaster(bytes.Repeat([]byte{1}, 32), params)
+	if err != nil {
+		t.Fatalf("unable to create master key: %v", err)
+	}
+	node := &fakeNode{txs: make(map[chainhash.Hash]*chainjson.TxRawResult)}
+	srv, err := New(Config{
+		Params:    params,
+		FeeXPub:   master.Neuter().String(),
+		AdminPass: "adminpass",
+	}, db, node, fakeWallets{})
+	if err != nil {
+		t.Fatalf("unable to create server: %v", err)
+	}
+	httpSrv := httptest.NewServer(srv.Handler())
+	defer httpSrv.Close()
+	httpClient := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	get := func(cookie *http.Cookie) string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, httpSrv.URL+"/admin", nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %v", err)
+		}
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /admin failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response: %v", err)
+		}
+		return string(body)
+	}
+	login := func(pass string) *http.Response {
+		t.Helper()
+		resp, err := httpClient.PostForm(httpSrv.URL+"/admin/login",
+			url.Values{"password": {pass}})
+		if err != nil {
+			t.Fatalf("POST /admin/login failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	if page := get(nil); !strings.Contains(page, `name="password"`) {
+		t.Fatal("login page not served without a session")
+	}
+	if resp := login("wrong"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unexpected status for wrong password -- got %d",
+			resp.StatusCode)
+	}
+	resp := login("adminpass")
+	if resp.StatusCode != http.StatusSeeOther || len(resp.Cookies()) != 1 {
+		t.Fatalf("unexpected login response -- got status %d", resp.StatusCode)
+	}
+	page := get(resp.Cookies()[0])
+	if !strings.Contains(page, "Voting wallets") {
+		t.Fatal("admin page not served with a session")
+	}
+
+	// Forged sessions must be rejected.
+	forged := *resp.Cookies()[0]
+	forged.Value = "9999999999." + strings.Repeat("0", 64)
+	if page := get(&forged); !strings.Contains(page, `name="password"`) {
+		t.Fatal("admin page served with a forged session")
+	}
+}
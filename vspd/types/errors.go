@@ -0,0 +1,92 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package types
+
+import "net/http"
+
+// ErrorCode identifies the reason a request to the vspd API failed.
+type ErrorCode int64
+
+// These constants are used to identify a specific ErrorCode.  The values are
+// part of the API and must not be changed.
+const (
+	ErrBadRequest ErrorCode = iota
+	ErrInternalError
+	ErrVspClosed
+	ErrFeeAlreadyReceived
+	ErrInvalidFeeTx
+	ErrFeeTooSmall
+	ErrUnknownTicket
+	ErrTicketCannotVote
+	ErrFeeExpired
+	ErrInvalidVoteChoices
+	ErrBadSignature
+	ErrInvalidPrivKey
+	ErrFeeNotReceived
+	ErrInvalidTicket
+	ErrCannotBroadcastTicket
+	ErrCannotBroadcastFee
+	ErrCannotBroadcastFeeUnknownOutputs
+	ErrInvalidTimestamp
+)
+
+// errorCodeInfo describes the default message and the HTTP status of an
+// ErrorCode.
+type errorCodeInfo struct {
+	message    string
+	httpStatus int
+}
+
+// errorCodeInfos maps each ErrorCode to its default message and HTTP status.
+var errorCodeInfos = map[ErrorCode]errorCodeInfo{
+	ErrBadRequest:                       {"bad request", http.StatusBadRequest},
+	ErrInternalError:                    {"internal error", http.StatusInternalServerError},
+	ErrVspClosed:                        {"vsp is closed", http.StatusBadRequest},
+	ErrFeeAlreadyReceived:               {"fee tx already received for ticket", http.StatusBadRequest},
+	ErrInvalidFeeTx:                     {"invalid fee tx", http.StatusBadRequest},
+	ErrFeeTooSmall:                      {"fee too small", http.StatusBadRequest},
+	ErrUnknownTicket:                    {"unknown ticket", http.StatusBadRequest},
+	ErrTicketCannotVote:                 {"ticket not eligible to vote", http.StatusBadRequest},
+	ErrFeeExpired:                       {"fee has expired", http.StatusBadRequest},
+	ErrInvalidVoteChoices:               {"invalid vote choices", http.StatusBadRequest},
+	ErrBadSignature:                     {"bad request signature", http.StatusBadRequest},
+	ErrInvalidPrivKey:                   {"invalid private key", http.StatusBadRequest},
+	ErrFeeNotReceived:                   {"no fee tx received for ticket", http.StatusBadRequest},
+	ErrInvalidTicket:                    {"not a valid ticket tx", http.StatusBadRequest},
+	ErrCannotBroadcastTicket:            {"ticket transaction could not be broadcast", http.StatusInternalServerError},
+	ErrCannotBroadcastFee:               {"fee transaction could not be broadcast", http.StatusInternalServerError},
+	ErrCannotBroadcastFeeUnknownOutputs: {"fee transaction could not be broadcast due to unknown outputs", http.StatusPreconditionRequired},
+	ErrInvalidTimestamp:                 {"old or reused timestamp", http.StatusNotAcceptable},
+}
+
+// DefaultMessage returns a descriptive error string for the ErrorCode.
+func (e ErrorCode) DefaultMessage() string {
+	if info, ok := errorCodeInfos[e]; ok {
+		return info.message
+	}
+	return "unknown error"
+}
+
+// HTTPStatus returns the HTTP status code which is used for responses with
+// the ErrorCode.
+func (e ErrorCode) HTTPStatus() int {
+	if info, ok := errorCodeInfos[e]; ok {
+		return info.httpStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorResponse is the response of the vspd API for requests that failed.  It
+// satisfies the error interface so clients may return it directly and callers
+// can use errors.As to inspect the code.
+type ErrorResponse struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// Error satisfies the error interface.
+func (e ErrorResponse) Error() string {
+	return e.Message
+}
@@ -0,0 +1,3 @@
+module github.com/kdsmith18542/vigil/vspd/types/v3
+
+go 1.17
@@ -0,0 +1,114 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package types defines the requests and responses of the vspd API.
+//
+// All responses are signed by the VSP with its ed25519 key and the signature is
+// provided base64 encoded in the VSP-Server-Signature HTTP header.  Requests to
+// the ticket specific endpoints are signed with the commitment address of the
+// ticket and the signature is provided base64 encoded in the
+// VSP-Client-Signature HTTP header.
+package types
+
+const (
+	// ServerSignatureHeader is the HTTP header which contains the signature
+	// of the VSP over the response body.
+	ServerSignatureHeader = "VSP-Server-Signature"
+
+	// ClientSignatureHeader is the HTTP header which contains the signature
+	// of the commitment address of a ticket over the request body.
+	ClientSignatureHeader = "VSP-Client-Signature"
+)
+
+// VspInfoResponse is the response of the /api/v3/vspinfo endpoint.
+type VspInfoResponse struct {
+	APIVersions         []int64 `json:"apiversions"`
+	Timestamp           int64   `json:"timestamp"`
+	PubKey              []byte  `json:"pubkey"`
+	FeePercentage       float64 `json:"feepercentage"`
+	VspClosed           bool    `json:"vspclosed"`
+	VspClosedMsg        string  `json:"vspclosedmsg"`
+	Network             string  `json:"network"`
+	VspdVersion         string  `json:"vspdversion"`
+	Voting              int64   `json:"voting"`
+	Voted               int64   `json:"voted"`
+	TotalVotingWallets  int64   `json:"totalvotingwallets"`
+	VotingWalletsOnline int64   `json:"votingwalletsonline"`
+	Expired             int64   `json:"expired"`
+	Missed              int64   `json:"missed"`
+	BlockHeight         uint32  `json:"blockheight"`
+	NetworkProportion   float32 `json:"estimatednetworkproportion"`
+}
+
+// FeeAddressRequest is the request of the /api/v3/feeaddress endpoint.
+type FeeAddressRequest struct {
+	Timestamp  int64  `json:"timestamp"`
+	TicketHash string `json:"tickethash"`
+	TicketHex  string `json:"tickethex"`
+	ParentHex  string `json:"parenthex"`
+}
+
+// FeeAddressResponse is the response of the /api/v3/feeaddress endpoint.
+type FeeAddressResponse struct {
+	Timestamp  int64  `json:"timestamp"`
+	FeeAddress string `json:"feeaddress"`
+	FeeAmount  int64  `json:"feeamount"`
+	Expiration int64  `json:"expiration"`
+	Request    []byte `json:"request"`
+}
+
+// PayFeeRequest is the request of the /api/v3/payfee endpoint.
+type PayFeeRequest struct {
+	Timestamp      int64             `json:"timestamp"`
+	TicketHash     string            `json:"tickethash"`
+	FeeTx          string            `json:"feetx"`
+	VotingKey      string            `json:"votingkey"`
+	VoteChoices    map[string]string `json:"votechoices"`
+	TSpendPolicy   map[string]string `json:"tspendpolicy"`
+	TreasuryPolicy map[string]string `json:"treasurypolicy"`
+}
+
+// PayFeeResponse is the response of the /api/v3/payfee endpoint.
+type PayFeeResponse struct {
+	Timestamp int64  `json:"timestamp"`
+	Request   []byte `json:"request"`
+}
+
+// SetVoteChoicesRequest is the request of the /api/v3/setvotechoices
+// endpoint.
+type SetVoteChoicesRequest struct {
+	Timestamp      int64             `json:"timestamp"`
+	TicketHash     string            `json:"tickethash"`
+	VoteChoices    map[string]string `json:"votechoices"`
+	TSpendPolicy   map[string]string `json:"tspendpolicy"`
+	TreasuryPolicy map[string]string `json:"treasurypolicy"`
+}
+
+// SetVoteChoicesResponse is the response of the /api/v3/setvotechoices
+// endpoint.
+type SetVoteChoicesResponse struct {
+	Timestamp int64  `json:"timestamp"`
+	Request   []byte `json:"request"`
+}
+
+// TicketStatusRequest is the request of the /api/v3/ticketstatus endpoint.
+type TicketStatusRequest struct {
+	TicketHash string `json:"tickethash"`
+}
+
+// TicketStatusResponse is the response of the /api/v3/ticketstatus endpoint.
+//
+// FeeTxStatus is one of "none", "received", "broadcast", "confirmed", or
+// "error".
+type TicketStatusResponse struct {
+	Timestamp       int64             `json:"timestamp"`
+	TicketConfirmed bool              `json:"ticketconfirmed"`
+	FeeTxStatus     string            `json:"feetxstatus"`
+	FeeTxHash       string            `json:"feetxhash"`
+	AltSignAddress  string            `json:"altsignaddress"`
+	VoteChoices     map[string]string `json:"votechoices"`
+	TSpendPolicy    map[string]string `json:"tspendpolicy"`
+	TreasuryPolicy  map[string]string `json:"treasurypolicy"`
+	Request         []byte            `json:"request"`
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kdsmith18542/vigil/rpcclient/v8"
+	"github.com/kdsmith18542/vigil/vspd/internal/database"
+	"github.com/kdsmith18542/vigil/vspd/internal/rpc"
+	"github.com/kdsmith18542/vigil/vspd/internal/webapi"
+)
+
+// version is the version of vspd reported by the vspinfo endpoint.
+const version = "1.0.0"
+
+// shutdownTimeout is the maximum amount of time to wait for in-flight HTTP
+// requests to complete on shutdown.
+const shutdownTimeout = time.Second * 10
+
+// serve serves the provided handler on the provided listeners until the
+// provided context is cancelled.
+func serve(ctx context.Context, handler http.Handler, listeners []net.Listener) error {
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: time.Second * 10,
+		ReadTimeout:       time.Second * 30,
+		WriteTimeout:      time.Second * 60,
+	}
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		vspdLog.Infof("API listening on %s", listener.Addr())
+		wg.Add(1)
+		go func(listener net.Listener) {
+			defer wg.Done()
+			err := srv.Serve(listener)
+			if !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}(listener)
+	}
+
+	var runErr error
+	select {
+	case err := <-errCh:
+		runErr = err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(),
+		shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		vspdLog.Warnf("Unable to gracefully stop HTTP server: %v", err)
+	}
+	wg.Wait()
+	return runErr
+}
+
+// newWalletPool returns the pool of voting wallets described by the provided
+// config.
+func newWalletPool(cfg *config) (*rpc.WalletPool, error) {
+	clientCert, err := tls.LoadX509KeyPair(cfg.WalletClientCert,
+		cfg.WalletClientKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load wallet client certificate: %w",
+			err)
+	}
+	walletCfgs := make([]rpc.WalletConfig, 0, len(cfg.WalletHosts))
+	for i, host := range cfg.WalletHosts {
+		cert, err := os.ReadFile(cfg.WalletCerts[i])
+		if err != nil {
+			return nil, fmt.Errorf("unable to read wallet certificate: %w",
+				err)
+		}
+		walletCfgs = append(walletCfgs, rpc.WalletConfig{
+			Host: host,
+			Cert: cert,
+		})
+	}
+	return rpc.NewWalletPool(walletCfgs, clientCert, []byte(cfg.WalletPass))
+}
+
+// run is the real main function for vspd.  It is necessary to work around the
+// fact that deferred functions do not run when os.Exit() is called.
+func run() error {
+	// Load configuration and parse command line.  This function also
+	// initializes logging and configures it accordingly.
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if logRotator != nil {
+			logRotator.Close()
+		}
+	}()
+
+	// Get a context that will be canceled when a shutdown signal has been
+	// triggered from an OS signal such as SIGINT (Ctrl+C).
+	ctx := shutdownListener()
+	defer vspdLog.Info("Shutdown complete")
+
+	vspdLog.Infof("Home dir: %s", cfg.HomeDir)
+	if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+		vspdLog.Errorf("Unable to create data directory: %v", err)
+		return err
+	}
+
+	// Open the VSP database.
+	dbPath := filepath.Join(cfg.DataDir, defaultDBFilename)
+	db, err := database.Open(dbPath)
+	if err != nil {
+		vspdLog.Errorf("Unable to open database: %v", err)
+		return err
+	}
+	defer func() {
+		vspdLog.Infof("Gracefully shutting down the database...")
+		db.Close()
+	}()
+
+	// Return now if a shutdown signal was triggered.
+	if shutdownRequested(ctx) {
+		return nil
+	}
+
+	wallets, err := newWalletPool(cfg)
+	if err != nil {
+		vspdLog.Errorf("%v", err)
+		return err
+	}
+	defer wallets.Close()
+
+	v := &vspd{
+		params:  cfg.params.Params,
+		db:      db,
+		wallets: wallets,
+		blockCh: make(chan struct{}, 1),
+	}
+	nodeCert, err := os.ReadFile(cfg.NodeRPCCert)
+	if err != nil {
+		vspdLog.Errorf("Unable to read vgld RPC certificate: %v", err)
+		return err
+	}
+	v.node, err = rpc.NewNode(&rpcclient.ConnConfig{
+		Host:         cfg.NodeRPCConnect,
+		User:         cfg.NodeRPCUser,
+		Pass:         cfg.NodeRPCPass,
+		Certificates: nodeCert,
+	}, v.signalBlock)
+	if err != nil {
+		vspdLog.Errorf("%v", err)
+		return err
+	}
+	v.api, err = webapi.New(webapi.Config{
+		Params:        cfg.params.Params,
+		FeeXPub:       cfg.FeeXPub,
+		FeePercentage: cfg.FeePercentage,
+		VspClosed:     cfg.VspClosed,
+		VspClosedMsg:  cfg.VspClosedMsg,
+		AdminPass:     cfg.AdminPass,
+		Version:       version,
+	}, db, v.node, wallets)
+	if err != nil {
+		vspdLog.Errorf("%v", err)
+		return err
+	}
+	vspdLog.Infof("VSP public key: %x", v.api.PubKey())
+
+	listeners := make([]net.Listener, 0, len(cfg.Listeners))
+	for _, addr := range cfg.Listeners {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			vspdLog.Errorf("Unable to listen on %s: %v", addr, err)
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	if err := v.node.Connect(ctx); err != nil {
+		for _, l := range listeners {
+			l.Close()
+		}
+		vspdLog.Errorf("Unable to connect to vgld: %v", err)
+		return err
+	}
+	defer v.node.Close()
+
+	// Serve the API while following the node and stop both when either
+	// fails.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		errs[0] = v.run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		errs[1] = serve(ctx, v.api.Handler(), listeners)
+	}()
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			vspdLog.Errorf("%v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
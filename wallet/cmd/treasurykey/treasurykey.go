@@ -1,13 +1,16 @@
 package main
 
 import (
+	"encoding/hex"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/kdsmith18542/vigil/chaincfg/v3"
 	"github.com/kdsmith18542/vigil/VGLec"
 	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4"
+	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4/schnorr"
 	"github.com/kdsmith18542/vigil/VGLutil/v4"
 )
 
@@ -31,13 +34,55 @@ func generateKeys(params *chaincfg.Params) error {
 	return nil
 }
 
+// aggregateKeys prints the MuSig2 aggregate of the comma separated public
+// keys of treasury key shares along with the order the wallets must be given
+// the keys in when signing.
+func aggregateKeys(keys string) error {
+	var pubKeys []*secp256k1.PublicKey
+	for _, key := range strings.Split(keys, ",") {
+		b, err := hex.DecodeString(strings.TrimSpace(key))
+		if err != nil {
+			return err
+		}
+		pubKey, err := schnorr.ParsePubKey(b)
+		if err != nil {
+			return err
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	schnorr.SortPubKeys(pubKeys)
+
+	aggKey, err := schnorr.AggregatePubKeys(pubKeys)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Aggregate key: %x\n", aggKey.SerializeCompressed())
+	fmt.Println("Key order    :")
+	for _, pubKey := range pubKeys {
+		fmt.Printf("  %x\n", pubKey.SerializeCompressed())
+	}
+
+	return nil
+}
+
 func main() {
 	mainnet := flag.Bool("mainnet", false, "use mainnet parameters")
 	simnet := flag.Bool("simnet", false, "use simnet parameters")
 	regnet := flag.Bool("regnet", false, "use regnet parameters")
 	testnet := flag.Bool("testnet", false, "use testnet parameters")
+	aggregate := flag.String("aggregate", "", "print the aggregate key of "+
+		"the comma separated public keys of treasury key shares")
 	flag.Parse()
 
+	if *aggregate != "" {
+		if err := aggregateKeys(*aggregate); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var net *chaincfg.Params
 	flags := 0
 	if *mainnet {
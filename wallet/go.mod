@@ -3,15 +3,29 @@ module github.com/kdsmith18542/vigil/wallet
 go 1.23.0
 
 require (
+	github.com/gorilla/websocket v1.5.1
 	github.com/kdsmith18542/vigil/addrmgr/v3 v3.0.0
 	github.com/kdsmith18542/vigil/blockchain/v5 v5.0.0
-	github.com/kdsmith18542/vigil/chaincfg/v3 v3.0.0
-	github.com/kdsmith18542/vigil/dcrec/secp256k1/v4 v4.0.0
-	github.com/kdsmith18542/vigil/dcrjson/v4 v4.0.0
-	github.com/kdsmith18542/vigil/dcrutil/v4 v4.0.0
+	github.com/kdsmith18542/vigil/chaincfg/v3 v3.2.1
+	github.com/kdsmith18542/vigil/dcrec/secp256k1/v4 v4.3.0
+	github.com/kdsmith18542/vigil/dcrjson/v4 v4.0.1
+	github.com/kdsmith18542/vigil/dcrutil/v4 v4.0.2
+	github.com/kdsmith18542/vigil/kawpow v0.0.0-00010101000000-000000000000
 	github.com/kdsmith18542/vigil/rpcclient/v8 v8.0.0
-	github.com/kdsmith18542/vigil/txscript/v4 v4.0.0
-	github.com/kdsmith18542/vigil/wire v1.0.0
+	github.com/kdsmith18542/vigil/txscript/v4 v4.1.1
+	github.com/kdsmith18542/vigil/vspd/client/v4 v4.0.0
+	github.com/kdsmith18542/vigil/vspd/types/v3 v3.0.0
+	github.com/kdsmith18542/vigil/wire v1.7.0
+	golang.org/x/crypto v0.24.0
+	golang.org/x/term v0.21.0
+)
+
+require (
+	github.com/dchest/siphash v1.2.3 // indirect
+	github.com/klauspost/cpuid/v2 v2.0.9 // indirect
+	golang.org/x/net v0.25.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	lukechampine.com/blake3 v1.3.0 // indirect
 )
 
 replace (
@@ -21,7 +35,10 @@ replace (
 	github.com/kdsmith18542/vigil/dcrec/secp256k1/v4 => ../node/dcrec/secp256k1
 	github.com/kdsmith18542/vigil/dcrjson/v4 => ../node/dcrjson
 	github.com/kdsmith18542/vigil/dcrutil/v4 => ../node/dcrutil
+	github.com/kdsmith18542/vigil/kawpow => ../node/kawpow
 	github.com/kdsmith18542/vigil/rpcclient/v8 => ../node/rpcclient
 	github.com/kdsmith18542/vigil/txscript/v4 => ../node/txscript
+	github.com/kdsmith18542/vigil/vspd/client/v4 => ../vspd/client
+	github.com/kdsmith18542/vigil/vspd/types/v3 => ../vspd/types
 	github.com/kdsmith18542/vigil/wire => ../node/wire
-)
\ No newline at end of file
+)
@@ -28,6 +28,7 @@ import (
 	"github.com/kdsmith18542/vigil/wallet/spv"
 	"github.com/kdsmith18542/vigil/wallet/version"
 	"github.com/kdsmith18542/vigil/wallet/wallet"
+	"github.com/kdsmith18542/vigil/wallet/wallet/psbt"
 	"github.com/kdsmith18542/vigil/wallet/wallet/txauthor"
 	"github.com/kdsmith18542/vigil/wallet/wallet/txrules"
 	"github.com/kdsmith18542/vigil/wallet/wallet/txsizes"
@@ -39,6 +40,7 @@ import (
 	"github.com/kdsmith18542/vigil/crypto/rand"
 	"github.com/kdsmith18542/vigil/VGLec"
 	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4"
+	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4/schnorr"
 	"github.com/kdsmith18542/vigil/VGLjson/v4"
 	"github.com/kdsmith18542/vigil/VGLutil/v4"
 	"github.com/kdsmith18542/vigil/hdkeychain/v3"
@@ -89,16 +91,22 @@ var handlers = map[string]handler{
 	"accountunlocked":           {fn: (*Server).accountUnlocked},
 	"addmultisigaddress":        {fn: (*Server).addMultiSigAddress},
 	"addtransaction":            {fn: (*Server).addTransaction},
+	"analyzepsbt":               {fn: (*Server).analyzePSBT},
 	"auditreuse":                {fn: (*Server).auditReuse},
+	"combinepsbt":               {fn: (*Server).combinePSBT},
 	"consolidate":               {fn: (*Server).consolidate},
 	"createmultisig":            {fn: (*Server).createMultiSig},
 	"createnewaccount":          {fn: (*Server).createNewAccount},
+	"createpsbt":                {fn: (*Server).createPSBT},
 	"createrawtransaction":      {fn: (*Server).createRawTransaction},
 	"createsignature":           {fn: (*Server).createSignature},
+	"createtreasuryspend":       {fn: (*Server).createTreasurySpend},
 	"debuglevel":                {fn: (*Server).debugLevel},
 	"disapprovepercent":         {fn: (*Server).disapprovePercent},
 	"discoverusage":             {fn: (*Server).discoverUsage},
 	"dumpprivkey":               {fn: (*Server).dumpPrivKey},
+	"finalizepsbt":              {fn: (*Server).finalizePSBT},
+	"finalizetreasuryspend":     {fn: (*Server).finalizeTreasurySpend},
 	"fundrawtransaction":        {fn: (*Server).fundRawTransaction},
 	"getaccount":                {fn: (*Server).getAccount},
 	"getaccountaddress":         {fn: (*Server).getAccountAddress},
@@ -167,13 +175,16 @@ var handlers = map[string]handler{
 	"settxfee":                  {fn: (*Server).setTxFee},
 	"setvotechoice":             {fn: (*Server).setVoteChoice},
 	"signmessage":               {fn: (*Server).signMessage},
+	"signpsbt":                  {fn: (*Server).signPSBT},
 	"signrawtransaction":        {fn: (*Server).signRawTransaction},
 	"signrawtransactions":       {fn: (*Server).signRawTransactions},
+	"signtreasuryspend":         {fn: (*Server).signTreasurySpend},
 	"spendoutputs":              {fn: (*Server).spendOutputs},
 	"sweepaccount":              {fn: (*Server).sweepAccount},
 	"syncstatus":                {fn: (*Server).syncStatus},
 	"ticketinfo":                {fn: (*Server).ticketInfo},
 	"treasurypolicy":            {fn: (*Server).treasuryPolicy},
+	"treasuryspendnonce":        {fn: (*Server).treasurySpendNonce},
 	"tspendpolicy":              {fn: (*Server).tspendPolicy},
 	"unlockaccount":             {fn: (*Server).unlockAccount},
 	"validateaddress":           {fn: (*Server).validateAddress},
@@ -3626,6 +3637,41 @@ func (s *Server) sendOutputsFromTreasury(ctx context.Context, w *wallet.Wallet,
 	}
 	defer zero()
 
+	msgTx, err := unsignedTreasurySpend(ctx, w, cmd.Amounts)
+	if err != nil {
+		return "", err
+	}
+
+	// Calculate TSpend signature without SigHashType.
+	privKeyBytes := privKey.Serialize()
+	sigscript, err := sign.TSpendSignatureScript(msgTx, privKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	msgTx.TxIn[0].SignatureScript = sigscript
+
+	_, _, err = stake.CheckTSpend(msgTx)
+	if err != nil {
+		return "", err
+	}
+
+	// Send to vgld.
+	n, ok := s.walletLoader.NetworkBackend()
+	if !ok {
+		return "", errNoNetwork
+	}
+	err = n.PublishTransactions(ctx, msgTx)
+	if err != nil {
+		return "", err
+	}
+
+	return msgTx.TxHash().String(), nil
+}
+
+// unsignedTreasurySpend creates a treasury spend paying the provided amounts
+// that expires at the end of the next treasury vote.  The signature script of
+// its single input is left empty.
+func unsignedTreasurySpend(ctx context.Context, w *wallet.Wallet, amounts map[string]float64) (*wire.MsgTx, error) {
 	_, tipHeight := w.MainChainTip(ctx)
 
 	// OP_RETURN <8 Bytes ValueIn><24 byte random>. The encoded ValueIn is
@@ -3637,8 +3683,8 @@ func (s *Server) sendOutputsFromTreasury(ctx context.Context, w *wallet.Wallet,
 	builder.AddData(payload[:])
 	opretScript, err := builder.Script()
 	if err != nil {
-		return "", rpcErrorf(VGLjson.ErrRPCInternal.Code,
-			"sendOutputsFromTreasury NewScriptBuilder: %v", err)
+		return nil, rpcErrorf(VGLjson.ErrRPCInternal.Code,
+			"unsignedTreasurySpend NewScriptBuilder: %v", err)
 	}
 	msgTx := wire.NewMsgTx()
 	msgTx.Version = wire.TxVersionTreasury
@@ -3651,10 +3697,10 @@ func (s *Server) sendOutputsFromTreasury(ctx context.Context, w *wallet.Wallet,
 
 	// OP_TGEN and calculate totals.
 	var totalPayout VGLutil.Amount
-	for address, amount := range cmd.Amounts {
+	for address, amount := range amounts {
 		amt, err := VGLutil.NewAmount(amount)
 		if err != nil {
-			return "", rpcError(VGLjson.ErrRPCInvalidParameter, err)
+			return nil, rpcError(VGLjson.ErrRPCInvalidParameter, err)
 		}
 
 		// While looping calculate total amount
@@ -3663,7 +3709,7 @@ func (s *Server) sendOutputsFromTreasury(ctx context.Context, w *wallet.Wallet,
 		// Decode address.
 		addr, err := decodeStakeAddress(address, w.ChainParams())
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		// Create OP_TGEN prefixed script.
@@ -3676,7 +3722,7 @@ func (s *Server) sendOutputsFromTreasury(ctx context.Context, w *wallet.Wallet,
 			PkScript: script,
 		}
 		if txrules.IsDustOutput(txOut, w.RelayFee()) {
-			return "", rpcErrorf(VGLjson.ErrRPCInvalidParameter,
+			return nil, rpcErrorf(VGLjson.ErrRPCInvalidParameter,
 				"Amount is dust: %v %v", addr, amt)
 		}
 
@@ -3706,30 +3752,7 @@ func (s *Server) sendOutputsFromTreasury(ctx context.Context, w *wallet.Wallet,
 	binary.LittleEndian.PutUint64(msgTx.TxOut[0].PkScript[2:2+8],
 		uint64(fee)+uint64(totalPayout))
 
-	// Calculate TSpend signature without SigHashType.
-	privKeyBytes := privKey.Serialize()
-	sigscript, err := sign.TSpendSignatureScript(msgTx, privKeyBytes)
-	if err != nil {
-		return "", err
-	}
-	msgTx.TxIn[0].SignatureScript = sigscript
-
-	_, _, err = stake.CheckTSpend(msgTx)
-	if err != nil {
-		return "", err
-	}
-
-	// Send to vgld.
-	n, ok := s.walletLoader.NetworkBackend()
-	if !ok {
-		return "", errNoNetwork
-	}
-	err = n.PublishTransactions(ctx, msgTx)
-	if err != nil {
-		return "", err
-	}
-
-	return msgTx.TxHash().String(), nil
+	return msgTx, nil
 }
 
 // treasuryPolicy returns voting policies for treasury spends by a particular
@@ -4669,6 +4692,337 @@ func (s *Server) sendFromTreasury(ctx context.Context, icmd any) (any, error) {
 	return s.sendOutputsFromTreasury(ctx, w, *cmd)
 }
 
+// createTreasurySpend handles a createtreasuryspend request by returning an
+// unsigned treasury spend paying the provided amounts that the holders of the
+// treasury key shares sign jointly.
+func (s *Server) createTreasurySpend(ctx context.Context, icmd any) (any, error) {
+	cmd := icmd.(*types.CreateTreasurySpendCmd)
+	w, ok := s.walletLoader.LoadedWallet()
+	if !ok {
+		return nil, errUnloadedWallet
+	}
+
+	msgTx, err := unsignedTreasurySpend(ctx, w, cmd.Amounts)
+	if err != nil {
+		return nil, err
+	}
+	b, err := msgTx.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// decodeTreasurySpend decodes a hex encoded unsigned treasury spend.
+func decodeTreasurySpend(s string) (*wire.MsgTx, error) {
+	tx := wire.NewMsgTx()
+	err := tx.Deserialize(hex.NewDecoder(strings.NewReader(s)))
+	if err != nil {
+		return nil, rpcError(VGLjson.ErrRPCDeserialization, err)
+	}
+	if tx.Version != wire.TxVersionTreasury || len(tx.TxIn) != 1 {
+		return nil, rpcErrorf(VGLjson.ErrRPCInvalidParameter,
+			"transaction is not a treasury spend")
+	}
+	return tx, nil
+}
+
+// decodeMuSigParams decodes the hex encoded public keys, public nonces, and
+// partial signatures of the signers of a treasury spend.
+func decodeMuSigParams(pubKeyStrs, pubNonceStrs, partialSigStrs []string) ([]*secp256k1.PublicKey,
+	[]*schnorr.PublicNonce, []*schnorr.PartialSignature, error) {
+
+	pubKeys := make([]*secp256k1.PublicKey, 0, len(pubKeyStrs))
+	for _, str := range pubKeyStrs {
+		b, err := hex.DecodeString(str)
+		if err != nil {
+			return nil, nil, nil, rpcError(VGLjson.ErrRPCDecodeHexString, err)
+		}
+		pubKey, err := schnorr.ParsePubKey(b)
+		if err != nil {
+			return nil, nil, nil, rpcError(VGLjson.ErrRPCInvalidAddressOrKey, err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	pubNonces := make([]*schnorr.PublicNonce, 0, len(pubNonceStrs))
+	for _, str := range pubNonceStrs {
+		var pubNonce schnorr.PublicNonce
+		if hex.DecodedLen(len(str)) != len(pubNonce) {
+			return nil, nil, nil, rpcErrorf(VGLjson.ErrRPCInvalidParameter,
+				"public nonce %q must be %d bytes", str, len(pubNonce))
+		}
+		if _, err := hex.Decode(pubNonce[:], []byte(str)); err != nil {
+			return nil, nil, nil, rpcError(VGLjson.ErrRPCDecodeHexString, err)
+		}
+		pubNonces = append(pubNonces, &pubNonce)
+	}
+	partialSigs := make([]*schnorr.PartialSignature, 0, len(partialSigStrs))
+	for _, str := range partialSigStrs {
+		b, err := hex.DecodeString(str)
+		if err != nil {
+			return nil, nil, nil, rpcError(VGLjson.ErrRPCDecodeHexString, err)
+		}
+		partialSig, err := schnorr.ParsePartialSignature(b)
+		if err != nil {
+			return nil, nil, nil, rpcError(VGLjson.ErrRPCInvalidParameter, err)
+		}
+		partialSigs = append(partialSigs, partialSig)
+	}
+	return pubKeys, pubNonces, partialSigs, nil
+}
+
+// treasurySpendNonce handles a treasuryspendnonce request by returning the
+// public MuSig2 nonce of an imported treasury key share for a treasury spend.
+func (s *Server) treasurySpendNonce(ctx context.Context, icmd any) (any, error) {
+	cmd := icmd.(*types.TreasurySpendNonceCmd)
+	w, ok := s.walletLoader.LoadedWallet()
+	if !ok {
+		return nil, errUnloadedWallet
+	}
+
+	addr, err := decodeAddress(cmd.Key, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	tx, err := decodeTreasurySpend(cmd.Tx)
+	if err != nil {
+		return nil, err
+	}
+	pubNonce, err := w.TreasurySpendNonce(ctx, addr, tx)
+	if err != nil {
+		return nil, err
+	}
+	return hex.EncodeToString(pubNonce[:]), nil
+}
+
+// signTreasurySpend handles a signtreasuryspend request by returning the
+// MuSig2 partial signature of an imported treasury key share for a treasury
+// spend.
+func (s *Server) signTreasurySpend(ctx context.Context, icmd any) (any, error) {
+	cmd := icmd.(*types.SignTreasurySpendCmd)
+	w, ok := s.walletLoader.LoadedWallet()
+	if !ok {
+		return nil, errUnloadedWallet
+	}
+
+	addr, err := decodeAddress(cmd.Key, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	tx, err := decodeTreasurySpend(cmd.Tx)
+	if err != nil {
+		return nil, err
+	}
+	pubKeys, pubNonces, _, err := decodeMuSigParams(cmd.PubKeys,
+		cmd.PubNonces, nil)
+	if err != nil {
+		return nil, err
+	}
+	partialSig, err := w.SignTreasurySpend(ctx, addr, tx, pubKeys, pubNonces)
+	if err != nil {
+		return nil, err
+	}
+	return hex.EncodeToString(partialSig.Serialize()), nil
+}
+
+// finalizeTreasurySpend handles a finalizetreasuryspend request by combining
+// the MuSig2 partial signatures of all treasury key shares into the signature
+// of the treasury spend and sending it.
+func (s *Server) finalizeTreasurySpend(ctx context.Context, icmd any) (any, error) {
+	cmd := icmd.(*types.FinalizeTreasurySpendCmd)
+
+	tx, err := decodeTreasurySpend(cmd.Tx)
+	if err != nil {
+		return nil, err
+	}
+	pubKeys, pubNonces, partialSigs, err := decodeMuSigParams(cmd.PubKeys,
+		cmd.PubNonces, cmd.PartialSigs)
+	if err != nil {
+		return nil, err
+	}
+	err = wallet.FinalizeTreasurySpend(tx, pubKeys, pubNonces, partialSigs)
+	if err != nil {
+		return nil, err
+	}
+
+	n, ok := s.walletLoader.NetworkBackend()
+	if !ok {
+		return nil, errNoNetwork
+	}
+	err = n.PublishTransactions(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.TxHash().String(), nil
+}
+
+// decodePSBT decodes a base64 encoded partially signed transaction.
+func decodePSBT(s string) (*psbt.Packet, error) {
+	p, err := psbt.ParseBase64(s)
+	if err != nil {
+		return nil, rpcError(VGLjson.ErrRPCDeserialization, err)
+	}
+	return p, nil
+}
+
+// psbtComplete returns whether all inputs of a partially signed transaction
+// have enough signatures to be finalized.
+func psbtComplete(p *psbt.Packet) bool {
+	next := p.Analyze().Next
+	return next == psbt.RoleFinalizer || next == psbt.RoleExtractor
+}
+
+// createPSBT handles a createpsbt request by wrapping an unsigned transaction
+// in a partially signed transaction and adding the previous outputs, redeem
+// scripts and key derivations known to the wallet.
+func (s *Server) createPSBT(ctx context.Context, icmd any) (any, error) {
+	cmd := icmd.(*types.CreatePSBTCmd)
+	w, ok := s.walletLoader.LoadedWallet()
+	if !ok {
+		return nil, errUnloadedWallet
+	}
+
+	tx := wire.NewMsgTx()
+	err := tx.Deserialize(hex.NewDecoder(strings.NewReader(cmd.RawTx)))
+	if err != nil {
+		return nil, rpcError(VGLjson.ErrRPCDeserialization, err)
+	}
+	p, err := psbt.New(tx)
+	if err != nil {
+		return nil, rpcError(VGLjson.ErrRPCInvalidParameter, err)
+	}
+	if err := w.UpdatePSBT(ctx, p); err != nil {
+		return nil, err
+	}
+	return p.Base64()
+}
+
+// analyzePSBT handles an analyzepsbt request by describing what each input of
+// a partially signed transaction is missing and which role must process it
+// next.
+func (s *Server) analyzePSBT(ctx context.Context, icmd any) (any, error) {
+	cmd := icmd.(*types.AnalyzePSBTCmd)
+
+	p, err := decodePSBT(cmd.PSBT)
+	if err != nil {
+		return nil, err
+	}
+	a := p.Analyze()
+	res := &types.AnalyzePSBTResult{
+		Inputs: make([]types.AnalyzePSBTInput, len(a.Inputs)),
+		Next:   string(a.Next),
+	}
+	for i, in := range a.Inputs {
+		res.Inputs[i] = types.AnalyzePSBTInput{
+			HasPrevOut:  in.HasPrevOut,
+			IsFinal:     in.IsFinal,
+			MissingSigs: in.MissingSigs,
+			Next:        string(in.Next),
+		}
+	}
+	if a.Fee >= 0 {
+		fee := VGLutil.Amount(a.Fee).ToCoin()
+		res.Fee = &fee
+	}
+	return res, nil
+}
+
+// signPSBT handles a signpsbt request by updating a partially signed
+// transaction with the data known to the wallet and adding the signatures of
+// all wallet keys that can sign its inputs.
+func (s *Server) signPSBT(ctx context.Context, icmd any) (any, error) {
+	cmd := icmd.(*types.SignPSBTCmd)
+	w, ok := s.walletLoader.LoadedWallet()
+	if !ok {
+		return nil, errUnloadedWallet
+	}
+
+	p, err := decodePSBT(cmd.PSBT)
+	if err != nil {
+		return nil, err
+	}
+	hashType, err := parseSigHashFlags(*cmd.Flags)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.UpdatePSBT(ctx, p); err != nil {
+		return nil, err
+	}
+	signed, err := w.SignPSBT(ctx, p, hashType)
+	if err != nil {
+		return nil, err
+	}
+	b64, err := p.Base64()
+	if err != nil {
+		return nil, err
+	}
+	return &types.SignPSBTResult{
+		PSBT:     b64,
+		Signed:   signed,
+		Complete: psbtComplete(p),
+	}, nil
+}
+
+// combinePSBT handles a combinepsbt request by merging partially signed
+// versions of the same transaction, such as the packets returned by different
+// signers of a multisig input.
+func (s *Server) combinePSBT(ctx context.Context, icmd any) (any, error) {
+	cmd := icmd.(*types.CombinePSBTCmd)
+
+	packets := make([]*psbt.Packet, 0, len(cmd.PSBTs))
+	for _, str := range cmd.PSBTs {
+		p, err := decodePSBT(str)
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, p)
+	}
+	combined, err := psbt.Combine(packets...)
+	if err != nil {
+		return nil, rpcError(VGLjson.ErrRPCInvalidParameter, err)
+	}
+	return combined.Base64()
+}
+
+// finalizePSBT handles a finalizepsbt request by creating the signature
+// scripts of all inputs with enough signatures.  When every input is final,
+// the scripts are verified and the signed transaction is extracted unless
+// requested otherwise.
+func (s *Server) finalizePSBT(ctx context.Context, icmd any) (any, error) {
+	cmd := icmd.(*types.FinalizePSBTCmd)
+
+	p, err := decodePSBT(cmd.PSBT)
+	if err != nil {
+		return nil, err
+	}
+	// An incomplete packet is not an error; the partially finalized packet
+	// is returned to collect the remaining signatures.
+	complete := p.Finalize() == nil
+	b64, err := p.Base64()
+	if err != nil {
+		return nil, err
+	}
+	res := &types.FinalizePSBTResult{
+		PSBT:     b64,
+		Complete: complete,
+	}
+	if complete && *cmd.Extract {
+		if err := wallet.VerifyPSBT(p); err != nil {
+			return nil, rpcError(VGLjson.ErrRPCInvalidParameter, err)
+		}
+		tx, err := p.Extract()
+		if err != nil {
+			return nil, err
+		}
+		b, err := tx.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		res.Hex = hex.EncodeToString(b)
+	}
+	return res, nil
+}
+
 // setTxFee sets the transaction fee per kilobyte added to transactions.
 func (s *Server) setTxFee(ctx context.Context, icmd any) (any, error) {
 	cmd := icmd.(*types.SetTxFeeCmd)
@@ -4809,6 +5163,30 @@ func (s *Server) signMessage(ctx context.Context, icmd any) (any, error) {
 	return base64.StdEncoding.EncodeToString(sig), nil
 }
 
+// parseSigHashFlags returns the signature hash type described by the sighash
+// flags parameter of signing requests.
+func parseSigHashFlags(flags string) (txscript.SigHashType, error) {
+	switch flags {
+	case "ALL":
+		return txscript.SigHashAll, nil
+	case "NONE":
+		return txscript.SigHashNone, nil
+	case "SINGLE":
+		return txscript.SigHashSingle, nil
+	case "ALL|ANYONECANPAY":
+		return txscript.SigHashAll | txscript.SigHashAnyOneCanPay, nil
+	case "NONE|ANYONECANPAY":
+		return txscript.SigHashNone | txscript.SigHashAnyOneCanPay, nil
+	case "SINGLE|ANYONECANPAY":
+		return txscript.SigHashSingle | txscript.SigHashAnyOneCanPay, nil
+	case "ssgen": // Special case of SigHashAll
+		return txscript.SigHashAll, nil
+	case "ssrtx": // Special case of SigHashAll
+		return txscript.SigHashAll, nil
+	}
+	return 0, rpcErrorf(VGLjson.ErrRPCInvalidParameter, "invalid sighash flag")
+}
+
 // signRawTransaction handles the signrawtransaction command.
 //
 // chainClient may be nil, in which case it was called by the NoChainRPC
@@ -4830,26 +5208,9 @@ func (s *Server) signRawTransaction(ctx context.Context, icmd any) (any, error)
 		return nil, rpcError(VGLjson.ErrRPCInvalidParameter, err)
 	}
 
-	var hashType txscript.SigHashType
-	switch *cmd.Flags {
-	case "ALL":
-		hashType = txscript.SigHashAll
-	case "NONE":
-		hashType = txscript.SigHashNone
-	case "SINGLE":
-		hashType = txscript.SigHashSingle
-	case "ALL|ANYONECANPAY":
-		hashType = txscript.SigHashAll | txscript.SigHashAnyOneCanPay
-	case "NONE|ANYONECANPAY":
-		hashType = txscript.SigHashNone | txscript.SigHashAnyOneCanPay
-	case "SINGLE|ANYONECANPAY":
-		hashType = txscript.SigHashSingle | txscript.SigHashAnyOneCanPay
-	case "ssgen": // Special case of SigHashAll
-		hashType = txscript.SigHashAll
-	case "ssrtx": // Special case of SigHashAll
-		hashType = txscript.SigHashAll
-	default:
-		return nil, rpcErrorf(VGLjson.ErrRPCInvalidParameter, "invalid sighash flag")
+	hashType, err := parseSigHashFlags(*cmd.Flags)
+	if err != nil {
+		return nil, err
 	}
 
 	// TODO: really we probably should look these up with vgld anyway to
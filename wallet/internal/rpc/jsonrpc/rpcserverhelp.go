@@ -10,16 +10,22 @@ func helpDescsEnUS() map[string]string {
 		"accountunlocked":           "accountunlocked \"account\"\n\nReport account encryption and locked status\n\nArguments:\n1. account (string, required) Account name\n\nResult:\n{\n \"encrypted\": true|false, (boolean) Whether the account is individually encrypted with a separate passphrase\n \"unlocked\": true|false,  (boolean) If the individually encrypted account is unlocked. Omitted for unencrypted accounts.\n}                         \n",
 		"addmultisigaddress":        "addmultisigaddress nrequired [\"key\",...] (\"account\")\n\nGenerates and imports a multisig address and redeeming script to the 'imported' account.\n\nArguments:\n1. nrequired (numeric, required)         The number of signatures required to redeem outputs paid to this address\n2. keys      (array of string, required) Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address\n3. account   (string, optional)          DEPRECATED -- Unused (all imported addresses belong to the imported account)\n\nResult:\n\"value\" (string) The imported pay-to-script-hash address\n",
 		"addtransaction":            "addtransaction \"blockhash\" \"transaction\"\n\nManually record a transaction mined in a main chain block\n\nArguments:\n1. blockhash   (string, required) Hash of block which mines transaction\n2. transaction (string, required) Hex-encoded serialized transaction\n\nResult:\nNothing\n",
+		"analyzepsbt":               "analyzepsbt \"psbt\"\n\nDescribe what the inputs of a partially signed transaction are missing and which role must process it next.\nThe roles are updater (createpsbt or signpsbt), signer (signpsbt), finalizer and extractor (finalizepsbt).\n\nArguments:\n1. psbt (string, required) The base64 encoded partially signed transaction\n\nResult:\n{\n \"inputs\": [{                (array of object) The state of each input\n  \"hasprevout\": true|false,  (boolean)         Whether the previous output spent by the input is known\n  \"isfinal\": true|false,     (boolean)         Whether the signature script of the input is complete\n  \"missingsigs\": true|false, (boolean)         Whether more signatures are required before the input can be finalized\n  \"next\": \"value\",           (string)          The next role that must process the input\n },...],                                       \n \"next\": \"value\",            (string)          The next role that must process the transaction\n \"fee\": n.nnn,               (numeric)         The transaction fee in Vigil, only set when the previous outputs of all inputs are known\n}                            \n",
 		"auditreuse":                "auditreuse (since)\n\nReports outputs identifying address reuse\n\nArguments:\n1. since (numeric, optional) Only report reusage since some main chain block height\n\nResult:\n{\n \"Array of outpoints referencing the reused address\": Reused address, (object) Object keying reused addresses to arrays of outpoint strings\n ...\n}\n",
+		"combinepsbt":               "combinepsbt [\"psbt\",...]\n\nMerge partially signed versions of the same transaction, such as the packets signed by different signers of a multisig input.\n\nArguments:\n1. psbts (array of string, required) The base64 encoded partially signed transactions to combine\n\nResult:\n\"value\" (string) The base64 encoded combined partially signed transaction\n",
 		"consolidate":               "consolidate inputs (\"account\" \"address\")\n\nConsolidate n many UTXOs into a single output in the wallet.\n\nArguments:\n1. inputs  (numeric, required) Number of UTXOs to consolidate as inputs\n2. account (string, optional)  Optional: Account from which unspent outputs are picked. When no address specified, also the account used to obtain an output address.\n3. address (string, optional)  Optional: Address to pay.  Default is obtained via getnewaddress from the account's address pool.\n\nResult:\n\"value\" (string) Transaction hash for the consolidation transaction\n",
 		"createmultisig":            "createmultisig nrequired [\"key\",...]\n\nGenerate a multisig address and redeem script.\n\nArguments:\n1. nrequired (numeric, required)         The number of signatures required to redeem outputs paid to this address\n2. keys      (array of string, required) Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address\n\nResult:\n{\n \"address\": \"value\",      (string) The generated pay-to-script-hash address\n \"redeemScript\": \"value\", (string) The script required to redeem outputs paid to the multisig address\n}                         \n",
 		"createnewaccount":          "createnewaccount \"account\"\n\nCreates a new account.\nThe wallet must be unlocked for this request to succeed.\n\nArguments:\n1. account (string, required) Name of the new account\n\nResult:\nNothing\n",
+		"createpsbt":                "createpsbt \"rawtx\"\n\nCreate a base64 encoded partially signed transaction for offline or multi-party signing from an unsigned transaction.\nThe previous outputs, redeem scripts and key derivations known to this wallet are added to the packet.\n\nArguments:\n1. rawtx (string, required) Unsigned transaction encoded as a hexadecimal string\n\nResult:\n\"value\" (string) The base64 encoded partially signed transaction\n",
 		"createrawtransaction":      "createrawtransaction [{\"amount\":n.nnn,\"txid\":\"value\",\"vout\":n,\"tree\":n},...] {\"address\":amount,...} (locktime expiry)\n\nReturns a new transaction spending the provided inputs and sending to the provided addresses.\nThe transaction inputs are not signed in the created transaction.\nThe signrawtransaction RPC command provided by wallet must be used to sign the resulting transaction.\n\nArguments:\n1. inputs (array of object, required) The inputs to the transaction\n[{\n \"amount\": n.nnn, (numeric) The previous output amount\n \"txid\": \"value\", (string)  The transaction hash of the referenced output\n \"vout\": n,       (numeric) The output index of the referenced output\n \"tree\": n,       (numeric) The tree to generate transaction for\n},...]\n2. amounts (object, required) JSON object with the destination addresses as keys and amounts as values\n{\n \"address\": n.nnn, (object) The destination address as the key and the amount in VGL as the value\n ...\n}\n3. locktime (numeric, optional) Locktime value; a non-zero value will also locktime-activate the inputs\n4. expiry   (numeric, optional) Expiry value; a non-zero value when the transaction expiry\n\nResult:\n\"value\" (string) Hex-encoded bytes of the serialized transaction\n",
 		"createsignature":           "createsignature \"address\" inputindex hashtype \"previouspkscript\" \"serializedtransaction\"\n\nGenerate a signature for a transaction input script.\n\nArguments:\n1. address               (string, required)  The address of the private key to use to create the signature.\n2. inputindex            (numeric, required) The index of the transaction input to sign.\n3. hashtype              (numeric, required) The signature hash flags to use.\n4. previouspkscript      (string, required)  The hex encoded previous output script or P2SH redeem script.\n5. serializedtransaction (string, required)  The hex encoded transaction to add input signatures to.\n\nResult:\n{\n \"signature\": \"value\", (string) The hex encoded signature.\n \"publickey\": \"value\", (string) The hex encoded serialized compressed pubkey of the address.\n}                      \n",
+		"createtreasuryspend":       "createtreasuryspend amounts\n\nCreate an unsigned treasury spend paying multiple recipients to be jointly signed by the holders of the treasury key shares.\n\nArguments:\n1. amounts (object, required) Pairs of payment addresses and the output amount to pay each\n{\n \"Address to pay\": Amount to send to the payment address valued in Vigil, (object) JSON object using payment addresses as keys and output amounts valued in Vigil to send to each address\n ...\n}\n\nResult:\n\"value\" (string) The hex encoded unsigned treasury spend\n",
 		"debuglevel":                "debuglevel \"levelspec\"\n\nDynamically changes the debug logging level.\nThe levelspec can either a debug level or of the form:\n<subsystem>=<level>,<subsystem2>=<level2>,...\nThe valid debug levels are trace, debug, info, warn, error, and critical.\nThe valid subsystems are CMGR, VGLW, GRPC, LODR, MIXC, MIXP, PEER, RPCS, SYNC, TKBY, VSPC, and WLLT.\nFinally the keyword 'show' will return a list of the available subsystems.\n\nArguments:\n1. levelspec (string, required) The debug level(s) to use or the keyword 'show'\n\nResult:\n\"value\" (string) The string 'Done.'\n",
 		"disapprovepercent":         "disapprovepercent\n\nReturns the wallet's current block disapprove percent per vote. i.e. 100 means that all votes disapprove the block they are called on. Only used for testing purposes.\n\nArguments:\nNone\n\nResult:\nn (numeric) The disapprove percent. When voting, this percent of votes will randomly disapprove the block they are called on.\n",
 		"discoverusage":             "discoverusage (\"startblock\" discoveraccounts gaplimit)\n\nPerform address and/or account discovery\n\nArguments:\n1. startblock       (string, optional)  Hash of block to begin discovery from, or null to scan from the genesis block\n2. discoveraccounts (boolean, optional) Perform account discovery in addition to address discovery.  Requires unlocked wallet.\n3. gaplimit         (numeric, optional) Allowed unused address gap.\n\nResult:\nNothing\n",
 		"dumpprivkey":               "dumpprivkey \"address\"\n\nReturns the private key in WIF encoding that controls some wallet address.\n\nArguments:\n1. address (string, required) The address to return a private key for\n\nResult:\n\"value\" (string) The WIF-encoded private key\n",
+		"finalizepsbt":              "finalizepsbt \"psbt\" (extract=true)\n\nCreate the signature scripts of all inputs of a partially signed transaction that have enough signatures.\nWhen all inputs are final, the scripts are verified and the signed transaction is extracted so it can be sent with sendrawtransaction.\n\nArguments:\n1. psbt    (string, required)                The base64 encoded partially signed transaction\n2. extract (boolean, optional, default=true) Extract the signed transaction when all inputs are final\n\nResult:\n{\n \"psbt\": \"value\",        (string)  The base64 encoded partially signed transaction with the finalized inputs\n \"hex\": \"value\",         (string)  The signed transaction encoded as a hexadecimal string, only set when the transaction is complete and was extracted\n \"complete\": true|false, (boolean) Whether all inputs are final\n}                        \n",
+		"finalizetreasuryspend":     "finalizetreasuryspend \"tx\" [\"pubkey\",...] [\"pubnonce\",...] [\"partialsig\",...]\n\nCombine the MuSig2 partial signatures of all treasury key shares into the treasury key signature of a treasury spend and send it.\n\nArguments:\n1. tx          (string, required)          The hex encoded unsigned treasury spend\n2. pubkeys     (array of string, required) Hex encoded public keys of all treasury key shares in the order they are aggregated in\n3. pubnonces   (array of string, required) Hex encoded public nonces of the signers in the same order as the public keys\n4. partialsigs (array of string, required) Hex encoded partial signatures of the signers in the same order as the public keys\n\nResult:\n\"value\" (string) The transaction hash of the sent treasury spend\n",
 		"fundrawtransaction":        "fundrawtransaction \"hexstring\" \"fundaccount\" ({\"changeaddress\":changeaddress,\"feerate\":feerate,\"conftarget\":conftarget})\n\nAdds unsigned inputs and change output to a raw transaction\n\nArguments:\n1. hexstring   (string, required) Serialized transaction in hex encoding\n2. fundaccount (string, required) Account of outputs to spend in transaction\n3. options     (object, optional) Object to specify fixed change address, alternative fee rate, and confirmation target\n{\n \"changeaddress\": \"value\", (string)  Provide a change address rather than deriving one from the funding account\n \"feerate\": n.nnn,         (numeric) Alternative fee rate\n \"conf_target\": n,         (numeric) Required confirmations of selected previous outputs\n}                          \n\nResult:\n{\n \"hex\": \"value\", (string)  Funded transaction in hex encoding\n \"fee\": n.nnn,   (numeric) Absolute fee of funded transaction\n}                \n",
 		"getaccount":                "getaccount \"address\"\n\nLookup the account name that some wallet address belongs to.\n\nArguments:\n1. address (string, required) The address to query the account for\n\nResult:\n\"value\" (string) The name of the account that 'address' belongs to\n",
 		"getaccountaddress":         "getaccountaddress \"account\"\n\nDEPRECATED -- Returns the most recent external payment address for an account that has not been seen publicly.\nA new address is generated for the account if the most recently generated address has been seen on the blockchain or in mempool.\n\nArguments:\n1. account (string, required) The account of the returned address\n\nResult:\n\"value\" (string) The unused address for 'account'\n",
@@ -88,13 +94,16 @@ func helpDescsEnUS() map[string]string {
 		"settxfee":                  "settxfee amount\n\nModify the fee per kB of the serialized tx size used each time more fee is required for an authored transaction.\n\nArguments:\n1. amount (numeric, required) The new fee per kB of the serialized tx size valued in Vigil\n\nResult:\ntrue|false (boolean) The boolean 'true'\n",
 		"setvotechoice":             "setvotechoice \"agendaid\" \"choiceid\" (\"tickethash\")\n\nSets choices for defined agendas in the latest stake version supported by this software\n\nArguments:\n1. agendaid   (string, required) The ID for the agenda to modify\n2. choiceid   (string, required) The ID for the choice to choose\n3. tickethash (string, optional) The hash of the ticket to set choices for\n\nResult:\nNothing\n",
 		"signmessage":               "signmessage \"address\" \"message\"\n\nSigns a message using the private key of a payment address.\n\nArguments:\n1. address (string, required) Payment address of private key used to sign the message with\n2. message (string, required) Message to sign\n\nResult:\n\"value\" (string) The signed message encoded as a base64 string\n",
+		"signpsbt":                  "signpsbt \"psbt\" (flags=\"ALL\")\n\nAdd the signatures of all keys of this wallet that can sign the inputs of a partially signed transaction.\nThe packet is first updated with the data known to this wallet.\nThe valid flags options are ALL, NONE, SINGLE, ALL|ANYONECANPAY, NONE|ANYONECANPAY, and SINGLE|ANYONECANPAY.\n\nArguments:\n1. psbt  (string, required)                The base64 encoded partially signed transaction\n2. flags (string, optional, default=\"ALL\") Sighash flags\n\nResult:\n{\n \"psbt\": \"value\",        (string)  The base64 encoded partially signed transaction with the added signatures\n \"signed\": n,            (numeric) The number of signatures created by this wallet\n \"complete\": true|false, (boolean) Whether all inputs have enough signatures to be finalized\n}                        \n",
 		"signrawtransaction":        "signrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\"},...] [\"privkey\",...] flags=\"ALL\")\n\nSigns transaction inputs using private keys from this wallet and request.\nThe valid flags options are ALL, NONE, SINGLE, ALL|ANYONECANPAY, NONE|ANYONECANPAY, and SINGLE|ANYONECANPAY.\n\nArguments:\n1. rawtx    (string, required)                Unsigned or partially unsigned transaction to sign encoded as a hexadecimal string\n2. inputs   (array of object, optional)       Additional data regarding inputs that this wallet may not be tracking\n3. privkeys (array of string, optional)       Additional WIF-encoded private keys to use when creating signatures\n4. flags    (string, optional, default=\"ALL\") Sighash flags\n\nResult:\n{\n \"hex\": \"value\",         (string)          The resulting transaction encoded as a hexadecimal string\n \"complete\": true|false, (boolean)         Whether all input signatures have been created\n \"errors\": [{            (array of object) Script verification errors (if exists)\n  \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n  \"vout\": n,             (numeric)         The output index of the referenced previous output\n  \"scriptSig\": \"value\",  (string)          The hex-encoded signature script\n  \"sequence\": n,         (numeric)         Script sequence number\n  \"error\": \"value\",      (string)          Verification or signing error related to the input\n },...],                                   \n}                        \n",
 		"signrawtransactions":       "signrawtransactions [\"rawtx\",...] (send=true)\n\nSigns transaction inputs using private keys from this wallet and request for a list of transactions.\n\n\nArguments:\n1. rawtxs (array of string, required)       A list of transactions to sign (and optionally send).\n2. send   (boolean, optional, default=true) Set true to send the transactions after signing.\n\nResult:\n{\n \"results\": [{             (array of object) Returned values from the signrawtransactions command.\n  \"signingresult\": {       (object)          Success or failure of signing.\n   \"hex\": \"value\",         (string)          The resulting transaction encoded as a hexadecimal string\n   \"complete\": true|false, (boolean)         Whether all input signatures have been created\n   \"errors\": [{            (array of object) Script verification errors (if exists)\n    \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output\n    \"vout\": n,             (numeric)         The output index of the referenced previous output\n    \"scriptSig\": \"value\",  (string)          The hex-encoded signature script\n    \"sequence\": n,         (numeric)         Script sequence number\n    \"error\": \"value\",      (string)          Verification or signing error related to the input\n   },...],                                   \n  },                                         \n  \"sent\": true|false,      (boolean)         Tells if the transaction was sent.\n  \"txhash\": \"value\",       (string)          The hash of the signed tx.\n },...],                                     \n}                          \n",
+		"signtreasuryspend":         "signtreasuryspend \"key\" \"tx\" [\"pubkey\",...] [\"pubnonce\",...]\n\nCreate the MuSig2 partial signature of an imported treasury key share for a treasury spend.\nThe nonce created by treasuryspendnonce is consumed and a new nonce must be created to sign again.\n\nArguments:\n1. key       (string, required)          Address of the imported treasury key share\n2. tx        (string, required)          The hex encoded unsigned treasury spend\n3. pubkeys   (array of string, required) Hex encoded public keys of all treasury key shares in the order they are aggregated in\n4. pubnonces (array of string, required) Hex encoded public nonces of all signers\n\nResult:\n\"value\" (string) The hex encoded partial signature\n",
 		"spendoutputs":              "spendoutputs \"account\" [\"previousoutpoint\",...] [{\"address\":\"value\",\"amount\":n.nnn},...]\n\nCreate, sign, and publish a transaction spending the specified wallet outputs, and paying an array of address/amount pairs.\nOutputs must belong to the specified account, and change (if needed) is returned to an internal address of the same account.\n\nArguments:\n1. account           (string, required)          Account of specified previous outpoints, and account used to return change\n2. previousoutpoints (array of string, required) Array of outpoints in string encoding (\"hash:index\")\n3. outputs           (array of object, required) Array of JSON objects, each specifying an address string and amount\n[{\n \"address\": \"value\", (string)  Address to pay\n \"amount\": n.nnn,    (numeric) Amount to pay the address\n},...]\n\nResult:\n\"value\" (string) The published transaction hash\n",
 		"sweepaccount":              "sweepaccount \"sourceaccount\" \"destinationaddress\" (requiredconfirmations feeperkb)\n\nMoves as much value as possible in a transaction from an account.\n\n\nArguments:\n1. sourceaccount         (string, required)  The account to be swept.\n2. destinationaddress    (string, required)  The destination address to pay to.\n3. requiredconfirmations (numeric, optional) The minimum utxo confirmation requirement (optional).\n4. feeperkb              (numeric, optional) The minimum relay fee policy (optional).\n\nResult:\n{\n \"unsignedtransaction\": \"value\",     (string)  The hex encoded string of the unsigned transaction.\n \"totalpreviousoutputamount\": n.nnn, (numeric) The total transaction input amount.\n \"totaloutputamount\": n.nnn,         (numeric) The total transaction output amount.\n \"estimatedsignedsize\": n,           (numeric) The estimated size of the transaction when signed.\n}                                    \n",
 		"syncstatus":                "syncstatus\n\nReturns information about this wallet's synchronization to the network.\n\nArguments:\nNone\n\nResult:\n{\n \"synced\": true|false,               (boolean) Whether or not the wallet is fully caught up to the network.\n \"initialblockdownload\": true|false, (boolean) Best guess of whether this wallet is in the initial block download mode used to catch up the blockchain when it is far behind.\n \"headersfetchprogress\": n.nnn,      (numeric) Estimated progress of the headers fetching stage of the current sync process.\n}                                    \n",
 		"ticketinfo":                "ticketinfo (startheight=0)\n\nReturns details of each wallet ticket transaction\n\nArguments:\n1. startheight (numeric, optional, default=0) Specify the starting block height to scan from\n\nResult:\n[{\n \"hash\": \"value\",               (string)          Transaction hash of the ticket\n \"cost\": n.nnn,                 (numeric)         Amount paid to purchase the ticket; this may be greater than the ticket price at time of purchase\n \"votingaddress\": \"value\",      (string)          Address of 0th output, which describes the requirements to spend the ticket\n \"status\": \"value\",             (string)          Description of ticket status (unknown, unmined, immature, mature, live, voted, missed, expired, unspent, revoked)\n \"blockhash\": \"value\",          (string)          Hash of block ticket is mined in\n \"blockheight\": n,              (numeric)         Height of block ticket is mined in\n \"vote\": \"value\",               (string)          Transaction hash of vote which spends the ticket\n \"revocation\": \"value\",         (string)          Transaction hash of revocation which spends the ticket\n \"choices\": [{                  (array of object) Vote preferences set for the ticket\n  \"agendaid\": \"value\",          (string)          The ID for the agenda the choice concerns\n  \"agendadescription\": \"value\", (string)          A description of the agenda the choice concerns\n  \"choiceid\": \"value\",          (string)          The ID of the current choice for this agenda\n  \"choicedescription\": \"value\", (string)          A description of the current choice for this agenda\n },...],                                          \n \"vsphost\": \"value\",            (string)          VSP Host associated with the ticket (if any)\n},...]\n",
 		"treasurypolicy":            "treasurypolicy (\"key\" \"ticket\")\n\nReturn voting policies for treasury spend transactions by key\n\nArguments:\n1. key    (string, optional) Return the policy for a particular key\n2. ticket (string, optional) Return policies used by a specific ticket hash\n\nResult (no key provided):\n[{\n \"key\": \"value\",    (string) Treasury key associated with a policy\n \"policy\": \"value\", (string) Voting policy description (abstain, yes, or no)\n \"ticket\": \"value\", (string) Ticket hash of a per-ticket treasury key approval policy\n},...]\n\nResult (key specified):\n{\n \"key\": \"value\",    (string) Treasury key associated with a policy\n \"policy\": \"value\", (string) Voting policy description (abstain, yes, or no)\n \"ticket\": \"value\", (string) Ticket hash of a per-ticket treasury key approval policy\n}                   \n",
+		"treasuryspendnonce":        "treasuryspendnonce \"key\" \"tx\"\n\nCreate the MuSig2 nonce of an imported treasury key share for a treasury spend.\nThe secret nonce is kept in memory until signtreasuryspend is called and is lost when the wallet restarts.\n\nArguments:\n1. key (string, required) Address of the imported treasury key share\n2. tx  (string, required) The hex encoded unsigned treasury spend\n\nResult:\n\"value\" (string) The hex encoded public nonce to share with the other signers\n",
 		"tspendpolicy":              "tspendpolicy (\"hash\" \"ticket\")\n\nReturn voting policies for treasury spend transactions\n\nArguments:\n1. hash   (string, optional) Return the policy for a particular tspend hash\n2. ticket (string, optional) Return policies used by a specific ticket hash\n\nResult (no tspend hash provided):\n[{\n \"hash\": \"value\",   (string) Treasury spend transaction hash\n \"policy\": \"value\", (string) Voting policy description (abstain, yes, or no)\n \"ticket\": \"value\", (string) Ticket hash of a per-ticket tspend approval policy\n},...]\n\nResult (tspend hash specified):\n{\n \"hash\": \"value\",   (string) Treasury spend transaction hash\n \"policy\": \"value\", (string) Voting policy description (abstain, yes, or no)\n \"ticket\": \"value\", (string) Ticket hash of a per-ticket tspend approval policy\n}                   \n",
 		"unlockaccount":             "unlockaccount \"account\" \"passphrase\"\n\nUnlock an individually-encrypted account\n\nArguments:\n1. account    (string, required) Account to unlock\n2. passphrase (string, required) Account passphrase\n\nResult:\nNothing\n",
 		"validateaddress":           "validateaddress \"address\"\n\nVerify that an address is valid.\nExtra details are returned if the address is controlled by this wallet.\nThe following fields are valid only when the address is controlled by this wallet (ismine=true): pubkey, account, addresses, hex, and sigsrequired.\nThe following fields are only valid when address has an associated public key: pubkey, iscompressed.\nThe following fields are only valid when address is a pay-to-script-hash address: addresses, hex, and script.\nIf the address is a multisig address controlled by this wallet, the multisig fields will be left unset if the wallet is locked since the redeem script cannot be decrypted.\n\nArguments:\n1. address (string, required) Address to validate\n\nResult:\n{\n \"isvalid\": true|false,      (boolean)         Whether or not the address is valid\n \"address\": \"value\",         (string)          The payment address (only when isvalid is true)\n \"ismine\": true|false,       (boolean)         Whether this address is controlled by the wallet (only when isvalid is true)\n \"iswatchonly\": true|false,  (boolean)         Unset\n \"isscript\": true|false,     (boolean)         Whether the payment address is a pay-to-script-hash address (only when isvalid is true)\n \"pubkeyaddr\": \"value\",      (string)          The pubkey for this payment address (only when isvalid is true)\n \"pubkey\": \"value\",          (string)          The associated public key of the payment address, if any (only when isvalid is true)\n \"iscompressed\": true|false, (boolean)         Whether the address was created by hashing a compressed public key, if any (only when isvalid is true)\n \"account\": \"value\",         (string)          The account this payment address belongs to (only when isvalid is true)\n \"addresses\": [\"value\",...], (array of string) All associated payment addresses of the script if address is a multisig address (only when isvalid is true)\n \"hex\": \"value\",             (string)          The redeem script \n \"script\": \"value\",          (string)          The class of redeem script for a multisig address\n \"sigsrequired\": n,          (numeric)         The number of required signatures to redeem outputs to the multisig address\n \"accountn\": n,              (numeric)         The account number. This number plus 2 ^ 31 is the HD account the address was derived from. Not available for imported accounts. Only present for BIP0044 derived addresses.\n \"branch\": n,                (numeric)         The HD branch. Only present for BIP0044 derived addresses.\n \"index\": n,                 (numeric)         The HD index. Only present for BIP0044 derived addresses.\n}                            \n",
@@ -114,4 +123,4 @@ var localeHelpDescs = map[string]func() map[string]string{
 	"en_US": helpDescsEnUS,
 }
 
-var requestUsages = "abandontransaction \"hash\"\naccountaddressindex \"account\" branch\naccountsyncaddressindex \"account\" branch index\naccountunlocked \"account\"\naddmultisigaddress nrequired [\"key\",...] (\"account\")\naddtransaction \"blockhash\" \"transaction\"\nauditreuse (since)\nconsolidate inputs (\"account\" \"address\")\ncreatemultisig nrequired [\"key\",...]\ncreatenewaccount \"account\"\ncreaterawtransaction [{\"amount\":n.nnn,\"txid\":\"value\",\"vout\":n,\"tree\":n},...] {\"address\":amount,...} (locktime expiry)\ncreatesignature \"address\" inputindex hashtype \"previouspkscript\" \"serializedtransaction\"\ndebuglevel \"levelspec\"\ndisapprovepercent\ndiscoverusage (\"startblock\" discoveraccounts gaplimit)\ndumpprivkey \"address\"\nfundrawtransaction \"hexstring\" \"fundaccount\" ({\"changeaddress\":changeaddress,\"feerate\":feerate,\"conftarget\":conftarget})\ngetaccount \"address\"\ngetaccountaddress \"account\"\ngetaddressesbyaccount \"account\"\ngetbalance (\"account\" minconf=1)\ngetbestblock\ngetbestblockhash\ngetblockcount\ngetblockhash index\ngetblockheader \"hash\" (verbose=true)\ngetblock \"hash\" (verbose=true verbosetx=false)\ngetcoinjoinsbyacct\ngetcurrentnet\ngetinfo\ngetmasterpubkey (\"account\")\ngetmultisigoutinfo \"hash\" index\ngetnewaddress (\"account\" \"gappolicy\")\ngetpeerinfo\ngetrawchangeaddress (\"account\")\ngetreceivedbyaccount \"account\" (minconf=1)\ngetreceivedbyaddress \"address\" (minconf=1)\ngetstakeinfo\ngettickets includeimmature\ngettransaction \"txid\" (includewatchonly=false)\ngettxout \"txid\" vout tree (includemempool=true)\ngetunconfirmedbalance (\"account\")\ngetvotechoices (\"tickethash\")\ngetwalletfee\ngetcfilterv2 \"blockhash\"\nhelp (\"command\")\nimportcfiltersv2 startheight [\"filter\",...]\nimportprivkey \"privkey\" (\"label\" rescan=true scanfrom)\nimportpubkey \"pubkey\" (\"label\" rescan=true scanfrom)\nimportscript \"hex\" (rescan=true scanfrom)\nimportxpub \"name\" \"xpub\"\nlistaccounts (minconf=1)\nlistaddresstransactions [\"address\",...] (\"account\")\nlistalltransactions (\"account\")\nlistlockunspent (\"account\")\nlistreceivedbyaccount (minconf=1 includeempty=false includewatchonly=false)\nlistreceivedbyaddress (minconf=1 includeempty=false includewatchonly=false)\nlistsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false)\nlisttransactions (\"account\" count=10 from=0 includewatchonly=false)\nlistunspent (minconf=1 maxconf=9999999 [\"address\",...] \"account\")\nlockaccount \"account\"\nlockunspent unlock [{\"amount\":n.nnn,\"txid\":\"value\",\"vout\":n,\"tree\":n},...]\nmixaccount\nmixoutput \"outpoint\"\nprocessunmanagedticket \"tickethash\"\npurchaseticket \"fromaccount\" spendlimit (minconf=1 numtickets=1 expiry \"comment\" dontsigntx)\nredeemmultisigout \"hash\" index tree (\"address\")\nredeemmultisigouts \"fromscraddress\" (\"toaddress\" number)\nrenameaccount \"oldaccount\" \"newaccount\"\nrescanwallet (beginheight=0)\nsendfrom \"fromaccount\" \"toaddress\" amount (minconf=1 \"comment\" \"commentto\")\nsendfromtreasury \"key\" amounts\nsendmany \"fromaccount\" {\"address\":amount,...} (minconf=1 \"comment\")\nsendrawtransaction \"hextx\" (allowhighfees=false)\nsendtoaddress \"address\" amount (\"comment\" \"commentto\")\nsendtomultisig \"fromaccount\" amount [\"pubkey\",...] (nrequired=1 minconf=1 \"comment\")\nsendtotreasury amount\nsetaccountpassphrase \"account\" \"passphrase\"\nsetdisapprovepercent percent\nsettreasurypolicy \"key\" \"policy\" (\"ticket\")\nsettspendpolicy \"hash\" \"policy\" (\"ticket\")\nsettxfee amount\nsetvotechoice \"agendaid\" \"choiceid\" (\"tickethash\")\nsignmessage \"address\" \"message\"\nsignrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\"},...] [\"privkey\",...] flags=\"ALL\")\nsignrawtransactions [\"rawtx\",...] (send=true)\nspendoutputs \"account\" [\"previousoutpoint\",...] [{\"address\":\"value\",\"amount\":n.nnn},...]\nsweepaccount \"sourceaccount\" \"destinationaddress\" (requiredconfirmations feeperkb)\nsyncstatus\nticketinfo (startheight=0)\ntreasurypolicy (\"key\" \"ticket\")\ntspendpolicy (\"hash\" \"ticket\")\nunlockaccount \"account\" \"passphrase\"\nvalidateaddress \"address\"\nvalidatepreVGLP0005cf\nverifymessage \"address\" \"signature\" \"message\"\nversion\nwalletinfo\nwalletislocked\nwalletlock\nwalletpassphrase \"passphrase\" timeout\nwalletpassphrasechange \"oldpassphrase\" \"newpassphrase\"\nwalletpubpassphrasechange \"oldpassphrase\" \"newpassphrase\""
+var requestUsages = "abandontransaction \"hash\"\naccountaddressindex \"account\" branch\naccountsyncaddressindex \"account\" branch index\naccountunlocked \"account\"\naddmultisigaddress nrequired [\"key\",...] (\"account\")\naddtransaction \"blockhash\" \"transaction\"\nanalyzepsbt \"psbt\"\nauditreuse (since)\ncombinepsbt [\"psbt\",...]\nconsolidate inputs (\"account\" \"address\")\ncreatemultisig nrequired [\"key\",...]\ncreatenewaccount \"account\"\ncreatepsbt \"rawtx\"\ncreaterawtransaction [{\"amount\":n.nnn,\"txid\":\"value\",\"vout\":n,\"tree\":n},...] {\"address\":amount,...} (locktime expiry)\ncreatesignature \"address\" inputindex hashtype \"previouspkscript\" \"serializedtransaction\"\ncreatetreasuryspend amounts\ndebuglevel \"levelspec\"\ndisapprovepercent\ndiscoverusage (\"startblock\" discoveraccounts gaplimit)\ndumpprivkey \"address\"\nfinalizepsbt \"psbt\" (extract=true)\nfinalizetreasuryspend \"tx\" [\"pubkey\",...] [\"pubnonce\",...] [\"partialsig\",...]\nfundrawtransaction \"hexstring\" \"fundaccount\" ({\"changeaddress\":changeaddress,\"feerate\":feerate,\"conftarget\":conftarget})\ngetaccount \"address\"\ngetaccountaddress \"account\"\ngetaddressesbyaccount \"account\"\ngetbalance (\"account\" minconf=1)\ngetbestblock\ngetbestblockhash\ngetblockcount\ngetblockhash index\ngetblockheader \"hash\" (verbose=true)\ngetblock \"hash\" (verbose=true verbosetx=false)\ngetcoinjoinsbyacct\ngetcurrentnet\ngetinfo\ngetmasterpubkey (\"account\")\ngetmultisigoutinfo \"hash\" index\ngetnewaddress (\"account\" \"gappolicy\")\ngetpeerinfo\ngetrawchangeaddress (\"account\")\ngetreceivedbyaccount \"account\" (minconf=1)\ngetreceivedbyaddress \"address\" (minconf=1)\ngetstakeinfo\ngettickets includeimmature\ngettransaction \"txid\" (includewatchonly=false)\ngettxout \"txid\" vout tree (includemempool=true)\ngetunconfirmedbalance (\"account\")\ngetvotechoices (\"tickethash\")\ngetwalletfee\ngetcfilterv2 \"blockhash\"\nhelp (\"command\")\nimportcfiltersv2 startheight [\"filter\",...]\nimportprivkey \"privkey\" (\"label\" rescan=true scanfrom)\nimportpubkey \"pubkey\" (\"label\" rescan=true scanfrom)\nimportscript \"hex\" (rescan=true scanfrom)\nimportxpub \"name\" \"xpub\"\nlistaccounts (minconf=1)\nlistaddresstransactions [\"address\",...] (\"account\")\nlistalltransactions (\"account\")\nlistlockunspent (\"account\")\nlistreceivedbyaccount (minconf=1 includeempty=false includewatchonly=false)\nlistreceivedbyaddress (minconf=1 includeempty=false includewatchonly=false)\nlistsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false)\nlisttransactions (\"account\" count=10 from=0 includewatchonly=false)\nlistunspent (minconf=1 maxconf=9999999 [\"address\",...] \"account\")\nlockaccount \"account\"\nlockunspent unlock [{\"amount\":n.nnn,\"txid\":\"value\",\"vout\":n,\"tree\":n},...]\nmixaccount\nmixoutput \"outpoint\"\nprocessunmanagedticket \"tickethash\"\npurchaseticket \"fromaccount\" spendlimit (minconf=1 numtickets=1 expiry \"comment\" dontsigntx)\nredeemmultisigout \"hash\" index tree (\"address\")\nredeemmultisigouts \"fromscraddress\" (\"toaddress\" number)\nrenameaccount \"oldaccount\" \"newaccount\"\nrescanwallet (beginheight=0)\nsendfrom \"fromaccount\" \"toaddress\" amount (minconf=1 \"comment\" \"commentto\")\nsendfromtreasury \"key\" amounts\nsendmany \"fromaccount\" {\"address\":amount,...} (minconf=1 \"comment\")\nsendrawtransaction \"hextx\" (allowhighfees=false)\nsendtoaddress \"address\" amount (\"comment\" \"commentto\")\nsendtomultisig \"fromaccount\" amount [\"pubkey\",...] (nrequired=1 minconf=1 \"comment\")\nsendtotreasury amount\nsetaccountpassphrase \"account\" \"passphrase\"\nsetdisapprovepercent percent\nsettreasurypolicy \"key\" \"policy\" (\"ticket\")\nsettspendpolicy \"hash\" \"policy\" (\"ticket\")\nsettxfee amount\nsetvotechoice \"agendaid\" \"choiceid\" (\"tickethash\")\nsignmessage \"address\" \"message\"\nsignpsbt \"psbt\" (flags=\"ALL\")\nsignrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"tree\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\"},...] [\"privkey\",...] flags=\"ALL\")\nsignrawtransactions [\"rawtx\",...] (send=true)\nsigntreasuryspend \"key\" \"tx\" [\"pubkey\",...] [\"pubnonce\",...]\nspendoutputs \"account\" [\"previousoutpoint\",...] [{\"address\":\"value\",\"amount\":n.nnn},...]\nsweepaccount \"sourceaccount\" \"destinationaddress\" (requiredconfirmations feeperkb)\nsyncstatus\nticketinfo (startheight=0)\ntreasurypolicy (\"key\" \"ticket\")\ntreasuryspendnonce \"key\" \"tx\"\ntspendpolicy (\"hash\" \"ticket\")\nunlockaccount \"account\" \"passphrase\"\nvalidateaddress \"address\"\nvalidatepreVGLP0005cf\nverifymessage \"address\" \"signature\" \"message\"\nversion\nwalletinfo\nwalletislocked\nwalletlock\nwalletpassphrase \"passphrase\" timeout\nwalletpassphrasechange \"oldpassphrase\" \"newpassphrase\"\nwalletpubpassphrasechange \"oldpassphrase\" \"newpassphrase\""
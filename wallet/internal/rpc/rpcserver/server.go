@@ -40,6 +40,7 @@ import (
 	"github.com/kdsmith18542/vigil/wallet/spv"
 	"github.com/kdsmith18542/vigil/wallet/ticketbuyer"
 	"github.com/kdsmith18542/vigil/wallet/wallet"
+	"github.com/kdsmith18542/vigil/wallet/wallet/psbt"
 	"github.com/kdsmith18542/vigil/wallet/wallet/txauthor"
 	"github.com/kdsmith18542/vigil/wallet/wallet/txrules"
 	"github.com/kdsmith18542/vigil/wallet/wallet/udb"
@@ -61,9 +62,9 @@ import (
 
 // Public API version constants
 const (
-	semverString = "9.0.0"
+	semverString = "9.1.0"
 	semverMajor  = 9
-	semverMinor  = 0
+	semverMinor  = 1
 	semverPatch  = 0
 )
 
@@ -1745,6 +1746,168 @@ func (s *walletServer) CreateSignature(ctx context.Context, req *pb.CreateSignat
 	return &pb.CreateSignatureResponse{Signature: sig, PublicKey: pubkey}, nil
 }
 
+// psbtRoles maps the roles of partially signed transactions to their RPC
+// enum values.
+var psbtRoles = map[psbt.Role]pb.AnalyzePSBTResponse_Role{
+	psbt.RoleUpdater:   pb.AnalyzePSBTResponse_UPDATER,
+	psbt.RoleSigner:    pb.AnalyzePSBTResponse_SIGNER,
+	psbt.RoleFinalizer: pb.AnalyzePSBTResponse_FINALIZER,
+	psbt.RoleExtractor: pb.AnalyzePSBTResponse_EXTRACTOR,
+}
+
+func parsePSBT(b []byte) (*psbt.Packet, error) {
+	p, err := psbt.Parse(bytes.NewReader(b))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Bytes do not represent a valid partially signed transaction: %v", err)
+	}
+	return p, nil
+}
+
+func (s *walletServer) CreatePSBT(ctx context.Context, req *pb.CreatePSBTRequest) (
+	*pb.CreatePSBTResponse, error) {
+
+	var tx wire.MsgTx
+	err := tx.Deserialize(bytes.NewReader(req.SerializedTransaction))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Bytes do not represent a valid raw transaction: %v", err)
+	}
+	p, err := psbt.New(&tx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	err = s.wallet.UpdatePSBT(ctx, p)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	b, err := p.Bytes()
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &pb.CreatePSBTResponse{Psbt: b}, nil
+}
+
+func (s *walletServer) AnalyzePSBT(ctx context.Context, req *pb.AnalyzePSBTRequest) (
+	*pb.AnalyzePSBTResponse, error) {
+
+	p, err := parsePSBT(req.Psbt)
+	if err != nil {
+		return nil, err
+	}
+	a := p.Analyze()
+	resp := &pb.AnalyzePSBTResponse{
+		Inputs: make([]*pb.AnalyzePSBTResponse_Input, len(a.Inputs)),
+		Next:   psbtRoles[a.Next],
+		Fee:    a.Fee,
+	}
+	for i, in := range a.Inputs {
+		resp.Inputs[i] = &pb.AnalyzePSBTResponse_Input{
+			HasPreviousOutput: in.HasPrevOut,
+			IsFinal:           in.IsFinal,
+			MissingSignatures: in.MissingSigs,
+			Next:              psbtRoles[in.Next],
+		}
+	}
+	return resp, nil
+}
+
+func (s *walletServer) SignPSBT(ctx context.Context, req *pb.SignPSBTRequest) (
+	*pb.SignPSBTResponse, error) {
+
+	defer zero(req.Passphrase)
+
+	p, err := parsePSBT(req.Psbt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Passphrase) > 0 {
+		lock := make(chan time.Time, 1)
+		defer func() {
+			lock <- time.Time{} // send matters, not the value
+		}()
+		err := s.wallet.Unlock(ctx, req.Passphrase, lock)
+		if err != nil {
+			return nil, translateError(err)
+		}
+	}
+
+	err = s.wallet.UpdatePSBT(ctx, p)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	signed, err := s.wallet.SignPSBT(ctx, p, txscript.SigHashAll)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	b, err := p.Bytes()
+	if err != nil {
+		return nil, translateError(err)
+	}
+	next := p.Analyze().Next
+	return &pb.SignPSBTResponse{
+		Psbt:       b,
+		Signatures: uint32(signed),
+		Complete:   next == psbt.RoleFinalizer || next == psbt.RoleExtractor,
+	}, nil
+}
+
+func (s *walletServer) CombinePSBT(ctx context.Context, req *pb.CombinePSBTRequest) (
+	*pb.CombinePSBTResponse, error) {
+
+	packets := make([]*psbt.Packet, 0, len(req.Psbts))
+	for _, b := range req.Psbts {
+		p, err := parsePSBT(b)
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, p)
+	}
+	combined, err := psbt.Combine(packets...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	b, err := combined.Bytes()
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &pb.CombinePSBTResponse{Psbt: b}, nil
+}
+
+func (s *walletServer) FinalizePSBT(ctx context.Context, req *pb.FinalizePSBTRequest) (
+	*pb.FinalizePSBTResponse, error) {
+
+	p, err := parsePSBT(req.Psbt)
+	if err != nil {
+		return nil, err
+	}
+	complete := p.Finalize() == nil
+	b, err := p.Bytes()
+	if err != nil {
+		return nil, translateError(err)
+	}
+	resp := &pb.FinalizePSBTResponse{
+		Psbt:     b,
+		Complete: complete,
+	}
+	if complete {
+		err := wallet.VerifyPSBT(p)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		tx, err := p.Extract()
+		if err != nil {
+			return nil, translateError(err)
+		}
+		resp.Transaction, err = tx.Bytes()
+		if err != nil {
+			return nil, translateError(err)
+		}
+	}
+	return resp, nil
+}
+
 func (s *walletServer) PublishTransaction(ctx context.Context, req *pb.PublishTransactionRequest) (
 	*pb.PublishTransactionResponse, error) {
 
@@ -744,6 +744,88 @@ var helpDescsEnUS = map[string]string{
 	"sendfromtreasury-amounts--value": "Amount to send to the payment address valued in Vigil",
 	"sendfromtreasury--result0":       "The transaction hash of the sent transaction",
 
+	// CreateTreasurySpendCmd help.
+	"createtreasuryspend--synopsis":      "Create an unsigned treasury spend paying multiple recipients to be jointly signed by the holders of the treasury key shares.",
+	"createtreasuryspend-amounts":        "Pairs of payment addresses and the output amount to pay each",
+	"createtreasuryspend-amounts--desc":  "JSON object using payment addresses as keys and output amounts valued in Vigil to send to each address",
+	"createtreasuryspend-amounts--key":   "Address to pay",
+	"createtreasuryspend-amounts--value": "Amount to send to the payment address valued in Vigil",
+	"createtreasuryspend--result0":       "The hex encoded unsigned treasury spend",
+
+	// TreasurySpendNonceCmd help.
+	"treasuryspendnonce--synopsis": "Create the MuSig2 nonce of an imported treasury key share for a treasury spend.\n" +
+		"The secret nonce is kept in memory until signtreasuryspend is called and is lost when the wallet restarts.",
+	"treasuryspendnonce-key":      "Address of the imported treasury key share",
+	"treasuryspendnonce-tx":       "The hex encoded unsigned treasury spend",
+	"treasuryspendnonce--result0": "The hex encoded public nonce to share with the other signers",
+
+	// SignTreasurySpendCmd help.
+	"signtreasuryspend--synopsis": "Create the MuSig2 partial signature of an imported treasury key share for a treasury spend.\n" +
+		"The nonce created by treasuryspendnonce is consumed and a new nonce must be created to sign again.",
+	"signtreasuryspend-key":       "Address of the imported treasury key share",
+	"signtreasuryspend-tx":        "The hex encoded unsigned treasury spend",
+	"signtreasuryspend-pubkeys":   "Hex encoded public keys of all treasury key shares in the order they are aggregated in",
+	"signtreasuryspend-pubnonces": "Hex encoded public nonces of all signers",
+	"signtreasuryspend--result0":  "The hex encoded partial signature",
+
+	// FinalizeTreasurySpendCmd help.
+	"finalizetreasuryspend--synopsis":   "Combine the MuSig2 partial signatures of all treasury key shares into the treasury key signature of a treasury spend and send it.",
+	"finalizetreasuryspend-tx":          "The hex encoded unsigned treasury spend",
+	"finalizetreasuryspend-pubkeys":     "Hex encoded public keys of all treasury key shares in the order they are aggregated in",
+	"finalizetreasuryspend-pubnonces":   "Hex encoded public nonces of the signers in the same order as the public keys",
+	"finalizetreasuryspend-partialsigs": "Hex encoded partial signatures of the signers in the same order as the public keys",
+	"finalizetreasuryspend--result0":    "The transaction hash of the sent treasury spend",
+
+	// CreatePSBTCmd help.
+	"createpsbt--synopsis": "Create a base64 encoded partially signed transaction for offline or multi-party signing from an unsigned transaction.\n" +
+		"The previous outputs, redeem scripts and key derivations known to this wallet are added to the packet.",
+	"createpsbt-rawtx":    "Unsigned transaction encoded as a hexadecimal string",
+	"createpsbt--result0": "The base64 encoded partially signed transaction",
+
+	// AnalyzePSBTCmd help.
+	"analyzepsbt--synopsis": "Describe what the inputs of a partially signed transaction are missing and which role must process it next.\n" +
+		"The roles are updater (createpsbt or signpsbt), signer (signpsbt), finalizer and extractor (finalizepsbt).",
+	"analyzepsbt-psbt": "The base64 encoded partially signed transaction",
+
+	// AnalyzePSBTResult help.
+	"analyzepsbtresult-inputs": "The state of each input",
+	"analyzepsbtresult-next":   "The next role that must process the transaction",
+	"analyzepsbtresult-fee":    "The transaction fee in Vigil, only set when the previous outputs of all inputs are known",
+
+	// AnalyzePSBTInput help.
+	"analyzepsbtinput-hasprevout":  "Whether the previous output spent by the input is known",
+	"analyzepsbtinput-isfinal":     "Whether the signature script of the input is complete",
+	"analyzepsbtinput-missingsigs": "Whether more signatures are required before the input can be finalized",
+	"analyzepsbtinput-next":        "The next role that must process the input",
+
+	// SignPSBTCmd help.
+	"signpsbt--synopsis": "Add the signatures of all keys of this wallet that can sign the inputs of a partially signed transaction.\n" +
+		"The packet is first updated with the data known to this wallet.\n" +
+		"The valid flags options are ALL, NONE, SINGLE, ALL|ANYONECANPAY, NONE|ANYONECANPAY, and SINGLE|ANYONECANPAY.",
+	"signpsbt-psbt":  "The base64 encoded partially signed transaction",
+	"signpsbt-flags": "Sighash flags",
+
+	// SignPSBTResult help.
+	"signpsbtresult-psbt":     "The base64 encoded partially signed transaction with the added signatures",
+	"signpsbtresult-signed":   "The number of signatures created by this wallet",
+	"signpsbtresult-complete": "Whether all inputs have enough signatures to be finalized",
+
+	// CombinePSBTCmd help.
+	"combinepsbt--synopsis": "Merge partially signed versions of the same transaction, such as the packets signed by different signers of a multisig input.",
+	"combinepsbt-psbts":     "The base64 encoded partially signed transactions to combine",
+	"combinepsbt--result0":  "The base64 encoded combined partially signed transaction",
+
+	// FinalizePSBTCmd help.
+	"finalizepsbt--synopsis": "Create the signature scripts of all inputs of a partially signed transaction that have enough signatures.\n" +
+		"When all inputs are final, the scripts are verified and the signed transaction is extracted so it can be sent with sendrawtransaction.",
+	"finalizepsbt-psbt":    "The base64 encoded partially signed transaction",
+	"finalizepsbt-extract": "Extract the signed transaction when all inputs are final",
+
+	// FinalizePSBTResult help.
+	"finalizepsbtresult-psbt":     "The base64 encoded partially signed transaction with the finalized inputs",
+	"finalizepsbtresult-hex":      "The signed transaction encoded as a hexadecimal string, only set when the transaction is complete and was extracted",
+	"finalizepsbtresult-complete": "Whether all inputs are final",
+
 	// SendManyCmd help.
 	"sendmany--synopsis": "Authors, signs, and sends a transaction that outputs to many payment addresses.\n" +
 		"A change output is automatically included to send extra output value back to the original account.",
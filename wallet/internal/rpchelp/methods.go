@@ -33,16 +33,22 @@ var Methods = []struct {
 	{"accountunlocked", []any{(*types.AccountUnlockedResult)(nil)}},
 	{"addmultisigaddress", returnsString},
 	{"addtransaction", nil},
+	{"analyzepsbt", []any{(*types.AnalyzePSBTResult)(nil)}},
 	{"auditreuse", []any{(*map[string][]string)(nil)}},
+	{"combinepsbt", returnsString},
 	{"consolidate", returnsString},
 	{"createmultisig", []any{(*types.CreateMultiSigResult)(nil)}},
 	{"createnewaccount", nil},
+	{"createpsbt", returnsString},
 	{"createrawtransaction", returnsString},
 	{"createsignature", []any{(*types.CreateSignatureResult)(nil)}},
+	{"createtreasuryspend", returnsString},
 	{"debuglevel", returnsString},
 	{"disapprovepercent", []any{(*uint32)(nil)}},
 	{"discoverusage", nil},
 	{"dumpprivkey", returnsString},
+	{"finalizepsbt", []any{(*types.FinalizePSBTResult)(nil)}},
+	{"finalizetreasuryspend", returnsString},
 	{"fundrawtransaction", []any{(*types.FundRawTransactionResult)(nil)}},
 	{"getaccount", returnsString},
 	{"getaccountaddress", returnsString},
@@ -111,13 +117,16 @@ var Methods = []struct {
 	{"settxfee", returnsBool},
 	{"setvotechoice", nil},
 	{"signmessage", returnsString},
+	{"signpsbt", []any{(*types.SignPSBTResult)(nil)}},
 	{"signrawtransaction", []any{(*types.SignRawTransactionResult)(nil)}},
 	{"signrawtransactions", []any{(*types.SignRawTransactionsResult)(nil)}},
+	{"signtreasuryspend", returnsString},
 	{"spendoutputs", returnsString},
 	{"sweepaccount", []any{(*types.SweepAccountResult)(nil)}},
 	{"syncstatus", []any{(*types.SyncStatusResult)(nil)}},
 	{"ticketinfo", []any{(*[]types.TicketInfoResult)(nil)}},
 	{"treasurypolicy", []any{(*[]types.TreasuryPolicyResult)(nil), (*types.TreasuryPolicyResult)(nil)}},
+	{"treasuryspendnonce", returnsString},
 	{"tspendpolicy", []any{(*[]types.TSpendPolicyResult)(nil), (*types.TSpendPolicyResult)(nil)}},
 	{"unlockaccount", nil},
 	{"validateaddress", []any{(*types.ValidateAddressWalletResult)(nil)}},
@@ -886,6 +886,147 @@ func NewSendFromTreasuryCmd(pubkey string, amounts map[string]float64) *SendFrom
 	}
 }
 
+// CreateTreasurySpendCmd defines the createtreasuryspend JSON-RPC command.
+type CreateTreasurySpendCmd struct {
+	Amounts map[string]float64
+}
+
+// NewCreateTreasurySpendCmd returns a new instance which can be used to issue
+// a createtreasuryspend JSON-RPC command.
+func NewCreateTreasurySpendCmd(amounts map[string]float64) *CreateTreasurySpendCmd {
+	return &CreateTreasurySpendCmd{
+		Amounts: amounts,
+	}
+}
+
+// TreasurySpendNonceCmd defines the treasuryspendnonce JSON-RPC command.
+type TreasurySpendNonceCmd struct {
+	Key string
+	Tx  string
+}
+
+// NewTreasurySpendNonceCmd returns a new instance which can be used to issue a
+// treasuryspendnonce JSON-RPC command.
+func NewTreasurySpendNonceCmd(key, tx string) *TreasurySpendNonceCmd {
+	return &TreasurySpendNonceCmd{
+		Key: key,
+		Tx:  tx,
+	}
+}
+
+// SignTreasurySpendCmd defines the signtreasuryspend JSON-RPC command.
+type SignTreasurySpendCmd struct {
+	Key       string
+	Tx        string
+	PubKeys   []string
+	PubNonces []string
+}
+
+// NewSignTreasurySpendCmd returns a new instance which can be used to issue a
+// signtreasuryspend JSON-RPC command.
+func NewSignTreasurySpendCmd(key, tx string, pubKeys, pubNonces []string) *SignTreasurySpendCmd {
+	return &SignTreasurySpendCmd{
+		Key:       key,
+		Tx:        tx,
+		PubKeys:   pubKeys,
+		PubNonces: pubNonces,
+	}
+}
+
+// FinalizeTreasurySpendCmd defines the finalizetreasuryspend JSON-RPC command.
+type FinalizeTreasurySpendCmd struct {
+	Tx          string
+	PubKeys     []string
+	PubNonces   []string
+	PartialSigs []string
+}
+
+// NewFinalizeTreasurySpendCmd returns a new instance which can be used to
+// issue a finalizetreasuryspend JSON-RPC command.
+func NewFinalizeTreasurySpendCmd(tx string, pubKeys, pubNonces, partialSigs []string) *FinalizeTreasurySpendCmd {
+	return &FinalizeTreasurySpendCmd{
+		Tx:          tx,
+		PubKeys:     pubKeys,
+		PubNonces:   pubNonces,
+		PartialSigs: partialSigs,
+	}
+}
+
+// CreatePSBTCmd defines the createpsbt JSON-RPC command.
+type CreatePSBTCmd struct {
+	RawTx string
+}
+
+// NewCreatePSBTCmd returns a new instance which can be used to issue a
+// createpsbt JSON-RPC command.
+func NewCreatePSBTCmd(hexEncodedTx string) *CreatePSBTCmd {
+	return &CreatePSBTCmd{
+		RawTx: hexEncodedTx,
+	}
+}
+
+// AnalyzePSBTCmd defines the analyzepsbt JSON-RPC command.
+type AnalyzePSBTCmd struct {
+	PSBT string
+}
+
+// NewAnalyzePSBTCmd returns a new instance which can be used to issue an
+// analyzepsbt JSON-RPC command.
+func NewAnalyzePSBTCmd(psbt string) *AnalyzePSBTCmd {
+	return &AnalyzePSBTCmd{
+		PSBT: psbt,
+	}
+}
+
+// SignPSBTCmd defines the signpsbt JSON-RPC command.
+type SignPSBTCmd struct {
+	PSBT  string
+	Flags *string `jsonrpcdefault:"\"ALL\""`
+}
+
+// NewSignPSBTCmd returns a new instance which can be used to issue a signpsbt
+// JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSignPSBTCmd(psbt string, flags *string) *SignPSBTCmd {
+	return &SignPSBTCmd{
+		PSBT:  psbt,
+		Flags: flags,
+	}
+}
+
+// CombinePSBTCmd defines the combinepsbt JSON-RPC command.
+type CombinePSBTCmd struct {
+	PSBTs []string
+}
+
+// NewCombinePSBTCmd returns a new instance which can be used to issue a
+// combinepsbt JSON-RPC command.
+func NewCombinePSBTCmd(psbts []string) *CombinePSBTCmd {
+	return &CombinePSBTCmd{
+		PSBTs: psbts,
+	}
+}
+
+// FinalizePSBTCmd defines the finalizepsbt JSON-RPC command.
+type FinalizePSBTCmd struct {
+	PSBT    string
+	Extract *bool `jsonrpcdefault:"true"`
+}
+
+// NewFinalizePSBTCmd returns a new instance which can be used to issue a
+// finalizepsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewFinalizePSBTCmd(psbt string, extract *bool) *FinalizePSBTCmd {
+	return &FinalizePSBTCmd{
+		PSBT:    psbt,
+		Extract: extract,
+	}
+}
+
 // DisapprovePercentCmd defines the parameters for the disapprovepercent
 // JSON-RPC command.
 type DisapprovePercentCmd struct{}
@@ -1208,15 +1349,21 @@ func init() {
 		{"accountunlocked", (*AccountUnlockedCmd)(nil)},
 		{"addmultisigaddress", (*AddMultisigAddressCmd)(nil)},
 		{"addtransaction", (*AddTransactionCmd)(nil)},
+		{"analyzepsbt", (*AnalyzePSBTCmd)(nil)},
 		{"auditreuse", (*AuditReuseCmd)(nil)},
+		{"combinepsbt", (*CombinePSBTCmd)(nil)},
 		{"consolidate", (*ConsolidateCmd)(nil)},
 		{"createmultisig", (*CreateMultisigCmd)(nil)},
 		{"createnewaccount", (*CreateNewAccountCmd)(nil)},
+		{"createpsbt", (*CreatePSBTCmd)(nil)},
 		{"createsignature", (*CreateSignatureCmd)(nil)},
+		{"createtreasuryspend", (*CreateTreasurySpendCmd)(nil)},
 		{"createvotingaccount", (*CreateVotingAccountCmd)(nil)},
 		{"disapprovepercent", (*DisapprovePercentCmd)(nil)},
 		{"discoverusage", (*DiscoverUsageCmd)(nil)},
 		{"dumpprivkey", (*DumpPrivKeyCmd)(nil)},
+		{"finalizepsbt", (*FinalizePSBTCmd)(nil)},
+		{"finalizetreasuryspend", (*FinalizeTreasurySpendCmd)(nil)},
 		{"fundrawtransaction", (*FundRawTransactionCmd)(nil)},
 		{"getaccount", (*GetAccountCmd)(nil)},
 		{"getaccountaddress", (*GetAccountAddressCmd)(nil)},
@@ -1272,13 +1419,16 @@ func init() {
 		{"settxfee", (*SetTxFeeCmd)(nil)},
 		{"setvotechoice", (*SetVoteChoiceCmd)(nil)},
 		{"signmessage", (*SignMessageCmd)(nil)},
+		{"signpsbt", (*SignPSBTCmd)(nil)},
 		{"signrawtransaction", (*SignRawTransactionCmd)(nil)},
 		{"signrawtransactions", (*SignRawTransactionsCmd)(nil)},
+		{"signtreasuryspend", (*SignTreasurySpendCmd)(nil)},
 		{"spendoutputs", (*SpendOutputsCmd)(nil)},
 		{"sweepaccount", (*SweepAccountCmd)(nil)},
 		{"syncstatus", (*SyncStatusCmd)(nil)},
 		{"ticketinfo", (*TicketInfoCmd)(nil)},
 		{"treasurypolicy", (*TreasuryPolicyCmd)(nil)},
+		{"treasuryspendnonce", (*TreasurySpendNonceCmd)(nil)},
 		{"tspendpolicy", (*TSpendPolicyCmd)(nil)},
 		{"unlockaccount", (*UnlockAccountCmd)(nil)},
 		{"validatepreVGLP0005cf", (*ValidatePreVGLP0005CFCmd)(nil)},
@@ -966,6 +966,165 @@ func TestWalletSvrCmds(t *testing.T) {
 				Amounts: map[string]float64{"1Address": 0.5},
 			},
 		},
+		{
+			name: "createtreasuryspend",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("createtreasuryspend"), `{"1Address":0.5}`)
+			},
+			staticCmd: func() any {
+				var debits = map[string]float64{"1Address": 0.5}
+				return NewCreateTreasurySpendCmd(debits)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"createtreasuryspend","params":[{"1Address":0.5}],"id":1}`,
+			unmarshalled: &CreateTreasurySpendCmd{
+				Amounts: map[string]float64{"1Address": 0.5},
+			},
+		},
+		{
+			name: "treasuryspendnonce",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("treasuryspendnonce"), "key", "tx")
+			},
+			staticCmd: func() any {
+				return NewTreasurySpendNonceCmd("key", "tx")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"treasuryspendnonce","params":["key","tx"],"id":1}`,
+			unmarshalled: &TreasurySpendNonceCmd{
+				Key: "key",
+				Tx:  "tx",
+			},
+		},
+		{
+			name: "signtreasuryspend",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("signtreasuryspend"), "key", "tx",
+					`["pk1","pk2"]`, `["n1","n2"]`)
+			},
+			staticCmd: func() any {
+				return NewSignTreasurySpendCmd("key", "tx",
+					[]string{"pk1", "pk2"}, []string{"n1", "n2"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signtreasuryspend","params":["key","tx",["pk1","pk2"],["n1","n2"]],"id":1}`,
+			unmarshalled: &SignTreasurySpendCmd{
+				Key:       "key",
+				Tx:        "tx",
+				PubKeys:   []string{"pk1", "pk2"},
+				PubNonces: []string{"n1", "n2"},
+			},
+		},
+		{
+			name: "finalizetreasuryspend",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("finalizetreasuryspend"), "tx",
+					`["pk1","pk2"]`, `["n1","n2"]`, `["s1","s2"]`)
+			},
+			staticCmd: func() any {
+				return NewFinalizeTreasurySpendCmd("tx", []string{"pk1", "pk2"},
+					[]string{"n1", "n2"}, []string{"s1", "s2"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"finalizetreasuryspend","params":["tx",["pk1","pk2"],["n1","n2"],["s1","s2"]],"id":1}`,
+			unmarshalled: &FinalizeTreasurySpendCmd{
+				Tx:          "tx",
+				PubKeys:     []string{"pk1", "pk2"},
+				PubNonces:   []string{"n1", "n2"},
+				PartialSigs: []string{"s1", "s2"},
+			},
+		},
+		{
+			name: "createpsbt",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("createpsbt"), "001122")
+			},
+			staticCmd: func() any {
+				return NewCreatePSBTCmd("001122")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"createpsbt","params":["001122"],"id":1}`,
+			unmarshalled: &CreatePSBTCmd{
+				RawTx: "001122",
+			},
+		},
+		{
+			name: "analyzepsbt",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("analyzepsbt"), "psbt")
+			},
+			staticCmd: func() any {
+				return NewAnalyzePSBTCmd("psbt")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"analyzepsbt","params":["psbt"],"id":1}`,
+			unmarshalled: &AnalyzePSBTCmd{
+				PSBT: "psbt",
+			},
+		},
+		{
+			name: "signpsbt",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("signpsbt"), "psbt")
+			},
+			staticCmd: func() any {
+				return NewSignPSBTCmd("psbt", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signpsbt","params":["psbt"],"id":1}`,
+			unmarshalled: &SignPSBTCmd{
+				PSBT:  "psbt",
+				Flags: VGLjson.String("ALL"),
+			},
+		},
+		{
+			name: "signpsbt optional",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("signpsbt"), "psbt", "SINGLE")
+			},
+			staticCmd: func() any {
+				return NewSignPSBTCmd("psbt", VGLjson.String("SINGLE"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signpsbt","params":["psbt","SINGLE"],"id":1}`,
+			unmarshalled: &SignPSBTCmd{
+				PSBT:  "psbt",
+				Flags: VGLjson.String("SINGLE"),
+			},
+		},
+		{
+			name: "combinepsbt",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("combinepsbt"), `["psbt1","psbt2"]`)
+			},
+			staticCmd: func() any {
+				return NewCombinePSBTCmd([]string{"psbt1", "psbt2"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"combinepsbt","params":[["psbt1","psbt2"]],"id":1}`,
+			unmarshalled: &CombinePSBTCmd{
+				PSBTs: []string{"psbt1", "psbt2"},
+			},
+		},
+		{
+			name: "finalizepsbt",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("finalizepsbt"), "psbt")
+			},
+			staticCmd: func() any {
+				return NewFinalizePSBTCmd("psbt", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"finalizepsbt","params":["psbt"],"id":1}`,
+			unmarshalled: &FinalizePSBTCmd{
+				PSBT:    "psbt",
+				Extract: VGLjson.Bool(true),
+			},
+		},
+		{
+			name: "finalizepsbt optional",
+			newCmd: func() (any, error) {
+				return VGLjson.NewCmd(Method("finalizepsbt"), "psbt", false)
+			},
+			staticCmd: func() any {
+				return NewFinalizePSBTCmd("psbt", VGLjson.Bool(false))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"finalizepsbt","params":["psbt",false],"id":1}`,
+			unmarshalled: &FinalizePSBTCmd{
+				PSBT:    "psbt",
+				Extract: VGLjson.Bool(false),
+			},
+		},
 		{
 			name: "sendtotreasury",
 			newCmd: func() (any, error) {
@@ -1154,17 +1313,17 @@ func TestWalletSvrCmds(t *testing.T) {
 		{
 			name: "sweepaccount - optionals provided",
 			newCmd: func() (any, error) {
-				return VGLjson.NewCmd(Method("sweepaccount"), "default", "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu", 6, 0.05)
+				return VGLjson.NewCmd(Method("sweepaccount"), "default", "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin", 6, 0.05)
 			},
 			staticCmd: func() any {
-				return NewSweepAccountCmd("default", "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+				return NewSweepAccountCmd("default", "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin",
 					func(i uint32) *uint32 { return &i }(6),
 					func(i float64) *float64 { return &i }(0.05))
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sweepaccount","params":["default","DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",6,0.05],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sweepaccount","params":["default","VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin",6,0.05],"id":1}`,
 			unmarshalled: &SweepAccountCmd{
 				SourceAccount:         "default",
-				DestinationAddress:    "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+				DestinationAddress:    "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin",
 				RequiredConfirmations: func(i uint32) *uint32 { return &i }(6),
 				FeePerKb:              func(i float64) *float64 { return &i }(0.05),
 			},
@@ -1172,15 +1331,15 @@ func TestWalletSvrCmds(t *testing.T) {
 		{
 			name: "sweepaccount - optionals omitted",
 			newCmd: func() (any, error) {
-				return VGLjson.NewCmd(Method("sweepaccount"), "default", "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu")
+				return VGLjson.NewCmd(Method("sweepaccount"), "default", "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin")
 			},
 			staticCmd: func() any {
-				return NewSweepAccountCmd("default", "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu", nil, nil)
+				return NewSweepAccountCmd("default", "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin", nil, nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sweepaccount","params":["default","DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sweepaccount","params":["default","VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin"],"id":1}`,
 			unmarshalled: &SweepAccountCmd{
 				SourceAccount:      "default",
-				DestinationAddress: "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+				DestinationAddress: "VsMxuXCdfiuXa6iBi72wqAbCmGwtj1mkAin",
 			},
 		},
 		{
@@ -330,6 +330,37 @@ type SignRawTransactionsResult struct {
 	Results []SignedTransaction `json:"results"`
 }
 
+// AnalyzePSBTInput describes the state of an input of a partially signed
+// transaction.
+type AnalyzePSBTInput struct {
+	HasPrevOut  bool   `json:"hasprevout"`
+	IsFinal     bool   `json:"isfinal"`
+	MissingSigs bool   `json:"missingsigs"`
+	Next        string `json:"next"`
+}
+
+// AnalyzePSBTResult models the data from the analyzepsbt command.
+type AnalyzePSBTResult struct {
+	Inputs []AnalyzePSBTInput `json:"inputs"`
+	Next   string             `json:"next"`
+	Fee    *float64           `json:"fee,omitempty"`
+}
+
+// SignPSBTResult models the data from the signpsbt command.
+type SignPSBTResult struct {
+	PSBT     string `json:"psbt"`
+	Signed   int    `json:"signed"`
+	Complete bool   `json:"complete"`
+}
+
+// FinalizePSBTResult models the data from the finalizepsbt command.  Hex is
+// only set when the transaction was complete and extracted.
+type FinalizePSBTResult struct {
+	PSBT     string `json:"psbt"`
+	Hex      string `json:"hex,omitempty"`
+	Complete bool   `json:"complete"`
+}
+
 // SweepAccountResult models the data returned from the sweepaccount
 // command.
 type SweepAccountResult struct {
@@ -161,6 +161,11 @@ const (
 	WalletService_SignTransaction_FullMethodName             = "/walletrpc.WalletService/SignTransaction"
 	WalletService_SignTransactions_FullMethodName            = "/walletrpc.WalletService/SignTransactions"
 	WalletService_CreateSignature_FullMethodName             = "/walletrpc.WalletService/CreateSignature"
+	WalletService_CreatePSBT_FullMethodName                  = "/walletrpc.WalletService/CreatePSBT"
+	WalletService_AnalyzePSBT_FullMethodName                 = "/walletrpc.WalletService/AnalyzePSBT"
+	WalletService_SignPSBT_FullMethodName                    = "/walletrpc.WalletService/SignPSBT"
+	WalletService_CombinePSBT_FullMethodName                 = "/walletrpc.WalletService/CombinePSBT"
+	WalletService_FinalizePSBT_FullMethodName                = "/walletrpc.WalletService/FinalizePSBT"
 	WalletService_PublishTransaction_FullMethodName          = "/walletrpc.WalletService/PublishTransaction"
 	WalletService_PublishUnminedTransactions_FullMethodName  = "/walletrpc.WalletService/PublishUnminedTransactions"
 	WalletService_PurchaseTickets_FullMethodName             = "/walletrpc.WalletService/PurchaseTickets"
@@ -235,6 +240,11 @@ type WalletServiceClient interface {
 	SignTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error)
 	SignTransactions(ctx context.Context, in *SignTransactionsRequest, opts ...grpc.CallOption) (*SignTransactionsResponse, error)
 	CreateSignature(ctx context.Context, in *CreateSignatureRequest, opts ...grpc.CallOption) (*CreateSignatureResponse, error)
+	CreatePSBT(ctx context.Context, in *CreatePSBTRequest, opts ...grpc.CallOption) (*CreatePSBTResponse, error)
+	AnalyzePSBT(ctx context.Context, in *AnalyzePSBTRequest, opts ...grpc.CallOption) (*AnalyzePSBTResponse, error)
+	SignPSBT(ctx context.Context, in *SignPSBTRequest, opts ...grpc.CallOption) (*SignPSBTResponse, error)
+	CombinePSBT(ctx context.Context, in *CombinePSBTRequest, opts ...grpc.CallOption) (*CombinePSBTResponse, error)
+	FinalizePSBT(ctx context.Context, in *FinalizePSBTRequest, opts ...grpc.CallOption) (*FinalizePSBTResponse, error)
 	PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error)
 	PublishUnminedTransactions(ctx context.Context, in *PublishUnminedTransactionsRequest, opts ...grpc.CallOption) (*PublishUnminedTransactionsResponse, error)
 	PurchaseTickets(ctx context.Context, in *PurchaseTicketsRequest, opts ...grpc.CallOption) (*PurchaseTicketsResponse, error)
@@ -736,6 +746,56 @@ func (c *walletServiceClient) CreateSignature(ctx context.Context, in *CreateSig
 	return out, nil
 }
 
+func (c *walletServiceClient) CreatePSBT(ctx context.Context, in *CreatePSBTRequest, opts ...grpc.CallOption) (*CreatePSBTResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePSBTResponse)
+	err := c.cc.Invoke(ctx, WalletService_CreatePSBT_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) AnalyzePSBT(ctx context.Context, in *AnalyzePSBTRequest, opts ...grpc.CallOption) (*AnalyzePSBTResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalyzePSBTResponse)
+	err := c.cc.Invoke(ctx, WalletService_AnalyzePSBT_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) SignPSBT(ctx context.Context, in *SignPSBTRequest, opts ...grpc.CallOption) (*SignPSBTResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignPSBTResponse)
+	err := c.cc.Invoke(ctx, WalletService_SignPSBT_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) CombinePSBT(ctx context.Context, in *CombinePSBTRequest, opts ...grpc.CallOption) (*CombinePSBTResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CombinePSBTResponse)
+	err := c.cc.Invoke(ctx, WalletService_CombinePSBT_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) FinalizePSBT(ctx context.Context, in *FinalizePSBTRequest, opts ...grpc.CallOption) (*FinalizePSBTResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FinalizePSBTResponse)
+	err := c.cc.Invoke(ctx, WalletService_FinalizePSBT_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletServiceClient) PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(PublishTransactionResponse)
@@ -1033,6 +1093,11 @@ type WalletServiceServer interface {
 	SignTransaction(context.Context, *SignTransactionRequest) (*SignTransactionResponse, error)
 	SignTransactions(context.Context, *SignTransactionsRequest) (*SignTransactionsResponse, error)
 	CreateSignature(context.Context, *CreateSignatureRequest) (*CreateSignatureResponse, error)
+	CreatePSBT(context.Context, *CreatePSBTRequest) (*CreatePSBTResponse, error)
+	AnalyzePSBT(context.Context, *AnalyzePSBTRequest) (*AnalyzePSBTResponse, error)
+	SignPSBT(context.Context, *SignPSBTRequest) (*SignPSBTResponse, error)
+	CombinePSBT(context.Context, *CombinePSBTRequest) (*CombinePSBTResponse, error)
+	FinalizePSBT(context.Context, *FinalizePSBTRequest) (*FinalizePSBTResponse, error)
 	PublishTransaction(context.Context, *PublishTransactionRequest) (*PublishTransactionResponse, error)
 	PublishUnminedTransactions(context.Context, *PublishUnminedTransactionsRequest) (*PublishUnminedTransactionsResponse, error)
 	PurchaseTickets(context.Context, *PurchaseTicketsRequest) (*PurchaseTicketsResponse, error)
@@ -1188,6 +1253,21 @@ func (UnimplementedWalletServiceServer) SignTransactions(context.Context, *SignT
 func (UnimplementedWalletServiceServer) CreateSignature(context.Context, *CreateSignatureRequest) (*CreateSignatureResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateSignature not implemented")
 }
+func (UnimplementedWalletServiceServer) CreatePSBT(context.Context, *CreatePSBTRequest) (*CreatePSBTResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePSBT not implemented")
+}
+func (UnimplementedWalletServiceServer) AnalyzePSBT(context.Context, *AnalyzePSBTRequest) (*AnalyzePSBTResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzePSBT not implemented")
+}
+func (UnimplementedWalletServiceServer) SignPSBT(context.Context, *SignPSBTRequest) (*SignPSBTResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignPSBT not implemented")
+}
+func (UnimplementedWalletServiceServer) CombinePSBT(context.Context, *CombinePSBTRequest) (*CombinePSBTResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CombinePSBT not implemented")
+}
+func (UnimplementedWalletServiceServer) FinalizePSBT(context.Context, *FinalizePSBTRequest) (*FinalizePSBTResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinalizePSBT not implemented")
+}
 func (UnimplementedWalletServiceServer) PublishTransaction(context.Context, *PublishTransactionRequest) (*PublishTransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PublishTransaction not implemented")
 }
@@ -1944,6 +2024,96 @@ func _WalletService_CreateSignature_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletService_CreatePSBT_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePSBTRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).CreatePSBT(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_CreatePSBT_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).CreatePSBT(ctx, req.(*CreatePSBTRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_AnalyzePSBT_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzePSBTRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).AnalyzePSBT(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_AnalyzePSBT_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).AnalyzePSBT(ctx, req.(*AnalyzePSBTRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_SignPSBT_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignPSBTRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).SignPSBT(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_SignPSBT_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).SignPSBT(ctx, req.(*SignPSBTRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_CombinePSBT_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CombinePSBTRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).CombinePSBT(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_CombinePSBT_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).CombinePSBT(ctx, req.(*CombinePSBTRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_FinalizePSBT_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalizePSBTRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).FinalizePSBT(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_FinalizePSBT_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).FinalizePSBT(ctx, req.(*FinalizePSBTRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletService_PublishTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PublishTransactionRequest)
 	if err := dec(in); err != nil {
@@ -2529,6 +2699,26 @@ var WalletService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateSignature",
 			Handler:    _WalletService_CreateSignature_Handler,
 		},
+		{
+			MethodName: "CreatePSBT",
+			Handler:    _WalletService_CreatePSBT_Handler,
+		},
+		{
+			MethodName: "AnalyzePSBT",
+			Handler:    _WalletService_AnalyzePSBT_Handler,
+		},
+		{
+			MethodName: "SignPSBT",
+			Handler:    _WalletService_SignPSBT_Handler,
+		},
+		{
+			MethodName: "CombinePSBT",
+			Handler:    _WalletService_CombinePSBT_Handler,
+		},
+		{
+			MethodName: "FinalizePSBT",
+			Handler:    _WalletService_FinalizePSBT_Handler,
+		},
 		{
 			MethodName: "PublishTransaction",
 			Handler:    _WalletService_PublishTransaction_Handler,
@@ -339,6 +339,148 @@ func (w *Wallet) calcNextBlake3DiffFromAnchor(prevNode, blake3Anchor *wire.Block
 	return nextDiff
 }
 
+func (w *Wallet) loadCachedKawpowWorkDiffCandidateAnchor() *wire.BlockHeader {
+	w.cachedKawpowWorkDiffCandidateAnchorMu.Lock()
+	defer w.cachedKawpowWorkDiffCandidateAnchorMu.Unlock()
+
+	return w.cachedKawpowWorkDiffCandidateAnchor
+}
+
+func (w *Wallet) storeCachedKawpowWorkDiffCandidateAnchor(candidate *wire.BlockHeader) {
+	w.cachedKawpowWorkDiffCandidateAnchorMu.Lock()
+	defer w.cachedKawpowWorkDiffCandidateAnchorMu.Unlock()
+
+	w.cachedKawpowWorkDiffCandidateAnchor = candidate
+}
+
+// isKawpowAgendaForcedActive returns whether or not the agenda to change the
+// proof of work hash function to KawPoW is forced active by the chain
+// parameters.
+func (w *Wallet) isKawpowAgendaForcedActive() bool {
+	const deploymentID = chaincfg.VoteIDKawPoW
+	deployment, ok := w.deploymentsByID[deploymentID]
+	if !ok {
+		return false
+	}
+
+	return deployment.ForcedChoiceID == "yes"
+}
+
+// kawpowDeploymentVersion returns the block version associated with the KawPoW
+// agenda deployment and whether or not the deployment exists for the network.
+func (w *Wallet) kawpowDeploymentVersion() (uint32, bool) {
+	for version, deployments := range w.chainParams.Deployments {
+		for i := range deployments {
+			if deployments[i].Vote.Id == chaincfg.VoteIDKawPoW {
+				return version, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// calcNextKawpowDiffFromAnchor calculates the required difficulty for the block
+// AFTER the passed previous block node relative to the given anchor block based
+// on the KawPoW difficulty retarget rules.  KawPoW uses the same ASERT
+// parameters as blake3.
+//
+// This must be kept in sync with calcNextKawpowDiffFromAnchor in vgld's
+// internal/blockchain.
+//
+// This function is safe for concurrent access.
+func (w *Wallet) calcNextKawpowDiffFromAnchor(prevNode, kawpowAnchor *wire.BlockHeader) uint32 {
+	// Calculate the time and height deltas as the difference between the
+	// provided block and the KawPoW anchor block.
+	timeDelta := prevNode.Timestamp.Unix() - kawpowAnchor.Timestamp.Unix()
+	heightDelta := int64(prevNode.Height) - int64(kawpowAnchor.Height)
+
+	// Calculate the next target difficulty using the ASERT algorithm.
+	params := w.chainParams
+	nextDiff := blockchain.CalcASERTDiff(params.WorkDiffV2Blake3StartBits,
+		params.PowLimit, int64(params.TargetTimePerBlock.Seconds()), timeDelta,
+		heightDelta, params.WorkDiffV2HalfLifeSecs)
+
+	// Prevent the difficulty from going higher than a maximum allowed
+	// difficulty on the test network.
+	if w.minTestNetTarget != nil && nextDiff < w.minTestNetDiffBits {
+		nextDiff = w.minTestNetDiffBits
+	}
+
+	return nextDiff
+}
+
+// checkKawpowDifficultyCandidates returns whether or not the difficulty
+// specified in the block header matches the required KawPoW difficulty for any
+// of the possible KawPoW anchors when the KawPoW agenda was voted in.
+//
+// Much like the blake3 difficulty checks, whether or not the agenda is active
+// can not be determined from the positional checks, so all blocks that could
+// be the final block prior to the activation of the agenda are tried as the
+// anchor.  Only headers whose KawPoW final hash calculated from the claimed mix
+// hash satisfies the target difficulty are considered.  The mix hash itself is
+// verified along with the rest of the proof of work by the caller, so the
+// light cache is not needed here.
+func (w *Wallet) checkKawpowDifficultyCandidates(dbtx walletdb.ReadTx, header *wire.BlockHeader,
+	prevNode *wire.BlockHeader, chain []*BlockNode) (bool, error) {
+
+	minKawpowBlockVersion, ok := w.kawpowDeploymentVersion()
+	if !ok {
+		return false, nil
+	}
+	rcai := int64(w.chainParams.RuleChangeActivationInterval)
+	svh := w.chainParams.StakeValidationHeight
+	firstPossibleActivationHeight := svh + rcai*2
+	isKawpowPossiblyActive := uint32(header.Version) >= minKawpowBlockVersion &&
+		int64(header.Height) >= firstPossibleActivationHeight
+	if !isKawpowPossiblyActive || header.MixHash == (chainhash.Hash{}) {
+		return false, nil
+	}
+	target := blockchain.CompactToBig(header.Bits)
+	if header.VerifyKawPowTarget(target) != nil {
+		return false, nil
+	}
+
+	// Try the cached candidate anchor first since it is very likely to be the
+	// correct one during the initial header sync.
+	cachedCandidate := w.loadCachedKawpowWorkDiffCandidateAnchor()
+	if cachedCandidate != nil {
+		isAncestor, err := w.isAncestorOf(dbtx, cachedCandidate, prevNode, chain)
+		if err != nil {
+			return false, err
+		}
+		if isAncestor {
+			kawpowDiff := w.calcNextKawpowDiffFromAnchor(prevNode, cachedCandidate)
+			if header.Bits == kawpowDiff {
+				return true, nil
+			}
+		}
+	}
+
+	// Iterate backwards through all possible anchor candidates which consist
+	// of the final blocks of previous rule change activation intervals.
+	finalNodeHeight := calcWantHeight(svh, rcai, int64(header.Height))
+	candidate, err := w.ancestorHeaderAtHeight(dbtx, prevNode, chain, int32(finalNodeHeight))
+	if err != nil {
+		return false, err
+	}
+	for candidate != nil &&
+		uint32(candidate.Version) >= minKawpowBlockVersion &&
+		int64(candidate.Height) >= firstPossibleActivationHeight-1 {
+
+		kawpowDiff := w.calcNextKawpowDiffFromAnchor(prevNode, candidate)
+		if header.Bits == kawpowDiff {
+			w.storeCachedKawpowWorkDiffCandidateAnchor(candidate)
+			return true, nil
+		}
+		candidate, err = w.relativeAncestor(dbtx, candidate, rcai, chain)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
 // calcWantHeight calculates the height of the final block of the previous
 // interval given a stake validation height, stake validation interval, and
 // block height.
@@ -376,6 +518,41 @@ func (w *Wallet) checkDifficultyPositional(dbtx walletdb.ReadTx, header *wire.Bl
 	// blake3 proof of work agenda.
 	// -------------------------------------------------------------------------
 
+	// Ensure the difficulty specified in the block header matches the
+	// calculated difficulty using the KawPoW difficulty algorithm when the
+	// KawPoW agenda is always active.  The KawPoW agenda takes precedence over
+	// the blake3 proof of work agenda.
+	//
+	// Unlike blake3, the anchor for networks where the agenda is always active
+	// is the genesis block.
+	if w.isKawpowAgendaForcedActive() {
+		anchor, err := w.ancestorHeaderAtHeight(dbtx, prevNode, chain, 0)
+		if err != nil {
+			return err
+		}
+		kawpowDiff := w.calcNextKawpowDiffFromAnchor(prevNode, anchor)
+		if header.Bits != kawpowDiff {
+			err := errors.Errorf("%w: block difficulty of %d is not the expected "+
+				"value of %d (difficulty algorithm: KawPoW ASERT)",
+				blockchain.ErrUnexpectedDifficulty, header.Bits, kawpowDiff)
+			return errors.E(errors.Consensus, err)
+		}
+
+		return nil
+	}
+
+	// Accept the header when it is solved for KawPoW and the difficulty matches
+	// the required KawPoW difficulty relative to any of the possible anchors.
+	// Otherwise, fall back to the blake3 and original difficulty algorithms.
+	kawpowMatched, err := w.checkKawpowDifficultyCandidates(dbtx, header,
+		prevNode, chain)
+	if err != nil {
+		return err
+	}
+	if kawpowMatched {
+		return nil
+	}
+
 	// Ensure the difficulty specified in the block header matches the
 	// calculated difficulty using the algorithm defined in VGLP0011 when the
 	// blake3 proof of work agenda is always active.
@@ -844,6 +1021,39 @@ func (w *Wallet) ValidateHeaderChainDifficulties(ctx context.Context, chain []*B
 	return invalid, err
 }
 
+// checkKawPowProofOfWork ensures the target difficulty of the header is in the
+// valid range per the provided proof of work limit, the KawPoW mix hash of the
+// header matches the one calculated from the header and nonce, and the KawPoW
+// final hash is less than or equal to the target difficulty.
+//
+// Only the KawPoW light cache for the epoch of the header is required, which
+// allows SPV wallets to fully verify the proof of work without the full
+// dataset.  The light cache is generated when it is not resident, so the final
+// hash calculated from the claimed mix hash is checked against the target and
+// the header must be at the height after the provided parent header, which
+// limits the epoch to at most one past that of the parent, before the light
+// cache is requested.  The parent must be nil for the genesis block.
+func checkKawPowProofOfWork(header, parent *wire.BlockHeader, powLimit *big.Int) error {
+	err := blockchain.CheckProofOfWorkRange(header.Bits, powLimit)
+	if err != nil {
+		return err
+	}
+	target := blockchain.CompactToBig(header.Bits)
+	err = header.VerifyKawPowTarget(target)
+	if err != nil {
+		return err
+	}
+	var wantHeight uint32
+	if parent != nil {
+		wantHeight = parent.Height + 1
+	}
+	if header.Height != wantHeight {
+		return errors.Errorf("header height %d does not match expected "+
+			"height %d", header.Height, wantHeight)
+	}
+	return header.VerifyKawPow(target)
+}
+
 func (w *Wallet) validateHeaderChainDifficulties(dbtx walletdb.ReadTx, chain []*BlockNode, idx int) ([]*BlockNode, error) {
 	const op errors.Op = "wallet.validateHeaderChainDifficulties"
 
@@ -883,6 +1093,10 @@ func (w *Wallet) validateHeaderChainDifficulties(dbtx walletdb.ReadTx, chain []*
 			err = blockchain.CheckProofOfWork(&blake3PowHash, h.Bits,
 				w.chainParams.PowLimit)
 		}
+		if err != nil {
+			// Check KawPoW Proof of Work
+			err = checkKawPowProofOfWork(h, parent, w.chainParams.PowLimit)
+		}
 		if err != nil {
 			return chain[idx:], errors.E(op, errors.Consensus, err)
 		}
@@ -0,0 +1,349 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	blockchain "github.com/kdsmith18542/vigil/blockchain/standalone/v2"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/chaincfg/v3"
+	"github.com/kdsmith18542/vigil/kawpow"
+	"github.com/kdsmith18542/vigil/wallet/errors"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// kawpowDiffTestDataFile is the KawPoW difficulty test data.  It contains the
+// same header chains as the vgld test data so the header chain difficulty
+// checks of both implementations are tested against the same header chains.
+var kawpowDiffTestDataFile = filepath.Join("testdata", "kawpowdiff.json")
+
+// newDifficultyTestWallet returns a wallet with only the fields required by
+// the positional difficulty checks populated.
+func newDifficultyTestWallet(params *chaincfg.Params) *Wallet {
+	deploymentsByID := make(map[string]*chaincfg.ConsensusDeployment)
+	for _, deployments := range params.Deployments {
+		for i := range deployments {
+			deployment := &deployments[i]
+			deploymentsByID[deployment.Vote.Id] = deployment
+		}
+	}
+	return &Wallet{
+		chainParams:     params,
+		deploymentsByID: deploymentsByID,
+	}
+}
+
+// TestKawpowHeaderChainDifficulty ensures the positional difficulty checks
+// accept header chains with the required KawPoW difficulty and reject headers
+// that specify any other difficulty.
+func TestKawpowHeaderChainDifficulty(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(kawpowDiffTestDataFile)
+	if err != nil {
+		t.Fatalf("unable to read test data: %v", err)
+	}
+	var testData struct {
+		Chains []struct {
+			Name    string `json:"name"`
+			Headers []struct {
+				Height    uint32 `json:"height"`
+				Timestamp int64  `json:"timestamp"`
+				Bits      string `json:"bits"`
+			} `json:"headers"`
+		} `json:"chains"`
+	}
+	if err := json.Unmarshal(data, &testData); err != nil {
+		t.Fatalf("unable to parse test data: %v", err)
+	}
+
+	// The KawPoW agenda is forced active on the main network.
+	params := chaincfg.MainNetParams()
+	w := newDifficultyTestWallet(params)
+	for _, test := range testData.Chains {
+		genesis := params.GenesisBlock.Header
+		genesisHash := genesis.BlockHash()
+		chain := []*BlockNode{NewBlockNode(&genesis, &genesisHash, nil)}
+		for _, h := range test.Headers {
+			bits, err := strconv.ParseUint(h.Bits, 16, 32)
+			if err != nil {
+				t.Fatalf("%q: bad bits %q: %v", test.Name, h.Bits, err)
+			}
+
+			prev := chain[len(chain)-1].Header
+			header := &wire.BlockHeader{
+				Version:   12,
+				PrevBlock: prev.BlockHash(),
+				Bits:      uint32(bits),
+				Height:    h.Height,
+				Timestamp: time.Unix(h.Timestamp, 0),
+			}
+
+			err = w.checkDifficultyPositional(nil, header, prev, chain)
+			if err != nil {
+				t.Fatalf("%q-%d: unexpected err: %v", test.Name, h.Height, err)
+			}
+
+			// Ensure a header with any other difficulty is rejected.
+			badHeader := *header
+			badHeader.Bits--
+			err = w.checkDifficultyPositional(nil, &badHeader, prev, chain)
+			if !errors.Is(err, blockchain.ErrUnexpectedDifficulty) {
+				t.Fatalf("%q-%d: mismatched err -- got %v, want %v", test.Name,
+					h.Height, err, blockchain.ErrUnexpectedDifficulty)
+			}
+
+			hash := header.BlockHash()
+			chain = append(chain, NewBlockNode(header, &hash, nil))
+		}
+	}
+}
+
+// TestKawPowProofOfWorkHeight ensures KawPoW headers that are not at the height
+// after their parent, including headers claiming heights in unsupported
+// epochs, are rejected without generating the light cache for the claimed
+// height.
+func TestKawPowProofOfWorkHeight(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	parent := params.GenesisBlock.Header
+	manager := kawpow.DefaultEpochManager()
+
+	for _, height := range []uint32{kawpow.EpochLength * 2, math.MaxUint32} {
+		header := &wire.BlockHeader{
+			PrevBlock: parent.BlockHash(),
+			Bits:      params.PowLimitBits,
+			Height:    height,
+		}
+
+		// Find a nonce that passes the cheap target check with the claimed
+		// mix hash so the height check is reached.
+		target := blockchain.CompactToBig(header.Bits)
+		for header.VerifyKawPowTarget(target) != nil {
+			header.Nonce++
+		}
+
+		generations := manager.Stats().CacheGenerations
+		err := checkKawPowProofOfWork(header, &parent, params.PowLimit)
+		if err == nil {
+			t.Fatalf("height %d: header was not rejected", height)
+		}
+		if got := manager.Stats().CacheGenerations; got != generations {
+			t.Fatalf("height %d: light cache was generated", height)
+		}
+	}
+}
+
+// TestKawpowDifficultyCandidates ensures headers solved for KawPoW on networks
+// where the KawPoW agenda is voted in are only accepted when their difficulty
+// matches the required KawPoW difficulty relative to one of the possible
+// anchors and that a matching anchor is cached.
+func TestKawpowDifficultyCandidates(t *testing.T) {
+	t.Parallel()
+
+	// The KawPoW agenda is not forced active on the simulation network, so
+	// the anchor is the final block of any previous rule change interval at
+	// or after the first possible activation.
+	params := chaincfg.SimNetParams()
+	w := newDifficultyTestWallet(params)
+	version, ok := w.kawpowDeploymentVersion()
+	if !ok {
+		t.Fatal("no KawPoW deployment")
+	}
+	rcai := int64(params.RuleChangeActivationInterval)
+	firstAnchorHeight := params.StakeValidationHeight + rcai*2 - 1
+	secondAnchorHeight := firstAnchorHeight + rcai
+	const tipHeight = 1199
+
+	// Create a chain where a few blocks after the first possible anchor share
+	// its timestamp so each anchor results in a different required
+	// difficulty.
+	genesis := params.GenesisBlock.Header
+	genesisHash := genesis.BlockHash()
+	chain := []*BlockNode{NewBlockNode(&genesis, &genesisHash, nil)}
+	timestamp := genesis.Timestamp
+	for height := uint32(1); height <= tipHeight; height++ {
+		if int64(height) <= firstAnchorHeight || int64(height) > firstAnchorHeight+6 {
+			timestamp = timestamp.Add(params.TargetTimePerBlock)
+		}
+		prev := chain[len(chain)-1].Header
+		header := &wire.BlockHeader{
+			Version:   int32(version),
+			PrevBlock: prev.BlockHash(),
+			Bits:      params.PowLimitBits,
+			Height:    height,
+			Timestamp: timestamp,
+		}
+		hash := header.BlockHash()
+		chain = append(chain, NewBlockNode(header, &hash, nil))
+	}
+	tip := chain[tipHeight].Header
+	firstAnchor := chain[firstAnchorHeight].Header
+	secondAnchor := chain[secondAnchorHeight].Header
+	firstBits := w.calcNextKawpowDiffFromAnchor(tip, firstAnchor)
+	secondBits := w.calcNextKawpowDiffFromAnchor(tip, secondAnchor)
+	if firstBits == secondBits {
+		t.Fatalf("anchors result in the same difficulty %08x", firstBits)
+	}
+
+	// newHeader returns a header building on the tip with the provided
+	// difficulty and a nonce that either satisfies the target with the
+	// claimed mix hash or not as requested.
+	newHeader := func(bits uint32, solved bool) *wire.BlockHeader {
+		header := &wire.BlockHeader{
+			Version:   int32(version),
+			PrevBlock: tip.BlockHash(),
+			Bits:      bits,
+			Height:    tip.Height + 1,
+			Timestamp: tip.Timestamp.Add(params.TargetTimePerBlock),
+			MixHash:   chainhash.Hash{0x01},
+		}
+		target := blockchain.CompactToBig(bits)
+		for (header.VerifyKawPowTarget(target) == nil) != solved {
+			header.Nonce++
+		}
+		return header
+	}
+
+	// A header from a different chain that must never be used as the cached
+	// anchor.
+	sideAnchor := *firstAnchor
+	sideAnchor.Timestamp = sideAnchor.Timestamp.Add(time.Second)
+
+	tests := []struct {
+		name       string            // test description
+		cached     *wire.BlockHeader // cached anchor prior to the call
+		header     *wire.BlockHeader // header to check
+		want       bool              // expected result
+		wantCached *wire.BlockHeader // expected cached anchor after the call
+	}{{
+		name:       "matches first possible anchor",
+		header:     newHeader(firstBits, true),
+		want:       true,
+		wantCached: firstAnchor,
+	}, {
+		name:       "matches most recent possible anchor",
+		cached:     firstAnchor,
+		header:     newHeader(secondBits, true),
+		want:       true,
+		wantCached: secondAnchor,
+	}, {
+		name:       "matches cached anchor",
+		cached:     firstAnchor,
+		header:     newHeader(firstBits, true),
+		want:       true,
+		wantCached: firstAnchor,
+	}, {
+		name:       "cached anchor not an ancestor",
+		cached:     &sideAnchor,
+		header:     newHeader(firstBits, true),
+		want:       true,
+		wantCached: firstAnchor,
+	}, {
+		name:   "no matching anchor",
+		header: newHeader(params.PowLimitBits-1, true),
+	}, {
+		name: "no mix hash",
+		header: func() *wire.BlockHeader {
+			header := newHeader(firstBits, true)
+			header.MixHash = chainhash.Hash{}
+			return header
+		}(),
+	}, {
+		name:   "not solved for KawPoW",
+		header: newHeader(firstBits, false),
+	}, {
+		name: "version prior to KawPoW deployment",
+		header: func() *wire.BlockHeader {
+			header := newHeader(firstBits, true)
+			header.Version = int32(version) - 1
+			return header
+		}(),
+	}}
+
+	for _, test := range tests {
+		w.storeCachedKawpowWorkDiffCandidateAnchor(test.cached)
+		got, err := w.checkKawpowDifficultyCandidates(nil, test.header, tip,
+			chain)
+		if err != nil {
+			t.Fatalf("%q: unexpected err: %v", test.name, err)
+		}
+		if got != test.want {
+			t.Fatalf("%q: mismatched result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+		wantCached := test.cached
+		if test.wantCached != nil {
+			wantCached = test.wantCached
+		}
+		if cached := w.loadCachedKawpowWorkDiffCandidateAnchor(); cached != wantCached {
+			t.Fatalf("%q: mismatched cached anchor -- got %v, want %v",
+				test.name, cached, wantCached)
+		}
+	}
+}
+
+// TestKawPowProofOfWork ensures headers with a valid KawPoW solution are
+// verified with the light cache and headers with any other mix hash or a
+// final hash that does not satisfy the target are rejected.
+func TestKawPowProofOfWork(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	parent := params.GenesisBlock.Header
+	header := &wire.BlockHeader{
+		PrevBlock: parent.BlockHash(),
+		Bits:      params.PowLimitBits,
+		Height:    parent.Height + 1,
+	}
+
+	// Solve the header with the light cache.
+	target := blockchain.CompactToBig(header.Bits)
+	for {
+		mixHash, _, err := header.PowHashKawPow()
+		if err != nil {
+			t.Fatalf("unexpected error calculating KawPoW hash: %v", err)
+		}
+		header.MixHash = mixHash
+		if header.VerifyKawPow(target) == nil {
+			break
+		}
+		header.Nonce++
+	}
+	err := checkKawPowProofOfWork(header, &parent, params.PowLimit)
+	if err != nil {
+		t.Fatalf("unexpected err for solved header: %v", err)
+	}
+
+	// Ensure a header with a mix hash that does not match the one calculated
+	// from the header and nonce is rejected even when the final hash
+	// calculated from it satisfies the target.
+	badHeader := *header
+	for {
+		badHeader.MixHash[0]++
+		if badHeader.VerifyKawPowTarget(target) == nil {
+			break
+		}
+	}
+	err = checkKawPowProofOfWork(&badHeader, &parent, params.PowLimit)
+	if !errors.Is(err, kawpow.ErrBadMixHash) {
+		t.Fatalf("mismatched err for bad mix hash -- got %v, want %v", err,
+			kawpow.ErrBadMixHash)
+	}
+
+	// Ensure a header whose final hash does not satisfy the target is
+	// rejected.
+	badHeader = *header
+	badHeader.Bits = 0x03000001
+	err = checkKawPowProofOfWork(&badHeader, &parent, params.PowLimit)
+	if !errors.Is(err, kawpow.ErrHighHash) {
+		t.Fatalf("mismatched err for high hash -- got %v, want %v", err,
+			kawpow.ErrHighHash)
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	"github.com/kdsmith18542/vigil/wallet/errors"
+	"github.com/kdsmith18542/vigil/wallet/wallet/psbt"
+	"github.com/kdsmith18542/vigil/wallet/wallet/txrules"
+	"github.com/kdsmith18542/vigil/wallet/wallet/udb"
+	"github.com/kdsmith18542/vigil/wallet/wallet/walletdb"
+
+	"github.com/kdsmith18542/vigil/VGLec"
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/hdkeychain/v3"
+	"github.com/kdsmith18542/vigil/txscript/v4"
+	"github.com/kdsmith18542/vigil/txscript/v4/sign"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdscript"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// KeyFingerprint returns the fingerprint that identifies an account extended
+// public key in the key derivations of partially signed transactions.
+func KeyFingerprint(xpub *hdkeychain.ExtendedKey) uint32 {
+	return binary.BigEndian.Uint32(VGLutil.Hash160(xpub.SerializedPubKey()))
+}
+
+// pubKeyHashAddr returns the pay-to-pubkey-hash address the wallet records for
+// addr, or addr itself when it is not a public key address.
+func pubKeyHashAddr(addr stdaddr.Address) stdaddr.Address {
+	if a, ok := addr.(stdaddr.AddressPubKeyHasher); ok {
+		return a.AddressPubKeyHash()
+	}
+	return addr
+}
+
+// psbtAddrs returns the addresses of the keys that may sign a previous output
+// script, together with the script that is signed.  For pay-to-script-hash
+// outputs, these are the keys and the script of the redeem script, which is
+// looked up in the wallet when the input does not include it.
+func (w *Wallet) psbtAddrs(addrmgrNs walletdb.ReadBucket, in *psbt.Input) (
+	[]stdaddr.Address, []byte, error) {
+
+	pkScript := in.PrevOut.PkScript
+	class, addrs := stdscript.ExtractAddrs(in.PrevOut.Version, pkScript,
+		w.chainParams)
+	if subClass, isStake := txrules.StakeSubScriptType(class); isStake {
+		class = subClass
+	}
+	if class != stdscript.STScriptHash {
+		return addrs, pkScript, nil
+	}
+	if len(addrs) != 1 {
+		return nil, nil, nil
+	}
+	scriptHash, ok := addrs[0].(stdaddr.Hash160er)
+	if !ok {
+		return nil, nil, nil
+	}
+	if in.RedeemScript == nil {
+		redeemScript, err := w.manager.RedeemScript(addrmgrNs, addrs[0])
+		if errors.Is(err, errors.NotExist) {
+			return nil, nil, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		in.RedeemScript = redeemScript
+	}
+	if !bytes.Equal(VGLutil.Hash160(in.RedeemScript), scriptHash.Hash160()[:]) {
+		return nil, nil, errors.E(errors.Invalid,
+			"redeem script does not match the previous output")
+	}
+	_, addrs = stdscript.ExtractAddrs(scriptVersionAssumed, in.RedeemScript,
+		w.chainParams)
+	return addrs, in.RedeemScript, nil
+}
+
+// UpdatePSBT adds the data the wallet knows about to a partially signed
+// transaction: the previous outputs spent by the inputs, the redeem scripts of
+// pay-to-script-hash inputs and outputs, and the key derivations of wallet
+// keys.  Inputs and outputs unrelated to the wallet are left unchanged.
+func (w *Wallet) UpdatePSBT(ctx context.Context, p *psbt.Packet) error {
+	const op errors.Op = "wallet.UpdatePSBT"
+
+	err := walletdb.View(ctx, w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+
+		fingerprints := make(map[uint32]uint32)
+		derivation := func(addr stdaddr.Address) (*psbt.Bip32Derivation, error) {
+			ma, err := w.manager.Address(addrmgrNs, pubKeyHashAddr(addr))
+			if errors.Is(err, errors.NotExist) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			pka, ok := ma.(udb.ManagedPubKeyAddress)
+			if !ok || ma.Imported() {
+				return nil, nil
+			}
+			fingerprint, ok := fingerprints[ma.Account()]
+			if !ok {
+				xpub, err := w.manager.AccountExtendedPubKey(dbtx,
+					ma.Account())
+				if err != nil {
+					return nil, err
+				}
+				fingerprint = KeyFingerprint(xpub)
+				fingerprints[ma.Account()] = fingerprint
+			}
+			var branch uint32
+			if ma.Internal() {
+				branch = udb.InternalBranch
+			}
+			return &psbt.Bip32Derivation{
+				PubKey:      pka.PubKey(),
+				Fingerprint: fingerprint,
+				Path:        []uint32{branch, pka.Index()},
+			}, nil
+		}
+		addDerivations := func(ds []*psbt.Bip32Derivation,
+			addrs []stdaddr.Address) ([]*psbt.Bip32Derivation, error) {
+		next:
+			for _, addr := range addrs {
+				d, err := derivation(addr)
+				if err != nil {
+					return nil, err
+				}
+				if d == nil {
+					continue
+				}
+				for _, e := range ds {
+					if bytes.Equal(d.PubKey, e.PubKey) {
+						continue next
+					}
+				}
+				ds = append(ds, d)
+			}
+			return ds, nil
+		}
+
+		for i := range p.Inputs {
+			in := &p.Inputs[i]
+			if in.IsFinal() {
+				continue
+			}
+			if in.PrevOut == nil {
+				prevOut := &p.UnsignedTx.TxIn[i].PreviousOutPoint
+				txDetails, err := w.txStore.TxDetails(txmgrNs, &prevOut.Hash)
+				if errors.Is(err, errors.NotExist) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if prevOut.Index >= uint32(len(txDetails.MsgTx.TxOut)) {
+					return errors.E(errors.Invalid, errors.Errorf(
+						"%v does not exist", prevOut))
+				}
+				txOut := txDetails.MsgTx.TxOut[prevOut.Index]
+				in.PrevOut = &wire.TxOut{
+					Value:    txOut.Value,
+					Version:  txOut.Version,
+					PkScript: txOut.PkScript,
+				}
+			}
+			if in.PrevOut.Version != scriptVersionAssumed {
+				continue
+			}
+			addrs, _, err := w.psbtAddrs(addrmgrNs, in)
+			if err != nil {
+				return errors.Errorf("input %d: %w", i, err)
+			}
+			in.Bip32Derivations, err = addDerivations(in.Bip32Derivations,
+				addrs)
+			if err != nil {
+				return err
+			}
+		}
+
+		for i, txOut := range p.UnsignedTx.TxOut {
+			if txOut.Version != scriptVersionAssumed {
+				continue
+			}
+			out := &p.Outputs[i]
+			class, addrs := stdscript.ExtractAddrs(txOut.Version,
+				txOut.PkScript, w.chainParams)
+			if subClass, isStake := txrules.StakeSubScriptType(class); isStake {
+				class = subClass
+			}
+			if class == stdscript.STScriptHash && len(addrs) == 1 {
+				if out.RedeemScript == nil {
+					script, err := w.manager.RedeemScript(addrmgrNs, addrs[0])
+					if errors.Is(err, errors.NotExist) {
+						continue
+					}
+					if err != nil {
+						return err
+					}
+					out.RedeemScript = script
+				}
+				_, addrs = stdscript.ExtractAddrs(scriptVersionAssumed,
+					out.RedeemScript, w.chainParams)
+			}
+			var err error
+			out.Bip32Derivations, err = addDerivations(out.Bip32Derivations,
+				addrs)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// SignPSBT adds the signatures of all wallet keys that can sign the inputs of
+// a partially signed transaction.  Only inputs with a known previous output
+// can be signed, so UpdatePSBT should be called first unless the packet was
+// already updated by another wallet.  The number of added signatures is
+// returned.
+func (w *Wallet) SignPSBT(ctx context.Context, p *psbt.Packet,
+	hashType txscript.SigHashType) (int, error) {
+
+	const op errors.Op = "wallet.SignPSBT"
+
+	var doneFuncs []func()
+	defer func() {
+		for _, f := range doneFuncs {
+			f()
+		}
+	}()
+
+	var signed int
+	err := walletdb.View(ctx, w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+
+		for i := range p.Inputs {
+			in := &p.Inputs[i]
+			if in.IsFinal() || in.PrevOut == nil ||
+				in.PrevOut.Version != scriptVersionAssumed {
+				continue
+			}
+
+			// SigHashSingle inputs can only be signed if there's a
+			// corresponding output.
+			if hashType&txscript.SigHashSingle == txscript.SigHashSingle &&
+				i >= len(p.UnsignedTx.TxOut) {
+				continue
+			}
+
+			addrs, script, err := w.psbtAddrs(addrmgrNs, in)
+			if err != nil {
+				return errors.Errorf("input %d: %w", i, err)
+			}
+			for _, addr := range addrs {
+				privKey, done, err := w.manager.PrivateKey(addrmgrNs,
+					pubKeyHashAddr(addr))
+				if errors.Is(err, errors.NotExist) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				doneFuncs = append(doneFuncs, done)
+
+				sig, err := sign.RawTxInSignature(p.UnsignedTx, i, script,
+					hashType, privKey.Serialize(), VGLec.STEcdsaSecp256k1)
+				if err != nil {
+					return errors.Errorf("input %d: %w", i, err)
+				}
+				pubKey := privKey.PubKey().SerializeCompressed()
+				if err := p.AddPartialSig(i, pubKey, sig); err != nil {
+					return err
+				}
+				signed++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	return signed, nil
+}
+
+// VerifyPSBT executes the scripts of all inputs of a finalized partially
+// signed transaction against their previous outputs.
+func VerifyPSBT(p *psbt.Packet) error {
+	const op errors.Op = "wallet.VerifyPSBT"
+
+	tx, err := p.Extract()
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for i := range p.Inputs {
+		prevOut := p.Inputs[i].PrevOut
+		if prevOut == nil {
+			// The stakebase of votes and inputs added by other
+			// parties without their previous outputs can not be
+			// verified.
+			continue
+		}
+		vm, err := txscript.NewEngine(prevOut.PkScript, tx, i,
+			sanityVerifyFlags, prevOut.Version, nil)
+		if err == nil {
+			err = vm.Execute()
+		}
+		if err != nil {
+			return errors.E(op, errors.Invalid,
+				errors.Errorf("input %d: %v", i, err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,436 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package psbt
+
+import (
+	"bytes"
+
+	"github.com/kdsmith18542/vigil/wallet/errors"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/txscript/v4"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdscript"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// AddPartialSig adds the signature of the public key for input idx.  The last
+// byte of the signature is its signature hash type, which must match the
+// signature hash type of any other signature of the input.
+func (p *Packet) AddPartialSig(idx int, pubKey, sig []byte) error {
+	const op errors.Op = "psbt.AddPartialSig"
+
+	if idx < 0 || idx >= len(p.Inputs) {
+		return errors.E(op, errors.Invalid,
+			errors.Errorf("input %d does not exist", idx))
+	}
+	if len(pubKey) != compressedPubKeySize || len(sig) == 0 {
+		return errors.E(op, errors.Invalid, "invalid public key or signature")
+	}
+	in := &p.Inputs[idx]
+	if in.IsFinal() {
+		return errors.E(op, errors.Invalid,
+			errors.Errorf("input %d is already finalized", idx))
+	}
+	hashType := txscript.SigHashType(sig[len(sig)-1])
+	if in.SigHashType != 0 && in.SigHashType != hashType {
+		return errors.E(op, errors.Invalid, errors.Errorf("signature hash "+
+			"type %v does not match %v of input %d", hashType,
+			in.SigHashType, idx))
+	}
+	for _, s := range in.PartialSigs {
+		if bytes.Equal(s.PubKey, pubKey) {
+			s.Signature = sig
+			in.SigHashType = hashType
+			return nil
+		}
+	}
+	in.PartialSigs = append(in.PartialSigs, &PartialSig{
+		PubKey:    pubKey,
+		Signature: sig,
+	})
+	in.SigHashType = hashType
+	return nil
+}
+
+// mergeBytes returns a when it is set, otherwise b.  An error is returned when
+// both are set and differ.
+func mergeBytes(a, b []byte, field string) ([]byte, error) {
+	switch {
+	case a == nil:
+		return b, nil
+	case b == nil || bytes.Equal(a, b):
+		return a, nil
+	}
+	return nil, errors.Errorf("conflicting %s", field)
+}
+
+// mergeDerivations returns the union of the key derivations.
+func mergeDerivations(a, b []*Bip32Derivation) []*Bip32Derivation {
+next:
+	for _, d := range b {
+		for _, e := range a {
+			if bytes.Equal(d.PubKey, e.PubKey) {
+				continue next
+			}
+		}
+		a = append(a, d)
+	}
+	return a
+}
+
+// mergeUnknowns returns the union of the unknown key-value pairs.
+func mergeUnknowns(a, b []*Unknown) []*Unknown {
+next:
+	for _, u := range b {
+		for _, e := range a {
+			if bytes.Equal(u.Key, e.Key) {
+				continue next
+			}
+		}
+		a = append(a, u)
+	}
+	return a
+}
+
+// Combine merges packets of the same unsigned transaction, such as packets
+// that were updated and signed in parallel by different signers, into a new
+// packet.  Conflicting data for the same input or output is an error.
+func Combine(packets ...*Packet) (*Packet, error) {
+	const op errors.Op = "psbt.Combine"
+
+	if len(packets) == 0 {
+		return nil, errors.E(op, errors.Invalid, "no packets to combine")
+	}
+	txHash := packets[0].UnsignedTx.TxHash()
+	combined, err := New(packets[0].UnsignedTx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	for _, p := range packets {
+		if p.UnsignedTx.TxHash() != txHash {
+			return nil, errors.E(op, errors.Invalid,
+				"packets are for different transactions")
+		}
+		combined.Unknowns = mergeUnknowns(combined.Unknowns, p.Unknowns)
+
+		for i := range p.Inputs {
+			in, c := &p.Inputs[i], &combined.Inputs[i]
+			if in.PrevOut != nil {
+				if c.PrevOut != nil && (c.PrevOut.Value != in.PrevOut.Value ||
+					c.PrevOut.Version != in.PrevOut.Version ||
+					!bytes.Equal(c.PrevOut.PkScript, in.PrevOut.PkScript)) {
+					return nil, errors.E(op, errors.Invalid, errors.Errorf(
+						"conflicting previous output of input %d", i))
+				}
+				c.PrevOut = in.PrevOut
+			}
+			c.RedeemScript, err = mergeBytes(c.RedeemScript,
+				in.RedeemScript, "redeem script")
+			if err != nil {
+				return nil, errors.E(op, errors.Invalid,
+					errors.Errorf("input %d: %v", i, err))
+			}
+			c.FinalScriptSig, err = mergeBytes(c.FinalScriptSig,
+				in.FinalScriptSig, "final signature script")
+			if err != nil {
+				return nil, errors.E(op, errors.Invalid,
+					errors.Errorf("input %d: %v", i, err))
+			}
+			if in.SigHashType != 0 {
+				if c.SigHashType != 0 && c.SigHashType != in.SigHashType {
+					return nil, errors.E(op, errors.Invalid, errors.Errorf(
+						"conflicting signature hash type of input %d", i))
+				}
+				c.SigHashType = in.SigHashType
+			}
+			c.PartialSigs = mergePartialSigs(c.PartialSigs, in.PartialSigs)
+			c.Bip32Derivations = mergeDerivations(c.Bip32Derivations,
+				in.Bip32Derivations)
+			c.Unknowns = mergeUnknowns(c.Unknowns, in.Unknowns)
+		}
+
+		for i := range p.Outputs {
+			out, c := &p.Outputs[i], &combined.Outputs[i]
+			c.RedeemScript, err = mergeBytes(c.RedeemScript,
+				out.RedeemScript, "redeem script")
+			if err != nil {
+				return nil, errors.E(op, errors.Invalid,
+					errors.Errorf("output %d: %v", i, err))
+			}
+			c.Bip32Derivations = mergeDerivations(c.Bip32Derivations,
+				out.Bip32Derivations)
+			c.Unknowns = mergeUnknowns(c.Unknowns, out.Unknowns)
+		}
+	}
+
+	// Partial signatures are no longer needed once an input is final.
+	for i := range combined.Inputs {
+		if combined.Inputs[i].IsFinal() {
+			combined.Inputs[i].PartialSigs = nil
+		}
+	}
+
+	return combined, nil
+}
+
+// mergePartialSigs returns the union of the partial signatures.
+func mergePartialSigs(a, b []*PartialSig) []*PartialSig {
+next:
+	for _, s := range b {
+		for _, e := range a {
+			if bytes.Equal(s.PubKey, e.PubKey) {
+				continue next
+			}
+		}
+		a = append(a, s)
+	}
+	return a
+}
+
+// partialSigByHash returns the signature of the public key with the provided hash
+// of the input, or nil.
+func (in *Input) partialSigByHash(pkHash []byte) *PartialSig {
+	for _, s := range in.PartialSigs {
+		if bytes.Equal(VGLutil.Hash160(s.PubKey), pkHash) {
+			return s
+		}
+	}
+	return nil
+}
+
+// partialSigByKey returns the signature of the public key of the input, or nil.
+func (in *Input) partialSigByKey(pubKey []byte) *PartialSig {
+	for _, s := range in.PartialSigs {
+		if bytes.Equal(s.PubKey, pubKey) {
+			return s
+		}
+	}
+	return nil
+}
+
+// errMissingSigs describes an input that does not have enough signatures yet.
+var errMissingSigs = errors.New("missing signatures")
+
+// finalScriptSig returns the signature script of the input created from its
+// partial signatures.
+func (in *Input) finalScriptSig() ([]byte, error) {
+	if in.PrevOut == nil {
+		return nil, errors.New("missing previous output")
+	}
+	if in.PrevOut.Version != 0 {
+		return nil, errors.Errorf("unsupported script version %d",
+			in.PrevOut.Version)
+	}
+	pkScript := in.PrevOut.PkScript
+	b := txscript.NewScriptBuilder()
+
+	// Pay-to-pubkey-hash, including the stake-tagged variants.
+	pkHash := stdscript.ExtractPubKeyHashV0(pkScript)
+	if pkHash == nil {
+		pkHash = stdscript.ExtractStakePubKeyHashV0(pkScript)
+	}
+	if pkHash != nil {
+		sig := in.partialSigByHash(pkHash)
+		if sig == nil {
+			return nil, errMissingSigs
+		}
+		return b.AddData(sig.Signature).AddData(sig.PubKey).Script()
+	}
+
+	// Pay-to-pubkey.
+	if pubKey := stdscript.ExtractPubKeyV0(pkScript); pubKey != nil {
+		sig := in.partialSigByKey(pubKey)
+		if sig == nil {
+			return nil, errMissingSigs
+		}
+		return b.AddData(sig.Signature).Script()
+	}
+
+	// Pay-to-script-hash of a multisig redeem script, including the
+	// stake-tagged variants.
+	scriptHash := stdscript.ExtractScriptHashV0(pkScript)
+	if scriptHash == nil {
+		scriptHash = stdscript.ExtractStakeScriptHashV0(pkScript)
+	}
+	if scriptHash != nil {
+		if in.RedeemScript == nil {
+			return nil, errors.New("missing redeem script")
+		}
+		if !bytes.Equal(VGLutil.Hash160(in.RedeemScript), scriptHash) {
+			return nil, errors.New("redeem script does not match the " +
+				"previous output")
+		}
+		details := stdscript.ExtractMultiSigScriptDetailsV0(in.RedeemScript,
+			true)
+		if !details.Valid {
+			return nil, errors.New("unsupported redeem script")
+		}
+		var n uint16
+		for _, pubKey := range details.PubKeys {
+			if n == details.RequiredSigs {
+				break
+			}
+			if sig := in.partialSigByKey(pubKey); sig != nil {
+				b.AddData(sig.Signature)
+				n++
+			}
+		}
+		if n < details.RequiredSigs {
+			return nil, errMissingSigs
+		}
+		return b.AddData(in.RedeemScript).Script()
+	}
+
+	return nil, errors.New("unsupported previous output script")
+}
+
+// Finalize creates the signature scripts of all inputs that have enough
+// partial signatures.  An error is returned when any input remains
+// incomplete, but inputs that could be finalized are kept.
+func (p *Packet) Finalize() error {
+	const op errors.Op = "psbt.Finalize"
+
+	var incomplete error
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		if in.IsFinal() {
+			continue
+		}
+		script, err := in.finalScriptSig()
+		if err != nil {
+			if incomplete == nil {
+				incomplete = errors.Errorf("input %d: %v", i, err)
+			}
+			continue
+		}
+		in.FinalScriptSig = script
+		in.PartialSigs = nil
+	}
+	if incomplete != nil {
+		return errors.E(op, errors.Invalid, incomplete)
+	}
+	return nil
+}
+
+// IsComplete returns whether all inputs are finalized.
+func (p *Packet) IsComplete() bool {
+	for i := range p.Inputs {
+		if !p.Inputs[i].IsFinal() {
+			return false
+		}
+	}
+	return true
+}
+
+// Extract returns the signed network transaction of a finalized packet.  The
+// input amounts are set from the previous outputs when they are known.
+func (p *Packet) Extract() (*wire.MsgTx, error) {
+	const op errors.Op = "psbt.Extract"
+
+	if !p.IsComplete() {
+		return nil, errors.E(op, errors.Invalid, "packet is not finalized")
+	}
+	tx := p.UnsignedTx.Copy()
+	for i, txIn := range tx.TxIn {
+		in := &p.Inputs[i]
+		txIn.SignatureScript = in.FinalScriptSig
+		if in.PrevOut != nil {
+			txIn.ValueIn = in.PrevOut.Value
+		}
+	}
+	return tx, nil
+}
+
+// Role describes the next role that must process a packet or input.
+type Role string
+
+// Roles of a packet.
+const (
+	RoleUpdater   Role = "updater"
+	RoleSigner    Role = "signer"
+	RoleFinalizer Role = "finalizer"
+	RoleExtractor Role = "extractor"
+)
+
+// InputAnalysis describes the state of an input.
+type InputAnalysis struct {
+	HasPrevOut  bool
+	IsFinal     bool
+	Next        Role
+	MissingSigs bool
+}
+
+// Analysis describes the state of a packet.
+type Analysis struct {
+	Inputs []InputAnalysis
+	Next   Role
+
+	// Fee is the transaction fee, which is only known when the previous
+	// outputs of all inputs are.  It is negative otherwise.
+	Fee int64
+}
+
+// Analyze reports what each input is missing and the next role that must
+// process the packet.
+func (p *Packet) Analyze() *Analysis {
+	a := &Analysis{
+		Inputs: make([]InputAnalysis, len(p.Inputs)),
+		Next:   RoleExtractor,
+	}
+	// The roles are ordered by the position of the role in the workflow.
+	order := map[Role]int{RoleUpdater: 0, RoleSigner: 1, RoleFinalizer: 2,
+		RoleExtractor: 3}
+
+	var totalIn int64
+	knownIn := true
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		ia := &a.Inputs[i]
+		ia.HasPrevOut = in.PrevOut != nil
+		ia.IsFinal = in.IsFinal()
+		switch {
+		case ia.IsFinal:
+			ia.Next = RoleExtractor
+		case !ia.HasPrevOut:
+			ia.Next = RoleUpdater
+		default:
+			_, err := in.finalScriptSig()
+			switch {
+			case err == nil:
+				ia.Next = RoleFinalizer
+			case errors.Is(err, errMissingSigs):
+				ia.Next = RoleSigner
+				ia.MissingSigs = true
+			default:
+				ia.Next = RoleUpdater
+			}
+		}
+		if order[ia.Next] < order[a.Next] {
+			a.Next = ia.Next
+		}
+
+		switch {
+		case in.PrevOut != nil:
+			totalIn += in.PrevOut.Value
+		case ia.IsFinal:
+			// Stakebase inputs have no previous output and carry
+			// their amount in the transaction.
+			totalIn += p.UnsignedTx.TxIn[i].ValueIn
+		default:
+			knownIn = false
+		}
+	}
+
+	a.Fee = -1
+	if knownIn {
+		var totalOut int64
+		for _, txOut := range p.UnsignedTx.TxOut {
+			totalOut += txOut.Value
+		}
+		a.Fee = totalIn - totalOut
+	}
+	return a
+}
@@ -0,0 +1,534 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package psbt implements partially signed transactions, a container for an
+// unsigned transaction together with the previous outputs, redeem scripts, key
+// derivations and signatures needed to sign it, modeled after Bitcoin's BIP0174.
+//
+// A packet moves between several roles, which may be performed by different
+// wallets or machines:
+//
+//   - A creator wraps an unsigned transaction with New or NewFromAuthoredTx
+//   - An updater adds the previous output, redeem script and key derivations of
+//     the inputs it knows about
+//   - Signers add partial signatures for the keys they control
+//   - A combiner merges packets signed in parallel with Combine
+//   - A finalizer turns the partial signatures into signature scripts with
+//     Finalize and extracts the network transaction with Extract
+//
+// Both regular and stake transactions are supported.  The stakebase input of a
+// vote is carried as an already finalized input.
+package psbt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+
+	"github.com/kdsmith18542/vigil/wallet/errors"
+	"github.com/kdsmith18542/vigil/wallet/wallet/txauthor"
+
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/txscript/v4"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// magic prefixes every serialized packet.
+var magic = [...]byte{'v', 'p', 's', 'b', 't', 0xff}
+
+// Key types of the global map.
+const (
+	globalUnsignedTx = 0x00
+	globalVersion    = 0xfb
+)
+
+// Key types of the input maps.
+const (
+	inputPrevOut         = 0x01
+	inputPartialSig      = 0x02
+	inputSigHashType     = 0x03
+	inputRedeemScript    = 0x04
+	inputBip32Derivation = 0x06
+	inputFinalScriptSig  = 0x07
+)
+
+// Key types of the output maps.
+const (
+	outputRedeemScript    = 0x00
+	outputBip32Derivation = 0x02
+)
+
+// maxFieldSize is the maximum size of a serialized key or value.
+const maxFieldSize = wire.MaxMessagePayload
+
+// compressedPubKeySize is the size of the compressed public keys that identify
+// partial signatures and key derivations.
+const compressedPubKeySize = 33
+
+// Bip32Derivation describes how the key of a signer is derived.  The path is
+// relative to the extended key identified by the fingerprint, which for wallet
+// accounts is the account extended public key.
+type Bip32Derivation struct {
+	PubKey      []byte
+	Fingerprint uint32
+	Path        []uint32
+}
+
+// PartialSig is the signature of a single key for an input.  The signature
+// includes the signature hash type byte.
+type PartialSig struct {
+	PubKey    []byte
+	Signature []byte
+}
+
+// Unknown is a key-value pair of a type not understood by this package.  It
+// is preserved so that packets pass through unchanged.
+type Unknown struct {
+	Key   []byte
+	Value []byte
+}
+
+// Input holds the signing data of a transaction input.
+type Input struct {
+	PrevOut          *wire.TxOut
+	PartialSigs      []*PartialSig
+	SigHashType      txscript.SigHashType
+	RedeemScript     []byte
+	Bip32Derivations []*Bip32Derivation
+	FinalScriptSig   []byte
+	Unknowns         []*Unknown
+}
+
+// IsFinal returns whether the signature script of the input is complete.
+func (in *Input) IsFinal() bool {
+	return in.FinalScriptSig != nil
+}
+
+// Output holds the data needed to identify the owner of a transaction output,
+// such as the change output of the creator.
+type Output struct {
+	RedeemScript     []byte
+	Bip32Derivations []*Bip32Derivation
+	Unknowns         []*Unknown
+}
+
+// Packet is a partially signed transaction.  Inputs and Outputs hold one
+// element for each input and output of the unsigned transaction.
+type Packet struct {
+	UnsignedTx *wire.MsgTx
+	Inputs     []Input
+	Outputs    []Output
+	Unknowns   []*Unknown
+}
+
+// New creates a packet for the provided unsigned transaction.  The signature
+// scripts of the transaction must be empty with the exception of the stakebase
+// input of a vote, which becomes a finalized input.
+func New(tx *wire.MsgTx) (*Packet, error) {
+	const op errors.Op = "psbt.New"
+
+	tx = tx.Copy()
+	p := &Packet{
+		UnsignedTx: tx,
+		Inputs:     make([]Input, len(tx.TxIn)),
+		Outputs:    make([]Output, len(tx.TxOut)),
+	}
+	isVote := stake.IsSSGen(tx)
+	for i, txIn := range tx.TxIn {
+		if i == 0 && isVote {
+			p.Inputs[0].FinalScriptSig = txIn.SignatureScript
+			txIn.SignatureScript = nil
+			continue
+		}
+		if len(txIn.SignatureScript) != 0 {
+			return nil, errors.E(op, errors.Invalid,
+				errors.Errorf("input %d is already signed", i))
+		}
+		txIn.SignatureScript = nil
+	}
+	return p, nil
+}
+
+// NewFromAuthoredTx creates a packet for a transaction created by txauthor.
+// The previous outputs of all inputs are recorded from the authored
+// transaction.
+func NewFromAuthoredTx(atx *txauthor.AuthoredTx) (*Packet, error) {
+	const op errors.Op = "psbt.NewFromAuthoredTx"
+
+	if len(atx.PrevScripts) != len(atx.Tx.TxIn) {
+		return nil, errors.E(op, errors.Invalid,
+			"missing previous output scripts")
+	}
+	p, err := New(atx.Tx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	for i, txIn := range atx.Tx.TxIn {
+		p.Inputs[i].PrevOut = &wire.TxOut{
+			Value:    txIn.ValueIn,
+			Version:  0,
+			PkScript: atx.PrevScripts[i],
+		}
+	}
+	return p, nil
+}
+
+// writeField writes a key-value pair with the provided key type.
+func writeField(w io.Writer, keyType byte, keyData, value []byte) error {
+	key := append([]byte{keyType}, keyData...)
+	if err := wire.WriteVarBytes(w, 0, key); err != nil {
+		return err
+	}
+	return wire.WriteVarBytes(w, 0, value)
+}
+
+// writeUnknowns writes unknown key-value pairs followed by the map separator.
+func writeUnknowns(w io.Writer, unknowns []*Unknown) error {
+	for _, u := range unknowns {
+		if err := wire.WriteVarBytes(w, 0, u.Key); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, u.Value); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write([]byte{0x00})
+	return err
+}
+
+// serializeDerivation returns the value of a key derivation field.
+func serializeDerivation(d *Bip32Derivation) []byte {
+	b := make([]byte, 4+4*len(d.Path))
+	binary.LittleEndian.PutUint32(b, d.Fingerprint)
+	for i, child := range d.Path {
+		binary.LittleEndian.PutUint32(b[4+4*i:], child)
+	}
+	return b
+}
+
+// Serialize writes the binary encoding of the packet to w.
+func (p *Packet) Serialize(w io.Writer) error {
+	const op errors.Op = "psbt.Serialize"
+
+	err := p.serialize(w)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+func (p *Packet) serialize(w io.Writer) error {
+	if _, err := w.Write(magic[:]); err != nil {
+		return err
+	}
+
+	tx, err := p.UnsignedTx.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := writeField(w, globalUnsignedTx, nil, tx); err != nil {
+		return err
+	}
+	if err := writeUnknowns(w, p.Unknowns); err != nil {
+		return err
+	}
+
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		if in.PrevOut != nil {
+			v := make([]byte, 10, 10+len(in.PrevOut.PkScript))
+			binary.LittleEndian.PutUint64(v, uint64(in.PrevOut.Value))
+			binary.LittleEndian.PutUint16(v[8:], in.PrevOut.Version)
+			v = append(v, in.PrevOut.PkScript...)
+			if err := writeField(w, inputPrevOut, nil, v); err != nil {
+				return err
+			}
+		}
+		for _, sig := range in.PartialSigs {
+			err := writeField(w, inputPartialSig, sig.PubKey, sig.Signature)
+			if err != nil {
+				return err
+			}
+		}
+		if in.SigHashType != 0 {
+			var v [4]byte
+			binary.LittleEndian.PutUint32(v[:], uint32(in.SigHashType))
+			if err := writeField(w, inputSigHashType, nil, v[:]); err != nil {
+				return err
+			}
+		}
+		if in.RedeemScript != nil {
+			err := writeField(w, inputRedeemScript, nil, in.RedeemScript)
+			if err != nil {
+				return err
+			}
+		}
+		for _, d := range in.Bip32Derivations {
+			err := writeField(w, inputBip32Derivation, d.PubKey,
+				serializeDerivation(d))
+			if err != nil {
+				return err
+			}
+		}
+		if in.FinalScriptSig != nil {
+			err := writeField(w, inputFinalScriptSig, nil, in.FinalScriptSig)
+			if err != nil {
+				return err
+			}
+		}
+		if err := writeUnknowns(w, in.Unknowns); err != nil {
+			return err
+		}
+	}
+
+	for i := range p.Outputs {
+		out := &p.Outputs[i]
+		if out.RedeemScript != nil {
+			err := writeField(w, outputRedeemScript, nil, out.RedeemScript)
+			if err != nil {
+				return err
+			}
+		}
+		for _, d := range out.Bip32Derivations {
+			err := writeField(w, outputBip32Derivation, d.PubKey,
+				serializeDerivation(d))
+			if err != nil {
+				return err
+			}
+		}
+		if err := writeUnknowns(w, out.Unknowns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bytes returns the binary encoding of the packet.
+func (p *Packet) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := p.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Base64 returns the base64 encoding of the binary packet, which is how
+// packets are exchanged over the RPC interfaces.
+func (p *Packet) Base64() (string, error) {
+	b, err := p.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// readMap calls fn for every key-value pair of the map read from r until the
+// map separator.
+func readMap(r io.Reader, fn func(key, value []byte) error) error {
+	for {
+		key, err := wire.ReadVarBytes(r, 0, maxFieldSize, "key")
+		if err != nil {
+			return err
+		}
+		if len(key) == 0 {
+			return nil
+		}
+		value, err := wire.ReadVarBytes(r, 0, maxFieldSize, "value")
+		if err != nil {
+			return err
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+}
+
+// parseDerivation parses the key and value of a key derivation field.
+func parseDerivation(key, value []byte) (*Bip32Derivation, error) {
+	if len(key) != 1+compressedPubKeySize {
+		return nil, errors.Errorf("invalid derivation public key length %d",
+			len(key)-1)
+	}
+	if len(value) < 4 || len(value)%4 != 0 {
+		return nil, errors.Errorf("invalid derivation length %d", len(value))
+	}
+	d := &Bip32Derivation{
+		PubKey:      key[1:],
+		Fingerprint: binary.LittleEndian.Uint32(value),
+		Path:        make([]uint32, 0, len(value)/4-1),
+	}
+	for i := 4; i < len(value); i += 4 {
+		d.Path = append(d.Path, binary.LittleEndian.Uint32(value[i:]))
+	}
+	return d, nil
+}
+
+// duplicateKey returns the error for a key that appears twice in a map.
+func duplicateKey(key []byte) error {
+	return errors.Errorf("duplicate key %x", key)
+}
+
+// Parse reads the binary encoding of a packet from r.
+func Parse(r io.Reader) (*Packet, error) {
+	const op errors.Op = "psbt.Parse"
+
+	p, err := parse(r)
+	if err != nil {
+		return nil, errors.E(op, errors.Encoding, err)
+	}
+	return p, nil
+}
+
+func parse(r io.Reader) (*Packet, error) {
+	var m [len(magic)]byte
+	if _, err := io.ReadFull(r, m[:]); err != nil {
+		return nil, err
+	}
+	if m != magic {
+		return nil, errors.New("invalid magic bytes")
+	}
+
+	p := new(Packet)
+	err := readMap(r, func(key, value []byte) error {
+		switch {
+		case key[0] == globalUnsignedTx && len(key) == 1:
+			if p.UnsignedTx != nil {
+				return duplicateKey(key)
+			}
+			tx := new(wire.MsgTx)
+			if err := tx.FromBytes(value); err != nil {
+				return err
+			}
+			p.UnsignedTx = tx
+		case key[0] == globalVersion && len(key) == 1:
+			if len(value) != 4 || binary.LittleEndian.Uint32(value) != 0 {
+				return errors.New("unsupported version")
+			}
+		default:
+			p.Unknowns = append(p.Unknowns, &Unknown{Key: key, Value: value})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.UnsignedTx == nil {
+		return nil, errors.New("missing unsigned transaction")
+	}
+	for i, txIn := range p.UnsignedTx.TxIn {
+		if len(txIn.SignatureScript) != 0 {
+			return nil, errors.Errorf("unsigned transaction input %d "+
+				"has a signature script", i)
+		}
+	}
+
+	p.Inputs = make([]Input, len(p.UnsignedTx.TxIn))
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		seen := make(map[string]struct{})
+		err := readMap(r, func(key, value []byte) error {
+			if _, ok := seen[string(key)]; ok {
+				return duplicateKey(key)
+			}
+			seen[string(key)] = struct{}{}
+
+			switch {
+			case key[0] == inputPrevOut && len(key) == 1:
+				if len(value) < 10 {
+					return errors.New("invalid previous output")
+				}
+				in.PrevOut = &wire.TxOut{
+					Value:    int64(binary.LittleEndian.Uint64(value)),
+					Version:  binary.LittleEndian.Uint16(value[8:]),
+					PkScript: value[10:],
+				}
+			case key[0] == inputPartialSig:
+				if len(key) != 1+compressedPubKeySize {
+					return errors.New("invalid partial signature key")
+				}
+				in.PartialSigs = append(in.PartialSigs, &PartialSig{
+					PubKey:    key[1:],
+					Signature: value,
+				})
+			case key[0] == inputSigHashType && len(key) == 1:
+				if len(value) != 4 {
+					return errors.New("invalid signature hash type")
+				}
+				in.SigHashType = txscript.SigHashType(
+					binary.LittleEndian.Uint32(value))
+			case key[0] == inputRedeemScript && len(key) == 1:
+				in.RedeemScript = value
+			case key[0] == inputBip32Derivation:
+				d, err := parseDerivation(key, value)
+				if err != nil {
+					return err
+				}
+				in.Bip32Derivations = append(in.Bip32Derivations, d)
+			case key[0] == inputFinalScriptSig && len(key) == 1:
+				in.FinalScriptSig = value
+			default:
+				in.Unknowns = append(in.Unknowns,
+					&Unknown{Key: key, Value: value})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Errorf("input %d: %v", i, err)
+		}
+	}
+
+	p.Outputs = make([]Output, len(p.UnsignedTx.TxOut))
+	for i := range p.Outputs {
+		out := &p.Outputs[i]
+		seen := make(map[string]struct{})
+		err := readMap(r, func(key, value []byte) error {
+			if _, ok := seen[string(key)]; ok {
+				return duplicateKey(key)
+			}
+			seen[string(key)] = struct{}{}
+
+			switch {
+			case key[0] == outputRedeemScript && len(key) == 1:
+				out.RedeemScript = value
+			case key[0] == outputBip32Derivation:
+				d, err := parseDerivation(key, value)
+				if err != nil {
+					return err
+				}
+				out.Bip32Derivations = append(out.Bip32Derivations, d)
+			default:
+				out.Unknowns = append(out.Unknowns,
+					&Unknown{Key: key, Value: value})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Errorf("output %d: %v", i, err)
+		}
+	}
+
+	return p, nil
+}
+
+// ParseBase64 parses a base64 encoded packet.  Unlike Parse, it is an error
+// for any data to follow the packet.
+func ParseBase64(s string) (*Packet, error) {
+	const op errors.Op = "psbt.ParseBase64"
+
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.E(op, errors.Encoding, err)
+	}
+	r := bytes.NewReader(b)
+	p, err := Parse(r)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if r.Len() != 0 {
+		return nil, errors.E(op, errors.Encoding,
+			"trailing data after packet")
+	}
+	return p, nil
+}
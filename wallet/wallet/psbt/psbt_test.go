@@ -0,0 +1,254 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package psbt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/kdsmith18542/vigil/wallet/errors"
+
+	"github.com/kdsmith18542/vigil/VGLutil/v4"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/txscript/v4"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// testPubKey returns a compressed public key encoding that is unique for n.
+func testPubKey(n byte) []byte {
+	pubKey := make([]byte, compressedPubKeySize)
+	pubKey[0] = 0x02
+	pubKey[1] = n
+	return pubKey
+}
+
+// testSig returns a signature encoding that is unique for n.
+func testSig(n byte) []byte {
+	return []byte{0x30, n, byte(txscript.SigHashAll)}
+}
+
+func p2pkhScript(t *testing.T, pubKey []byte) []byte {
+	t.Helper()
+	script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).
+		AddOp(txscript.OP_HASH160).AddData(VGLutil.Hash160(pubKey)).
+		AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func p2shScript(t *testing.T, redeemScript []byte) []byte {
+	t.Helper()
+	script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_HASH160).
+		AddData(VGLutil.Hash160(redeemScript)).AddOp(txscript.OP_EQUAL).
+		Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+// multisigScript returns a 2-of-3 multisig script of the keys 1, 2 and 3.
+func multisigScript(t *testing.T) []byte {
+	t.Helper()
+	script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_2).
+		AddData(testPubKey(1)).AddData(testPubKey(2)).AddData(testPubKey(3)).
+		AddOp(txscript.OP_3).AddOp(txscript.OP_CHECKMULTISIG).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+// testPacket returns a packet spending a pay-to-pubkey-hash output of key 1
+// and a pay-to-script-hash output of a 2-of-3 multisig script.
+func testPacket(t *testing.T) *Packet {
+	t.Helper()
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0,
+		wire.TxTreeRegular), 0, nil))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{2}, 1,
+		wire.TxTreeRegular), 0, nil))
+	tx.AddTxOut(wire.NewTxOut(2e8, p2pkhScript(t, testPubKey(9))))
+	p, err := New(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	redeemScript := multisigScript(t)
+	p.Inputs[0].PrevOut = wire.NewTxOut(1e8, p2pkhScript(t, testPubKey(1)))
+	p.Inputs[1].PrevOut = wire.NewTxOut(1.5e8, p2shScript(t, redeemScript))
+	p.Inputs[1].RedeemScript = redeemScript
+	p.Inputs[1].Bip32Derivations = []*Bip32Derivation{{
+		PubKey:      testPubKey(2),
+		Fingerprint: 0xdeadbeef,
+		Path:        []uint32{0, 7},
+	}}
+	p.Outputs[0].Bip32Derivations = []*Bip32Derivation{{
+		PubKey:      testPubKey(9),
+		Fingerprint: 0xdeadbeef,
+		Path:        []uint32{1, 3},
+	}}
+	p.Unknowns = []*Unknown{{Key: []byte{0xf0, 1}, Value: []byte{2}}}
+	return p
+}
+
+// TestSerialize ensures packets survive a serialization round trip and that
+// malformed packets are rejected.
+func TestSerialize(t *testing.T) {
+	p := testPacket(t)
+	if err := p.AddPartialSig(1, testPubKey(1), testSig(1)); err != nil {
+		t.Fatal(err)
+	}
+	s, err := p.Base64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseBase64(s)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	b, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reserialized, err := parsed.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reserialized, b) {
+		t.Fatalf("round trip mismatch:\ngot  %x\nwant %x", reserialized, b)
+	}
+	if parsed.UnsignedTx.TxHash() != p.UnsignedTx.TxHash() {
+		t.Error("unsigned transaction changed")
+	}
+	if !reflect.DeepEqual(parsed.Inputs[1].PartialSigs, p.Inputs[1].PartialSigs) {
+		t.Error("partial signatures changed")
+	}
+	if !reflect.DeepEqual(parsed.Inputs[1].Bip32Derivations,
+		p.Inputs[1].Bip32Derivations) {
+		t.Error("key derivations changed")
+	}
+	if !reflect.DeepEqual(parsed.Unknowns, p.Unknowns) {
+		t.Error("unknown fields changed")
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"bad magic", append([]byte{'x'}, b[1:]...)},
+		{"truncated", b[:len(b)-1]},
+	}
+	for _, test := range tests {
+		_, err := Parse(bytes.NewReader(test.data))
+		if !errors.Is(err, errors.Encoding) {
+			t.Errorf("%s: expected encoding error, got %v", test.name, err)
+		}
+	}
+	for _, s := range []string{"!", base64.StdEncoding.EncodeToString(
+		append(b[:len(b):len(b)], 0))} {
+		if _, err := ParseBase64(s); !errors.Is(err, errors.Encoding) {
+			t.Errorf("%q: expected encoding error, got %v", s, err)
+		}
+	}
+}
+
+// TestNew ensures packets are only created for unsigned transactions.
+func TestNew(t *testing.T) {
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0,
+		wire.TxTreeRegular), 0, []byte{txscript.OP_1}))
+	if _, err := New(tx); !errors.Is(err, errors.Invalid) {
+		t.Fatalf("expected invalid error for signed input, got %v", err)
+	}
+}
+
+// TestSignWorkflow runs two signers in parallel, combines and finalizes their
+// packets, and checks the extracted transaction.
+func TestSignWorkflow(t *testing.T) {
+	p := testPacket(t)
+	if a := p.Analyze(); a.Next != RoleSigner || a.Fee != 0.5e8 {
+		t.Fatalf("unexpected analysis of unsigned packet: %+v", a)
+	}
+
+	// The first signer controls keys 1 and 3, the second only key 2.
+	signer1, signer2 := testPacket(t), testPacket(t)
+	if err := signer1.AddPartialSig(0, testPubKey(1), testSig(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := signer1.AddPartialSig(1, testPubKey(3), testSig(3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := signer2.AddPartialSig(1, testPubKey(2), testSig(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Neither signer can complete the multisig input alone.
+	if err := signer1.Finalize(); !errors.Is(err, errors.Invalid) {
+		t.Fatalf("expected incomplete packet, got %v", err)
+	}
+	if !signer1.Inputs[0].IsFinal() || signer1.Inputs[1].IsFinal() {
+		t.Fatal("unexpected finalized inputs")
+	}
+	if a := signer1.Analyze(); a.Next != RoleSigner ||
+		!a.Inputs[1].MissingSigs {
+		t.Fatalf("unexpected analysis of partially signed packet: %+v", a)
+	}
+
+	combined, err := Combine(signer1, signer2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := combined.Analyze(); a.Next != RoleFinalizer {
+		t.Fatalf("unexpected analysis of combined packet: %+v", a)
+	}
+	if err := combined.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := combined.Extract()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantScript0, _ := txscript.NewScriptBuilder().AddData(testSig(1)).
+		AddData(testPubKey(1)).Script()
+	// Signatures are ordered by the keys of the redeem script.
+	wantScript1, _ := txscript.NewScriptBuilder().AddData(testSig(2)).
+		AddData(testSig(3)).AddData(multisigScript(t)).Script()
+	if !bytes.Equal(tx.TxIn[0].SignatureScript, wantScript0) {
+		t.Errorf("input 0 script %x, want %x", tx.TxIn[0].SignatureScript,
+			wantScript0)
+	}
+	if !bytes.Equal(tx.TxIn[1].SignatureScript, wantScript1) {
+		t.Errorf("input 1 script %x, want %x", tx.TxIn[1].SignatureScript,
+			wantScript1)
+	}
+	if tx.TxIn[1].ValueIn != 1.5e8 {
+		t.Errorf("input 1 amount %d, want %d", tx.TxIn[1].ValueIn,
+			int64(1.5e8))
+	}
+	if tx.TxHash() != p.UnsignedTx.TxHash() {
+		t.Error("extracted transaction hash differs from unsigned transaction")
+	}
+}
+
+// TestCombineConflicts ensures packets of different transactions or with
+// conflicting input data are not combined.
+func TestCombineConflicts(t *testing.T) {
+	p1, p2 := testPacket(t), testPacket(t)
+	p2.Inputs[0].PrevOut = wire.NewTxOut(2e8, p1.Inputs[0].PrevOut.PkScript)
+	if _, err := Combine(p1, p2); !errors.Is(err, errors.Invalid) {
+		t.Errorf("expected conflicting previous output error, got %v", err)
+	}
+
+	p3 := testPacket(t)
+	p3.UnsignedTx.LockTime = 1
+	if _, err := Combine(p1, p3); !errors.Is(err, errors.Invalid) {
+		t.Errorf("expected different transaction error, got %v", err)
+	}
+}
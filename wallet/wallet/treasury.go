@@ -0,0 +1,182 @@
+// Copyright (c) 2024 The Vigil developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"context"
+
+	"github.com/kdsmith18542/vigil/wallet/errors"
+
+	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4"
+	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4/schnorr"
+	"github.com/kdsmith18542/vigil/blockchain/stake/v5"
+	"github.com/kdsmith18542/vigil/chaincfg/chainhash"
+	"github.com/kdsmith18542/vigil/txscript/v4"
+	"github.com/kdsmith18542/vigil/txscript/v4/stdaddr"
+	"github.com/kdsmith18542/vigil/wire"
+)
+
+// tspendNonceKey identifies the secret MuSig2 nonce of a treasury key share for
+// a particular treasury spend.
+type tspendNonceKey struct {
+	pubKey  [secp256k1.PubKeyBytesLenCompressed]byte
+	sigHash chainhash.Hash
+}
+
+// tspendSigHash returns the hash that is signed by the treasury key of the
+// provided treasury spend.
+func tspendSigHash(tx *wire.MsgTx) (chainhash.Hash, error) {
+	var hash chainhash.Hash
+	if len(tx.TxIn) != 1 {
+		return hash, errors.E(errors.Invalid, "treasury spend must have "+
+			"a single input")
+	}
+	sigHash, err := txscript.CalcSignatureHash(nil, txscript.SigHashAll, tx,
+		0, nil)
+	if err != nil {
+		return hash, err
+	}
+	copy(hash[:], sigHash)
+	return hash, nil
+}
+
+// TreasurySpendNonce creates the MuSig2 nonce of the treasury key share with
+// the provided address for the jointly signed treasury spend tx and returns
+// its public part to be shared with the other signers.  The secret nonce is
+// only held in memory until SignTreasurySpend is called for the same key and
+// transaction, so a nonce must be recreated after the wallet restarts.
+func (w *Wallet) TreasurySpendNonce(ctx context.Context, addr stdaddr.Address,
+	tx *wire.MsgTx) (*schnorr.PublicNonce, error) {
+
+	const op errors.Op = "wallet.TreasurySpendNonce"
+	sigHash, err := tspendSigHash(tx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	privKey, zero, err := w.LoadPrivateKey(ctx, addr)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	defer zero()
+
+	secNonce, pubNonce, err := schnorr.GenerateNonce(privKey, sigHash[:])
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	key := tspendNonceKey{sigHash: sigHash}
+	copy(key.pubKey[:], privKey.PubKey().SerializeCompressed())
+
+	w.tspendNoncesMu.Lock()
+	if old, ok := w.tspendNonces[key]; ok {
+		old.Zero()
+	}
+	w.tspendNonces[key] = secNonce
+	w.tspendNoncesMu.Unlock()
+
+	return pubNonce, nil
+}
+
+// SignTreasurySpend creates the MuSig2 partial signature of the treasury key
+// share with the provided address for the treasury spend tx.  The public keys
+// of all signers must be provided in the order they were aggregated in and the
+// public nonces in any order.  The nonce previously created by
+// TreasurySpendNonce is consumed and can not be used again.
+func (w *Wallet) SignTreasurySpend(ctx context.Context, addr stdaddr.Address,
+	tx *wire.MsgTx, pubKeys []*secp256k1.PublicKey,
+	pubNonces []*schnorr.PublicNonce) (*schnorr.PartialSignature, error) {
+
+	const op errors.Op = "wallet.SignTreasurySpend"
+	sigHash, err := tspendSigHash(tx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	privKey, zero, err := w.LoadPrivateKey(ctx, addr)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	defer zero()
+
+	key := tspendNonceKey{sigHash: sigHash}
+	copy(key.pubKey[:], privKey.PubKey().SerializeCompressed())
+	w.tspendNoncesMu.Lock()
+	secNonce, ok := w.tspendNonces[key]
+	delete(w.tspendNonces, key)
+	w.tspendNoncesMu.Unlock()
+	if !ok {
+		return nil, errors.E(op, errors.NotExist, "no nonce was created "+
+			"for this key and treasury spend")
+	}
+
+	aggNonce, err := schnorr.AggregateNonces(pubNonces)
+	if err != nil {
+		secNonce.Zero()
+		return nil, errors.E(op, errors.Invalid, err)
+	}
+	sig, err := schnorr.PartialSign(secNonce, privKey, pubKeys, aggNonce,
+		sigHash[:])
+	if err != nil {
+		return nil, errors.E(op, errors.Invalid, err)
+	}
+	return sig, nil
+}
+
+// FinalizeTreasurySpend verifies the MuSig2 partial signatures of all treasury
+// key shares for the treasury spend tx, combines them into the signature of
+// the aggregate treasury key, and adds the signature script to the
+// transaction.  The public keys, public nonces, and partial signatures must
+// all be provided in the order the keys were aggregated in.
+func FinalizeTreasurySpend(tx *wire.MsgTx, pubKeys []*secp256k1.PublicKey,
+	pubNonces []*schnorr.PublicNonce,
+	partialSigs []*schnorr.PartialSignature) error {
+
+	const op errors.Op = "wallet.FinalizeTreasurySpend"
+	if len(pubNonces) != len(pubKeys) || len(partialSigs) != len(pubKeys) {
+		return errors.E(op, errors.Invalid, "number of public keys, nonces "+
+			"and partial signatures differ")
+	}
+	sigHash, err := tspendSigHash(tx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	aggKey, err := schnorr.AggregatePubKeys(pubKeys)
+	if err != nil {
+		return errors.E(op, errors.Invalid, err)
+	}
+	aggNonce, err := schnorr.AggregateNonces(pubNonces)
+	if err != nil {
+		return errors.E(op, errors.Invalid, err)
+	}
+	for i, partialSig := range partialSigs {
+		err := schnorr.VerifyPartialSignature(partialSig, pubNonces[i],
+			pubKeys[i], pubKeys, aggNonce, sigHash[:])
+		if err != nil {
+			return errors.E(op, errors.Invalid, errors.Errorf("partial "+
+				"signature of key %x: %v", pubKeys[i].SerializeCompressed(),
+				err))
+		}
+	}
+	sig, err := schnorr.CombinePartialSignatures(pubKeys, aggNonce,
+		sigHash[:], partialSigs)
+	if err != nil {
+		return errors.E(op, errors.Invalid, err)
+	}
+
+	// Signature scripts of treasury spends are
+	// <signature> <compressed key> OP_TSPEND.
+	sigScript, err := txscript.NewScriptBuilder().
+		AddData(sig.Serialize()).
+		AddData(aggKey.SerializeCompressed()).
+		AddOp(txscript.OP_TSPEND).
+		Script()
+	if err != nil {
+		return errors.E(op, err)
+	}
+	tx.TxIn[0].SignatureScript = sigScript
+
+	if _, _, err := stake.CheckTSpend(tx); err != nil {
+		return errors.E(op, errors.Invalid, err)
+	}
+	return nil
+}
@@ -39,6 +39,7 @@ import (
 	"github.com/kdsmith18542/vigil/VGLec"
 	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4"
 	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4/ecdsa"
+	"github.com/kdsmith18542/vigil/VGLec/secp256k1/v4/schnorr"
 	"github.com/kdsmith18542/vigil/VGLutil/v4"
 	gcs2 "github.com/kdsmith18542/vigil/gcs/v4"
 	"github.com/kdsmith18542/vigil/hdkeychain/v3"
@@ -120,6 +121,10 @@ type Wallet struct {
 	vspTSpendPolicy    map[udb.VSPTSpend]stake.TreasuryVoteT
 	vspTSpendKeyPolicy map[udb.VSPTreasuryKey]stake.TreasuryVoteT
 
+	// Secret MuSig2 nonces of treasury key shares.
+	tspendNonces   map[tspendNonceKey]*schnorr.SecretNonce
+	tspendNoncesMu sync.Mutex
+
 	// Start up flags/settings
 	gapLimit        uint32
 	watchLast       uint32
@@ -164,6 +169,10 @@ type Wallet struct {
 	cachedBlake3WorkDiffCandidateAnchor   *wire.BlockHeader
 	cachedBlake3WorkDiffCandidateAnchorMu sync.Mutex
 
+	// Cached KawPoW anchor candidate
+	cachedKawpowWorkDiffCandidateAnchor   *wire.BlockHeader
+	cachedKawpowWorkDiffCandidateAnchorMu sync.Mutex
+
 	NtfnServer *NotificationServer
 
 	chainParams        *chaincfg.Params
@@ -5391,6 +5400,7 @@ func Open(ctx context.Context, cfg *Config) (*Wallet, error) {
 		// StakeOptions
 		votingEnabled:      cfg.VotingEnabled,
 		tspends:            make(map[chainhash.Hash]wire.MsgTx),
+		tspendNonces:       make(map[tspendNonceKey]*schnorr.SecretNonce),
 		tspendPolicy:       make(map[chainhash.Hash]stake.TreasuryVoteT),
 		tspendKeyPolicy:    make(map[string]stake.TreasuryVoteT),
 		vspTSpendPolicy:    make(map[udb.VSPTSpend]stake.TreasuryVoteT),
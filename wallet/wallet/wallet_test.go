@@ -101,8 +101,8 @@ func TestVotingXprivFromSeed(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to create seed: %v", err)
 	}
-	wantXpriv := "dprv3oaf1h1hLxLZNHn6Gn9AaUaMJxhpHAj2KGMMHTi2AnoiHdRhCc" +
-		"iKbwf3dB6zpPEq8ffdT4NZ7gjtrZBQhSWDm2RVXbphmpdPnbq299ddB8a"
+	wantXpriv := "vprvVYoTuo7fFx81kQGwpMNkhCmVGupviL78YVqMHS7z44Dj2b1jJr" +
+		"xJUaArYgHi6bqUwLozobH9PKL9CsCoSBn2ZeTwifWFB9RiE5UrrMUoATMH"
 
 	tests := []struct {
 		name, want string